@@ -29,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 
 	backend "github.com/kube-bind/kube-bind/contrib/example-backend"
+	"github.com/kube-bind/kube-bind/contrib/example-backend/admin"
 	"github.com/kube-bind/kube-bind/contrib/example-backend/options"
 )
 
@@ -36,6 +37,15 @@ func main() {
 	ctx := genericapiserver.SetupSignalContext()
 	defer klog.Flush()
 
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		cmd := admin.NewCommand()
+		cmd.SetArgs(os.Args[2:])
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
 	options := options.NewOptions()
 	options.AddFlags(pflag.CommandLine)
 	pflag.Parse()
@@ -75,11 +85,15 @@ func main() {
 		os.Exit(1)
 	}
 	server.OptionallyStartInformers(ctx)
+	server.OptionallyStartMetricsServer(ctx)
 	if err := server.Run(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v", err) // nolint: errcheck
 		os.Exit(1)
 	}
 	fmt.Printf("Listening on port %s\n", server.Addr())
+	if completed.Dev {
+		fmt.Printf("Development mode: bind with \"kubectl bind http://%s/export\"\n", server.Addr())
+	}
 
 	<-ctx.Done()
 }
@@ -20,16 +20,28 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	"k8s.io/client-go/tools/leaderelection"
 	logsv1 "k8s.io/component-base/logs/api/v1"
 	_ "k8s.io/component-base/logs/json/register"
 	componentbaseversion "k8s.io/component-base/version"
 	"k8s.io/klog/v2"
 
 	"github.com/kube-bind/kube-bind/pkg/konnector"
+	"github.com/kube-bind/kube-bind/pkg/konnector/drain"
+	"github.com/kube-bind/kube-bind/pkg/konnector/dryrun"
+	"github.com/kube-bind/kube-bind/pkg/konnector/healthz"
+	"github.com/kube-bind/kube-bind/pkg/konnector/management"
 	konnectoroptions "github.com/kube-bind/kube-bind/pkg/konnector/options"
+	"github.com/kube-bind/kube-bind/pkg/konnector/selfupgrade"
+	"github.com/kube-bind/kube-bind/pkg/konnector/sharding"
+	"github.com/kube-bind/kube-bind/pkg/konnector/tracing"
 	bindversion "github.com/kube-bind/kube-bind/pkg/version"
 )
 
@@ -62,31 +74,185 @@ func New(ctx context.Context) *cobra.Command {
 			if err := completed.Validate(); err != nil {
 				return err
 			}
-			config, err := konnector.NewConfig(completed)
+			shutdownTracing, err := tracing.Setup(ctx, options.TracingEndpoint, options.TracingSamplingRatePerMillion, options.LeaseLockIdentity, ver)
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to set up tracing: %w", err)
 			}
-			server, err := konnector.NewServer(config)
+			defer func() {
+				// flush the spans of the last syncs
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := shutdownTracing(ctx); err != nil {
+					logger.Error(err, "failed to flush traces")
+				}
+			}()
+			config, err := konnector.NewConfig(completed)
 			if err != nil {
 				return err
 			}
-			prepared, err := server.PrepareRun(ctx)
-			if err != nil {
+
+			// serve probes early, such that the konnector is live while informers sync
+			watchDog := leaderelection.NewLeaderHealthzAdaptor(20 * time.Second)
+			status := healthz.NewStatus(watchDog)
+			if options.HealthProbeBindAddress != "" {
+				status.Serve(ctx, options.HealthProbeBindAddress)
+			}
+
+			// the konnector's own cluster is always a consumer, additional ones are optional
+			type consumer struct {
+				name     string
+				ctx      context.Context
+				prepared konnector.Prepared
+			}
+			var consumers []consumer
+			var sharder *sharding.Sharder
+			if options.Sharding {
+				sharder = sharding.NewSharder(config.KubeClient.CoordinationV1(), options.LeaseLockNamespace, options.LeaseLockName, options.LeaseLockIdentity, options.LeaderElection.LeaseDuration, options.LeaderElection.RetryPeriod)
+			}
+			versionRequirements := selfupgrade.NewRequirements()
+			informersSynced := true
+			for i, target := range append([]string{""}, completed.ConsumerKubeconfigs...) {
+				consumerConfig, consumerCtx := config, ctx
+				if i > 0 {
+					path, kubeContext := konnectoroptions.SplitConsumerKubeconfig(target)
+					if consumerConfig, err = konnector.NewConsumerConfig(completed, path, kubeContext); err != nil {
+						return fmt.Errorf("failed to load consumer kubeconfig %q: %w", target, err)
+					}
+					consumerCtx = klog.NewContext(ctx, logger.WithValues("consumer", target))
+				}
+				if sharder != nil {
+					consumerConfig.Shards = sharder
+				}
+				consumerConfig.VersionRequirements = versionRequirements
+
+				server, err := konnector.NewServer(consumerConfig)
+				if err != nil {
+					return err
+				}
+				prepared, err := server.PrepareRun(consumerCtx)
+				if err != nil {
+					return err
+				}
+				if !prepared.OptionallyStartInformers(consumerCtx) {
+					informersSynced = false
+				}
+				consumers = append(consumers, consumer{name: target, ctx: consumerCtx, prepared: prepared})
+			}
+			status.SetInformersSynced(informersSynced)
+			consumers[0].prepared.OptionallyStartMetricsServer(ctx)
+			consumers[0].prepared.OptionallyStartDebugServer(ctx)
+			if err := consumers[0].prepared.OptionallyStartWebhookServer(ctx); err != nil {
 				return err
 			}
-			prepared.OptionallyStartInformers(ctx)
+			if options.ManagementBindAddress != "" {
+				token, err := management.EnsureToken(ctx, config.KubeClient.CoreV1(), options.LeaseLockNamespace)
+				if err != nil {
+					return fmt.Errorf("failed to get management token: %w", err)
+				}
+				backends := map[string]management.Backend{}
+				for _, c := range consumers {
+					backends[c.name] = c.prepared.Controller
+				}
+				management.NewServer(options.LeaseLockIdentity, ver, token, backends, options.ManagementDashboard).Serve(ctx, options.ManagementBindAddress)
+			}
+
+			// Leader election outlives the termination signal such that the lease is
+			// only released after in-flight work is drained.
+			leaderElectionCtx, cancelLeaderElection := context.WithCancel(klog.NewContext(context.Background(), logger))
+			defer cancelLeaderElection()
+			started, drained := make(chan struct{}), make(chan struct{})
+			go func() {
+				<-ctx.Done()
+				select {
+				case <-started:
+					// the leader stops leader election itself after draining
+					select {
+					case <-drained:
+					case <-time.After(options.ShutdownGracePeriod + 5*time.Second):
+					}
+				default:
+				}
+				cancelLeaderElection()
+			}()
+
+			tracker := drain.NewTracker()
+			run := func(leaderCtx context.Context) {
+				close(started)
+				defer close(drained)
+				defer cancelLeaderElection() // release the lease promptly
+				status.SetLeading(true)
+				defer status.SetLeading(false)
 
-			logger.Info("trying to acquire the lock")
-			lock := NewLock(config.KubeClient, options.LeaseLockNamespace, options.LeaseLockName, options.LeaseLockIdentity)
-			runLeaderElection(ctx, lock, options.LeaseLockIdentity, func(ctx context.Context) {
-				logger.Info("starting konnector controller")
-				err = prepared.Run(ctx)
-			})
+				// run until terminated or no longer leading
+				runCtx, cancel := context.WithCancel(drain.WithTracker(leaderCtx, tracker))
+				defer cancel()
+				go func() {
+					select {
+					case <-ctx.Done():
+					case <-runCtx.Done():
+					}
+					cancel()
+				}()
+
+				if options.SelfUpgrade {
+					deployments := config.KubeClient.AppsV1()
+					if options.DryRun {
+						if deployments, err = appsv1client.NewForConfig(dryrun.Wrap(config.ClientConfig, "consumer")); err != nil {
+							logger.Error(err, "failed to create dry-run client for self-upgrade")
+							return
+						}
+					}
+					go selfupgrade.NewController(deployments, options.LeaseLockNamespace, options.SelfUpgradeDeployment, options.SelfUpgradeImage, ver, versionRequirements).Start(runCtx)
+				}
+
+				logger.Info("starting konnector controller", "consumers", len(consumers))
+				status.SetControllersStarted(true)
+				defer status.SetControllersStarted(false)
+				var wg sync.WaitGroup
+				errs := make([]error, len(consumers))
+				for i, c := range consumers {
+					wg.Add(1)
+					go func(i int, c consumer) {
+						defer wg.Done()
+						errs[i] = c.prepared.Run(klog.NewContext(runCtx, klog.FromContext(c.ctx)))
+					}(i, c)
+				}
+				wg.Wait()
+				err = utilerrors.NewAggregate(errs)
+
+				logger.Info("draining in-flight work", "gracePeriod", options.ShutdownGracePeriod)
+				summary := tracker.Drain(options.ShutdownGracePeriod)
+				logger.Info("stopped konnector controller", "completed", summary.Completed, "aborted", summary.Aborted, "duration", summary.Duration.Round(time.Millisecond))
+			}
+			if sharder != nil {
+				// the membership Lease is deleted after draining, handing the bindings over
+				logger.Info("joining shard group", "group", options.LeaseLockName, "identity", options.LeaseLockIdentity)
+				if err := sharder.Start(leaderElectionCtx); err != nil {
+					return fmt.Errorf("failed to join shard group: %w", err)
+				}
+				run(leaderElectionCtx)
+			} else if options.LeaderElection.Enabled {
+				logger.Info("trying to acquire the lock")
+				lock := NewLock(config.KubeClient, options.LeaseLockNamespace, options.LeaseLockName, options.LeaseLockIdentity)
+				runLeaderElection(leaderElectionCtx, lock, options.LeaseLockIdentity, options.LeaderElection, watchDog, run)
+			} else {
+				logger.Info("leader election disabled, starting immediately")
+				run(leaderElectionCtx)
+			}
+
+			// leader election returns early when losing the lease. Wait for draining.
+			select {
+			case <-started:
+				<-drained
+			default:
+			}
 
 			return err
 		},
 	}
 	options.AddFlags(cmd.Flags())
 
+	cmd.AddCommand(newManifestsCommand())
+
 	return cmd
 }
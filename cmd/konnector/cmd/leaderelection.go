@@ -18,13 +18,14 @@ package cmd
 
 import (
 	"context"
-	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/klog/v2"
+
+	konnectoroptions "github.com/kube-bind/kube-bind/pkg/konnector/options"
 )
 
 func NewLock(client kubeclient.Interface, namespace, lockName, podName string) *resourcelock.LeaseLock {
@@ -40,19 +41,20 @@ func NewLock(client kubeclient.Interface, namespace, lockName, podName string) *
 	}
 }
 
-func runLeaderElection(ctx context.Context, lock *resourcelock.LeaseLock, id string, run func(ctx context.Context)) {
+func runLeaderElection(ctx context.Context, lock *resourcelock.LeaseLock, id string, timings konnectoroptions.LeaderElection, watchDog *leaderelection.HealthzAdaptor, run func(ctx context.Context)) {
 	logger := klog.FromContext(ctx)
 
 	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
 		Lock:            lock,
 		ReleaseOnCancel: true,
-		LeaseDuration:   15 * time.Second,
-		RenewDeadline:   10 * time.Second,
-		RetryPeriod:     2 * time.Second,
+		LeaseDuration:   timings.LeaseDuration,
+		RenewDeadline:   timings.RenewDeadline,
+		RetryPeriod:     timings.RetryPeriod,
+		WatchDog:        watchDog,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(c context.Context) {
 				logger.Info("started leading", "id", id)
-				run(ctx)
+				run(c)
 			},
 			OnStoppedLeading: func() {
 				logger.Info("no longer the leader, staying inactive.")
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	componentbaseversion "k8s.io/component-base/version"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kube-bind/kube-bind/deploy/konnector"
+	bindversion "github.com/kube-bind/kube-bind/pkg/version"
+)
+
+var (
+	manifestsExampleUses = `
+	# print the konnector installation of this konnector version.
+	%[1]s manifests | kubectl apply -f -
+
+	# install into a custom namespace with metrics scraped by the Prometheus operator.
+	%[1]s manifests --namespace konnector --metrics --service-monitor | kubectl apply -f -
+
+	# install without granting cluster-admin to the konnector.
+	%[1]s manifests --least-privilege | kubectl apply -f -
+
+	# write a kustomize base to overlay with local patches.
+	%[1]s manifests --output-dir ./konnector/base
+	`
+)
+
+func newManifestsCommand() *cobra.Command {
+	image := "ghcr.io/kube-bind/konnector:latest"
+	if ver, err := bindversion.BinaryVersion(componentbaseversion.Get().GitVersion); err == nil && ver != "v0.0.0" {
+		image = "ghcr.io/kube-bind/konnector:" + ver
+	}
+	opts := konnector.ManifestOptions{
+		Namespace: "kube-bind",
+		Image:     image,
+	}
+	var outputDir string
+
+	cmd := &cobra.Command{
+		Use:          "manifests",
+		Short:        "Print the konnector installation manifests",
+		Example:      fmt.Sprintf(manifestsExampleUses, "konnector"),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Namespace == "" {
+				return fmt.Errorf("--namespace must not be empty")
+			}
+			if opts.Image == "" {
+				return fmt.Errorf("--image must not be empty")
+			}
+
+			manifests, err := konnector.Manifests(opts)
+			if err != nil {
+				return err
+			}
+
+			if outputDir == "" {
+				var buf bytes.Buffer
+				for _, m := range manifests {
+					for _, obj := range m.Objects {
+						bs, err := yaml.Marshal(obj.Object)
+						if err != nil {
+							return err
+						}
+						buf.WriteString("---\n")
+						buf.Write(bs)
+					}
+				}
+				_, err := cmd.OutOrStdout().Write(buf.Bytes())
+				return err
+			}
+
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				return err
+			}
+			kustomization := "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n"
+			for _, m := range manifests {
+				var buf bytes.Buffer
+				for i, obj := range m.Objects {
+					bs, err := yaml.Marshal(obj.Object)
+					if err != nil {
+						return err
+					}
+					if i > 0 {
+						buf.WriteString("---\n")
+					}
+					buf.Write(bs)
+				}
+				if err := os.WriteFile(filepath.Join(outputDir, m.Name), buf.Bytes(), 0644); err != nil {
+					return err
+				}
+				kustomization += "- " + m.Name + "\n"
+			}
+			return os.WriteFile(filepath.Join(outputDir, "kustomization.yaml"), []byte(kustomization), 0644)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Namespace, "namespace", "n", opts.Namespace, "The namespace to install the konnector into.")
+	cmd.Flags().StringVar(&opts.Image, "image", opts.Image, "The konnector image.")
+	cmd.Flags().BoolVar(&opts.Metrics, "metrics", opts.Metrics, "Serve metrics and add a Service for them.")
+	cmd.Flags().BoolVar(&opts.ServiceMonitor, "service-monitor", opts.ServiceMonitor, "Add a Prometheus operator ServiceMonitor for the metrics Service. Requires --metrics.")
+	cmd.Flags().BoolVar(&opts.LeastPrivilege, "least-privilege", opts.LeastPrivilege, "Grant the konnector only the permissions it needs instead of cluster-admin. It reconciles the permissions on bound resources itself.")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", outputDir, "Write the manifests and a kustomization.yaml as kustomize base into this directory instead of printing them.")
+
+	return cmd
+}
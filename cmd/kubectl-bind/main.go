@@ -25,6 +25,20 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	apiservicecmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-apiservice/cmd"
+	bundlecmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-bundle/cmd"
+	democmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-demo/cmd"
+	fleetcmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-fleet/cmd"
+	initprovidercmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-init-provider/cmd"
+	konnectorcmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-konnector/cmd"
+	lintexportcmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-lint-export/cmd"
+	listcmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-list/cmd"
+	logscmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-logs/cmd"
+	rotatecredentialscmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-rotate-credentials/cmd"
+	simulateclaimscmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-simulate-claims/cmd"
+	statuscmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-status/cmd"
+	testconnectioncmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-test-connection/cmd"
+	upgradeschemacmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-upgrade-schema/cmd"
+	usagecmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind-usage/cmd"
 	bindcmd "github.com/kube-bind/kube-bind/pkg/kubectl/bind/cmd"
 )
 
@@ -45,6 +59,104 @@ func main() {
 	}
 	bindCmd.AddCommand(apiserviceCmd)
 
+	testConnectionCmd, err := testconnectioncmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(testConnectionCmd)
+
+	logsCmd, err := logscmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(logsCmd)
+
+	initProviderCmd, err := initprovidercmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(initProviderCmd)
+
+	bundleCmd, err := bundlecmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(bundleCmd)
+
+	rotateCredentialsCmd, err := rotatecredentialscmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(rotateCredentialsCmd)
+
+	listCmd, err := listcmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(listCmd)
+
+	usageCmd, err := usagecmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(usageCmd)
+
+	statusCmd, err := statuscmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(statusCmd)
+
+	upgradeSchemaCmd, err := upgradeschemacmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(upgradeSchemaCmd)
+
+	fleetCmd, err := fleetcmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(fleetCmd)
+
+	lintExportCmd, err := lintexportcmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(lintExportCmd)
+
+	simulateClaimsCmd, err := simulateclaimscmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(simulateClaimsCmd)
+
+	demoCmd, err := democmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(demoCmd)
+
+	konnectorCmd, err := konnectorcmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
+	bindCmd.AddCommand(konnectorCmd)
+
 	if err := bindCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -0,0 +1,400 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package admin implements the "example-backend admin" commands for service
+// provider operators, e.g. to list consumers, approve requests or revoke access.
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/cookie"
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+)
+
+type options struct {
+	kubeconfig string
+
+	kubeClient kubeclient.Interface
+	bindClient bindclient.Interface
+}
+
+func (o *options) complete() error {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, nil).ClientConfig()
+	if err != nil {
+		return err
+	}
+	config = rest.AddUserAgent(rest.CopyConfig(config), "kube-bind-example-backend-admin")
+
+	if o.kubeClient, err = kubeclient.NewForConfig(config); err != nil {
+		return err
+	}
+	if o.bindClient, err = bindclient.NewForConfig(config); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewCommand returns the admin command with its sub-commands.
+func NewCommand() *cobra.Command {
+	opts := &options{}
+	cmd := &cobra.Command{
+		Use:          "admin",
+		Short:        "Administrate the consumers of the service provider",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.complete()
+		},
+	}
+	cmd.CompletionOptions.DisableDefaultCmd = true
+	cmd.PersistentFlags().StringVar(&opts.kubeconfig, "kubeconfig", opts.kubeconfig, "path to a kubeconfig. Only required if out-of-cluster")
+
+	cmd.AddCommand(
+		newClusterBindingsCommand(opts),
+		newRequestsCommand(opts),
+		newApproveCommand(opts, kuberesources.ApprovalApproved),
+		newApproveCommand(opts, kuberesources.ApprovalDenied),
+		newRevokeCommand(opts),
+		newResyncCommand(opts),
+		newHeartbeatsCommand(opts),
+		newRotateSigningKeysCommand(opts),
+	)
+
+	return cmd
+}
+
+func newClusterBindingsCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:     "clusterbindings",
+		Aliases: []string{"list"},
+		Short:   "List the ClusterBindings of all consumers",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bindings, err := listClusterBindings(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			identities, err := namespaceIdentities(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "NAMESPACE\tIDENTITY\tREADY\tKONNECTOR\tHEARTBEAT") // nolint: errcheck
+			for _, cb := range bindings {
+				ready := "Unknown"
+				if c := conditions.Get(cb, "Ready"); c != nil {
+					ready = string(c.Status)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", cb.Namespace, identities[cb.Namespace], ready, valueOrNone(cb.Status.KonnectorVersion), heartbeatState(cb, time.Now())) // nolint: errcheck
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newRequestsCommand(opts *options) *cobra.Command {
+	all := false
+	cmd := &cobra.Command{
+		Use:   "requests",
+		Short: "List pending APIServiceExportRequests",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			requests, err := opts.bindClient.KubeBindV1alpha1().APIServiceExportRequests(metav1.NamespaceAll).List(cmd.Context(), metav1.ListOptions{})
+			if err != nil {
+				return err
+			}
+			sort.Slice(requests.Items, func(i, j int) bool {
+				return requests.Items[i].CreationTimestamp.Before(&requests.Items[j].CreationTimestamp)
+			})
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tRESOURCES\tPHASE\tAPPROVAL\tAGE") // nolint: errcheck
+			for _, req := range requests.Items {
+				if !all && req.Status.Phase != "" && req.Status.Phase != kubebindv1alpha1.APIServiceExportRequestPhasePending {
+					continue
+				}
+				var resources []string
+				for _, res := range req.Spec.Resources {
+					resources = append(resources, res.Resource+"."+res.Group)
+				}
+				age := duration.HumanDuration(time.Since(req.CreationTimestamp.Time))
+				fmt.Fprintf(w, "%s/%s\t%s\t%s\t%s\t%s\n", req.Namespace, req.Name, strings.Join(resources, ","), valueOrNone(string(req.Status.Phase)), valueOrNone(req.Annotations[kuberesources.ApprovalAnnotation]), age) // nolint: errcheck
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", all, "List all requests, not only pending ones")
+	return cmd
+}
+
+func newApproveCommand(opts *options, decision string) *cobra.Command {
+	verb, short := "approve", "Approve a pending APIServiceExportRequest. Requires the backend to run with --require-approval"
+	if decision == kuberesources.ApprovalDenied {
+		verb, short = "deny", "Deny a pending APIServiceExportRequest. Requires the backend to run with --require-approval"
+	}
+	message := ""
+	cmd := &cobra.Command{
+		Use:   verb + " <namespace>/<name>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns, name, err := splitNamespaceName(args[0])
+			if err != nil {
+				return err
+			}
+			if err := kuberesources.SetApproval(cmd.Context(), opts.bindClient, ns, name, decision, message); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "APIServiceExportRequest %s/%s %s\n", ns, name, strings.ToLower(decision)) // nolint: errcheck
+			return nil
+		},
+	}
+	if decision == kuberesources.ApprovalDenied {
+		cmd.Flags().StringVar(&message, "message", message, "The reason shown to the consumer")
+	}
+	return cmd
+}
+
+func newRevokeCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke <namespace>",
+		Short: "Revoke all credentials of the consumer of the given namespace",
+		Long:  "Revoke all credentials of the consumer of the given namespace. The konnector loses access immediately, and the consumer has to bind again to get new credentials.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns := args[0]
+			if _, err := opts.bindClient.KubeBindV1alpha1().ClusterBindings(ns).Get(cmd.Context(), kuberesources.ClusterBindingName, metav1.GetOptions{}); err != nil {
+				return fmt.Errorf("namespace %s does not belong to a consumer: %w", ns, err)
+			}
+			revoked, deleted, err := kuberesources.RevokeConsumer(cmd.Context(), opts.kubeClient, ns)
+			for _, name := range revoked {
+				fmt.Fprintf(cmd.OutOrStdout(), "Revoked credentials secret %s/%s\n", ns, name) // nolint: errcheck
+			}
+			if err != nil {
+				return err
+			}
+			if deleted {
+				fmt.Fprintf(cmd.OutOrStdout(), "Deleted service account %s/%s\n", ns, kuberesources.ServiceAccountName) // nolint: errcheck
+			} else if len(revoked) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No credentials found in namespace %s\n", ns) // nolint: errcheck
+			}
+			return nil
+		},
+	}
+}
+
+func newResyncCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resync <namespace>[/<name>]",
+		Short: "Notify the konnector of a consumer to resync one or all APIServiceExports",
+		Long:  "Notify the konnector of a consumer to resync one or all APIServiceExports, e.g. after changing claims. The konnector watches the exports and restarts their sync immediately.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns, name, _ := strings.Cut(args[0], "/")
+
+			var names []string
+			if name != "" {
+				names = append(names, name)
+			} else {
+				exports, err := opts.bindClient.KubeBindV1alpha1().APIServiceExports(ns).List(cmd.Context(), metav1.ListOptions{})
+				if err != nil {
+					return err
+				}
+				for _, export := range exports.Items {
+					names = append(names, export.Name)
+				}
+				if len(names) == 0 {
+					fmt.Fprintf(cmd.OutOrStdout(), "No APIServiceExports found in namespace %s\n", ns) // nolint: errcheck
+					return nil
+				}
+			}
+
+			now := time.Now()
+			for _, name := range names {
+				if err := kuberesources.RequestResync(cmd.Context(), opts.bindClient, ns, name, now); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Requested resync of APIServiceExport %s/%s\n", ns, name) // nolint: errcheck
+			}
+			return nil
+		},
+	}
+}
+
+func newHeartbeatsCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "heartbeats [<namespace>]",
+		Short: "Inspect the konnector heartbeats of all or one consumer",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bindings, err := listClusterBindings(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "NAMESPACE\tLAST HEARTBEAT\tINTERVAL\tSTATE\tKONNECTOR COMMIT") // nolint: errcheck
+			for _, cb := range bindings {
+				if len(args) > 0 && cb.Namespace != args[0] {
+					continue
+				}
+				last := "<none>"
+				if !cb.Status.LastHeartbeatTime.IsZero() {
+					last = cb.Status.LastHeartbeatTime.UTC().Format(time.RFC3339)
+				}
+				commit := "<none>"
+				if cb.Status.KonnectorBuild != nil && cb.Status.KonnectorBuild.GitCommit != "" {
+					commit = cb.Status.KonnectorBuild.GitCommit
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", cb.Namespace, last, cb.Status.HeartbeatInterval.Duration, heartbeatState(cb, now), commit) // nolint: errcheck
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newRotateSigningKeysCommand(opts *options) *cobra.Command {
+	secret := ""
+	keep := 2
+	cmd := &cobra.Command{
+		Use:   "rotate-signing-keys",
+		Short: "Generate new cookie signing and encryption keys",
+		Long: `Generate new cookie signing and encryption keys.
+
+With --secret, a new key version is added to the Secret passed to the backend via
+--cookie-keys-secret. The backend signs with the new version right away and keeps
+accepting the older versions still in the Secret, so binding sessions in flight
+continue to work.
+
+Without --secret, the keys are printed to pass to --cookie-signing-key and
+--cookie-encryption-key. Binding sessions in flight during the restart of the
+backend have to be started again.`,
+		Args: cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if secret == "" {
+				return nil // no cluster access needed
+			}
+			return opts.complete()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			signingKey, encryptionKey, err := cookie.GenerateKeys()
+			if err != nil {
+				return err
+			}
+			if secret == "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "--cookie-signing-key=%s\n", base64.StdEncoding.EncodeToString(signingKey))       // nolint: errcheck
+				fmt.Fprintf(cmd.OutOrStdout(), "--cookie-encryption-key=%s\n", base64.StdEncoding.EncodeToString(encryptionKey)) // nolint: errcheck
+				return nil
+			}
+
+			ns, name, err := splitNamespaceName(secret)
+			if err != nil {
+				return err
+			}
+			version, pruned, err := cookie.RotateKeysSecret(cmd.Context(), opts.kubeClient, ns, name, signingKey, encryptionKey, keep)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Added cookie key version %d to secret %s/%s\n", version, ns, name) // nolint: errcheck
+			for _, v := range pruned {
+				fmt.Fprintf(cmd.OutOrStdout(), "Removed cookie key version %d\n", v) // nolint: errcheck
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&secret, "secret", secret, "The <namespace>/<name> of the cookie keys secret to add a new key version to. It is created if it does not exist.")
+	cmd.Flags().IntVar(&keep, "keep", keep, "The number of newest key versions to keep in the secret, including the new one.")
+	return cmd
+}
+
+func listClusterBindings(ctx context.Context, opts *options) ([]*kubebindv1alpha1.ClusterBinding, error) {
+	list, err := opts.bindClient.KubeBindV1alpha1().ClusterBindings(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	bindings := make([]*kubebindv1alpha1.ClusterBinding, 0, len(list.Items))
+	for i := range list.Items {
+		bindings = append(bindings, &list.Items[i])
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Namespace < bindings[j].Namespace
+	})
+	return bindings, nil
+}
+
+func namespaceIdentities(ctx context.Context, opts *options) (map[string]string, error) {
+	nss, err := opts.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	identities := map[string]string{}
+	for _, ns := range nss.Items {
+		if id, found := ns.Annotations[kuberesources.IdentityAnnotationKey]; found {
+			identities[ns.Name] = id
+		}
+	}
+	return identities, nil
+}
+
+// heartbeatState summarizes the heartbeat of the konnector of a ClusterBinding.
+// A heartbeat is stale if it is older than twice the promised interval.
+func heartbeatState(cb *kubebindv1alpha1.ClusterBinding, now time.Time) string {
+	if cb.Status.LastHeartbeatTime.IsZero() {
+		return "Never"
+	}
+	age := now.Sub(cb.Status.LastHeartbeatTime.Time)
+	ago := duration.HumanDuration(age) + " ago"
+	if interval := cb.Status.HeartbeatInterval.Duration; interval > 0 && age > 2*interval {
+		return "Stale (" + ago + ")"
+	}
+	return "Healthy (" + ago + ")"
+}
+
+func splitNamespaceName(s string) (string, string, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("expected <namespace>/<name>, got %q", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestHeartbeatState(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		last     time.Time
+		interval time.Duration
+		want     string
+	}{
+		{name: "never", want: "Never"},
+		{name: "healthy", last: now.Add(-time.Minute), interval: 5 * time.Minute, want: "Healthy (60s ago)"},
+		{name: "late but not stale", last: now.Add(-7 * time.Minute), interval: 5 * time.Minute, want: "Healthy (7m ago)"},
+		{name: "stale", last: now.Add(-11 * time.Minute), interval: 5 * time.Minute, want: "Stale (11m ago)"},
+		{name: "no interval", last: now.Add(-time.Hour), want: "Healthy (60m ago)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := &kubebindv1alpha1.ClusterBinding{
+				Status: kubebindv1alpha1.ClusterBindingStatus{
+					HeartbeatInterval: metav1.Duration{Duration: tt.interval},
+				},
+			}
+			if !tt.last.IsZero() {
+				cb.Status.LastHeartbeatTime = metav1.NewTime(tt.last)
+			}
+			require.Equal(t, tt.want, heartbeatState(cb, now))
+		})
+	}
+}
+
+func TestSplitNamespaceName(t *testing.T) {
+	ns, name, err := splitNamespaceName("cluster-abc/export-xyz")
+	require.NoError(t, err)
+	require.Equal(t, "cluster-abc", ns)
+	require.Equal(t, "export-xyz", name)
+
+	for _, s := range []string{"export-xyz", "/export-xyz", "cluster-abc/"} {
+		_, _, err := splitNamespaceName(s)
+		require.Error(t, err, s)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendtest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/securecookie"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	backend "github.com/kube-bind/kube-bind/contrib/example-backend"
+	"github.com/kube-bind/kube-bind/contrib/example-backend/options"
+	"github.com/kube-bind/kube-bind/deploy/crd"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// callbackURL is where the backend redirects to with the BindingResponse. It is
+// never dialed, the redirect is intercepted by Bind.
+const callbackURL = "http://kube-bind.invalid/callback"
+
+// StartBackend installs the kube-bind CRDs into the service provider cluster of the
+// given client config and starts the example backend with the fake OIDC issuer on a
+// random local port. It is stopped on test cleanup. Extra backend flags can be
+// passed as args.
+func StartBackend(t *testing.T, clientConfig *rest.Config, issuer *OIDCIssuer, args ...string) (net.Addr, *backend.Server) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	crdClient, err := apiextensionsclient.NewForConfig(clientConfig)
+	require.NoError(t, err)
+	err = crd.Create(ctx,
+		crdClient.ApiextensionsV1().CustomResourceDefinitions(),
+		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "clusterbindings"},
+		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiserviceexports"},
+		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiservicenamespaces"},
+		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiserviceexportrequests"},
+		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiserviceactions"},
+	)
+	require.NoError(t, err)
+
+	signingKey := securecookie.GenerateRandomKey(32)
+	require.NotEmpty(t, signingKey, "error creating signing key")
+
+	fs := pflag.NewFlagSet("example-backend", pflag.ContinueOnError)
+	opts := options.NewOptions()
+	opts.AddFlags(fs)
+	err = fs.Parse(append([]string{
+		"--oidc-issuer-client-id=" + ClientID,
+		"--oidc-issuer-client-secret=" + ClientSecret,
+		"--oidc-issuer-url=" + issuer.IssuerURL(),
+		"--cookie-signing-key=" + base64.StdEncoding.EncodeToString(signingKey),
+	}, args...))
+	require.NoError(t, err)
+
+	opts.Serve.Listener, err = net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	addr := opts.Serve.Listener.Addr()
+	opts.OIDC.CallbackURL = fmt.Sprintf("http://%s/callback", addr)
+
+	completed, err := opts.Complete()
+	require.NoError(t, err)
+	config, err := backend.NewConfig(completed)
+	require.NoError(t, err)
+	server, err := backend.NewServer(config)
+	require.NoError(t, err)
+
+	server.OptionallyStartInformers(ctx)
+	err = server.Run(ctx)
+	require.NoError(t, err)
+	t.Logf("backend listening on %s", addr)
+
+	return addr, server
+}
+
+// Session is a logged in browser session with the backend.
+type Session struct {
+	// ID is the session ID of the kubectl bind flow.
+	ID string
+
+	client  *http.Client
+	baseURL string
+}
+
+// Login runs the OAuth2 code flow of kubectl bind for the given consumer cluster ID
+// against the backend, following the redirects through the OIDC issuer like a
+// browser, and returns the logged in session.
+func Login(t *testing.T, addr net.Addr, clusterID string) *Session {
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+
+	s := &Session{
+		ID:      strings.ReplaceAll(t.Name(), "/", "-"),
+		baseURL: "http://" + addr.String(),
+		client: &http.Client{
+			Jar: jar,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if strings.HasPrefix(req.URL.String(), callbackURL) {
+					return http.ErrUseLastResponse
+				}
+				return nil
+			},
+		},
+	}
+
+	values := url.Values{"u": {callbackURL}, "s": {s.ID}, "c": {clusterID}}
+	resp, err := s.client.Get(s.baseURL + "/authorize?" + values.Encode())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body) // nolint:errcheck
+	require.Equal(t, http.StatusOK, resp.StatusCode, "login failed: %s", body)
+	require.Equal(t, "/resources", resp.Request.URL.Path, "login did not end on the resources page")
+
+	return s
+}
+
+// Get requests the path of the backend within the session.
+func (s *Session) Get(path string) (*http.Response, error) {
+	return s.client.Get(s.baseURL + path)
+}
+
+// Bind selects the resource on the bind screen, and returns the BindingResponse
+// the backend sends back to kubectl bind. It fails the test if the backend does
+// not respond with a redirect, e.g. because of unmet identity requirements.
+func (s *Session) Bind(t *testing.T, group, resource string) *kubebindv1alpha1.BindingResponse {
+	values := url.Values{"s": {s.ID}, "group": {group}, "resource": {resource}}
+	resp, err := s.Get("/bind?" + values.Encode())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body) // nolint:errcheck
+	require.Equal(t, http.StatusFound, resp.StatusCode, "bind failed: %s", body)
+
+	location, err := resp.Location()
+	require.NoError(t, err)
+	encoded := location.Query().Get("response")
+	require.NotEmpty(t, encoded, "no response in redirect to %s", location)
+	bs, err := base64.StdEncoding.DecodeString(encoded)
+	require.NoError(t, err)
+
+	var response kubebindv1alpha1.BindingResponse
+	require.NoError(t, json.Unmarshal(bs, &response))
+	return &response
+}
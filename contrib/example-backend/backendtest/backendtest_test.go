@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendtest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/coreos/go-oidc"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/catalog"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestOIDCIssuer(t *testing.T) {
+	ctx := context.Background()
+	issuer := NewOIDCIssuer(t)
+	issuer.SetClaims(map[string]interface{}{"sub": "alice", "email_verified": false})
+
+	provider, err := oidc.NewProvider(ctx, issuer.IssuerURL())
+	require.NoError(t, err)
+	config := &oauth2.Config{
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  "http://localhost/callback",
+		Scopes:       []string{oidc.ScopeOpenID},
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(config.AuthCodeURL("state"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "state", location.Query().Get("state"))
+
+	token, err := config.Exchange(ctx, location.Query().Get("code"))
+	require.NoError(t, err)
+	rawIDToken, ok := token.Extra("id_token").(string)
+	require.True(t, ok, "no id_token in token response")
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: ClientID}).Verify(ctx, rawIDToken)
+	require.NoError(t, err)
+	var claims struct {
+		EmailVerified bool `json:"email_verified"`
+	}
+	require.NoError(t, idToken.Claims(&claims))
+	require.Equal(t, "alice", idToken.Subject)
+	require.False(t, claims.EmailVerified)
+
+	_, err = config.Exchange(ctx, location.Query().Get("code"))
+	require.Error(t, err, "codes must only be redeemable once")
+}
+
+func TestNewCatalog(t *testing.T) {
+	unexported := ExportedCRD("example.com", "bars", apiextensionsv1.NamespaceScoped)
+	unexported.Labels = nil
+	cache := NewCatalog(t, kubebindv1alpha1.NamespacedScope,
+		ExportedCRD("example.com", "foos", apiextensionsv1.NamespaceScoped),
+		ExportedCRD("example.com", "clusterfoos", apiextensionsv1.ClusterScoped),
+		unexported,
+	)
+
+	_, err := cache.Schema("example.com", "foos")
+	require.NoError(t, err)
+	for _, resource := range []string{"clusterfoos", "bars"} {
+		_, err := cache.Schema("example.com", resource)
+		require.ErrorIs(t, err, catalog.ErrNotFound, "%s must not be in the catalog", resource)
+	}
+}
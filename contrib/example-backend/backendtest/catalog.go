@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/catalog"
+	"github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// NewCatalog returns an in-memory catalog of the given CRDs, without a cluster.
+// CRDs are only listed if they carry the kube-bind.io/exported label, e.g. as
+// returned by ExportedCRD.
+func NewCatalog(t *testing.T, scope kubebindv1alpha1.Scope, crds ...*apiextensionsv1.CustomResourceDefinition) *catalog.Cache {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	objs := make([]runtime.Object, 0, len(crds))
+	for _, crd := range crds {
+		objs = append(objs, crd)
+	}
+	client := apiextensionsfake.NewSimpleClientset(objs...)
+	informers := apiextensionsinformers.NewSharedInformerFactory(client, time.Minute)
+	cache := catalog.NewCache(scope, informers.Apiextensions().V1().CustomResourceDefinitions())
+
+	informers.Start(ctx.Done())
+	for typ, synced := range informers.WaitForCacheSync(ctx.Done()) {
+		require.True(t, synced, "informer for %v not synced", typ)
+	}
+
+	return cache
+}
+
+// ExportedCRD returns a minimal v1 CRD of the given group and plural, labeled to
+// be exported by the backend.
+func ExportedCRD(group, plural string, scope apiextensionsv1.ResourceScope) *apiextensionsv1.CustomResourceDefinition {
+	singular := strings.TrimSuffix(plural, "s")
+	kind := strings.ToUpper(singular[:1]) + singular[1:]
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Scope: scope,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   plural,
+				Singular: singular,
+				Kind:     kind,
+				ListKind: kind + "List",
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type:                   "object",
+						XPreserveUnknownFields: pointer.Bool(true),
+					},
+				},
+			}},
+		},
+	}
+	crd.Name = plural + "." + group
+	crd.Labels = map[string]string{resources.ExportedCRDsLabel: "true"}
+	return crd
+}
@@ -0,0 +1,25 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backendtest provides fixtures for integration tests of kube-bind backends
+// and their frontends: a fake OIDC issuer, an in-memory catalog, and helpers to run
+// the example backend and walk through the kubectl bind login and bind flow:
+//
+//	issuer := backendtest.NewOIDCIssuer(t)
+//	addr, _ := backendtest.StartBackend(t, providerConfig, issuer)
+//	session := backendtest.Login(t, addr, "consumer-cluster-id")
+//	response := session.Bind(t, "example.com", "foos")
+package backendtest
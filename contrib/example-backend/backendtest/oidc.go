@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/devoidc"
+)
+
+const (
+	// ClientID is the OIDC client ID the fake issuer expects.
+	ClientID = devoidc.ClientID
+	// ClientSecret is the OIDC client secret the fake issuer expects.
+	ClientSecret = devoidc.ClientSecret
+)
+
+// OIDCIssuer is a fake OIDC issuer that logs in every authorization request
+// immediately, without user interaction, and issues RS256 signed ID tokens with
+// the configured claims.
+type OIDCIssuer struct {
+	*devoidc.Issuer
+}
+
+// NewOIDCIssuer starts a fake OIDC issuer that is closed on test cleanup. The ID
+// tokens carry the subject "user" with a verified e-mail address by default.
+func NewOIDCIssuer(t *testing.T) *OIDCIssuer {
+	issuer, err := devoidc.Start("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(issuer.Close)
+
+	return &OIDCIssuer{Issuer: issuer}
+}
+
+// IssuerURL is the issuer URL to configure the backend with.
+func (i *OIDCIssuer) IssuerURL() string {
+	return i.URL
+}
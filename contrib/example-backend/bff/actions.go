@@ -0,0 +1,243 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/cookie"
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+)
+
+const (
+	// ApprovePath approves a pending APIServiceExportRequest.
+	ApprovePath = "/bff/v1/requests/{namespace}/{name}/approve"
+	// DenyPath denies a pending APIServiceExportRequest. The body can carry an
+	// ActionRequest with the reason shown to the consumer.
+	DenyPath = "/bff/v1/requests/{namespace}/{name}/deny"
+	// RevokePath revokes all credentials of the consumer of a namespace.
+	RevokePath = "/bff/v1/consumers/{namespace}/revoke"
+	// ResyncPath notifies the konnector of a consumer to resync all APIServiceExports.
+	ResyncPath = "/bff/v1/consumers/{namespace}/resync"
+	// RotateSigningKeysPath adds a new key version to the cookie keys secret.
+	RotateSigningKeysPath = "/bff/v1/rotate-signing-keys"
+)
+
+// ActionRequest is the optional body of an action.
+type ActionRequest struct {
+	Message string `json:"message,omitempty"`
+}
+
+// ActionResult is the response of a successful action.
+type ActionResult struct {
+	Message string `json:"message"`
+}
+
+// Actions serves the endpoints changing kube-bind objects on behalf of a
+// dashboard user. Like the admin commands, they act with the identity of the
+// backend, after checking that the user could perform them in the service
+// provider cluster directly.
+type Actions struct {
+	authorizer *Authorizer
+	kubeClient kubeclient.Interface
+	bindClient bindclient.Interface
+
+	cookieKeysNamespace, cookieKeysName string
+	cookieKeysKeep                      int
+}
+
+// NewActions returns the Actions. Rotation of cookie keys is only offered with
+// a cookie keys secret, i.e. if cookieKeysName is non-empty.
+func NewActions(authorizer *Authorizer, kubeClient kubeclient.Interface, bindClient bindclient.Interface, cookieKeysNamespace, cookieKeysName string) *Actions {
+	return &Actions{
+		authorizer:          authorizer,
+		kubeClient:          kubeClient,
+		bindClient:          bindClient,
+		cookieKeysNamespace: cookieKeysNamespace,
+		cookieKeysName:      cookieKeysName,
+		cookieKeysKeep:      2,
+	}
+}
+
+func (a *Actions) AddRoutes(mux *mux.Router) {
+	mux.HandleFunc(ApprovePath, a.handleApproval(kuberesources.ApprovalApproved)).Methods("POST")
+	mux.HandleFunc(DenyPath, a.handleApproval(kuberesources.ApprovalDenied)).Methods("POST")
+	mux.HandleFunc(RevokePath, a.handleRevoke).Methods("POST")
+	mux.HandleFunc(ResyncPath, a.handleResync).Methods("POST")
+	mux.HandleFunc(RotateSigningKeysPath, a.handleRotateSigningKeys).Methods("POST")
+}
+
+func (a *Actions) handleApproval(decision string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ns, name := mux.Vars(r)["namespace"], mux.Vars(r)["name"]
+		user, ok := a.authorize(w, r, authorizationv1.ResourceAttributes{
+			Verb:      "patch",
+			Group:     kubebindv1alpha1.GroupName,
+			Resource:  "apiserviceexportrequests",
+			Namespace: ns,
+			Name:      name,
+		})
+		if !ok {
+			return
+		}
+
+		var body ActionRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, 4096)).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if decision == kuberesources.ApprovalApproved {
+			body.Message = ""
+		}
+
+		if err := kuberesources.SetApproval(r.Context(), a.bindClient, ns, name, decision, body.Message); err != nil {
+			a.writeError(w, r, err)
+			return
+		}
+		klog.FromContext(r.Context()).Info("APIServiceExportRequest decided on dashboard", "namespace", ns, "name", name, "decision", decision, "user", user)
+		writeResult(w, fmt.Sprintf("APIServiceExportRequest %s/%s %s", ns, name, decision))
+	}
+}
+
+func (a *Actions) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	ns := mux.Vars(r)["namespace"]
+	user, ok := a.authorize(w, r, authorizationv1.ResourceAttributes{
+		Verb:      "delete",
+		Resource:  "serviceaccounts",
+		Namespace: ns,
+		Name:      kuberesources.ServiceAccountName,
+	})
+	if !ok {
+		return
+	}
+
+	if _, err := a.bindClient.KubeBindV1alpha1().ClusterBindings(ns).Get(r.Context(), kuberesources.ClusterBindingName, metav1.GetOptions{}); err != nil {
+		a.writeError(w, r, err)
+		return
+	}
+	revoked, deleted, err := kuberesources.RevokeConsumer(r.Context(), a.kubeClient, ns)
+	if err != nil {
+		a.writeError(w, r, err)
+		return
+	}
+	klog.FromContext(r.Context()).Info("Consumer revoked on dashboard", "namespace", ns, "secrets", revoked, "serviceAccountDeleted", deleted, "user", user)
+	writeResult(w, fmt.Sprintf("Revoked %d credentials of consumer %s", len(revoked), ns))
+}
+
+func (a *Actions) handleResync(w http.ResponseWriter, r *http.Request) {
+	ns := mux.Vars(r)["namespace"]
+	user, ok := a.authorize(w, r, authorizationv1.ResourceAttributes{
+		Verb:      "patch",
+		Group:     kubebindv1alpha1.GroupName,
+		Resource:  "apiserviceexports",
+		Namespace: ns,
+	})
+	if !ok {
+		return
+	}
+
+	exports, err := a.bindClient.KubeBindV1alpha1().APIServiceExports(ns).List(r.Context(), metav1.ListOptions{})
+	if err != nil {
+		a.writeError(w, r, err)
+		return
+	}
+	now := time.Now()
+	for _, export := range exports.Items {
+		if err := kuberesources.RequestResync(r.Context(), a.bindClient, ns, export.Name, now); err != nil {
+			a.writeError(w, r, err)
+			return
+		}
+	}
+	klog.FromContext(r.Context()).Info("Resync requested on dashboard", "namespace", ns, "exports", len(exports.Items), "user", user)
+	writeResult(w, fmt.Sprintf("Requested resync of %d APIServiceExports of consumer %s", len(exports.Items), ns))
+}
+
+func (a *Actions) handleRotateSigningKeys(w http.ResponseWriter, r *http.Request) {
+	if a.cookieKeysName == "" {
+		http.Error(w, "the backend runs without --cookie-keys-secret", http.StatusConflict)
+		return
+	}
+	user, ok := a.authorize(w, r, authorizationv1.ResourceAttributes{
+		Verb:      "update",
+		Resource:  "secrets",
+		Namespace: a.cookieKeysNamespace,
+		Name:      a.cookieKeysName,
+	})
+	if !ok {
+		return
+	}
+
+	signingKey, encryptionKey, err := cookie.GenerateKeys()
+	if err != nil {
+		a.writeError(w, r, err)
+		return
+	}
+	version, pruned, err := cookie.RotateKeysSecret(r.Context(), a.kubeClient, a.cookieKeysNamespace, a.cookieKeysName, signingKey, encryptionKey, a.cookieKeysKeep)
+	if err != nil {
+		a.writeError(w, r, err)
+		return
+	}
+	klog.FromContext(r.Context()).Info("Cookie keys rotated on dashboard", "version", version, "pruned", pruned, "user", user)
+	writeResult(w, fmt.Sprintf("Added cookie key version %d", version))
+}
+
+// authorize writes the error response and returns false if the user of the
+// request may not perform the action.
+func (a *Actions) authorize(w http.ResponseWriter, r *http.Request, attrs authorizationv1.ResourceAttributes) (string, bool) {
+	user, err := a.authorizer.Authorize(r, attrs)
+	if err != nil {
+		klog.FromContext(r.Context()).V(2).Info("refusing action", "url", r.URL.String(), "err", err)
+		writeAuthError(w, err)
+		return "", false
+	}
+	return user, true
+}
+
+func (a *Actions) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if apierrors.IsNotFound(err) {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	klog.FromContext(r.Context()).Error(err, "action failed", "url", r.URL.String())
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+func writeResult(w http.ResponseWriter, message string) {
+	bs, err := json.Marshal(ActionResult{Message: message})
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(bs) // nolint:errcheck
+}
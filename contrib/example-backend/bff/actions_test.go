@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bff
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindfake "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned/fake"
+)
+
+func TestActions(t *testing.T) {
+	ctx := context.Background()
+
+	// "admin" may do everything, "approver" only approve and deny
+	kubeClient := kubefake.NewSimpleClientset(
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-a", Name: kuberesources.ServiceAccountName}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-a", Name: "kube-binder-token", Annotations: map[string]string{kuberesources.ServiceAccountTokenAnnotation: kuberesources.ServiceAccountName}},
+			Type:       kuberesources.ServiceAccountTokenType,
+		},
+	)
+	kubeClient.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		review.Status.Authenticated = true
+		review.Status.User.Username = review.Spec.Token
+		return true, review, nil
+	})
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		sar := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		sar.Status.Allowed = sar.Spec.User == "admin" || (sar.Spec.User == "approver" && sar.Spec.ResourceAttributes.Resource == "apiserviceexportrequests")
+		return true, sar, nil
+	})
+	bindClient := bindfake.NewSimpleClientset(
+		&kubebindv1alpha1.ClusterBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-a", Name: kuberesources.ClusterBindingName}},
+		&kubebindv1alpha1.APIServiceExport{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-a", Name: "mangodbs.mangodb.com"}},
+		&kubebindv1alpha1.APIServiceExportRequest{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-a", Name: "request"}},
+	)
+
+	router := mux.NewRouter()
+	NewActions(NewAuthorizer(kubeClient), kubeClient, bindClient, "", "").AddRoutes(router)
+	post := func(user, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+user)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("deny", func(t *testing.T) {
+		w := post("approver", "/bff/v1/requests/cluster-a/request/deny", `{"message":"not in this region"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		req, err := bindClient.KubeBindV1alpha1().APIServiceExportRequests("cluster-a").Get(ctx, "request", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, kuberesources.ApprovalDenied, req.Annotations[kuberesources.ApprovalAnnotation])
+		require.Equal(t, "not in this region", req.Annotations[kuberesources.ApprovalMessageAnnotation])
+	})
+
+	t.Run("resync", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, post("approver", "/bff/v1/consumers/cluster-a/resync", "").Code)
+		w := post("admin", "/bff/v1/consumers/cluster-a/resync", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		export, err := bindClient.KubeBindV1alpha1().APIServiceExports("cluster-a").Get(ctx, "mangodbs.mangodb.com", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotEmpty(t, export.Annotations[kubebindv1alpha1.ResyncAnnotationKey])
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, post("approver", "/bff/v1/consumers/cluster-a/revoke", "").Code)
+		require.Equal(t, http.StatusNotFound, post("admin", "/bff/v1/consumers/cluster-b/revoke", "").Code)
+		w := post("admin", "/bff/v1/consumers/cluster-a/revoke", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		secrets, err := kubeClient.CoreV1().Secrets("cluster-a").List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Empty(t, secrets.Items)
+		_, err = kubeClient.CoreV1().ServiceAccounts("cluster-a").Get(ctx, kuberesources.ServiceAccountName, metav1.GetOptions{})
+		require.Error(t, err)
+	})
+
+	t.Run("rotate without keys secret", func(t *testing.T) {
+		require.Equal(t, http.StatusConflict, post("admin", RotateSigningKeysPath, "").Code)
+	})
+}
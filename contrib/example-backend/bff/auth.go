@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bff
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+)
+
+var (
+	// ErrUnauthenticated is returned by Authorize if the request has no valid bearer token.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden is returned by Authorize if the user is not allowed to perform the action.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Authorizer delegates authentication and authorization of dashboard requests
+// to the service provider cluster. Requests carry a bearer token accepted by
+// its kube-apiserver, e.g. of a ServiceAccount, and the user needs RBAC
+// permissions on the kube-bind objects shown or changed.
+type Authorizer struct {
+	client kubeclient.Interface
+}
+
+// NewAuthorizer returns an Authorizer using TokenReviews and SubjectAccessReviews
+// of the given client.
+func NewAuthorizer(client kubeclient.Interface) *Authorizer {
+	return &Authorizer{client: client}
+}
+
+// Authorize authenticates the bearer token of the request and checks that the
+// user may perform the given action. It returns the user name.
+func (a *Authorizer) Authorize(r *http.Request, attrs authorizationv1.ResourceAttributes) (string, error) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		return "", ErrUnauthenticated
+	}
+
+	review, err := a.client.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return "", ErrUnauthenticated
+	}
+	user := review.Status.User
+
+	extra := map[string]authorizationv1.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar, err := a.client.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &attrs,
+			User:               user.Username,
+			Groups:             user.Groups,
+			UID:                user.UID,
+			Extra:              extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review access: %w", err)
+	}
+	if !sar.Status.Allowed {
+		return user.Username, ErrForbidden
+	}
+	return user.Username, nil
+}
+
+// writeAuthError writes the error of Authorize with the matching status code.
+func writeAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bff implements a backend-for-frontend endpoint of the example backend.
+// It serves the catalog, the state of the bindings and the fleet of consumer
+// clusters in one round trip, such that service providers can build dashboards
+// without assembling the data from many queries of kube-bind objects.
+package bff
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/catalog"
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
+	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+)
+
+// OverviewPath serves the Overview.
+const OverviewPath = "/bff/v1/overview"
+
+// Overview is everything a provider dashboard shows on its landing page.
+type Overview struct {
+	Time     metav1.Time       `json:"time"`
+	Provider Provider          `json:"provider"`
+	Catalog  []CatalogResource `json:"catalog"`
+	// Consumers are the consumer clusters, sorted by namespace.
+	Consumers []Consumer `json:"consumers"`
+	// PendingRequests are the APIServiceExportRequests waiting to be processed or
+	// approved, oldest first.
+	PendingRequests []Request `json:"pendingRequests"`
+}
+
+// Provider describes the backend.
+type Provider struct {
+	PrettyName      string `json:"prettyName"`
+	Version         string `json:"version,omitempty"`
+	Region          string `json:"region,omitempty"`
+	ConsumerScope   string `json:"consumerScope"`
+	RequireApproval bool   `json:"requireApproval"`
+	// CookieKeysSecret is the <namespace>/<name> of the cookie keys secret, if
+	// signing keys can be rotated.
+	CookieKeysSecret string `json:"cookieKeysSecret,omitempty"`
+}
+
+// CatalogResource is an exported resource with the number of consumers bound to it.
+type CatalogResource struct {
+	kubebindv1alpha1.APIServiceCatalogResource `json:",inline"`
+
+	Bindings int `json:"bindings"`
+}
+
+// Consumer is a consumer cluster, i.e. a ClusterBinding and its namespace.
+type Consumer struct {
+	Namespace        string `json:"namespace"`
+	Identity         string `json:"identity,omitempty"`
+	Ready            string `json:"ready"`
+	KonnectorVersion string `json:"konnectorVersion,omitempty"`
+	// Heartbeat is Healthy, Stale or Never.
+	Heartbeat         string       `json:"heartbeat"`
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// Namespaces is the number of APIServiceNamespaces, i.e. of bound consumer namespaces.
+	Namespaces int      `json:"namespaces"`
+	Exports    []Export `json:"exports"`
+}
+
+// Export is an APIServiceExport of a consumer.
+type Export struct {
+	Name     string `json:"name"`
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+	Ready    string `json:"ready"`
+}
+
+// Request is a pending APIServiceExportRequest.
+type Request struct {
+	Namespace         string      `json:"namespace"`
+	Name              string      `json:"name"`
+	Identity          string      `json:"identity,omitempty"`
+	Resources         []string    `json:"resources"`
+	Phase             string      `json:"phase,omitempty"`
+	Approval          string      `json:"approval,omitempty"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+}
+
+// Handler serves the backend-for-frontend endpoints.
+type Handler struct {
+	provider   Provider
+	authorizer *Authorizer
+	catalog    *catalog.Cache
+
+	clusterBindingLister   bindlisters.ClusterBindingLister
+	serviceExportLister    bindlisters.APIServiceExportLister
+	exportRequestLister    bindlisters.APIServiceExportRequestLister
+	serviceNamespaceLister bindlisters.APIServiceNamespaceLister
+	namespaceLister        corelisters.NamespaceLister
+}
+
+// NewHandler returns a Handler serving from the given informers.
+func NewHandler(
+	provider Provider,
+	authorizer *Authorizer,
+	catalog *catalog.Cache,
+	clusterBindingInformer bindinformers.ClusterBindingInformer,
+	serviceExportInformer bindinformers.APIServiceExportInformer,
+	exportRequestInformer bindinformers.APIServiceExportRequestInformer,
+	serviceNamespaceInformer bindinformers.APIServiceNamespaceInformer,
+	namespaceInformer coreinformers.NamespaceInformer,
+) *Handler {
+	return &Handler{
+		provider:               provider,
+		authorizer:             authorizer,
+		catalog:                catalog,
+		clusterBindingLister:   clusterBindingInformer.Lister(),
+		serviceExportLister:    serviceExportInformer.Lister(),
+		exportRequestLister:    exportRequestInformer.Lister(),
+		serviceNamespaceLister: serviceNamespaceInformer.Lister(),
+		namespaceLister:        namespaceInformer.Lister(),
+	}
+}
+
+func (h *Handler) AddRoutes(mux *mux.Router) {
+	mux.HandleFunc(OverviewPath, h.handleOverview).Methods("GET")
+}
+
+func (h *Handler) handleOverview(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	// the overview spans all consumers
+	if _, err := h.authorizer.Authorize(r, authorizationv1.ResourceAttributes{
+		Verb:     "list",
+		Group:    kubebindv1alpha1.GroupName,
+		Resource: "clusterbindings",
+	}); err != nil {
+		logger.V(2).Info("refusing overview", "err", err)
+		writeAuthError(w, err)
+		return
+	}
+
+	overview, err := h.Overview(time.Now())
+	if err != nil {
+		logger.Error(err, "failed to compute overview")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	bs, err := json.Marshal(overview)
+	if err != nil {
+		logger.Error(err, "failed to marshal overview")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(bs) // nolint:errcheck
+}
+
+// Overview computes the Overview from the informer caches.
+func (h *Handler) Overview(now time.Time) (*Overview, error) {
+	overview := &Overview{
+		Time:            metav1.NewTime(now),
+		Provider:        h.provider,
+		Catalog:         []CatalogResource{},
+		Consumers:       []Consumer{},
+		PendingRequests: []Request{},
+	}
+
+	identities := map[string]string{}
+	namespaces, err := h.namespaceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range namespaces {
+		if id, found := ns.Annotations[kuberesources.IdentityAnnotationKey]; found {
+			identities[ns.Name] = id
+		}
+	}
+
+	// exports and bound namespaces by consumer namespace
+	exports, err := h.serviceExportLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	exportsByNamespace := map[string][]Export{}
+	bindings := map[kubebindv1alpha1.GroupResource]int{}
+	for _, export := range exports {
+		exportsByNamespace[export.Namespace] = append(exportsByNamespace[export.Namespace], Export{
+			Name:     export.Name,
+			Group:    export.Spec.Group,
+			Resource: export.Spec.Names.Plural,
+			Ready:    conditionStatus(export),
+		})
+		bindings[kubebindv1alpha1.GroupResource{Group: export.Spec.Group, Resource: export.Spec.Names.Plural}]++
+	}
+	serviceNamespaces, err := h.serviceNamespaceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	serviceNamespacesByNamespace := map[string]int{}
+	for _, sn := range serviceNamespaces {
+		serviceNamespacesByNamespace[sn.Namespace]++
+	}
+
+	// catalog
+	e, err := h.catalog.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	var cat kubebindv1alpha1.APIServiceCatalog
+	if err := json.Unmarshal(e.Data, &cat); err != nil {
+		return nil, err
+	}
+	for _, r := range cat.Resources {
+		overview.Catalog = append(overview.Catalog, CatalogResource{
+			APIServiceCatalogResource: r,
+			Bindings:                  bindings[kubebindv1alpha1.GroupResource{Group: r.Group, Resource: r.Resource}],
+		})
+	}
+
+	// fleet
+	clusterBindings, err := h.clusterBindingLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, cb := range clusterBindings {
+		consumer := Consumer{
+			Namespace:        cb.Namespace,
+			Identity:         identities[cb.Namespace],
+			Ready:            conditionStatus(cb),
+			KonnectorVersion: cb.Status.KonnectorVersion,
+			Heartbeat:        heartbeat(cb, now),
+			Namespaces:       serviceNamespacesByNamespace[cb.Namespace],
+			Exports:          exportsByNamespace[cb.Namespace],
+		}
+		if !cb.Status.LastHeartbeatTime.IsZero() {
+			t := cb.Status.LastHeartbeatTime
+			consumer.LastHeartbeatTime = &t
+		}
+		if consumer.Exports == nil {
+			consumer.Exports = []Export{}
+		}
+		sort.Slice(consumer.Exports, func(i, j int) bool { return consumer.Exports[i].Name < consumer.Exports[j].Name })
+		overview.Consumers = append(overview.Consumers, consumer)
+	}
+	sort.Slice(overview.Consumers, func(i, j int) bool { return overview.Consumers[i].Namespace < overview.Consumers[j].Namespace })
+
+	// pending requests
+	requests, err := h.exportRequestLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range requests {
+		if req.Status.Phase != "" && req.Status.Phase != kubebindv1alpha1.APIServiceExportRequestPhasePending {
+			continue
+		}
+		request := Request{
+			Namespace:         req.Namespace,
+			Name:              req.Name,
+			Identity:          identities[req.Namespace],
+			Resources:         []string{},
+			Phase:             string(req.Status.Phase),
+			Approval:          req.Annotations[kuberesources.ApprovalAnnotation],
+			CreationTimestamp: req.CreationTimestamp,
+		}
+		for _, res := range req.Spec.Resources {
+			request.Resources = append(request.Resources, res.Resource+"."+res.Group)
+		}
+		overview.PendingRequests = append(overview.PendingRequests, request)
+	}
+	sort.Slice(overview.PendingRequests, func(i, j int) bool {
+		return overview.PendingRequests[i].CreationTimestamp.Before(&overview.PendingRequests[j].CreationTimestamp)
+	})
+
+	return overview, nil
+}
+
+func conditionStatus(obj conditions.Getter) string {
+	if c := conditions.Get(obj, "Ready"); c != nil {
+		return string(c.Status)
+	}
+	return "Unknown"
+}
+
+// heartbeat summarizes the heartbeat of the konnector of a ClusterBinding. A
+// heartbeat is stale if it is older than twice the promised interval.
+func heartbeat(cb *kubebindv1alpha1.ClusterBinding, now time.Time) string {
+	if cb.Status.LastHeartbeatTime.IsZero() {
+		return "Never"
+	}
+	if interval := cb.Status.HeartbeatInterval.Duration; interval > 0 && now.Sub(cb.Status.LastHeartbeatTime.Time) > 2*interval {
+		return "Stale"
+	}
+	return "Healthy"
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bff
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/catalog"
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	bindfake "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned/fake"
+	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions"
+)
+
+func TestOverview(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	crdInformers := apiextensionsinformers.NewSharedInformerFactory(apiextensionsfake.NewSimpleClientset(), 0)
+	crdInformer := crdInformers.Apiextensions().V1().CustomResourceDefinitions()
+	require.NoError(t, crdInformer.Informer().GetIndexer().Add(&apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com", Labels: map[string]string{kuberesources.ExportedCRDsLabel: "true"}},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group:    "mangodb.com",
+			Names:    apiextensionsv1.CustomResourceDefinitionNames{Plural: "mangodbs", Kind: "MangoDB"},
+			Scope:    apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}},
+		},
+	}))
+
+	bindInformers := bindinformers.NewSharedInformerFactory(bindfake.NewSimpleClientset(), 0)
+	kubeInformers := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0)
+	for _, obj := range []runtime.Object{
+		&kubebindv1alpha1.ClusterBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-b", Name: "cluster"},
+			Status: kubebindv1alpha1.ClusterBindingStatus{
+				KonnectorVersion:  "v0.5.0",
+				LastHeartbeatTime: metav1.NewTime(now.Add(-time.Hour)),
+				HeartbeatInterval: metav1.Duration{Duration: time.Minute},
+			},
+		},
+		&kubebindv1alpha1.ClusterBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-a", Name: "cluster"},
+			Status: kubebindv1alpha1.ClusterBindingStatus{
+				LastHeartbeatTime: metav1.NewTime(now.Add(-30 * time.Second)),
+				HeartbeatInterval: metav1.Duration{Duration: time.Minute},
+				Conditions:        conditionsapi.Conditions{{Type: "Ready", Status: corev1.ConditionTrue}},
+			},
+		},
+	} {
+		require.NoError(t, bindInformers.KubeBind().V1alpha1().ClusterBindings().Informer().GetIndexer().Add(obj))
+	}
+	require.NoError(t, bindInformers.KubeBind().V1alpha1().APIServiceExports().Informer().GetIndexer().Add(&kubebindv1alpha1.APIServiceExport{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-a", Name: "mangodbs.mangodb.com"},
+		Spec: kubebindv1alpha1.APIServiceExportSpec{APIServiceExportCRDSpec: kubebindv1alpha1.APIServiceExportCRDSpec{
+			Group: "mangodb.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "mangodbs"},
+		}},
+	}))
+	require.NoError(t, bindInformers.KubeBind().V1alpha1().APIServiceNamespaces().Informer().GetIndexer().Add(&kubebindv1alpha1.APIServiceNamespace{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-a", Name: "default"},
+	}))
+	for _, obj := range []runtime.Object{
+		&kubebindv1alpha1.APIServiceExportRequest{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-b", Name: "pending", CreationTimestamp: metav1.NewTime(now)},
+			Spec: kubebindv1alpha1.APIServiceExportRequestSpec{Resources: []kubebindv1alpha1.APIServiceExportRequestResource{
+				{GroupResource: kubebindv1alpha1.GroupResource{Group: "mangodb.com", Resource: "mangodbs"}},
+			}},
+			Status: kubebindv1alpha1.APIServiceExportRequestStatus{Phase: kubebindv1alpha1.APIServiceExportRequestPhasePending},
+		},
+		&kubebindv1alpha1.APIServiceExportRequest{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-a", Name: "done"},
+			Status:     kubebindv1alpha1.APIServiceExportRequestStatus{Phase: kubebindv1alpha1.APIServiceExportRequestPhaseSucceeded},
+		},
+	} {
+		require.NoError(t, bindInformers.KubeBind().V1alpha1().APIServiceExportRequests().Informer().GetIndexer().Add(obj))
+	}
+	require.NoError(t, kubeInformers.Core().V1().Namespaces().Informer().GetIndexer().Add(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-a", Annotations: map[string]string{kuberesources.IdentityAnnotationKey: "alice"}},
+	}))
+
+	h := NewHandler(
+		Provider{PrettyName: "MangoDB", ConsumerScope: "Namespaced"},
+		nil,
+		catalog.NewCache(kubebindv1alpha1.NamespacedScope, crdInformer),
+		bindInformers.KubeBind().V1alpha1().ClusterBindings(),
+		bindInformers.KubeBind().V1alpha1().APIServiceExports(),
+		bindInformers.KubeBind().V1alpha1().APIServiceExportRequests(),
+		bindInformers.KubeBind().V1alpha1().APIServiceNamespaces(),
+		kubeInformers.Core().V1().Namespaces(),
+	)
+	overview, err := h.Overview(now)
+	require.NoError(t, err)
+
+	require.Equal(t, "MangoDB", overview.Provider.PrettyName)
+	require.Len(t, overview.Catalog, 1)
+	require.Equal(t, "mangodbs", overview.Catalog[0].Resource)
+	require.Equal(t, 1, overview.Catalog[0].Bindings)
+
+	require.Len(t, overview.Consumers, 2)
+	a, b := overview.Consumers[0], overview.Consumers[1]
+	require.Equal(t, "cluster-a", a.Namespace)
+	require.Equal(t, "alice", a.Identity)
+	require.Equal(t, "True", a.Ready)
+	require.Equal(t, "Healthy", a.Heartbeat)
+	require.Equal(t, 1, a.Namespaces)
+	require.Equal(t, []Export{{Name: "mangodbs.mangodb.com", Group: "mangodb.com", Resource: "mangodbs", Ready: "Unknown"}}, a.Exports)
+	require.Equal(t, "cluster-b", b.Namespace)
+	require.Equal(t, "Unknown", b.Ready)
+	require.Equal(t, "Stale", b.Heartbeat)
+	require.Equal(t, "v0.5.0", b.KonnectorVersion)
+	require.Empty(t, b.Exports)
+
+	require.Len(t, overview.PendingRequests, 1)
+	require.Equal(t, "pending", overview.PendingRequests[0].Name)
+	require.Equal(t, []string{"mangodbs.mangodb.com"}, overview.PendingRequests[0].Resources)
+}
+
+func TestAuthorize(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		switch review.Spec.Token {
+		case "admin", "viewer":
+			review.Status.Authenticated = true
+			review.Status.User.Username = review.Spec.Token
+		}
+		return true, review, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		sar := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		sar.Status.Allowed = sar.Spec.User == "admin" && sar.Spec.ResourceAttributes.Resource == "clusterbindings"
+		return true, sar, nil
+	})
+	a := NewAuthorizer(client)
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantUser      string
+		wantErr       error
+	}{
+		{name: "no token", wantErr: ErrUnauthenticated},
+		{name: "not bearer", authorization: "Basic admin", wantErr: ErrUnauthenticated},
+		{name: "invalid token", authorization: "Bearer unknown", wantErr: ErrUnauthenticated},
+		{name: "not allowed", authorization: "Bearer viewer", wantUser: "viewer", wantErr: ErrForbidden},
+		{name: "allowed", authorization: "Bearer admin", wantUser: "admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, OverviewPath, nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			user, err := a.Authorize(r, authorizationv1.ResourceAttributes{Verb: "list", Group: kubebindv1alpha1.GroupName, Resource: "clusterbindings"})
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.wantUser, user)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bff
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// DashboardPath serves the embedded provider dashboard.
+const DashboardPath = "/dashboard/"
+
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// AddDashboardRoutes serves the embedded provider dashboard. It is a static
+// single page using the overview and the actions, and needs both routes on the
+// same mux. Users log in with a bearer token of the service provider cluster.
+func AddDashboardRoutes(mux *mux.Router) {
+	files, err := fs.Sub(dashboardFiles, "dashboard")
+	if err != nil {
+		panic(err) // embedded at build time
+	}
+	fileServer := http.StripPrefix(DashboardPath, http.FileServer(http.FS(files)))
+	mux.Handle(DashboardPath[:len(DashboardPath)-1], http.RedirectHandler(DashboardPath, http.StatusMovedPermanently)).Methods("GET")
+	mux.PathPrefix(DashboardPath).Methods("GET").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the page only talks to the backend itself, and must not be framed
+		// to protect the action buttons from clickjacking.
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		fileServer.ServeHTTP(w, r)
+	})
+}
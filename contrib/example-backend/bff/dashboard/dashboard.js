@@ -0,0 +1,175 @@
+// The provider dashboard renders the backend-for-frontend overview and calls
+// its actions with the bearer token of the logged in user.
+"use strict";
+
+const tokenKey = "kube-bind-dashboard-token";
+const refreshInterval = 15000;
+
+let timer;
+
+function el(tag, attrs, ...children) {
+  const e = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    if (k === "onclick") {
+      e.addEventListener("click", v);
+    } else {
+      e.setAttribute(k, v);
+    }
+  }
+  for (const c of children) {
+    e.append(c === undefined || c === null ? "" : c);
+  }
+  return e;
+}
+
+function age(t) {
+  if (!t) {
+    return "";
+  }
+  const s = Math.max(0, Math.round((Date.now() - Date.parse(t)) / 1000));
+  if (s < 120) {
+    return s + "s";
+  } else if (s < 7200) {
+    return Math.round(s / 60) + "m";
+  } else if (s < 172800) {
+    return Math.round(s / 3600) + "h";
+  }
+  return Math.round(s / 86400) + "d";
+}
+
+async function call(method, path, body) {
+  const resp = await fetch(path, {
+    method: method,
+    headers: {
+      "Authorization": "Bearer " + sessionStorage.getItem(tokenKey),
+      "Content-Type": "application/json",
+    },
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (resp.status === 401) {
+    logout();
+    throw new Error("the token is invalid or expired");
+  }
+  if (!resp.ok) {
+    throw new Error((await resp.text()).trim() || resp.statusText);
+  }
+  return resp.json();
+}
+
+function show(text, isError) {
+  const m = document.getElementById("message");
+  m.textContent = text;
+  m.className = isError ? "error" : "";
+}
+
+async function action(path, body, confirmation) {
+  if (confirmation && !window.confirm(confirmation)) {
+    return;
+  }
+  try {
+    const result = await call("POST", path, body);
+    show(result.message, false);
+  } catch (e) {
+    show(e.message, true);
+  }
+  refresh();
+}
+
+function renderRequests(requests) {
+  const rows = requests.map((r) => {
+    const path = "/bff/v1/requests/" + encodeURIComponent(r.namespace) + "/" + encodeURIComponent(r.name);
+    return el("tr", {},
+      el("td", {}, r.namespace + "/" + r.name),
+      el("td", {}, r.identity),
+      el("td", {}, r.resources.join(", ")),
+      el("td", {}, r.approval || "pending"),
+      el("td", {}, age(r.creationTimestamp)),
+      el("td", {},
+        el("button", {onclick: () => action(path + "/approve", {})}, "Approve"), " ",
+        el("button", {onclick: () => {
+          const message = window.prompt("Reason shown to the consumer (optional)");
+          if (message !== null) {
+            action(path + "/deny", {message: message});
+          }
+        }}, "Deny")));
+  });
+  document.getElementById("requests").replaceChildren(...(rows.length ? rows : [el("tr", {}, el("td", {colspan: 6}, "No pending requests"))]));
+}
+
+function renderConsumers(consumers) {
+  const rows = consumers.map((c) => {
+    const path = "/bff/v1/consumers/" + encodeURIComponent(c.namespace);
+    const exports = el("ul", {}, ...c.exports.map((e) => el("li", {class: e.ready}, e.resource + "." + e.group)));
+    return el("tr", {},
+      el("td", {}, c.namespace),
+      el("td", {}, c.identity),
+      el("td", {class: c.ready}, c.ready),
+      el("td", {class: c.heartbeat, title: c.lastHeartbeatTime || ""}, c.heartbeat + (c.lastHeartbeatTime ? " (" + age(c.lastHeartbeatTime) + " ago)" : "")),
+      el("td", {}, c.konnectorVersion),
+      el("td", {}, String(c.namespaces)),
+      el("td", {}, exports),
+      el("td", {},
+        el("button", {onclick: () => action(path + "/resync")}, "Resync"), " ",
+        el("button", {onclick: () => action(path + "/revoke", undefined,
+          "Revoke all credentials of " + c.namespace + "? The consumer has to bind again.")}, "Revoke")));
+  });
+  document.getElementById("consumers").replaceChildren(...(rows.length ? rows : [el("tr", {}, el("td", {colspan: 8}, "No connected clusters"))]));
+}
+
+function renderCatalog(catalog) {
+  const rows = catalog.map((r) => el("tr", {},
+    el("td", {}, r.resource + "." + r.group),
+    el("td", {}, r.scope),
+    el("td", {}, String(r.bindings))));
+  document.getElementById("catalog").replaceChildren(...(rows.length ? rows : [el("tr", {}, el("td", {colspan: 3}, "No exported resources"))]));
+}
+
+async function refresh() {
+  clearTimeout(timer);
+  if (!sessionStorage.getItem(tokenKey)) {
+    return;
+  }
+  try {
+    const overview = await call("GET", "/bff/v1/overview");
+    document.getElementById("title").textContent = overview.provider.prettyName;
+    document.getElementById("provider").textContent = [overview.provider.version, overview.provider.region].filter((s) => s).join(" · ");
+    document.getElementById("keys").hidden = !overview.provider.cookieKeysSecret;
+    renderRequests(overview.pendingRequests);
+    renderConsumers(overview.consumers);
+    renderCatalog(overview.catalog);
+  } catch (e) {
+    show(e.message, true);
+  }
+  timer = setTimeout(refresh, refreshInterval);
+}
+
+function login(token) {
+  sessionStorage.setItem(tokenKey, token);
+  document.getElementById("login").hidden = true;
+  document.getElementById("dashboard").hidden = false;
+  document.getElementById("logout").hidden = false;
+  refresh();
+}
+
+function logout() {
+  clearTimeout(timer);
+  sessionStorage.removeItem(tokenKey);
+  document.getElementById("login").hidden = false;
+  document.getElementById("dashboard").hidden = true;
+  document.getElementById("logout").hidden = true;
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+  document.getElementById("login-form").addEventListener("submit", (e) => {
+    e.preventDefault();
+    const input = document.getElementById("token");
+    login(input.value.trim());
+    input.value = "";
+  });
+  document.getElementById("logout").addEventListener("click", logout);
+  document.getElementById("rotate").addEventListener("click", () => action("/bff/v1/rotate-signing-keys", undefined,
+    "Add a new cookie signing key version?"));
+  if (sessionStorage.getItem(tokenKey)) {
+    login(sessionStorage.getItem(tokenKey));
+  }
+});
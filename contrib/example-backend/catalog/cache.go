@@ -0,0 +1,214 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+// ErrNotFound is returned by Schema when the requested resource is not exported.
+var ErrNotFound = errors.New("resource not exported")
+
+// Cache holds the serialized export catalog and per-resource schemas. Entries are
+// computed on first request and dropped whenever an exported CRD changes, so
+// polling consumers are served from memory. Every entry carries an ETag so
+// that unchanged entries can be answered with 304 Not Modified.
+type Cache struct {
+	scope     kubebindv1alpha1.Scope
+	crdLister apiextensionslisters.CustomResourceDefinitionLister
+
+	lock    sync.Mutex
+	catalog *Entry
+	schemas map[string]*Entry
+}
+
+// Entry is a serialized catalog or schema with its ETag.
+type Entry struct {
+	Data []byte
+	ETag string
+}
+
+func newEntry(data []byte) *Entry {
+	sum := sha256.Sum256(data)
+	return &Entry{
+		Data: data,
+		ETag: `"` + hex.EncodeToString(sum[:16]) + `"`,
+	}
+}
+
+// Matches returns true if the given If-None-Match header value matches the
+// ETag of the entry.
+func (e *Entry) Matches(ifNoneMatch string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == e.ETag {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCache returns a catalog cache which is invalidated by events of the given
+// CRD informer.
+func NewCache(scope kubebindv1alpha1.Scope, crdInformer apiextensionsinformers.CustomResourceDefinitionInformer) *Cache {
+	c := newCache(scope, crdInformer.Lister())
+
+	crdInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if isExported(obj) {
+				c.invalidate()
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if isExported(oldObj) || isExported(newObj) {
+				c.invalidate()
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if isExported(obj) {
+				c.invalidate()
+			}
+		},
+	})
+
+	return c
+}
+
+func newCache(scope kubebindv1alpha1.Scope, crdLister apiextensionslisters.CustomResourceDefinitionLister) *Cache {
+	return &Cache{
+		scope:     scope,
+		crdLister: crdLister,
+		schemas:   map[string]*Entry{},
+	}
+}
+
+func isExported(obj interface{}) bool {
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	return ok && crd.Labels[resources.ExportedCRDsLabel] == "true"
+}
+
+func (c *Cache) invalidate() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.catalog = nil
+	c.schemas = map[string]*Entry{}
+}
+
+func (c *Cache) inScope(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	return c.scope == kubebindv1alpha1.ClusterScope || crd.Spec.Scope == apiextensionsv1.NamespaceScoped
+}
+
+// Catalog returns the JSON encoded APIServiceCatalog of all exported resources.
+func (c *Cache) Catalog() (*Entry, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.catalog != nil {
+		return c.catalog, nil
+	}
+
+	crds, err := c.crdLister.List(labels.Set{resources.ExportedCRDsLabel: "true"}.AsSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := kubebindv1alpha1.APIServiceCatalog{}
+	catalog.APIVersion = kubebindv1alpha1.SchemeGroupVersion.String()
+	catalog.Kind = "APIServiceCatalog"
+	for _, crd := range crds {
+		if !c.inScope(crd) {
+			continue
+		}
+		resource := kubebindv1alpha1.APIServiceCatalogResource{
+			Group:    crd.Spec.Group,
+			Resource: crd.Spec.Names.Plural,
+			Kind:     crd.Spec.Names.Kind,
+			Scope:    crd.Spec.Scope,
+		}
+		for _, v := range crd.Spec.Versions {
+			if v.Served {
+				resource.Versions = append(resource.Versions, v.Name)
+			}
+		}
+		catalog.Resources = append(catalog.Resources, resource)
+	}
+	sort.Slice(catalog.Resources, func(i, j int) bool {
+		if catalog.Resources[i].Group != catalog.Resources[j].Group {
+			return catalog.Resources[i].Group < catalog.Resources[j].Group
+		}
+		return catalog.Resources[i].Resource < catalog.Resources[j].Resource
+	})
+
+	bs, err := json.Marshal(&catalog)
+	if err != nil {
+		return nil, err
+	}
+	c.catalog = newEntry(bs)
+	return c.catalog, nil
+}
+
+// Schema returns the JSON encoded APIServiceExportCRDSpec of the given exported
+// resource, or ErrNotFound.
+func (c *Cache) Schema(group, resource string) (*Entry, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	name := resource + "." + group
+	if e, found := c.schemas[name]; found {
+		return e, nil
+	}
+
+	crd, err := c.crdLister.Get(name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	} else if apierrors.IsNotFound(err) || !isExported(crd) || !c.inScope(crd) {
+		return nil, ErrNotFound
+	}
+
+	spec, err := kubebindhelpers.CRDToServiceExport(crd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert CRD %s: %w", name, err)
+	}
+	bs, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	c.schemas[name] = newEntry(bs)
+	return c.schemas[name], nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func newCRD(group, plural string, scope apiextensionsv1.ResourceScope, exported bool) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: plural + "." + group},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: plural, Kind: "Kind"},
+			Scope: scope,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v1beta1", Served: false},
+			},
+		},
+	}
+	if exported {
+		crd.Labels = map[string]string{resources.ExportedCRDsLabel: "true"}
+	}
+	return crd
+}
+
+func TestCache(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(newCRD("example.com", "foos", apiextensionsv1.NamespaceScoped, true)))
+	require.NoError(t, indexer.Add(newCRD("example.com", "bars", apiextensionsv1.ClusterScoped, true)))
+	require.NoError(t, indexer.Add(newCRD("example.com", "bazs", apiextensionsv1.NamespaceScoped, false)))
+
+	c := newCache(kubebindv1alpha1.NamespacedScope, apiextensionslisters.NewCustomResourceDefinitionLister(indexer))
+
+	e, err := c.Catalog()
+	require.NoError(t, err)
+	var catalog kubebindv1alpha1.APIServiceCatalog
+	require.NoError(t, json.Unmarshal(e.Data, &catalog))
+	require.Equal(t, []kubebindv1alpha1.APIServiceCatalogResource{
+		{Group: "example.com", Resource: "foos", Kind: "Kind", Scope: apiextensionsv1.NamespaceScoped, Versions: []string{"v1"}},
+	}, catalog.Resources)
+
+	_, err = c.Schema("example.com", "foos")
+	require.NoError(t, err)
+	_, err = c.Schema("example.com", "bars")
+	require.ErrorIs(t, err, ErrNotFound, "cluster scoped CRD must not be served to namespaced consumers")
+	_, err = c.Schema("example.com", "bazs")
+	require.ErrorIs(t, err, ErrNotFound, "non-exported CRD must not be served")
+
+	// cached until invalidated
+	require.NoError(t, indexer.Add(newCRD("example.com", "quxs", apiextensionsv1.NamespaceScoped, true)))
+	cached, err := c.Catalog()
+	require.NoError(t, err)
+	require.Equal(t, e, cached)
+
+	c.invalidate()
+	updated, err := c.Catalog()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(updated.Data, &catalog))
+	require.Len(t, catalog.Resources, 2)
+	require.NotEqual(t, e.ETag, updated.ETag)
+}
+
+func TestEntryMatches(t *testing.T) {
+	e := newEntry([]byte("foo"))
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{name: "empty", ifNoneMatch: "", want: false},
+		{name: "equal", ifNoneMatch: e.ETag, want: true},
+		{name: "weak", ifNoneMatch: "W/" + e.ETag, want: true},
+		{name: "list", ifNoneMatch: `"abc", ` + e.ETag, want: true},
+		{name: "wildcard", ifNoneMatch: "*", want: true},
+		{name: "different", ifNoneMatch: `"abc"`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, e.Matches(tt.ifNoneMatch))
+		})
+	}
+}
@@ -21,6 +21,8 @@ import (
 
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	kubeinformers "k8s.io/client-go/informers"
 	kubernetesclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -42,6 +44,9 @@ type Config struct {
 	KubeInformers          kubeinformers.SharedInformerFactory
 	BindInformers          bindinformers.SharedInformerFactory
 	ApiextensionsInformers apiextensionsinformers.SharedInformerFactory
+
+	// CookieKeysInformers watches the cookie keys secret, if configured.
+	CookieKeysInformers kubeinformers.SharedInformerFactory
 }
 
 func NewConfig(options *options.CompletedOptions) (*Config, error) {
@@ -74,6 +79,18 @@ func NewConfig(options *options.CompletedOptions) (*Config, error) {
 	config.KubeInformers = kubeinformers.NewSharedInformerFactory(config.KubeClient, time.Minute*30)
 	config.BindInformers = bindinformers.NewSharedInformerFactory(config.BindClient, time.Minute*30)
 	config.ApiextensionsInformers = apiextensionsinformers.NewSharedInformerFactory(config.ApiextensionsClient, time.Minute*30)
+	if options.Cookie.KeysSecret != "" {
+		ns, name, err := options.Cookie.KeysSecretNamespaceName()
+		if err != nil {
+			return nil, err
+		}
+		config.CookieKeysInformers = kubeinformers.NewSharedInformerFactoryWithOptions(config.KubeClient, time.Minute*30,
+			kubeinformers.WithNamespace(ns),
+			kubeinformers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+				opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+			}),
+		)
+	}
 
 	return config, nil
 }
@@ -39,6 +39,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/kube-bind/kube-bind/contrib/example-backend/fairqueue"
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
@@ -60,8 +61,10 @@ func NewController(
 	clusterRoleBindingInformer rbacinformers.ClusterRoleBindingInformer,
 	roleBindingInformer rbacinformers.RoleBindingInformer,
 	namespaceInformer kubeinformers.NamespaceInformer,
+	konnectorVersions *kubebindv1alpha1.KonnectorVersionRequirements,
 ) (*Controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	// serve consumers fairly, independent of how many objects they have
+	queue := fairqueue.NewNamedRateLimitingQueue(fairqueue.DefaultControllerRateLimiter(fairqueue.NamespaceTenant), controllerName, fairqueue.NamespaceTenant)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -96,6 +99,8 @@ func NewController(
 		namespaceIndexer: namespaceInformer.Informer().GetIndexer(),
 
 		reconciler: reconciler{
+			konnectorVersions: konnectorVersions,
+
 			scope: scope,
 			listServiceExports: func(ns string) ([]*kubebindv1alpha1.APIServiceExport, error) {
 				return serviceExportInformer.Lister().APIServiceExports(ns).List(labels.Everything())
@@ -124,6 +129,9 @@ func NewController(
 			getNamespace: func(name string) (*v1.Namespace, error) {
 				return namespaceInformer.Lister().Get(name)
 			},
+			deleteNamespace: func(ctx context.Context, name string) error {
+				return kubeClient.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{})
+			},
 			createRoleBinding: func(ctx context.Context, ns string, binding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
 				return kubeClient.RbacV1().RoleBindings(ns).Create(ctx, binding, metav1.CreateOptions{})
 			},
@@ -141,6 +149,14 @@ func NewController(
 			},
 		),
 	}
+	c.reconciler.requeueAfter = func(clusterBinding *kubebindv1alpha1.ClusterBinding, after time.Duration) {
+		key, err := cache.MetaNamespaceKeyFunc(clusterBinding)
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		c.queue.AddAfter(key, after)
+	}
 
 	clusterBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 
 	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
@@ -38,6 +39,9 @@ import (
 type reconciler struct {
 	scope kubebindv1alpha1.Scope
 
+	// konnectorVersions are published in the spec of ClusterBindings, nil if unset.
+	konnectorVersions *kubebindv1alpha1.KonnectorVersionRequirements
+
 	listServiceExports func(ns string) ([]*kubebindv1alpha1.APIServiceExport, error)
 
 	getClusterRole    func(name string) (*rbacv1.ClusterRole, error)
@@ -53,7 +57,10 @@ type reconciler struct {
 	createRoleBinding func(ctx context.Context, ns string, binding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
 	updateRoleBinding func(ctx context.Context, ns string, binding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
 
-	getNamespace func(name string) (*corev1.Namespace, error)
+	getNamespace    func(name string) (*corev1.Namespace, error)
+	deleteNamespace func(ctx context.Context, name string) error
+
+	requeueAfter func(clusterBinding *kubebindv1alpha1.ClusterBinding, after time.Duration)
 }
 
 func (r *reconciler) reconcile(ctx context.Context, clusterBinding *kubebindv1alpha1.ClusterBinding) error {
@@ -62,6 +69,11 @@ func (r *reconciler) reconcile(ctx context.Context, clusterBinding *kubebindv1al
 	if err := r.ensureClusterBindingConditions(ctx, clusterBinding); err != nil {
 		errs = append(errs, err)
 	}
+	r.ensureCredentialsConditions(clusterBinding)
+	r.ensureKonnectorVersions(clusterBinding)
+	if err := r.ensureTrialExpiration(ctx, clusterBinding); err != nil {
+		errs = append(errs, err)
+	}
 	if err := r.ensureRBACRoleBinding(ctx, clusterBinding); err != nil {
 		errs = append(errs, err)
 	}
@@ -117,6 +129,70 @@ func (r *reconciler) ensureClusterBindingConditions(ctx context.Context, cluster
 	return nil
 }
 
+func (r *reconciler) ensureCredentialsConditions(clusterBinding *kubebindv1alpha1.ClusterBinding) {
+	expiration := clusterBinding.Status.CredentialsExpirationTime
+	if expiration == nil {
+		conditions.MarkTrue(clusterBinding, kubebindv1alpha1.ClusterBindingConditionCredentialsValid)
+		return
+	}
+
+	if left := time.Until(expiration.Time); left <= 0 {
+		conditions.MarkFalse(clusterBinding,
+			kubebindv1alpha1.ClusterBindingConditionCredentialsValid,
+			"CredentialsExpired",
+			conditionsapi.ConditionSeverityError,
+			"Credentials expired at %s. Rotate them with \"kubectl bind rotate-credentials\"",
+			expiration.Time,
+		)
+	} else {
+		conditions.MarkTrue(clusterBinding, kubebindv1alpha1.ClusterBindingConditionCredentialsValid)
+		r.requeueAfter(clusterBinding, left)
+	}
+}
+
+// ensureKonnectorVersions publishes the supported konnector versions, such that
+// konnectors can report or fix their version skew.
+func (r *reconciler) ensureKonnectorVersions(clusterBinding *kubebindv1alpha1.ClusterBinding) {
+	if r.konnectorVersions == nil {
+		clusterBinding.Spec.KonnectorVersions = nil
+		return
+	}
+	versions := *r.konnectorVersions
+	clusterBinding.Spec.KonnectorVersions = &versions
+}
+
+// ensureTrialExpiration deletes the namespace of an expired trial binding, and with it
+// the ClusterBinding and all bound objects.
+func (r *reconciler) ensureTrialExpiration(ctx context.Context, clusterBinding *kubebindv1alpha1.ClusterBinding) error {
+	expiration := clusterBinding.Spec.ExpirationTime
+	if expiration == nil {
+		conditions.Delete(clusterBinding, kubebindv1alpha1.ClusterBindingConditionTrialActive)
+		return nil
+	}
+
+	if left := time.Until(expiration.Time); left > 0 {
+		conditions.MarkTrue(clusterBinding, kubebindv1alpha1.ClusterBindingConditionTrialActive)
+		r.requeueAfter(clusterBinding, left)
+		return nil
+	}
+
+	conditions.MarkFalse(clusterBinding,
+		kubebindv1alpha1.ClusterBindingConditionTrialActive,
+		"TrialExpired",
+		conditionsapi.ConditionSeverityError,
+		"Trial binding expired at %s",
+		expiration.Time,
+	)
+
+	logger := klog.FromContext(ctx)
+	logger.Info("Deleting namespace of expired trial binding", "namespace", clusterBinding.Namespace, "expirationTime", expiration.Time)
+	if err := r.deleteNamespace(ctx, clusterBinding.Namespace); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Namespace %s: %w", clusterBinding.Namespace, err)
+	}
+
+	return nil
+}
+
 func (r *reconciler) ensureRBACClusterRole(ctx context.Context, clusterBinding *kubebindv1alpha1.ClusterBinding) error {
 	name := "kube-binder-" + clusterBinding.Namespace
 	role, err := r.getClusterRole(name)
@@ -154,7 +230,53 @@ func (r *reconciler) ensureRBACClusterRole(ctx context.Context, clusterBinding *
 			Verbs:     []string{"get", "list", "watch", "update", "patch", "delete", "create"},
 		})
 	}
+	if err := r.applyClusterRole(ctx, role, expected); err != nil {
+		return err
+	}
+
+	// Events are granted through a separate ClusterRole that is only bound by
+	// RoleBindings in the service namespaces of the consumer, never cluster-wide,
+	// such that consumers cannot access Events of other consumers.
+	eventsName := kuberesources.EventsClusterRoleName(clusterBinding.Namespace)
+	eventsRole, err := r.getClusterRole(eventsName)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get ClusterRole %s: %w", eventsName, err)
+	}
+	expectedEvents := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            eventsName,
+			OwnerReferences: expected.OwnerReferences,
+		},
+		Rules: eventsRules(exports),
+	}
+	return r.applyClusterRole(ctx, eventsRole, expectedEvents)
+}
+
+// eventsRules returns the rules for Events needed by the given exports.
+func eventsRules(exports []*kubebindv1alpha1.APIServiceExport) []rbacv1.PolicyRule {
+	var eventsRead, eventsWrite bool
+	for _, export := range exports {
+		eventsRead = eventsRead || export.Spec.EventsAccess || export.Spec.EventRelay != nil
+		eventsWrite = eventsWrite || (export.Spec.EventRelay != nil && export.Spec.EventRelay.Upsync)
+	}
+	switch {
+	case eventsWrite:
+		return []rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"get", "list", "watch", "create", "update"},
+		}}
+	case eventsRead:
+		return []rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"get", "list", "watch"},
+		}}
+	}
+	return nil
+}
 
+func (r *reconciler) applyClusterRole(ctx context.Context, role, expected *rbacv1.ClusterRole) error {
 	if role == nil {
 		if _, err := r.createClusterRole(ctx, expected); err != nil {
 			return fmt.Errorf("failed to create ClusterRole %s: %w", expected.Name, err)
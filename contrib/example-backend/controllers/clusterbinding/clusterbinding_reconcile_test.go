@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterbinding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+)
+
+func TestEnsureRBACClusterRole(t *testing.T) {
+	newExport := func(eventsAccess bool, relay *kubebindv1alpha1.EventRelay) *kubebindv1alpha1.APIServiceExport {
+		export := &kubebindv1alpha1.APIServiceExport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-abc", Name: "foos.example.com"},
+		}
+		export.Spec.Group = "example.com"
+		export.Spec.Names.Plural = "foos"
+		export.Spec.EventsAccess = eventsAccess
+		export.Spec.EventRelay = relay
+		return export
+	}
+
+	tests := []struct {
+		name       string
+		export     *kubebindv1alpha1.APIServiceExport
+		wantEvents []string
+	}{
+		{
+			name:   "no events",
+			export: newExport(false, nil),
+		},
+		{
+			name:       "events access",
+			export:     newExport(true, nil),
+			wantEvents: []string{"get", "list", "watch"},
+		},
+		{
+			name:       "event relay upsync",
+			export:     newExport(false, &kubebindv1alpha1.EventRelay{Upsync: true}),
+			wantEvents: []string{"get", "list", "watch", "create", "update"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := map[string]*rbacv1.ClusterRole{}
+			r := &reconciler{
+				scope: kubebindv1alpha1.ClusterScope,
+				listServiceExports: func(ns string) ([]*kubebindv1alpha1.APIServiceExport, error) {
+					return []*kubebindv1alpha1.APIServiceExport{tt.export}, nil
+				},
+				getClusterRole: func(name string) (*rbacv1.ClusterRole, error) {
+					return nil, errors.NewNotFound(schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"}, name)
+				},
+				createClusterRole: func(ctx context.Context, role *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error) {
+					created[role.Name] = role
+					return role, nil
+				},
+				getNamespace: func(name string) (*corev1.Namespace, error) {
+					return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, UID: "uid"}}, nil
+				},
+			}
+			clusterBinding := &kubebindv1alpha1.ClusterBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-abc", Name: "cluster"},
+			}
+
+			require.NoError(t, r.ensureRBACClusterRole(context.Background(), clusterBinding))
+
+			// the ClusterRole bound cluster-wide under ClusterScope must never grant Events.
+			role, found := created["kube-binder-cluster-abc"]
+			require.True(t, found)
+			for _, rule := range role.Rules {
+				require.NotContains(t, rule.Resources, "events")
+			}
+
+			events, found := created[kuberesources.EventsClusterRoleName("cluster-abc")]
+			require.True(t, found)
+			if tt.wantEvents == nil {
+				require.Empty(t, events.Rules)
+				return
+			}
+			require.Equal(t, []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: tt.wantEvents}}, events.Rules)
+		})
+	}
+}
+
+func TestEnsureTrialExpiration(t *testing.T) {
+	tests := []struct {
+		name            string
+		expiration      time.Duration
+		noTrial         bool
+		deleteErr       error
+		wantCondition   corev1.ConditionStatus
+		wantDeleted     bool
+		wantRequeue     bool
+		wantErr         bool
+		wantNoCondition bool
+	}{
+		{
+			name:            "no trial",
+			noTrial:         true,
+			wantNoCondition: true,
+		},
+		{
+			name:          "not expired",
+			expiration:    time.Hour,
+			wantCondition: corev1.ConditionTrue,
+			wantRequeue:   true,
+		},
+		{
+			name:          "expired",
+			expiration:    -time.Minute,
+			wantCondition: corev1.ConditionFalse,
+			wantDeleted:   true,
+		},
+		{
+			name:          "expired with namespace already deleted",
+			expiration:    -time.Minute,
+			deleteErr:     errors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "cluster-abc"),
+			wantCondition: corev1.ConditionFalse,
+			wantDeleted:   true,
+		},
+		{
+			name:          "expired with failing deletion",
+			expiration:    -time.Minute,
+			deleteErr:     errors.NewServiceUnavailable("etcd down"),
+			wantCondition: corev1.ConditionFalse,
+			wantDeleted:   true,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted bool
+			var requeued time.Duration
+			r := &reconciler{
+				deleteNamespace: func(ctx context.Context, name string) error {
+					require.Equal(t, "cluster-abc", name)
+					deleted = true
+					return tt.deleteErr
+				},
+				requeueAfter: func(clusterBinding *kubebindv1alpha1.ClusterBinding, after time.Duration) {
+					requeued = after
+				},
+			}
+			clusterBinding := &kubebindv1alpha1.ClusterBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-abc", Name: "cluster"},
+			}
+			if !tt.noTrial {
+				clusterBinding.Spec.ExpirationTime = &metav1.Time{Time: time.Now().Add(tt.expiration)}
+			}
+			conditions.MarkTrue(clusterBinding, kubebindv1alpha1.ClusterBindingConditionTrialActive)
+
+			err := r.ensureTrialExpiration(context.Background(), clusterBinding)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantDeleted, deleted)
+			if tt.wantRequeue {
+				require.InDelta(t, time.Hour, requeued, float64(time.Minute))
+			} else {
+				require.Zero(t, requeued)
+			}
+
+			cond := conditions.Get(clusterBinding, kubebindv1alpha1.ClusterBindingConditionTrialActive)
+			if tt.wantNoCondition {
+				require.Nil(t, cond)
+				return
+			}
+			require.NotNil(t, cond)
+			require.Equal(t, tt.wantCondition, cond.Status)
+			if tt.wantCondition == corev1.ConditionFalse {
+				require.Equal(t, "TrialExpired", cond.Reason)
+				require.Equal(t, conditionsapi.ConditionSeverityError, cond.Severity)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespacereaper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	kubernetesclient "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
+	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+)
+
+const (
+	controllerName = "kube-bind-example-backend-namespacereaper"
+)
+
+var (
+	retainedNamespaces = metrics.NewGauge(&metrics.GaugeOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "backend",
+		Name:           "retained_namespaces",
+		Help:           "Number of service provider namespaces retained after their consumer namespace was deleted.",
+		StabilityLevel: metrics.ALPHA,
+	})
+	reapedNamespaces = metrics.NewCounter(&metrics.CounterOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "backend",
+		Name:           "reaped_namespaces_total",
+		Help:           "Number of retained service provider namespaces deleted after their retention period.",
+		StabilityLevel: metrics.ALPHA,
+	})
+)
+
+func init() {
+	legacyregistry.MustRegister(retainedNamespaces, reapedNamespaces)
+}
+
+// NewController returns a new controller deleting retained namespaces after their
+// retention period.
+func NewController(
+	config *rest.Config,
+	serviceNamespaceInformer bindinformers.APIServiceNamespaceInformer,
+	namespaceInformer coreinformers.NamespaceInformer,
+) (*Controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	logger := klog.Background().WithValues("Controller", controllerName)
+
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, controllerName)
+
+	kubeClient, err := kubernetesclient.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		queue: queue,
+
+		kubeClient: kubeClient,
+
+		serviceNamespaceLister: serviceNamespaceInformer.Lister(),
+		namespaceLister:        namespaceInformer.Lister(),
+	}
+
+	namespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueNamespace(logger, obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueNamespace(logger, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueNamespace(logger, obj)
+		},
+	})
+
+	return c, nil
+}
+
+// Controller deletes namespaces annotated with kube-bind.io/retain-until once that
+// time has passed, unless the APIServiceNamespace has been recreated in the meantime.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	kubeClient kubernetesclient.Interface
+
+	serviceNamespaceLister bindlisters.APIServiceNamespaceLister
+	namespaceLister        corelisters.NamespaceLister
+}
+
+func (c *Controller) enqueueNamespace(logger klog.Logger, obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger.V(2).Info("queueing Namespace", "key", key)
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithValues("Controller", controllerName)
+
+	logger.Info("Starting Controller")
+	defer logger.Info("Shutting down Controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	defer runtime.HandleCrash()
+
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := klog.FromContext(ctx).WithValues("key", key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(2).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q Controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+
+	defer c.updateMetrics()
+
+	ns, err := c.namespaceLister.Get(key)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	value, found := ns.Annotations[kuberesources.RetainUntilAnnotation]
+	if !found || ns.DeletionTimestamp != nil {
+		return nil
+	}
+
+	// the servicenamespace controller recovers the namespace if the APIServiceNamespace is back.
+	if snsKey := ns.Annotations[kubebindv1alpha1.APIServiceNamespaceAnnotationKey]; snsKey != "" {
+		snsNamespace, snsName, err := cache.SplitMetaNamespaceKey(snsKey)
+		if err != nil {
+			runtime.HandleError(err)
+			return nil // we cannot do anything
+		}
+		if _, err := c.serviceNamespaceLister.APIServiceNamespaces(snsNamespace).Get(snsName); err == nil {
+			return nil
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	retainUntil, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		logger.Error(err, "invalid annotation value", "annotation", kuberesources.RetainUntilAnnotation)
+		return nil // nothing we can do
+	}
+	if remaining := time.Until(retainUntil); remaining > 0 {
+		logger.V(2).Info("retaining namespace", "remaining", remaining)
+		c.queue.AddAfter(key, remaining)
+		return nil
+	}
+
+	logger.Info("Deleting namespace after retention period", "retainUntil", value)
+	if err := c.kubeClient.CoreV1().Namespaces().Delete(ctx, ns.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	reapedNamespaces.Inc()
+
+	return nil
+}
+
+func (c *Controller) updateMetrics() {
+	nss, err := c.namespaceLister.List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	retained := 0
+	for _, ns := range nss {
+		if isRetained(ns) {
+			retained++
+		}
+	}
+	retainedNamespaces.Set(float64(retained))
+}
+
+func isRetained(ns *corev1.Namespace) bool {
+	_, found := ns.Annotations[kuberesources.RetainUntilAnnotation]
+	return found && ns.DeletionTimestamp == nil
+}
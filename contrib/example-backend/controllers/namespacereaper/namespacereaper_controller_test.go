@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespacereaper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/testutil"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+)
+
+type recordingQueue struct {
+	workqueue.RateLimitingInterface
+	after []time.Duration
+}
+
+func (q *recordingQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.after = append(q.after, duration)
+}
+
+func TestProcess(t *testing.T) {
+	newNamespace := func(retainUntil time.Time) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster-abc-default",
+			Annotations: map[string]string{
+				kubebindv1alpha1.APIServiceNamespaceAnnotationKey: "cluster-abc/default",
+			},
+		}}
+		if !retainUntil.IsZero() {
+			ns.Annotations[kuberesources.RetainUntilAnnotation] = retainUntil.UTC().Format(time.RFC3339)
+		}
+		return ns
+	}
+
+	tests := []struct {
+		name             string
+		ns               *corev1.Namespace
+		serviceNamespace bool
+		wantDeleted      bool
+		wantRequeued     bool
+	}{
+		{
+			name: "not retained",
+			ns:   newNamespace(time.Time{}),
+		},
+		{
+			name:         "retention not expired",
+			ns:           newNamespace(time.Now().Add(time.Hour)),
+			wantRequeued: true,
+		},
+		{
+			name:        "retention expired",
+			ns:          newNamespace(time.Now().Add(-time.Minute)),
+			wantDeleted: true,
+		},
+		{
+			name:             "service namespace recreated",
+			ns:               newNamespace(time.Now().Add(-time.Minute)),
+			serviceNamespace: true,
+		},
+		{
+			name: "invalid annotation",
+			ns: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster-abc-default",
+				Annotations: map[string]string{kuberesources.RetainUntilAnnotation: "tomorrow"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			require.NoError(t, nsIndexer.Add(tt.ns))
+			snsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if tt.serviceNamespace {
+				require.NoError(t, snsIndexer.Add(&kubebindv1alpha1.APIServiceNamespace{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-abc", Name: "default"},
+				}))
+			}
+
+			queue := &recordingQueue{RateLimitingInterface: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)}
+			defer queue.ShutDown()
+			client := fake.NewSimpleClientset(tt.ns)
+			c := &Controller{
+				queue:                  queue,
+				kubeClient:             client,
+				serviceNamespaceLister: bindlisters.NewAPIServiceNamespaceLister(snsIndexer),
+				namespaceLister:        corelisters.NewNamespaceLister(nsIndexer),
+			}
+
+			require.NoError(t, c.process(context.Background(), tt.ns.Name))
+
+			_, err := client.CoreV1().Namespaces().Get(context.Background(), tt.ns.Name, metav1.GetOptions{})
+			if tt.wantDeleted {
+				require.True(t, errors.IsNotFound(err), "expected namespace to be deleted, got: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.wantRequeued {
+				require.Len(t, queue.after, 1)
+				require.InDelta(t, time.Hour, queue.after[0], float64(time.Minute))
+			} else {
+				require.Empty(t, queue.after)
+			}
+
+			// the lister still has the namespace, so the gauge only depends on the annotation.
+			retained, err := testutil.GetGaugeMetricValue(retainedNamespaces)
+			require.NoError(t, err)
+			if isRetained(tt.ns) {
+				require.Equal(t, float64(1), retained)
+			} else {
+				require.Equal(t, float64(0), retained)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemarollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/indexers"
+)
+
+const (
+	controllerName = "kube-bind-example-backend-schemarollout"
+)
+
+// NewController returns a new controller rolling out schema revisions of CRDs
+// to the ClusterBindings step by step.
+func NewController(
+	config *rest.Config,
+	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
+	serviceExportInformer bindinformers.APIServiceExportInformer,
+	clusterBindingInformer bindinformers.ClusterBindingInformer,
+) (*Controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	logger := klog.Background().WithValues("controller", controllerName)
+
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, controllerName)
+
+	bindClient, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	apiextensionsClient, err := apiextensionsclient.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	indexers.AddIfNotPresentOrDie(serviceExportInformer.Informer().GetIndexer(), cache.Indexers{
+		indexers.ServiceExportByCustomResourceDefinition: indexers.IndexServiceExportByCustomResourceDefinition,
+	})
+	serviceExportIndexer := serviceExportInformer.Informer().GetIndexer()
+
+	c := &Controller{
+		queue: queue,
+
+		crdLister: crdInformer.Lister(),
+
+		reconciler: reconciler{
+			listServiceExports: func(crdName string) ([]*kubebindv1alpha1.APIServiceExport, error) {
+				objs, err := serviceExportIndexer.ByIndex(indexers.ServiceExportByCustomResourceDefinition, crdName)
+				if err != nil {
+					return nil, err
+				}
+				exports := make([]*kubebindv1alpha1.APIServiceExport, 0, len(objs))
+				for _, obj := range objs {
+					exports = append(exports, obj.(*kubebindv1alpha1.APIServiceExport))
+				}
+				return exports, nil
+			},
+			getClusterBinding: func(ns string) (*kubebindv1alpha1.ClusterBinding, error) {
+				return clusterBindingInformer.Lister().ClusterBindings(ns).Get("cluster")
+			},
+			approveServiceExport: func(ctx context.Context, ns, name, revision string) error {
+				patch, err := annotationPatch(kuberesources.SchemaRolloutRevisionAnnotation, revision)
+				if err != nil {
+					return err
+				}
+				_, err = bindClient.KubeBindV1alpha1().APIServiceExports(ns).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+				return err
+			},
+			updateRolloutStatus: func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, status string) error {
+				patch, err := annotationPatch(kuberesources.SchemaRolloutStatusAnnotation, status)
+				if err != nil {
+					return err
+				}
+				_, err = apiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().Patch(ctx, crd.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+				return err
+			},
+		},
+	}
+
+	crdInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueCRD(logger, obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueCRD(logger, newObj)
+		},
+	})
+
+	serviceExportInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueServiceExport(logger, obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueServiceExport(logger, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueServiceExport(logger, obj)
+		},
+	})
+
+	return c, nil
+}
+
+// Controller approves schema revisions of CRDs annotated with kube-bind.io/schema-rollout-step
+// for a batch of APIServiceExports at a time, and pauses when an upgraded export becomes unhealthy.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	crdLister apiextensionslisters.CustomResourceDefinitionLister
+
+	reconciler
+}
+
+func annotationPatch(key, value string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{key: value},
+		},
+	})
+}
+
+func (c *Controller) enqueueCRD(logger klog.Logger, obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger.V(2).Info("queueing CustomResourceDefinition", "key", key)
+	c.queue.Add(key)
+}
+
+func (c *Controller) enqueueServiceExport(logger klog.Logger, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	export, ok := obj.(*kubebindv1alpha1.APIServiceExport)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected type %T", obj))
+		return
+	}
+
+	logger.V(2).Info("queueing CustomResourceDefinition", "key", export.Name, "reason", "APIServiceExport", "APIServiceExportKey", export.Namespace+"/"+export.Name)
+	c.queue.Add(export.Name)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	defer runtime.HandleCrash()
+
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := klog.FromContext(ctx).WithValues("key", key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(2).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) process(ctx context.Context, key string) error {
+	crd, err := c.crdLister.Get(key)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return c.reconcile(ctx, crd)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemarollout
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+)
+
+type reconciler struct {
+	listServiceExports func(crdName string) ([]*kubebindv1alpha1.APIServiceExport, error)
+	getClusterBinding  func(ns string) (*kubebindv1alpha1.ClusterBinding, error)
+
+	approveServiceExport func(ctx context.Context, ns, name, revision string) error
+	updateRolloutStatus  func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, status string) error
+}
+
+// reconcile moves the canary rollout of the schema revision of the CRD forward.
+// The next batch of exports is approved only when all approved exports are bound
+// and healthy. A failing export pauses the rollout until it recovers, or until the
+// provider rolls back the revision.
+func (r *reconciler) reconcile(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) error {
+	logger := klog.FromContext(ctx)
+
+	revision := crd.Annotations[kuberesources.SchemaRevisionAnnotation]
+	if _, found := crd.Annotations[kuberesources.SchemaRolloutStepAnnotation]; !found || revision == "" {
+		return nil
+	}
+
+	selector, err := kuberesources.SchemaRolloutSelector(crd)
+	if err != nil {
+		return r.setStatus(ctx, crd, fmt.Sprintf("Failed: %v", err))
+	}
+	exports, err := r.listServiceExports(crd.Name)
+	if err != nil {
+		return err
+	}
+
+	var eligible, pending, inFlight, regressed []*kubebindv1alpha1.APIServiceExport
+	for _, export := range exports {
+		if ok, err := r.selected(export, selector); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		eligible = append(eligible, export)
+
+		switch {
+		case export.Spec.SchemaRevision != revision && export.Annotations[kuberesources.SchemaRolloutRevisionAnnotation] != revision:
+			pending = append(pending, export)
+		case regression(export, revision):
+			regressed = append(regressed, export)
+		case !settled(export, revision):
+			inFlight = append(inFlight, export)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Namespace < pending[j].Namespace })
+
+	upgraded := len(eligible) - len(pending)
+	if len(regressed) > 0 {
+		names := make([]string, 0, len(regressed))
+		for _, export := range regressed {
+			names = append(names, export.Namespace)
+		}
+		sort.Strings(names)
+		logger.Info("Pausing schema rollout on regressions", "revision", revision, "clusterBindings", names)
+		return r.setStatus(ctx, crd, fmt.Sprintf("Paused: revision %s is unhealthy for ClusterBindings %s", revision, strings.Join(names, ", ")))
+	}
+	if len(pending) == 0 {
+		return r.setStatus(ctx, crd, fmt.Sprintf("Complete: revision %s on %d/%d ClusterBindings", revision, upgraded, len(eligible)))
+	}
+	if len(inFlight) > 0 {
+		return r.setStatus(ctx, crd, fmt.Sprintf("Progressing: revision %s on %d/%d ClusterBindings, waiting for %d", revision, upgraded, len(eligible), len(inFlight)))
+	}
+
+	step, _, err := kuberesources.SchemaRolloutStep(crd, len(eligible))
+	if err != nil {
+		return r.setStatus(ctx, crd, fmt.Sprintf("Failed: %v", err))
+	}
+	if step > len(pending) {
+		step = len(pending)
+	}
+	for _, export := range pending[:step] {
+		logger.V(1).Info("Approving schema revision", "revision", revision, "namespace", export.Namespace)
+		if err := r.approveServiceExport(ctx, export.Namespace, export.Name, revision); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return r.setStatus(ctx, crd, fmt.Sprintf("Progressing: revision %s on %d/%d ClusterBindings, waiting for %d", revision, upgraded+step, len(eligible), step))
+}
+
+func (r *reconciler) selected(export *kubebindv1alpha1.APIServiceExport, selector labels.Selector) (bool, error) {
+	if selector.Empty() {
+		return true, nil
+	}
+	binding, err := r.getClusterBinding(export.Namespace)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	} else if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return selector.Matches(labels.Set(binding.Labels)), nil
+}
+
+func (r *reconciler) setStatus(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, status string) error {
+	if crd.Annotations[kuberesources.SchemaRolloutStatusAnnotation] == status {
+		return nil
+	}
+	return r.updateRolloutStatus(ctx, crd, status)
+}
+
+// regression returns whether an export fails after it has been upgraded to the revision.
+func regression(export *kubebindv1alpha1.APIServiceExport, revision string) bool {
+	if export.Spec.SchemaRevision != revision {
+		return false
+	}
+	ready := conditions.Get(export, conditionsapi.ReadyCondition)
+	return ready != nil && ready.Status == "False" && ready.Severity == conditionsapi.ConditionSeverityError
+}
+
+// settled returns whether an approved export has been upgraded and the consumer
+// cluster bound the revision and is healthy, or does not follow it at all.
+func settled(export *kubebindv1alpha1.APIServiceExport, revision string) bool {
+	if export.Spec.SchemaRevision != revision {
+		return false
+	}
+	if !conditions.Has(export, conditionsapi.ReadyCondition) || conditions.IsFalse(export, kubebindv1alpha1.APIServiceExportConditionConnected) {
+		return true // no konnector reports for this export
+	}
+	if pinned := export.Status.PinnedSchemaRevision; pinned != "" && pinned != revision {
+		return true // the consumer stays on another revision
+	}
+	return export.Status.SchemaRevision == revision && conditions.IsTrue(export, conditionsapi.ReadyCondition)
+}
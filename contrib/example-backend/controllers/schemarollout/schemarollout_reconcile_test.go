@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemarollout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+)
+
+func TestReconcile(t *testing.T) {
+	type state int
+	const (
+		pending state = iota
+		approved
+		healthy
+		failing
+	)
+	newExport := func(ns string, s state) *kubebindv1alpha1.APIServiceExport {
+		export := &kubebindv1alpha1.APIServiceExport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "foos.example.com"},
+		}
+		export.Spec.SchemaRevision = "r1"
+		conditions.MarkTrue(export, kubebindv1alpha1.APIServiceExportConditionConnected)
+		conditions.MarkTrue(export, conditionsapi.ReadyCondition)
+		export.Status.SchemaRevision = "r1"
+		switch s {
+		case approved:
+			export.Spec.SchemaRevision = "r2"
+		case healthy:
+			export.Spec.SchemaRevision = "r2"
+			export.Status.SchemaRevision = "r2"
+		case failing:
+			export.Spec.SchemaRevision = "r2"
+			conditions.MarkFalse(export, conditionsapi.ReadyCondition, "Failed", conditionsapi.ConditionSeverityError, "failed")
+		}
+		return export
+	}
+
+	tests := []struct {
+		name         string
+		step         string
+		selector     string
+		exports      []*kubebindv1alpha1.APIServiceExport
+		wantApproved []string
+		wantStatus   string
+	}{
+		{
+			name:         "first step",
+			step:         "1",
+			exports:      []*kubebindv1alpha1.APIServiceExport{newExport("b", pending), newExport("a", pending), newExport("c", pending)},
+			wantApproved: []string{"a"},
+			wantStatus:   "Progressing: revision r2 on 1/3 ClusterBindings, waiting for 1",
+		},
+		{
+			name:       "waiting for upgraded export",
+			step:       "1",
+			exports:    []*kubebindv1alpha1.APIServiceExport{newExport("a", approved), newExport("b", pending)},
+			wantStatus: "Progressing: revision r2 on 1/2 ClusterBindings, waiting for 1",
+		},
+		{
+			name:         "percentage step",
+			step:         "50%",
+			exports:      []*kubebindv1alpha1.APIServiceExport{newExport("a", healthy), newExport("b", pending), newExport("c", pending), newExport("d", pending)},
+			wantApproved: []string{"b", "c"},
+			wantStatus:   "Progressing: revision r2 on 3/4 ClusterBindings, waiting for 2",
+		},
+		{
+			name:       "paused on regression",
+			step:       "1",
+			exports:    []*kubebindv1alpha1.APIServiceExport{newExport("a", failing), newExport("b", pending)},
+			wantStatus: "Paused: revision r2 is unhealthy for ClusterBindings a",
+		},
+		{
+			name:         "restricted by selector",
+			step:         "100%",
+			selector:     "canary=true",
+			exports:      []*kubebindv1alpha1.APIServiceExport{newExport("a", pending), newExport("b", pending)},
+			wantApproved: []string{"a"},
+			wantStatus:   "Progressing: revision r2 on 1/1 ClusterBindings, waiting for 1",
+		},
+		{
+			name:       "complete",
+			step:       "1",
+			exports:    []*kubebindv1alpha1.APIServiceExport{newExport("a", healthy), newExport("b", healthy)},
+			wantStatus: "Complete: revision r2 on 2/2 ClusterBindings",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crd := &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foos.example.com",
+					Annotations: map[string]string{
+						kuberesources.SchemaRevisionAnnotation:    "r2",
+						kuberesources.SchemaRolloutStepAnnotation: tt.step,
+					},
+				},
+			}
+			if tt.selector != "" {
+				crd.Annotations[kuberesources.SchemaRolloutSelectorAnnotation] = tt.selector
+			}
+
+			var gotApproved []string
+			var gotStatus string
+			r := &reconciler{
+				listServiceExports: func(crdName string) ([]*kubebindv1alpha1.APIServiceExport, error) {
+					return tt.exports, nil
+				},
+				getClusterBinding: func(ns string) (*kubebindv1alpha1.ClusterBinding, error) {
+					if ns != "a" {
+						return nil, errors.NewNotFound(schema.GroupResource{}, "cluster")
+					}
+					return &kubebindv1alpha1.ClusterBinding{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"canary": "true"}}}, nil
+				},
+				approveServiceExport: func(ctx context.Context, ns, name, revision string) error {
+					gotApproved = append(gotApproved, ns)
+					return nil
+				},
+				updateRolloutStatus: func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, status string) error {
+					gotStatus = status
+					return nil
+				},
+			}
+
+			err := r.reconcile(context.Background(), crd)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantApproved, gotApproved)
+			require.Equal(t, tt.wantStatus, gotStatus)
+		})
+	}
+}
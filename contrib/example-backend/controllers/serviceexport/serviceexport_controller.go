@@ -34,6 +34,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/kube-bind/kube-bind/contrib/example-backend/fairqueue"
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
@@ -49,10 +50,13 @@ const (
 // NewController returns a new controller to reconcile ServiceExports.
 func NewController(
 	config *rest.Config,
+	region string,
+	groupAliasSuffix string,
 	serviceExportInformer bindinformers.APIServiceExportInformer,
 	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
 ) (*Controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	// serve consumers fairly, independent of how many objects they have
+	queue := fairqueue.NewNamedRateLimitingQueue(fairqueue.DefaultControllerRateLimiter(fairqueue.NamespaceTenant), controllerName, fairqueue.NamespaceTenant)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -76,6 +80,8 @@ func NewController(
 		crdIndexer: crdInformer.Informer().GetIndexer(),
 
 		reconciler: reconciler{
+			region:           region,
+			groupAliasSuffix: groupAliasSuffix,
 			getCRD: func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 				return crdInformer.Lister().Get(name)
 			},
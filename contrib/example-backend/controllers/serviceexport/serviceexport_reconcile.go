@@ -20,10 +20,12 @@ import (
 	"context"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
 	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
@@ -31,6 +33,11 @@ import (
 )
 
 type reconciler struct {
+	// region is kept as kube-bind.io/region label on APIServiceExports. Empty removes it.
+	region string
+	// groupAliasSuffix is kept as kube-bind.io/group-alias-suffix annotation on APIServiceExports. Empty removes it.
+	groupAliasSuffix string
+
 	getCRD              func(name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	deleteServiceExport func(ctx context.Context, namespace, name string) error
 
@@ -40,6 +47,9 @@ type reconciler struct {
 func (r *reconciler) reconcile(ctx context.Context, export *kubebindv1alpha1.APIServiceExport) error {
 	var errs []error
 
+	r.ensureRegion(export)
+	r.ensureGroupAliasSuffix(export)
+
 	if specChanged, err := r.ensureSchema(ctx, export); err != nil {
 		errs = append(errs, err)
 	} else if specChanged {
@@ -50,6 +60,34 @@ func (r *reconciler) reconcile(ctx context.Context, export *kubebindv1alpha1.API
 	return utilerrors.NewAggregate(errs)
 }
 
+func (r *reconciler) ensureRegion(export *kubebindv1alpha1.APIServiceExport) {
+	if export.Labels[kubebindv1alpha1.RegionLabelKey] == r.region {
+		return
+	}
+	if r.region == "" {
+		delete(export.Labels, kubebindv1alpha1.RegionLabelKey)
+		return
+	}
+	if export.Labels == nil {
+		export.Labels = map[string]string{}
+	}
+	export.Labels[kubebindv1alpha1.RegionLabelKey] = r.region
+}
+
+func (r *reconciler) ensureGroupAliasSuffix(export *kubebindv1alpha1.APIServiceExport) {
+	if export.Annotations[kubebindv1alpha1.GroupAliasSuffixAnnotationKey] == r.groupAliasSuffix {
+		return
+	}
+	if r.groupAliasSuffix == "" {
+		delete(export.Annotations, kubebindv1alpha1.GroupAliasSuffixAnnotationKey)
+		return
+	}
+	if export.Annotations == nil {
+		export.Annotations = map[string]string{}
+	}
+	export.Annotations[kubebindv1alpha1.GroupAliasSuffixAnnotationKey] = r.groupAliasSuffix
+}
+
 func (r *reconciler) ensureSchema(ctx context.Context, export *kubebindv1alpha1.APIServiceExport) (specChanged bool, err error) {
 	logger := klog.FromContext(ctx)
 
@@ -77,10 +115,21 @@ func (r *reconciler) ensureSchema(ctx context.Context, export *kubebindv1alpha1.
 		return false, nil //nothing we can do
 	}
 
-	if hash := kubebindhelpers.APIServiceExportCRDSpecHash(expected); export.Annotations[kubebindv1alpha1.SourceSpecHashAnnotationKey] != hash {
+	revision := crd.Annotations[kuberesources.SchemaRevisionAnnotation]
+	if export.Spec.SchemaRevision != revision && !kuberesources.SchemaRevisionApproved(crd, export, revision) {
+		// canary rollout: keep the current schema until the rollout reaches this export
+		logger.V(2).Info("Waiting for schema rollout", "schemaRevision", revision)
+		revision = export.Spec.SchemaRevision
+		expected = export.Spec.APIServiceExportCRDSpec.DeepCopy()
+	}
+	if hash := kubebindhelpers.APIServiceExportCRDSpecHash(expected); export.Annotations[kubebindv1alpha1.SourceSpecHashAnnotationKey] != hash || export.Spec.SchemaRevision != revision {
 		// both exist, update APIServiceExport
-		logger.V(1).Info("Updating APIServiceExport")
+		logger.V(1).Info("Updating APIServiceExport", "schemaRevision", revision)
+		if export.Spec.SchemaRevision != revision {
+			export.Spec.PreviousSchemaRevisions = kuberesources.PublishSchemaRevision(export, revision)
+		}
 		export.Spec.APIServiceExportCRDSpec = *expected
+		export.Spec.SchemaRevision = revision
 		if export.Annotations == nil {
 			export.Annotations = map[string]string{}
 		}
@@ -88,6 +137,42 @@ func (r *reconciler) ensureSchema(ctx context.Context, export *kubebindv1alpha1.
 		return true, nil
 	}
 
+	if eventsAccess := crd.Annotations[kuberesources.EventsAccessAnnotation] == "true"; export.Spec.EventsAccess != eventsAccess {
+		logger.V(1).Info("Updating APIServiceExport events access", "eventsAccess", eventsAccess)
+		export.Spec.EventsAccess = eventsAccess
+		return true, nil
+	}
+
+	if eventRelay := kuberesources.EventRelay(crd); !equality.Semantic.DeepEqual(export.Spec.EventRelay, eventRelay) {
+		logger.V(1).Info("Updating APIServiceExport event relay", "eventRelay", eventRelay)
+		export.Spec.EventRelay = eventRelay
+		return true, nil
+	}
+
+	if capabilities := kuberesources.ExportCapabilities(ctx, crd); !equality.Semantic.DeepEqual(export.Spec.Capabilities, capabilities) {
+		logger.V(1).Info("Updating APIServiceExport capabilities", "capabilities", capabilities)
+		export.Spec.Capabilities = capabilities
+		return true, nil
+	}
+
+	if retention := kuberesources.NamespaceRetention(crd); !equality.Semantic.DeepEqual(export.Spec.NamespaceRetention, retention) {
+		logger.V(1).Info("Updating APIServiceExport namespace retention", "retention", retention)
+		export.Spec.NamespaceRetention = retention
+		return true, nil
+	}
+
+	if statusSync := kuberesources.StatusSync(ctx, crd); !equality.Semantic.DeepEqual(export.Spec.StatusSync, statusSync) {
+		logger.V(1).Info("Updating APIServiceExport status sync", "statusSync", statusSync)
+		export.Spec.StatusSync = statusSync
+		return true, nil
+	}
+
+	if notices := kuberesources.Notices(crd); !equality.Semantic.DeepEqual(export.Spec.Notices, notices) {
+		logger.V(1).Info("Updating APIServiceExport notices", "notices", len(notices))
+		export.Spec.Notices = notices
+		return true, nil
+	}
+
 	conditions.MarkTrue(export, kubebindv1alpha1.APIServiceExportConditionProviderInSync)
 
 	return false, nil
@@ -35,6 +35,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/kube-bind/kube-bind/contrib/example-backend/fairqueue"
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
@@ -52,11 +53,15 @@ const (
 func NewController(
 	config *rest.Config,
 	scope kubebindv1alpha1.Scope,
+	requireApproval bool,
+	region string,
+	groupAliasSuffix string,
 	serviceExportRequestInformer bindinformers.APIServiceExportRequestInformer,
 	serviceExportInformer bindinformers.APIServiceExportInformer,
 	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
 ) (*Controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	// serve consumers fairly, independent of how many objects they have
+	queue := fairqueue.NewNamedRateLimitingQueue(fairqueue.DefaultControllerRateLimiter(fairqueue.NamespaceTenant), controllerName, fairqueue.NamespaceTenant)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -88,7 +93,10 @@ func NewController(
 		crdIndexer: crdInformer.Informer().GetIndexer(),
 
 		reconciler: reconciler{
-			informerScope: scope,
+			informerScope:    scope,
+			requireApproval:  requireApproval,
+			region:           region,
+			groupAliasSuffix: groupAliasSuffix,
 			getCRD: func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 				return crdInformer.Lister().Get(name)
 			},
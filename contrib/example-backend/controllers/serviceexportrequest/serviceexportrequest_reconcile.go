@@ -26,6 +26,7 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
@@ -33,7 +34,12 @@ import (
 )
 
 type reconciler struct {
-	informerScope kubebindv1alpha1.Scope
+	informerScope   kubebindv1alpha1.Scope
+	requireApproval bool
+	// region is set as kube-bind.io/region label on APIServiceExports if not empty.
+	region string
+	// groupAliasSuffix is set as kube-bind.io/group-alias-suffix annotation on APIServiceExports if not empty.
+	groupAliasSuffix string
 
 	getCRD              func(name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	getServiceExport    func(ns, name string) (*kubebindv1alpha1.APIServiceExport, error)
@@ -58,6 +64,36 @@ func (r *reconciler) ensureExports(ctx context.Context, req *kubebindv1alpha1.AP
 	logger := klog.FromContext(ctx)
 
 	if req.Status.Phase == kubebindv1alpha1.APIServiceExportRequestPhasePending {
+		if r.requireApproval {
+			switch req.Annotations[kuberesources.ApprovalAnnotation] {
+			case kuberesources.ApprovalApproved:
+			case kuberesources.ApprovalDenied:
+				message := "APIServiceExportRequest was denied by the service provider"
+				if reason := req.Annotations[kuberesources.ApprovalMessageAnnotation]; reason != "" {
+					message += ": " + reason
+				}
+				conditions.MarkFalse(
+					req,
+					kubebindv1alpha1.APIServiceExportRequestConditionExportsReady,
+					"Denied",
+					conditionsapi.ConditionSeverityError,
+					message,
+				)
+				req.Status.Phase = kubebindv1alpha1.APIServiceExportRequestPhaseFailed
+				req.Status.TerminalMessage = message
+				return nil
+			default:
+				conditions.MarkFalse(
+					req,
+					kubebindv1alpha1.APIServiceExportRequestConditionExportsReady,
+					"PendingApproval",
+					conditionsapi.ConditionSeverityInfo,
+					"Waiting for approval by the service provider",
+				)
+				return nil
+			}
+		}
+
 		failure := false
 		for _, res := range req.Spec.Resources {
 			name := res.Resource + "." + res.Group
@@ -110,9 +146,23 @@ func (r *reconciler) ensureExports(ctx context.Context, req *kubebindv1alpha1.AP
 				Spec: kubebindv1alpha1.APIServiceExportSpec{
 					APIServiceExportCRDSpec: *exportSpec,
 					InformerScope:           r.informerScope,
+					Isolation:               kuberesources.ExportIsolation(crd),
+					EventsAccess:            crd.Annotations[kuberesources.EventsAccessAnnotation] == "true",
+					EventRelay:              kuberesources.EventRelay(crd),
+					Capabilities:            kuberesources.ExportCapabilities(ctx, crd),
+					NamespaceRetention:      kuberesources.NamespaceRetention(crd),
+					StatusSync:              kuberesources.StatusSync(ctx, crd),
+					SchemaRevision:          crd.Annotations[kuberesources.SchemaRevisionAnnotation],
 				},
 			}
 
+			if r.region != "" {
+				export.Labels = map[string]string{kubebindv1alpha1.RegionLabelKey: r.region}
+			}
+			if r.groupAliasSuffix != "" {
+				export.Annotations[kubebindv1alpha1.GroupAliasSuffixAnnotationKey] = r.groupAliasSuffix
+			}
+
 			logger.V(1).Info("Creating APIServiceExport", "name", export.Name, "namespace", export.Namespace)
 			if _, err = r.createServiceExport(ctx, export); err != nil {
 				return err
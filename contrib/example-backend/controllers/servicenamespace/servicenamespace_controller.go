@@ -26,6 +26,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -39,6 +40,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/kube-bind/kube-bind/contrib/example-backend/fairqueue"
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
@@ -62,7 +64,8 @@ func NewController(
 	roleInformer rbacinformers.RoleInformer,
 	roleBindingInformer rbacinformers.RoleBindingInformer,
 ) (*Controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	// serve consumers fairly, independent of how many objects they have
+	queue := fairqueue.NewNamedRateLimitingQueue(fairqueue.DefaultControllerRateLimiter(fairqueue.NamespaceTenant), controllerName, fairqueue.NamespaceTenant)
 
 	logger := klog.Background().WithValues("Controller", controllerName)
 
@@ -109,10 +112,17 @@ func NewController(
 			createNamespace: func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error) {
 				return kubeClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
 			},
+			updateNamespace: func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error) {
+				return kubeClient.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+			},
 			deleteNamespace: func(ctx context.Context, name string) error {
 				return kubeClient.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{})
 			},
 
+			listServiceExports: func(ns string) ([]*kubebindv1alpha1.APIServiceExport, error) {
+				return serviceExportInformer.Lister().APIServiceExports(ns).List(labels.Everything())
+			},
+
 			getRoleBinding: func(ns, name string) (*rbacv1.RoleBinding, error) {
 				return roleBindingInformer.Lister().RoleBindings(ns).Get(name)
 			},
@@ -194,8 +204,8 @@ func NewController(
 type Resource = committer.Resource[*kubebindv1alpha1.APIServiceNamespaceSpec, *kubebindv1alpha1.APIServiceNamespaceStatus]
 type CommitFunc = func(context.Context, *Resource, *Resource) error
 
-// Controller reconciles ServiceNamespaces by creating a Namespace for each, and deleting it if
-// the APIServiceNamespace is deleted.
+// Controller reconciles ServiceNamespaces by creating a Namespace for each, and deleting
+// or retaining it if the APIServiceNamespace is deleted.
 type Controller struct {
 	queue workqueue.RateLimitingInterface
 
@@ -375,10 +385,7 @@ func (c *Controller) process(ctx context.Context, key string) error {
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	} else if errors.IsNotFound(err) {
-		if err := c.deleteNamespace(ctx, nsName); err != nil && !errors.IsNotFound(err) {
-			return err
-		}
-		return nil
+		return c.reconcileDeletion(ctx, snsNamespace, nsName)
 	}
 
 	old := obj
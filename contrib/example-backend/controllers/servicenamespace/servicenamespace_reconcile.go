@@ -18,16 +18,21 @@ package servicenamespace
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
 
 	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 )
 
 type reconciler struct {
@@ -35,8 +40,11 @@ type reconciler struct {
 
 	getNamespace    func(name string) (*corev1.Namespace, error)
 	createNamespace func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error)
+	updateNamespace func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error)
 	deleteNamespace func(ctx context.Context, name string) error
 
+	listServiceExports func(ns string) ([]*kubebindv1alpha1.APIServiceExport, error)
+
 	getRoleBinding    func(ns, name string) (*rbacv1.RoleBinding, error)
 	createRoleBinding func(ctx context.Context, crb *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
 	updateRoleBinding func(ctx context.Context, cr *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
@@ -44,7 +52,7 @@ type reconciler struct {
 
 func (c *reconciler) reconcile(ctx context.Context, sns *kubebindv1alpha1.APIServiceNamespace) error {
 	var ns *corev1.Namespace
-	nsName := sns.Namespace + "-" + sns.Name
+	nsName := serviceNamespaceName(sns)
 	if sns.Status.Namespace != "" {
 		nsName = sns.Status.Namespace
 		ns, _ = c.getNamespace(nsName) // golint:errcheck
@@ -61,13 +69,23 @@ func (c *reconciler) reconcile(ctx context.Context, sns *kubebindv1alpha1.APISer
 		if _, err := c.createNamespace(ctx, ns); err != nil && !errors.IsAlreadyExists(err) {
 			return fmt.Errorf("failed to create namespace %q: %w", nsName, err)
 		}
+	} else if _, found := ns.Annotations[kuberesources.RetainUntilAnnotation]; found {
+		// the consumer namespace came back while the namespace was retained. Recover it.
+		ns = ns.DeepCopy()
+		delete(ns.Annotations, kuberesources.RetainUntilAnnotation)
+		if _, err := c.updateNamespace(ctx, ns); err != nil {
+			return fmt.Errorf("failed to recover retained namespace %q: %w", nsName, err)
+		}
 	}
 
 	if c.scope == kubebindv1alpha1.NamespacedScope {
-		if err := c.ensureRBACRoleBinding(ctx, nsName, sns); err != nil {
+		if err := c.ensureRBACRoleBinding(ctx, nsName, "kube-binder", "kube-binder-"+sns.Namespace, sns); err != nil {
 			return fmt.Errorf("failed to ensure RBAC: %w", err)
 		}
 	}
+	if err := c.ensureRBACRoleBinding(ctx, nsName, kuberesources.EventsRoleBindingName, kuberesources.EventsClusterRoleName(sns.Namespace), sns); err != nil {
+		return fmt.Errorf("failed to ensure RBAC for events: %w", err)
+	}
 
 	if sns.Status.Namespace != nsName {
 		sns.Status.Namespace = nsName
@@ -76,8 +94,68 @@ func (c *reconciler) reconcile(ctx context.Context, sns *kubebindv1alpha1.APISer
 	return nil
 }
 
-func (c *reconciler) ensureRBACRoleBinding(ctx context.Context, ns string, sns *kubebindv1alpha1.APIServiceNamespace) error {
-	objName := "kube-binder"
+// serviceNamespaceName returns the name of the namespace of an APIServiceNamespace. With
+// Object isolation, the object name is hashed as it can contain dots and be long.
+func serviceNamespaceName(sns *kubebindv1alpha1.APIServiceNamespace) string {
+	object := helpers.IsolatedObject(sns.Name)
+	if object == "" {
+		return sns.Namespace + "-" + sns.Name
+	}
+	hash := sha256.Sum256([]byte(object))
+	return sns.Namespace + "-" + helpers.ConsumerNamespace(sns.Name) + "-" + hex.EncodeToString(hash[:])[:10]
+}
+
+// reconcileDeletion deletes the namespace of a deleted APIServiceNamespace, or marks
+// it for retention if any APIServiceExport of the consumer asks for it. Retained
+// namespaces are deleted by the namespace reaper.
+func (c *reconciler) reconcileDeletion(ctx context.Context, snsNamespace, nsName string) error {
+	logger := klog.FromContext(ctx)
+
+	ns, err := c.getNamespace(nsName)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if ns.DeletionTimestamp != nil {
+		return nil
+	}
+	if _, found := ns.Annotations[kuberesources.RetainUntilAnnotation]; found {
+		return nil // already retained
+	}
+
+	exports, err := c.listServiceExports(snsNamespace)
+	if err != nil {
+		return err
+	}
+	var retention time.Duration
+	for _, export := range exports {
+		if export.Spec.NamespaceRetention != nil && export.Spec.NamespaceRetention.Duration > retention {
+			retention = export.Spec.NamespaceRetention.Duration
+		}
+	}
+
+	if retention == 0 {
+		if err := c.deleteNamespace(ctx, nsName); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	retainUntil := time.Now().Add(retention).UTC().Format(time.RFC3339)
+	logger.Info("Retaining namespace", "namespace", nsName, "retainUntil", retainUntil)
+	ns = ns.DeepCopy()
+	if ns.Annotations == nil {
+		ns.Annotations = map[string]string{}
+	}
+	ns.Annotations[kuberesources.RetainUntilAnnotation] = retainUntil
+	if _, err := c.updateNamespace(ctx, ns); err != nil {
+		return fmt.Errorf("failed to retain namespace %q: %w", nsName, err)
+	}
+	return nil
+}
+
+func (c *reconciler) ensureRBACRoleBinding(ctx context.Context, ns, objName, clusterRoleName string, sns *kubebindv1alpha1.APIServiceNamespace) error {
 	binding, err := c.getRoleBinding(ns, objName)
 	if err != nil && !errors.IsNotFound(err) {
 		return fmt.Errorf("failed to get role binding %s/%s: %w", ns, objName, err)
@@ -97,7 +175,7 @@ func (c *reconciler) ensureRBACRoleBinding(ctx context.Context, ns string, sns *
 		},
 		RoleRef: rbacv1.RoleRef{
 			Kind:     "ClusterRole",
-			Name:     "kube-binder-" + sns.Namespace,
+			Name:     clusterRoleName,
 			APIGroup: "rbac.authorization.k8s.io",
 		},
 	}
@@ -0,0 +1,201 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicenamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestReconcileDeletion(t *testing.T) {
+	newExport := func(name string, retention time.Duration) *kubebindv1alpha1.APIServiceExport {
+		export := &kubebindv1alpha1.APIServiceExport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-abc", Name: name},
+		}
+		if retention > 0 {
+			export.Spec.NamespaceRetention = &metav1.Duration{Duration: retention}
+		}
+		return export
+	}
+	now := metav1.Now()
+
+	tests := []struct {
+		name          string
+		ns            *corev1.Namespace
+		exports       []*kubebindv1alpha1.APIServiceExport
+		wantDeleted   bool
+		wantRetention time.Duration
+	}{
+		{
+			name: "namespace not found",
+		},
+		{
+			name:        "no retention",
+			ns:          &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster-abc-default"}},
+			exports:     []*kubebindv1alpha1.APIServiceExport{newExport("foos.example.com", 0)},
+			wantDeleted: true,
+		},
+		{
+			name:          "longest retention wins",
+			ns:            &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster-abc-default"}},
+			exports:       []*kubebindv1alpha1.APIServiceExport{newExport("foos.example.com", time.Hour), newExport("bars.example.com", 2*time.Hour), newExport("bazs.example.com", 0)},
+			wantRetention: 2 * time.Hour,
+		},
+		{
+			name: "already retained",
+			ns: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster-abc-default",
+				Annotations: map[string]string{kuberesources.RetainUntilAnnotation: "2000-01-01T00:00:00Z"},
+			}},
+			exports: []*kubebindv1alpha1.APIServiceExport{newExport("foos.example.com", time.Hour)},
+		},
+		{
+			name:    "namespace terminating",
+			ns:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster-abc-default", DeletionTimestamp: &now}},
+			exports: []*kubebindv1alpha1.APIServiceExport{newExport("foos.example.com", 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted bool
+			var updated *corev1.Namespace
+			r := &reconciler{
+				getNamespace: func(name string) (*corev1.Namespace, error) {
+					if tt.ns == nil {
+						return nil, errors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, name)
+					}
+					return tt.ns, nil
+				},
+				updateNamespace: func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error) {
+					updated = ns
+					return ns, nil
+				},
+				deleteNamespace: func(ctx context.Context, name string) error {
+					require.Equal(t, "cluster-abc-default", name)
+					deleted = true
+					return nil
+				},
+				listServiceExports: func(ns string) ([]*kubebindv1alpha1.APIServiceExport, error) {
+					require.Equal(t, "cluster-abc", ns)
+					return tt.exports, nil
+				},
+			}
+
+			before := time.Now()
+			err := r.reconcileDeletion(context.Background(), "cluster-abc", "cluster-abc-default")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDeleted, deleted)
+
+			if tt.wantRetention == 0 {
+				require.Nil(t, updated)
+				return
+			}
+			require.NotNil(t, updated)
+			retainUntil, err := time.Parse(time.RFC3339, updated.Annotations[kuberesources.RetainUntilAnnotation])
+			require.NoError(t, err)
+			require.WithinDuration(t, before.Add(tt.wantRetention), retainUntil, time.Minute)
+		})
+	}
+}
+
+func TestReconcileRecoversRetainedNamespace(t *testing.T) {
+	sns := &kubebindv1alpha1.APIServiceNamespace{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-abc", Name: "default"},
+		Status:     kubebindv1alpha1.APIServiceNamespaceStatus{Namespace: "cluster-abc-default"},
+	}
+	retained := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "cluster-abc-default",
+		Annotations: map[string]string{kuberesources.RetainUntilAnnotation: "2000-01-01T00:00:00Z", "other": "value"},
+	}}
+
+	var updated *corev1.Namespace
+	r := &reconciler{
+		scope: kubebindv1alpha1.ClusterScope,
+		getNamespace: func(name string) (*corev1.Namespace, error) {
+			return retained, nil
+		},
+		createNamespace: func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error) {
+			t.Fatalf("unexpected namespace creation")
+			return nil, nil
+		},
+		updateNamespace: func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error) {
+			updated = ns
+			return ns, nil
+		},
+		getRoleBinding: func(ns, name string) (*rbacv1.RoleBinding, error) {
+			return nil, errors.NewNotFound(schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "rolebindings"}, name)
+		},
+		createRoleBinding: func(ctx context.Context, rb *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
+			return rb, nil
+		},
+	}
+
+	require.NoError(t, r.reconcile(context.Background(), sns))
+	require.NotNil(t, updated)
+	require.NotContains(t, updated.Annotations, kuberesources.RetainUntilAnnotation)
+	require.Equal(t, "value", updated.Annotations["other"])
+	require.Contains(t, retained.Annotations, kuberesources.RetainUntilAnnotation, "cached object must not be mutated")
+}
+
+func TestReconcileEventsRoleBinding(t *testing.T) {
+	for _, scope := range []kubebindv1alpha1.Scope{kubebindv1alpha1.ClusterScope, kubebindv1alpha1.NamespacedScope} {
+		t.Run(string(scope), func(t *testing.T) {
+			sns := &kubebindv1alpha1.APIServiceNamespace{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-abc", Name: "default"},
+			}
+			created := map[string]*rbacv1.RoleBinding{}
+			r := &reconciler{
+				scope: scope,
+				getNamespace: func(name string) (*corev1.Namespace, error) {
+					return nil, errors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, name)
+				},
+				createNamespace: func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error) {
+					return ns, nil
+				},
+				getRoleBinding: func(ns, name string) (*rbacv1.RoleBinding, error) {
+					return nil, errors.NewNotFound(schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "rolebindings"}, name)
+				},
+				createRoleBinding: func(ctx context.Context, rb *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
+					created[rb.Name] = rb
+					return rb, nil
+				},
+			}
+
+			require.NoError(t, r.reconcile(context.Background(), sns))
+
+			events, found := created[kuberesources.EventsRoleBindingName]
+			require.True(t, found, "expected events RoleBinding in every scope")
+			require.Equal(t, "cluster-abc-default", events.Namespace)
+			require.Equal(t, kuberesources.EventsClusterRoleName("cluster-abc"), events.RoleRef.Name)
+			require.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Namespace: "cluster-abc", Name: kuberesources.ServiceAccountName}}, events.Subjects)
+
+			_, found = created["kube-binder"]
+			require.Equal(t, scope == kubebindv1alpha1.NamespacedScope, found)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cookie
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/securecookie"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SigningKeyPrefix is the prefix of Secret data keys holding a signing key,
+	// followed by the version, e.g. "signing-key.3".
+	SigningKeyPrefix = "signing-key."
+	// EncryptionKeyPrefix is the prefix of Secret data keys holding an optional
+	// encryption key, followed by the version of the matching signing key.
+	EncryptionKeyPrefix = "encryption-key."
+)
+
+// KeyPair is a versioned pair of keys to sign and optionally encrypt cookies.
+type KeyPair struct {
+	Version       int
+	SigningKey    []byte
+	EncryptionKey []byte
+}
+
+// KeySet signs and encrypts with the KeyPair of the highest version, and accepts
+// values of all its KeyPairs. This allows rotation without invalidating cookies
+// and state of binding sessions in flight. It is safe for concurrent use.
+type KeySet struct {
+	lock     sync.RWMutex
+	versions []int
+	codecs   []securecookie.Codec
+}
+
+// NewKeySet returns a KeySet with the given KeyPairs.
+func NewKeySet(pairs ...KeyPair) (*KeySet, error) {
+	s := &KeySet{}
+	if err := s.Update(pairs...); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update replaces the KeyPairs of the KeySet.
+func (s *KeySet) Update(pairs ...KeyPair) error {
+	pairs = append([]KeyPair(nil), pairs...)
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Version > pairs[j].Version
+	})
+
+	versions := make([]int, 0, len(pairs))
+	codecs := make([]securecookie.Codec, 0, len(pairs))
+	for i, p := range pairs {
+		if len(p.SigningKey) == 0 {
+			return fmt.Errorf("signing key of version %d must not be empty", p.Version)
+		}
+		if i > 0 && pairs[i-1].Version == p.Version {
+			return fmt.Errorf("duplicate key version %d", p.Version)
+		}
+		var encryptionKey []byte
+		switch len(p.EncryptionKey) {
+		case 0:
+		case 16, 24, 32:
+			encryptionKey = p.EncryptionKey
+		default:
+			return fmt.Errorf("encryption key of version %d must be 16, 24 or 32 bytes long", p.Version)
+		}
+		versions = append(versions, p.Version)
+		codecs = append(codecs, securecookie.New(p.SigningKey, encryptionKey))
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.versions = versions
+	s.codecs = codecs
+	return nil
+}
+
+// Versions returns the versions of the KeyPairs, newest first.
+func (s *KeySet) Versions() []int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return append([]int(nil), s.versions...)
+}
+
+// Encode signs and encrypts the value with the newest KeyPair.
+func (s *KeySet) Encode(name string, value interface{}) (string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if len(s.codecs) == 0 {
+		return "", errors.New("no cookie keys configured")
+	}
+	return securecookie.EncodeMulti(name, value, s.codecs[0])
+}
+
+// Decode verifies and decrypts the value with any of the KeyPairs.
+func (s *KeySet) Decode(name, value string, dst interface{}) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if len(s.codecs) == 0 {
+		return errors.New("no cookie keys configured")
+	}
+	return securecookie.DecodeMulti(name, value, dst, s.codecs...)
+}
+
+// KeyPairsFromSecret returns the KeyPairs of a Secret with "signing-key.<version>"
+// and optional "encryption-key.<version>" data keys. Versions must be positive.
+func KeyPairsFromSecret(secret *corev1.Secret) ([]KeyPair, error) {
+	var pairs []KeyPair
+	for key, value := range secret.Data {
+		switch {
+		case strings.HasPrefix(key, SigningKeyPrefix):
+			version, err := strconv.Atoi(strings.TrimPrefix(key, SigningKeyPrefix))
+			if err != nil || version <= 0 {
+				return nil, fmt.Errorf("invalid key %q in secret %s/%s: version must be a positive integer", key, secret.Namespace, secret.Name)
+			}
+			pairs = append(pairs, KeyPair{
+				Version:       version,
+				SigningKey:    value,
+				EncryptionKey: secret.Data[EncryptionKeyPrefix+strconv.Itoa(version)],
+			})
+		case strings.HasPrefix(key, EncryptionKeyPrefix):
+			if _, found := secret.Data[SigningKeyPrefix+strings.TrimPrefix(key, EncryptionKeyPrefix)]; !found {
+				return nil, fmt.Errorf("encryption key %q in secret %s/%s without signing key", key, secret.Namespace, secret.Name)
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Version > pairs[j].Version
+	})
+	return pairs, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cookie
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestKeySetRotation(t *testing.T) {
+	v1 := KeyPair{Version: 1, SigningKey: []byte("0123456789abcdef0123456789abcdef"), EncryptionKey: []byte("0123456789abcdef")}
+	v2 := KeyPair{Version: 2, SigningKey: []byte("fedcba9876543210fedcba9876543210")}
+
+	s, err := NewKeySet(v1)
+	require.NoError(t, err)
+	old, err := s.Encode("session", "value")
+	require.NoError(t, err)
+
+	// rotate: sign with v2, still accept v1
+	require.NoError(t, s.Update(v1, v2))
+	require.Equal(t, []int{2, 1}, s.Versions())
+	var got string
+	require.NoError(t, s.Decode("session", old, &got))
+	require.Equal(t, "value", got)
+	current, err := s.Encode("session", "other")
+	require.NoError(t, err)
+
+	// drop v1: old values are rejected, new ones accepted
+	require.NoError(t, s.Update(v2))
+	require.Error(t, s.Decode("session", old, &got))
+	require.NoError(t, s.Decode("session", current, &got))
+	require.Equal(t, "other", got)
+
+	// the name is part of the signature
+	require.Error(t, s.Decode("state", current, &got))
+
+	require.Error(t, s.Update(v1, v1), "duplicate version")
+	require.Error(t, s.Update(KeyPair{Version: 3, SigningKey: v1.SigningKey, EncryptionKey: []byte("short")}), "invalid encryption key")
+}
+
+func TestKeyPairsFromSecret(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string][]byte
+		versions []int
+		wantErr  bool
+	}{
+		{name: "empty"},
+		{
+			name:     "versions newest first",
+			data:     map[string][]byte{"signing-key.1": []byte("a"), "signing-key.10": []byte("b"), "encryption-key.10": []byte("c"), "other": []byte("d")},
+			versions: []int{10, 1},
+		},
+		{name: "invalid version", data: map[string][]byte{"signing-key.x": []byte("a")}, wantErr: true},
+		{name: "zero version", data: map[string][]byte{"signing-key.0": []byte("a")}, wantErr: true},
+		{name: "encryption key without signing key", data: map[string][]byte{"encryption-key.1": []byte("a")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pairs, err := KeyPairsFromSecret(&corev1.Secret{Data: tt.data})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var versions []int
+			for _, p := range pairs {
+				versions = append(versions, p.Version)
+				require.Equal(t, tt.data[SigningKeyPrefix+strconv.Itoa(p.Version)], p.SigningKey)
+			}
+			require.Equal(t, tt.versions, versions)
+		})
+	}
+}
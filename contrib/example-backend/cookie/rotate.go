@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cookie
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// GenerateKeys returns a random 32 byte signing and encryption key.
+func GenerateKeys() (signingKey, encryptionKey []byte, err error) {
+	signingKey = make([]byte, 32)
+	if _, err := rand.Read(signingKey); err != nil {
+		return nil, nil, err
+	}
+	encryptionKey = make([]byte, 32)
+	if _, err := rand.Read(encryptionKey); err != nil {
+		return nil, nil, err
+	}
+	return signingKey, encryptionKey, nil
+}
+
+// RotateKeysSecret adds a new key version to the cookie keys secret and removes
+// all but the keep newest versions. The secret is created if it does not exist.
+// It returns the new and the removed versions.
+func RotateKeysSecret(ctx context.Context, client kubeclient.Interface, ns, name string, signingKey, encryptionKey []byte, keep int) (int, []int, error) {
+	if keep < 1 {
+		return 0, nil, fmt.Errorf("keep must be at least 1")
+	}
+
+	var version int
+	var pruned []int
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := client.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+		create := apierrors.IsNotFound(err)
+		if err != nil && !create {
+			return err
+		} else if create {
+			secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+		}
+
+		pairs, err := KeyPairsFromSecret(secret)
+		if err != nil {
+			return err
+		}
+		version = 1
+		if len(pairs) > 0 {
+			version = pairs[0].Version + 1
+		}
+		pruned = nil
+		if len(pairs) >= keep {
+			for _, p := range pairs[keep-1:] {
+				pruned = append(pruned, p.Version)
+			}
+		}
+
+		data := map[string][]byte{}
+		for k, v := range secret.Data {
+			data[k] = v
+		}
+		for _, v := range pruned {
+			delete(data, SigningKeyPrefix+strconv.Itoa(v))
+			delete(data, EncryptionKeyPrefix+strconv.Itoa(v))
+		}
+		data[SigningKeyPrefix+strconv.Itoa(version)] = signingKey
+		data[EncryptionKeyPrefix+strconv.Itoa(version)] = encryptionKey
+		secret.Data = data
+
+		if create {
+			_, err = client.CoreV1().Secrets(ns).Create(ctx, secret, metav1.CreateOptions{})
+		} else {
+			_, err = client.CoreV1().Secrets(ns).Update(ctx, secret, metav1.UpdateOptions{})
+		}
+		return err
+	})
+	return version, pruned, err
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cookie
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRotateKeysSecret(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+
+	for i := 1; i <= 3; i++ {
+		version, pruned, err := RotateKeysSecret(ctx, client, "kube-bind", "cookie-keys", []byte("signing"), []byte("encryption"), 2)
+		require.NoError(t, err)
+		require.Equal(t, i, version)
+		if i <= 2 {
+			require.Empty(t, pruned)
+		} else {
+			require.Equal(t, []int{1}, pruned)
+		}
+	}
+
+	secret, err := client.CoreV1().Secrets("kube-bind").Get(ctx, "cookie-keys", metav1.GetOptions{})
+	require.NoError(t, err)
+	pairs, err := KeyPairsFromSecret(secret)
+	require.NoError(t, err)
+	require.Len(t, pairs, 2)
+	require.Equal(t, 3, pairs[0].Version)
+	require.Equal(t, 2, pairs[1].Version)
+}
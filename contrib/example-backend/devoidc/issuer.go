@@ -0,0 +1,232 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package devoidc provides a throwaway in-memory OIDC issuer for development,
+// demos and tests. It logs in every authorization request immediately, without
+// user interaction. It must never be used in production.
+package devoidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// ClientID is the OIDC client ID the issuer is used with.
+	ClientID = "kube-bind"
+	// ClientSecret is the OIDC client secret the issuer is used with.
+	ClientSecret = "kube-bind-secret"
+
+	keyID = "devoidc"
+)
+
+// Issuer is an OIDC issuer that logs in every authorization request immediately
+// and issues RS256 signed ID tokens with the configured claims. The signing key
+// lives in memory only, i.e. tokens become invalid with the process.
+type Issuer struct {
+	// URL is the issuer URL, e.g. http://127.0.0.1:34567.
+	URL string
+
+	key      *rsa.PrivateKey
+	server   *http.Server
+	listener net.Listener
+
+	lock   sync.Mutex
+	claims map[string]interface{}
+	codes  map[string]map[string]interface{}
+}
+
+// Start starts an issuer listening on the given address, e.g. 127.0.0.1:0. When
+// listening on all interfaces, e.g. 0.0.0.0:5556, the issuer URL uses 127.0.0.1.
+// The ID tokens carry the subject "user" with a verified e-mail address by default.
+func Start(addr string) (*Issuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	host := listener.Addr().String()
+	if tcpAddr, ok := listener.Addr().(*net.TCPAddr); ok && tcpAddr.IP.IsUnspecified() {
+		host = net.JoinHostPort("127.0.0.1", strconv.Itoa(tcpAddr.Port))
+	}
+
+	i := &Issuer{
+		URL:      "http://" + host,
+		key:      key,
+		listener: listener,
+		claims: map[string]interface{}{
+			"sub":            "user",
+			"email":          "user@example.com",
+			"email_verified": true,
+		},
+		codes: map[string]map[string]interface{}{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", i.handleDiscovery)
+	mux.HandleFunc("/keys", i.handleKeys)
+	mux.HandleFunc("/auth", i.handleAuth)
+	mux.HandleFunc("/token", i.handleToken)
+	i.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go i.server.Serve(listener) // nolint:errcheck
+
+	return i, nil
+}
+
+// Close stops the issuer.
+func (i *Issuer) Close() {
+	i.server.Close() // nolint:errcheck
+}
+
+// SetClaims sets the claims of the ID tokens issued for future logins. The
+// issuer, audience and timestamps are always set by the issuer.
+func (i *Issuer) SetClaims(claims map[string]interface{}) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.claims = claims
+}
+
+func (i *Issuer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"issuer":                                i.URL,
+		"authorization_endpoint":                i.URL + "/auth",
+		"token_endpoint":                        i.URL + "/token",
+		"jwks_uri":                              i.URL + "/keys",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (i *Issuer) handleKeys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(i.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(i.key.E)).Bytes()),
+		}},
+	})
+}
+
+// handleAuth logs in immediately and redirects back to the client with a code.
+func (i *Issuer) handleAuth(w http.ResponseWriter, r *http.Request) {
+	redirectURL, err := url.Parse(r.URL.Query().Get("redirect_uri"))
+	if err != nil || redirectURL.Host == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	bs := make([]byte, 16)
+	if _, err := rand.Read(bs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	code := hex.EncodeToString(bs)
+
+	i.lock.Lock()
+	claims := map[string]interface{}{}
+	for k, v := range i.claims {
+		claims[k] = v
+	}
+	claims["aud"] = r.URL.Query().Get("client_id")
+	i.codes[code] = claims
+	i.lock.Unlock()
+
+	values := redirectURL.Query()
+	values.Set("code", code)
+	values.Set("state", r.URL.Query().Get("state"))
+	redirectURL.RawQuery = values.Encode()
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+}
+
+func (i *Issuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	code := r.PostForm.Get("code")
+
+	i.lock.Lock()
+	claims, found := i.codes[code]
+	delete(i.codes, code)
+	i.lock.Unlock()
+	if !found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`)) // nolint:errcheck
+		return
+	}
+
+	now := time.Now()
+	claims["iss"] = i.URL
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(time.Hour).Unix()
+	idToken, err := i.sign(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"access_token": "access-" + code,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// sign returns a compact RS256 JWS of the claims.
+func (i *Issuer) sign(claims map[string]interface{}) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": keyID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(obj) // nolint:errcheck
+}
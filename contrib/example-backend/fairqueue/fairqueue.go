@@ -0,0 +1,245 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fairqueue provides a workqueue that hands out items round-robin across
+// tenants, such that one consumer with many items cannot starve the others.
+package fairqueue
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// TenantFunc returns the tenant of a queue item.
+type TenantFunc func(item interface{}) string
+
+// NamespaceTenant returns the namespace of a namespace/name key as tenant. On the
+// service provider side, every consumer has its own namespace. Cluster-scoped keys
+// share the empty tenant.
+func NamespaceTenant(item interface{}) string {
+	key, ok := item.(string)
+	if !ok {
+		return ""
+	}
+	ns, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return ""
+	}
+	return ns
+}
+
+// NewNamedRateLimitingQueue returns a rate limiting queue like
+// workqueue.NewNamedRateLimitingQueue, but serving the tenants fairly.
+func NewNamedRateLimitingQueue(rateLimiter workqueue.RateLimiter, name string, tenantOf TenantFunc) workqueue.RateLimitingInterface {
+	return workqueue.NewRateLimitingQueueWithDelayingInterface(
+		workqueue.NewDelayingQueueWithCustomQueue(New(tenantOf), name),
+		rateLimiter,
+	)
+}
+
+// DefaultControllerRateLimiter is like workqueue.DefaultControllerRateLimiter, but with
+// the overall retry bucket per tenant, such that the retries of one consumer do not
+// delay those of the others.
+func DefaultControllerRateLimiter(tenantOf TenantFunc) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
+		&tenantBucketRateLimiter{tenantOf: tenantOf, buckets: map[string]*rate.Limiter{}},
+	)
+}
+
+// tenantBucketRateLimiter is a workqueue.BucketRateLimiter per tenant, with 10 qps and
+// a bucket size of 100.
+type tenantBucketRateLimiter struct {
+	tenantOf TenantFunc
+
+	lock    sync.Mutex
+	buckets map[string]*rate.Limiter
+}
+
+func (r *tenantBucketRateLimiter) When(item interface{}) time.Duration {
+	tenant := r.tenantOf(item)
+
+	r.lock.Lock()
+	bucket, found := r.buckets[tenant]
+	if !found {
+		bucket = rate.NewLimiter(rate.Limit(10), 100)
+		r.buckets[tenant] = bucket
+	}
+	r.lock.Unlock()
+
+	return bucket.Reserve().Delay()
+}
+
+func (r *tenantBucketRateLimiter) NumRequeues(item interface{}) int {
+	return 0
+}
+
+func (r *tenantBucketRateLimiter) Forget(item interface{}) {
+}
+
+// New returns a queue that serves the tenants with waiting items round-robin, each
+// tenant in FIFO order. Like workqueue.Type, an item is never processed concurrently
+// and items added while being processed are queued again when done.
+func New(tenantOf TenantFunc) workqueue.Interface {
+	return &queue{
+		cond:       sync.NewCond(&sync.Mutex{}),
+		tenantOf:   tenantOf,
+		waiting:    map[string][]interface{}{},
+		dirty:      map[interface{}]struct{}{},
+		processing: map[interface{}]struct{}{},
+	}
+}
+
+type queue struct {
+	cond     *sync.Cond
+	tenantOf TenantFunc
+
+	// tenants with waiting items, in the order they are served next.
+	tenants []string
+	// waiting are the items by tenant.
+	waiting map[string][]interface{}
+	// len is the number of waiting items.
+	len int
+
+	// dirty are the items that need processing.
+	dirty map[interface{}]struct{}
+	// processing are the items currently processed.
+	processing map[interface{}]struct{}
+
+	shuttingDown bool
+	drain        bool
+}
+
+var _ workqueue.Interface = &queue{}
+
+func (q *queue) push(item interface{}) {
+	tenant := q.tenantOf(item)
+	if len(q.waiting[tenant]) == 0 {
+		q.tenants = append(q.tenants, tenant)
+	}
+	q.waiting[tenant] = append(q.waiting[tenant], item)
+	q.len++
+}
+
+// Add marks item as needing processing.
+func (q *queue) Add(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	if q.shuttingDown {
+		return
+	}
+	if _, found := q.dirty[item]; found {
+		return
+	}
+	q.dirty[item] = struct{}{}
+	if _, found := q.processing[item]; found {
+		return
+	}
+	q.push(item)
+	q.cond.Signal()
+}
+
+// Len returns the number of waiting items.
+func (q *queue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.len
+}
+
+// Get blocks until it can return an item to be processed, taking it from the next
+// tenant in turn.
+func (q *queue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	for q.len == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.len == 0 {
+		return nil, true
+	}
+
+	tenant := q.tenants[0]
+	q.tenants = q.tenants[1:]
+	items := q.waiting[tenant]
+	item := items[0]
+	items[0] = nil
+	if items = items[1:]; len(items) > 0 {
+		q.waiting[tenant] = items
+		q.tenants = append(q.tenants, tenant)
+	} else {
+		delete(q.waiting, tenant)
+	}
+	q.len--
+
+	q.processing[item] = struct{}{}
+	delete(q.dirty, item)
+
+	return item, false
+}
+
+// Done marks item as done processing, and if it has been marked as dirty again
+// while it was being processed, it will be re-added to the queue.
+func (q *queue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	delete(q.processing, item)
+	if _, found := q.dirty[item]; found {
+		q.push(item)
+		q.cond.Signal()
+	} else if len(q.processing) == 0 {
+		q.cond.Signal()
+	}
+}
+
+// ShutDown makes Get return with shutdown true once the queue is empty, and ignores
+// further adds.
+func (q *queue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// ShutDownWithDrain is like ShutDown, but waits for items being processed.
+func (q *queue) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+
+	for len(q.processing) != 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+// ShuttingDown returns true after ShutDown.
+func (q *queue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fairqueue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue(t *testing.T) {
+	q := New(NamespaceTenant)
+
+	// a busy consumer adds a lot first
+	for _, key := range []string{"busy/a", "busy/b", "busy/c", "busy/d", "other/a", "third/a", "other/b", "busy/a"} {
+		q.Add(key)
+	}
+	require.Equal(t, 7, q.Len())
+
+	var got []string
+	for q.Len() > 0 {
+		item, shutdown := q.Get()
+		require.False(t, shutdown)
+		got = append(got, item.(string))
+	}
+	require.Equal(t, []string{"busy/a", "other/a", "third/a", "busy/b", "other/b", "busy/c", "busy/d"}, got)
+
+	// re-added while processing comes back when done
+	q.Add("busy/a")
+	require.Equal(t, 0, q.Len())
+	for _, key := range got {
+		q.Done(key)
+	}
+	require.Equal(t, 1, q.Len())
+
+	q.ShutDown()
+	item, shutdown := q.Get()
+	require.False(t, shutdown)
+	require.Equal(t, "busy/a", item)
+	_, shutdown = q.Get()
+	require.True(t, shutdown)
+}
@@ -18,7 +18,9 @@ package http
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,16 +32,17 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/gorilla/securecookie"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	componentbaseversion "k8s.io/component-base/version"
 	"k8s.io/klog/v2"
 
+	"github.com/kube-bind/kube-bind/contrib/example-backend/catalog"
 	"github.com/kube-bind/kube-bind/contrib/example-backend/cookie"
 	"github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes"
 	"github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
@@ -52,6 +55,12 @@ var (
 	resourcesTemplate = htmltemplate.Must(htmltemplate.New("resource").Parse(mustRead(template.Files.ReadFile, "resources.gohtml")))
 )
 
+// stateName is the name the OAuth2 state is signed with.
+const stateName = "kube-bind-state"
+
+// trialIssuer is the issuer of the id tokens of anonymous trial sessions.
+const trialIssuer = "kube-bind.io/trial"
+
 // See https://developers.google.com/web/fundamentals/performance/optimizing-content-efficiency/http-caching?hl=en
 var noCacheHeaders = map[string]string{
 	"Expires":         time.Unix(0, 0).Format(time.RFC1123),
@@ -67,9 +76,11 @@ type handler struct {
 	backendCallbackURL string
 	providerPrettyName string
 	testingAutoSelect  string
+	region             string
+	trial              bool
 
-	cookieEncryptionKey []byte
-	cookieSigningKey    []byte
+	cookieKeys *cookie.KeySet
+	catalog    *catalog.Cache
 
 	client              *http.Client
 	apiextensionsLister apiextensionslisters.CustomResourceDefinitionLister
@@ -78,11 +89,13 @@ type handler struct {
 
 func NewHandler(
 	provider *OIDCServiceProvider,
-	oidcAuthorizeURL, backendCallbackURL, providerPrettyName, testingAutoSelect string,
-	cookieSigningKey, cookieEncryptionKey []byte,
+	oidcAuthorizeURL, backendCallbackURL, providerPrettyName, testingAutoSelect, region string,
+	trial bool,
+	cookieKeys *cookie.KeySet,
 	scope kubebindv1alpha1.Scope,
 	mgr *kubernetes.Manager,
 	apiextensionsLister apiextensionslisters.CustomResourceDefinitionLister,
+	catalog *catalog.Cache,
 ) (*handler, error) {
 	return &handler{
 		oidc:                provider,
@@ -90,21 +103,28 @@ func NewHandler(
 		backendCallbackURL:  backendCallbackURL,
 		providerPrettyName:  providerPrettyName,
 		testingAutoSelect:   testingAutoSelect,
+		region:              region,
+		trial:               trial,
 		scope:               scope,
 		client:              http.DefaultClient,
 		kubeManager:         mgr,
 		apiextensionsLister: apiextensionsLister,
-		cookieSigningKey:    cookieSigningKey,
-		cookieEncryptionKey: cookieEncryptionKey,
+		cookieKeys:          cookieKeys,
+		catalog:             catalog,
 	}, nil
 }
 
 func (h *handler) AddRoutes(mux *mux.Router) {
 	mux.HandleFunc("/export", h.handleServiceExport).Methods("GET")
 	mux.HandleFunc("/resources", h.handleResources).Methods("GET")
+	mux.HandleFunc("/catalog", h.handleCatalog).Methods("GET")
+	mux.HandleFunc("/catalog/{group}/{resource}", h.handleCatalogSchema).Methods("GET")
 	mux.HandleFunc("/bind", h.handleBind).Methods("GET")
 	mux.HandleFunc("/authorize", h.handleAuthorize).Methods("GET")
 	mux.HandleFunc("/callback", h.handleCallback).Methods("GET")
+	mux.HandleFunc("/credentials/rotate", h.handleRotateCredentials).Methods("POST")
+	mux.HandleFunc("/credentials/revoke", h.handleRevokeCredentials).Methods("POST")
+	mux.HandleFunc("/events", h.handleEvents).Methods("GET")
 }
 
 func (h *handler) handleServiceExport(w http.ResponseWriter, r *http.Request) {
@@ -114,6 +134,19 @@ func (h *handler) handleServiceExport(w http.ResponseWriter, r *http.Request) {
 	if oidcAuthorizeURL == "" {
 		oidcAuthorizeURL = fmt.Sprintf("http://%s/authorize", r.Host)
 	}
+	if h.trial && r.URL.Query().Get("trial") == "true" {
+		// kubectl bind keeps the query of the authenticated URL.
+		u, err := url.Parse(oidcAuthorizeURL)
+		if err != nil {
+			logger.Error(err, "failed to parse authorize url")
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		values := u.Query()
+		values.Set("trial", "true")
+		u.RawQuery = values.Encode()
+		oidcAuthorizeURL = u.String()
+	}
 
 	ver, err := bindversion.BinaryVersion(componentbaseversion.Get().GitVersion)
 	if err != nil {
@@ -138,6 +171,10 @@ func (h *handler) handleServiceExport(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	if h.region != "" {
+		provider.Regions = []string{h.region}
+	}
+
 	bs, err := json.Marshal(provider)
 	if err != nil {
 		logger.Error(err, "failed to marshal provider")
@@ -175,18 +212,68 @@ func (h *handler) handleAuthorize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dataCode, err := json.Marshal(code)
-	if err != nil {
-		logger.Info("failed to marshal auth code", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if r.URL.Query().Get("trial") == "true" {
+		if !h.trial {
+			http.Error(w, "trial bindings are not enabled", http.StatusBadRequest)
+			return
+		}
+		h.authorizeTrial(w, r, code)
 		return
 	}
 
-	encoded := base64.StdEncoding.EncodeToString(dataCode)
+	// sign the state to verify that it is not faked by the oauth provider
+	encoded, err := h.cookieKeys.Encode(stateName, code)
+	if err != nil {
+		logger.Info("failed to encode auth code", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
 	authURL := h.oidc.OIDCProviderConfig(scopes).AuthCodeURL(encoded)
 	http.Redirect(w, r, authURL, http.StatusFound)
 }
 
+// authorizeTrial starts an anonymous session for a trial binding, without OIDC login.
+// It is only used if the consumer explicitly asks for a trial binding.
+func (h *handler) authorizeTrial(w http.ResponseWriter, r *http.Request, code *AuthCode) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		logger.Info("failed to generate trial identity", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	idToken, err := json.Marshal(map[string]string{
+		"iss": trialIssuer,
+		"sub": "trial-" + hex.EncodeToString(id),
+	})
+	if err != nil {
+		logger.Info("failed to marshal trial id token", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	sessionCookie := cookie.SessionState{
+		CreatedAt:   time.Now(),
+		ExpiresOn:   time.Now().Add(time.Hour),
+		IDToken:     string(idToken),
+		RedirectURL: code.RedirectURL,
+		SessionID:   code.SessionID,
+		ClusterID:   code.ClusterID,
+	}
+
+	cookieName := "kube-bind-" + code.SessionID
+	encoded, err := h.cookieKeys.Encode(cookieName, sessionCookie)
+	if err != nil {
+		logger.Info("failed to encode secure session cookie", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, cookie.MakeCookie(r, cookieName, encoded, time.Duration(1)*time.Hour))
+	http.Redirect(w, r, "/resources?s="+code.SessionID, http.StatusFound)
+}
+
 func parseJWT(p string) ([]byte, error) {
 	parts := strings.Split(p, ".")
 	if len(parts) < 2 {
@@ -222,21 +309,13 @@ func (h *handler) handleCallback(w http.ResponseWriter, r *http.Request) {
 	if state == "" {
 		state = r.URL.Query().Get("state")
 	}
-	decoded, err := base64.StdEncoding.DecodeString(state)
-	if err != nil {
-		logger.Info("failed to decode state", "error", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
 	authCode := &AuthCode{}
-	if err := json.Unmarshal(decoded, authCode); err != nil {
-		logger.Info("faile to unmarshal authCode", "error", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := h.cookieKeys.Decode(stateName, state, authCode); err != nil {
+		logger.Info("failed to decode state", "error", err)
+		http.Error(w, "invalid state", http.StatusBadRequest)
 		return
 	}
 
-	// TODO: sign state and verify that it is not faked by the oauth provider
-
 	token, err := h.oidc.OIDCProviderConfig(nil).Exchange(r.Context(), code)
 	if err != nil {
 		logger.Info("failed to exchange token", "error", err)
@@ -274,8 +353,7 @@ func (h *handler) handleCallback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	cookieName := "kube-bind-" + authCode.SessionID
-	s := securecookie.New(h.cookieSigningKey, h.cookieEncryptionKey)
-	encoded, err := s.Encode(cookieName, sessionCookie)
+	encoded, err := h.cookieKeys.Encode(cookieName, sessionCookie)
 	if err != nil {
 		logger.Info("failed to encode secure session cookie", "error", err)
 		http.Error(w, "internal error", http.StatusInternalServerError)
@@ -347,8 +425,7 @@ func (h *handler) handleBind(w http.ResponseWriter, r *http.Request) {
 	}
 
 	state := cookie.SessionState{}
-	s := securecookie.New(h.cookieSigningKey, h.cookieEncryptionKey)
-	if err := s.Decode(cookieName, ck.Value, &state); err != nil {
+	if err := h.cookieKeys.Decode(cookieName, ck.Value, &state); err != nil {
 		logger.Error(err, "failed to decode session cookie")
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
@@ -366,8 +443,40 @@ func (h *handler) handleBind(w http.ResponseWriter, r *http.Request) {
 
 	group := r.URL.Query().Get("group")
 	resource := r.URL.Query().Get("resource")
-	kfg, err := h.kubeManager.HandleResources(r.Context(), idToken.Subject+"#"+state.ClusterID, resource, group)
+
+	// guard exports requiring stronger identity assurance, e.g. a verified e-mail.
+	crd, err := h.apiextensionsLister.Get(resource + "." + group)
+	if err != nil {
+		logger.Error(err, "failed to get crd")
+		http.Error(w, "unknown resource", http.StatusNotFound)
+		return
+	}
+	required, err := resources.RequiredClaims(crd)
 	if err != nil {
+		logger.Error(err, "failed to get required claims")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal([]byte(state.IDToken), &claims); err != nil {
+		logger.Error(err, "failed to unmarshal id token claims")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if unmet := resources.UnmetClaims(required, claims); len(unmet) > 0 {
+		logger.Info("identity verification failed", "subject", idToken.Subject, "unmetClaims", unmet)
+		http.Error(w, fmt.Sprintf("identity verification required to bind %s.%s: the identity provider did not assert %s", resource, group, strings.Join(unmet, ", ")), http.StatusForbidden)
+		return
+	}
+
+	trial := h.trial && idToken.Issuer == trialIssuer
+	kfg, err := h.kubeManager.HandleResources(r.Context(), idToken.Subject+"#"+state.ClusterID, resource, group, crd.Annotations[resources.CredentialIssuerAnnotation], trial)
+	if errors.Is(err, kubernetes.ErrTrialCapacityExhausted) {
+		logger.Info("refusing trial binding", "error", err)
+		w.Header().Set("Retry-After", "3600")
+		http.Error(w, "no trial bindings available, try again later", http.StatusServiceUnavailable)
+		return
+	} else if err != nil {
 		logger.Error(err, "failed to handle resources")
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
@@ -437,6 +546,149 @@ func (h *handler) handleBind(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, parsedAuthURL.String(), http.StatusFound)
 }
 
+// handleRotateCredentials issues new credentials to a consumer authenticated with
+// its current service provider kubeconfig token.
+func (h *handler) handleRotateCredentials(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	kfg, err := h.kubeManager.RotateCredentials(r.Context(), bearerToken(r))
+	if errors.Is(err, kubernetes.ErrUnauthorized) {
+		logger.Info("failed to authenticate consumer", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		logger.Error(err, "failed to rotate credentials")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(kfg) // nolint:errcheck
+}
+
+// handleRevokeCredentials revokes all credentials of a consumer but the one
+// it authenticates with.
+func (h *handler) handleRevokeCredentials(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	revoked, err := h.kubeManager.RevokeCredentials(r.Context(), bearerToken(r))
+	if errors.Is(err, kubernetes.ErrUnauthorized) {
+		logger.Info("failed to authenticate consumer", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		logger.Error(err, "failed to revoke credentials")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	bs, err := json.Marshal(struct {
+		Revoked []string `json:"revoked"`
+	}{Revoked: revoked})
+	if err != nil {
+		logger.Error(err, "failed to marshal response")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs) // nolint:errcheck
+}
+
+// handleEvents returns the Events about objects of an APIServiceExport in the
+// namespaces of a consumer authenticated with its service provider kubeconfig token.
+func (h *handler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	query := r.URL.Query()
+	export := query.Get("export")
+	if export == "" {
+		http.Error(w, "missing export", http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.kubeManager.ConsumerEvents(r.Context(), bearerToken(r), export, query.Get("namespace"), query.Get("name"))
+	if errors.Is(err, kubernetes.ErrUnauthorized) {
+		logger.Info("failed to authenticate consumer", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	} else if errors.Is(err, kubernetes.ErrForbidden) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	} else if apierrors.IsNotFound(err) {
+		http.Error(w, "export not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		logger.Error(err, "failed to list events")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	bs, err := json.Marshal(events)
+	if err != nil {
+		logger.Error(err, "failed to marshal response")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs) // nolint:errcheck
+}
+
+func (h *handler) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	e, err := h.catalog.Catalog()
+	if err != nil {
+		logger.Error(err, "failed to get catalog")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	writeCatalogEntry(w, r, e)
+}
+
+func (h *handler) handleCatalogSchema(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	vars := mux.Vars(r)
+	e, err := h.catalog.Schema(vars["group"], vars["resource"])
+	if errors.Is(err, catalog.ErrNotFound) {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		logger.Error(err, "failed to get schema")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	writeCatalogEntry(w, r, e)
+}
+
+// writeCatalogEntry writes the entry, or 304 Not Modified if the client already
+// has it. Clients must revalidate on every use, but do not transfer anything
+// while the entry is unchanged.
+func writeCatalogEntry(w http.ResponseWriter, r *http.Request, e *catalog.Entry) {
+	w.Header().Set("ETag", e.ETag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if e.Matches(r.Header.Get("If-None-Match")) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(e.Data) // nolint:errcheck
+}
+
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+}
+
 func mustRead(f func(name string) ([]byte, error), name string) string {
 	bs, err := f(name)
 	if err != nil {
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+)
+
+// ErrUnauthorized is returned if the credentials of a consumer cannot be authenticated.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// RotateCredentials issues a new kubeconfig for the consumer authenticated by the
+// given service account token. The old credentials stay valid until they are revoked
+// with RevokeCredentials.
+func (m *Manager) RotateCredentials(ctx context.Context, token string) ([]byte, error) {
+	nsObj, err := m.authenticateConsumer(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	ns := nsObj.Name
+	logger := klog.FromContext(ctx).WithValues("namespace", ns)
+	ctx = klog.NewContext(ctx, logger)
+
+	issuer, err := m.namespaceIssuer(nsObj)
+	if err != nil {
+		return nil, err
+	}
+
+	cb, err := m.bindClient.KubeBindV1alpha1().ClusterBindings(ns).Get(ctx, kuberesources.ClusterBindingName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	token, expiration, err := m.issueToken(ctx, issuer, ns, kuberesources.ServiceAccountName, true)
+	if err != nil {
+		return nil, err
+	}
+	kfgSecret, err := kuberesources.GenerateKubeconfig(ctx, m.kubeClient, m.clusterConfig, issuer.ExternalAddress, issuer.ExternalCA, issuer.ExternalTLSServerName, issuer.ExternalFallbacks, token, ns, cb.Spec.KubeconfigSecretRef.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.updateCredentialsExpiration(ctx, ns, expiration); err != nil {
+		return nil, err
+	}
+	logger.Info("Rotated credentials")
+
+	return kfgSecret.Data["kubeconfig"], nil
+}
+
+// RevokeCredentials deletes all service account token secrets of the consumer
+// authenticated by the given token, except the one of the token itself. Tokens
+// with a lifetime cannot be revoked individually, they expire.
+func (m *Manager) RevokeCredentials(ctx context.Context, token string) ([]string, error) {
+	nsObj, err := m.authenticateConsumer(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	ns := nsObj.Name
+	logger := klog.FromContext(ctx).WithValues("namespace", ns)
+
+	issuer, err := m.namespaceIssuer(nsObj)
+	if err != nil {
+		return nil, err
+	}
+
+	if issuer.TokenOptions.Lifetime > 0 {
+		// the token is bound, not backed by a secret. Remove secrets from before.
+		revoked, _, err := kuberesources.RevokeSASecrets(ctx, m.kubeClient, ns, kuberesources.ServiceAccountName, "")
+		if err != nil {
+			return revoked, err
+		}
+		logger.Info("Revoked credentials", "secrets", revoked)
+		return revoked, nil
+	}
+
+	revoked, found, err := kuberesources.RevokeSASecrets(ctx, m.kubeClient, ns, kuberesources.ServiceAccountName, token)
+	if err != nil {
+		return revoked, err
+	} else if !found {
+		// only tokens backed by a secret can be told apart
+		return nil, fmt.Errorf("%w: token is not backed by a service account token secret", ErrUnauthorized)
+	}
+	logger.Info("Revoked credentials", "secrets", revoked)
+
+	return revoked, nil
+}
+
+// issueToken returns a token for the given service account, either a bound token
+// with the lifetime of the issuer and its expiration time, or the non-expiring
+// token of a service account token secret. With rotate, a new secret is created.
+func (m *Manager) issueToken(ctx context.Context, issuer *CredentialIssuer, ns, saName string, rotate bool) (string, *metav1.Time, error) {
+	if issuer.TokenOptions.Lifetime > 0 {
+		token, expiration, err := kuberesources.RequestSAToken(ctx, m.kubeClient, ns, saName, issuer.TokenOptions)
+		if err != nil {
+			return "", nil, err
+		}
+		return token, &expiration, nil
+	}
+
+	var saSecret *corev1.Secret
+	var err error
+	if rotate {
+		saSecret, err = kuberesources.CreateRotatedSASecret(ctx, m.kubeClient, ns, saName)
+	} else {
+		saSecret, err = kuberesources.CreateSASecret(ctx, m.kubeClient, ns, saName)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	token, err := kuberesources.WaitForSASecretToken(ctx, m.kubeClient, ns, saSecret.Name)
+	return token, nil, err
+}
+
+// updateCredentialsExpiration reflects the expiration time of the credentials
+// on the ClusterBinding of the namespace.
+func (m *Manager) updateCredentialsExpiration(ctx context.Context, ns string, expiration *metav1.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cb, err := m.bindClient.KubeBindV1alpha1().ClusterBindings(ns).Get(ctx, kuberesources.ClusterBindingName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(cb.Status.CredentialsExpirationTime, expiration) {
+			return nil
+		}
+		cb.Status.CredentialsExpirationTime = expiration
+		_, err = m.bindClient.KubeBindV1alpha1().ClusterBindings(ns).UpdateStatus(ctx, cb, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// authenticateConsumer authenticates the token and returns the service provider
+// namespace of the consumer owning it.
+func (m *Manager) authenticateConsumer(ctx context.Context, token string) (*corev1.Namespace, error) {
+	if token == "" {
+		return nil, ErrUnauthorized
+	}
+	review, err := m.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if !review.Status.Authenticated {
+		return nil, ErrUnauthorized
+	}
+	ns, name, err := serviceaccount.SplitUsername(review.Status.User.Username)
+	if err != nil || name != kuberesources.ServiceAccountName {
+		return nil, fmt.Errorf("%w: %s is not a consumer", ErrUnauthorized, review.Status.User.Username)
+	}
+	nsObj, err := m.namespaceLister.Get(ns)
+	if err != nil {
+		return nil, err
+	}
+	if _, found := nsObj.Annotations[kuberesources.IdentityAnnotationKey]; !found {
+		return nil, fmt.Errorf("%w: namespace %s does not belong to a consumer", ErrUnauthorized, ns)
+	}
+	return nsObj, nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+)
+
+func newTokenSecret(ns, name, saName, token string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   ns,
+			Name:        name,
+			Annotations: map[string]string{resources.ServiceAccountTokenAnnotation: saName},
+		},
+		Type: resources.ServiceAccountTokenType,
+		Data: map[string][]byte{"token": []byte(token)},
+	}
+}
+
+func TestAuthenticateConsumer(t *testing.T) {
+	tokens := map[string]string{
+		"consumer":    "system:serviceaccount:cluster-a:kube-binder",
+		"other-sa":    "system:serviceaccount:cluster-a:default",
+		"user":        "alice",
+		"no-identity": "system:serviceaccount:kube-system:kube-binder",
+		"missing-ns":  "system:serviceaccount:cluster-gone:kube-binder",
+	}
+	kubeObjects := []runtime.Object{
+		newConsumerNamespace("cluster-a"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	}
+
+	tests := []struct {
+		name             string
+		token            string
+		wantNamespace    string
+		wantUnauthorized bool
+	}{
+		{name: "consumer", token: "consumer", wantNamespace: "cluster-a"},
+		{name: "empty token", token: "", wantUnauthorized: true},
+		{name: "failed token review", token: "invalid", wantUnauthorized: true},
+		{name: "other service account", token: "other-sa", wantUnauthorized: true},
+		{name: "not a service account", token: "user", wantUnauthorized: true},
+		{name: "namespace without identity", token: "no-identity", wantUnauthorized: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t, tokens, kubeObjects, nil)
+
+			ns, err := m.authenticateConsumer(context.Background(), tt.token)
+			if tt.wantUnauthorized {
+				require.True(t, errors.Is(err, ErrUnauthorized), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantNamespace, ns.Name)
+		})
+	}
+
+	t.Run("rotate rejects unauthorized consumers", func(t *testing.T) {
+		m, _ := newTestManager(t, tokens, kubeObjects, nil)
+		_, err := m.RotateCredentials(context.Background(), "no-identity")
+		require.True(t, errors.Is(err, ErrUnauthorized), "unexpected error: %v", err)
+	})
+
+	t.Run("missing namespace is no consumer", func(t *testing.T) {
+		m, _ := newTestManager(t, tokens, kubeObjects, nil)
+		_, err := m.authenticateConsumer(context.Background(), "missing-ns")
+		require.Error(t, err)
+	})
+}
+
+func TestRevokeCredentials(t *testing.T) {
+	tokens := map[string]string{
+		"current":  "system:serviceaccount:cluster-a:kube-binder",
+		"old":      "system:serviceaccount:cluster-a:kube-binder",
+		"unbacked": "system:serviceaccount:cluster-a:kube-binder",
+		"other":    "system:serviceaccount:cluster-b:kube-binder",
+	}
+	newObjects := func() []runtime.Object {
+		return []runtime.Object{
+			newConsumerNamespace("cluster-a"),
+			newConsumerNamespace("cluster-b"),
+			newTokenSecret("cluster-a", "kube-binder", "kube-binder", "old"),
+			newTokenSecret("cluster-a", "kube-binder-abcde", "kube-binder", "current"),
+			newTokenSecret("cluster-a", "default-token", "default", "default"),
+			newTokenSecret("cluster-b", "kube-binder", "kube-binder", "other"),
+		}
+	}
+	secretNames := func(t *testing.T, m *Manager, ns string) []string {
+		secrets, err := m.kubeClient.CoreV1().Secrets(ns).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		var names []string
+		for _, s := range secrets.Items {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+
+	t.Run("keeps the caller's secret", func(t *testing.T) {
+		m, _ := newTestManager(t, tokens, newObjects(), nil)
+
+		revoked, err := m.RevokeCredentials(context.Background(), "current")
+		require.NoError(t, err)
+		require.Equal(t, []string{"kube-binder"}, revoked)
+		require.ElementsMatch(t, []string{"kube-binder-abcde", "default-token"}, secretNames(t, m, "cluster-a"))
+		require.ElementsMatch(t, []string{"kube-binder"}, secretNames(t, m, "cluster-b"))
+	})
+
+	t.Run("token not backed by a secret", func(t *testing.T) {
+		m, _ := newTestManager(t, tokens, newObjects(), nil)
+
+		_, err := m.RevokeCredentials(context.Background(), "unbacked")
+		require.True(t, errors.Is(err, ErrUnauthorized), "unexpected error: %v", err)
+		require.ElementsMatch(t, []string{"kube-binder", "kube-binder-abcde", "default-token"}, secretNames(t, m, "cluster-a"))
+	})
+
+	t.Run("bound tokens revoke all secrets", func(t *testing.T) {
+		m, _ := newTestManager(t, tokens, newObjects(), nil)
+		m.defaultIssuer.TokenOptions.Lifetime = time.Hour
+
+		revoked, err := m.RevokeCredentials(context.Background(), "unbacked")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"kube-binder", "kube-binder-abcde"}, revoked)
+		require.ElementsMatch(t, []string{"default-token"}, secretNames(t, m, "cluster-a"))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		m, _ := newTestManager(t, tokens, newObjects(), nil)
+
+		_, err := m.RevokeCredentials(context.Background(), "invalid")
+		require.True(t, errors.Is(err, ErrUnauthorized), "unexpected error: %v", err)
+		require.Len(t, secretNames(t, m, "cluster-a"), 3)
+	})
+}
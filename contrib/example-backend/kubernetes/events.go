@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrForbidden is returned if an authenticated consumer is not allowed to access
+// the requested data.
+var ErrForbidden = errors.New("forbidden")
+
+// ConsumerEvents returns the Events about objects of the given APIServiceExport in
+// the service namespaces of the consumer authenticated by the given token. The
+// namespaces of the Events and their involved objects are translated to consumer
+// namespaces, empty for cluster-scoped objects. objectNamespace and objectName
+// optionally restrict the Events to one consumer object.
+func (m *Manager) ConsumerEvents(ctx context.Context, token, exportName, objectNamespace, objectName string) (*corev1.EventList, error) {
+	nsObj, err := m.authenticateConsumer(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	ns := nsObj.Name
+
+	export, err := m.exportLister.APIServiceExports(ns).Get(exportName)
+	if err != nil {
+		return nil, err
+	}
+	if !export.Spec.EventsAccess {
+		return nil, fmt.Errorf("%w: the service provider does not give access to events of %s", ErrForbidden, exportName)
+	}
+
+	// map provider namespaces to consumer namespaces
+	upstreamNamespaces := map[string]string{}
+	if export.Spec.Scope == apiextensionsv1.ClusterScoped || objectNamespace == "" {
+		upstreamNamespaces[ns] = ""
+	}
+	sns, err := m.bindClient.KubeBindV1alpha1().APIServiceNamespaces(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, sn := range sns.Items {
+		if sn.Status.Namespace == "" || (objectNamespace != "" && sn.Name != objectNamespace) {
+			continue
+		}
+		upstreamNamespaces[sn.Status.Namespace] = sn.Name
+	}
+
+	events := &corev1.EventList{}
+	for upstreamNamespace, consumerNamespace := range upstreamNamespaces {
+		list, err := m.kubeClient.CoreV1().Events(upstreamNamespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range list.Items {
+			if e.InvolvedObject.Kind != export.Spec.Names.Kind {
+				continue
+			}
+			if objectName != "" && e.InvolvedObject.Name != objectName {
+				continue
+			}
+			e.Namespace = consumerNamespace
+			e.InvolvedObject.Namespace = consumerNamespace
+			e.InvolvedObject.UID = ""
+			events.Items = append(events.Items, e)
+		}
+	}
+
+	return events, nil
+}
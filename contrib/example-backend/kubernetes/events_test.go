@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindfake "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned/fake"
+	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+)
+
+// newTestManager returns a Manager on fake clients. tokens maps bearer tokens to
+// the usernames the TokenReview authenticates them as.
+func newTestManager(t *testing.T, tokens map[string]string, kubeObjects []runtime.Object, bindObjects []runtime.Object) (*Manager, *kubefake.Clientset) {
+	t.Helper()
+
+	kubeClient := kubefake.NewSimpleClientset(kubeObjects...)
+	kubeClient.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview).DeepCopy()
+		if username, found := tokens[review.Spec.Token]; found {
+			review.Status.Authenticated = true
+			review.Status.User.Username = username
+		}
+		return true, review, nil
+	})
+
+	namespaceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	exportIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range kubeObjects {
+		if ns, ok := obj.(*corev1.Namespace); ok {
+			require.NoError(t, namespaceIndexer.Add(ns))
+		}
+	}
+	for _, obj := range bindObjects {
+		if export, ok := obj.(*kubebindv1alpha1.APIServiceExport); ok {
+			require.NoError(t, exportIndexer.Add(export))
+		}
+	}
+
+	return &Manager{
+		kubeClient:       kubeClient,
+		bindClient:       bindfake.NewSimpleClientset(bindObjects...),
+		namespaceLister:  corev1listers.NewNamespaceLister(namespaceIndexer),
+		namespaceIndexer: namespaceIndexer,
+		exportLister:     bindlisters.NewAPIServiceExportLister(exportIndexer),
+		exportIndexer:    exportIndexer,
+	}, kubeClient
+}
+
+func newConsumerNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Annotations: map[string]string{resources.IdentityAnnotationKey: "alice#cluster-1"},
+	}}
+}
+
+func TestConsumerEvents(t *testing.T) {
+	newEvent := func(ns, name, kind, object string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: ns, Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Namespace: ns, Name: object, UID: "uid"},
+			Reason:         "Provisioned",
+		}
+	}
+	newExport := func(ns string, eventsAccess bool) *kubebindv1alpha1.APIServiceExport {
+		export := &kubebindv1alpha1.APIServiceExport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "foos.example.com"},
+		}
+		export.Spec.Names.Kind = "Foo"
+		export.Spec.Scope = apiextensionsv1.NamespaceScoped
+		export.Spec.EventsAccess = eventsAccess
+		return export
+	}
+	newServiceNamespace := func(ns, name string) *kubebindv1alpha1.APIServiceNamespace {
+		return &kubebindv1alpha1.APIServiceNamespace{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Status:     kubebindv1alpha1.APIServiceNamespaceStatus{Namespace: ns + "-" + name},
+		}
+	}
+	kubeObjects := []runtime.Object{
+		newConsumerNamespace("cluster-a"),
+		newConsumerNamespace("cluster-b"),
+		newEvent("cluster-a-default", "e1", "Foo", "one"),
+		newEvent("cluster-a-default", "e2", "Bar", "one"),
+		newEvent("cluster-a-prod", "e3", "Foo", "two"),
+		newEvent("cluster-b-default", "e4", "Foo", "three"),
+	}
+	tokens := map[string]string{
+		"token-a":     "system:serviceaccount:cluster-a:kube-binder",
+		"token-b":     "system:serviceaccount:cluster-b:kube-binder",
+		"token-other": "system:serviceaccount:cluster-a:default",
+	}
+
+	tests := []struct {
+		name            string
+		token           string
+		bindObjects     []runtime.Object
+		objectNamespace string
+		objectName      string
+		wantObjects     []string
+		wantErr         func(error) bool
+	}{
+		{
+			name:        "all events of the consumer",
+			token:       "token-a",
+			bindObjects: []runtime.Object{newExport("cluster-a", true), newServiceNamespace("cluster-a", "default"), newServiceNamespace("cluster-a", "prod")},
+			wantObjects: []string{"default/one", "prod/two"},
+		},
+		{
+			name:            "restricted to one object",
+			token:           "token-a",
+			bindObjects:     []runtime.Object{newExport("cluster-a", true), newServiceNamespace("cluster-a", "default"), newServiceNamespace("cluster-a", "prod")},
+			objectNamespace: "prod",
+			objectName:      "two",
+			wantObjects:     []string{"prod/two"},
+		},
+		{
+			name:        "events of other consumers are not visible",
+			token:       "token-b",
+			bindObjects: []runtime.Object{newExport("cluster-b", true), newServiceNamespace("cluster-b", "default"), newServiceNamespace("cluster-a", "default")},
+			wantObjects: []string{"default/three"},
+		},
+		{
+			name:        "no events access",
+			token:       "token-a",
+			bindObjects: []runtime.Object{newExport("cluster-a", false)},
+			wantErr:     func(err error) bool { return errors.Is(err, ErrForbidden) },
+		},
+		{
+			name:        "export of another consumer",
+			token:       "token-b",
+			bindObjects: []runtime.Object{newExport("cluster-a", true)},
+			wantErr:     apierrors.IsNotFound,
+		},
+		{
+			name:        "not a consumer service account",
+			token:       "token-other",
+			bindObjects: []runtime.Object{newExport("cluster-a", true)},
+			wantErr:     func(err error) bool { return errors.Is(err, ErrUnauthorized) },
+		},
+		{
+			name:        "unauthenticated",
+			token:       "invalid",
+			bindObjects: []runtime.Object{newExport("cluster-a", true)},
+			wantErr:     func(err error) bool { return errors.Is(err, ErrUnauthorized) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t, tokens, kubeObjects, tt.bindObjects)
+
+			events, err := m.ConsumerEvents(context.Background(), tt.token, "foos.example.com", tt.objectNamespace, tt.objectName)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				require.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+
+			var objects []string
+			for _, e := range events.Items {
+				require.Equal(t, e.Namespace, e.InvolvedObject.Namespace)
+				require.Empty(t, e.InvolvedObject.UID)
+				objects = append(objects, e.InvolvedObject.Namespace+"/"+e.InvolvedObject.Name)
+			}
+			require.ElementsMatch(t, tt.wantObjects, objects)
+		})
+	}
+}
@@ -33,8 +33,17 @@ func IndexNamespacesByIdentity(obj interface{}) ([]string, error) {
 	}
 
 	if id, found := ns.Annotations[resources.IdentityAnnotationKey]; found {
-		return []string{id}, nil
+		return []string{NamespaceIdentityKey(id, ns.Annotations[resources.CredentialIssuerAnnotationKey])}, nil
 	}
 
 	return nil, nil
 }
+
+// NamespaceIdentityKey is the NamespacesByIdentity index key of the service
+// provider namespace of a consumer identity and credential issuer.
+func NamespaceIdentityKey(identity, issuer string) string {
+	if issuer == "" {
+		return identity
+	}
+	return identity + "@" + issuer
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+)
+
+func TestIndexNamespacesByIdentity(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []string
+	}{
+		{name: "no consumer"},
+		{
+			name:        "default issuer",
+			annotations: map[string]string{resources.IdentityAnnotationKey: "alice#cluster-1"},
+			want:        []string{"alice#cluster-1"},
+		},
+		{
+			name: "named issuer",
+			annotations: map[string]string{
+				resources.IdentityAnnotationKey:         "alice#cluster-1",
+				resources.CredentialIssuerAnnotationKey: "restricted",
+			},
+			want: []string{"alice#cluster-1@restricted"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IndexNamespacesByIdentity(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster-abc", Annotations: tt.annotations}})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+)
+
+// CredentialIssuer issues the kubeconfigs of konnectors. The default issuer is
+// configured with the backend flags, named issuers are selected by exported
+// CRDs with the kube-bind.io/credential-issuer annotation.
+//
+// Issuers separate the consumers of different exports into their own service
+// provider namespaces, and differ in how konnectors reach the service provider
+// cluster and in the token lifetime and audiences. All issuers share the
+// backend's client though: the service accounts and their tokens are created
+// in the same service provider cluster and signed by its service account
+// signer. Exports with different trust requirements across clusters or
+// signers need separate backends.
+type CredentialIssuer struct {
+	// NamespacePrefix is the prefix of the service provider namespaces holding
+	// the service accounts of the issued credentials.
+	NamespacePrefix       string
+	ExternalAddress       string
+	ExternalCA            []byte
+	ExternalTLSServerName string
+	// ExternalFallbacks are further endpoints konnectors fail over to.
+	ExternalFallbacks []kuberesources.Endpoint
+	TokenOptions      kuberesources.TokenOptions
+}
+
+// issuer returns the named credential issuer, or the default one for the
+// empty name.
+func (m *Manager) issuer(name string) (*CredentialIssuer, error) {
+	if name == "" {
+		return &m.defaultIssuer, nil
+	}
+	issuer, found := m.issuers[name]
+	if !found {
+		return nil, fmt.Errorf("unknown credential issuer %q", name)
+	}
+	return &issuer, nil
+}
+
+// namespaceIssuer returns the credential issuer of the service provider namespace.
+func (m *Manager) namespaceIssuer(ns *corev1.Namespace) (*CredentialIssuer, error) {
+	return m.issuer(ns.Annotations[kuberesources.CredentialIssuerAnnotationKey])
+}
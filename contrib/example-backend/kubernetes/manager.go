@@ -19,6 +19,7 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -38,13 +39,14 @@ import (
 )
 
 type Manager struct {
-	namespacePrefix    string
 	providerPrettyName string
 
-	clusterConfig         *rest.Config
-	externalAddress       string
-	externalCA            []byte
-	externalTLSServerName string
+	clusterConfig *rest.Config
+	defaultIssuer CredentialIssuer
+	issuers       map[string]CredentialIssuer
+	trialDuration time.Duration
+	// trialMaxBindings caps the number of trial consumers, zero for unlimited.
+	trialMaxBindings int
 
 	kubeClient kubeclient.Interface
 	bindClient bindclient.Interface
@@ -57,11 +59,12 @@ type Manager struct {
 }
 
 func NewKubernetesManager(
-	namespacePrefix, providerPrettyName string,
+	providerPrettyName string,
 	config *rest.Config,
-	externalAddress string,
-	externalCA []byte,
-	externalTLSServerName string,
+	defaultIssuer CredentialIssuer,
+	issuers map[string]CredentialIssuer,
+	trialDuration time.Duration,
+	trialMaxBindings int,
 	namespaceInformer corev1informers.NamespaceInformer,
 	exportInformer bindinformers.APIServiceExportInformer,
 ) (*Manager, error) {
@@ -78,13 +81,13 @@ func NewKubernetesManager(
 	}
 
 	m := &Manager{
-		namespacePrefix:    namespacePrefix,
 		providerPrettyName: providerPrettyName,
 
-		clusterConfig:         config,
-		externalAddress:       externalAddress,
-		externalCA:            externalCA,
-		externalTLSServerName: externalTLSServerName,
+		clusterConfig:    config,
+		defaultIssuer:    defaultIssuer,
+		issuers:          issuers,
+		trialDuration:    trialDuration,
+		trialMaxBindings: trialMaxBindings,
 
 		kubeClient: kubeClient,
 		bindClient: bindClient,
@@ -103,12 +106,24 @@ func NewKubernetesManager(
 	return m, nil
 }
 
-func (m *Manager) HandleResources(ctx context.Context, identity, resource, group string) ([]byte, error) {
+// HandleResources returns a kubeconfig for the consumer with the given identity
+// to bind the resource, issued by the named credential issuer. Consumers have a
+// service provider namespace per credential issuer. Anonymous trial bindings are
+// capacity-limited and expire after the trial duration.
+func (m *Manager) HandleResources(ctx context.Context, identity, resource, group, issuerName string, trial bool) ([]byte, error) {
 	logger := klog.FromContext(ctx).WithValues("identity", identity, "resource", resource, "group", group)
+	if issuerName != "" {
+		logger = logger.WithValues("credentialIssuer", issuerName)
+	}
 	ctx = klog.NewContext(ctx, logger)
 
+	issuer, err := m.issuer(issuerName)
+	if err != nil {
+		return nil, err
+	}
+
 	// try to find an existing namespace by annotation, or create a new one.
-	nss, err := m.namespaceIndexer.ByIndex(NamespacesByIdentity, identity)
+	nss, err := m.namespaceIndexer.ByIndex(NamespacesByIdentity, NamespaceIdentityKey(identity, issuerName))
 	if err != nil {
 		return nil, err
 	}
@@ -120,7 +135,12 @@ func (m *Manager) HandleResources(ctx context.Context, identity, resource, group
 	if len(nss) == 1 {
 		ns = nss[0].(*corev1.Namespace).Name
 	} else {
-		nsObj, err := kuberesources.CreateNamespace(ctx, m.kubeClient, m.namespacePrefix, identity)
+		if trial {
+			if err := m.checkTrialCapacity(); err != nil {
+				return nil, err
+			}
+		}
+		nsObj, err := kuberesources.CreateNamespace(ctx, m.kubeClient, issuer.NamespacePrefix, identity, issuerName, trial)
 		logger.Info("Created namespace", "namespace", nsObj.Name)
 		if err != nil {
 			return nil, err
@@ -136,7 +156,11 @@ func (m *Manager) HandleResources(ctx context.Context, identity, resource, group
 	if err != nil && !errors.IsNotFound(err) {
 		return nil, err
 	} else if errors.IsNotFound(err) {
-		if err := kuberesources.CreateClusterBinding(ctx, m.bindClient, ns, "kubeconfig", m.providerPrettyName); err != nil {
+		var expirationTime *metav1.Time
+		if trial && m.trialDuration > 0 {
+			expirationTime = &metav1.Time{Time: time.Now().Add(m.trialDuration)}
+		}
+		if err := kuberesources.CreateClusterBinding(ctx, m.bindClient, ns, "kubeconfig", m.providerPrettyName, expirationTime); err != nil {
 			return nil, err
 		}
 	} else {
@@ -149,15 +173,18 @@ func (m *Manager) HandleResources(ctx context.Context, identity, resource, group
 		return nil, err
 	}
 
-	saSecret, err := kuberesources.CreateSASecret(ctx, m.kubeClient, ns, sa.Name)
+	token, expiration, err := m.issueToken(ctx, issuer, ns, sa.Name, false)
 	if err != nil {
 		return nil, err
 	}
 
-	kfgSecret, err := kuberesources.GenerateKubeconfig(ctx, m.kubeClient, m.clusterConfig, m.externalAddress, m.externalCA, m.externalTLSServerName, saSecret.Name, ns, kubeconfigSecretName)
+	kfgSecret, err := kuberesources.GenerateKubeconfig(ctx, m.kubeClient, m.clusterConfig, issuer.ExternalAddress, issuer.ExternalCA, issuer.ExternalTLSServerName, issuer.ExternalFallbacks, token, ns, kubeconfigSecretName)
 	if err != nil {
 		return nil, err
 	}
+	if err := m.updateCredentialsExpiration(ctx, ns, expiration); err != nil {
+		return nil, err
+	}
 
 	return kfgSecret.Data["kubeconfig"], nil
 }
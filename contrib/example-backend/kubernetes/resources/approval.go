@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+)
+
+// SetApproval approves or denies an APIServiceExportRequest, depending on the
+// decision ApprovalApproved or ApprovalDenied. The message is shown to the
+// consumer, and any previous message is removed if empty.
+func SetApproval(ctx context.Context, client bindclient.Interface, ns, name, decision, message string) error {
+	annotations := map[string]interface{}{
+		ApprovalAnnotation:        decision,
+		ApprovalMessageAnnotation: nil,
+	}
+	if message != "" {
+		annotations[ApprovalMessageAnnotation] = message
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+	if err != nil {
+		return err
+	}
+	_, err = client.KubeBindV1alpha1().APIServiceExportRequests(ns).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// RequestResync notifies the konnector of a consumer to resync the given
+// APIServiceExport.
+func RequestResync(ctx context.Context, client bindclient.Interface, ns, name string, now time.Time) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				kubebindv1alpha1.ResyncAnnotationKey: now.UTC().Format(time.RFC3339Nano),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = client.KubeBindV1alpha1().APIServiceExports(ns).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
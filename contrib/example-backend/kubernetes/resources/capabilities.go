@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	"github.com/kube-bind/kube-bind/pkg/features"
+)
+
+// ExportCapabilities returns the capabilities to advertise for an exported CRD. The
+// scale subresource is detected, all others are taken from the CapabilitiesAnnotation.
+// Capabilities behind a disabled feature gate are not advertised, unknown ones are
+// dropped with a warning.
+func ExportCapabilities(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) []kubebindv1alpha1.APIServiceExportCapability {
+	logger := klog.FromContext(ctx)
+
+	capabilities := map[kubebindv1alpha1.APIServiceExportCapability]bool{}
+	for _, v := range crd.Spec.Versions {
+		if v.Served && v.Subresources != nil && v.Subresources.Scale != nil {
+			capabilities[kubebindv1alpha1.APIServiceExportCapabilityScaleSubresource] = true
+		}
+	}
+	for _, c := range strings.Split(crd.Annotations[CapabilitiesAnnotation], ",") {
+		capability := kubebindv1alpha1.APIServiceExportCapability(strings.TrimSpace(c))
+		if capability == "" {
+			continue
+		}
+		if !helpers.KnownCapability(capability) {
+			logger.Info("ignoring unknown capability", "crd", crd.Name, "annotation", CapabilitiesAnnotation, "capability", capability)
+			continue
+		}
+		if features.CapabilityEnabled(features.DefaultFeatureGate, capability) {
+			capabilities[capability] = true
+		}
+	}
+
+	var ret []kubebindv1alpha1.APIServiceExportCapability
+	for c := range capabilities {
+		ret = append(ret, c)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
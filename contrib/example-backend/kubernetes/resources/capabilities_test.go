@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestExportCapabilities(t *testing.T) {
+	scale := apiextensionsv1.CustomResourceDefinitionVersion{
+		Name:         "v1",
+		Served:       true,
+		Subresources: &apiextensionsv1.CustomResourceSubresources{Scale: &apiextensionsv1.CustomResourceSubresourceScale{}},
+	}
+
+	tests := []struct {
+		name       string
+		annotation *string
+		versions   []apiextensionsv1.CustomResourceDefinitionVersion
+		want       []kubebindv1alpha1.APIServiceExportCapability
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:     "scale subresource detected",
+			versions: []apiextensionsv1.CustomResourceDefinitionVersion{scale},
+			want:     []kubebindv1alpha1.APIServiceExportCapability{"ScaleSubresource"},
+		},
+		{
+			name:       "annotated and sorted",
+			annotation: pointer.String(" ReadOnly, ConnectionSecrets,,"),
+			want:       []kubebindv1alpha1.APIServiceExportCapability{"ConnectionSecrets", "ReadOnly"},
+		},
+		{
+			name:       "unknown values dropped",
+			annotation: pointer.String("ReadOnly,Teleport,readonly"),
+			want:       []kubebindv1alpha1.APIServiceExportCapability{"ReadOnly"},
+		},
+		{
+			name:       "feature gated capability not advertised",
+			annotation: pointer.String("ClaimsV2,ReadOnly"),
+			want:       []kubebindv1alpha1.APIServiceExportCapability{"ReadOnly"},
+		},
+		{
+			name:       "duplicates",
+			annotation: pointer.String("ScaleSubresource"),
+			versions:   []apiextensionsv1.CustomResourceDefinitionVersion{scale},
+			want:       []kubebindv1alpha1.APIServiceExportCapability{"ScaleSubresource"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crd := &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com"},
+				Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Versions: tt.versions},
+			}
+			if tt.annotation != nil {
+				crd.Annotations = map[string]string{CapabilitiesAnnotation: *tt.annotation}
+			}
+			require.Equal(t, tt.want, ExportCapabilities(context.Background(), crd))
+		})
+	}
+}
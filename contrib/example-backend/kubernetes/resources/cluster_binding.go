@@ -26,7 +26,7 @@ import (
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 )
 
-func CreateClusterBinding(ctx context.Context, client bindclient.Interface, ns, secretName, providerPrettyName string) error {
+func CreateClusterBinding(ctx context.Context, client bindclient.Interface, ns, secretName, providerPrettyName string, expirationTime *metav1.Time) error {
 	logger := klog.FromContext(ctx)
 
 	clusterBinding := &kubebindv1alpha1.ClusterBinding{
@@ -40,6 +40,7 @@ func CreateClusterBinding(ctx context.Context, client bindclient.Interface, ns,
 				Name: secretName,
 				Key:  "kubeconfig",
 			},
+			ExpirationTime: expirationTime,
 		},
 	}
 
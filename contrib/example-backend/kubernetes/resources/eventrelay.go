@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// EventRelay returns the event relay offered for an exported CRD from the event relay
+// annotations, or nil if none is set.
+func EventRelay(crd *apiextensionsv1.CustomResourceDefinition) *kubebindv1alpha1.EventRelay {
+	relay := &kubebindv1alpha1.EventRelay{
+		Upsync: crd.Annotations[EventRelayUpsyncAnnotation] == "true",
+	}
+	for _, reason := range strings.Split(crd.Annotations[EventRelayReasonsAnnotation], ",") {
+		if reason = strings.TrimSpace(reason); reason != "" {
+			relay.Reasons = append(relay.Reasons, reason)
+		}
+	}
+	if len(relay.Reasons) == 0 && !relay.Upsync {
+		return nil
+	}
+	return relay
+}
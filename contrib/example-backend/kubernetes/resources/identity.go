@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// RequiredClaims returns the ID token claims a consumer must carry to bind the
+// exported CRD, from the RequiredClaimsAnnotation.
+func RequiredClaims(crd *apiextensionsv1.CustomResourceDefinition) (map[string]string, error) {
+	value := strings.TrimSpace(crd.Annotations[RequiredClaimsAnnotation])
+	if value == "" {
+		return nil, nil
+	}
+	ret := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		comps := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(comps) != 2 || comps[0] == "" {
+			return nil, fmt.Errorf("invalid %s annotation %q: expected <claim>=<value>", RequiredClaimsAnnotation, value)
+		}
+		ret[comps[0]] = comps[1]
+	}
+	return ret, nil
+}
+
+// UnmetClaims returns the sorted names of the required claims the given ID token
+// claims do not carry with the required value.
+func UnmetClaims(required map[string]string, claims map[string]interface{}) []string {
+	var unmet []string
+	for name, want := range required {
+		got, found := claims[name]
+		if !found || fmt.Sprint(got) != want {
+			unmet = append(unmet, name)
+		}
+	}
+	sort.Strings(unmet)
+	return unmet
+}
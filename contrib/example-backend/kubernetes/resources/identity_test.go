@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUnmetClaims(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		claims     map[string]interface{}
+		want       []string
+		wantErr    bool
+	}{
+		{name: "no requirement", claims: map[string]interface{}{}},
+		{name: "verified", annotation: "email_verified=true", claims: map[string]interface{}{"email_verified": true}},
+		{name: "not verified", annotation: "email_verified=true", claims: map[string]interface{}{"email_verified": false}, want: []string{"email_verified"}},
+		{name: "missing claims", annotation: "email_verified=true, hd=example.com", claims: map[string]interface{}{}, want: []string{"email_verified", "hd"}},
+		{name: "string claim", annotation: "hd=example.com", claims: map[string]interface{}{"hd": "example.com"}},
+		{name: "invalid", annotation: "email_verified", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{RequiredClaimsAnnotation: tt.annotation},
+			}}
+			required, err := RequiredClaims(crd)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, UnmetClaims(required, tt.claims))
+		})
+	}
+}
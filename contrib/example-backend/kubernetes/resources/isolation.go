@@ -0,0 +1,32 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// ExportIsolation returns the isolation of the APIServiceExport of an exported CRD.
+// Cluster-scoped CRDs and unknown values default to Namespace isolation.
+func ExportIsolation(crd *apiextensionsv1.CustomResourceDefinition) kubebindv1alpha1.Isolation {
+	if crd.Spec.Scope == apiextensionsv1.NamespaceScoped && crd.Annotations[IsolationAnnotation] == string(kubebindv1alpha1.ObjectIsolation) {
+		return kubebindv1alpha1.ObjectIsolation
+	}
+	return kubebindv1alpha1.NamespaceIsolation
+}
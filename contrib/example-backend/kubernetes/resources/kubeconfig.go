@@ -31,15 +31,26 @@ import (
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
+
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 )
 
+// Endpoint is a fallback endpoint of the service provider cluster, written into
+// issued kubeconfigs for konnectors to fail over to.
+type Endpoint struct {
+	Address       string
+	CA            []byte
+	TLSServerName string
+}
+
 func GenerateKubeconfig(ctx context.Context,
 	client kubernetes.Interface,
 	clusterConfig *rest.Config,
 	externalAddress string,
 	externalCA []byte,
 	externalTLSServerName string,
-	saSecretName, ns, kubeconfigSecretName string,
+	fallbacks []Endpoint,
+	token, ns, kubeconfigSecretName string,
 ) (*corev1.Secret, error) {
 	logger := klog.FromContext(ctx)
 
@@ -50,20 +61,6 @@ func GenerateKubeconfig(ctx context.Context,
 		externalCA = clusterConfig.CAData
 	}
 
-	var saSecret *corev1.Secret
-	logger.V(2).Info("Waiting for service account secret to be updated with a token", "name", saSecretName)
-	if err := wait.PollImmediateWithContext(ctx, 500*time.Millisecond, 10*time.Second, func(ctx context.Context) (done bool, err error) {
-		saSecret, err = client.CoreV1().Secrets(ns).Get(ctx, saSecretName, v1.GetOptions{})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		} else if errors.IsNotFound(err) {
-			return false, nil
-		}
-		return saSecret.Data["token"] != nil && saSecret.Data["ca.crt"] != nil, nil
-	}); err != nil {
-		return nil, err
-	}
-
 	cfg := clientcmdapi.Config{
 		Clusters: map[string]*clientcmdapi.Cluster{
 			"default": {
@@ -81,11 +78,18 @@ func GenerateKubeconfig(ctx context.Context,
 		},
 		AuthInfos: map[string]*clientcmdapi.AuthInfo{
 			"default": {
-				Token: string(saSecret.Data["token"]),
+				Token: token,
 			},
 		},
 		CurrentContext: "default",
 	}
+	for i, fallback := range fallbacks {
+		cfg.Clusters[helpers.FallbackClusterName("default", i+1)] = &clientcmdapi.Cluster{
+			Server:                   fallback.Address,
+			TLSServerName:            fallback.TLSServerName,
+			CertificateAuthorityData: fallback.CA,
+		}
+	}
 
 	kubeconfig, err := clientcmd.Write(cfg)
 	if err != nil {
@@ -124,3 +128,25 @@ func GenerateKubeconfig(ctx context.Context,
 	}
 	return updated, nil
 }
+
+// WaitForSASecretToken waits for the token controller to populate the service
+// account token secret and returns the token.
+func WaitForSASecretToken(ctx context.Context, client kubernetes.Interface, ns, saSecretName string) (string, error) {
+	logger := klog.FromContext(ctx)
+
+	var saSecret *corev1.Secret
+	logger.V(2).Info("Waiting for service account secret to be updated with a token", "name", saSecretName)
+	if err := wait.PollImmediateWithContext(ctx, 500*time.Millisecond, 10*time.Second, func(ctx context.Context) (done bool, err error) {
+		saSecret, err = client.CoreV1().Secrets(ns).Get(ctx, saSecretName, v1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return false, err
+		} else if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return saSecret.Data["token"] != nil && saSecret.Data["ca.crt"] != nil, nil
+	}); err != nil {
+		return "", err
+	}
+
+	return string(saSecret.Data["token"]), nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+func TestGenerateKubeconfigFallbacks(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	secret, err := GenerateKubeconfig(context.Background(), client,
+		&rest.Config{Host: "https://internal:6443", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("internal-ca")}},
+		"https://old.provider.example:6443", []byte("old-ca"), "",
+		[]Endpoint{
+			{Address: "https://new.provider.example:6443", CA: []byte("new-ca"), TLSServerName: "api.provider.example"},
+			{Address: "https://dr.provider.example:6443", CA: []byte("old-ca")},
+		},
+		"token", "kube-bind-abcde", "kubeconfig",
+	)
+	require.NoError(t, err)
+
+	configs, err := helpers.ProviderRESTConfigs(secret.Data["kubeconfig"])
+	require.NoError(t, err)
+	require.Len(t, configs, 3)
+	for i, want := range []struct {
+		host, ca, serverName string
+	}{
+		{"https://old.provider.example:6443", "old-ca", ""},
+		{"https://new.provider.example:6443", "new-ca", "api.provider.example"},
+		{"https://dr.provider.example:6443", "old-ca", ""},
+	} {
+		require.Equal(t, want.host, configs[i].Host)
+		require.Equal(t, want.ca, string(configs[i].CAData))
+		require.Equal(t, want.serverName, configs[i].ServerName)
+		require.Equal(t, "token", configs[i].BearerToken)
+	}
+}
@@ -28,9 +28,18 @@ import (
 
 const (
 	IdentityAnnotationKey = "example-backend.kube-bind.io/identity"
+
+	// CredentialIssuerAnnotationKey on a service provider namespace names the
+	// credential issuer of the konnector credentials. It is not set for the
+	// default issuer.
+	CredentialIssuerAnnotationKey = "example-backend.kube-bind.io/credential-issuer"
+
+	// TrialAnnotationKey marks a service provider namespace of an anonymous
+	// trial binding.
+	TrialAnnotationKey = "example-backend.kube-bind.io/trial"
 )
 
-func CreateNamespace(ctx context.Context, client kubernetes.Interface, generateName, id string) (*corev1.Namespace, error) {
+func CreateNamespace(ctx context.Context, client kubernetes.Interface, generateName, id, issuer string, trial bool) (*corev1.Namespace, error) {
 	if !strings.HasSuffix(generateName, "-") {
 		generateName = generateName + "-"
 	}
@@ -43,6 +52,13 @@ func CreateNamespace(ctx context.Context, client kubernetes.Interface, generateN
 		},
 	}
 
+	if issuer != "" {
+		namespace.Annotations[CredentialIssuerAnnotationKey] = issuer
+	}
+	if trial {
+		namespace.Annotations[TrialAnnotationKey] = "true"
+	}
+
 	ns, err := client.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
 	if err != nil && !errors.IsAlreadyExists(err) {
 		return nil, err
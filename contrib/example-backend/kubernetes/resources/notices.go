@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// Notices returns the provider notices of an exported CRD from the
+// NoticesAnnotation, or nil if it is not set or invalid, e.g. with duplicate
+// names.
+func Notices(crd *apiextensionsv1.CustomResourceDefinition) []kubebindv1alpha1.ProviderNotice {
+	value, found := crd.Annotations[NoticesAnnotation]
+	if !found {
+		return nil
+	}
+	var notices []kubebindv1alpha1.ProviderNotice
+	if err := json.Unmarshal([]byte(value), &notices); err != nil {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, n := range notices {
+		if names[n.Name] {
+			return nil
+		}
+		names[n.Name] = true
+		if n.Name == "" || n.Message == "" || (n.Type != kubebindv1alpha1.ProviderNoticeTypeIncident && n.Type != kubebindv1alpha1.ProviderNoticeTypeMaintenance) {
+			return nil
+		}
+	}
+	return notices
+}
@@ -26,6 +26,17 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// EventsRoleBindingName is the name of the RoleBinding granting a consumer access
+// to Events in one of its service namespaces.
+const EventsRoleBindingName = "kube-binder-events"
+
+// EventsClusterRoleName returns the name of the ClusterRole with the Events rules
+// of the consumer with the given provider namespace. It must only be bound by
+// RoleBindings in the service namespaces of that consumer.
+func EventsClusterRoleName(clusterNamespace string) string {
+	return "kube-binder-events-" + clusterNamespace
+}
+
 func CreateServiceAccount(ctx context.Context, client kubeclient.Interface, ns, name string) (*corev1.ServiceAccount, error) {
 	logger := klog.FromContext(ctx)
 
@@ -25,4 +25,94 @@ const (
 
 	//TODO(MQ): maybe think of a better label name.
 	ExportedCRDsLabel = "kube-bind.io/exported"
+
+	// EventsAccessAnnotation on an exported CRD set to "true" gives consumers read
+	// access to events in their namespaces.
+	EventsAccessAnnotation = "kube-bind.io/events-access"
+
+	// EventRelayReasonsAnnotation on an exported CRD is a comma separated list of
+	// Event reasons offered to consumers to be relayed into their namespaces.
+	EventRelayReasonsAnnotation = "kube-bind.io/event-relay-reasons"
+
+	// EventRelayUpsyncAnnotation on an exported CRD set to "true" offers consumers
+	// to relay their Events about bound objects to the service provider.
+	EventRelayUpsyncAnnotation = "kube-bind.io/event-relay-upsync"
+
+	// CapabilitiesAnnotation on an exported CRD is a comma separated list of
+	// APIServiceExport capabilities to advertise to consumers.
+	CapabilitiesAnnotation = "kube-bind.io/capabilities"
+
+	// NamespaceRetentionAnnotation on an exported CRD is a duration for which
+	// service provider namespaces are retained after consumer namespace deletion.
+	NamespaceRetentionAnnotation = "kube-bind.io/namespace-retention"
+
+	// IsolationAnnotation on an exported CRD set to "Object" gives every consumer
+	// object its own service provider namespace.
+	IsolationAnnotation = "kube-bind.io/isolation"
+
+	// StatusSyncIncludeAnnotation on an exported CRD is a comma separated list of
+	// JSONPaths of status fields downsynced to consumers.
+	StatusSyncIncludeAnnotation = "kube-bind.io/status-sync-include"
+
+	// StatusSyncExcludeAnnotation on an exported CRD is a comma separated list of
+	// JSONPaths of status fields not downsynced to consumers.
+	StatusSyncExcludeAnnotation = "kube-bind.io/status-sync-exclude"
+
+	// StatusMaxSizeAnnotation on an exported CRD is the maximum size of downsynced
+	// statuses as quantity, e.g. 512Ki.
+	StatusMaxSizeAnnotation = "kube-bind.io/status-max-size"
+
+	// StatusTruncationAnnotation on an exported CRD is the truncation policy of
+	// oversized statuses, KeepHead or KeepTail.
+	StatusTruncationAnnotation = "kube-bind.io/status-truncation"
+
+	// NoticesAnnotation on an exported CRD is a JSON list of incident and
+	// maintenance notices published to all consumers of the CRD, e.g.
+	// [{"name":"db-upgrade","type":"Maintenance","message":"read-only for 10 minutes"}].
+	NoticesAnnotation = "kube-bind.io/notices"
+
+	// CredentialIssuerAnnotation on an exported CRD names the credential issuer of
+	// the backend configuration issuing the kubeconfigs of its consumers. They get
+	// a service provider namespace of their own per issuer.
+	CredentialIssuerAnnotation = "kube-bind.io/credential-issuer"
+
+	// SchemaRevisionAnnotation on an exported CRD names the revision of its schema.
+	// When it changes, the previous schema stays published to bindings pinned to it.
+	SchemaRevisionAnnotation = "kube-bind.io/schema-revision"
+
+	// RequiredClaimsAnnotation on an exported CRD is a comma separated list of
+	// <claim>=<value> pairs the ID token of a consumer must carry to bind it,
+	// e.g. email_verified=true.
+	RequiredClaimsAnnotation = "kube-bind.io/required-claims"
+
+	// SchemaRolloutStepAnnotation on an exported CRD enables the canary rollout of
+	// new schema revisions. It is the number or percentage of ClusterBindings
+	// upgraded at a time, e.g. 10%.
+	SchemaRolloutStepAnnotation = "kube-bind.io/schema-rollout-step"
+
+	// SchemaRolloutSelectorAnnotation on an exported CRD is a label selector of the
+	// ClusterBindings a canary rollout is restricted to.
+	SchemaRolloutSelectorAnnotation = "kube-bind.io/schema-rollout-selector"
+
+	// SchemaRolloutStatusAnnotation on an exported CRD is set by the backend to the
+	// progress of the canary rollout.
+	SchemaRolloutStatusAnnotation = "kube-bind.io/schema-rollout-status"
+
+	// SchemaRolloutRevisionAnnotation on an APIServiceExport is set by the backend
+	// to the schema revision the export may be upgraded to during a canary rollout.
+	SchemaRolloutRevisionAnnotation = "kube-bind.io/schema-rollout-revision"
+
+	// ApprovalAnnotation on an APIServiceExportRequest is set by the service provider
+	// to Approved or Denied if the backend requires approval of requests.
+	ApprovalAnnotation = "kube-bind.io/approval"
+	ApprovalApproved   = "Approved"
+	ApprovalDenied     = "Denied"
+
+	// ApprovalMessageAnnotation on a denied APIServiceExportRequest is the reason
+	// shown to the consumer.
+	ApprovalMessageAnnotation = "kube-bind.io/approval-message"
+
+	// RetainUntilAnnotation on a retained service provider namespace holds the
+	// RFC3339 time after which the namespace is deleted.
+	RetainUntilAnnotation = "kube-bind.io/retain-until"
 )
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// maxPreviousSchemaRevisions is the number of previous schema revisions kept
+// published. Bindings pinned to older revisions have to be upgraded.
+const maxPreviousSchemaRevisions = 5
+
+// PublishSchemaRevision returns the previous schema revisions of the export after
+// the given revision replaced its current one. The current revision is kept for
+// pinned bindings, unless it has no name, and the oldest revisions are dropped.
+func PublishSchemaRevision(export *kubebindv1alpha1.APIServiceExport, revision string) []kubebindv1alpha1.APIServiceExportSchemaRevision {
+	var ret []kubebindv1alpha1.APIServiceExportSchemaRevision
+	for _, r := range export.Spec.PreviousSchemaRevisions {
+		if r.Name != revision && r.Name != export.Spec.SchemaRevision {
+			ret = append(ret, r)
+		}
+	}
+	if export.Spec.SchemaRevision != "" {
+		ret = append(ret, kubebindv1alpha1.APIServiceExportSchemaRevision{
+			Name:     export.Spec.SchemaRevision,
+			Versions: export.Spec.Versions,
+		})
+	}
+	if len(ret) > maxPreviousSchemaRevisions {
+		ret = ret[len(ret)-maxPreviousSchemaRevisions:]
+	}
+	return ret
+}
+
+// SchemaRevisionApproved returns whether the export may be upgraded to the given
+// schema revision of the CRD. Without canary rollout, every export may.
+func SchemaRevisionApproved(crd *apiextensionsv1.CustomResourceDefinition, export *kubebindv1alpha1.APIServiceExport, revision string) bool {
+	if _, found := crd.Annotations[SchemaRolloutStepAnnotation]; !found {
+		return true
+	}
+	return export.Annotations[SchemaRolloutRevisionAnnotation] == revision
+}
+
+// SchemaRolloutStep returns the number of exports out of total that are upgraded
+// at a time during a canary rollout of the CRD, at least one, or false if the CRD
+// is not rolled out by canary.
+func SchemaRolloutStep(crd *apiextensionsv1.CustomResourceDefinition, total int) (int, bool, error) {
+	value, found := crd.Annotations[SchemaRolloutStepAnnotation]
+	if !found {
+		return 0, false, nil
+	}
+	step := intstr.Parse(value)
+	n, err := intstr.GetScaledValueFromIntOrPercent(&step, total, true)
+	if err != nil {
+		return 0, true, fmt.Errorf("invalid %s annotation %q: %w", SchemaRolloutStepAnnotation, value, err)
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n, true, nil
+}
+
+// SchemaRolloutSelector returns the selector of the ClusterBindings a canary
+// rollout of the CRD is restricted to.
+func SchemaRolloutSelector(crd *apiextensionsv1.CustomResourceDefinition) (labels.Selector, error) {
+	value, found := crd.Annotations[SchemaRolloutSelectorAnnotation]
+	if !found {
+		return labels.Everything(), nil
+	}
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation %q: %w", SchemaRolloutSelectorAnnotation, value, err)
+	}
+	return selector, nil
+}
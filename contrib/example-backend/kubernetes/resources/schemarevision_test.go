@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestPublishSchemaRevision(t *testing.T) {
+	v1 := []kubebindv1alpha1.APIServiceExportVersion{{Name: "v1", Served: true, Storage: true}}
+	rev := func(name string) kubebindv1alpha1.APIServiceExportSchemaRevision {
+		return kubebindv1alpha1.APIServiceExportSchemaRevision{Name: name, Versions: v1}
+	}
+	names := func(revs []kubebindv1alpha1.APIServiceExportSchemaRevision) []string {
+		var ret []string
+		for _, r := range revs {
+			ret = append(ret, r.Name)
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name     string
+		current  string
+		previous []kubebindv1alpha1.APIServiceExportSchemaRevision
+		revision string
+		want     []string
+	}{
+		{name: "first revision", revision: "r1"},
+		{name: "roll forward", current: "r1", revision: "r2", want: []string{"r1"}},
+		{name: "roll back", current: "r2", previous: []kubebindv1alpha1.APIServiceExportSchemaRevision{rev("r1")}, revision: "r1", want: []string{"r2"}},
+		{name: "oldest dropped", current: "r6", previous: []kubebindv1alpha1.APIServiceExportSchemaRevision{rev("r1"), rev("r2"), rev("r3"), rev("r4"), rev("r5")}, revision: "r7", want: []string{"r2", "r3", "r4", "r5", "r6"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export := &kubebindv1alpha1.APIServiceExport{}
+			export.Spec.SchemaRevision = tt.current
+			export.Spec.Versions = v1
+			export.Spec.PreviousSchemaRevisions = tt.previous
+			got := PublishSchemaRevision(export, tt.revision)
+			require.Equal(t, tt.want, names(got), fmt.Sprintf("%v", got))
+		})
+	}
+}
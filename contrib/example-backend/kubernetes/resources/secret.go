@@ -52,3 +52,79 @@ func CreateSASecret(ctx context.Context, client kubernetes.Interface, ns, saName
 
 	return secret, nil
 }
+
+// CreateRotatedSASecret creates another service account token secret for the
+// given service account with a generated name, e.g. to rotate credentials.
+func CreateRotatedSASecret(ctx context.Context, client kubernetes.Interface, ns, saName string) (*corev1.Secret, error) {
+	logger := klog.FromContext(ctx)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: saName + "-",
+			Namespace:    ns,
+			Annotations: map[string]string{
+				ServiceAccountTokenAnnotation: saName,
+			},
+		},
+		Type: ServiceAccountTokenType,
+	}
+
+	logger.V(1).Info("Creating rotated service account secret", "serviceAccount", saName)
+	return client.CoreV1().Secrets(ns).Create(ctx, secret, metav1.CreateOptions{})
+}
+
+// RevokeSASecrets deletes the service account token secrets of the given service
+// account, except the one holding the token keep, if not empty. It returns the
+// names of the deleted secrets, and whether a secret holding keep was found.
+func RevokeSASecrets(ctx context.Context, client kubernetes.Interface, ns, saName, keep string) ([]string, bool, error) {
+	logger := klog.FromContext(ctx)
+
+	secrets, err := client.CoreV1().Secrets(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	var revoke []string
+	kept := false
+	for _, s := range secrets.Items {
+		if s.Type != ServiceAccountTokenType || s.Annotations[ServiceAccountTokenAnnotation] != saName {
+			continue
+		}
+		if keep != "" && string(s.Data["token"]) == keep {
+			kept = true
+			continue
+		}
+		revoke = append(revoke, s.Name)
+	}
+	if keep != "" && !kept {
+		return nil, false, nil
+	}
+
+	var revoked []string
+	for _, name := range revoke {
+		logger.V(1).Info("Deleting service account secret", "name", name)
+		if err := client.CoreV1().Secrets(ns).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return revoked, kept, err
+		}
+		revoked = append(revoked, name)
+	}
+	return revoked, kept, nil
+}
+
+// RevokeConsumer revokes all credentials of the consumer of the given namespace.
+// It deletes the service account token secrets and the service account itself,
+// invalidating tokens with a lifetime bound to it. It returns the names of the
+// deleted secrets and whether the service account existed.
+func RevokeConsumer(ctx context.Context, client kubernetes.Interface, ns string) ([]string, bool, error) {
+	revoked, _, err := RevokeSASecrets(ctx, client, ns, ServiceAccountName, "")
+	if err != nil {
+		return revoked, false, err
+	}
+
+	// the service account is recreated on the next bind
+	if err := client.CoreV1().ServiceAccounts(ns).Delete(ctx, ServiceAccountName, metav1.DeleteOptions{}); errors.IsNotFound(err) {
+		return revoked, false, nil
+	} else if err != nil {
+		return revoked, false, err
+	}
+	return revoked, true, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+// StatusSync returns the status sync policy of an exported CRD from the status sync
+// annotations, or nil if none is set. Invalid values are ignored, invalid paths with
+// a warning.
+func StatusSync(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) *kubebindv1alpha1.StatusSyncPolicy {
+	logger := klog.FromContext(ctx).WithValues("crd", crd.Name)
+	policy := &kubebindv1alpha1.StatusSyncPolicy{
+		Include: statusFieldPaths(logger, StatusSyncIncludeAnnotation, crd.Annotations[StatusSyncIncludeAnnotation]),
+		Exclude: statusFieldPaths(logger, StatusSyncExcludeAnnotation, crd.Annotations[StatusSyncExcludeAnnotation]),
+	}
+	if value, found := crd.Annotations[StatusMaxSizeAnnotation]; found {
+		if q, err := resource.ParseQuantity(value); err == nil {
+			policy.MaxSize = &q
+		}
+	}
+	switch p := kubebindv1alpha1.StatusTruncationPolicy(crd.Annotations[StatusTruncationAnnotation]); p {
+	case kubebindv1alpha1.StatusTruncationKeepHead, kubebindv1alpha1.StatusTruncationKeepTail:
+		policy.Truncation = p
+	}
+
+	if len(policy.Include) == 0 && len(policy.Exclude) == 0 && policy.MaxSize == nil && policy.Truncation == "" {
+		return nil
+	}
+	if policy.Truncation == "" {
+		policy.Truncation = kubebindv1alpha1.StatusTruncationKeepTail
+	}
+	return policy
+}
+
+func statusFieldPaths(logger klog.Logger, annotation, value string) []kubebindv1alpha1.StatusFieldPath {
+	var paths []kubebindv1alpha1.StatusFieldPath
+	for _, p := range strings.Split(value, ",") {
+		path := kubebindv1alpha1.StatusFieldPath(strings.TrimSpace(p))
+		if path == "" {
+			continue
+		}
+		if !helpers.ValidStatusFieldPath(path) {
+			logger.Info("ignoring invalid status field path", "annotation", annotation, "path", path)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
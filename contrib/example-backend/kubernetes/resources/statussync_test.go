@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestStatusSync(t *testing.T) {
+	maxSize := resource.MustParse("512Ki")
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *kubebindv1alpha1.StatusSyncPolicy
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name: "include and exclude",
+			annotations: map[string]string{
+				StatusSyncIncludeAnnotation: ".status.phase, .status.endpoint",
+				StatusSyncExcludeAnnotation: ".status.internal",
+			},
+			want: &kubebindv1alpha1.StatusSyncPolicy{
+				Include:    []kubebindv1alpha1.StatusFieldPath{".status.phase", ".status.endpoint"},
+				Exclude:    []kubebindv1alpha1.StatusFieldPath{".status.internal"},
+				Truncation: kubebindv1alpha1.StatusTruncationKeepTail,
+			},
+		},
+		{
+			name: "invalid paths skipped",
+			annotations: map[string]string{
+				StatusSyncIncludeAnnotation: ".status.phase,.status.conditions[0],.spec.replicas,status.x",
+			},
+			want: &kubebindv1alpha1.StatusSyncPolicy{
+				Include:    []kubebindv1alpha1.StatusFieldPath{".status.phase"},
+				Truncation: kubebindv1alpha1.StatusTruncationKeepTail,
+			},
+		},
+		{
+			name: "only invalid paths",
+			annotations: map[string]string{
+				StatusSyncExcludeAnnotation: ".status[*]",
+			},
+		},
+		{
+			name: "max size and truncation",
+			annotations: map[string]string{
+				StatusMaxSizeAnnotation:    "512Ki",
+				StatusTruncationAnnotation: "KeepHead",
+			},
+			want: &kubebindv1alpha1.StatusSyncPolicy{
+				MaxSize:    &maxSize,
+				Truncation: kubebindv1alpha1.StatusTruncationKeepHead,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crd := &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com", Annotations: tt.annotations},
+			}
+			require.Equal(t, tt.want, StatusSync(context.Background(), crd))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// TokenOptions configures the credentials minted for konnectors.
+type TokenOptions struct {
+	// Lifetime of the tokens. If zero, non-expiring service account token
+	// secrets are used. Otherwise, tokens are requested via the TokenRequest API.
+	Lifetime time.Duration
+	// Audiences of the tokens. The service provider kube-apiserver must accept
+	// them, i.e. they must be part of its --api-audiences.
+	Audiences []string
+}
+
+// RequestSAToken requests a bound token for the service account via the TokenRequest
+// API. It returns the token and its expiration time.
+func RequestSAToken(ctx context.Context, client kubernetes.Interface, ns, saName string, opts TokenOptions) (string, metav1.Time, error) {
+	logger := klog.FromContext(ctx)
+
+	seconds := int64(opts.Lifetime.Seconds())
+	logger.V(1).Info("Requesting service account token", "serviceAccount", saName, "lifetime", opts.Lifetime, "audiences", opts.Audiences)
+	tr, err := client.CoreV1().ServiceAccounts(ns).CreateToken(ctx, saName, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         opts.Audiences,
+			ExpirationSeconds: &seconds,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", metav1.Time{}, err
+	}
+
+	return tr.Status.Token, tr.Status.ExpirationTimestamp, nil
+}
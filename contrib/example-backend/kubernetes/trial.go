@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+)
+
+// ErrTrialCapacityExhausted is returned if no further trial consumers are accepted.
+var ErrTrialCapacityExhausted = errors.New("trial capacity exhausted")
+
+// checkTrialCapacity returns ErrTrialCapacityExhausted if the maximum number of
+// trial consumers is reached. Every anonymous request has a new identity, hence the
+// trial namespaces are counted. Namespaces of authenticated consumers are not
+// limited. The informer cache can lag behind, so the limit
+// can be exceeded slightly by concurrent requests.
+func (m *Manager) checkTrialCapacity() error {
+	if m.trialDuration == 0 || m.trialMaxBindings == 0 {
+		return nil
+	}
+	nss, err := m.namespaceLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	consumers := 0
+	for _, ns := range nss {
+		if ns.Annotations[kuberesources.TrialAnnotationKey] == "true" && ns.DeletionTimestamp == nil {
+			consumers++
+		}
+	}
+	if consumers >= m.trialMaxBindings {
+		return fmt.Errorf("%w: %d trial bindings", ErrTrialCapacityExhausted, consumers)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+)
+
+func newTrialNamespace(name string) *corev1.Namespace {
+	ns := newConsumerNamespace(name)
+	ns.Annotations[resources.TrialAnnotationKey] = "true"
+	return ns
+}
+
+func TestCheckTrialCapacity(t *testing.T) {
+	now := metav1.Now()
+	terminating := newTrialNamespace("cluster-c")
+	terminating.DeletionTimestamp = &now
+	namespaces := []runtime.Object{
+		newTrialNamespace("cluster-a"),
+		newTrialNamespace("cluster-b"),
+		terminating,
+		newConsumerNamespace("cluster-d"),
+		newConsumerNamespace("cluster-e"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	}
+
+	tests := []struct {
+		name          string
+		trialDuration time.Duration
+		maxBindings   int
+		wantErr       bool
+	}{
+		{name: "no trial", maxBindings: 1},
+		{name: "unlimited", trialDuration: time.Hour},
+		{name: "below limit", trialDuration: time.Hour, maxBindings: 3},
+		{name: "limit reached", trialDuration: time.Hour, maxBindings: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t, nil, namespaces, nil)
+			m.trialDuration = tt.trialDuration
+			m.trialMaxBindings = tt.maxBindings
+
+			err := m.checkTrialCapacity()
+			if tt.wantErr {
+				require.True(t, errors.Is(err, ErrTrialCapacityExhausted), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
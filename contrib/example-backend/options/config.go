@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"github.com/spf13/pflag"
+
+	configv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/config/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/features"
+)
+
+// applyConfigFile sets the options from the BackendConfiguration in --config,
+// except those given as flags.
+func (options *Options) applyConfigFile(fs *pflag.FlagSet) error {
+	config, err := configv1alpha1.LoadBackendConfiguration(options.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	if err := features.SetFromConfigFile(fs, config.FeatureGates); err != nil {
+		return err
+	}
+
+	configv1alpha1.OverrideString(fs, "kubeconfig", &options.KubeConfig, config.Kubeconfig)
+	configv1alpha1.OverrideString(fs, "namespace-prefix", &options.NamespacePrefix, config.NamespacePrefix)
+	configv1alpha1.OverrideString(fs, "pretty-name", &options.PrettyName, config.PrettyName)
+	configv1alpha1.OverrideString(fs, "consumer-scope", &options.ConsumerScope, config.ConsumerScope)
+	configv1alpha1.OverrideString(fs, "region", &options.Region, config.Region)
+	configv1alpha1.OverrideString(fs, "group-alias-suffix", &options.GroupAliasSuffix, config.GroupAliasSuffix)
+	configv1alpha1.Override(fs, "require-approval", &options.RequireApproval, config.RequireApproval)
+	configv1alpha1.OverrideDuration(fs, "trial-duration", &options.TrialDuration, config.TrialDuration)
+	configv1alpha1.Override(fs, "trial-max-bindings", &options.TrialMaxBindings, config.TrialMaxBindings)
+	configv1alpha1.Override(fs, "migrate-storage", &options.MigrateStorage, config.MigrateStorage)
+	configv1alpha1.Override(fs, "enable-bff", &options.EnableBFF, config.EnableBFF)
+	configv1alpha1.Override(fs, "enable-dashboard", &options.EnableDashboard, config.EnableDashboard)
+
+	if e := config.External; e != nil {
+		configv1alpha1.OverrideString(fs, "external-address", &options.ExternalAddress, e.Address)
+		configv1alpha1.OverrideString(fs, "external-ca-file", &options.ExternalCAFile, e.CAFile)
+		configv1alpha1.OverrideString(fs, "external-server-name", &options.TLSExternalServerName, e.ServerName)
+		options.ExternalFallbacks = externalFallbacksFromConfig(e.Fallbacks)
+	}
+	if t := config.Tokens; t != nil {
+		configv1alpha1.OverrideDuration(fs, "token-lifetime", &options.TokenLifetime, t.Lifetime)
+		configv1alpha1.OverrideSlice(fs, "token-audiences", &options.TokenAudiences, t.Audiences)
+	}
+	if s := config.Serving; s != nil {
+		configv1alpha1.OverrideString(fs, "listen-address", &options.Serve.ListenAddress, s.ListenAddress)
+		configv1alpha1.OverrideString(fs, "tls-cert-file", &options.Serve.CertFile, s.TLSCertFile)
+		configv1alpha1.OverrideString(fs, "tls-key-file", &options.Serve.KeyFile, s.TLSKeyFile)
+		configv1alpha1.OverrideString(fs, "metrics-bind-address", &options.MetricsBindAddress, s.MetricsBindAddress)
+	}
+	if o := config.OIDC; o != nil {
+		configv1alpha1.OverrideString(fs, "oidc-issuer-url", &options.OIDC.IssuerURL, o.IssuerURL)
+		configv1alpha1.OverrideString(fs, "oidc-issuer-client-id", &options.OIDC.IssuerClientID, o.ClientID)
+		configv1alpha1.OverrideString(fs, "oidc-issuer-client-secret", &options.OIDC.IssuerClientSecret, o.ClientSecret)
+		configv1alpha1.OverrideString(fs, "oidc-callback-url", &options.OIDC.CallbackURL, o.CallbackURL)
+		configv1alpha1.OverrideString(fs, "oidc-authorize-url", &options.OIDC.AuthorizeURL, o.AuthorizeURL)
+	}
+	if c := config.Cookie; c != nil {
+		configv1alpha1.OverrideString(fs, "cookie-keys-secret", &options.Cookie.KeysSecret, c.KeysSecret)
+	}
+	if k := config.KonnectorVersions; k != nil {
+		configv1alpha1.OverrideString(fs, "konnector-minimum-version", &options.KonnectorMinimumVersion, k.Minimum)
+		configv1alpha1.OverrideString(fs, "konnector-recommended-version", &options.KonnectorRecommendedVersion, k.Recommended)
+	}
+	for _, c := range config.CredentialIssuers {
+		options.CredentialIssuers = append(options.CredentialIssuers, credentialIssuerFromConfig(c))
+	}
+
+	return nil
+}
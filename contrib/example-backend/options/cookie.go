@@ -19,6 +19,7 @@ package options
 import (
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/pflag"
 )
@@ -26,6 +27,9 @@ import (
 type Cookie struct {
 	SigningKey    string
 	EncryptionKey string
+
+	// KeysSecret is the <namespace>/<name> of a Secret with versioned cookie keys.
+	KeysSecret string
 }
 
 func NewCookie() *Cookie {
@@ -35,6 +39,7 @@ func NewCookie() *Cookie {
 func (options *Cookie) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&options.SigningKey, "cookie-signing-key", options.SigningKey, "The key which is used to sign cookies, base64 encoded. Valid lengths are 32 or 64 bytes.")
 	fs.StringVar(&options.EncryptionKey, "cookie-encryption-key", options.EncryptionKey, "The key which is used to encrypt cookies, base64 encoded, optional. Valid lengths are 16, 24, or 32 bytes selecting AES-128, AES-192, or AES-256.")
+	fs.StringVar(&options.KeysSecret, "cookie-keys-secret", options.KeysSecret, "A <namespace>/<name> of a Secret with versioned cookie keys \"signing-key.<version>\" and optional \"encryption-key.<version>\". The highest version signs, all versions and --cookie-signing-key are accepted. The Secret is watched for rotation.")
 }
 
 func (options *Cookie) Complete() error {
@@ -42,20 +47,31 @@ func (options *Cookie) Complete() error {
 }
 
 func (options *Cookie) Validate() error {
-	if options.SigningKey == "" {
-		return fmt.Errorf("cookie signing key must not be empty")
+	if options.SigningKey == "" && options.KeysSecret == "" {
+		return fmt.Errorf("cookie signing key or cookie keys secret must not be empty")
 	}
 
-	if err := checkKey(options.SigningKey, 32, 64); err != nil {
-		return fmt.Errorf("invalid signing key: %w", err)
+	if options.SigningKey != "" {
+		if err := checkKey(options.SigningKey, 32, 64); err != nil {
+			return fmt.Errorf("invalid signing key: %w", err)
+		}
 	}
 
 	if options.EncryptionKey != "" {
-		if err := checkKey(options.SigningKey, 16, 24, 32); err != nil {
+		if options.SigningKey == "" {
+			return fmt.Errorf("cookie encryption key requires a cookie signing key")
+		}
+		if err := checkKey(options.EncryptionKey, 16, 24, 32); err != nil {
 			return fmt.Errorf("invalid encryption key: %w", err)
 		}
 	}
 
+	if options.KeysSecret != "" {
+		if _, _, err := options.KeysSecretNamespaceName(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -72,3 +88,12 @@ func checkKey(key string, validLengths ...int) error {
 
 	return fmt.Errorf("invalid key length: %d", len(b))
 }
+
+// KeysSecretNamespaceName splits the cookie keys secret into namespace and name.
+func (options *Cookie) KeysSecretNamespaceName() (string, string, error) {
+	parts := strings.SplitN(options.KeysSecret, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("cookie keys secret must be <namespace>/<name>, got %q", options.KeysSecret)
+	}
+	return parts[0], parts[1], nil
+}
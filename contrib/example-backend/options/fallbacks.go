@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	configv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/config/v1alpha1"
+)
+
+// ExternalFallback is a further endpoint of the service provider cluster that
+// konnectors fail over to, e.g. during a control-plane migration. It is only
+// configurable in the --config file.
+type ExternalFallback struct {
+	Address       string
+	CAFile        string
+	CA            []byte
+	TLSServerName string
+}
+
+func externalFallbacksFromConfig(endpoints []configv1alpha1.BackendEndpoint) []ExternalFallback {
+	var fallbacks []ExternalFallback
+	for _, e := range endpoints {
+		fallbacks = append(fallbacks, ExternalFallback{
+			Address:       e.Address,
+			CAFile:        e.CAFile,
+			TLSServerName: e.ServerName,
+		})
+	}
+	return fallbacks
+}
+
+// completeExternalFallbacks reads the CA files of the fallbacks, defaulting to
+// the CA of the primary address.
+func completeExternalFallbacks(fallbacks []ExternalFallback, ca []byte) error {
+	for i := range fallbacks {
+		fallback := &fallbacks[i]
+		if fallback.CAFile == "" {
+			fallback.CA = ca
+			continue
+		}
+		bs, err := os.ReadFile(fallback.CAFile)
+		if err != nil {
+			return fmt.Errorf("error reading CA file of external fallback %q: %v", fallback.Address, err)
+		}
+		fallback.CA = bs
+	}
+	return nil
+}
+
+func validateExternalFallbacks(fallbacks []ExternalFallback, address string) error {
+	seen := map[string]bool{address: true}
+	for _, fallback := range fallbacks {
+		if !strings.HasPrefix(fallback.Address, "https://") {
+			return fmt.Errorf("external fallback address %q must start with https://", fallback.Address)
+		}
+		if _, err := url.Parse(fallback.Address); err != nil {
+			return fmt.Errorf("invalid external fallback address %q: %v", fallback.Address, err)
+		}
+		if seen[fallback.Address] {
+			return fmt.Errorf("duplicate external fallback address %q", fallback.Address)
+		}
+		seen[fallback.Address] = true
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	configv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/config/v1alpha1"
+)
+
+// CredentialIssuer is a named issuer of konnector credentials, selected by
+// exported CRDs with the kube-bind.io/credential-issuer annotation. It is only
+// configurable in the --config file. Unset values are taken from the flags.
+// All issuers create their credentials in the service provider cluster of the
+// backend, see kubernetes.CredentialIssuer.
+type CredentialIssuer struct {
+	Name                  string
+	NamespacePrefix       string
+	ExternalAddress       string
+	ExternalCAFile        string
+	ExternalCA            []byte
+	TLSExternalServerName string
+	ExternalFallbacks     []ExternalFallback
+	TokenLifetime         time.Duration
+	TokenAudiences        []string
+
+	// tokensSet is true if the issuer sets its own token lifetime and audiences.
+	tokensSet bool
+}
+
+func credentialIssuerFromConfig(c configv1alpha1.BackendCredentialIssuer) CredentialIssuer {
+	issuer := CredentialIssuer{
+		Name:            c.Name,
+		NamespacePrefix: c.NamespacePrefix,
+	}
+	if e := c.External; e != nil {
+		issuer.ExternalAddress = e.Address
+		issuer.ExternalCAFile = e.CAFile
+		issuer.TLSExternalServerName = e.ServerName
+		issuer.ExternalFallbacks = externalFallbacksFromConfig(e.Fallbacks)
+	}
+	if t := c.Tokens; t != nil {
+		issuer.tokensSet = true
+		if t.Lifetime != nil {
+			issuer.TokenLifetime = t.Lifetime.Duration
+		}
+		issuer.TokenAudiences = t.Audiences
+	}
+	return issuer
+}
+
+// completeCredentialIssuers defaults the unset values of the credential issuers
+// to those of the flags and reads their CA files.
+func (options *Options) completeCredentialIssuers() error {
+	for i := range options.CredentialIssuers {
+		issuer := &options.CredentialIssuers[i]
+		if issuer.NamespacePrefix == "" {
+			issuer.NamespacePrefix = options.NamespacePrefix
+		}
+		if issuer.ExternalAddress == "" {
+			issuer.ExternalAddress = options.ExternalAddress
+			issuer.TLSExternalServerName = options.TLSExternalServerName
+			if issuer.ExternalCAFile == "" {
+				issuer.ExternalCA = options.ExternalCA
+			}
+			if len(issuer.ExternalFallbacks) == 0 {
+				issuer.ExternalFallbacks = append([]ExternalFallback(nil), options.ExternalFallbacks...)
+			}
+		}
+		if issuer.ExternalCAFile != "" {
+			ca, err := os.ReadFile(issuer.ExternalCAFile)
+			if err != nil {
+				return fmt.Errorf("error reading external CA file of credential issuer %q: %v", issuer.Name, err)
+			}
+			issuer.ExternalCA = ca
+		}
+		if err := completeExternalFallbacks(issuer.ExternalFallbacks, issuer.ExternalCA); err != nil {
+			return fmt.Errorf("credential issuer %q: %w", issuer.Name, err)
+		}
+		if !issuer.tokensSet {
+			issuer.TokenLifetime = options.TokenLifetime
+			issuer.TokenAudiences = options.TokenAudiences
+		}
+	}
+	return nil
+}
+
+func (options *CompletedOptions) validateCredentialIssuers() error {
+	names := map[string]bool{}
+	for _, issuer := range options.CredentialIssuers {
+		if errs := validation.IsDNS1123Label(issuer.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid credential issuer name %q: %s", issuer.Name, strings.Join(errs, ", "))
+		}
+		if names[issuer.Name] {
+			return fmt.Errorf("duplicate credential issuer %q", issuer.Name)
+		}
+		names[issuer.Name] = true
+
+		if issuer.TokenLifetime != 0 && issuer.TokenLifetime < 10*time.Minute {
+			return fmt.Errorf("token lifetime of credential issuer %q must be at least 10m", issuer.Name)
+		}
+		if len(issuer.TokenAudiences) > 0 && issuer.TokenLifetime == 0 {
+			return fmt.Errorf("token audiences of credential issuer %q require a token lifetime", issuer.Name)
+		}
+		if issuer.ExternalAddress != "" {
+			if !strings.HasPrefix(issuer.ExternalAddress, "https://") {
+				return fmt.Errorf("external address of credential issuer %q must start with https://", issuer.Name)
+			}
+			if _, err := url.Parse(issuer.ExternalAddress); err != nil {
+				return fmt.Errorf("invalid external address of credential issuer %q: %v", issuer.Name, err)
+			}
+		}
+		if len(issuer.ExternalFallbacks) > 0 && issuer.ExternalAddress == "" {
+			return fmt.Errorf("external fallbacks of credential issuer %q require an external address", issuer.Name)
+		}
+		if err := validateExternalFallbacks(issuer.ExternalFallbacks, issuer.ExternalAddress); err != nil {
+			return fmt.Errorf("credential issuer %q: %w", issuer.Name, err)
+		}
+	}
+	return nil
+}
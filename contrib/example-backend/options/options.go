@@ -17,17 +17,25 @@ limitations under the License.
 package options
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/blang/semver/v4"
+	"github.com/gorilla/securecookie"
 	"github.com/spf13/pflag"
 
 	"k8s.io/component-base/logs"
 	logsv1 "k8s.io/component-base/logs/api/v1"
 
+	"github.com/kube-bind/kube-bind/contrib/example-backend/bff"
+	"github.com/kube-bind/kube-bind/contrib/example-backend/devoidc"
+	configv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/config/v1alpha1"
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/features"
 )
 
 type Options struct {
@@ -37,8 +45,14 @@ type Options struct {
 	Serve  *Serve
 
 	ExtraOptions
+
+	// flags are the flags added with AddFlags, for the precedence over ConfigFile.
+	flags *pflag.FlagSet
 }
 type ExtraOptions struct {
+	// ConfigFile is a BackendConfiguration file. Flags override its values.
+	ConfigFile string
+
 	KubeConfig string
 
 	NamespacePrefix       string
@@ -48,6 +62,27 @@ type ExtraOptions struct {
 	ExternalCAFile        string
 	ExternalCA            []byte
 	TLSExternalServerName string
+	RequireApproval       bool
+	Region                string
+	GroupAliasSuffix      string
+	TokenLifetime         time.Duration
+	TokenAudiences        []string
+	TrialDuration         time.Duration
+	TrialMaxBindings      int
+	Dev                   bool
+	DevIssuerAddress      string
+	MetricsBindAddress    string
+	MigrateStorage        bool
+	EnableBFF             bool
+	EnableDashboard       bool
+
+	KonnectorMinimumVersion     string
+	KonnectorRecommendedVersion string
+
+	// ExternalFallbacks are the fallback endpoints of the config file.
+	ExternalFallbacks []ExternalFallback
+	// CredentialIssuers are the named credential issuers of the config file.
+	CredentialIssuers []CredentialIssuer
 
 	TestingAutoSelect string
 }
@@ -77,19 +112,25 @@ func NewOptions() *Options {
 		Serve:  NewServe(),
 
 		ExtraOptions: ExtraOptions{
-			NamespacePrefix: "cluster",
-			PrettyName:      "Example Backend",
-			ConsumerScope:   string(kubebindv1alpha1.NamespacedScope),
+			NamespacePrefix:  "cluster",
+			PrettyName:       "Example Backend",
+			ConsumerScope:    string(kubebindv1alpha1.NamespacedScope),
+			DevIssuerAddress: "127.0.0.1:0",
+			TrialMaxBindings: 100,
+			MigrateStorage:   true,
 		},
 	}
 }
 
 func (options *Options) AddFlags(fs *pflag.FlagSet) {
 	logsv1.AddFlags(options.Logs, fs)
+	features.DefaultMutableFeatureGate.AddFlag(fs)
 	options.OIDC.AddFlags(fs)
 	options.Cookie.AddFlags(fs)
 	options.Serve.AddFlags(fs)
+	options.flags = fs
 
+	fs.StringVar(&options.ConfigFile, "config", options.ConfigFile, "A BackendConfiguration file of apiVersion "+configv1alpha1.SchemeGroupVersion.String()+" with the options of the backend. Flags given on the command line override the values of the file.")
 	fs.StringVar(&options.KubeConfig, "kubeconfig", options.KubeConfig, "path to a kubeconfig. Only required if out-of-cluster")
 	fs.StringVar(&options.NamespacePrefix, "namespace-prefix", options.NamespacePrefix, "The prefix to use for cluster namespaces")
 	fs.StringVar(&options.PrettyName, "pretty-name", options.PrettyName, "Pretty name for the backend")
@@ -97,15 +138,54 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&options.ExternalAddress, "external-address", options.ExternalAddress, "The external address for the service provider cluster, including https:// and port. If not specified, service account's hosts are used.")
 	fs.StringVar(&options.ExternalCAFile, "external-ca-file", options.ExternalCAFile, "The external CA file for the service provider cluster. If not specified, service account's CA is used.")
 	fs.StringVar(&options.TLSExternalServerName, "external-server-name", options.TLSExternalServerName, "The external (TLS) server name used by consumers to talk to the service provider cluster. This can be useful to select the right certificate via SNI.")
+	fs.DurationVar(&options.TokenLifetime, "token-lifetime", options.TokenLifetime, "The lifetime of the credentials issued to konnectors, at least 10m. If zero, non-expiring service account token secrets are used. Konnectors have to rotate expiring credentials with \"kubectl bind rotate-credentials\".")
+	fs.StringSliceVar(&options.TokenAudiences, "token-audiences", options.TokenAudiences, "The audiences of the credentials issued to konnectors. They must be accepted by the service provider cluster's kube-apiserver. Requires --token-lifetime.")
+	fs.StringVar(&options.GroupAliasSuffix, "group-alias-suffix", options.GroupAliasSuffix, "A domain owned by the service provider, e.g. acme.example. It is set as kube-bind.io/group-alias-suffix annotation on APIServiceExports, such that consumers with a conflicting CRD can bind under the API group <group>.<suffix> instead.")
+	fs.StringVar(&options.Region, "region", options.Region, "The region where data of consumers is stored and processed, e.g. eu. It is advertised to consumers and set as kube-bind.io/region label on APIServiceExports, such that konnectors can refuse regions outside of their data residency.")
+	fs.DurationVar(&options.TrialDuration, "trial-duration", options.TrialDuration, "If set, consumers can explicitly ask for an anonymous trial binding without OIDC login by binding /export?trial=true. Trial bindings expire after this duration, and the ClusterBinding with all bound objects is deleted on expiry. Authenticated bindings are not affected. Useful for demos and evaluation.")
+	fs.IntVar(&options.TrialMaxBindings, "trial-max-bindings", options.TrialMaxBindings, "The maximum number of unexpired trial bindings. Further trial binding requests are refused until trial bindings expire. Zero means unlimited. Only used with --trial-duration.")
+	fs.BoolVar(&options.RequireApproval, "require-approval", options.RequireApproval, "Require APIServiceExportRequests to be approved, e.g. with \"example-backend admin approve\", before APIServiceExports are created.")
+	fs.BoolVar(&options.Dev, "dev", options.Dev, "Run with an embedded throwaway OIDC issuer on localhost that logs in every user immediately, and a random cookie signing key if none is given. For development and demos only, never use it in production.")
+	fs.StringVar(&options.DevIssuerAddress, "dev-issuer-address", options.DevIssuerAddress, "The address the embedded OIDC issuer of --dev listens on. On all interfaces, e.g. 0.0.0.0:5556, the issuer URL is http://127.0.0.1:<port>, e.g. to reach it through a port-forward or a kind port mapping.")
+
+	fs.StringVar(&options.MetricsBindAddress, "metrics-bind-address", options.MetricsBindAddress, "The address to serve Prometheus metrics on, e.g. :8081. It is separate from the address of the OIDC and bind endpoints and must not be exposed publicly. Empty disables the metrics endpoint.")
+
+	fs.BoolVar(&options.MigrateStorage, "migrate-storage", options.MigrateStorage, "Rewrite the objects of the kube-bind CRDs of the backend at startup if the CRDs list old stored versions or have pending migrations, such that upgrades need no manual steps. Completed migrations are recorded in the kube-bind.io/migrations annotation of the CRDs.")
+
+	fs.BoolVar(&options.EnableBFF, "enable-bff", options.EnableBFF, "Serve the backend-for-frontend endpoint "+bff.OverviewPath+" with the catalog, the bindings and the consumer clusters in one JSON document for provider dashboards. Requests need a bearer token of the service provider cluster whose user may list clusterbindings.kube-bind.io in all namespaces.")
+	fs.StringVar(&options.KonnectorMinimumVersion, "konnector-minimum-version", options.KonnectorMinimumVersion, "The oldest konnector version supported, e.g. v0.4.0. It is published on the ClusterBindings, and older konnectors report a VersionSkew condition or upgrade themselves if enabled by the consumer.")
+	fs.StringVar(&options.KonnectorRecommendedVersion, "konnector-recommended-version", options.KonnectorRecommendedVersion, "The recommended konnector version, e.g. v0.5.0. It is published on the ClusterBindings like --konnector-minimum-version, and must not be older.")
+	fs.BoolVar(&options.EnableDashboard, "enable-dashboard", options.EnableDashboard, "Serve an embedded web dashboard at "+bff.DashboardPath+" showing the consumer clusters, their bindings and heartbeats, and the pending approvals, with actions to approve, deny, resync, revoke and rotate signing keys. Implies --enable-bff. Users log in with a bearer token of the service provider cluster, and each action is authorized against the permissions of its user.")
 
 	fs.StringVar(&options.TestingAutoSelect, "testing-auto-select", options.TestingAutoSelect, "<resource>.<group> that is automatically selected on th bind screen for testing")
 	fs.MarkHidden("testing-auto-select") // nolint: errcheck
 }
 
 func (options *Options) Complete() (*CompletedOptions, error) {
+	if options.ConfigFile != "" {
+		fs := options.flags
+		if fs == nil {
+			fs = pflag.NewFlagSet("example-backend", pflag.ContinueOnError)
+		}
+		if err := options.applyConfigFile(fs); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := options.OIDC.Complete(); err != nil {
 		return nil, err
 	}
+	if options.Dev {
+		if options.OIDC.IssuerClientID == "" {
+			options.OIDC.IssuerClientID = devoidc.ClientID
+		}
+		if options.OIDC.IssuerClientSecret == "" {
+			options.OIDC.IssuerClientSecret = devoidc.ClientSecret
+		}
+		if options.Cookie.SigningKey == "" && options.Cookie.KeysSecret == "" {
+			options.Cookie.SigningKey = base64.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32))
+		}
+	}
 	if err := options.Cookie.Complete(); err != nil {
 		return nil, err
 	}
@@ -131,6 +211,12 @@ func (options *Options) Complete() (*CompletedOptions, error) {
 		}
 		options.ExternalCA = ca
 	}
+	if err := completeExternalFallbacks(options.ExternalFallbacks, options.ExternalCA); err != nil {
+		return nil, err
+	}
+	if err := options.completeCredentialIssuers(); err != nil {
+		return nil, err
+	}
 
 	return &CompletedOptions{
 		completedOptions: &completedOptions{
@@ -151,7 +237,23 @@ func (options *CompletedOptions) Validate() error {
 		return fmt.Errorf("pretty name cannot be empty")
 	}
 
-	if err := options.OIDC.Validate(); err != nil {
+	if options.TrialDuration < 0 {
+		return fmt.Errorf("trial duration cannot be negative")
+	}
+	if options.TrialMaxBindings < 0 {
+		return fmt.Errorf("trial max bindings cannot be negative")
+	}
+	if options.Dev {
+		if options.OIDC.IssuerURL != "" {
+			return fmt.Errorf("--dev and --oidc-issuer-url are mutually exclusive")
+		}
+		if options.TrialDuration != 0 {
+			return fmt.Errorf("--dev and --trial-duration are mutually exclusive")
+		}
+		if options.RequireApproval {
+			return fmt.Errorf("--dev and --require-approval are mutually exclusive")
+		}
+	} else if err := options.OIDC.Validate(); err != nil {
 		return err
 	}
 	if err := options.Cookie.Validate(); err != nil {
@@ -161,6 +263,29 @@ func (options *CompletedOptions) Validate() error {
 		return fmt.Errorf("consumer scope must be either %q or %q", kubebindv1alpha1.NamespacedScope, kubebindv1alpha1.ClusterScope)
 	}
 
+	if options.TokenLifetime != 0 && options.TokenLifetime < 10*time.Minute {
+		return fmt.Errorf("token lifetime must be at least 10m")
+	}
+	if len(options.TokenAudiences) > 0 && options.TokenLifetime == 0 {
+		return fmt.Errorf("token audiences require a token lifetime")
+	}
+
+	var minimum, recommended semver.Version
+	var err error
+	if v := options.KonnectorMinimumVersion; v != "" {
+		if minimum, err = parseKonnectorVersion(v); err != nil {
+			return fmt.Errorf("invalid --konnector-minimum-version: %w", err)
+		}
+	}
+	if v := options.KonnectorRecommendedVersion; v != "" {
+		if recommended, err = parseKonnectorVersion(v); err != nil {
+			return fmt.Errorf("invalid --konnector-recommended-version: %w", err)
+		}
+		if options.KonnectorMinimumVersion != "" && recommended.LT(minimum) {
+			return fmt.Errorf("--konnector-recommended-version must not be older than --konnector-minimum-version")
+		}
+	}
+
 	if options.ExternalAddress != "" {
 		if !strings.HasPrefix(options.ExternalAddress, "https://") {
 			return fmt.Errorf("external hostname must start with https://")
@@ -170,6 +295,24 @@ func (options *CompletedOptions) Validate() error {
 			return fmt.Errorf("invalid external hostname: %v", err)
 		}
 	}
+	if len(options.ExternalFallbacks) > 0 && options.ExternalAddress == "" {
+		return fmt.Errorf("external fallbacks require --external-address")
+	}
+	if err := validateExternalFallbacks(options.ExternalFallbacks, options.ExternalAddress); err != nil {
+		return err
+	}
+
+	if err := options.validateCredentialIssuers(); err != nil {
+		return err
+	}
 
 	return nil
 }
+
+// parseKonnectorVersion parses a konnector version like v0.4.0.
+func parseKonnectorVersion(s string) (semver.Version, error) {
+	if !strings.HasPrefix(s, "v") {
+		return semver.Version{}, fmt.Errorf("version %q must start with v", s)
+	}
+	return semver.Parse(strings.TrimPrefix(s, "v"))
+}
@@ -19,29 +19,48 @@ package backend
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/legacyregistry"
+	componentbaseversion "k8s.io/component-base/version"
 	"k8s.io/klog/v2"
 
+	"github.com/kube-bind/kube-bind/contrib/example-backend/bff"
+	"github.com/kube-bind/kube-bind/contrib/example-backend/catalog"
 	"github.com/kube-bind/kube-bind/contrib/example-backend/controllers/clusterbinding"
+	"github.com/kube-bind/kube-bind/contrib/example-backend/controllers/namespacereaper"
+	"github.com/kube-bind/kube-bind/contrib/example-backend/controllers/schemarollout"
 	"github.com/kube-bind/kube-bind/contrib/example-backend/controllers/serviceexport"
 	"github.com/kube-bind/kube-bind/contrib/example-backend/controllers/serviceexportrequest"
 	"github.com/kube-bind/kube-bind/contrib/example-backend/controllers/servicenamespace"
+	"github.com/kube-bind/kube-bind/contrib/example-backend/cookie"
 	"github.com/kube-bind/kube-bind/contrib/example-backend/deploy"
+	"github.com/kube-bind/kube-bind/contrib/example-backend/devoidc"
 	examplehttp "github.com/kube-bind/kube-bind/contrib/example-backend/http"
 	examplekube "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes"
+	kuberesources "github.com/kube-bind/kube-bind/contrib/example-backend/kubernetes/resources"
+	"github.com/kube-bind/kube-bind/contrib/example-backend/options"
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/migration"
+	bindversion "github.com/kube-bind/kube-bind/pkg/version"
 )
 
 type Server struct {
 	Config *Config
 
 	OIDC       *examplehttp.OIDCServiceProvider
+	DevIssuer  *devoidc.Issuer // only with --dev
 	Kubernetes *examplekube.Manager
 	WebServer  *examplehttp.Server
+	CookieKeys *cookie.KeySet
 
 	Controllers
 }
@@ -51,6 +70,8 @@ type Controllers struct {
 	ServiceNamespace     *servicenamespace.Controller
 	ServiceExport        *serviceexport.Controller
 	ServiceExportRequest *serviceexportrequest.Controller
+	NamespaceReaper      *namespacereaper.Controller
+	SchemaRollout        *schemarollout.Controller
 }
 
 func NewServer(config *Config) (*Server, error) {
@@ -69,22 +90,55 @@ func NewServer(config *Config) (*Server, error) {
 	if callback == "" {
 		callback = fmt.Sprintf("http://%s/callback", s.WebServer.Addr().String())
 	}
+	issuerURL := config.Options.OIDC.IssuerURL
+	if config.Options.Dev {
+		s.DevIssuer, err = devoidc.Start(config.Options.DevIssuerAddress)
+		if err != nil {
+			return nil, fmt.Errorf("error starting development OIDC issuer: %w", err)
+		}
+		issuerURL = s.DevIssuer.URL
+		klog.Background().Info("Started development OIDC issuer, every login succeeds without user interaction", "url", issuerURL)
+	}
 	s.OIDC, err = examplehttp.NewOIDCServiceProvider(
 		config.Options.OIDC.IssuerClientID,
 		config.Options.OIDC.IssuerClientSecret,
 		callback,
-		config.Options.OIDC.IssuerURL,
+		issuerURL,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error setting up OIDC: %w", err)
 	}
+	issuers := map[string]examplekube.CredentialIssuer{}
+	for _, issuer := range config.Options.CredentialIssuers {
+		issuers[issuer.Name] = examplekube.CredentialIssuer{
+			NamespacePrefix:       issuer.NamespacePrefix,
+			ExternalAddress:       issuer.ExternalAddress,
+			ExternalCA:            issuer.ExternalCA,
+			ExternalTLSServerName: issuer.TLSExternalServerName,
+			ExternalFallbacks:     fallbackEndpoints(issuer.ExternalFallbacks),
+			TokenOptions: kuberesources.TokenOptions{
+				Lifetime:  issuer.TokenLifetime,
+				Audiences: issuer.TokenAudiences,
+			},
+		}
+	}
 	s.Kubernetes, err = examplekube.NewKubernetesManager(
-		config.Options.NamespacePrefix,
 		config.Options.PrettyName,
 		config.ClientConfig,
-		config.Options.ExternalAddress,
-		config.Options.ExternalCA,
-		config.Options.TLSExternalServerName,
+		examplekube.CredentialIssuer{
+			NamespacePrefix:       config.Options.NamespacePrefix,
+			ExternalAddress:       config.Options.ExternalAddress,
+			ExternalCA:            config.Options.ExternalCA,
+			ExternalTLSServerName: config.Options.TLSExternalServerName,
+			ExternalFallbacks:     fallbackEndpoints(config.Options.ExternalFallbacks),
+			TokenOptions: kuberesources.TokenOptions{
+				Lifetime:  config.Options.TokenLifetime,
+				Audiences: config.Options.TokenAudiences,
+			},
+		},
+		issuers,
+		config.Options.TrialDuration,
+		config.Options.TrialMaxBindings,
 		config.KubeInformers.Core().V1().Namespaces(),
 		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
 	)
@@ -92,38 +146,122 @@ func NewServer(config *Config) (*Server, error) {
 		return nil, fmt.Errorf("error setting up Kubernetes Manager: %w", err)
 	}
 
-	signingKey, err := base64.StdEncoding.DecodeString(config.Options.Cookie.SigningKey)
+	var staticKeys []cookie.KeyPair
+	if config.Options.Cookie.SigningKey != "" {
+		signingKey, err := base64.StdEncoding.DecodeString(config.Options.Cookie.SigningKey)
+		if err != nil {
+			return nil, fmt.Errorf("error creating signing key: %w", err)
+		}
+		var encryptionKey []byte
+		if config.Options.Cookie.EncryptionKey != "" {
+			encryptionKey, err = base64.StdEncoding.DecodeString(config.Options.Cookie.EncryptionKey)
+			if err != nil {
+				return nil, fmt.Errorf("error creating encryption key: %w", err)
+			}
+		}
+		staticKeys = append(staticKeys, cookie.KeyPair{Version: 0, SigningKey: signingKey, EncryptionKey: encryptionKey})
+	}
+	s.CookieKeys, err = cookie.NewKeySet(staticKeys...)
 	if err != nil {
-		return nil, fmt.Errorf("error creating signing key: %w", err)
+		return nil, fmt.Errorf("error setting up cookie keys: %w", err)
 	}
-
-	var encryptionKey []byte
-	if config.Options.Cookie.EncryptionKey != "" {
-		var err error
-		encryptionKey, err = base64.StdEncoding.DecodeString(config.Options.Cookie.EncryptionKey)
-		if err != nil {
-			return nil, fmt.Errorf("error creating encryption key: %w", err)
+	if config.CookieKeysInformers != nil {
+		logger := klog.Background().WithValues("secret", config.Options.Cookie.KeysSecret)
+		update := func(obj interface{}) {
+			secret, ok := obj.(*corev1.Secret)
+			if !ok {
+				return
+			}
+			pairs, err := cookie.KeyPairsFromSecret(secret)
+			if err == nil {
+				err = s.CookieKeys.Update(append(pairs, staticKeys...)...)
+			}
+			if err != nil {
+				logger.Error(err, "failed to update cookie keys, keeping the previous ones")
+				return
+			}
+			logger.Info("Updated cookie keys", "versions", s.CookieKeys.Versions())
 		}
+		config.CookieKeysInformers.Core().V1().Secrets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: update,
+			UpdateFunc: func(_, newObj interface{}) {
+				update(newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if err := s.CookieKeys.Update(staticKeys...); err != nil {
+					logger.Error(err, "failed to update cookie keys")
+					return
+				}
+				logger.Info("Cookie keys secret deleted", "versions", s.CookieKeys.Versions())
+			},
+		})
 	}
 
+	catalogCache := catalog.NewCache(
+		kubebindv1alpha1.Scope(config.Options.ConsumerScope),
+		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
+	)
 	handler, err := examplehttp.NewHandler(
 		s.OIDC,
 		config.Options.OIDC.AuthorizeURL,
 		callback,
 		config.Options.PrettyName,
 		config.Options.TestingAutoSelect,
-		signingKey,
-		encryptionKey,
+		config.Options.Region,
+		config.Options.TrialDuration > 0,
+		s.CookieKeys,
 		kubebindv1alpha1.Scope(config.Options.ConsumerScope),
 		s.Kubernetes,
 		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions().Lister(),
+		catalogCache,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error setting up HTTP Handler: %w", err)
 	}
 	handler.AddRoutes(s.WebServer.Router)
+	if config.Options.EnableBFF || config.Options.EnableDashboard {
+		ver, err := bindversion.BinaryVersion(componentbaseversion.Get().GitVersion)
+		if err != nil {
+			ver = "" // omitted from the overview
+		}
+		var cookieKeysNamespace, cookieKeysName string
+		if config.Options.Cookie.KeysSecret != "" {
+			if cookieKeysNamespace, cookieKeysName, err = config.Options.Cookie.KeysSecretNamespaceName(); err != nil {
+				return nil, err
+			}
+		}
+		authorizer := bff.NewAuthorizer(config.KubeClient)
+		bff.NewHandler(
+			bff.Provider{
+				PrettyName:       config.Options.PrettyName,
+				Version:          ver,
+				Region:           config.Options.Region,
+				ConsumerScope:    config.Options.ConsumerScope,
+				RequireApproval:  config.Options.RequireApproval,
+				CookieKeysSecret: config.Options.Cookie.KeysSecret,
+			},
+			authorizer,
+			catalogCache,
+			config.BindInformers.KubeBind().V1alpha1().ClusterBindings(),
+			config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
+			config.BindInformers.KubeBind().V1alpha1().APIServiceExportRequests(),
+			config.BindInformers.KubeBind().V1alpha1().APIServiceNamespaces(),
+			config.KubeInformers.Core().V1().Namespaces(),
+		).AddRoutes(s.WebServer.Router)
+		bff.NewActions(authorizer, config.KubeClient, config.BindClient, cookieKeysNamespace, cookieKeysName).AddRoutes(s.WebServer.Router)
+		if config.Options.EnableDashboard {
+			bff.AddDashboardRoutes(s.WebServer.Router)
+		}
+	}
 
 	// construct controllers
+	var konnectorVersions *kubebindv1alpha1.KonnectorVersionRequirements
+	if config.Options.KonnectorMinimumVersion != "" || config.Options.KonnectorRecommendedVersion != "" {
+		konnectorVersions = &kubebindv1alpha1.KonnectorVersionRequirements{
+			Minimum:     config.Options.KonnectorMinimumVersion,
+			Recommended: config.Options.KonnectorRecommendedVersion,
+		}
+	}
 	s.ClusterBinding, err = clusterbinding.NewController(
 		config.ClientConfig,
 		kubebindv1alpha1.Scope(config.Options.ConsumerScope),
@@ -133,6 +271,7 @@ func NewServer(config *Config) (*Server, error) {
 		config.KubeInformers.Rbac().V1().ClusterRoleBindings(),
 		config.KubeInformers.Rbac().V1().RoleBindings(),
 		config.KubeInformers.Core().V1().Namespaces(),
+		konnectorVersions,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error setting up ClusterBinding Controller: %v", err)
@@ -152,6 +291,8 @@ func NewServer(config *Config) (*Server, error) {
 	}
 	s.ServiceExport, err = serviceexport.NewController(
 		config.ClientConfig,
+		config.Options.Region,
+		config.Options.GroupAliasSuffix,
 		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
 		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
 	)
@@ -161,6 +302,9 @@ func NewServer(config *Config) (*Server, error) {
 	s.ServiceExportRequest, err = serviceexportrequest.NewController(
 		config.ClientConfig,
 		kubebindv1alpha1.Scope(config.Options.ConsumerScope),
+		config.Options.RequireApproval,
+		config.Options.Region,
+		config.Options.GroupAliasSuffix,
 		config.BindInformers.KubeBind().V1alpha1().APIServiceExportRequests(),
 		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
 		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
@@ -168,6 +312,23 @@ func NewServer(config *Config) (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error setting up ServiceExportRequest Controller: %w", err)
 	}
+	s.NamespaceReaper, err = namespacereaper.NewController(
+		config.ClientConfig,
+		config.BindInformers.KubeBind().V1alpha1().APIServiceNamespaces(),
+		config.KubeInformers.Core().V1().Namespaces(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up NamespaceReaper Controller: %w", err)
+	}
+	s.SchemaRollout, err = schemarollout.NewController(
+		config.ClientConfig,
+		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
+		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
+		config.BindInformers.KubeBind().V1alpha1().ClusterBindings(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up SchemaRollout Controller: %w", err)
+	}
 
 	return s, nil
 }
@@ -180,6 +341,10 @@ func (s *Server) OptionallyStartInformers(ctx context.Context) {
 	s.Config.KubeInformers.Start(ctx.Done())
 	s.Config.BindInformers.Start(ctx.Done())
 	s.Config.ApiextensionsInformers.Start(ctx.Done())
+	if s.Config.CookieKeysInformers != nil {
+		s.Config.CookieKeysInformers.Start(ctx.Done())
+		s.Config.CookieKeysInformers.WaitForCacheSync(ctx.Done())
+	}
 	kubeSynced := s.Config.KubeInformers.WaitForCacheSync(ctx.Done())
 	kubeBindSynced := s.Config.BindInformers.WaitForCacheSync(ctx.Done())
 	apiextensionsSynced := s.Config.ApiextensionsInformers.WaitForCacheSync(ctx.Done())
@@ -191,6 +356,40 @@ func (s *Server) OptionallyStartInformers(ctx context.Context) {
 	)
 }
 
+// fallbackEndpoints converts the external fallbacks of the options into the
+// endpoints written into issued kubeconfigs.
+func fallbackEndpoints(fallbacks []options.ExternalFallback) []kuberesources.Endpoint {
+	var endpoints []kuberesources.Endpoint
+	for _, f := range fallbacks {
+		endpoints = append(endpoints, kuberesources.Endpoint{Address: f.Address, CA: f.CA, TLSServerName: f.TLSServerName})
+	}
+	return endpoints
+}
+
+// OptionallyStartMetricsServer serves Prometheus metrics if a metrics bind address
+// is configured. They are not served on the public router of the OIDC and bind
+// endpoints.
+func (s *Server) OptionallyStartMetricsServer(ctx context.Context) {
+	if s.Config.Options.MetricsBindAddress == "" {
+		return
+	}
+	logger := klog.FromContext(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", legacyregistry.Handler())
+	server := &http.Server{Addr: s.Config.Options.MetricsBindAddress, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close() // nolint:errcheck
+	}()
+	go func() {
+		logger.Info("serving metrics", "address", s.Config.Options.MetricsBindAddress)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(err, "failed to serve metrics")
+		}
+	}()
+}
+
 func (s *Server) Addr() net.Addr {
 	return s.WebServer.Addr()
 }
@@ -201,18 +400,42 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
+	if len(s.CookieKeys.Versions()) == 0 {
+		return fmt.Errorf("no cookie keys found in secret %s", s.Config.Options.Cookie.KeysSecret)
+	}
+
 	if err := deploy.Bootstrap(ctx, s.Config.KubeClient.Discovery(), dynamicClient, sets.NewString()); err != nil {
 		return err
 	}
 
+	if s.Config.Options.MigrateStorage {
+		if err := migration.Migrate(ctx,
+			s.Config.ApiextensionsClient.ApiextensionsV1().CustomResourceDefinitions(),
+			dynamicClient,
+			migration.Migrations,
+			metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "clusterbindings"},
+			metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiserviceexports"},
+			metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiservicenamespaces"},
+			metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiserviceexportrequests"},
+			metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiserviceactions"},
+		); err != nil {
+			return err
+		}
+	}
+
 	// start controllers
 	go s.Controllers.ServiceExport.Start(ctx, 1)
 	go s.Controllers.ServiceNamespace.Start(ctx, 1)
 	go s.Controllers.ClusterBinding.Start(ctx, 1)
 	go s.Controllers.ServiceExportRequest.Start(ctx, 1)
+	go s.Controllers.NamespaceReaper.Start(ctx, 1)
+	go s.Controllers.SchemaRollout.Start(ctx, 1)
 
 	go func() {
 		<-ctx.Done()
+		if s.DevIssuer != nil {
+			s.DevIssuer.Close()
+		}
 	}()
 	return s.WebServer.Start(ctx)
 }
@@ -82,6 +82,38 @@ func Create(ctx context.Context, client apiextensionsv1client.CustomResourceDefi
 	return CreateFromFS(ctx, client, raw, grs...)
 }
 
+// WaitForEstablished waits until the given CRDs, installed by someone else,
+// exist and are established. This call is blocking.
+func WaitForEstablished(ctx context.Context, client apiextensionsv1client.CustomResourceDefinitionInterface, grs ...metav1.GroupResource) error {
+	for _, gr := range grs {
+		name := schema.GroupResource{Group: gr.Group, Resource: gr.Resource}.String()
+		logged := false
+		if err := wait.PollImmediateInfiniteWithContext(ctx, time.Second, func(ctx context.Context) (bool, error) {
+			crd, err := client.Get(ctx, name, metav1.GetOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				klog.V(2).Infof("Failed to get CRD %s, retrying: %v", name, err)
+				return false, nil
+			}
+			if err == nil && crdhelpers.IsCRDConditionTrue(crd, apiextensionsv1.Established) {
+				return true, nil
+			}
+			if !logged {
+				klog.Infof("Waiting for CRD %s to be installed and established", name)
+				logged = true
+			}
+			return false, nil
+		}); err != nil {
+			return fmt.Errorf("CRD %s was not established: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Get returns the embedded CRD for the GroupResource specified by gr.
+func Get(gr metav1.GroupResource) (*apiextensionsv1.CustomResourceDefinition, error) {
+	return CRD(raw, gr)
+}
+
 // CreateFromFS creates the given CRD using the target client from the
 // provided filesystem and waits for it to become established. This call is blocking.
 func createSingleFromFS(ctx context.Context, client apiextensionsv1client.CustomResourceDefinitionInterface, gr metav1.GroupResource, fs embed.FS) error {
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// UpgradePolicy decides what happens to existing CRDs whose versions differ from
+// the embedded ones.
+type UpgradePolicy string
+
+const (
+	// UpgradePolicyCreate only creates missing CRDs and leaves existing ones alone.
+	UpgradePolicyCreate UpgradePolicy = "Create"
+	// UpgradePolicyUpdate overwrites existing CRDs with the embedded ones.
+	UpgradePolicyUpdate UpgradePolicy = "Update"
+	// UpgradePolicyFail refuses to touch existing CRDs that differ, and fails.
+	UpgradePolicyFail UpgradePolicy = "Fail"
+)
+
+// Action is what CreateWithPolicy did with a CRD.
+type Action string
+
+const (
+	ActionCreated   Action = "Created"
+	ActionUpdated   Action = "Updated"
+	ActionUnchanged Action = "Unchanged"
+	ActionSkipped   Action = "Skipped"
+	ActionRefused   Action = "Refused"
+)
+
+// ErrUpgradeRefused is returned by CreateWithPolicy for existing CRDs that differ
+// from the embedded ones with UpgradePolicyFail.
+var ErrUpgradeRefused = errors.New("existing CRD differs from the embedded one")
+
+// CreateWithPolicy creates the given CRDs like Create, but existing CRDs whose
+// versions differ from the embedded ones are handled according to policy. record
+// is called with the action taken for every CRD, and the error if it failed.
+func CreateWithPolicy(ctx context.Context, client apiextensionsv1client.CustomResourceDefinitionInterface, policy UpgradePolicy,
+	record func(crd *apiextensionsv1.CustomResourceDefinition, action Action, err error), grs ...metav1.GroupResource,
+) error {
+	var errs []error
+	for _, gr := range grs {
+		rawCRD, err := CRD(raw, gr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		action, err := createWithPolicy(ctx, client, policy, rawCRD)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		record(rawCRD, action, err)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := kerrors.NewAggregate(errs); err != nil {
+		return fmt.Errorf("could not bootstrap CRDs: %w", err)
+	}
+	return nil
+}
+
+func createWithPolicy(ctx context.Context, client apiextensionsv1client.CustomResourceDefinitionInterface, policy UpgradePolicy, rawCRD *apiextensionsv1.CustomResourceDefinition) (Action, error) {
+	var action Action
+	if err := retryRetryableErrors(func() error {
+		existing, err := client.Get(ctx, rawCRD.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			action = ActionCreated
+		case err != nil:
+			return err
+		case equality.Semantic.DeepEqual(existing.Spec.Versions, rawCRD.Spec.Versions):
+			action = ActionUnchanged
+		case policy == UpgradePolicyCreate:
+			action = ActionSkipped
+		case policy == UpgradePolicyFail:
+			action = ActionRefused
+		default:
+			action = ActionUpdated
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("error fetching CRD %s: %w", rawCRD.Name, err)
+	}
+
+	switch action {
+	case ActionRefused:
+		return action, fmt.Errorf("CRD %s: %w", rawCRD.Name, ErrUpgradeRefused)
+	case ActionSkipped:
+		return action, nil
+	}
+	// unchanged CRDs are updated too, e.g. for labels and annotations
+	return action, retryRetryableErrors(func() error {
+		return CreateSingle(ctx, client, rawCRD.DeepCopy())
+	})
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kube-bind/kube-bind/deploy/crd"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/rbac"
+)
+
+//go:embed metrics/*.yaml
+var metrics embed.FS
+
+const metricsPort = 8080
+
+// ManifestOptions customize the konnector installation returned by Manifests.
+type ManifestOptions struct {
+	// Namespace is the namespace the konnector is installed into.
+	Namespace string
+	// Image is the konnector image.
+	Image string
+	// Metrics enables the metrics endpoint of the konnector and adds a Service for it.
+	Metrics bool
+	// ServiceMonitor adds a Prometheus operator ServiceMonitor for the metrics Service.
+	ServiceMonitor bool
+	// LeastPrivilege replaces the cluster-admin like ClusterRole of the konnector with
+	// the permissions it needs independently of bindings. The permissions on bound
+	// resources are reconciled by the konnector itself.
+	LeastPrivilege bool
+}
+
+// Manifest is a named set of objects of the konnector installation.
+type Manifest struct {
+	// Name is the file name of the manifest, e.g. for a kustomization.
+	Name    string
+	Objects []*unstructured.Unstructured
+}
+
+// Manifests returns the full konnector installation, i.e. the namespace, the CRDs,
+// the RBAC, the Deployment and optionally a metrics Service and ServiceMonitor.
+func Manifests(opts ManifestOptions) ([]Manifest, error) {
+	if opts.ServiceMonitor && !opts.Metrics {
+		return nil, errors.New("a ServiceMonitor requires metrics to be enabled")
+	}
+
+	var ret []Manifest
+
+	// CRDs
+	var crds []*unstructured.Unstructured
+	for _, resource := range []string{"apiservicebindings", "backendtrustpolicies"} {
+		c, err := crd.Get(metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: resource})
+		if err != nil {
+			return nil, err
+		}
+		c.APIVersion, c.Kind = "apiextensions.k8s.io/v1", "CustomResourceDefinition"
+		obj, err := toUnstructured(c)
+		if err != nil {
+			return nil, err
+		}
+		crds = append(crds, obj)
+	}
+
+	files, err := raw.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		objs, err := readObjects(raw, f.Name())
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, Manifest{Name: f.Name(), Objects: objs})
+		if f.Name() == "00-namespace.yaml" {
+			// CRDs go right after the namespace
+			ret = append(ret, Manifest{Name: "00-crds.yaml", Objects: crds})
+		}
+	}
+
+	if opts.Metrics {
+		files := []string{"metrics/service.yaml"}
+		if opts.ServiceMonitor {
+			files = append(files, "metrics/servicemonitor.yaml")
+		}
+		for _, name := range files {
+			objs, err := readObjects(metrics, name)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, Manifest{Name: "80-metrics-" + name[len("metrics/"):], Objects: objs})
+		}
+	}
+
+	for _, m := range ret {
+		for i, obj := range m.Objects {
+			if m.Objects[i], err = customize(obj, opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+func readObjects(fs fs.ReadFileFS, name string) ([]*unstructured.Unstructured, error) {
+	bs, err := fs.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*unstructured.Unstructured
+	d := kubeyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(bs)))
+	for {
+		doc, err := d.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		var obj unstructured.Unstructured
+		if err := yaml.Unmarshal(doc, &obj.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		ret = append(ret, &obj)
+	}
+	return ret, nil
+}
+
+func customize(obj *unstructured.Unstructured, opts ManifestOptions) (*unstructured.Unstructured, error) {
+	if obj.GetNamespace() != "" {
+		obj.SetNamespace(opts.Namespace)
+	}
+
+	switch obj.GetKind() {
+	case "Namespace":
+		obj.SetName(opts.Namespace)
+	case "ClusterRole":
+		if !opts.LeastPrivilege || obj.GetName() != "kube-bind-konnector" {
+			break
+		}
+		var role rbacv1.ClusterRole
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &role); err != nil {
+			return nil, err
+		}
+		role.Rules = rbac.BaseRules()
+		return toUnstructured(&role)
+	case "ClusterRoleBinding":
+		subjects, _, err := unstructured.NestedSlice(obj.Object, "subjects")
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range subjects {
+			if s, ok := s.(map[string]interface{}); ok && s["kind"] == "ServiceAccount" {
+				s["namespace"] = opts.Namespace
+			}
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, subjects, "subjects"); err != nil {
+			return nil, err
+		}
+	case "Deployment":
+		var deployment appsv1.Deployment
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deployment); err != nil {
+			return nil, err
+		}
+		for i := range deployment.Spec.Template.Spec.Containers {
+			c := &deployment.Spec.Template.Spec.Containers[i]
+			if c.Name != "konnector" {
+				continue
+			}
+			c.Image = opts.Image
+			if opts.Metrics {
+				c.Args = append(c.Args, fmt.Sprintf("--metrics-bind-address=:%d", metricsPort))
+				c.Ports = append(c.Ports, corev1.ContainerPort{Name: "metrics", ContainerPort: metricsPort, Protocol: corev1.ProtocolTCP})
+			}
+		}
+		return toUnstructured(&deployment)
+	}
+
+	return obj, nil
+}
+
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	ret := &unstructured.Unstructured{Object: u}
+	// drop empty fields of typed objects
+	unstructured.RemoveNestedField(ret.Object, "status")
+	unstructured.RemoveNestedField(ret.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(ret.Object, "spec", "template", "metadata", "creationTimestamp")
+	if strategy, found, _ := unstructured.NestedMap(ret.Object, "spec", "strategy"); found && len(strategy) == 0 {
+		unstructured.RemoveNestedField(ret.Object, "spec", "strategy")
+	}
+	return ret, nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackendConfiguration is the configuration file of the example backend,
+// passed with --config. Flags given on the command line take precedence.
+type BackendConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// kubeconfig is the kubeconfig file of the service provider cluster. Only
+	// required if out-of-cluster.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+
+	// featureGates enables or disables alpha and beta features by name.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// namespacePrefix is the prefix of cluster namespaces.
+	NamespacePrefix string `json:"namespacePrefix,omitempty"`
+	// prettyName is the name of the backend shown to consumers.
+	PrettyName string `json:"prettyName,omitempty"`
+	// consumerScope is Namespaced or Cluster.
+	ConsumerScope string `json:"consumerScope,omitempty"`
+	// region is where data of consumers is stored and processed, e.g. eu.
+	Region string `json:"region,omitempty"`
+	// groupAliasSuffix is a domain owned by the service provider under which
+	// consumers can bind conflicting API groups.
+	GroupAliasSuffix string `json:"groupAliasSuffix,omitempty"`
+	// requireApproval requires APIServiceExportRequests to be approved.
+	RequireApproval *bool `json:"requireApproval,omitempty"`
+	// trialDuration issues anonymous trial bindings expiring after this duration if non-zero.
+	TrialDuration *metav1.Duration `json:"trialDuration,omitempty"`
+	// trialMaxBindings is the maximum number of unexpired trial bindings, zero for unlimited.
+	TrialMaxBindings *int `json:"trialMaxBindings,omitempty"`
+	// migrateStorage rewrites objects of the kube-bind CRDs at startup if needed.
+	MigrateStorage *bool `json:"migrateStorage,omitempty"`
+	// enableBFF serves the backend-for-frontend endpoint for provider dashboards.
+	EnableBFF *bool `json:"enableBFF,omitempty"`
+	// enableDashboard serves the embedded web dashboard for providers. It implies enableBFF.
+	EnableDashboard *bool `json:"enableDashboard,omitempty"`
+
+	// external describes how consumers reach the service provider cluster.
+	External *BackendExternal `json:"external,omitempty"`
+	// tokens configures the credentials issued to konnectors.
+	Tokens *BackendTokens `json:"tokens,omitempty"`
+	// serving configures the web server of the backend.
+	Serving *BackendServing `json:"serving,omitempty"`
+	// oidc configures the OIDC issuer users log in with.
+	OIDC *BackendOIDC `json:"oidc,omitempty"`
+	// cookie configures the keys of the session cookies.
+	Cookie *BackendCookie `json:"cookie,omitempty"`
+	// konnectorVersions are the konnector versions the service provider supports.
+	KonnectorVersions *BackendKonnectorVersions `json:"konnectorVersions,omitempty"`
+	// credentialIssuers are additional issuers of konnector credentials, selected
+	// by exported CRDs with the kube-bind.io/credential-issuer annotation. There
+	// are no flags for them.
+	CredentialIssuers []BackendCredentialIssuer `json:"credentialIssuers,omitempty"`
+}
+
+// BackendExternal describes how consumers reach the service provider cluster.
+type BackendExternal struct {
+	// address is the external address including https:// and port.
+	Address string `json:"address,omitempty"`
+	// caFile is the external CA file.
+	CAFile string `json:"caFile,omitempty"`
+	// serverName is the TLS server name used by consumers, e.g. for SNI.
+	ServerName string `json:"serverName,omitempty"`
+	// fallbacks are further endpoints of the service provider cluster written
+	// into the issued kubeconfigs, e.g. the new address during a control-plane
+	// migration. Konnectors fail over to them in order if the address cannot be
+	// reached. There are no flags for them.
+	Fallbacks []BackendEndpoint `json:"fallbacks,omitempty"`
+}
+
+// BackendEndpoint is a fallback endpoint of the service provider cluster.
+type BackendEndpoint struct {
+	// address is the external address including https:// and port.
+	Address string `json:"address"`
+	// caFile is the CA file of the endpoint. If unset, the CA of the primary
+	// address is used.
+	CAFile string `json:"caFile,omitempty"`
+	// serverName is the TLS server name used by consumers, e.g. for SNI.
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// BackendTokens configures the credentials issued to konnectors.
+type BackendTokens struct {
+	// lifetime of the credentials, at least 10m. Zero issues non-expiring credentials.
+	Lifetime *metav1.Duration `json:"lifetime,omitempty"`
+	// audiences of the credentials.
+	Audiences []string `json:"audiences,omitempty"`
+}
+
+// BackendCredentialIssuer is a named issuer of konnector credentials. Consumers
+// get a service provider namespace of their own per issuer. Unset values are
+// taken from the top-level namespacePrefix, external and tokens. The service
+// accounts of all issuers live in the same service provider cluster and their
+// tokens are signed by the same service account signer.
+type BackendCredentialIssuer struct {
+	// name is referenced by the kube-bind.io/credential-issuer annotation.
+	Name string `json:"name"`
+	// namespacePrefix is the prefix of the service provider namespaces holding
+	// the service accounts of the issued credentials.
+	NamespacePrefix string `json:"namespacePrefix,omitempty"`
+	// external is the address, CA and TLS server name of the service provider
+	// cluster written into the issued kubeconfigs, e.g. a dedicated front proxy.
+	External *BackendExternal `json:"external,omitempty"`
+	// tokens are the lifetime and audiences of the issued credentials.
+	Tokens *BackendTokens `json:"tokens,omitempty"`
+}
+
+// BackendServing configures the web server of the backend.
+type BackendServing struct {
+	ListenAddress string `json:"listenAddress,omitempty"`
+	TLSCertFile   string `json:"tlsCertFile,omitempty"`
+	TLSKeyFile    string `json:"tlsKeyFile,omitempty"`
+	// metricsBindAddress serves Prometheus metrics on a separate listener if set.
+	MetricsBindAddress string `json:"metricsBindAddress,omitempty"`
+}
+
+// BackendOIDC configures the OIDC issuer users log in with.
+type BackendOIDC struct {
+	IssuerURL    string `json:"issuerURL,omitempty"`
+	ClientID     string `json:"clientID,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	CallbackURL  string `json:"callbackURL,omitempty"`
+	AuthorizeURL string `json:"authorizeURL,omitempty"`
+}
+
+// BackendCookie configures the keys of the session cookies.
+type BackendCookie struct {
+	// keysSecret is a <namespace>/<name> of a Secret with versioned cookie keys.
+	KeysSecret string `json:"keysSecret,omitempty"`
+}
+
+// BackendKonnectorVersions are the konnector versions published on ClusterBindings.
+type BackendKonnectorVersions struct {
+	// minimum is the oldest supported konnector version, e.g. v0.4.0.
+	Minimum string `json:"minimum,omitempty"`
+	// recommended is the recommended konnector version, e.g. v0.5.0.
+	Recommended string `json:"recommended,omitempty"`
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KonnectorConfiguration is the configuration file of the konnector, passed
+// with --config. Flags given on the command line take precedence.
+type KonnectorConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// kubeconfig is the kubeconfig file of the local cluster.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// consumerKubeconfigs are kubeconfig files of additional consumer clusters,
+	// each as <path> or <path>#<context>.
+	ConsumerKubeconfigs []string `json:"consumerKubeconfigs,omitempty"`
+	// dryRun sends all writes of the controllers as server-side dry-run, and logs them.
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// featureGates enables or disables alpha and beta features by name.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// leaderElection configures the leader election between konnector replicas.
+	LeaderElection *KonnectorLeaderElection `json:"leaderElection,omitempty"`
+	// endpoints are the addresses of the metrics, probe, debug and webhook servers.
+	Endpoints *KonnectorEndpoints `json:"endpoints,omitempty"`
+	// tracing exports OpenTelemetry traces of the sync of bound objects.
+	Tracing *KonnectorTracing `json:"tracing,omitempty"`
+	// crds configures the installation of the kube-bind CRDs and the CRDs of bound resources.
+	CRDs *KonnectorCRDs `json:"crds,omitempty"`
+	// clientConnection rate limits the clients against the consumer and the service provider clusters.
+	ClientConnection *KonnectorClientConnection `json:"clientConnection,omitempty"`
+	// sync tunes the spec and status syncing of bound objects.
+	Sync *KonnectorSync `json:"sync,omitempty"`
+	// workers tunes the concurrency of the controllers other than the syncers.
+	Workers *KonnectorWorkers `json:"workers,omitempty"`
+	// workqueue configures the retries of failed items of the controllers.
+	Workqueue *KonnectorWorkqueue `json:"workqueue,omitempty"`
+	// policy restricts what is bound and synced.
+	Policy *KonnectorPolicy `json:"policy,omitempty"`
+	// provider configures the connections to service provider clusters.
+	Provider *KonnectorProvider `json:"provider,omitempty"`
+	// selfUpgrade configures the upgrade of the konnector to the versions
+	// required by the service providers.
+	SelfUpgrade *KonnectorSelfUpgrade `json:"selfUpgrade,omitempty"`
+}
+
+// KonnectorLeaderElection configures the leader election between konnector replicas.
+type KonnectorLeaderElection struct {
+	// leaderElect makes replicas acquire a Lease before starting the controllers.
+	LeaderElect *bool `json:"leaderElect,omitempty"`
+	// sharding runs all replicas active-active instead, each serving a subset of
+	// the APIServiceBindings.
+	Sharding *bool `json:"sharding,omitempty"`
+	// resourceName is the name of the Lease.
+	ResourceName string `json:"resourceName,omitempty"`
+	// resourceNamespace is the namespace of the Lease.
+	ResourceNamespace string `json:"resourceNamespace,omitempty"`
+	// leaseDuration is how long standby replicas wait before taking over a lease.
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+	// renewDeadline is how long the leader retries renewing the lease.
+	RenewDeadline *metav1.Duration `json:"renewDeadline,omitempty"`
+	// retryPeriod is the interval between attempts to acquire or renew the lease.
+	RetryPeriod *metav1.Duration `json:"retryPeriod,omitempty"`
+}
+
+// KonnectorEndpoints are the addresses of the servers of the konnector. An
+// empty string disables the respective server.
+type KonnectorEndpoints struct {
+	MetricsBindAddress     *string `json:"metricsBindAddress,omitempty"`
+	HealthProbeBindAddress *string `json:"healthProbeBindAddress,omitempty"`
+	DebugAddress           *string `json:"debugAddress,omitempty"`
+	WebhookBindAddress     *string `json:"webhookBindAddress,omitempty"`
+	WebhookCertDir         string  `json:"webhookCertDir,omitempty"`
+	ManagementBindAddress  *string `json:"managementBindAddress,omitempty"`
+	// managementDashboard serves the read-only consumer dashboard on the
+	// management address.
+	ManagementDashboard *bool `json:"managementDashboard,omitempty"`
+}
+
+// KonnectorTracing configures the OpenTelemetry tracing of the konnector.
+type KonnectorTracing struct {
+	// endpoint is the OTLP gRPC endpoint the spans are exported to. Empty
+	// disables tracing.
+	Endpoint string `json:"endpoint,omitempty"`
+	// samplingRatePerMillion is the number of syncs per million that are traced.
+	SamplingRatePerMillion *int32 `json:"samplingRatePerMillion,omitempty"`
+}
+
+// KonnectorCRDs configures the CRD handling of the konnector.
+type KonnectorCRDs struct {
+	// install makes the konnector install and upgrade its CRDs at startup.
+	Install *bool `json:"install,omitempty"`
+	// upgradePolicy is Update, Create or Fail.
+	UpgradePolicy string `json:"upgradePolicy,omitempty"`
+	// conflictPolicy is Fail or Warn.
+	ConflictPolicy string `json:"conflictPolicy,omitempty"`
+	// migrateStorage rewrites objects of the kube-bind CRDs at startup if needed.
+	MigrateStorage *bool `json:"migrateStorage,omitempty"`
+	// allowlist are the CRDs of bound API services that may be created and
+	// updated, by name or as *.<group>.
+	Allowlist []string `json:"allowlist,omitempty"`
+	// syncedPrinterColumns adds Synced and Provider printer columns to the CRDs
+	// of bound resources.
+	SyncedPrinterColumns *bool `json:"syncedPrinterColumns,omitempty"`
+}
+
+// KonnectorClientConnection rate limits the clients of the konnector.
+type KonnectorClientConnection struct {
+	// consumer rate limits the client against the consumer cluster.
+	Consumer *ClientConnection `json:"consumer,omitempty"`
+	// provider rate limits the clients against each service provider cluster.
+	Provider *ClientConnection `json:"provider,omitempty"`
+}
+
+// ClientConnection is the rate limit of a client. Zero uses the client-go defaults.
+type ClientConnection struct {
+	QPS   *float32 `json:"qps,omitempty"`
+	Burst *int     `json:"burst,omitempty"`
+}
+
+// KonnectorSync tunes the syncing of bound objects.
+type KonnectorSync struct {
+	// spec tunes the syncing of consumer objects to the service provider, per binding.
+	Spec *SyncController `json:"spec,omitempty"`
+	// status tunes the syncing of the status of service provider objects to the consumer, per binding.
+	Status *SyncController `json:"status,omitempty"`
+
+	// syncedConditionMaxStaleness maintains a kube-bind.io/Synced condition on
+	// bound objects if non-zero.
+	SyncedConditionMaxStaleness *metav1.Duration `json:"syncedConditionMaxStaleness,omitempty"`
+	// hibernateIdleBindingsAfter stops the informers of idle bindings if non-zero.
+	HibernateIdleBindingsAfter *metav1.Duration `json:"hibernateIdleBindingsAfter,omitempty"`
+	// canaryInterval probes the sync path of bindings with a canary object if non-zero.
+	CanaryInterval *metav1.Duration `json:"canaryInterval,omitempty"`
+	// snapshotDir persists which bound objects are in sync.
+	SnapshotDir string `json:"snapshotDir,omitempty"`
+	// resyncPeriod is the resync period of the informers of the konnector, except
+	// the dynamic informers of bound resources in service provider clusters.
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+	// providerResyncPeriod is the resync period of the dynamic informers of bound
+	// resources in service provider clusters.
+	ProviderResyncPeriod *metav1.Duration `json:"providerResyncPeriod,omitempty"`
+	// shutdownGracePeriod is how long to wait for in-flight syncs on termination.
+	ShutdownGracePeriod *metav1.Duration `json:"shutdownGracePeriod,omitempty"`
+}
+
+// SyncController tunes one direction of the syncing.
+type SyncController struct {
+	Workers *int     `json:"workers,omitempty"`
+	QPS     *float32 `json:"qps,omitempty"`
+	Burst   *int     `json:"burst,omitempty"`
+}
+
+// KonnectorWorkers tunes the concurrency of the controllers.
+type KonnectorWorkers struct {
+	// default is the number of workers of the konnector controller and of the
+	// controllers of each service provider cluster.
+	Default *int `json:"default,omitempty"`
+	// serviceBinding is the number of workers of the APIServiceBinding controllers.
+	ServiceBinding *int `json:"serviceBinding,omitempty"`
+}
+
+// KonnectorWorkqueue configures the retries of failed items of the controllers.
+type KonnectorWorkqueue struct {
+	// baseDelay is the delay of the first retry of a failed item. It doubles with
+	// every further failure up to maxDelay.
+	BaseDelay *metav1.Duration `json:"baseDelay,omitempty"`
+	// maxDelay is the maximum delay between retries of a failed item.
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+	// qps and burst limit the overall rate of retries of each controller.
+	QPS   *float64 `json:"qps,omitempty"`
+	Burst *int     `json:"burst,omitempty"`
+}
+
+// KonnectorPolicy restricts what the konnector binds and syncs.
+type KonnectorPolicy struct {
+	// upsyncPoliciesFile is a YAML file with CEL policies for consumer objects.
+	UpsyncPoliciesFile string `json:"upsyncPoliciesFile,omitempty"`
+	// allowedRegions are the regions of service providers that bound resources
+	// may be synced to.
+	AllowedRegions []string `json:"allowedRegions,omitempty"`
+	// watchNamespaces restricts list/watch of namespaced consumer objects.
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+	// requireBindingApproval only syncs approved APIServiceBindings.
+	RequireBindingApproval *bool `json:"requireBindingApproval,omitempty"`
+	// rbacClusterRole is the ClusterRole granted to the konnector, reported on
+	// if it grants more than needed.
+	RBACClusterRole *string `json:"rbacClusterRole,omitempty"`
+}
+
+// KonnectorProvider configures the connections to service provider clusters.
+type KonnectorProvider struct {
+	// proxyURL is an http, https or socks5 proxy URL.
+	ProxyURL string `json:"proxyURL,omitempty"`
+	// caFile is a PEM CA bundle file trusted for all service provider clusters.
+	CAFile string `json:"caFile,omitempty"`
+	// caConfigMap is a <namespace>/<name> of a ConfigMap with a PEM CA bundle.
+	CAConfigMap string `json:"caConfigMap,omitempty"`
+}
+
+// KonnectorSelfUpgrade configures the upgrade of the konnector Deployment.
+type KonnectorSelfUpgrade struct {
+	// enabled updates the image of the konnector Deployment if a service provider
+	// requires or recommends a newer konnector.
+	Enabled *bool `json:"enabled,omitempty"`
+	// image is the konnector image repository, e.g. of a registry mirror.
+	Image string `json:"image,omitempty"`
+	// deployment is the name of the konnector Deployment in the lease namespace.
+	Deployment string `json:"deployment,omitempty"`
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// LoadKonnectorConfiguration reads a KonnectorConfiguration from a YAML or JSON file.
+func LoadKonnectorConfiguration(path string) (*KonnectorConfiguration, error) {
+	var config KonnectorConfiguration
+	if err := load(path, "KonnectorConfiguration", &config, &config.TypeMeta); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// LoadBackendConfiguration reads a BackendConfiguration from a YAML or JSON file.
+func LoadBackendConfiguration(path string) (*BackendConfiguration, error) {
+	var config BackendConfiguration
+	if err := load(path, "BackendConfiguration", &config, &config.TypeMeta); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func load(path, kind string, obj interface{}, typeMeta *metav1.TypeMeta) error {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.UnmarshalStrict(bs, obj); err != nil {
+		return fmt.Errorf("failed to decode config file %q: %w", path, err)
+	}
+	if typeMeta.APIVersion != SchemeGroupVersion.String() || typeMeta.Kind != kind {
+		return fmt.Errorf("config file %q must be of apiVersion %q and kind %q, got %q and %q", path, SchemeGroupVersion, kind, typeMeta.APIVersion, typeMeta.Kind)
+	}
+	return nil
+}
+
+// Override sets *dst to *value if value is set and the flag has not been given
+// on the command line, which takes precedence over the configuration file.
+func Override[T any](fs *pflag.FlagSet, flag string, dst *T, value *T) {
+	if value != nil && !fs.Changed(flag) {
+		*dst = *value
+	}
+}
+
+// OverrideString is like Override for strings, with empty meaning unset.
+func OverrideString(fs *pflag.FlagSet, flag string, dst *string, value string) {
+	if value != "" {
+		Override(fs, flag, dst, &value)
+	}
+}
+
+// OverrideSlice is like Override for slices, with empty meaning unset.
+func OverrideSlice[T any](fs *pflag.FlagSet, flag string, dst *[]T, value []T) {
+	if len(value) > 0 {
+		Override(fs, flag, dst, &value)
+	}
+}
+
+// OverrideDuration is like Override for durations.
+func OverrideDuration(fs *pflag.FlagSet, flag string, dst *time.Duration, value *metav1.Duration) {
+	if value != nil {
+		Override(fs, flag, dst, &value.Duration)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ActionType is an action the service provider can request on a consumer object.
+//
+// +kubebuilder:validation:Enum=Restart
+type ActionType string
+
+const (
+	// ActionTypeRestart restarts the pods of a Deployment, StatefulSet or DaemonSet
+	// in the consumer cluster, like "kubectl rollout restart".
+	ActionTypeRestart ActionType = "Restart"
+)
+
+// ActionPhase is the outcome of an APIServiceAction.
+//
+// +kubebuilder:validation:Enum=Succeeded;Denied;Failed
+type ActionPhase string
+
+const (
+	// ActionPhaseSucceeded means the konnector executed the action.
+	ActionPhaseSucceeded ActionPhase = "Succeeded"
+	// ActionPhaseDenied means the consumer did not allow the action in the APIServiceBinding.
+	ActionPhaseDenied ActionPhase = "Denied"
+	// ActionPhaseFailed means the action was allowed, but could not be executed.
+	ActionPhaseFailed ActionPhase = "Failed"
+)
+
+// APIServiceAction is a request of the service provider to act on an object in
+// the consumer cluster, e.g. to restart a workload. It is created by the service
+// provider in the cluster namespace, and executed once by the konnector if the
+// consumer allowed the action in the APIServiceBinding of the export. The
+// outcome is reported in status.phase.
+//
+// +crd
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Namespaced,categories=kube-bindings
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Export",type="string",JSONPath=`.spec.export`,priority=0
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=`.spec.action`,priority=0
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`,priority=0
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`,priority=0
+type APIServiceAction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec specifies the action.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+	Spec APIServiceActionSpec `json:"spec"`
+
+	// status contains the outcome of the action.
+	Status APIServiceActionStatus `json:"status,omitempty"`
+}
+
+// APIServiceActionSpec specifies an action on a consumer object.
+type APIServiceActionSpec struct {
+	// export is the name of the APIServiceExport the action is requested for. The
+	// consumer policy of its APIServiceBinding applies.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Export string `json:"export"`
+
+	// action is the action to execute.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Action ActionType `json:"action"`
+
+	// target is the consumer object the action is executed on.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Target ActionTarget `json:"target"`
+}
+
+// ActionTarget references an object in the consumer cluster.
+type ActionTarget struct {
+	// group is the API group of the object, e.g. apps.
+	//
+	// +optional
+	Group string `json:"group"`
+
+	// resource is the plural lower-case resource name, e.g. deployments.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+
+	// namespace is the consumer namespace of the object.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// name is the name of the object.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// APIServiceActionStatus is the outcome of an action.
+type APIServiceActionStatus struct {
+	// phase is the outcome of the action. It is empty while the action is pending.
+	//
+	// +optional
+	Phase ActionPhase `json:"phase,omitempty"`
+
+	// message explains the phase.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// completionTime is when the action was executed or denied.
+	//
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// APIServiceActionList is the list of APIServiceActions.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type APIServiceActionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []APIServiceAction `json:"items"`
+}
@@ -40,9 +40,112 @@ const (
 	// schema is applied to the consumer cluster.
 	APIServiceBindingConditionSchemaInSync conditionsapi.ConditionType = "SchemaInSync"
 
+	// APIServiceBindingConditionCapabilitiesSatisfied is set to true when the APIServiceExport
+	// advertises all capabilities required by the APIServiceBinding.
+	APIServiceBindingConditionCapabilitiesSatisfied conditionsapi.ConditionType = "CapabilitiesSatisfied"
+
+	// APIServiceBindingConditionSLOMet is set by the konnector if spec.slo is set. It is
+	// false when the canary probes burn the error budget faster than it is refilled,
+	// i.e. when the service provider consistently misses the sync-latency target.
+	APIServiceBindingConditionSLOMet conditionsapi.ConditionType = "SLOMet"
+
+	// APIServiceBindingConditionBackendTrusted is set to false when the service provider
+	// backend of the kubeconfig secret is not trusted by any BackendTrustPolicy. The
+	// konnector does not sync untrusted bindings.
+	APIServiceBindingConditionBackendTrusted conditionsapi.ConditionType = "BackendTrusted"
+
+	// APIServiceBindingConditionApproved is set by a konnector that requires approvals
+	// for bindings. It is false until the binding has the kube-bind.io/approved-by
+	// annotation, and the konnector does not sync before.
+	APIServiceBindingConditionApproved conditionsapi.ConditionType = "Approved"
+
+	// APIServiceBindingConditionProviderThrottled is true while the konnector slows down
+	// the upsync because the service provider signals overload, either by the
+	// kube-bind.io/backpressure annotation on the APIServiceExport or by 429 responses.
+	// It is removed when the upsync runs at full speed again.
+	APIServiceBindingConditionProviderThrottled conditionsapi.ConditionType = "ProviderThrottled"
+
+	// APIServiceBindingConditionTrialActive is set by the konnector if the ClusterBinding
+	// is a trial binding. It is false when the trial has expired.
+	APIServiceBindingConditionTrialActive conditionsapi.ConditionType = "TrialActive"
+
+	// APIServiceBindingConditionProviderNotice is true while the service provider
+	// publishes incident or maintenance notices about the APIServiceExport. It does
+	// not make the binding unready, and is removed when the notices end.
+	APIServiceBindingConditionProviderNotice conditionsapi.ConditionType = "ProviderNotice"
+
+	// APIServiceBindingConditionPaused is true while the sync is stopped by the
+	// kube-bind.io/paused annotation. It does not make the binding unready.
+	APIServiceBindingConditionPaused conditionsapi.ConditionType = "Paused"
+
+	// APIServiceBindingConditionVirtualClusterValid is set by the konnector if
+	// spec.virtualCluster is set, or if the binding targets another cluster than
+	// the APIServiceBindings it shares the kubeconfig secret with.
+	APIServiceBindingConditionVirtualClusterValid conditionsapi.ConditionType = "VirtualClusterValid"
+
+	// ApprovedByAnnotationKey is set on APIServiceBindings by the approver, usually a member of
+	// the platform team. Admission should restrict who may set it, e.g. with the
+	// ValidatingAdmissionPolicy in deploy/examples.
+	ApprovedByAnnotationKey = "kube-bind.io/approved-by"
+
+	// PausedAnnotationKey set to "true" on an APIServiceBinding stops the spec and
+	// status sync of its objects, e.g. during maintenance of the service provider or
+	// incident response. Nothing is deleted, and the sync resumes with a full
+	// resync when the annotation is removed.
+	PausedAnnotationKey = "kube-bind.io/paused"
+
+	// RequiredCapabilitiesAnnotationKey is a comma separated list of APIServiceExport capabilities
+	// the consumer relies on. A warning is shown if the service provider does not advertise them.
+	RequiredCapabilitiesAnnotationKey = "kube-bind.io/required-capabilities"
+
+	// RelayedEventAnnotationKey is set by the konnector on Events it relayed between
+	// service provider and consumer cluster to "provider" or "consumer", the origin of
+	// the Event. Relayed Events are never relayed back.
+	RelayedEventAnnotationKey = "kube-bind.io/relayed-from"
+	// RelayedFromProvider is the RelayedEventAnnotationKey value of Events downsynced from the service provider.
+	RelayedFromProvider = "provider"
+	// RelayedFromConsumer is the RelayedEventAnnotationKey value of Events upsynced from the consumer.
+	RelayedFromConsumer = "consumer"
+
+	// BindingAnnotationKey is set by the konnector on synced downstream objects to the
+	// name of the APIServiceBinding they are bound by.
+	BindingAnnotationKey = "kube-bind.io/binding"
+
+	// ExportUIDLabelKey is set by the konnector on synced downstream objects to the UID
+	// of the APIServiceExport in the service provider cluster.
+	ExportUIDLabelKey = "kube-bind.io/export-uid"
+
+	// ProviderLabelKey is set by the konnector on synced downstream objects to a hash of
+	// the service provider cluster URL, as computed by helpers.ProviderHash. Together with
+	// ExportUIDLabelKey, it tells which service provider manages an object.
+	ProviderLabelKey = "kube-bind.io/provider"
+
+	// PrunedUpstreamFieldsAnnotationKey is set by the konnector on downstream objects of
+	// APIServiceBindings with PruneAndReport field validation to the comma separated
+	// paths of the spec fields the service provider dropped from the upstream object.
+	PrunedUpstreamFieldsAnnotationKey = "kube-bind.io/pruned-upstream-fields"
+
+	// PrunedDownstreamFieldsAnnotationKey is set by the konnector on downstream objects of
+	// APIServiceBindings with PruneAndReport field validation to the comma separated
+	// paths of the upstream status fields the consumer cluster dropped.
+	PrunedDownstreamFieldsAnnotationKey = "kube-bind.io/pruned-downstream-fields"
+
 	// DownstreamFinalizer is put on downstream objects to block their deletion until
 	// the upstream object has been deleted.
 	DownstreamFinalizer = "kubebind.io/syncer"
+
+	// ProviderMessageConditionType is the condition type put on downstream objects when
+	// the service provider rejected or failed to reconcile the upstream object.
+	ProviderMessageConditionType = "kube-bind.io/provider-message"
+
+	// StatusTruncatedConditionType is the condition type put on downstream objects when
+	// the upstream status was too large and has been truncated.
+	StatusTruncatedConditionType = "kube-bind.io/status-truncated"
+
+	// SyncedConditionType is the condition type optionally put on downstream objects by the
+	// konnector. It is true when the service provider acknowledged the current spec and the
+	// status reflects it, e.g. for `kubectl wait --for=condition=kube-bind.io/Synced`.
+	SyncedConditionType = "kube-bind.io/Synced"
 )
 
 // APIServiceBinding binds an API service represented by a APIServiceExport
@@ -87,17 +190,360 @@ type APIServiceBindingSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="kubeconfigSecretRef is immutable"
 	KubeconfigSecretRef ClusterSecretKeyRef `json:"kubeconfigSecretRef"`
+
+	// adoption controls what happens to objects that already existed in the consumer
+	// cluster before the binding was created, e.g. left behind by a previous local
+	// operator. With Ignore (the default) they are not touched. With Adopt they are
+	// upsynced to the service provider like any newly created object.
+	//
+	// +optional
+	Adoption AdoptionPolicy `json:"adoption,omitempty"`
+
+	// fieldValidation controls how synced objects with fields unknown to the schema
+	// of the counterpart cluster are treated, e.g. during version skew between
+	// consumer and service provider. With Prune (the default) the unknown fields are
+	// dropped silently. With PruneAndReport they are dropped too, but the dropped
+	// fields are listed in the kube-bind.io/pruned-upstream-fields and
+	// kube-bind.io/pruned-downstream-fields annotations of the consumer object.
+	// With Strict the objects are rejected instead, not synced, and
+	// the rejection is reported in the kube-bind.io/provider-message condition of the
+	// consumer object. Strict requires server-side field validation in both clusters.
+	//
+	// +optional
+	FieldValidation FieldValidationPolicy `json:"fieldValidation,omitempty"`
+
+	// virtualCluster makes bound CRDs and objects materialize inside a virtual
+	// cluster, e.g. a vcluster, while the APIServiceBinding and the konnector live
+	// in the host cluster. All APIServiceBindings using the same kubeconfig secret
+	// must target the same virtual cluster.
+	//
+	// The konnector talks to the virtual cluster API directly, i.e. objects are
+	// synced with the namespaces as they are named inside the virtual cluster. The
+	// host namespaces the vcluster syncer translates them to are not involved.
+	//
+	// +optional
+	VirtualCluster *VirtualClusterTarget `json:"virtualCluster,omitempty"`
+
+	// sync configures how often and how fresh bound objects are synced for this
+	// binding. If not set, the konnector defaults apply.
+	//
+	// +optional
+	Sync *SyncPolicy `json:"sync,omitempty"`
+
+	// acceptedClaims are the cluster-scoped claims of the APIServiceExport the
+	// consumer consented to. Claims of the export that are not accepted here are
+	// not synced.
+	//
+	// +optional
+	AcceptedClaims []ClusterScopedClaim `json:"acceptedClaims,omitempty"`
+
+	// acceptedServiceAccountTokenClaims are the service account token claims of
+	// the APIServiceExport the consumer consented to, matched by namespace, name
+	// and audience. Tokens of claims that are not accepted here are not issued.
+	//
+	// +optional
+	AcceptedServiceAccountTokenClaims []ServiceAccountTokenClaim `json:"acceptedServiceAccountTokenClaims,omitempty"`
+
+	// acceptedEventRelay is the event relay of the APIServiceExport the consumer
+	// consented to. Only Events with the reasons accepted here are downsynced, and
+	// consumer Events are only upsynced if upsync is accepted here too.
+	//
+	// +optional
+	AcceptedEventRelay *EventRelay `json:"acceptedEventRelay,omitempty"`
+
+	// allowedActions are the actions the service provider may request on consumer
+	// objects with APIServiceActions for this binding. Actions that are not allowed
+	// here are denied.
+	//
+	// +optional
+	AllowedActions []ActionPolicy `json:"allowedActions,omitempty"`
+
+	// slo is the sync-latency objective of the binding. It is measured with the
+	// konnector canary probes and reported in the SLOMet condition.
+	//
+	// +optional
+	SLO *SLOPolicy `json:"slo,omitempty"`
+
+	// groupAlias is the API group under which the bound resource is served in the
+	// consumer cluster instead of the group of the APIServiceExport, e.g. because an
+	// unrelated CRD of the same name exists. The konnector translates between the
+	// two groups when syncing.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)+$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="groupAlias is immutable"
+	GroupAlias string `json:"groupAlias,omitempty"`
+
+	// export is the name of the APIServiceExport in the service provider cluster.
+	// It defaults to the name of the binding. It is set if the binding is named
+	// after its group alias, e.g. when binding the same resource of two service
+	// providers under different groups.
+	//
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="export is immutable"
+	Export string `json:"export,omitempty"`
+
+	// schemaRevision pins the bound CustomResourceDefinition to this schema
+	// revision of the APIServiceExport. If empty, the latest revision is bound.
+	// It is rolled forward with "kubectl bind upgrade-schema".
+	//
+	// +optional
+	SchemaRevision string `json:"schemaRevision,omitempty"`
+}
+
+// SLOPolicy is a sync-latency objective, measured by canary probes.
+type SLOPolicy struct {
+	// latencyTarget is the round-trip time a canary probe must stay within.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	LatencyTarget metav1.Duration `json:"latencyTarget"`
+
+	// objective is the percentage of canary probes that must succeed within the
+	// latency target, e.g. "99.5". Defaults to 99.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(100|[0-9]{1,2}(\.[0-9]+)?)$`
+	Objective string `json:"objective,omitempty"`
+
+	// window is the period over which the error budget is computed. Defaults to
+	// 1h. Longer windows than 24h are capped.
+	//
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+}
+
+// SyncPolicy is the sync frequency and freshness target of an APIServiceBinding.
+type SyncPolicy struct {
+	// resyncPeriod is how often all bound objects are reconciled again, independent
+	// of change events. Shorter periods repair missed updates faster at the cost of
+	// more load on both clusters. It overrides the konnector's --resync-period and
+	// --provider-resync-period, which default to 30 minutes.
+	//
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+
+	// maxStaleness is the freshness target: how long the service provider may take to
+	// acknowledge a spec change and reflect it in the status, e.g. 15s. It enables the
+	// kube-bind.io/Synced condition on bound objects, overriding the konnector's
+	// --synced-condition-max-staleness, and objects exceeding it are counted as missed
+	// in the konnector's freshness metrics.
+	//
+	// +optional
+	MaxStaleness *metav1.Duration `json:"maxStaleness,omitempty"`
+
+	// spec tunes the syncing of consumer objects to the service provider, overriding
+	// the konnector's --spec-sync-workers, --spec-sync-qps and --spec-sync-burst.
+	//
+	// +optional
+	Spec *SyncTuning `json:"spec,omitempty"`
+
+	// status tunes the syncing of the status of service provider objects to the
+	// consumer, overriding the konnector's --status-sync-workers, --status-sync-qps
+	// and --status-sync-burst.
+	//
+	// +optional
+	Status *SyncTuning `json:"status,omitempty"`
+
+	// watchNamespaces restricts the consumer objects of a namespaced bound resource
+	// that are synced to these namespaces, overriding the konnector's
+	// --watch-namespaces. Objects in other namespaces are not synced.
+	//
+	// +optional
+	// +listType=set
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+}
+
+// SyncTuning is the concurrency and rate limit of one sync direction of an
+// APIServiceBinding. Unset fields keep the konnector defaults.
+type SyncTuning struct {
+	// workers is the number of concurrent workers.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Workers *int32 `json:"workers,omitempty"`
+
+	// qps is the maximum number of requests per second.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	QPS *int32 `json:"qps,omitempty"`
+
+	// burst is the maximum request burst.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Burst *int32 `json:"burst,omitempty"`
+}
+
+// VirtualClusterTarget is a virtual cluster API that bound CRDs and objects are
+// synced with.
+type VirtualClusterTarget struct {
+	// kubeconfigSecretRef references the kubeconfig of the virtual cluster API.
+	// For vcluster, this is the vc-<name> secret in the host namespace of the
+	// vcluster with the key "config".
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	KubeconfigSecretRef VirtualClusterSecretKeyRef `json:"kubeconfigSecretRef"`
+}
+
+// VirtualClusterSecretKeyRef references a kubeconfig of a virtual cluster.
+type VirtualClusterSecretKeyRef struct {
+	// Namespace of the referent.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Name of the referent.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The key of the secret to select from.
+	//
+	// +optional
+	// +kubebuilder:default=config
+	Key string `json:"key,omitempty"`
 }
 
+// AdoptionPolicy is the treatment of pre-existing consumer objects.
+//
+// +kubebuilder:validation:Enum=Ignore;Adopt
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyIgnore leaves pre-existing consumer objects untouched.
+	AdoptionPolicyIgnore AdoptionPolicy = "Ignore"
+	// AdoptionPolicyAdopt upsyncs pre-existing consumer objects.
+	AdoptionPolicyAdopt AdoptionPolicy = "Adopt"
+)
+
+// FieldValidationPolicy is the treatment of fields unknown to the counterpart cluster.
+//
+// +kubebuilder:validation:Enum=Prune;PruneAndReport;Strict
+type FieldValidationPolicy string
+
+const (
+	// FieldValidationPolicyPrune drops unknown fields silently.
+	FieldValidationPolicyPrune FieldValidationPolicy = "Prune"
+	// FieldValidationPolicyPruneAndReport drops unknown fields and records them on
+	// the consumer object.
+	FieldValidationPolicyPruneAndReport FieldValidationPolicy = "PruneAndReport"
+	// FieldValidationPolicyStrict rejects objects with unknown fields.
+	FieldValidationPolicyStrict FieldValidationPolicy = "Strict"
+)
+
+const (
+	// VirtualClusterManagedByLabel is set by the vcluster syncer on host objects it
+	// translated from a virtual cluster. A konnector syncing the host cluster skips
+	// them. They are synced under their virtual cluster namespace by the
+	// APIServiceBinding targeting the virtual cluster.
+	VirtualClusterManagedByLabel = "vcluster.loft.sh/managed-by"
+)
+
 type APIServiceBindingStatus struct {
 	// providerPrettyName is the pretty name of the service provider cluster. This
 	// can be shared among different APIServiceBindings.
 	ProviderPrettyName string `json:"providerPrettyName,omitempty"`
 
+	// connection is the result of the last credential check against the service
+	// provider cluster, done periodically by the konnector.
+	//
+	// +optional
+	Connection *ConnectionStatus `json:"connection,omitempty"`
+
+	// slo is the error budget consumption of spec.slo over its window.
+	//
+	// +optional
+	SLO *SLOStatus `json:"slo,omitempty"`
+
+	// schemaRevision is the schema revision of the APIServiceExport the
+	// CustomResourceDefinition is bound with.
+	//
+	// +optional
+	SchemaRevision string `json:"schemaRevision,omitempty"`
+
+	// latestSchemaRevision is the latest schema revision the APIServiceExport
+	// publishes.
+	//
+	// +optional
+	LatestSchemaRevision string `json:"latestSchemaRevision,omitempty"`
+
+	// expirationTime is the time the trial binding with the service provider
+	// expires. It is not set for regular bindings.
+	//
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// limits are the limits of the plan with the service provider, enforced by
+	// the optional admission webhook of the konnector.
+	//
+	// +optional
+	Limits *PlanLimits `json:"limits,omitempty"`
+
+	// notices are the current incident and maintenance notices of the service
+	// provider about the APIServiceExport.
+	//
+	// +optional
+	Notices []ProviderNotice `json:"notices,omitempty"`
+
 	// conditions is a list of conditions that apply to the APIServiceBinding.
 	Conditions conditionsapi.Conditions `json:"conditions,omitempty"`
 }
 
+// ConnectionAuthStatus is the outcome of an authenticated call to the service provider cluster.
+//
+// +kubebuilder:validation:Enum=Authorized;Unauthorized;Forbidden;Unreachable
+type ConnectionAuthStatus string
+
+const (
+	// ConnectionAuthorized means the credentials were accepted by the service provider cluster.
+	ConnectionAuthorized ConnectionAuthStatus = "Authorized"
+	// ConnectionUnauthorized means the service provider cluster did not accept the credentials.
+	ConnectionUnauthorized ConnectionAuthStatus = "Unauthorized"
+	// ConnectionForbidden means the credentials were accepted, but lack permissions.
+	ConnectionForbidden ConnectionAuthStatus = "Forbidden"
+	// ConnectionUnreachable means the service provider cluster could not be reached.
+	ConnectionUnreachable ConnectionAuthStatus = "Unreachable"
+)
+
+type ConnectionStatus struct {
+	// lastCheckTime is the time the connection was last checked.
+	LastCheckTime metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// latency is the round-trip time of the last check.
+	Latency metav1.Duration `json:"latency,omitempty"`
+
+	// authStatus is the outcome of the last check.
+	AuthStatus ConnectionAuthStatus `json:"authStatus,omitempty"`
+
+	// message is a human readable message about the last check in case of failure.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SLOStatus is the error budget consumption of an SLOPolicy.
+type SLOStatus struct {
+	// probes is the number of canary probes in the window.
+	Probes int32 `json:"probes"`
+
+	// missed is the number of canary probes in the window that failed or exceeded
+	// the latency target.
+	Missed int32 `json:"missed"`
+
+	// burnRate is the rate the error budget is consumed with, as a decimal, e.g.
+	// "2.50". 1 means the budget is used up exactly at the end of the window.
+	// Above 1, the objective is missed. "Inf" means that the objective allows no
+	// misses, but there were some.
+	BurnRate string `json:"burnRate"`
+}
+
 // APIServiceBindingList is a list of APIServiceBindings.
 //
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -134,3 +580,18 @@ type ClusterSecretKeyRef struct {
 	// +kubebuilder:validation:MinLength=1
 	Namespace string `json:"namespace"`
 }
+
+// ActionPolicy allows the service provider to request an action on consumer objects.
+type ActionPolicy struct {
+	// action is the allowed action.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Action ActionType `json:"action"`
+
+	// namespaces restricts the action to objects in these consumer namespaces.
+	// If empty, objects in all namespaces are allowed.
+	//
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
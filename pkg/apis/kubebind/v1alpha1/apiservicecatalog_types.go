@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// APIServiceCatalog is a non-CRUD resource that is returned by the server without
+// authentication. It lists the resources a service provider exports, and is what
+// kubectl bind list renders.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type APIServiceCatalog struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// resources is the list of exported resources, sorted by group and resource.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Resources []APIServiceCatalogResource `json:"resources,omitempty"`
+}
+
+// APIServiceCatalogResource describes one exported resource. The full schema
+// is served separately per resource.
+type APIServiceCatalogResource struct {
+	// group is the API group of the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Group string `json:"group"`
+
+	// resource is the plural name of the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Resource string `json:"resource"`
+
+	// kind is the kind of the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Kind string `json:"kind"`
+
+	// scope is the scope of the resource, either Cluster or Namespaced.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Scope apiextensionsv1.ResourceScope `json:"scope"`
+
+	// versions is the list of served versions of the resource.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Versions []string `json:"versions,omitempty"`
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -26,6 +27,48 @@ import (
 
 const (
 	SourceSpecHashAnnotationKey = "kube-bind.io/source-spec-hash"
+
+	// ConsumerGenerationAnnotationKey is put on upstream objects and holds the generation of the
+	// downstream object whose spec was synced last. It is used to translate the observedGeneration
+	// of the upstream status into the generation of the downstream object.
+	ConsumerGenerationAnnotationKey = "kube-bind.io/consumer-generation"
+
+	// ResyncAnnotationKey is set by the service provider on an APIServiceExport to notify
+	// konnectors of changes that are not reflected in the spec, e.g. of claims. Konnectors
+	// watch the APIServiceExport and restart the sync of the resource whenever the value
+	// changes. The value is opaque, usually a timestamp.
+	ResyncAnnotationKey = "kube-bind.io/resync"
+
+	// UsageAnnotationKey can be set by the service provider on an APIServiceExport to
+	// report usage of the consumer, e.g. for chargeback. The value is a JSON object of
+	// strings, e.g. {"storage":"10Gi","requests":"1200"}. It is shown by kubectl bind usage.
+	UsageAnnotationKey = "kube-bind.io/usage"
+
+	// BackpressureAnnotationKey can be set by the service provider on an APIServiceExport
+	// while it is overloaded. The value is the minimal time between writes of a consumer,
+	// e.g. 500ms. The konnector slows down the upsync accordingly until it is removed.
+	BackpressureAnnotationKey = "kube-bind.io/backpressure"
+
+	// RegionLabelKey is set by the service provider on an APIServiceExport to the
+	// region where data of consumers is stored and processed, e.g. eu. Konnectors
+	// and kubectl bind refuse exports outside of their allowed regions.
+	RegionLabelKey = "kube-bind.io/region"
+
+	// GroupAliasSuffixAnnotationKey can be set by the service provider on an APIServiceExport
+	// to a domain owned by the provider, e.g. acme.example. Consumers where a CRD of the
+	// export's name already exists bind it under the group <group>.<suffix> instead.
+	GroupAliasSuffixAnnotationKey = "kube-bind.io/group-alias-suffix"
+
+	// CanaryObjectAnnotationKey can be set by the service provider on an APIServiceExport
+	// to opt into the konnector canary probe. The value is the JSON body of a harmless
+	// object of the resource without metadata, e.g. {"spec":{"size":"tiny"}}. Every
+	// occurrence of {{probe}} is replaced with a new value per probe, which makes the
+	// konnector probe updates too.
+	CanaryObjectAnnotationKey = "kube-bind.io/canary-object"
+
+	// CanaryLabelKey is set to "true" on canary probe objects, in both clusters, so that
+	// service provider controllers can skip them.
+	CanaryLabelKey = "kube-bind.io/canary"
 )
 
 const (
@@ -39,6 +82,11 @@ const (
 	// APIServiceExportConditionConsumerInSync is set to true when the APIServiceExport's
 	// schema is applied to the consumer cluster.
 	APIServiceExportConditionConsumerInSync conditionsapi.ConditionType = "ConsumerInSync"
+
+	// APIServiceExportConditionCanaryHealthy is set by the konnector if the canary probe
+	// is enabled. It is true if the last probe object made the round-trip through the
+	// sync in time.
+	APIServiceExportConditionCanaryHealthy conditionsapi.ConditionType = "CanaryHealthy"
 )
 
 // APIServiceExport specifies the resource to be exported. It is mostly a CRD:
@@ -76,6 +124,7 @@ func (in *APIServiceExport) SetConditions(conditions conditionsapi.Conditions) {
 // APIServiceExportSpec defines the desired state of APIServiceExport.
 //
 // +kubebuilder:validation:XValidation:rule=`self.scope == "Namespaced" || self.informerScope == "Cluster"`,message="informerScope is must be Cluster for cluster-scoped resources"
+// +kubebuilder:validation:XValidation:rule=`self.scope == "Namespaced" || !has(self.isolation) || self.isolation == "Namespace"`,message="isolation must be Namespace for cluster-scoped resources"
 type APIServiceExportSpec struct {
 	APIServiceExportCRDSpec `json:",inline"`
 
@@ -90,8 +139,359 @@ type APIServiceExportSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="informerScope is immutable"
 	InformerScope Scope `json:"informerScope"`
+
+	// isolation is how consumer objects are mapped to service provider namespaces.
+	// With Namespace, all objects of a consumer namespace share one service provider
+	// namespace. With Object, every consumer object gets its own service provider
+	// namespace with its own RBAC, which is deleted together with the object. This is
+	// meant for highly sensitive resources and costs a namespace per object.
+	//
+	// +optional
+	// +kubebuilder:default=Namespace
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="isolation is immutable"
+	Isolation Isolation `json:"isolation,omitempty"`
+
+	// eventsAccess opts into consumers reading the service provider's events about
+	// their objects, e.g. via `kubectl bind logs`.
+	//
+	// +optional
+	EventsAccess bool `json:"eventsAccess,omitempty"`
+
+	// capabilities are optional features the service provider supports for this
+	// resource. Consumers and the konnector adapt their behaviour to them.
+	//
+	// +optional
+	// +listType=set
+	Capabilities []APIServiceExportCapability `json:"capabilities,omitempty"`
+
+	// namespaceRetention is how long the service provider keeps a namespace after the
+	// corresponding consumer namespace has been deleted, e.g. to allow data recovery.
+	// If unset, the service provider namespace is deleted immediately.
+	//
+	// +optional
+	NamespaceRetention *metav1.Duration `json:"namespaceRetention,omitempty"`
+
+	// statusSync selects the status fields that are downsynced to the consumer cluster,
+	// e.g. to leave out verbose internal diagnostics. If unset, the whole status is
+	// downsynced.
+	//
+	// +optional
+	StatusSync *StatusSyncPolicy `json:"statusSync,omitempty"`
+
+	// clusterScopedClaims are cluster-scoped consumer resources, e.g. StorageClasses,
+	// the service provider wants to read, e.g. for placement decisions. They are only
+	// synced after the consumer accepted them in the APIServiceBinding, and only
+	// read-only into status.claimedObjects. Only a fixed set of resources can be
+	// claimed, some of them metadata-only.
+	//
+	// +optional
+	ClusterScopedClaims []ClusterScopedClaim `json:"clusterScopedClaims,omitempty"`
+
+	// serviceAccountTokenClaims are consumer ServiceAccounts the service provider
+	// wants projected, audience-bound tokens of, e.g. to call back into the consumer
+	// cluster. They are only issued after the consumer accepted them in the
+	// APIServiceBinding. The konnector requests the tokens via the TokenRequest API,
+	// stores them in Secrets in the namespace of the APIServiceExport and renews
+	// them before they expire.
+	//
+	// +optional
+	ServiceAccountTokenClaims []ServiceAccountTokenClaim `json:"serviceAccountTokenClaims,omitempty"`
+
+	// eventRelay relays Events of the service provider about bound objects, e.g.
+	// of its operators, into the consumer namespaces of the objects, and optionally
+	// the consumer's Events about them to the service provider. It is only active
+	// after the consumer accepted it in the APIServiceBinding. Events about objects
+	// of cluster-scoped resources are not relayed.
+	//
+	// +optional
+	EventRelay *EventRelay `json:"eventRelay,omitempty"`
+
+	// schemaRevision is the name of the schema revision of spec.versions. Bindings
+	// that do not pin a revision follow it.
+	//
+	// +optional
+	SchemaRevision string `json:"schemaRevision,omitempty"`
+
+	// previousSchemaRevisions are older schema revisions still published to
+	// bindings that are pinned to them, such that providers can roll out schema
+	// changes gradually across their consumers. Revisions must use the same
+	// version names as spec.versions, and differ only in the schemas.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	PreviousSchemaRevisions []APIServiceExportSchemaRevision `json:"previousSchemaRevisions,omitempty"`
+
+	// limits are the limits of the plan of the consumer. They are propagated to
+	// the APIServiceBinding and enforced by the optional admission webhook of the
+	// konnector when objects are created in the consumer cluster.
+	//
+	// +optional
+	Limits *PlanLimits `json:"limits,omitempty"`
+
+	// notices are incident and maintenance notices of the service provider about
+	// this export. The konnector publishes the current ones on the
+	// APIServiceBinding, in its ProviderNotice condition and as Events, such that
+	// consumers learn about outages from within their own cluster.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Notices []ProviderNotice `json:"notices,omitempty"`
+}
+
+// ProviderNotice is an incident or maintenance notice of the service provider.
+type ProviderNotice struct {
+	// name identifies the notice, e.g. 2023-01-12-database-outage.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// type is the kind of the notice, Incident or Maintenance.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Type ProviderNoticeType `json:"type"`
+
+	// message describes the impact for consumers.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Message string `json:"message"`
+
+	// startTime is when the incident started or the maintenance begins.
+	//
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// endTime is when the incident was resolved or the maintenance ends. The
+	// notice is not shown to consumers anymore afterwards.
+	//
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// url links to more information, e.g. the status page of the service provider.
+	//
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// ProviderNoticeType is the kind of a provider notice.
+//
+// +kubebuilder:validation:Enum=Incident;Maintenance
+type ProviderNoticeType string
+
+const (
+	// ProviderNoticeTypeIncident is an ongoing outage or degradation of the service.
+	ProviderNoticeTypeIncident ProviderNoticeType = "Incident"
+	// ProviderNoticeTypeMaintenance is planned maintenance of the service.
+	ProviderNoticeTypeMaintenance ProviderNoticeType = "Maintenance"
+)
+
+// PlanLimits are limits of the plan of a consumer for a resource.
+type PlanLimits struct {
+	// maxObjects is the maximal number of objects of the resource in the
+	// consumer cluster.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxObjects *int64 `json:"maxObjects,omitempty"`
+
+	// maxObjectSize is the maximal size of an object in its JSON serialization,
+	// e.g. 64Ki.
+	//
+	// +optional
+	MaxObjectSize *resource.Quantity `json:"maxObjectSize,omitempty"`
+}
+
+// APIServiceExportSchemaRevision is a published revision of the versions of an export.
+type APIServiceExportSchemaRevision struct {
+	// name is the name of the revision.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// versions are the API versions of the revision.
+	//
+	// +required
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	Versions []APIServiceExportVersion `json:"versions"`
+}
+
+// ServiceAccountTokenClaim is a claim on tokens of a ServiceAccount of the consumer cluster.
+type ServiceAccountTokenClaim struct {
+	// namespace is the namespace of the ServiceAccount in the consumer cluster.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// name is the name of the ServiceAccount.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// audience is the intended audience of the token. The consumer cluster
+	// rejects the token for any other audience.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Audience string `json:"audience"`
+
+	// expirationSeconds is the requested lifetime of the token. The konnector
+	// renews it after 80% of the lifetime. The consumer cluster may issue shorter
+	// lived tokens.
+	//
+	// +optional
+	// +kubebuilder:default=3600
+	// +kubebuilder:validation:Minimum=600
+	ExpirationSeconds int64 `json:"expirationSeconds,omitempty"`
+}
+
+// EventRelay selects the Events relayed between service provider and consumer cluster.
+type EventRelay struct {
+	// reasons are the reasons of the service provider's Events that are downsynced,
+	// e.g. BackupCompleted. If empty, no Events are downsynced.
+	//
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+
+	// upsync relays the consumer's Events about bound objects to the service provider,
+	// independently of their reason.
+	//
+	// +optional
+	Upsync bool `json:"upsync,omitempty"`
+}
+
+// ClusterScopedClaim is a claim on a cluster-scoped resource of the consumer cluster.
+type ClusterScopedClaim struct {
+	// group is the API group of the resource. Empty for the core group.
+	//
+	// +optional
+	Group string `json:"group"`
+
+	// resource is the plural lower-case resource name, e.g. storageclasses.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+}
+
+// ClaimedObject is a read-only copy of a claimed cluster-scoped consumer object.
+type ClaimedObject struct {
+	// group is the API group of the object. Empty for the core group.
+	//
+	// +optional
+	Group string `json:"group"`
+
+	// resource is the plural lower-case resource name of the object.
+	Resource string `json:"resource"`
+
+	// name is the name of the object.
+	Name string `json:"name"`
+
+	// labels are the labels of the object.
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// object is the object without metadata other than name and labels. It is
+	// not set for resources that can only be claimed metadata-only.
+	//
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Object *runtime.RawExtension `json:"object,omitempty"`
+}
+
+// StatusSyncPolicy selects status fields by JSONPath, e.g. `.status.conditions`.
+type StatusSyncPolicy struct {
+	// include lists the status fields that are downsynced. If empty, all fields
+	// are included.
+	//
+	// +optional
+	Include []StatusFieldPath `json:"include,omitempty"`
+
+	// exclude lists the status fields that are not downsynced. It is applied after
+	// include.
+	//
+	// +optional
+	Exclude []StatusFieldPath `json:"exclude,omitempty"`
+
+	// maxSize is the maximum size of the downsynced status, serialized as JSON.
+	// Larger statuses are truncated according to truncation, and the truncation is
+	// reported in the kube-bind.io/status-truncated condition. Defaults to 1Mi.
+	//
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+
+	// truncation is how oversized statuses are truncated. Arrays are shortened first,
+	// then long strings, then whole fields are dropped.
+	//
+	// KeepHead: the first items of arrays are kept.
+	// KeepTail: the last items of arrays are kept, e.g. the newest entries of histories.
+	//
+	// +optional
+	// +kubebuilder:default=KeepTail
+	Truncation StatusTruncationPolicy `json:"truncation,omitempty"`
 }
 
+// StatusTruncationPolicy is the way an oversized status is truncated.
+//
+// +kubebuilder:validation:Enum=KeepHead;KeepTail
+type StatusTruncationPolicy string
+
+const (
+	StatusTruncationKeepHead StatusTruncationPolicy = "KeepHead"
+	StatusTruncationKeepTail StatusTruncationPolicy = "KeepTail"
+)
+
+// StatusFieldPath is a JSONPath of a field below status, e.g. `.status.diagnostics`.
+// Only field names are supported, no array indices, wildcards or filters.
+//
+// +kubebuilder:validation:Pattern=`^\.status(\.[a-zA-Z0-9_-]+)+$`
+type StatusFieldPath string
+
+// APIServiceExportCapability is an optional feature of an exported resource.
+//
+// +kubebuilder:validation:Enum=ScaleSubresource;ConnectionSecrets;ClaimsV2;ReadOnly
+type APIServiceExportCapability string
+
+const (
+	// APIServiceExportCapabilityScaleSubresource means the service provider honors the scale subresource.
+	APIServiceExportCapabilityScaleSubresource APIServiceExportCapability = "ScaleSubresource"
+	// APIServiceExportCapabilityConnectionSecrets means the service provider delivers connection secrets.
+	APIServiceExportCapabilityConnectionSecrets APIServiceExportCapability = "ConnectionSecrets"
+	// APIServiceExportCapabilityClaimsV2 means the service provider supports the second version of claims.
+	APIServiceExportCapabilityClaimsV2 APIServiceExportCapability = "ClaimsV2"
+	// APIServiceExportCapabilityReadOnly means objects are owned by the service provider. Changes
+	// in the consumer cluster are not synced to the service provider.
+	APIServiceExportCapabilityReadOnly APIServiceExportCapability = "ReadOnly"
+)
+
+// Isolation is how consumer objects are mapped to service provider namespaces.
+//
+// +kubebuilder:validation:Enum=Namespace;Object
+type Isolation string
+
+const (
+	// NamespaceIsolation maps every consumer namespace to one service provider namespace.
+	NamespaceIsolation Isolation = "Namespace"
+	// ObjectIsolation maps every consumer object to its own service provider namespace.
+	ObjectIsolation Isolation = "Object"
+)
+
 type APIServiceExportCRDSpec struct {
 	// group is the API group of the defined custom resource. Empty string means the
 	// core API group. 	The resources are served under `/apis/<group>/...` or `/api` for the core group.
@@ -208,6 +608,56 @@ type APIServiceExportStatus struct {
 	// conditions is a list of conditions that apply to the APIServiceExport. It is
 	// updated by the konnector on the consumer cluster.
 	Conditions conditionsapi.Conditions `json:"conditions,omitempty"`
+
+	// claimedObjects are the cluster-scoped consumer objects of the claims the
+	// consumer accepted. It is updated by the konnector on the consumer cluster.
+	//
+	// +optional
+	ClaimedObjects []ClaimedObject `json:"claimedObjects,omitempty"`
+
+	// serviceAccountTokens are the tokens issued for the service account token
+	// claims the consumer accepted. It is updated by the konnector on the consumer
+	// cluster.
+	//
+	// +optional
+	ServiceAccountTokens []ServiceAccountTokenStatus `json:"serviceAccountTokens,omitempty"`
+
+	// schemaRevision is the schema revision the consumer cluster has bound. It is
+	// updated by the konnector on the consumer cluster.
+	//
+	// +optional
+	SchemaRevision string `json:"schemaRevision,omitempty"`
+
+	// pinnedSchemaRevision is the schema revision the consumer pinned the binding
+	// to, if any. Such a binding does not follow spec.schemaRevision. It is updated
+	// by the konnector on the consumer cluster.
+	//
+	// +optional
+	PinnedSchemaRevision string `json:"pinnedSchemaRevision,omitempty"`
+}
+
+// ServiceAccountTokenStatus references the Secret with the token of a service
+// account token claim.
+type ServiceAccountTokenStatus struct {
+	// namespace is the namespace of the ServiceAccount in the consumer cluster.
+	Namespace string `json:"namespace"`
+
+	// name is the name of the ServiceAccount.
+	Name string `json:"name"`
+
+	// audience is the audience of the token.
+	Audience string `json:"audience"`
+
+	// secretName is the name of the Secret in the namespace of the APIServiceExport
+	// holding the token under the "token" key.
+	SecretName string `json:"secretName"`
+
+	// expirationTimestamp is when the current token expires.
+	ExpirationTimestamp metav1.Time `json:"expirationTimestamp"`
+
+	// renewTimestamp is when the konnector renews the token, after 80% of the
+	// lifetime the consumer cluster issued it with.
+	RenewTimestamp metav1.Time `json:"renewTimestamp"`
 }
 
 // APIServiceExportList is the objects list that represents the APIServiceExport.
@@ -29,7 +29,8 @@ const (
 // created by the service provider.
 //
 // The name of the APIServiceNamespace equals the namespace name in the consumer
-// cluster.
+// cluster, or is <namespace>.<object-name> for APIServiceExports with Object
+// isolation.
 //
 // +crd
 // +genclient
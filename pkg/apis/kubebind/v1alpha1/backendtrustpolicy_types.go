@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BackendURLAnnotationKey is set by kubectl bind on the kubeconfig secret of a
+	// service provider to the URL of the backend the consumer bound to. It is checked
+	// against BackendTrustPolicies by the konnector.
+	BackendURLAnnotationKey = "kube-bind.io/backend-url"
+)
+
+// BackendTrustPolicy lists the service provider backends the consumer cluster may be
+// bound to. It lives in the consumer cluster. If at least one BackendTrustPolicy exists,
+// kubectl bind refuses to bind to other backends, and the konnector refuses to sync
+// with them. Multiple policies are merged.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kube-bindings
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`,priority=0
+type BackendTrustPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec lists the trusted backends.
+	Spec BackendTrustPolicySpec `json:"spec"`
+}
+
+// BackendTrustPolicySpec lists the trusted backends.
+type BackendTrustPolicySpec struct {
+	// backends are the trusted backends. A backend is trusted if it matches all
+	// the constraints of one of them.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Backends []TrustedBackend `json:"backends"`
+}
+
+// TrustedBackend constrains a trusted backend. Unset fields are not checked.
+type TrustedBackend struct {
+	// url is a prefix of the kube-bind URLs of the backend, e.g.
+	// https://mangodb.com/kube-bind.
+	//
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// issuers are prefixes of the authorization URLs the backend may send the
+	// user to for authentication, e.g. https://login.mangodb.com. They are only
+	// checked by kubectl bind.
+	//
+	// +optional
+	Issuers []string `json:"issuers,omitempty"`
+
+	// servers are the API server URLs of the service provider cluster, e.g.
+	// https://api.mangodb.com:6443.
+	//
+	// +optional
+	Servers []string `json:"servers,omitempty"`
+
+	// caPins are the SHA-256 hashes of the subject public key info of the CA
+	// certificates the service provider cluster is trusted with, as
+	// sha256/<base64>. One of the CA certificates of the kubeconfig must match.
+	//
+	// +optional
+	CAPins []string `json:"caPins,omitempty"`
+}
+
+// BackendTrustPolicyList is a list of BackendTrustPolicies.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BackendTrustPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []BackendTrustPolicy `json:"items"`
+}
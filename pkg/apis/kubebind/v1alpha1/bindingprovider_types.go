@@ -49,6 +49,12 @@ type BindingProvider struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	AuthenticationMethods []AuthenticationMethod `json:"authenticationMethods,omitempty"`
+
+	// regions are where the service provider stores and processes data of consumers,
+	// e.g. eu. Empty means unknown.
+	//
+	// +optional
+	Regions []string `json:"regions,omitempty"`
 }
 
 type AuthenticationMethod struct {
@@ -32,6 +32,20 @@ const (
 
 	// ClusterBindingConditionHealthy is set when the cluster binding is healthy.
 	ClusterBindingConditionHealthy = "Healthy"
+
+	// ClusterBindingConditionCredentialsValid is set when the credentials issued
+	// by the service provider have not expired.
+	ClusterBindingConditionCredentialsValid = "CredentialsValid"
+
+	// ClusterBindingConditionTrialActive is set for trial bindings, and is false
+	// when the trial has expired.
+	ClusterBindingConditionTrialActive = "TrialActive"
+
+	// ClusterBindingConditionVersionSkew is set by the konnector to true if it is
+	// older than the minimum or recommended version of spec.konnectorVersions. It
+	// does not make the ClusterBinding unready, and is removed when the konnector
+	// is up to date.
+	ClusterBindingConditionVersionSkew = "VersionSkew"
 )
 
 // ClusterBinding represents a bound consumer class. It lives in a service provider cluster
@@ -43,7 +57,9 @@ const (
 // +kubebuilder:resource:scope=Namespaced,categories=kube-bindings
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Konnector Version",type="string",JSONPath=`.status.konnectorVersion`,priority=0
+// +kubebuilder:printcolumn:name="Konnector Commit",type="string",JSONPath=`.status.konnectorBuild.gitCommit`,priority=1
 // +kubebuilder:printcolumn:name="Last Heartbeat",type="date",JSONPath=`.status.lastHeartbeatTime`,priority=0
+// +kubebuilder:printcolumn:name="Credentials Expire",type="date",JSONPath=`.status.credentialsExpirationTime`,priority=1
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`,priority=0
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`,priority=0
 type ClusterBinding struct {
@@ -111,6 +127,39 @@ type ClusterBindingSpec struct {
 	// binding request. The service providers decide what they need and what to configure based on what then include in
 	// this field, such as service region, type, tiers, etc...
 	ServiceProviderSpec runtime.RawExtension `json:"serviceProviderSpec,omitempty"`
+
+	// expirationTime is the time a trial binding expires. After that the service
+	// provider deletes the ClusterBinding with all bound objects. It is not set
+	// for regular bindings.
+	//
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="expirationTime is immutable"
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// konnectorVersions are the konnector versions the service provider requires
+	// and recommends. The konnector reports a VersionSkew condition if it is
+	// older, and can upgrade itself if enabled by the consumer.
+	//
+	// +optional
+	KonnectorVersions *KonnectorVersionRequirements `json:"konnectorVersions,omitempty"`
+}
+
+// KonnectorVersionRequirements are the konnector versions a service provider
+// supports.
+type KonnectorVersionRequirements struct {
+	// minimum is the oldest konnector version the service provider supports,
+	// e.g. v0.4.0.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^v[0-9]+\.[0-9]+\.[0-9]+.*$`
+	Minimum string `json:"minimum,omitempty"`
+
+	// recommended is the konnector version the service provider recommends,
+	// e.g. v0.5.0. It is not older than minimum.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^v[0-9]+\.[0-9]+\.[0-9]+.*$`
+	Recommended string `json:"recommended,omitempty"`
 }
 
 // ClusterBindingStatus stores status information about a service binding. It is
@@ -129,11 +178,87 @@ type ClusterBindingStatus struct {
 	// consumer cluster.
 	KonnectorVersion string `json:"konnectorVersion,omitempty"`
 
+	// konnectorBuild is the precise build information of the konnector that is
+	// running on the consumer cluster. It is reported at startup and can be used
+	// for an inventory of the consumer fleet.
+	KonnectorBuild *KonnectorBuildInfo `json:"konnectorBuild,omitempty"`
+
+	// credentialsExpirationTime is the time the credentials issued by the service
+	// provider to the konnector expire. It is not set if they do not expire.
+	CredentialsExpirationTime *metav1.Time `json:"credentialsExpirationTime,omitempty"`
+
+	// konnector is the health of the konnector as reported with every heartbeat:
+	// which replica is serving, and how the APIServiceBindings of this
+	// ClusterBinding are doing on the consumer cluster.
+	//
+	// +optional
+	Konnector *KonnectorStatus `json:"konnector,omitempty"`
+
 	// conditions is a list of conditions that apply to the ClusterBinding. It is
 	// updated by the konnector and the service provider.
 	Conditions conditionsapi.Conditions `json:"conditions,omitempty"`
 }
 
+// KonnectorBuildInfo is the build information of a konnector binary.
+type KonnectorBuildInfo struct {
+	// gitVersion is the full version string the konnector was built with,
+	// e.g. v1.25.2+kube-bind-v0.1.0.
+	GitVersion string `json:"gitVersion,omitempty"`
+
+	// gitCommit is the commit the konnector was built from.
+	GitCommit string `json:"gitCommit,omitempty"`
+
+	// gitTreeState is "clean" or "dirty", depending on the state of the
+	// git tree the konnector was built from.
+	GitTreeState string `json:"gitTreeState,omitempty"`
+
+	// buildDate is the RFC3339 time the konnector was built at.
+	BuildDate string `json:"buildDate,omitempty"`
+
+	// goVersion is the Go version the konnector was built with.
+	GoVersion string `json:"goVersion,omitempty"`
+
+	// platform is the os/arch the konnector is running on.
+	Platform string `json:"platform,omitempty"`
+}
+
+// KonnectorStatus is the health of a konnector as reported with its heartbeat.
+type KonnectorStatus struct {
+	// identity is the konnector replica that sent the last heartbeat, i.e. the
+	// leader, or the replica owning the APIServiceBindings when sharding.
+	Identity string `json:"identity,omitempty"`
+
+	// servedBindings is the number of APIServiceBindings on the consumer
+	// cluster using this ClusterBinding.
+	ServedBindings int32 `json:"servedBindings"`
+
+	// readyBindings is the number of served APIServiceBindings that are Ready.
+	ReadyBindings int32 `json:"readyBindings"`
+
+	// bindings are the served APIServiceBindings, sorted by name.
+	//
+	// +optional
+	Bindings []KonnectorBindingStatus `json:"bindings,omitempty"`
+}
+
+// KonnectorBindingStatus is the health of an APIServiceBinding served by a konnector.
+type KonnectorBindingStatus struct {
+	// name is the name of the APIServiceBinding on the consumer cluster.
+	Name string `json:"name"`
+
+	// lastSyncTime is the last heartbeat at which the APIServiceBinding was Ready.
+	// It is not set if it has not been Ready yet.
+	//
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// error is the reason and message of the Ready condition of the
+	// APIServiceBinding if it is not True.
+	//
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
 // ClusterBindingList is the objects list that represents the ClusterBinding.
 //
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// ClaimableResource is a cluster-scoped consumer resource that can be claimed by
+// a service provider.
+type ClaimableResource struct {
+	// Version is the version the resource is read with.
+	Version string
+	// Kind is the kind of the objects of the resource.
+	Kind string
+	// MetadataOnly means that only name and labels of the objects are synced.
+	MetadataOnly bool
+}
+
+// claimableResources are the cluster-scoped resources a service provider can
+// claim. Everything else is refused, independently of what the consumer accepts.
+var claimableResources = map[schema.GroupResource]ClaimableResource{
+	{Group: "storage.k8s.io", Resource: "storageclasses"}:     {Version: "v1", Kind: "StorageClass"},
+	{Group: "networking.k8s.io", Resource: "ingressclasses"}:  {Version: "v1", Kind: "IngressClass"},
+	{Group: "node.k8s.io", Resource: "runtimeclasses"}:        {Version: "v1", Kind: "RuntimeClass"},
+	{Group: "scheduling.k8s.io", Resource: "priorityclasses"}: {Version: "v1", Kind: "PriorityClass"},
+	{Group: "", Resource: "nodes"}:                            {Version: "v1", Kind: "Node", MetadataOnly: true},
+}
+
+// Claimable returns how the given cluster-scoped resource can be claimed, or false
+// if it cannot be claimed at all.
+func Claimable(claim kubebindv1alpha1.ClusterScopedClaim) (ClaimableResource, bool) {
+	r, ok := claimableResources[schema.GroupResource{Group: claim.Group, Resource: claim.Resource}]
+	return r, ok
+}
+
+// ClaimedObject returns the read-only copy of a consumer object of a claimable
+// resource that is published in the APIServiceExport status. obj must be
+// unstructured unless the resource is metadata-only.
+func ClaimedObject(claim kubebindv1alpha1.ClusterScopedClaim, obj runtime.Object) (kubebindv1alpha1.ClaimedObject, error) {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return kubebindv1alpha1.ClaimedObject{}, err
+	}
+	ret := kubebindv1alpha1.ClaimedObject{
+		Group:    claim.Group,
+		Resource: claim.Resource,
+		Name:     m.GetName(),
+		Labels:   m.GetLabels(),
+	}
+	if claimable, _ := Claimable(claim); claimable.MetadataOnly {
+		return ret, nil
+	}
+
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return kubebindv1alpha1.ClaimedObject{}, fmt.Errorf("unexpected type %T", obj)
+	}
+	content := runtime.DeepCopyJSON(u.Object)
+	delete(content, "metadata")
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return kubebindv1alpha1.ClaimedObject{}, err
+	}
+	ret.Object = &runtime.RawExtension{Raw: raw}
+
+	return ret, nil
+}
+
+// AcceptedClaims returns the claims of the export that are claimable and accepted
+// by the binding.
+func AcceptedClaims(export *kubebindv1alpha1.APIServiceExport, binding *kubebindv1alpha1.APIServiceBinding) []kubebindv1alpha1.ClusterScopedClaim {
+	var ret []kubebindv1alpha1.ClusterScopedClaim
+	for _, claim := range export.Spec.ClusterScopedClaims {
+		if _, ok := Claimable(claim); !ok {
+			continue
+		}
+		if containsClaim(binding.Spec.AcceptedClaims, claim) {
+			ret = append(ret, claim)
+		}
+	}
+	return ret
+}
+
+func containsClaim(claims []kubebindv1alpha1.ClusterScopedClaim, claim kubebindv1alpha1.ClusterScopedClaim) bool {
+	for _, c := range claims {
+		if c.Group == claim.Group && c.Resource == claim.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptedServiceAccountTokenClaims returns the service account token claims of
+// the export that are accepted by the binding.
+func AcceptedServiceAccountTokenClaims(export *kubebindv1alpha1.APIServiceExport, binding *kubebindv1alpha1.APIServiceBinding) []kubebindv1alpha1.ServiceAccountTokenClaim {
+	var ret []kubebindv1alpha1.ServiceAccountTokenClaim
+	for _, claim := range export.Spec.ServiceAccountTokenClaims {
+		for _, accepted := range binding.Spec.AcceptedServiceAccountTokenClaims {
+			if accepted.Namespace == claim.Namespace && accepted.Name == claim.Name && accepted.Audience == claim.Audience {
+				ret = append(ret, claim)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// ServiceAccountTokenSecretName returns the name of the Secret in the namespace
+// of the export that holds the token of the given claim.
+func ServiceAccountTokenSecretName(exportName string, claim kubebindv1alpha1.ServiceAccountTokenClaim) string {
+	hash := sha256.Sum256([]byte(claim.Namespace + "/" + claim.Name + "/" + claim.Audience))
+	return fmt.Sprintf("%s-token-%x", exportName, hash[:5])
+}
+
+// AcceptedEventRelay returns the event relay of the export as far as it is accepted
+// by the binding, or nil if nothing is relayed.
+func AcceptedEventRelay(export *kubebindv1alpha1.APIServiceExport, binding *kubebindv1alpha1.APIServiceBinding) *kubebindv1alpha1.EventRelay {
+	offered, accepted := export.Spec.EventRelay, binding.Spec.AcceptedEventRelay
+	if offered == nil || accepted == nil {
+		return nil
+	}
+
+	ret := &kubebindv1alpha1.EventRelay{Upsync: offered.Upsync && accepted.Upsync}
+	for _, reason := range offered.Reasons {
+		for _, r := range accepted.Reasons {
+			if r == reason {
+				ret.Reasons = append(ret.Reasons, reason)
+				break
+			}
+		}
+	}
+	if len(ret.Reasons) == 0 && !ret.Upsync {
+		return nil
+	}
+	return ret
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestAcceptedEventRelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		offered  *kubebindv1alpha1.EventRelay
+		accepted *kubebindv1alpha1.EventRelay
+		want     *kubebindv1alpha1.EventRelay
+	}{
+		{"not offered", nil, &kubebindv1alpha1.EventRelay{Reasons: []string{"A"}}, nil},
+		{"not accepted", &kubebindv1alpha1.EventRelay{Reasons: []string{"A"}}, nil, nil},
+		{
+			"subset of reasons",
+			&kubebindv1alpha1.EventRelay{Reasons: []string{"A", "B", "C"}, Upsync: true},
+			&kubebindv1alpha1.EventRelay{Reasons: []string{"C", "A", "D"}},
+			&kubebindv1alpha1.EventRelay{Reasons: []string{"A", "C"}},
+		},
+		{
+			"upsync only",
+			&kubebindv1alpha1.EventRelay{Reasons: []string{"A"}, Upsync: true},
+			&kubebindv1alpha1.EventRelay{Upsync: true},
+			&kubebindv1alpha1.EventRelay{Upsync: true},
+		},
+		{"nothing in common", &kubebindv1alpha1.EventRelay{Reasons: []string{"A"}}, &kubebindv1alpha1.EventRelay{Reasons: []string{"B"}, Upsync: true}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export := &kubebindv1alpha1.APIServiceExport{Spec: kubebindv1alpha1.APIServiceExportSpec{EventRelay: tt.offered}}
+			binding := &kubebindv1alpha1.APIServiceBinding{Spec: kubebindv1alpha1.APIServiceBindingSpec{AcceptedEventRelay: tt.accepted}}
+			require.Equal(t, tt.want, AcceptedEventRelay(export, binding))
+		})
+	}
+}
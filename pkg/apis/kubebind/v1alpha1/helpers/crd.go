@@ -40,3 +40,30 @@ func IsOwnedByBinding(name string, uid types.UID, refs []v1.OwnerReference) bool
 	}
 	return false
 }
+
+// BoundGroup returns the API group the resource of the binding is served under in the
+// consumer cluster, i.e. the group alias of the binding or else the given group of
+// the APIServiceExport.
+func BoundGroup(binding *v1alpha1.APIServiceBinding, exportGroup string) string {
+	if binding != nil && binding.Spec.GroupAlias != "" {
+		return binding.Spec.GroupAlias
+	}
+	return exportGroup
+}
+
+// BoundCRDName returns the name of the CRD of the binding in the consumer cluster.
+func BoundCRDName(binding *v1alpha1.APIServiceBinding) string {
+	exportName := ExportName(binding)
+	if binding.Spec.GroupAlias == "" {
+		return exportName
+	}
+	return strings.SplitN(exportName, ".", 2)[0] + "." + binding.Spec.GroupAlias
+}
+
+// ExportName returns the name of the APIServiceExport the binding binds to.
+func ExportName(binding *v1alpha1.APIServiceBinding) string {
+	if binding.Spec.Export != "" {
+		return binding.Spec.Export
+	}
+	return binding.Name
+}
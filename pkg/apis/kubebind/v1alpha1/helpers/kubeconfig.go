@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// FallbackClusterName returns the name of the i-th fallback, starting at 1, of
+// the given cluster in a kubeconfig issued by a service provider. Fallbacks are
+// further API endpoints, with their own CA bundle and TLS server name, of the
+// same service provider cluster, e.g. during a control-plane migration. They
+// share the user and namespace of the context.
+func FallbackClusterName(cluster string, i int) string {
+	return fmt.Sprintf("%s-fallback-%d", cluster, i)
+}
+
+// ProviderRESTConfigs returns the rest configs of the current context of a
+// kubeconfig issued by a service provider: first the primary endpoint, then
+// the fallbacks in order.
+func ProviderRESTConfigs(kubeconfig []byte) ([]*rest.Config, error) {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeContext, found := cfg.Contexts[cfg.CurrentContext]
+	if !found {
+		return nil, fmt.Errorf("kubeconfig does not have a current context")
+	}
+
+	primary, err := clientcmd.NewDefaultClientConfig(*cfg, nil).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	configs := []*rest.Config{primary}
+	for i := 1; ; i++ {
+		name := FallbackClusterName(kubeContext.Cluster, i)
+		if _, found := cfg.Clusters[name]; !found {
+			return configs, nil
+		}
+		fallback := cfg.DeepCopy()
+		fallback.Contexts[fallback.CurrentContext].Cluster = name
+		config, err := clientcmd.NewDefaultClientConfig(*fallback, nil).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid fallback cluster %q: %w", name, err)
+		}
+		configs = append(configs, config)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestProviderRESTConfigs(t *testing.T) {
+	cfg := clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"default":            {Server: "https://old.example.com", CertificateAuthorityData: []byte("old-ca")},
+			"default-fallback-1": {Server: "https://new.example.com", CertificateAuthorityData: []byte("new-ca"), TLSServerName: "api.example.com"},
+			"default-fallback-3": {Server: "https://ignored.example.com"},
+			"other":              {Server: "https://other.example.com"},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"default": {Cluster: "default", AuthInfo: "default", Namespace: "kube-bind-abcde"},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"default": {Token: "token"},
+		},
+		CurrentContext: "default",
+	}
+	kubeconfig, err := clientcmd.Write(cfg)
+	require.NoError(t, err)
+
+	configs, err := ProviderRESTConfigs(kubeconfig)
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	require.Equal(t, "https://old.example.com", configs[0].Host)
+	require.Equal(t, []byte("old-ca"), configs[0].CAData)
+	require.Equal(t, "https://new.example.com", configs[1].Host)
+	require.Equal(t, []byte("new-ca"), configs[1].CAData)
+	require.Equal(t, "api.example.com", configs[1].ServerName)
+	require.Equal(t, "token", configs[1].BearerToken)
+
+	cfg.CurrentContext = "missing"
+	kubeconfig, err = clientcmd.Write(cfg)
+	require.NoError(t, err)
+	_, err = ProviderRESTConfigs(kubeconfig)
+	require.Error(t, err)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// CurrentNotices returns the notices that have not ended at the given time,
+// including announced maintenance that has not started yet.
+func CurrentNotices(notices []kubebindv1alpha1.ProviderNotice, now time.Time) []kubebindv1alpha1.ProviderNotice {
+	var ret []kubebindv1alpha1.ProviderNotice
+	for _, n := range notices {
+		if n.EndTime != nil && !now.Before(n.EndTime.Time) {
+			continue
+		}
+		ret = append(ret, n)
+	}
+	return ret
+}
+
+// NoticeMessage is a human readable one-line summary of a notice.
+func NoticeMessage(n kubebindv1alpha1.ProviderNotice) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", n.Type, n.Name)
+	switch {
+	case n.StartTime != nil && n.EndTime != nil:
+		fmt.Fprintf(&b, " from %s until %s", n.StartTime.UTC().Format(time.RFC3339), n.EndTime.UTC().Format(time.RFC3339))
+	case n.StartTime != nil:
+		fmt.Fprintf(&b, " since %s", n.StartTime.UTC().Format(time.RFC3339))
+	case n.EndTime != nil:
+		fmt.Fprintf(&b, " until %s", n.EndTime.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, ": %s", n.Message)
+	if n.URL != "" {
+		fmt.Fprintf(&b, " (%s)", n.URL)
+	}
+	return b.String()
+}
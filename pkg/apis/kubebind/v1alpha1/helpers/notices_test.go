@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestNoticeMessage(t *testing.T) {
+	start := metav1.NewTime(time.Date(2023, 1, 12, 10, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(time.Hour))
+
+	tests := []struct {
+		name   string
+		notice kubebindv1alpha1.ProviderNotice
+		want   string
+	}{
+		{
+			name:   "plain",
+			notice: kubebindv1alpha1.ProviderNotice{Name: "outage", Type: kubebindv1alpha1.ProviderNoticeTypeIncident, Message: "Provisioning is delayed."},
+			want:   "Incident outage: Provisioning is delayed.",
+		},
+		{
+			name:   "since",
+			notice: kubebindv1alpha1.ProviderNotice{Name: "outage", Type: kubebindv1alpha1.ProviderNoticeTypeIncident, Message: "down", StartTime: &start, URL: "https://status.example.com"},
+			want:   "Incident outage since 2023-01-12T10:00:00Z: down (https://status.example.com)",
+		},
+		{
+			name:   "window",
+			notice: kubebindv1alpha1.ProviderNotice{Name: "db-upgrade", Type: kubebindv1alpha1.ProviderNoticeTypeMaintenance, Message: "read-only", StartTime: &start, EndTime: &end},
+			want:   "Maintenance db-upgrade from 2023-01-12T10:00:00Z until 2023-01-12T11:00:00Z: read-only",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NoticeMessage(tt.notice))
+		})
+	}
+
+	current := CurrentNotices([]kubebindv1alpha1.ProviderNotice{tests[1].notice, tests[2].notice}, end.Time)
+	require.Equal(t, []kubebindv1alpha1.ProviderNotice{tests[1].notice}, current)
+}
@@ -0,0 +1,24 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+// ProviderHash returns the value of the kube-bind.io/provider label for the given
+// service provider cluster URL, e.g. https://mangodb.com:6443. It is a valid label
+// value of at most 38 characters.
+func ProviderHash(providerURL string) string {
+	return toSha224Base62(providerURL)
+}
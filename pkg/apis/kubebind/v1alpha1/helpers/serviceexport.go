@@ -21,6 +21,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"regexp"
+	"strings"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -122,6 +124,69 @@ func CRDToServiceExport(crd *apiextensionsv1.CustomResourceDefinition) (*kubebin
 	return spec, nil
 }
 
+// statusFieldPathRegexp is the validation pattern of StatusFieldPath in the CRDs.
+var statusFieldPathRegexp = regexp.MustCompile(`^\.status(\.[a-zA-Z0-9_-]+)+$`)
+
+// ValidStatusFieldPath returns true if the path is accepted by the CRD validation
+// of StatusFieldPath.
+func ValidStatusFieldPath(path kubebindv1alpha1.StatusFieldPath) bool {
+	return statusFieldPathRegexp.MatchString(string(path))
+}
+
+// KnownCapability returns true if the capability is one of the APIServiceExport
+// capabilities accepted by the CRD validation.
+func KnownCapability(capability kubebindv1alpha1.APIServiceExportCapability) bool {
+	switch capability {
+	case kubebindv1alpha1.APIServiceExportCapabilityScaleSubresource,
+		kubebindv1alpha1.APIServiceExportCapabilityConnectionSecrets,
+		kubebindv1alpha1.APIServiceExportCapabilityClaimsV2,
+		kubebindv1alpha1.APIServiceExportCapabilityReadOnly:
+		return true
+	}
+	return false
+}
+
+// HasCapability returns true if the APIServiceExport advertises the given capability.
+func HasCapability(export *kubebindv1alpha1.APIServiceExport, capability kubebindv1alpha1.APIServiceExportCapability) bool {
+	for _, c := range export.Spec.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingCapabilities returns the capabilities of the comma separated list that
+// the APIServiceExport does not advertise.
+func MissingCapabilities(export *kubebindv1alpha1.APIServiceExport, required string) []string {
+	var missing []string
+	for _, c := range strings.Split(required, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if !HasCapability(export, kubebindv1alpha1.APIServiceExportCapability(c)) {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// RegionAllowed returns true if the region is one of the allowed regions. Empty
+// allowed regions allow everything, while an unknown, empty region is only allowed
+// then.
+func RegionAllowed(region string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == region && region != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func APIServiceExportCRDSpecHash(obj *kubebindv1alpha1.APIServiceExportCRDSpec) string {
 	bs, err := json.Marshal(obj)
 	if err != nil {
@@ -141,3 +206,21 @@ func toBase62(hash [28]byte) string {
 	i.SetBytes(hash[:])
 	return i.Text(62)
 }
+
+// ExportAtSchemaRevision returns the export with the versions of the given schema
+// revision, or false if the export does not publish it. An empty revision is the
+// latest revision.
+func ExportAtSchemaRevision(export *kubebindv1alpha1.APIServiceExport, revision string) (*kubebindv1alpha1.APIServiceExport, bool) {
+	if revision == "" || revision == export.Spec.SchemaRevision {
+		return export, true
+	}
+	for _, r := range export.Spec.PreviousSchemaRevisions {
+		if r.Name == revision {
+			export = export.DeepCopy()
+			export.Spec.SchemaRevision = r.Name
+			export.Spec.Versions = r.Versions
+			return export, true
+		}
+	}
+	return nil, false
+}
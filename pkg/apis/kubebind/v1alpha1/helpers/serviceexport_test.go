@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestMissingCapabilities(t *testing.T) {
+	export := &kubebindv1alpha1.APIServiceExport{}
+	export.Spec.Capabilities = []kubebindv1alpha1.APIServiceExportCapability{"ScaleSubresource", "ReadOnly"}
+
+	tests := []struct {
+		name     string
+		required string
+		want     []string
+	}{
+		{name: "none required"},
+		{name: "all advertised", required: "ReadOnly, ScaleSubresource", want: nil},
+		{name: "missing", required: "ReadOnly,ConnectionSecrets,ClaimsV2", want: []string{"ConnectionSecrets", "ClaimsV2"}},
+		{name: "empty entries", required: ",ReadOnly,,", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, MissingCapabilities(export, tt.required))
+		})
+	}
+}
+
+func TestKnownCapability(t *testing.T) {
+	for _, c := range []kubebindv1alpha1.APIServiceExportCapability{"ScaleSubresource", "ConnectionSecrets", "ClaimsV2", "ReadOnly"} {
+		require.True(t, KnownCapability(c), c)
+	}
+	require.False(t, KnownCapability("Teleport"))
+	require.False(t, KnownCapability("readonly"))
+}
+
+func TestValidStatusFieldPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: ".status.diagnostics", want: true},
+		{path: ".status.a_b.c-d.E1", want: true},
+		{path: ".status"},
+		{path: "status.diagnostics"},
+		{path: ".spec.replicas"},
+		{path: ".status.conditions[0]"},
+		{path: ".status.*"},
+		{path: ".status..x"},
+		{path: ".status.x "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require.Equal(t, tt.want, ValidStatusFieldPath(kubebindv1alpha1.StatusFieldPath(tt.path)))
+		})
+	}
+}
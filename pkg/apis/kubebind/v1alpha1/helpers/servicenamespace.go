@@ -0,0 +1,46 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"strings"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// ServiceNamespaceName returns the name of the APIServiceNamespace of a consumer object
+// in the given namespace: the namespace itself, or <namespace>.<name> with Object
+// isolation. Consumer namespaces cannot contain dots.
+func ServiceNamespaceName(isolation kubebindv1alpha1.Isolation, ns, name string) string {
+	if isolation == kubebindv1alpha1.ObjectIsolation {
+		return ns + "." + name
+	}
+	return ns
+}
+
+// ConsumerNamespace returns the consumer namespace of an APIServiceNamespace.
+func ConsumerNamespace(serviceNamespace string) string {
+	ns, _, _ := strings.Cut(serviceNamespace, ".")
+	return ns
+}
+
+// IsolatedObject returns the name of the consumer object of an APIServiceNamespace
+// with Object isolation, or empty otherwise.
+func IsolatedObject(serviceNamespace string) string {
+	_, name, _ := strings.Cut(serviceNamespace, ".")
+	return name
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// BackendIdentity is what is known about a service provider backend when checking
+// it against BackendTrustPolicies. Empty fields are unknown.
+type BackendIdentity struct {
+	// URL is the kube-bind URL of the backend.
+	URL string
+	// Issuer is the authorization URL the backend sends the user to.
+	Issuer string
+	// Server is the API server URL of the service provider cluster.
+	Server string
+	// CAData are the PEM encoded CA certificates of the service provider cluster.
+	CAData []byte
+}
+
+// BackendTrusted returns nil if there are no policies or if one of their backends
+// matches the identity. Unknown fields of the identity satisfy a constraint only if
+// partial is true, e.g. before authentication when the service provider cluster is
+// not known yet. Issuers are only checked if known.
+func BackendTrusted(policies []*kubebindv1alpha1.BackendTrustPolicy, id BackendIdentity, partial bool) error {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	pins, err := CAPins(id.CAData)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		for _, backend := range policy.Spec.Backends {
+			if backend.URL != "" && !known(id.URL, partial, func() bool { return hasURLPrefix(id.URL, backend.URL) }) {
+				continue
+			}
+			if len(backend.Issuers) > 0 && id.Issuer != "" && !anyString(backend.Issuers, func(issuer string) bool { return hasURLPrefix(id.Issuer, issuer) }) {
+				continue
+			}
+			if len(backend.Servers) > 0 && !known(id.Server, partial, func() bool {
+				return anyString(backend.Servers, func(server string) bool { return strings.TrimSuffix(server, "/") == strings.TrimSuffix(id.Server, "/") })
+			}) {
+				continue
+			}
+			if len(backend.CAPins) > 0 && !(len(id.CAData) == 0 && partial) && !anyString(backend.CAPins, func(pin string) bool { return anyString(pins, func(p string) bool { return p == pin }) }) {
+				continue
+			}
+			return nil
+		}
+	}
+
+	var what []string
+	if id.URL != "" {
+		what = append(what, fmt.Sprintf("url %s", id.URL))
+	}
+	if id.Issuer != "" {
+		what = append(what, fmt.Sprintf("issuer %s", id.Issuer))
+	}
+	if id.Server != "" {
+		what = append(what, fmt.Sprintf("server %s", id.Server))
+	}
+	if len(pins) > 0 {
+		what = append(what, fmt.Sprintf("CA %s", strings.Join(pins, ", ")))
+	}
+	return fmt.Errorf("backend with %s is not trusted by any BackendTrustPolicy", strings.Join(what, ", "))
+}
+
+// CAPins returns the sha256/<base64> pins of the subject public key info of the
+// PEM encoded certificates.
+func CAPins(caData []byte) ([]string, error) {
+	var pins []string
+	for rest := caData; len(rest) > 0; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA certificate: %w", err)
+		}
+		sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		pins = append(pins, "sha256/"+base64.StdEncoding.EncodeToString(sum[:]))
+	}
+	return pins, nil
+}
+
+// known returns match() for known values, and partial for unknown ones.
+func known(value string, partial bool, match func() bool) bool {
+	if value == "" {
+		return partial
+	}
+	return match()
+}
+
+// hasURLPrefix returns true if url starts with prefix at a path boundary.
+func hasURLPrefix(url, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if !strings.HasPrefix(url, prefix) {
+		return false
+	}
+	rest := url[len(prefix):]
+	return rest == "" || strings.HasPrefix(rest, "/") || strings.HasPrefix(rest, "?")
+}
+
+func anyString(ss []string, f func(string) bool) bool {
+	for _, s := range ss {
+		if f(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestBackendTrusted(t *testing.T) {
+	ca := testCA(t)
+	pins, err := CAPins(ca)
+	require.NoError(t, err)
+	require.Len(t, pins, 1)
+
+	policies := []*kubebindv1alpha1.BackendTrustPolicy{{
+		Spec: kubebindv1alpha1.BackendTrustPolicySpec{Backends: []kubebindv1alpha1.TrustedBackend{{
+			URL:     "https://mangodb.com/kube-bind",
+			Issuers: []string{"https://login.mangodb.com"},
+			Servers: []string{"https://api.mangodb.com:6443"},
+			CAPins:  pins,
+		}}},
+	}}
+
+	tests := []struct {
+		name     string
+		policies []*kubebindv1alpha1.BackendTrustPolicy
+		id       BackendIdentity
+		partial  bool
+		wantErr  bool
+	}{
+		{name: "no policies", id: BackendIdentity{URL: "https://evil.com"}},
+		{name: "before authentication", policies: policies, id: BackendIdentity{URL: "https://mangodb.com/kube-bind/exports", Issuer: "https://login.mangodb.com/authorize"}, partial: true},
+		{name: "URL prefix at path boundary only", policies: policies, id: BackendIdentity{URL: "https://mangodb.com/kube-bind-evil"}, partial: true, wantErr: true},
+		{name: "untrusted issuer", policies: policies, id: BackendIdentity{URL: "https://mangodb.com/kube-bind", Issuer: "https://evil.com"}, partial: true, wantErr: true},
+		{name: "full", policies: policies, id: BackendIdentity{URL: "https://mangodb.com/kube-bind", Server: "https://api.mangodb.com:6443/", CAData: ca}},
+		{name: "unknown server", policies: policies, id: BackendIdentity{URL: "https://mangodb.com/kube-bind", CAData: ca}, wantErr: true},
+		{name: "other CA", policies: policies, id: BackendIdentity{URL: "https://mangodb.com/kube-bind", Server: "https://api.mangodb.com:6443", CAData: testCA(t)}, wantErr: true},
+		{name: "unknown URL", policies: policies, id: BackendIdentity{Server: "https://api.mangodb.com:6443", CAData: ca}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := BackendTrusted(tt.policies, tt.id, tt.partial)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func testCA(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
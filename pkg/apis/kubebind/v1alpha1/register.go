@@ -54,10 +54,15 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&APIServiceExportRequestList{},
 		&APIServiceNamespace{},
 		&APIServiceNamespaceList{},
+		&APIServiceAction{},
+		&APIServiceActionList{},
 		&ClusterBinding{},
 		&ClusterBindingList{},
 		&BindingProvider{},
 		&BindingResponse{},
+		&APIServiceCatalog{},
+		&BackendTrustPolicy{},
+		&BackendTrustPolicyList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -22,14 +22,15 @@ limitations under the License.
 package v1alpha1
 
 import (
-	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 
 	conditionsv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *APIServiceBinding) DeepCopyInto(out *APIServiceBinding) {
+func (in *APIServiceAction) DeepCopyInto(out *APIServiceAction) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -38,6 +39,104 @@ func (in *APIServiceBinding) DeepCopyInto(out *APIServiceBinding) {
 	return
 }
 
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceAction.
+func (in *APIServiceAction) DeepCopy() *APIServiceAction {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIServiceAction) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceActionList) DeepCopyInto(out *APIServiceActionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIServiceAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceActionList.
+func (in *APIServiceActionList) DeepCopy() *APIServiceActionList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceActionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIServiceActionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceActionSpec) DeepCopyInto(out *APIServiceActionSpec) {
+	*out = *in
+	out.Target = in.Target
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceActionSpec.
+func (in *APIServiceActionSpec) DeepCopy() *APIServiceActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceActionStatus) DeepCopyInto(out *APIServiceActionStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceActionStatus.
+func (in *APIServiceActionStatus) DeepCopy() *APIServiceActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceBinding) DeepCopyInto(out *APIServiceBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceBinding.
 func (in *APIServiceBinding) DeepCopy() *APIServiceBinding {
 	if in == nil {
@@ -93,6 +192,43 @@ func (in *APIServiceBindingList) DeepCopyObject() runtime.Object {
 func (in *APIServiceBindingSpec) DeepCopyInto(out *APIServiceBindingSpec) {
 	*out = *in
 	out.KubeconfigSecretRef = in.KubeconfigSecretRef
+	if in.VirtualCluster != nil {
+		in, out := &in.VirtualCluster, &out.VirtualCluster
+		*out = new(VirtualClusterTarget)
+		**out = **in
+	}
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = new(SyncPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AcceptedClaims != nil {
+		in, out := &in.AcceptedClaims, &out.AcceptedClaims
+		*out = make([]ClusterScopedClaim, len(*in))
+		copy(*out, *in)
+	}
+	if in.AcceptedServiceAccountTokenClaims != nil {
+		in, out := &in.AcceptedServiceAccountTokenClaims, &out.AcceptedServiceAccountTokenClaims
+		*out = make([]ServiceAccountTokenClaim, len(*in))
+		copy(*out, *in)
+	}
+	if in.AcceptedEventRelay != nil {
+		in, out := &in.AcceptedEventRelay, &out.AcceptedEventRelay
+		*out = new(EventRelay)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedActions != nil {
+		in, out := &in.AllowedActions, &out.AllowedActions
+		*out = make([]ActionPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SLO != nil {
+		in, out := &in.SLO, &out.SLO
+		*out = new(SLOPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -109,6 +245,32 @@ func (in *APIServiceBindingSpec) DeepCopy() *APIServiceBindingSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServiceBindingStatus) DeepCopyInto(out *APIServiceBindingStatus) {
 	*out = *in
+	if in.Connection != nil {
+		in, out := &in.Connection, &out.Connection
+		*out = new(ConnectionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SLO != nil {
+		in, out := &in.SLO, &out.SLO
+		*out = new(SLOStatus)
+		**out = **in
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(PlanLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notices != nil {
+		in, out := &in.Notices, &out.Notices
+		*out = make([]ProviderNotice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(conditionsv1alpha1.Conditions, len(*in))
@@ -129,6 +291,59 @@ func (in *APIServiceBindingStatus) DeepCopy() *APIServiceBindingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceCatalog) DeepCopyInto(out *APIServiceCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]APIServiceCatalogResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceCatalog.
+func (in *APIServiceCatalog) DeepCopy() *APIServiceCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIServiceCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceCatalogResource) DeepCopyInto(out *APIServiceCatalogResource) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceCatalogResource.
+func (in *APIServiceCatalogResource) DeepCopy() *APIServiceCatalogResource {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceCatalogResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServiceExport) DeepCopyInto(out *APIServiceExport) {
 	*out = *in
@@ -393,10 +608,82 @@ func (in *APIServiceExportSchema) DeepCopy() *APIServiceExportSchema {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceExportSchemaRevision) DeepCopyInto(out *APIServiceExportSchemaRevision) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]APIServiceExportVersion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceExportSchemaRevision.
+func (in *APIServiceExportSchemaRevision) DeepCopy() *APIServiceExportSchemaRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceExportSchemaRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServiceExportSpec) DeepCopyInto(out *APIServiceExportSpec) {
 	*out = *in
 	in.APIServiceExportCRDSpec.DeepCopyInto(&out.APIServiceExportCRDSpec)
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]APIServiceExportCapability, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceRetention != nil {
+		in, out := &in.NamespaceRetention, &out.NamespaceRetention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.StatusSync != nil {
+		in, out := &in.StatusSync, &out.StatusSync
+		*out = new(StatusSyncPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterScopedClaims != nil {
+		in, out := &in.ClusterScopedClaims, &out.ClusterScopedClaims
+		*out = make([]ClusterScopedClaim, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccountTokenClaims != nil {
+		in, out := &in.ServiceAccountTokenClaims, &out.ServiceAccountTokenClaims
+		*out = make([]ServiceAccountTokenClaim, len(*in))
+		copy(*out, *in)
+	}
+	if in.EventRelay != nil {
+		in, out := &in.EventRelay, &out.EventRelay
+		*out = new(EventRelay)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreviousSchemaRevisions != nil {
+		in, out := &in.PreviousSchemaRevisions, &out.PreviousSchemaRevisions
+		*out = make([]APIServiceExportSchemaRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(PlanLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notices != nil {
+		in, out := &in.Notices, &out.Notices
+		*out = make([]ProviderNotice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -426,6 +713,20 @@ func (in *APIServiceExportStatus) DeepCopyInto(out *APIServiceExportStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ClaimedObjects != nil {
+		in, out := &in.ClaimedObjects, &out.ClaimedObjects
+		*out = make([]ClaimedObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServiceAccountTokens != nil {
+		in, out := &in.ServiceAccountTokens, &out.ServiceAccountTokens
+		*out = make([]ServiceAccountTokenStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -451,7 +752,7 @@ func (in *APIServiceExportVersion) DeepCopyInto(out *APIServiceExportVersion) {
 	in.Subresources.DeepCopyInto(&out.Subresources)
 	if in.AdditionalPrinterColumns != nil {
 		in, out := &in.AdditionalPrinterColumns, &out.AdditionalPrinterColumns
-		*out = make([]v1.CustomResourceColumnDefinition, len(*in))
+		*out = make([]apiextensionsv1.CustomResourceColumnDefinition, len(*in))
 		copy(*out, *in)
 	}
 	return
@@ -560,6 +861,43 @@ func (in *APIServiceNamespaceStatus) DeepCopy() *APIServiceNamespaceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionPolicy) DeepCopyInto(out *ActionPolicy) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionPolicy.
+func (in *ActionPolicy) DeepCopy() *ActionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionTarget) DeepCopyInto(out *ActionTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionTarget.
+func (in *ActionTarget) DeepCopy() *ActionTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthenticationMethod) DeepCopyInto(out *AuthenticationMethod) {
 	*out = *in
@@ -582,31 +920,26 @@ func (in *AuthenticationMethod) DeepCopy() *AuthenticationMethod {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BindingProvider) DeepCopyInto(out *BindingProvider) {
+func (in *BackendTrustPolicy) DeepCopyInto(out *BackendTrustPolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	if in.AuthenticationMethods != nil {
-		in, out := &in.AuthenticationMethods, &out.AuthenticationMethods
-		*out = make([]AuthenticationMethod, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingProvider.
-func (in *BindingProvider) DeepCopy() *BindingProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendTrustPolicy.
+func (in *BackendTrustPolicy) DeepCopy() *BackendTrustPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(BindingProvider)
+	out := new(BackendTrustPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *BindingProvider) DeepCopyObject() runtime.Object {
+func (in *BackendTrustPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -614,18 +947,13 @@ func (in *BindingProvider) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BindingResponse) DeepCopyInto(out *BindingResponse) {
+func (in *BackendTrustPolicyList) DeepCopyInto(out *BackendTrustPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.Authentication.DeepCopyInto(&out.Authentication)
-	if in.Kubeconfig != nil {
-		in, out := &in.Kubeconfig, &out.Kubeconfig
-		*out = make([]byte, len(*in))
-		copy(*out, *in)
-	}
-	if in.Requests != nil {
-		in, out := &in.Requests, &out.Requests
-		*out = make([]runtime.RawExtension, len(*in))
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BackendTrustPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -633,18 +961,18 @@ func (in *BindingResponse) DeepCopyInto(out *BindingResponse) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingResponse.
-func (in *BindingResponse) DeepCopy() *BindingResponse {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendTrustPolicyList.
+func (in *BackendTrustPolicyList) DeepCopy() *BackendTrustPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(BindingResponse)
+	out := new(BackendTrustPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *BindingResponse) DeepCopyObject() runtime.Object {
+func (in *BackendTrustPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -652,9 +980,107 @@ func (in *BindingResponse) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BindingResponseAuthentication) DeepCopyInto(out *BindingResponseAuthentication) {
+func (in *BackendTrustPolicySpec) DeepCopyInto(out *BackendTrustPolicySpec) {
 	*out = *in
-	if in.OAuth2CodeGrant != nil {
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]TrustedBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendTrustPolicySpec.
+func (in *BackendTrustPolicySpec) DeepCopy() *BackendTrustPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendTrustPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingProvider) DeepCopyInto(out *BindingProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.AuthenticationMethods != nil {
+		in, out := &in.AuthenticationMethods, &out.AuthenticationMethods
+		*out = make([]AuthenticationMethod, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingProvider.
+func (in *BindingProvider) DeepCopy() *BindingProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BindingProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingResponse) DeepCopyInto(out *BindingResponse) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.Authentication.DeepCopyInto(&out.Authentication)
+	if in.Kubeconfig != nil {
+		in, out := &in.Kubeconfig, &out.Kubeconfig
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingResponse.
+func (in *BindingResponse) DeepCopy() *BindingResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BindingResponse) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingResponseAuthentication) DeepCopyInto(out *BindingResponseAuthentication) {
+	*out = *in
+	if in.OAuth2CodeGrant != nil {
 		in, out := &in.OAuth2CodeGrant, &out.OAuth2CodeGrant
 		*out = new(BindingResponseAuthenticationOAuth2CodeGrant)
 		**out = **in
@@ -688,6 +1114,34 @@ func (in *BindingResponseAuthenticationOAuth2CodeGrant) DeepCopy() *BindingRespo
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimedObject) DeepCopyInto(out *ClaimedObject) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Object != nil {
+		in, out := &in.Object, &out.Object
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimedObject.
+func (in *ClaimedObject) DeepCopy() *ClaimedObject {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimedObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterBinding) DeepCopyInto(out *ClusterBinding) {
 	*out = *in
@@ -754,6 +1208,15 @@ func (in *ClusterBindingSpec) DeepCopyInto(out *ClusterBindingSpec) {
 	*out = *in
 	out.KubeconfigSecretRef = in.KubeconfigSecretRef
 	in.ServiceProviderSpec.DeepCopyInto(&out.ServiceProviderSpec)
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.KonnectorVersions != nil {
+		in, out := &in.KonnectorVersions, &out.KonnectorVersions
+		*out = new(KonnectorVersionRequirements)
+		**out = **in
+	}
 	return
 }
 
@@ -772,6 +1235,20 @@ func (in *ClusterBindingStatus) DeepCopyInto(out *ClusterBindingStatus) {
 	*out = *in
 	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
 	out.HeartbeatInterval = in.HeartbeatInterval
+	if in.KonnectorBuild != nil {
+		in, out := &in.KonnectorBuild, &out.KonnectorBuild
+		*out = new(KonnectorBuildInfo)
+		**out = **in
+	}
+	if in.CredentialsExpirationTime != nil {
+		in, out := &in.CredentialsExpirationTime, &out.CredentialsExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Konnector != nil {
+		in, out := &in.Konnector, &out.Konnector
+		*out = new(KonnectorStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(conditionsv1alpha1.Conditions, len(*in))
@@ -792,6 +1269,22 @@ func (in *ClusterBindingStatus) DeepCopy() *ClusterBindingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterScopedClaim) DeepCopyInto(out *ClusterScopedClaim) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterScopedClaim.
+func (in *ClusterScopedClaim) DeepCopy() *ClusterScopedClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterScopedClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSecretKeyRef) DeepCopyInto(out *ClusterSecretKeyRef) {
 	*out = *in
@@ -809,6 +1302,45 @@ func (in *ClusterSecretKeyRef) DeepCopy() *ClusterSecretKeyRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionStatus) DeepCopyInto(out *ConnectionStatus) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+	out.Latency = in.Latency
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionStatus.
+func (in *ConnectionStatus) DeepCopy() *ConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventRelay) DeepCopyInto(out *EventRelay) {
+	*out = *in
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventRelay.
+func (in *EventRelay) DeepCopy() *EventRelay {
+	if in == nil {
+		return nil
+	}
+	out := new(EventRelay)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupResource) DeepCopyInto(out *GroupResource) {
 	*out = *in
@@ -825,6 +1357,81 @@ func (in *GroupResource) DeepCopy() *GroupResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KonnectorBindingStatus) DeepCopyInto(out *KonnectorBindingStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KonnectorBindingStatus.
+func (in *KonnectorBindingStatus) DeepCopy() *KonnectorBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KonnectorBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KonnectorBuildInfo) DeepCopyInto(out *KonnectorBuildInfo) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KonnectorBuildInfo.
+func (in *KonnectorBuildInfo) DeepCopy() *KonnectorBuildInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(KonnectorBuildInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KonnectorStatus) DeepCopyInto(out *KonnectorStatus) {
+	*out = *in
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]KonnectorBindingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KonnectorStatus.
+func (in *KonnectorStatus) DeepCopy() *KonnectorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KonnectorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KonnectorVersionRequirements) DeepCopyInto(out *KonnectorVersionRequirements) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KonnectorVersionRequirements.
+func (in *KonnectorVersionRequirements) DeepCopy() *KonnectorVersionRequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(KonnectorVersionRequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalSecretKeyRef) DeepCopyInto(out *LocalSecretKeyRef) {
 	*out = *in
@@ -872,3 +1479,292 @@ func (in *OAuth2CodeGrant) DeepCopy() *OAuth2CodeGrant {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlanLimits) DeepCopyInto(out *PlanLimits) {
+	*out = *in
+	if in.MaxObjects != nil {
+		in, out := &in.MaxObjects, &out.MaxObjects
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxObjectSize != nil {
+		in, out := &in.MaxObjectSize, &out.MaxObjectSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanLimits.
+func (in *PlanLimits) DeepCopy() *PlanLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(PlanLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderNotice) DeepCopyInto(out *ProviderNotice) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderNotice.
+func (in *ProviderNotice) DeepCopy() *ProviderNotice {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderNotice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOPolicy) DeepCopyInto(out *SLOPolicy) {
+	*out = *in
+	out.LatencyTarget = in.LatencyTarget
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOPolicy.
+func (in *SLOPolicy) DeepCopy() *SLOPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOStatus) DeepCopyInto(out *SLOStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOStatus.
+func (in *SLOStatus) DeepCopy() *SLOStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountTokenClaim) DeepCopyInto(out *ServiceAccountTokenClaim) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountTokenClaim.
+func (in *ServiceAccountTokenClaim) DeepCopy() *ServiceAccountTokenClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountTokenClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountTokenStatus) DeepCopyInto(out *ServiceAccountTokenStatus) {
+	*out = *in
+	in.ExpirationTimestamp.DeepCopyInto(&out.ExpirationTimestamp)
+	in.RenewTimestamp.DeepCopyInto(&out.RenewTimestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountTokenStatus.
+func (in *ServiceAccountTokenStatus) DeepCopy() *ServiceAccountTokenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountTokenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusSyncPolicy) DeepCopyInto(out *StatusSyncPolicy) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]StatusFieldPath, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]StatusFieldPath, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusSyncPolicy.
+func (in *StatusSyncPolicy) DeepCopy() *StatusSyncPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusSyncPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncPolicy) DeepCopyInto(out *SyncPolicy) {
+	*out = *in
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxStaleness != nil {
+		in, out := &in.MaxStaleness, &out.MaxStaleness
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(SyncTuning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(SyncTuning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WatchNamespaces != nil {
+		in, out := &in.WatchNamespaces, &out.WatchNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncPolicy.
+func (in *SyncPolicy) DeepCopy() *SyncPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncTuning) DeepCopyInto(out *SyncTuning) {
+	*out = *in
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QPS != nil {
+		in, out := &in.QPS, &out.QPS
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncTuning.
+func (in *SyncTuning) DeepCopy() *SyncTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedBackend) DeepCopyInto(out *TrustedBackend) {
+	*out = *in
+	if in.Issuers != nil {
+		in, out := &in.Issuers, &out.Issuers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CAPins != nil {
+		in, out := &in.CAPins, &out.CAPins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedBackend.
+func (in *TrustedBackend) DeepCopy() *TrustedBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualClusterSecretKeyRef) DeepCopyInto(out *VirtualClusterSecretKeyRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualClusterSecretKeyRef.
+func (in *VirtualClusterSecretKeyRef) DeepCopy() *VirtualClusterSecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualClusterSecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualClusterTarget) DeepCopyInto(out *VirtualClusterTarget) {
+	*out = *in
+	out.KubeconfigSecretRef = in.KubeconfigSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualClusterTarget.
+func (in *VirtualClusterTarget) DeepCopy() *VirtualClusterTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualClusterTarget)
+	in.DeepCopyInto(out)
+	return out
+}
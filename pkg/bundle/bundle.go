@@ -0,0 +1,261 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle implements signed binding bundles. A service provider generates
+// a bundle out-of-band with the kubeconfig and the APIServiceExports of a consumer,
+// and the consumer installs it without a network path to the service provider
+// backend at bind time.
+//
+// A bundle is a gzipped tar archive with a manifest.json listing the SHA256 of
+// every other file, and a manifest.json.sig holding the ed25519 signature of the
+// manifest.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+const (
+	manifestFile   = "manifest.json"
+	signatureFile  = "manifest.json.sig"
+	kubeconfigFile = "kubeconfig"
+	exportsDir     = "exports/"
+
+	manifestVersion = "kube-bind.io/bundle/v1alpha1"
+
+	// maxFileSize limits the size of every file in a bundle.
+	maxFileSize = 16 << 20
+)
+
+// Bundle is the content of a binding bundle.
+type Bundle struct {
+	// Kubeconfig gives the konnector access to the consumer's namespace in the
+	// service provider cluster.
+	Kubeconfig []byte
+	// Exports are the APIServiceExports the consumer binds to.
+	Exports []*kubebindv1alpha1.APIServiceExport
+}
+
+type manifest struct {
+	APIVersion string            `json:"apiVersion"`
+	Files      map[string]string `json:"files"`
+}
+
+// Write writes the bundle as signed archive to w.
+func Write(w io.Writer, b *Bundle, key ed25519.PrivateKey) error {
+	if len(b.Kubeconfig) == 0 {
+		return errors.New("bundle kubeconfig must not be empty")
+	}
+	if len(b.Exports) == 0 {
+		return errors.New("bundle must contain at least one APIServiceExport")
+	}
+
+	files := map[string][]byte{kubeconfigFile: b.Kubeconfig}
+	for _, export := range b.Exports {
+		export = export.DeepCopy()
+		export.TypeMeta.APIVersion = kubebindv1alpha1.SchemeGroupVersion.String()
+		export.TypeMeta.Kind = "APIServiceExport"
+		export.ObjectMeta = metaForBundle(export)
+		export.Status = kubebindv1alpha1.APIServiceExportStatus{}
+		bs, err := yaml.Marshal(export)
+		if err != nil {
+			return err
+		}
+		files[exportsDir+export.Name+".yaml"] = bs
+	}
+
+	m := manifest{APIVersion: manifestVersion, Files: map[string]string{}}
+	for name, bs := range files {
+		sum := sha256.Sum256(bs)
+		m.Files[name] = hex.EncodeToString(sum[:])
+	}
+	manifestBytes, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	files[manifestFile] = manifestBytes
+	files[signatureFile] = ed25519.Sign(key, manifestBytes)
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	now := time.Now()
+	for _, name := range names {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(files[name])), ModTime: now}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// Read reads a bundle archive from r and verifies its signature with the given key.
+func Read(r io.Reader, key ed25519.PublicKey) (*Bundle, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	tr := tar.NewReader(gr)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > maxFileSize {
+			return nil, fmt.Errorf("invalid bundle: %s exceeds %d bytes", hdr.Name, maxFileSize)
+		}
+		bs, err := io.ReadAll(io.LimitReader(tr, maxFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+		files[hdr.Name] = bs
+	}
+
+	manifestBytes, found := files[manifestFile]
+	if !found {
+		return nil, fmt.Errorf("invalid bundle: %s missing", manifestFile)
+	}
+	if !ed25519.Verify(key, manifestBytes, files[signatureFile]) {
+		return nil, errors.New("invalid bundle: signature verification failed")
+	}
+	var m manifest
+	if err := json.Unmarshal(manifestBytes, &m); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if m.APIVersion != manifestVersion {
+		return nil, fmt.Errorf("unsupported bundle version %q", m.APIVersion)
+	}
+	for name, bs := range files {
+		if name == manifestFile || name == signatureFile {
+			continue
+		}
+		expected, found := m.Files[name]
+		if !found {
+			return nil, fmt.Errorf("invalid bundle: %s is not signed", name)
+		}
+		if sum := sha256.Sum256(bs); hex.EncodeToString(sum[:]) != expected {
+			return nil, fmt.Errorf("invalid bundle: checksum mismatch of %s", name)
+		}
+	}
+	for name := range m.Files {
+		if _, found := files[name]; !found {
+			return nil, fmt.Errorf("invalid bundle: %s missing", name)
+		}
+	}
+
+	b := &Bundle{Kubeconfig: files[kubeconfigFile]}
+	if len(b.Kubeconfig) == 0 {
+		return nil, fmt.Errorf("invalid bundle: %s missing", kubeconfigFile)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !strings.HasPrefix(name, exportsDir) {
+			continue
+		}
+		var export kubebindv1alpha1.APIServiceExport
+		if err := yaml.UnmarshalStrict(files[name], &export); err != nil {
+			return nil, fmt.Errorf("invalid bundle: failed to parse %s: %w", name, err)
+		}
+		b.Exports = append(b.Exports, &export)
+	}
+	if len(b.Exports) == 0 {
+		return nil, errors.New("invalid bundle: no APIServiceExports")
+	}
+
+	return b, nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS #8 ed25519 private key, e.g. generated
+// with "openssl genpkey -algorithm ed25519".
+func ParsePrivateKey(bs []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(bs)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an ed25519 private key, got %T", key)
+	}
+	return edKey, nil
+}
+
+// ParsePublicKey parses a PEM encoded PKIX ed25519 public key, e.g. generated
+// with "openssl pkey -pubout".
+func ParsePublicKey(bs []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(bs)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an ed25519 public key, got %T", key)
+	}
+	return edKey, nil
+}
+
+func metaForBundle(export *kubebindv1alpha1.APIServiceExport) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        export.Name,
+		Namespace:   export.Namespace,
+		Labels:      export.Labels,
+		Annotations: export.Annotations,
+	}
+}
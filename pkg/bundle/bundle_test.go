@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestBundle(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	b := &Bundle{
+		Kubeconfig: []byte("apiVersion: v1\nkind: Config\n"),
+		Exports: []*kubebindv1alpha1.APIServiceExport{{
+			ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com", Namespace: "cluster-abc", ResourceVersion: "42"},
+			Spec: kubebindv1alpha1.APIServiceExportSpec{
+				APIServiceExportCRDSpec: kubebindv1alpha1.APIServiceExportCRDSpec{
+					Group: "mangodb.com",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "mangodbs", Kind: "MangoDB"},
+				},
+			},
+		}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, b, priv))
+
+	tests := []struct {
+		name    string
+		archive func() []byte
+		key     ed25519.PublicKey
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			archive: buf.Bytes,
+			key:     pub,
+		},
+		{
+			name:    "wrong key",
+			archive: buf.Bytes,
+			key:     otherPub,
+			wantErr: "signature verification failed",
+		},
+		{
+			name: "tampered kubeconfig",
+			archive: func() []byte {
+				return rewrite(t, buf.Bytes(), func(name string, bs []byte) []byte {
+					if name == kubeconfigFile {
+						return []byte("apiVersion: v1\nkind: Config\nclusters: []\n")
+					}
+					return bs
+				})
+			},
+			key:     pub,
+			wantErr: "checksum mismatch of kubeconfig",
+		},
+		{
+			name: "added file",
+			archive: func() []byte {
+				return rewrite(t, buf.Bytes(), nil, "exports/evil.yaml")
+			},
+			key:     pub,
+			wantErr: "exports/evil.yaml is not signed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(bytes.NewReader(tt.archive()), tt.key)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, b.Kubeconfig, got.Kubeconfig)
+			require.Len(t, got.Exports, 1)
+			require.Equal(t, "mangodbs.mangodb.com", got.Exports[0].Name)
+			require.Empty(t, got.Exports[0].ResourceVersion)
+			require.Equal(t, b.Exports[0].Spec, got.Exports[0].Spec)
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	gotPriv, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}))
+	require.NoError(t, err)
+	require.Equal(t, priv, gotPriv)
+
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	gotPub, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+	require.NoError(t, err)
+	require.Equal(t, pub, gotPub)
+
+	_, err = ParsePublicKey([]byte("garbage"))
+	require.Error(t, err)
+}
+
+// rewrite modifies the files of a bundle archive with fn and appends empty
+// files with the extra names.
+func rewrite(t *testing.T, archive []byte, fn func(name string, bs []byte) []byte, extra ...string) []byte {
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	var out bytes.Buffer
+	gw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gw)
+	write := func(name string, bs []byte) {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(bs))}))
+		_, err := tw.Write(bs)
+		require.NoError(t, err)
+	}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		bs, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		if fn != nil {
+			bs = fn(hdr.Name, bs)
+		}
+		write(hdr.Name, bs)
+	}
+	for _, name := range extra {
+		write(name, []byte("{}"))
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return out.Bytes()
+}
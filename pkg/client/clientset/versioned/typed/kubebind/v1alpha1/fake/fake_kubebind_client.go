@@ -29,6 +29,10 @@ type FakeKubeBindV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeKubeBindV1alpha1) APIServiceActions(namespace string) v1alpha1.APIServiceActionInterface {
+	return &FakeAPIServiceActions{c, namespace}
+}
+
 func (c *FakeKubeBindV1alpha1) APIServiceBindings() v1alpha1.APIServiceBindingInterface {
 	return &FakeAPIServiceBindings{c}
 }
@@ -45,6 +49,10 @@ func (c *FakeKubeBindV1alpha1) APIServiceNamespaces(namespace string) v1alpha1.A
 	return &FakeAPIServiceNamespaces{c, namespace}
 }
 
+func (c *FakeKubeBindV1alpha1) BackendTrustPolicies() v1alpha1.BackendTrustPolicyInterface {
+	return &FakeBackendTrustPolicies{c}
+}
+
 func (c *FakeKubeBindV1alpha1) ClusterBindings(namespace string) v1alpha1.ClusterBindingInterface {
 	return &FakeClusterBindings{c, namespace}
 }
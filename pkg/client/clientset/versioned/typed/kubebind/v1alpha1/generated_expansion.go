@@ -18,6 +18,8 @@ limitations under the License.
 
 package v1alpha1
 
+type APIServiceActionExpansion interface{}
+
 type APIServiceBindingExpansion interface{}
 
 type APIServiceExportExpansion interface{}
@@ -26,4 +28,6 @@ type APIServiceExportRequestExpansion interface{}
 
 type APIServiceNamespaceExpansion interface{}
 
+type BackendTrustPolicyExpansion interface{}
+
 type ClusterBindingExpansion interface{}
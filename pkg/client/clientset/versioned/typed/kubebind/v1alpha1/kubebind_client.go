@@ -29,10 +29,12 @@ import (
 
 type KubeBindV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	APIServiceActionsGetter
 	APIServiceBindingsGetter
 	APIServiceExportsGetter
 	APIServiceExportRequestsGetter
 	APIServiceNamespacesGetter
+	BackendTrustPoliciesGetter
 	ClusterBindingsGetter
 }
 
@@ -41,6 +43,10 @@ type KubeBindV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *KubeBindV1alpha1Client) APIServiceActions(namespace string) APIServiceActionInterface {
+	return newAPIServiceActions(c, namespace)
+}
+
 func (c *KubeBindV1alpha1Client) APIServiceBindings() APIServiceBindingInterface {
 	return newAPIServiceBindings(c)
 }
@@ -57,6 +63,10 @@ func (c *KubeBindV1alpha1Client) APIServiceNamespaces(namespace string) APIServi
 	return newAPIServiceNamespaces(c, namespace)
 }
 
+func (c *KubeBindV1alpha1Client) BackendTrustPolicies() BackendTrustPolicyInterface {
+	return newBackendTrustPolicies(c)
+}
+
 func (c *KubeBindV1alpha1Client) ClusterBindings(namespace string) ClusterBindingInterface {
 	return newClusterBindings(c, namespace)
 }
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// APIServiceActions returns a APIServiceActionInformer.
+	APIServiceActions() APIServiceActionInformer
 	// APIServiceBindings returns a APIServiceBindingInformer.
 	APIServiceBindings() APIServiceBindingInformer
 	// APIServiceExports returns a APIServiceExportInformer.
@@ -32,6 +34,8 @@ type Interface interface {
 	APIServiceExportRequests() APIServiceExportRequestInformer
 	// APIServiceNamespaces returns a APIServiceNamespaceInformer.
 	APIServiceNamespaces() APIServiceNamespaceInformer
+	// BackendTrustPolicies returns a BackendTrustPolicyInformer.
+	BackendTrustPolicies() BackendTrustPolicyInformer
 	// ClusterBindings returns a ClusterBindingInformer.
 	ClusterBindings() ClusterBindingInformer
 }
@@ -47,6 +51,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// APIServiceActions returns a APIServiceActionInformer.
+func (v *version) APIServiceActions() APIServiceActionInformer {
+	return &aPIServiceActionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // APIServiceBindings returns a APIServiceBindingInformer.
 func (v *version) APIServiceBindings() APIServiceBindingInformer {
 	return &aPIServiceBindingInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -67,6 +76,11 @@ func (v *version) APIServiceNamespaces() APIServiceNamespaceInformer {
 	return &aPIServiceNamespaceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// BackendTrustPolicies returns a BackendTrustPolicyInformer.
+func (v *version) BackendTrustPolicies() BackendTrustPolicyInformer {
+	return &backendTrustPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterBindings returns a ClusterBindingInformer.
 func (v *version) ClusterBindings() ClusterBindingInformer {
 	return &clusterBindingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
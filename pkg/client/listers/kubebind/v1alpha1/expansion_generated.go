@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// APIServiceActionListerExpansion allows custom methods to be added to
+// APIServiceActionLister.
+type APIServiceActionListerExpansion interface{}
+
+// APIServiceActionNamespaceListerExpansion allows custom methods to be added to
+// APIServiceActionNamespaceLister.
+type APIServiceActionNamespaceListerExpansion interface{}
+
 // APIServiceBindingListerExpansion allows custom methods to be added to
 // APIServiceBindingLister.
 type APIServiceBindingListerExpansion interface{}
@@ -46,6 +54,10 @@ type APIServiceNamespaceListerExpansion interface{}
 // APIServiceNamespaceNamespaceLister.
 type APIServiceNamespaceNamespaceListerExpansion interface{}
 
+// BackendTrustPolicyListerExpansion allows custom methods to be added to
+// BackendTrustPolicyLister.
+type BackendTrustPolicyListerExpansion interface{}
+
 // ClusterBindingListerExpansion allows custom methods to be added to
 // ClusterBindingLister.
 type ClusterBindingListerExpansion interface{}
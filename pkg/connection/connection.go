@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+)
+
+// Check does a cheap authenticated call against the service provider cluster, i.e. a
+// GET of the ClusterBinding in the given namespace, and reports latency and auth status.
+func Check(ctx context.Context, client bindclient.Interface, namespace string) *kubebindv1alpha1.ConnectionStatus {
+	start := time.Now()
+	_, err := client.KubeBindV1alpha1().ClusterBindings(namespace).Get(ctx, "cluster", metav1.GetOptions{})
+	status := &kubebindv1alpha1.ConnectionStatus{
+		LastCheckTime: metav1.NewTime(start).Rfc3339Copy(),
+		Latency:       metav1.Duration{Duration: time.Since(start).Round(time.Millisecond)},
+	}
+
+	switch {
+	case err == nil || errors.IsNotFound(err):
+		status.AuthStatus = kubebindv1alpha1.ConnectionAuthorized
+	case errors.IsUnauthorized(err):
+		status.AuthStatus = kubebindv1alpha1.ConnectionUnauthorized
+		status.Message = err.Error()
+	case errors.IsForbidden(err):
+		status.AuthStatus = kubebindv1alpha1.ConnectionForbidden
+		status.Message = err.Error()
+	default:
+		status.AuthStatus = kubebindv1alpha1.ConnectionUnreachable
+		status.Message = err.Error()
+	}
+
+	return status
+}
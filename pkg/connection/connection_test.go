@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindfake "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned/fake"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  kubebindv1alpha1.ConnectionAuthStatus
+		wantMessage bool
+	}{
+		{name: "success", wantStatus: kubebindv1alpha1.ConnectionAuthorized},
+		{name: "no ClusterBinding yet", err: errors.NewNotFound(kubebindv1alpha1.Resource("clusterbindings"), "cluster"), wantStatus: kubebindv1alpha1.ConnectionAuthorized},
+		{name: "unauthorized", err: errors.NewUnauthorized("token expired"), wantStatus: kubebindv1alpha1.ConnectionUnauthorized, wantMessage: true},
+		{name: "forbidden", err: errors.NewForbidden(kubebindv1alpha1.Resource("clusterbindings"), "cluster", fmt.Errorf("no RBAC")), wantStatus: kubebindv1alpha1.ConnectionForbidden, wantMessage: true},
+		{name: "network error", err: fmt.Errorf("dial tcp 10.0.0.1:6443: connect: connection refused"), wantStatus: kubebindv1alpha1.ConnectionUnreachable, wantMessage: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := bindfake.NewSimpleClientset()
+			client.PrependReactor("get", "clusterbindings", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				require.Equal(t, "kube-bind-abc", action.GetNamespace())
+				return true, &kubebindv1alpha1.ClusterBinding{}, tt.err
+			})
+
+			status := Check(context.Background(), client, "kube-bind-abc")
+			require.Equal(t, tt.wantStatus, status.AuthStatus)
+			require.False(t, status.LastCheckTime.IsZero())
+			if tt.wantMessage {
+				require.Equal(t, tt.err.Error(), status.Message)
+			} else {
+				require.Empty(t, status.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features defines the feature gates shared by the konnector, the
+// example backend and kubectl bind. New subsystems ship behind an alpha gate
+// that is disabled by default, and are enabled progressively with
+// --feature-gates=<Feature>=true.
+package features
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+const (
+	// ClaimsV2 enables the second version of claims. Without it, the backend
+	// does not advertise the ClaimsV2 capability, and consumers cannot require it.
+	//
+	// alpha: v0.1
+	ClaimsV2 featuregate.Feature = "ClaimsV2"
+)
+
+var (
+	// DefaultMutableFeatureGate is the feature gate of the component, set with --feature-gates.
+	DefaultMutableFeatureGate featuregate.MutableFeatureGate = featuregate.NewFeatureGate()
+
+	// DefaultFeatureGate is the read-only view of DefaultMutableFeatureGate.
+	DefaultFeatureGate featuregate.FeatureGate = DefaultMutableFeatureGate
+)
+
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	ClaimsV2: {Default: false, PreRelease: featuregate.Alpha},
+}
+
+// capabilityGates are the APIServiceExport capabilities behind a feature gate.
+var capabilityGates = map[kubebindv1alpha1.APIServiceExportCapability]featuregate.Feature{
+	kubebindv1alpha1.APIServiceExportCapabilityClaimsV2: ClaimsV2,
+}
+
+func init() {
+	runtime.Must(DefaultMutableFeatureGate.Add(defaultFeatureGates))
+}
+
+// SetFromConfigFile sets the feature gates of a configuration file on
+// DefaultMutableFeatureGate. Gates given with --feature-gates on fs take
+// precedence.
+func SetFromConfigFile(fs *pflag.FlagSet, gates map[string]bool) error {
+	if len(gates) == 0 {
+		return nil
+	}
+	var flagged string
+	if flag := fs.Lookup("feature-gates"); flag != nil && flag.Changed {
+		flagged = flag.Value.String()
+	}
+	if err := DefaultMutableFeatureGate.SetFromMap(gates); err != nil {
+		return err
+	}
+	if flagged != "" {
+		return DefaultMutableFeatureGate.Set(flagged)
+	}
+	return nil
+}
+
+// CapabilityEnabled returns whether the APIServiceExport capability is not behind
+// a disabled feature gate.
+func CapabilityEnabled(gate featuregate.FeatureGate, capability kubebindv1alpha1.APIServiceExportCapability) bool {
+	feature, found := capabilityGates[capability]
+	return !found || gate.Enabled(feature)
+}
+
+// ValidateCapabilities returns an error if one of the capabilities is unknown or
+// behind a disabled feature gate.
+func ValidateCapabilities(gate featuregate.FeatureGate, capabilities []string) error {
+	for _, c := range capabilities {
+		if !helpers.KnownCapability(kubebindv1alpha1.APIServiceExportCapability(c)) {
+			return fmt.Errorf("unknown capability %q", c)
+		}
+		if !CapabilityEnabled(gate, kubebindv1alpha1.APIServiceExportCapability(c)) {
+			return fmt.Errorf("capability %s requires --feature-gates=%s=true", c, capabilityGates[kubebindv1alpha1.APIServiceExportCapability(c)])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCapabilities(t *testing.T) {
+	tests := []struct {
+		name         string
+		gates        map[string]bool
+		capabilities []string
+		wantErr      string
+	}{
+		{name: "ungated", capabilities: []string{"ScaleSubresource", "ReadOnly"}},
+		{name: "disabled", capabilities: []string{"ReadOnly", "ClaimsV2"}, wantErr: "capability ClaimsV2 requires --feature-gates=ClaimsV2=true"},
+		{name: "enabled", gates: map[string]bool{"ClaimsV2": true}, capabilities: []string{"ClaimsV2"}},
+		{name: "unknown", capabilities: []string{"ReadOnly", "Teleport"}, wantErr: `unknown capability "Teleport"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := DefaultMutableFeatureGate.DeepCopy()
+			require.NoError(t, gate.SetFromMap(tt.gates))
+
+			err := ValidateCapabilities(gate, tt.capabilities)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
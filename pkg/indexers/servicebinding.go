@@ -18,10 +18,13 @@ package indexers
 
 import (
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 )
 
 const (
-	ByServiceBindingKubeconfigSecret = "byKubeconfigSecret"
+	ByServiceBindingKubeconfigSecret     = "byKubeconfigSecret"
+	ByServiceBindingVirtualClusterSecret = "byVirtualClusterSecret"
+	ByServiceBindingExport               = "byExport"
 )
 
 func IndexServiceBindingByKubeconfigSecret(obj interface{}) ([]string, error) {
@@ -36,3 +39,26 @@ func ByServiceBindingKubeconfigSecretKey(binding *kubebindv1alpha1.APIServiceBin
 	ref := &binding.Spec.KubeconfigSecretRef
 	return ref.Namespace + "/" + ref.Name
 }
+
+func IndexServiceBindingByVirtualClusterSecret(obj interface{}) ([]string, error) {
+	binding, ok := obj.(*kubebindv1alpha1.APIServiceBinding)
+	if !ok || binding.Spec.VirtualCluster == nil {
+		return nil, nil
+	}
+	ref := &binding.Spec.VirtualCluster.KubeconfigSecretRef
+	return []string{ref.Namespace + "/" + ref.Name}, nil
+}
+
+func IndexServiceBindingByExport(obj interface{}) ([]string, error) {
+	binding, ok := obj.(*kubebindv1alpha1.APIServiceBinding)
+	if !ok {
+		return nil, nil
+	}
+	return []string{ByServiceBindingExportKey(ByServiceBindingKubeconfigSecretKey(binding), helpers.ExportName(binding))}, nil
+}
+
+// ByServiceBindingExportKey returns the index key of the bindings of the given export of the service
+// provider of the given kubeconfig secret key.
+func ByServiceBindingExportKey(secretKey, exportName string) string {
+	return secretKey + "/" + exportName
+}
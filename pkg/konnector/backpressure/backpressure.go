@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backpressure slows down the upsync to service providers that signal
+// overload, either by the kube-bind.io/backpressure annotation on their
+// APIServiceExports or by answering writes with 429 Too Many Requests.
+package backpressure
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultRetryAfter is the delay after a 429 response without Retry-After.
+const DefaultRetryAfter = 5 * time.Second
+
+// Tracker keeps the throttles by APIServiceExport name. It is safe for concurrent use.
+type Tracker struct {
+	lock      sync.Mutex
+	throttles map[string]*Throttle
+	onChange  []func(name string)
+}
+
+// NewTracker returns an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{throttles: map[string]*Throttle{}}
+}
+
+// OnChange registers f to be called when an export becomes throttled or unthrottled.
+func (t *Tracker) OnChange(f func(name string)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.onChange = append(t.onChange, f)
+}
+
+// Throttle returns the throttle of the given export, creating it if needed.
+func (t *Tracker) Throttle(name string) *Throttle {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if th, found := t.throttles[name]; found {
+		return th
+	}
+	th := &Throttle{changed: func() { t.notify(name) }}
+	t.throttles[name] = th
+	return th
+}
+
+// Forget drops the throttle of the given export.
+func (t *Tracker) Forget(name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.throttles, name)
+}
+
+// State returns whether the given export is throttled and a human readable reason.
+func (t *Tracker) State(name string, now time.Time) (bool, string) {
+	t.lock.Lock()
+	th, found := t.throttles[name]
+	t.lock.Unlock()
+	if !found {
+		return false, ""
+	}
+	return th.State(now)
+}
+
+func (t *Tracker) notify(name string) {
+	t.lock.Lock()
+	callbacks := t.onChange
+	t.lock.Unlock()
+
+	for _, f := range callbacks {
+		f(name)
+	}
+}
+
+// Throttle paces the upsync writes of one export.
+type Throttle struct {
+	lock sync.Mutex
+	// interval is the minimal time between writes the provider asks for.
+	interval time.Duration
+	// next is the earliest time of the next write by interval.
+	next time.Time
+	// blockedUntil is the end of the back-off after a 429 response.
+	blockedUntil time.Time
+
+	changed func()
+}
+
+// SetInterval sets the minimal time between writes the service provider announced. Zero
+// disables pacing.
+func (th *Throttle) SetInterval(interval time.Duration) {
+	th.lock.Lock()
+	if th.interval == interval {
+		th.lock.Unlock()
+		return
+	}
+	th.interval = interval
+	th.lock.Unlock()
+
+	th.changed()
+}
+
+// Reject records a 429 response and blocks writes for retryAfter.
+func (th *Throttle) Reject(now time.Time, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = DefaultRetryAfter
+	}
+
+	th.lock.Lock()
+	until := now.Add(retryAfter)
+	if !until.After(th.blockedUntil) {
+		th.lock.Unlock()
+		return
+	}
+	wasBlocked := th.blockedUntil.After(now)
+	th.blockedUntil = until
+	th.lock.Unlock()
+
+	if !wasBlocked {
+		th.changed()
+	}
+	// report the end of the back-off
+	time.AfterFunc(retryAfter, th.changed)
+}
+
+// Delay returns how long to wait before the next write. If zero, the write may happen
+// now and is accounted for.
+func (th *Throttle) Delay(now time.Time) time.Duration {
+	if th == nil {
+		return 0
+	}
+
+	th.lock.Lock()
+	defer th.lock.Unlock()
+
+	if th.blockedUntil.After(now) {
+		return th.blockedUntil.Sub(now)
+	}
+	if th.interval <= 0 {
+		return 0
+	}
+	if th.next.After(now) {
+		return th.next.Sub(now)
+	}
+	th.next = now.Add(th.interval)
+	return 0
+}
+
+// State returns whether writes are throttled and a human readable reason.
+func (th *Throttle) State(now time.Time) (bool, string) {
+	th.lock.Lock()
+	defer th.lock.Unlock()
+
+	if th.blockedUntil.After(now) {
+		return true, fmt.Sprintf("The service provider rejected writes with 429 Too Many Requests, retrying in %s.", th.blockedUntil.Sub(now).Round(time.Second))
+	}
+	if th.interval > 0 {
+		return true, fmt.Sprintf("The service provider asked to slow down to one write per %s.", th.interval)
+	}
+	return false, ""
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backpressure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottleDelay(t *testing.T) {
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("nil", func(t *testing.T) {
+		var th *Throttle
+		require.Zero(t, th.Delay(now))
+	})
+
+	t.Run("unthrottled", func(t *testing.T) {
+		th := &Throttle{changed: func() {}}
+		require.Zero(t, th.Delay(now))
+		require.Zero(t, th.Delay(now))
+		throttled, _ := th.State(now)
+		require.False(t, throttled)
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		th := &Throttle{changed: func() {}}
+		th.SetInterval(time.Second)
+		require.Zero(t, th.Delay(now))
+		require.Equal(t, time.Second, th.Delay(now))
+		require.Equal(t, 500*time.Millisecond, th.Delay(now.Add(500*time.Millisecond)))
+		require.Zero(t, th.Delay(now.Add(time.Second)))
+		throttled, _ := th.State(now)
+		require.True(t, throttled)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		changes := 0
+		th := &Throttle{changed: func() { changes++ }}
+		th.Reject(now, time.Hour)
+		th.Reject(now, time.Minute)
+		require.Equal(t, 1, changes)
+		require.Equal(t, time.Hour, th.Delay(now))
+		throttled, _ := th.State(now)
+		require.True(t, throttled)
+		require.Zero(t, th.Delay(now.Add(time.Hour)))
+	})
+}
@@ -17,44 +17,78 @@ limitations under the License.
 package konnector
 
 import (
-	"time"
-
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	kubeinformers "k8s.io/client-go/informers"
 	kubernetesclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
 	"github.com/kube-bind/kube-bind/pkg/konnector/options"
+	"github.com/kube-bind/kube-bind/pkg/konnector/selfupgrade"
+	"github.com/kube-bind/kube-bind/pkg/konnector/sharding"
+	"github.com/kube-bind/kube-bind/pkg/konnector/tracing"
 )
 
 type Config struct {
+	Options *options.CompletedOptions
+
 	ClientConfig        *rest.Config
 	BindClient          *bindclient.Clientset
 	KubeClient          *kubernetesclient.Clientset
 	ApiextensionsClient *apiextensionsclient.Clientset
+	MetadataClient      metadata.Interface
 
 	KubeInformers          kubeinformers.SharedInformerFactory
 	BindInformers          bindinformers.SharedInformerFactory
 	ApiextensionsInformers apiextensionsinformers.SharedInformerFactory
+	MetadataInformers      metadatainformer.SharedInformerFactory
+	// ProviderCAInformers watch the --provider-ca-configmap ConfigMap. They are
+	// nil if it is not set.
+	ProviderCAInformers kubeinformers.SharedInformerFactory
+
+	// Shards tells which APIServiceBindings this replica serves with --sharding.
+	// If nil, it serves all.
+	Shards sharding.Owner
+
+	// VersionRequirements collects the konnector versions required by the
+	// service providers of all consumers. If nil, they are only reported in the
+	// VersionSkew condition.
+	VersionRequirements *selfupgrade.Requirements
 }
 
 func NewConfig(options *options.CompletedOptions) (*Config, error) {
-	config := &Config{}
+	return NewConsumerConfig(options, options.KubeConfigPath, "")
+}
+
+// NewConsumerConfig returns a Config for the consumer cluster of the given
+// kubeconfig and context. Empty values fall back to the default loading rules
+// and the current context.
+func NewConsumerConfig(options *options.CompletedOptions, kubeconfigPath, context string) (*Config, error) {
+	config := &Config{
+		Options: options,
+	}
 
 	// create clients
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
-	rules.ExplicitPath = options.KubeConfigPath
+	rules.ExplicitPath = kubeconfigPath
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: context}
 	var err error
-	config.ClientConfig, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, nil).ClientConfig()
+	config.ClientConfig, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
 	if err != nil {
 		return nil, err
 	}
-	config.ClientConfig = rest.CopyConfig(config.ClientConfig)
+	config.ClientConfig = options.KubeAPI.Config(config.ClientConfig)
 	config.ClientConfig = rest.AddUserAgent(config.ClientConfig, "konnector")
+	tracing.WrapConfig(config.ClientConfig)
 
 	if config.BindClient, err = bindclient.NewForConfig(config.ClientConfig); err != nil {
 		return nil, err
@@ -65,11 +99,36 @@ func NewConfig(options *options.CompletedOptions) (*Config, error) {
 	if config.ApiextensionsClient, err = apiextensionsclient.NewForConfig(config.ClientConfig); err != nil {
 		return nil, err
 	}
+	if config.MetadataClient, err = metadata.NewForConfig(config.ClientConfig); err != nil {
+		return nil, err
+	}
 
 	// construct informer factories
-	config.KubeInformers = kubeinformers.NewSharedInformerFactory(config.KubeClient, time.Minute*30)
-	config.BindInformers = bindinformers.NewSharedInformerFactory(config.BindClient, time.Minute*30)
-	config.ApiextensionsInformers = apiextensionsinformers.NewSharedInformerFactory(config.ApiextensionsClient, time.Minute*30)
+	config.KubeInformers = kubeinformers.NewSharedInformerFactory(config.KubeClient, options.Resync.Period)
+	config.BindInformers = bindinformers.NewSharedInformerFactory(config.BindClient, options.Resync.Period)
+	config.ApiextensionsInformers = apiextensionsinformers.NewSharedInformerFactory(config.ApiextensionsClient, options.Resync.Period)
+	config.MetadataInformers = metadatainformer.NewSharedInformerFactory(config.MetadataClient, options.Resync.Period)
+
+	if options.ProviderCAConfigMap != "" {
+		ns, name, err := options.ProviderCAConfigMapNamespaceName()
+		if err != nil {
+			return nil, err
+		}
+		config.ProviderCAInformers = kubeinformers.NewSharedInformerFactoryWithOptions(config.KubeClient, options.Resync.Period,
+			kubeinformers.WithNamespace(ns),
+			kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+			}),
+		)
+	}
+
+	// keep only what the controllers read in the caches of large clusters
+	if err := config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions().Informer().SetTransform(dynamic.TrimCustomResourceDefinition); err != nil {
+		return nil, err
+	}
+	if err := config.MetadataInformers.ForResource(corev1.SchemeGroupVersion.WithResource("namespaces")).Informer().SetTransform(dynamic.TrimObjectMetadata); err != nil {
+		return nil, err
+	}
 
 	return config, nil
 }
@@ -22,13 +22,17 @@ import (
 	"reflect"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
 	crdlisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	kubernetesinformers "k8s.io/client-go/informers"
 	kubernetesclient "k8s.io/client-go/kubernetes"
-	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -40,11 +44,17 @@ import (
 	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions"
 	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/indexers"
+	"github.com/kube-bind/kube-bind/pkg/konnector/backpressure"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/clusterbinding"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/namespacedeletion"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceaction"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/servicebinding"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/policy"
+	"github.com/kube-bind/kube-bind/pkg/konnector/selfupgrade"
+	"github.com/kube-bind/kube-bind/pkg/konnector/slo"
 )
 
 const (
@@ -58,9 +68,25 @@ func NewController(
 	consumerSecretRefKey string,
 	providerNamespace string,
 	consumerConfig, providerConfig *rest.Config,
-	namespaceInformer dynamic.Informer[corelisters.NamespaceLister],
+	virtualClusterConfig *rest.Config,
+	namespaceInformer dynamic.Informer[cache.GenericLister],
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	crdInformer dynamic.Informer[crdlisters.CustomResourceDefinitionLister],
+	syncedMaxStaleness time.Duration,
+	hibernateIdleAfter time.Duration,
+	canaryInterval time.Duration,
+	snapshotDir string,
+	syncTuning tuning.Sync,
+	resync tuning.Resync,
+	workers tuning.Workers,
+	upsyncPolicy *policy.Evaluator,
+	crdAllowlist []string,
+	allowedRegions []string,
+	watchNamespaces []string,
+	syncedPrinterColumns bool,
+	versionRequirements *selfupgrade.Requirements,
+	identity string,
+	rateLimiter tuning.RateLimiter,
 ) (*controller, error) {
 	consumerConfig = rest.CopyConfig(consumerConfig)
 	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
@@ -85,24 +111,55 @@ func NewController(
 	if err != nil {
 		return nil, err
 	}
-	providerBindInformers := bindinformers.NewSharedInformerFactoryWithOptions(providerBindClient, time.Minute*30, bindinformers.WithNamespace(providerNamespace))
-	providerKubeInformers := kubernetesinformers.NewSharedInformerFactoryWithOptions(providerKubeClient, time.Minute*30, kubernetesinformers.WithNamespace(providerNamespace))
+	providerBindInformers := bindinformers.NewSharedInformerFactoryWithOptions(providerBindClient, resync.Period, bindinformers.WithNamespace(providerNamespace))
+	providerKubeInformers := kubernetesinformers.NewSharedInformerFactoryWithOptions(providerKubeClient, resync.Period, kubernetesinformers.WithNamespace(providerNamespace))
 	consumerSecretNS, consumeSecretName, err := cache.SplitMetaNamespaceKey(consumerSecretRefKey)
 	if err != nil {
 		return nil, err
 	}
-	consumerSecretInformers := kubernetesinformers.NewSharedInformerFactoryWithOptions(consumerKubeClient, time.Minute*30,
+	consumerSecretInformers := kubernetesinformers.NewSharedInformerFactoryWithOptions(consumerKubeClient, resync.Period,
 		kubernetesinformers.WithNamespace(consumerSecretNS),
 		kubernetesinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
 			options.FieldSelector = fmt.Sprintf("metadata.name=%s", consumeSecretName)
 		}),
 	)
 
+	// bound CRDs and objects materialize in the virtual cluster if there is one
+	targetConfig := consumerConfig
+	var targetFactories []SharedInformerFactory
+	if virtualClusterConfig != nil {
+		targetConfig = rest.CopyConfig(virtualClusterConfig)
+		targetConfig = rest.AddUserAgent(targetConfig, controllerName)
+
+		targetMetadataClient, err := metadata.NewForConfig(targetConfig)
+		if err != nil {
+			return nil, err
+		}
+		targetApiextensionsClient, err := apiextensionsclient.NewForConfig(targetConfig)
+		if err != nil {
+			return nil, err
+		}
+		targetMetadataInformers := metadatainformer.NewSharedInformerFactory(targetMetadataClient, resync.Period)
+		targetApiextensionsInformers := apiextensionsinformers.NewSharedInformerFactory(targetApiextensionsClient, resync.Period)
+		targetNamespaceInformer := targetMetadataInformers.ForResource(corev1.SchemeGroupVersion.WithResource("namespaces"))
+		if err := targetNamespaceInformer.Informer().SetTransform(dynamic.TrimObjectMetadata); err != nil {
+			return nil, err
+		}
+		targetCRDInformer := targetApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions()
+		if err := targetCRDInformer.Informer().SetTransform(dynamic.TrimCustomResourceDefinition); err != nil {
+			return nil, err
+		}
+		namespaceInformer = dynamic.NewDynamicInformer[cache.GenericLister](targetNamespaceInformer)
+		crdInformer = dynamic.NewDynamicInformer[crdlisters.CustomResourceDefinitionLister](targetCRDInformer)
+		targetFactories = append(targetFactories, metadataInformerFactory{targetMetadataInformers}, targetApiextensionsInformers)
+	}
+
 	// create controllers
 	clusterbindingCtrl, err := clusterbinding.NewController(
 		consumerSecretRefKey,
 		providerNamespace,
 		heartbeatInterval,
+		identity,
 		consumerConfig,
 		providerConfig,
 		providerBindInformers.KubeBind().V1alpha1().ClusterBindings(),
@@ -110,6 +167,8 @@ func NewController(
 		providerBindInformers.KubeBind().V1alpha1().APIServiceExports(),
 		consumerSecretInformers.Core().V1().Secrets(),
 		providerKubeInformers.Core().V1().Secrets(),
+		versionRequirements,
+		rateLimiter,
 	)
 	if err != nil {
 		return nil, err
@@ -118,18 +177,30 @@ func NewController(
 		providerConfig,
 		providerBindInformers.KubeBind().V1alpha1().APIServiceNamespaces(),
 		namespaceInformer,
+		rateLimiter,
 	)
 	if err != nil {
 		return nil, err
 	}
+	// canary probes of the exports feed the SLO of the bindings
+	sloTracker := slo.NewTracker()
+	// overload signals of the service provider slow down the upsync of the bindings
+	backpressureTracker := backpressure.NewTracker()
 	servicebindingCtrl, err := servicebinding.NewController(
 		consumerSecretRefKey,
 		providerNamespace,
 		consumerConfig,
+		targetConfig,
 		providerConfig,
 		serviceBindingInformer,
 		providerBindInformers.KubeBind().V1alpha1().APIServiceExports(),
 		crdInformer,
+		crdAllowlist,
+		allowedRegions,
+		syncedPrinterColumns,
+		sloTracker,
+		backpressureTracker,
+		rateLimiter,
 	)
 	if err != nil {
 		return nil, err
@@ -137,12 +208,36 @@ func NewController(
 	serviceexportCtrl, err := serviceexport.NewController(
 		consumerSecretRefKey,
 		providerNamespace,
-		consumerConfig,
+		targetConfig,
 		providerConfig,
 		providerBindInformers.KubeBind().V1alpha1().APIServiceExports(),
 		providerBindInformers.KubeBind().V1alpha1().APIServiceNamespaces(),
 		serviceBindingInformer,
 		crdInformer,
+		syncedMaxStaleness,
+		hibernateIdleAfter,
+		canaryInterval,
+		snapshotDir,
+		syncTuning,
+		resync,
+		upsyncPolicy,
+		allowedRegions,
+		watchNamespaces,
+		sloTracker,
+		backpressureTracker,
+		rateLimiter,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceactionCtrl, err := serviceaction.NewController(
+		consumerSecretRefKey,
+		consumerConfig,
+		providerConfig,
+		providerBindInformers.KubeBind().V1alpha1().APIServiceActions(),
+		serviceBindingInformer,
+		rateLimiter,
 	)
 	if err != nil {
 		return nil, err
@@ -150,14 +245,15 @@ func NewController(
 
 	return &controller{
 		consumerSecretRefKey: consumerSecretRefKey,
+		workers:              workers,
 
 		bindClient: consumerBindClient,
 
-		factories: []SharedInformerFactory{
+		factories: append([]SharedInformerFactory{
 			providerBindInformers,
 			providerKubeInformers,
 			consumerSecretInformers,
-		},
+		}, targetFactories...),
 
 		serviceBindingLister:  serviceBindingInformer.Lister(),
 		serviceBindingIndexer: serviceBindingInformer.Informer().GetIndexer(),
@@ -166,6 +262,7 @@ func NewController(
 		namespacedeletionCtrl:      namespacedeletionCtrl,
 		servicebindingCtrl:         servicebindingCtrl,
 		serviceresourcebindingCtrl: serviceexportCtrl,
+		serviceactionCtrl:          serviceactionCtrl,
 	}, nil
 }
 
@@ -178,9 +275,24 @@ type SharedInformerFactory interface {
 	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
 }
 
+// metadataInformerFactory adapts a metadata informer factory, whose informers
+// are all of type PartialObjectMetadata, to SharedInformerFactory.
+type metadataInformerFactory struct {
+	metadatainformer.SharedInformerFactory
+}
+
+func (f metadataInformerFactory) WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool {
+	allSynced := true
+	for _, synced := range f.SharedInformerFactory.WaitForCacheSync(stopCh) {
+		allSynced = allSynced && synced
+	}
+	return map[reflect.Type]bool{reflect.TypeOf(&metav1.PartialObjectMetadata{}): allSynced}
+}
+
 // controller holding all controller that are per provider cluster.
 type controller struct {
 	consumerSecretRefKey string
+	workers              tuning.Workers
 
 	bindClient bindclient.Interface
 
@@ -193,6 +305,7 @@ type controller struct {
 	namespacedeletionCtrl      GenericController
 	servicebindingCtrl         GenericController
 	serviceresourcebindingCtrl GenericController
+	serviceactionCtrl          GenericController
 }
 
 // Start starts the controller, which stops when ctx.Done() is closed.
@@ -243,10 +356,11 @@ func (c *controller) Start(ctx context.Context) {
 		conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionInformersSynced)
 	})
 
-	go c.clusterbindingCtrl.Start(ctx, 2)
-	go c.namespacedeletionCtrl.Start(ctx, 2)
-	go c.servicebindingCtrl.Start(ctx, 2)
-	go c.serviceresourcebindingCtrl.Start(ctx, 2)
+	go c.clusterbindingCtrl.Start(ctx, c.workers.NumDefault())
+	go c.namespacedeletionCtrl.Start(ctx, c.workers.NumDefault())
+	go c.servicebindingCtrl.Start(ctx, c.workers.NumServiceBinding())
+	go c.serviceresourcebindingCtrl.Start(ctx, c.workers.NumDefault())
+	go c.serviceactionCtrl.Start(ctx, 1)
 
 	<-ctx.Done()
 }
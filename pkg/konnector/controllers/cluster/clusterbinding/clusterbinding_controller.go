@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -43,8 +44,12 @@ import (
 	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
 	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/committer"
+	"github.com/kube-bind/kube-bind/pkg/connection"
 	"github.com/kube-bind/kube-bind/pkg/indexers"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/drain"
+	"github.com/kube-bind/kube-bind/pkg/konnector/selfupgrade"
 )
 
 const (
@@ -56,13 +61,16 @@ func NewController(
 	consumerSecretRefKey string,
 	providerNamespace string,
 	heartbeatInterval time.Duration,
+	identity string,
 	consumerConfig, providerConfig *rest.Config,
 	clusterBindingInformer bindinformers.ClusterBindingInformer,
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	serviceExportInformer bindinformers.APIServiceExportInformer,
 	consumerSecretInformer, providerSecretInformer coreinformers.SecretInformer,
+	versionRequirements *selfupgrade.Requirements,
+	rateLimiter tuning.RateLimiter,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -112,7 +120,24 @@ func NewController(
 			consumerSecretRefKey: consumerSecretRefKey,
 			providerNamespace:    providerNamespace,
 			heartbeatInterval:    heartbeatInterval,
+			identity:             identity,
 
+			reportVersionRequirements: func(req *kubebindv1alpha1.KonnectorVersionRequirements) {
+				if versionRequirements != nil {
+					versionRequirements.Set(providerConfig.Host+"/"+providerNamespace, req)
+				}
+			},
+			listServiceBindings: func() ([]*kubebindv1alpha1.APIServiceBinding, error) {
+				objs, err := serviceBindingInformer.Informer().GetIndexer().ByIndex(indexers.ByServiceBindingKubeconfigSecret, consumerSecretRefKey)
+				if err != nil {
+					return nil, err
+				}
+				ret := make([]*kubebindv1alpha1.APIServiceBinding, 0, len(objs))
+				for _, obj := range objs {
+					ret = append(ret, obj.(*kubebindv1alpha1.APIServiceBinding))
+				}
+				return ret, nil
+			},
 			getProviderSecret: func() (*corev1.Secret, error) {
 				cb, err := clusterBindingInformer.Lister().ClusterBindings(providerNamespace).Get("cluster")
 				if err != nil {
@@ -231,6 +256,9 @@ type controller struct {
 	reconciler
 
 	commit CommitFunc
+
+	lock           sync.Mutex
+	lastConnection *kubebindv1alpha1.ConnectionStatus
 }
 
 func (c *controller) enqueueClusterBinding(logger klog.Logger, obj interface{}) {
@@ -316,6 +344,7 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 
 	logger.Info("Starting controller")
 	defer logger.Info("Shutting down controller")
+	defer c.reconciler.reportVersionRequirements(nil) // the binding is gone or the konnector stops
 
 	for i := 0; i < numThreads; i++ {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
@@ -354,7 +383,13 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	started, err := drain.Process(ctx, func(ctx context.Context) error {
+		return c.process(ctx, key)
+	})
+	if !started {
+		return false // shutting down, don't start new work
+	}
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		c.queue.AddRateLimited(key)
 		return true
@@ -397,11 +432,13 @@ func (c *controller) process(ctx context.Context, key string) error {
 	// If the object being reconciled changed as a result, update it.
 	oldResource := &Resource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
 	newResource := &Resource{ObjectMeta: obj.ObjectMeta, Spec: &obj.Spec, Status: &obj.Status}
+	connectionStatus := c.checkConnection(ctx)
 	if err := c.commit(ctx, oldResource, newResource); err != nil {
 		errs = append(errs, err)
 
 		// try to update service bindings
 		c.updateServiceBindings(ctx, func(binding *kubebindv1alpha1.APIServiceBinding) {
+			binding.Status.Connection = connectionStatus
 			conditions.MarkFalse(
 				binding,
 				kubebindv1alpha1.APIServiceBindingConditionHeartbeating,
@@ -413,6 +450,7 @@ func (c *controller) process(ctx context.Context, key string) error {
 	} else {
 		// try to update service bindings
 		c.updateServiceBindings(ctx, func(binding *kubebindv1alpha1.APIServiceBinding) {
+			binding.Status.Connection = connectionStatus
 			conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionHeartbeating)
 		})
 	}
@@ -420,6 +458,24 @@ func (c *controller) process(ctx context.Context, key string) error {
 	return utilerrors.NewAggregate(errs)
 }
 
+// checkConnection does a credential check against the service provider cluster,
+// at most once per half heartbeat interval.
+func (c *controller) checkConnection(ctx context.Context) *kubebindv1alpha1.ConnectionStatus {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.lastConnection != nil && time.Since(c.lastConnection.LastCheckTime.Time) < c.heartbeatInterval/2 {
+		return c.lastConnection
+	}
+
+	c.lastConnection = connection.Check(ctx, c.providerBindClient, c.providerNamespace)
+	if c.lastConnection.AuthStatus != kubebindv1alpha1.ConnectionAuthorized {
+		klog.FromContext(ctx).Info("connection check to service provider failed", "status", c.lastConnection.AuthStatus, "message", c.lastConnection.Message)
+	}
+
+	return c.lastConnection
+}
+
 func (c *controller) updateServiceBindings(ctx context.Context, update func(*kubebindv1alpha1.APIServiceBinding)) {
 	logger := klog.FromContext(ctx)
 
@@ -433,7 +489,7 @@ func (c *controller) updateServiceBindings(ctx context.Context, update func(*kub
 		orig := binding
 		binding = binding.DeepCopy()
 		update(binding)
-		if !reflect.DeepEqual(binding.Status.Conditions, orig.Status.Conditions) {
+		if !reflect.DeepEqual(binding.Status, orig.Status) {
 			logger.V(2).Info("updating service binding", "binding", binding.Name)
 			if _, err := c.consumerBindClient.KubeBindV1alpha1().APIServiceBindings().UpdateStatus(ctx, binding, metav1.UpdateOptions{}); err != nil {
 				logger.Error(err, "failed to update service binding", "binding", binding.Name)
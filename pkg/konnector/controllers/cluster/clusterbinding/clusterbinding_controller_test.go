@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterbinding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindfake "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned/fake"
+)
+
+func TestCheckConnection(t *testing.T) {
+	var err error
+	var checks int
+	client := bindfake.NewSimpleClientset()
+	client.PrependReactor("get", "clusterbindings", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		checks++
+		return true, &kubebindv1alpha1.ClusterBinding{}, err
+	})
+
+	c := &controller{
+		providerBindClient: client,
+		reconciler: reconciler{
+			providerNamespace: "kube-bind-abc",
+			heartbeatInterval: time.Minute,
+		},
+	}
+
+	status := c.checkConnection(context.Background())
+	require.Equal(t, 1, checks)
+	require.Equal(t, kubebindv1alpha1.ConnectionAuthorized, status.AuthStatus)
+
+	// within half the heartbeat interval, the last result is reused
+	err = errors.NewUnauthorized("token expired")
+	require.Same(t, status, c.checkConnection(context.Background()))
+	require.Equal(t, 1, checks)
+
+	// afterwards, the connection is checked again
+	c.lastConnection.LastCheckTime = metav1.NewTime(time.Now().Add(-31 * time.Second))
+	status = c.checkConnection(context.Background())
+	require.Equal(t, 2, checks)
+	require.Equal(t, kubebindv1alpha1.ConnectionUnauthorized, status.AuthStatus)
+	require.Same(t, status, c.lastConnection)
+}
@@ -18,6 +18,9 @@ package clusterbinding
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"sort"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +34,7 @@ import (
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
 	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kube-bind/kube-bind/pkg/konnector/selfupgrade"
 	"github.com/kube-bind/kube-bind/pkg/version"
 )
 
@@ -39,7 +43,12 @@ type reconciler struct {
 	consumerSecretRefKey string
 	providerNamespace    string
 	heartbeatInterval    time.Duration
+	// identity is the konnector replica reported in the heartbeat.
+	identity string
 
+	reportVersionRequirements func(req *kubebindv1alpha1.KonnectorVersionRequirements)
+
+	listServiceBindings  func() ([]*kubebindv1alpha1.APIServiceBinding, error)
 	getProviderSecret    func() (*corev1.Secret, error)
 	getConsumerSecret    func() (*corev1.Secret, error)
 	updateConsumerSecret func(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error)
@@ -61,6 +70,12 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.Cl
 		errs = append(errs, err)
 	}
 
+	r.ensureVersionSkew(binding)
+
+	if err := r.ensureKonnectorStatus(ctx, binding); err != nil {
+		errs = append(errs, err)
+	}
+
 	conditions.SetSummary(binding)
 
 	return utilerrors.NewAggregate(errs)
@@ -75,6 +90,48 @@ func (r *reconciler) ensureHeartbeat(ctx context.Context, binding *kubebindv1alp
 	return nil
 }
 
+// ensureKonnectorStatus reports the replica and the health of the served
+// APIServiceBindings. It must run after ensureHeartbeat, as the sync times are
+// heartbeat times.
+func (r *reconciler) ensureKonnectorStatus(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding) error {
+	serviceBindings, err := r.listServiceBindings()
+	if err != nil {
+		return err
+	}
+	sort.Slice(serviceBindings, func(i, j int) bool { return serviceBindings[i].Name < serviceBindings[j].Name })
+
+	lastSyncTimes := map[string]*metav1.Time{}
+	if binding.Status.Konnector != nil {
+		for _, b := range binding.Status.Konnector.Bindings {
+			lastSyncTimes[b.Name] = b.LastSyncTime
+		}
+	}
+
+	status := &kubebindv1alpha1.KonnectorStatus{
+		Identity:       r.identity,
+		ServedBindings: int32(len(serviceBindings)),
+	}
+	for _, sb := range serviceBindings {
+		bs := kubebindv1alpha1.KonnectorBindingStatus{
+			Name:         sb.Name,
+			LastSyncTime: lastSyncTimes[sb.Name],
+		}
+		if ready := conditions.Get(sb, conditionsapi.ReadyCondition); ready == nil {
+			bs.Error = "not reconciled yet"
+		} else if ready.Status != corev1.ConditionTrue {
+			bs.Error = fmt.Sprintf("%s: %s", ready.Reason, ready.Message)
+		} else {
+			status.ReadyBindings++
+			lastHeartbeat := binding.Status.LastHeartbeatTime
+			bs.LastSyncTime = &lastHeartbeat
+		}
+		status.Bindings = append(status.Bindings, bs)
+	}
+	binding.Status.Konnector = status
+
+	return nil
+}
+
 func (r *reconciler) ensureConsumerSecret(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding) error {
 	logger := klog.FromContext(ctx)
 
@@ -108,19 +165,17 @@ func (r *reconciler) ensureConsumerSecret(ctx context.Context, binding *kubebind
 	}
 
 	consumerSecret, err := r.getConsumerSecret()
-	if err != nil || !errors.IsNotFound(err) {
+	if err != nil && !errors.IsNotFound(err) {
 		return err
-	}
-
-	if consumerSecret == nil {
+	} else if errors.IsNotFound(err) {
 		ns, name, err := cache.SplitMetaNamespaceKey(r.consumerSecretRefKey)
 		if err != nil {
 			return err
 		}
 		consumerSecret := corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      ns,
-				Namespace: name,
+				Namespace: ns,
+				Name:      name,
 			},
 			Data: providerSecret.Data,
 			Type: providerSecret.Type,
@@ -129,16 +184,17 @@ func (r *reconciler) ensureConsumerSecret(ctx context.Context, binding *kubebind
 		if _, err := r.createConsumerSecret(ctx, &consumerSecret); err != nil {
 			return err
 		}
-	} else {
+	} else if !reflect.DeepEqual(consumerSecret.Data, providerSecret.Data) || consumerSecret.Type != providerSecret.Type {
+		// The service provider rotated the kubeconfig. The konnector picks up
+		// the updated consumer secret and restarts the sync with it.
+		consumerSecret = consumerSecret.DeepCopy()
 		consumerSecret.Data = providerSecret.Data
 		consumerSecret.Type = providerSecret.Type
 
-		logger.V(2).Info("Updating consumer secret", "namespace", consumerSecret.Namespace, "name", consumerSecret.Name)
+		logger.Info("Updating consumer secret with rotated kubeconfig", "namespace", consumerSecret.Namespace, "name", consumerSecret.Name)
 		if _, err := r.updateConsumerSecret(ctx, consumerSecret); err != nil {
 			return err
 		}
-
-		// TODO: create events
 	}
 
 	conditions.MarkTrue(
@@ -150,8 +206,17 @@ func (r *reconciler) ensureConsumerSecret(ctx context.Context, binding *kubebind
 }
 
 func (r *reconciler) ensureKonnectorVersion(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding) error {
-	gitVersion := componentbaseversion.Get().GitVersion
-	ver, err := version.BinaryVersion(gitVersion)
+	info := componentbaseversion.Get()
+	binding.Status.KonnectorBuild = &kubebindv1alpha1.KonnectorBuildInfo{
+		GitVersion:   info.GitVersion,
+		GitCommit:    info.GitCommit,
+		GitTreeState: info.GitTreeState,
+		BuildDate:    info.BuildDate,
+		GoVersion:    info.GoVersion,
+		Platform:     info.Platform,
+	}
+
+	ver, err := version.BinaryVersion(info.GitVersion)
 	if err != nil {
 		binding.Status.KonnectorVersion = "unknown"
 
@@ -161,7 +226,7 @@ func (r *reconciler) ensureKonnectorVersion(ctx context.Context, binding *kubebi
 			"ParseError",
 			conditionsapi.ConditionSeverityWarning,
 			"Konnector binary version string %q cannot be parsed: %v",
-			componentbaseversion.Get().GitVersion,
+			info.GitVersion,
 			err,
 		)
 		return nil
@@ -176,3 +241,22 @@ func (r *reconciler) ensureKonnectorVersion(ctx context.Context, binding *kubebi
 
 	return nil
 }
+
+// ensureVersionSkew reports the requirements of the service provider for the
+// self-upgrade, and sets the VersionSkew condition while the konnector is older.
+func (r *reconciler) ensureVersionSkew(binding *kubebindv1alpha1.ClusterBinding) {
+	r.reportVersionRequirements(binding.Spec.KonnectorVersions)
+
+	reason, message := selfupgrade.Skew(binding.Status.KonnectorVersion, binding.Spec.KonnectorVersions)
+	if reason == "" {
+		conditions.Delete(binding, kubebindv1alpha1.ClusterBindingConditionVersionSkew)
+		return
+	}
+	conditions.Set(binding, &conditionsapi.Condition{
+		Type:     kubebindv1alpha1.ClusterBindingConditionVersionSkew,
+		Status:   corev1.ConditionTrue,
+		Severity: conditionsapi.ConditionSeverityNone,
+		Reason:   reason,
+		Message:  message,
+	})
+}
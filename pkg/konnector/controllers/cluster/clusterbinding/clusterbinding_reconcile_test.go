@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterbinding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+)
+
+func TestEnsureConsumerSecret(t *testing.T) {
+	provider := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-abc", Name: "kubeconfig"},
+		Data:       map[string][]byte{"kubeconfig": []byte("rotated")},
+	}
+
+	tests := []struct {
+		name        string
+		consumer    *corev1.Secret
+		wantCreated *corev1.Secret
+		wantUpdated *corev1.Secret
+	}{
+		{
+			name: "missing",
+			wantCreated: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig-xyz"},
+				Data:       map[string][]byte{"kubeconfig": []byte("rotated")},
+			},
+		},
+		{
+			name: "up to date",
+			consumer: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig-xyz"},
+				Data:       map[string][]byte{"kubeconfig": []byte("rotated")},
+			},
+		},
+		{
+			name: "rotated",
+			consumer: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig-xyz"},
+				Data:       map[string][]byte{"kubeconfig": []byte("old")},
+			},
+			wantUpdated: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig-xyz"},
+				Data:       map[string][]byte{"kubeconfig": []byte("rotated")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created, updated *corev1.Secret
+			r := &reconciler{
+				consumerSecretRefKey: "kube-bind/kubeconfig-xyz",
+				providerNamespace:    "cluster-abc",
+				getProviderSecret: func() (*corev1.Secret, error) {
+					return provider, nil
+				},
+				getConsumerSecret: func() (*corev1.Secret, error) {
+					if tt.consumer == nil {
+						return nil, errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "kubeconfig-xyz")
+					}
+					return tt.consumer, nil
+				},
+				createConsumerSecret: func(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+					created = secret
+					return secret, nil
+				},
+				updateConsumerSecret: func(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+					updated = secret
+					return secret, nil
+				},
+			}
+
+			binding := &kubebindv1alpha1.ClusterBinding{
+				Spec: kubebindv1alpha1.ClusterBindingSpec{
+					KubeconfigSecretRef: kubebindv1alpha1.LocalSecretKeyRef{Name: "kubeconfig", Key: "kubeconfig"},
+				},
+			}
+			require.NoError(t, r.ensureConsumerSecret(context.Background(), binding))
+			require.Equal(t, tt.wantCreated, created)
+			require.Equal(t, tt.wantUpdated, updated)
+			require.True(t, conditions.IsTrue(binding, kubebindv1alpha1.ClusterBindingConditionSecretValid))
+			if tt.wantUpdated != nil {
+				require.Equal(t, []byte("old"), tt.consumer.Data["kubeconfig"], "lister object must not be mutated")
+			}
+		})
+	}
+}
+
+func TestEnsureVersionSkew(t *testing.T) {
+	var reported *kubebindv1alpha1.KonnectorVersionRequirements
+	r := &reconciler{
+		reportVersionRequirements: func(req *kubebindv1alpha1.KonnectorVersionRequirements) { reported = req },
+	}
+	binding := &kubebindv1alpha1.ClusterBinding{
+		Spec:   kubebindv1alpha1.ClusterBindingSpec{KonnectorVersions: &kubebindv1alpha1.KonnectorVersionRequirements{Minimum: "v0.4.0"}},
+		Status: kubebindv1alpha1.ClusterBindingStatus{KonnectorVersion: "v0.3.0"},
+	}
+
+	r.ensureVersionSkew(binding)
+	require.Equal(t, binding.Spec.KonnectorVersions, reported)
+	require.True(t, conditions.IsTrue(binding, kubebindv1alpha1.ClusterBindingConditionVersionSkew))
+	require.Equal(t, "BelowMinimumVersion", conditions.GetReason(binding, kubebindv1alpha1.ClusterBindingConditionVersionSkew))
+
+	binding.Status.KonnectorVersion = "v0.4.0"
+	r.ensureVersionSkew(binding)
+	require.False(t, conditions.Has(binding, kubebindv1alpha1.ClusterBindingConditionVersionSkew))
+}
+
+func TestEnsureKonnectorStatus(t *testing.T) {
+	ready := &kubebindv1alpha1.APIServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "mangodbs"}}
+	conditions.MarkTrue(ready, conditionsapi.ReadyCondition)
+	failing := &kubebindv1alpha1.APIServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "foos"}}
+	conditions.MarkFalse(failing, conditionsapi.ReadyCondition, "CRDNotFound", conditionsapi.ConditionSeverityError, "CRD foos.example.com not found")
+	pending := &kubebindv1alpha1.APIServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "bars"}}
+
+	r := &reconciler{
+		identity: "konnector-abc",
+		listServiceBindings: func() ([]*kubebindv1alpha1.APIServiceBinding, error) {
+			return []*kubebindv1alpha1.APIServiceBinding{ready, failing, pending}, nil
+		},
+	}
+	lastSync := metav1.NewTime(time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC))
+	heartbeat := metav1.NewTime(lastSync.Add(time.Hour))
+	binding := &kubebindv1alpha1.ClusterBinding{
+		Status: kubebindv1alpha1.ClusterBindingStatus{
+			LastHeartbeatTime: heartbeat,
+			Konnector: &kubebindv1alpha1.KonnectorStatus{
+				Bindings: []kubebindv1alpha1.KonnectorBindingStatus{{Name: "foos", LastSyncTime: &lastSync}},
+			},
+		},
+	}
+
+	require.NoError(t, r.ensureKonnectorStatus(context.Background(), binding))
+	require.Equal(t, &kubebindv1alpha1.KonnectorStatus{
+		Identity:       "konnector-abc",
+		ServedBindings: 3,
+		ReadyBindings:  1,
+		Bindings: []kubebindv1alpha1.KonnectorBindingStatus{
+			{Name: "bars", Error: "not reconciled yet"},
+			{Name: "foos", LastSyncTime: &lastSync, Error: "CRDNotFound: CRD foos.example.com not found"},
+			{Name: "mangodbs", LastSyncTime: &heartbeat},
+		},
+	}, binding.Status.Konnector)
+}
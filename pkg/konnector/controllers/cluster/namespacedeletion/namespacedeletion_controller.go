@@ -21,23 +21,23 @@ import (
 	"fmt"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	kubernetesclient "k8s.io/client-go/kubernetes"
-	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
 	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
 )
 
 const (
@@ -48,9 +48,10 @@ const (
 func NewController(
 	config *rest.Config,
 	serviceNamespaceInformer bindinformers.APIServiceNamespaceInformer,
-	namespaceInformer dynamic.Informer[corelisters.NamespaceLister],
+	namespaceInformer dynamic.Informer[cache.GenericLister],
+	rateLimiter tuning.RateLimiter,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -77,7 +78,13 @@ func NewController(
 
 		namespaceInformer: namespaceInformer,
 
-		getNamespace: namespaceInformer.Lister().Get,
+		getNamespace: func(name string) (*metav1.PartialObjectMetadata, error) {
+			obj, err := namespaceInformer.Lister().Get(name)
+			if err != nil {
+				return nil, err
+			}
+			return obj.(*metav1.PartialObjectMetadata), nil
+		},
 
 		getServiceNamespace: func(ns, name string) (*kubebindv1alpha1.APIServiceNamespace, error) {
 			return serviceNamespaceInformer.Lister().APIServiceNamespaces(ns).Get(name)
@@ -110,12 +117,12 @@ type controller struct {
 	bindClient bindclient.Interface
 	kubeClient kubernetesclient.Interface
 
-	namespaceInformer dynamic.Informer[corelisters.NamespaceLister]
+	namespaceInformer dynamic.Informer[cache.GenericLister]
 
 	serviceNamespaceLister  bindlisters.APIServiceNamespaceLister
 	serviceNamespaceIndexer cache.Indexer
 
-	getNamespace           func(name string) (*corev1.Namespace, error)
+	getNamespace           func(name string) (*metav1.PartialObjectMetadata, error)
 	getServiceNamespace    func(ns, name string) (*kubebindv1alpha1.APIServiceNamespace, error)
 	deleteServiceNamespace func(ctx context.Context, ns, name string) error
 }
@@ -210,7 +217,8 @@ func (c *controller) process(ctx context.Context, key string) error {
 		return nil // we cannot do anything
 	}
 
-	if _, err := c.getNamespace(name); err != nil && !errors.IsNotFound(err) {
+	// with Object isolation, the APIServiceNamespace is named <namespace>.<object>
+	if _, err := c.getNamespace(kubebindhelpers.ConsumerNamespace(name)); err != nil && !errors.IsNotFound(err) {
 		return err
 	} else if errors.IsNotFound(err) {
 		if err := c.deleteServiceNamespace(ctx, snsNamespace, name); err != nil && !errors.IsNotFound(err) {
@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
+	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/committer"
+	"github.com/kube-bind/kube-bind/pkg/indexers"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+)
+
+const (
+	controllerName = "kube-bind-konnector-cluster-serviceaction"
+)
+
+// NewController returns a new controller executing the APIServiceActions of the
+// service provider on consumer objects.
+func NewController(
+	consumerSecretRefKey string,
+	consumerConfig, providerConfig *rest.Config,
+	serviceActionInformer bindinformers.APIServiceActionInformer,
+	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
+	rateLimiter tuning.RateLimiter,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
+
+	logger := klog.Background().WithValues("controller", controllerName)
+
+	consumerConfig = rest.CopyConfig(consumerConfig)
+	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
+
+	providerConfig = rest.CopyConfig(providerConfig)
+	providerConfig = rest.AddUserAgent(providerConfig, controllerName)
+
+	providerBindClient, err := bindclient.NewForConfig(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+	consumerDynamicClient, err := dynamicclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &controller{
+		queue: queue,
+
+		serviceActionLister:  serviceActionInformer.Lister(),
+		serviceActionIndexer: serviceActionInformer.Informer().GetIndexer(),
+
+		reconciler: reconciler{
+			getServiceBinding: func(exportName string) (*kubebindv1alpha1.APIServiceBinding, error) {
+				bindings, err := serviceBindingInformer.Informer().GetIndexer().ByIndex(indexers.ByServiceBindingExport, indexers.ByServiceBindingExportKey(consumerSecretRefKey, exportName))
+				if err != nil {
+					return nil, err
+				}
+				if len(bindings) == 0 {
+					return nil, errors.NewNotFound(kubebindv1alpha1.Resource("apiservicebindings"), exportName)
+				}
+				return bindings[0].(*kubebindv1alpha1.APIServiceBinding), nil
+			},
+			patchTarget: func(ctx context.Context, gvr runtimeschema.GroupVersionResource, ns, name string, patch []byte) error {
+				_, err := consumerDynamicClient.Resource(gvr).Namespace(ns).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+				return err
+			},
+		},
+
+		commit: committer.NewCommitter[*kubebindv1alpha1.APIServiceAction, *kubebindv1alpha1.APIServiceActionSpec, *kubebindv1alpha1.APIServiceActionStatus](
+			func(ns string) committer.Patcher[*kubebindv1alpha1.APIServiceAction] {
+				return providerBindClient.KubeBindV1alpha1().APIServiceActions(ns)
+			},
+		),
+	}
+
+	serviceActionInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueServiceAction(logger, obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueServiceAction(logger, newObj)
+		},
+	})
+
+	return c, nil
+}
+
+type Resource = committer.Resource[*kubebindv1alpha1.APIServiceActionSpec, *kubebindv1alpha1.APIServiceActionStatus]
+type CommitFunc = func(context.Context, *Resource, *Resource) error
+
+// controller executes APIServiceActions once and reports the outcome in their status.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	serviceActionLister  bindlisters.APIServiceActionLister
+	serviceActionIndexer cache.Indexer
+
+	reconciler
+
+	commit CommitFunc
+}
+
+func (c *controller) enqueueServiceAction(logger klog.Logger, obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger.V(2).Info("queueing APIServiceAction", "key", key)
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	defer runtime.HandleCrash()
+
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := klog.FromContext(ctx).WithValues("key", key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(2).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil // we cannot do anything
+	}
+
+	obj, err := c.serviceActionLister.APIServiceActions(ns).Get(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		return nil // nothing to do
+	}
+
+	old := obj
+	obj = obj.DeepCopy()
+
+	var errs []error
+	if err := c.reconcile(ctx, obj); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Regardless of whether reconcile returned an error or not, always try to patch status if needed. Return the
+	// reconciliation error at the end.
+
+	// If the object being reconciled changed as a result, update it.
+	oldResource := &Resource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
+	newResource := &Resource{ObjectMeta: obj.ObjectMeta, Spec: &obj.Spec, Status: &obj.Status}
+	if err := c.commit(ctx, oldResource, newResource); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// restartableResources are the consumer resources that can be restarted.
+var restartableResources = map[runtimeschema.GroupResource]string{
+	{Group: "apps", Resource: "deployments"}:  "v1",
+	{Group: "apps", Resource: "statefulsets"}: "v1",
+	{Group: "apps", Resource: "daemonsets"}:   "v1",
+}
+
+type reconciler struct {
+	getServiceBinding func(exportName string) (*kubebindv1alpha1.APIServiceBinding, error)
+	patchTarget       func(ctx context.Context, gvr runtimeschema.GroupVersionResource, ns, name string, patch []byte) error
+}
+
+// reconcile executes a pending action once, if the APIServiceBinding of the
+// export allows it. Actions with a phase are never executed again.
+func (r *reconciler) reconcile(ctx context.Context, action *kubebindv1alpha1.APIServiceAction) error {
+	if action.Status.Phase != "" {
+		return nil
+	}
+
+	logger := klog.FromContext(ctx)
+	target := action.Spec.Target
+
+	binding, err := r.getServiceBinding(action.Spec.Export)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		complete(action, kubebindv1alpha1.ActionPhaseDenied, "No APIServiceBinding for APIServiceExport %s", action.Spec.Export)
+		return nil
+	}
+	if !allowed(binding.Spec.AllowedActions, action.Spec.Action, target.Namespace) {
+		logger.Info("Denied APIServiceAction", "action", action.Spec.Action, "binding", binding.Name)
+		complete(action, kubebindv1alpha1.ActionPhaseDenied, "Action %s on namespace %s is not allowed by APIServiceBinding %s", action.Spec.Action, target.Namespace, binding.Name)
+		return nil
+	}
+
+	switch action.Spec.Action {
+	case kubebindv1alpha1.ActionTypeRestart:
+		gr := runtimeschema.GroupResource{Group: target.Group, Resource: target.Resource}
+		version, ok := restartableResources[gr]
+		if !ok {
+			complete(action, kubebindv1alpha1.ActionPhaseFailed, "Resource %s cannot be restarted", gr)
+			return nil
+		}
+		patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":%q}}}}}`, time.Now().Format(time.RFC3339))
+		if err := r.patchTarget(ctx, gr.WithVersion(version), target.Namespace, target.Name, []byte(patch)); err != nil && !errors.IsNotFound(err) {
+			return err
+		} else if errors.IsNotFound(err) {
+			complete(action, kubebindv1alpha1.ActionPhaseFailed, "%s %s/%s not found", gr, target.Namespace, target.Name)
+			return nil
+		}
+	default:
+		complete(action, kubebindv1alpha1.ActionPhaseFailed, "Unknown action %s", action.Spec.Action)
+		return nil
+	}
+
+	logger.Info("Executed APIServiceAction", "action", action.Spec.Action, "resource", target.Resource, "namespace", target.Namespace, "name", target.Name)
+	complete(action, kubebindv1alpha1.ActionPhaseSucceeded, "")
+	return nil
+}
+
+func allowed(policies []kubebindv1alpha1.ActionPolicy, action kubebindv1alpha1.ActionType, ns string) bool {
+	for _, p := range policies {
+		if p.Action != action {
+			continue
+		}
+		if len(p.Namespaces) == 0 {
+			return true
+		}
+		for _, n := range p.Namespaces {
+			if n == ns {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func complete(action *kubebindv1alpha1.APIServiceAction, phase kubebindv1alpha1.ActionPhase, format string, args ...interface{}) {
+	now := metav1.Now()
+	action.Status.Phase = phase
+	action.Status.Message = fmt.Sprintf(format, args...)
+	action.Status.CompletionTime = &now
+}
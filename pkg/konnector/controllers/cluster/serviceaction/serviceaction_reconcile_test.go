@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	deployment := kubebindv1alpha1.ActionTarget{Group: "apps", Resource: "deployments", Namespace: "default", Name: "db"}
+
+	tests := []struct {
+		name        string
+		binding     *kubebindv1alpha1.APIServiceBinding
+		target      kubebindv1alpha1.ActionTarget
+		phase       kubebindv1alpha1.ActionPhase
+		notFound    bool
+		wantPhase   kubebindv1alpha1.ActionPhase
+		wantPatched bool
+	}{
+		{
+			name:      "no binding",
+			target:    deployment,
+			wantPhase: kubebindv1alpha1.ActionPhaseDenied,
+		},
+		{
+			name:      "not allowed",
+			binding:   &kubebindv1alpha1.APIServiceBinding{},
+			target:    deployment,
+			wantPhase: kubebindv1alpha1.ActionPhaseDenied,
+		},
+		{
+			name:      "other namespace",
+			binding:   newBinding(kubebindv1alpha1.ActionPolicy{Action: kubebindv1alpha1.ActionTypeRestart, Namespaces: []string{"other"}}),
+			target:    deployment,
+			wantPhase: kubebindv1alpha1.ActionPhaseDenied,
+		},
+		{
+			name:        "restarted",
+			binding:     newBinding(kubebindv1alpha1.ActionPolicy{Action: kubebindv1alpha1.ActionTypeRestart, Namespaces: []string{"default"}}),
+			target:      deployment,
+			wantPhase:   kubebindv1alpha1.ActionPhaseSucceeded,
+			wantPatched: true,
+		},
+		{
+			name:      "not restartable",
+			binding:   newBinding(kubebindv1alpha1.ActionPolicy{Action: kubebindv1alpha1.ActionTypeRestart}),
+			target:    kubebindv1alpha1.ActionTarget{Resource: "pods", Namespace: "default", Name: "db"},
+			wantPhase: kubebindv1alpha1.ActionPhaseFailed,
+		},
+		{
+			name:        "target not found",
+			binding:     newBinding(kubebindv1alpha1.ActionPolicy{Action: kubebindv1alpha1.ActionTypeRestart}),
+			target:      deployment,
+			notFound:    true,
+			wantPhase:   kubebindv1alpha1.ActionPhaseFailed,
+			wantPatched: true,
+		},
+		{
+			name:      "executed once",
+			binding:   newBinding(kubebindv1alpha1.ActionPolicy{Action: kubebindv1alpha1.ActionTypeRestart}),
+			target:    deployment,
+			phase:     kubebindv1alpha1.ActionPhaseSucceeded,
+			wantPhase: kubebindv1alpha1.ActionPhaseSucceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patched bool
+			r := &reconciler{
+				getServiceBinding: func(name string) (*kubebindv1alpha1.APIServiceBinding, error) {
+					if tt.binding == nil {
+						return nil, errors.NewNotFound(kubebindv1alpha1.Resource("apiservicebindings"), name)
+					}
+					return tt.binding, nil
+				},
+				patchTarget: func(ctx context.Context, gvr runtimeschema.GroupVersionResource, ns, name string, patch []byte) error {
+					patched = true
+					require.Equal(t, runtimeschema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, gvr)
+					if tt.notFound {
+						return errors.NewNotFound(gvr.GroupResource(), name)
+					}
+					return nil
+				},
+			}
+			action := &kubebindv1alpha1.APIServiceAction{
+				Spec: kubebindv1alpha1.APIServiceActionSpec{
+					Export: "mangodbs.mangodb.com",
+					Action: kubebindv1alpha1.ActionTypeRestart,
+					Target: tt.target,
+				},
+				Status: kubebindv1alpha1.APIServiceActionStatus{Phase: tt.phase},
+			}
+			require.NoError(t, r.reconcile(context.Background(), action))
+			require.Equal(t, tt.wantPhase, action.Status.Phase, action.Status.Message)
+			require.Equal(t, tt.wantPatched, patched)
+		})
+	}
+}
+
+func newBinding(policies ...kubebindv1alpha1.ActionPolicy) *kubebindv1alpha1.APIServiceBinding {
+	return &kubebindv1alpha1.APIServiceBinding{
+		Spec: kubebindv1alpha1.APIServiceBindingSpec{AllowedActions: policies},
+	}
+}
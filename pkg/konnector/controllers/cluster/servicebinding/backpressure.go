@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// ensureProviderThrottled sets the ProviderThrottled condition while the upsync is
+// slowed down. It is true then, but does not make the binding unready.
+func (r *reconciler) ensureProviderThrottled(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) {
+	if r.backpressure == nil {
+		return
+	}
+
+	throttled, message := r.backpressure.State(kubebindhelpers.ExportName(binding), time.Now())
+	if !throttled {
+		conditions.Delete(binding, kubebindv1alpha1.APIServiceBindingConditionProviderThrottled)
+		return
+	}
+	conditions.Set(binding, &conditionsapi.Condition{
+		Type:     kubebindv1alpha1.APIServiceBindingConditionProviderThrottled,
+		Status:   corev1.ConditionTrue,
+		Severity: conditionsapi.ConditionSeverityNone,
+		Reason:   "ProviderOverloaded",
+		Message:  message,
+	})
+}
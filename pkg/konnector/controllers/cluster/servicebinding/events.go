@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// recordEvent creates an Event about the cluster-scoped APIServiceBinding in the
+// default namespace, where kubectl describe finds it. Failures are only logged.
+func recordEvent(ctx context.Context, events corev1client.EventsGetter, binding *kubebindv1alpha1.APIServiceBinding, eventType, reason, message string) {
+	now := metav1.Now()
+	if _, err := events.Events(metav1.NamespaceDefault).Create(ctx, &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: binding.Name + ".",
+			Namespace:    metav1.NamespaceDefault,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: kubebindv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "APIServiceBinding",
+			Name:       binding.Name,
+			UID:        binding.UID,
+		},
+		Reason:              reason,
+		Message:             message,
+		Type:                eventType,
+		Source:              corev1.EventSource{Component: "konnector"},
+		ReportingController: "konnector",
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}, metav1.CreateOptions{}); err != nil {
+		klog.FromContext(ctx).Error(err, "failed to record APIServiceBinding event", "reason", reason)
+	}
+}
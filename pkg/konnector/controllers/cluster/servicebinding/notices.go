@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// ensureNotices publishes the current incident and maintenance notices of the
+// APIServiceExport on the binding, sets the ProviderNotice condition while there
+// are any, and records Events when notices appear and end.
+func (r *reconciler) ensureNotices(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
+	export, err := r.getServiceExport(kubebindhelpers.ExportName(binding))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	var current []kubebindv1alpha1.ProviderNotice
+	if export != nil {
+		current = kubebindhelpers.CurrentNotices(export.Spec.Notices, time.Now())
+	}
+
+	previous := map[string]kubebindv1alpha1.ProviderNotice{}
+	for _, n := range binding.Status.Notices {
+		previous[n.Name] = n
+	}
+	for _, n := range current {
+		if _, found := previous[n.Name]; !found {
+			eventType := corev1.EventTypeNormal
+			if n.Type == kubebindv1alpha1.ProviderNoticeTypeIncident {
+				eventType = corev1.EventTypeWarning
+			}
+			r.recordEvent(ctx, binding, eventType, "Provider"+string(n.Type), kubebindhelpers.NoticeMessage(n))
+		}
+		delete(previous, n.Name)
+	}
+	for _, n := range binding.Status.Notices {
+		if _, ended := previous[n.Name]; ended {
+			r.recordEvent(ctx, binding, corev1.EventTypeNormal, "Provider"+string(n.Type)+"Ended", kubebindhelpers.NoticeMessage(n))
+		}
+	}
+	binding.Status.Notices = current
+
+	if len(current) == 0 {
+		conditions.Delete(binding, kubebindv1alpha1.APIServiceBindingConditionProviderNotice)
+		return nil
+	}
+	reason := string(kubebindv1alpha1.ProviderNoticeTypeMaintenance)
+	messages := make([]string, 0, len(current))
+	for _, n := range current {
+		if n.Type == kubebindv1alpha1.ProviderNoticeTypeIncident {
+			reason = string(kubebindv1alpha1.ProviderNoticeTypeIncident)
+		}
+		messages = append(messages, kubebindhelpers.NoticeMessage(n))
+	}
+	conditions.Set(binding, &conditionsapi.Condition{
+		Type:     kubebindv1alpha1.APIServiceBindingConditionProviderNotice,
+		Status:   corev1.ConditionTrue,
+		Severity: conditionsapi.ConditionSeverityNone,
+		Reason:   reason,
+		Message:  strings.Join(messages, "; "),
+	})
+
+	return nil
+}
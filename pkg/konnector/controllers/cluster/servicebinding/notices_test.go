@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+)
+
+func TestEnsureNotices(t *testing.T) {
+	past := metav1.NewTime(time.Now().Add(-time.Hour))
+	export := &kubebindv1alpha1.APIServiceExport{ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com"}}
+	binding := &kubebindv1alpha1.APIServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com"}}
+
+	var events []string
+	r := &reconciler{
+		getServiceExport: func(name string) (*kubebindv1alpha1.APIServiceExport, error) {
+			return export, nil
+		},
+		recordEvent: func(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding, eventType, reason, message string) {
+			events = append(events, eventType+" "+reason)
+		},
+	}
+	ctx := context.Background()
+
+	require.NoError(t, r.ensureNotices(ctx, binding))
+	require.Empty(t, binding.Status.Notices)
+	require.Nil(t, conditions.Get(binding, kubebindv1alpha1.APIServiceBindingConditionProviderNotice))
+
+	export.Spec.Notices = []kubebindv1alpha1.ProviderNotice{
+		{Name: "db-upgrade", Type: kubebindv1alpha1.ProviderNoticeTypeMaintenance, Message: "read-only"},
+		{Name: "outage", Type: kubebindv1alpha1.ProviderNoticeTypeIncident, Message: "down", StartTime: &past},
+		{Name: "resolved", Type: kubebindv1alpha1.ProviderNoticeTypeIncident, Message: "was down", EndTime: &past},
+	}
+	require.NoError(t, r.ensureNotices(ctx, binding))
+	require.Len(t, binding.Status.Notices, 2)
+	cond := conditions.Get(binding, kubebindv1alpha1.APIServiceBindingConditionProviderNotice)
+	require.NotNil(t, cond)
+	require.Equal(t, corev1.ConditionTrue, cond.Status)
+	require.Equal(t, "Incident", cond.Reason)
+	require.Contains(t, cond.Message, "Maintenance db-upgrade: read-only")
+	require.Equal(t, []string{"Normal ProviderMaintenance", "Warning ProviderIncident"}, events)
+
+	// unchanged notices are not recorded again
+	events = nil
+	require.NoError(t, r.ensureNotices(ctx, binding))
+	require.Empty(t, events)
+
+	export.Spec.Notices = export.Spec.Notices[:1]
+	require.NoError(t, r.ensureNotices(ctx, binding))
+	require.Len(t, binding.Status.Notices, 1)
+	require.Equal(t, "Maintenance", conditions.Get(binding, kubebindv1alpha1.APIServiceBindingConditionProviderNotice).Reason)
+	require.Equal(t, []string{"Normal ProviderIncidentEnded"}, events)
+
+	export.Spec.Notices = nil
+	require.NoError(t, r.ensureNotices(ctx, binding))
+	require.Empty(t, binding.Status.Notices)
+	require.Nil(t, conditions.Get(binding, kubebindv1alpha1.APIServiceBindingConditionProviderNotice))
+}
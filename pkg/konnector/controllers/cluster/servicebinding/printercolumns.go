@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// syncedPrinterColumns show the sync state of bound objects in kubectl get. Provider
+// shows the APIServiceBinding, i.e. the service provider, that the object is synced with.
+var syncedPrinterColumns = []apiextensionsv1.CustomResourceColumnDefinition{
+	{
+		Name:        "Synced",
+		Type:        "string",
+		Description: "Whether the service provider acknowledged the spec and the status is up to date.",
+		JSONPath:    fmt.Sprintf(".status.conditions[?(@.type==%q)].reason", kubebindv1alpha1.SyncedConditionType),
+	},
+	{
+		Name:        "Provider",
+		Type:        "string",
+		Description: "The APIServiceBinding of the service provider the object is synced with.",
+		JSONPath:    ".metadata.annotations.kube-bind\\.io/binding",
+	},
+}
+
+// ageColumn is the default column of kubectl get, which is lost when defining other columns.
+var ageColumn = apiextensionsv1.CustomResourceColumnDefinition{
+	Name:     "Age",
+	Type:     "date",
+	JSONPath: ".metadata.creationTimestamp",
+}
+
+// addSyncedPrinterColumns adds the synced printer columns to all versions of the CRD,
+// unless the service provider defines columns of the same name. They go before Age,
+// which kubectl shows last by convention.
+func addSyncedPrinterColumns(crd *apiextensionsv1.CustomResourceDefinition) {
+	for i := range crd.Spec.Versions {
+		v := &crd.Spec.Versions[i]
+		if len(v.AdditionalPrinterColumns) == 0 {
+			v.AdditionalPrinterColumns = []apiextensionsv1.CustomResourceColumnDefinition{ageColumn}
+		}
+
+		var columns []apiextensionsv1.CustomResourceColumnDefinition
+		for _, c := range syncedPrinterColumns {
+			if !hasPrinterColumn(v.AdditionalPrinterColumns, c.Name) {
+				columns = append(columns, c)
+			}
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		ret := make([]apiextensionsv1.CustomResourceColumnDefinition, 0, len(v.AdditionalPrinterColumns)+len(columns))
+		for _, c := range v.AdditionalPrinterColumns {
+			if c.Name == "Age" && columns != nil {
+				ret = append(ret, columns...)
+				columns = nil
+			}
+			ret = append(ret, c)
+		}
+		v.AdditionalPrinterColumns = append(ret, columns...)
+	}
+}
+
+func hasPrinterColumn(columns []apiextensionsv1.CustomResourceColumnDefinition, name string) bool {
+	for _, c := range columns {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
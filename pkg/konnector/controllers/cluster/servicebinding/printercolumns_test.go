@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestAddSyncedPrinterColumns(t *testing.T) {
+	column := func(name string) apiextensionsv1.CustomResourceColumnDefinition {
+		return apiextensionsv1.CustomResourceColumnDefinition{Name: name}
+	}
+	tests := []struct {
+		name    string
+		columns []apiextensionsv1.CustomResourceColumnDefinition
+		want    []string
+	}{
+		{"no columns", nil, []string{"Synced", "Provider", "Age"}},
+		{"before age", []apiextensionsv1.CustomResourceColumnDefinition{column("Ready"), column("Age")}, []string{"Ready", "Synced", "Provider", "Age"}},
+		{"without age", []apiextensionsv1.CustomResourceColumnDefinition{column("Ready")}, []string{"Ready", "Synced", "Provider"}},
+		{"provider column", []apiextensionsv1.CustomResourceColumnDefinition{column("Provider"), column("Age")}, []string{"Provider", "Synced", "Age"}},
+		{"all defined", []apiextensionsv1.CustomResourceColumnDefinition{column("Synced"), column("Provider")}, []string{"Synced", "Provider"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crd := &apiextensionsv1.CustomResourceDefinition{
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", AdditionalPrinterColumns: tt.columns}},
+				},
+			}
+			addSyncedPrinterColumns(crd)
+
+			var got []string
+			for _, c := range crd.Spec.Versions[0].AdditionalPrinterColumns {
+				got = append(got, c.Name)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -29,6 +29,7 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -40,7 +41,11 @@ import (
 	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/committer"
 	"github.com/kube-bind/kube-bind/pkg/indexers"
+	"github.com/kube-bind/kube-bind/pkg/konnector/backpressure"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/drain"
+	"github.com/kube-bind/kube-bind/pkg/konnector/slo"
 )
 
 const (
@@ -50,12 +55,18 @@ const (
 // NewController returns a new controller for ServiceBindings.
 func NewController(
 	consumerSecretRefKey, providerNamespace string,
-	consumerConfig, providerConfig *rest.Config,
+	consumerConfig, targetConfig, providerConfig *rest.Config,
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	serviceExportInformer bindinformers.APIServiceExportInformer,
 	crdInformer dynamic.Informer[apiextensionslisters.CustomResourceDefinitionLister],
+	crdAllowlist []string,
+	allowedRegions []string,
+	syncedPrinterColumns bool,
+	sloTracker *slo.Tracker,
+	backpressureTracker *backpressure.Tracker,
+	rateLimiter tuning.RateLimiter,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -65,11 +76,15 @@ func NewController(
 	consumerConfig = rest.CopyConfig(consumerConfig)
 	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
 
+	targetConfig = rest.CopyConfig(targetConfig)
+	targetConfig = rest.AddUserAgent(targetConfig, controllerName)
+
 	consumerBindClient, err := bindclient.NewForConfig(consumerConfig)
 	if err != nil {
 		return nil, err
 	}
-	apiextensionsClient, err := apiextensionsclient.NewForConfig(consumerConfig)
+	// CRDs are created in the target cluster, which is a virtual cluster or the consumer cluster
+	apiextensionsClient, err := apiextensionsclient.NewForConfig(targetConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -77,6 +92,10 @@ func NewController(
 	if err != nil {
 		return nil, err
 	}
+	consumerKubeClient, err := kubeclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	c := &controller{
 		queue: queue,
@@ -91,6 +110,11 @@ func NewController(
 		reconciler: reconciler{
 			consumerSecretRefKey: consumerSecretRefKey,
 			providerNamespace:    providerNamespace,
+			crdAllowlist:         crdAllowlist,
+			allowedRegions:       allowedRegions,
+			syncedPrinterColumns: syncedPrinterColumns,
+			sloTracker:           sloTracker,
+			backpressure:         backpressureTracker,
 
 			getServiceExport: func(name string) (*kubebindv1alpha1.APIServiceExport, error) {
 				return serviceExportInformer.Lister().APIServiceExports(providerNamespace).Get(name)
@@ -113,6 +137,9 @@ func NewController(
 			createCRD: func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
 				return apiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().Create(ctx, crd, metav1.CreateOptions{})
 			},
+			recordEvent: func(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding, eventType, reason, message string) {
+				recordEvent(ctx, consumerKubeClient.CoreV1(), binding, eventType, reason, message)
+			},
 		},
 
 		commit: committer.NewCommitter[*kubebindv1alpha1.APIServiceBinding, *kubebindv1alpha1.APIServiceBindingSpec, *kubebindv1alpha1.APIServiceBindingStatus](
@@ -121,6 +148,9 @@ func NewController(
 			},
 		),
 	}
+	c.reconciler.requeueAfter = func(binding *kubebindv1alpha1.APIServiceBinding, after time.Duration) {
+		c.queue.AddAfter(binding.Name, after)
+	}
 
 	indexers.AddIfNotPresentOrDie(serviceExportInformer.Informer().GetIndexer(), cache.Indexers{
 		indexers.ServiceExportByCustomResourceDefinition: indexers.IndexServiceExportByCustomResourceDefinition,
@@ -138,6 +168,13 @@ func NewController(
 		},
 	})
 
+	sloTracker.OnRecord(func(name string) {
+		c.enqueueExportBindings(logger.V(2), name, "CanaryProbe")
+	})
+	backpressureTracker.OnChange(func(name string) {
+		c.enqueueExportBindings(logger.V(2), name, "Backpressure")
+	})
+
 	return c, nil
 }
 
@@ -204,12 +241,38 @@ func (c *controller) enqueueCRD(logger klog.Logger, obj interface{}) {
 	}
 	for _, obj := range exports {
 		export := obj.(*kubebindv1alpha1.APIServiceExport)
-		key := export.Name
+		c.enqueueExportBindings(logger, export.Name, "CustomResourceDefinition")
+	}
+
+	// CRDs of bindings with group alias are named differently than their export
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	bindings, err := indexers.IndexCRDByServiceBinding(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, key := range bindings {
 		logger.V(2).Info("queueing APIServiceBinding", "key", key, "reason", "CustomResourceDefinition", "CustomResourceDefinitionKey", name)
 		c.queue.Add(key)
 	}
 }
 
+// enqueueExportBindings queues the bindings of the given APIServiceExport.
+func (c *controller) enqueueExportBindings(logger klog.Logger, exportName, reason string) {
+	bindings, err := c.serviceBindingInformer.Informer().GetIndexer().ByIndex(indexers.ByServiceBindingExport, indexers.ByServiceBindingExportKey(c.consumerSecretRefKey, exportName))
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, obj := range bindings {
+		binding := obj.(*kubebindv1alpha1.APIServiceBinding)
+		logger.V(2).Info("queueing APIServiceBinding", "key", binding.Name, "reason", reason, "APIServiceExport", exportName)
+		c.queue.Add(binding.Name)
+	}
+}
+
 // Start starts the controller, which stops when ctx.Done() is closed.
 func (c *controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
@@ -274,7 +337,13 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	started, err := drain.Process(ctx, func(ctx context.Context) error {
+		return c.process(ctx, key)
+	})
+	if !started {
+		return false // shutting down, don't start new work
+	}
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		c.queue.AddRateLimited(key)
 		return true
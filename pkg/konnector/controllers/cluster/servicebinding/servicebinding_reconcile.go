@@ -18,22 +18,40 @@ package servicebinding
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
 	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kube-bind/kube-bind/pkg/features"
+	"github.com/kube-bind/kube-bind/pkg/konnector/backpressure"
+	"github.com/kube-bind/kube-bind/pkg/konnector/slo"
 )
 
 type reconciler struct {
 	consumerSecretRefKey, providerNamespace string
 
+	// crdAllowlist restricts the CRDs that may be created and updated, by name
+	// or as *.<group>. Empty allows all.
+	crdAllowlist []string
+	// allowedRegions restricts the regions of APIServiceExports. Empty allows all.
+	allowedRegions []string
+	// syncedPrinterColumns adds printer columns for the sync state to the CRDs.
+	syncedPrinterColumns bool
+	// sloTracker has the canary probes the SLO of bindings is computed from.
+	sloTracker *slo.Tracker
+	// backpressure tells whether the upsync of bindings is throttled by the service provider.
+	backpressure *backpressure.Tracker
+
 	getServiceExport  func(ns string) (*kubebindv1alpha1.APIServiceExport, error)
 	getServiceBinding func(name string) (*kubebindv1alpha1.APIServiceBinding, error)
 	getClusterBinding func(ctx context.Context) (*kubebindv1alpha1.ClusterBinding, error)
@@ -43,6 +61,10 @@ type reconciler struct {
 	getCRD    func(name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	updateCRD func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
 	createCRD func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
+
+	recordEvent func(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding, eventType, reason, message string)
+
+	requeueAfter func(binding *kubebindv1alpha1.APIServiceBinding, after time.Duration)
 }
 
 func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
@@ -59,6 +81,22 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 	if err := r.ensurePrettyName(ctx, binding); err != nil {
 		errs = append(errs, err)
 	}
+	r.ensureTrialConditions(binding)
+
+	if err := r.ensureCapabilities(ctx, binding); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := r.ensureLimits(binding); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := r.ensureNotices(ctx, binding); err != nil {
+		errs = append(errs, err)
+	}
+
+	r.ensureSLO(ctx, binding)
+	r.ensureProviderThrottled(ctx, binding)
 
 	conditions.SetSummary(binding)
 
@@ -66,7 +104,7 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 }
 
 func (r *reconciler) ensureValidServiceExport(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
-	if _, err := r.getServiceExport(binding.Name); err != nil && !errors.IsNotFound(err) {
+	if _, err := r.getServiceExport(kubebindhelpers.ExportName(binding)); err != nil && !errors.IsNotFound(err) {
 		return err
 	} else if errors.IsNotFound(err) {
 		conditions.MarkFalse(
@@ -75,7 +113,7 @@ func (r *reconciler) ensureValidServiceExport(ctx context.Context, binding *kube
 			"APIServiceExportNotFound",
 			conditionsapi.ConditionSeverityError,
 			"APIServiceExport %s not found on the service provider cluster. Rerun kubectl bind for repair.",
-			binding.Name,
+			kubebindhelpers.ExportName(binding),
 		)
 		return nil
 	}
@@ -91,7 +129,7 @@ func (r *reconciler) ensureValidServiceExport(ctx context.Context, binding *kube
 func (r *reconciler) ensureCRDs(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
 	var errs []error
 
-	export, err := r.getServiceExport(binding.Name)
+	export, err := r.getServiceExport(kubebindhelpers.ExportName(binding))
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	} else if errors.IsNotFound(err) {
@@ -101,11 +139,40 @@ func (r *reconciler) ensureCRDs(ctx context.Context, binding *kubebindv1alpha1.A
 			"APIServiceExportNotFound",
 			conditionsapi.ConditionSeverityError,
 			"APIServiceExport %s not found on the service provider cluster.",
-			binding.Name,
+			kubebindhelpers.ExportName(binding),
 		)
 		return nil // nothing we can do here
 	}
 
+	if region := export.Labels[kubebindv1alpha1.RegionLabelKey]; !kubebindhelpers.RegionAllowed(region, r.allowedRegions) {
+		if region == "" {
+			region = "unknown"
+		}
+		conditions.MarkFalse(
+			binding,
+			kubebindv1alpha1.APIServiceBindingConditionConnected,
+			"RegionNotAllowed",
+			conditionsapi.ConditionSeverityError,
+			"APIServiceExport %s is in region %s, but the konnector only allows %s.",
+			kubebindhelpers.ExportName(binding), region, strings.Join(r.allowedRegions, ", "),
+		)
+		return nil
+	}
+
+	binding.Status.LatestSchemaRevision = export.Spec.SchemaRevision
+	export, found := kubebindhelpers.ExportAtSchemaRevision(export, binding.Spec.SchemaRevision)
+	if !found {
+		conditions.MarkFalse(
+			binding,
+			kubebindv1alpha1.APIServiceBindingConditionConnected,
+			"SchemaRevisionNotFound",
+			conditionsapi.ConditionSeverityError,
+			"APIServiceExport %s does not publish schema revision %s anymore. Run kubectl bind upgrade-schema.",
+			kubebindhelpers.ExportName(binding), binding.Spec.SchemaRevision,
+		)
+		return nil // keep the CRD as it is
+	}
+
 	crd, err := kubebindhelpers.ServiceExportToCRD(export)
 	if err != nil {
 		conditions.MarkFalse(
@@ -114,10 +181,39 @@ func (r *reconciler) ensureCRDs(ctx context.Context, binding *kubebindv1alpha1.A
 			"APIServiceExportInvalid",
 			conditionsapi.ConditionSeverityError,
 			"APIServiceExport %s on the service provider cluster is invalid: %s",
-			binding.Name, err,
+			kubebindhelpers.ExportName(binding), err,
 		)
 		return nil // nothing we can do here
 	}
+	crd.Name = kubebindhelpers.BoundCRDName(binding)
+	crd.Spec.Group = kubebindhelpers.BoundGroup(binding, crd.Spec.Group)
+
+	if !kubebindhelpers.HasCapability(export, kubebindv1alpha1.APIServiceExportCapabilityScaleSubresource) {
+		// don't offer scaling that the service provider would ignore
+		for i := range crd.Spec.Versions {
+			if subresources := crd.Spec.Versions[i].Subresources; subresources != nil && subresources.Scale != nil {
+				subresources = subresources.DeepCopy()
+				subresources.Scale = nil
+				crd.Spec.Versions[i].Subresources = subresources
+			}
+		}
+	}
+
+	if r.syncedPrinterColumns {
+		addSyncedPrinterColumns(crd)
+	}
+
+	if !r.crdAllowed(crd.Name) {
+		conditions.MarkFalse(
+			binding,
+			kubebindv1alpha1.APIServiceBindingConditionConnected,
+			"CustomResourceDefinitionNotAllowed",
+			conditionsapi.ConditionSeverityError,
+			"CustomResourceDefinition %s is not in the CRD allowlist of the konnector.",
+			crd.Name,
+		)
+		return nil
+	}
 
 	// put binding owner reference on the CRD.
 	newReference := metav1.OwnerReference{
@@ -142,29 +238,40 @@ func (r *reconciler) ensureCRDs(ctx context.Context, binding *kubebindv1alpha1.A
 				"CustomResourceDefinitionCreateFailed",
 				conditionsapi.ConditionSeverityError,
 				"CustomResourceDefinition %s cannot be created: %s",
-				binding.Name, err,
+				crd.Name, err,
 			)
 			return nil
 		}
 
+		binding.Status.SchemaRevision = export.Spec.SchemaRevision
 		conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionConnected)
 		return nil // we wait for a new reconcile to update APIServiceExport status
 	}
 
 	// first check this really ours and we don't override something else
-	if !kubebindhelpers.IsOwnedByBinding(binding.Name, binding.UID, crd.OwnerReferences) {
-		conditions.MarkFalse(
-			binding,
-			kubebindv1alpha1.APIServiceBindingConditionConnected,
-			"ForeignCustomResourceDefinition",
-			conditionsapi.ConditionSeverityError,
-			"CustomResourceDefinition %s is not owned by kube-bind.io.",
-			binding.Name,
-		)
-		return nil
+	ownerReferences := existing.OwnerReferences
+	if !kubebindhelpers.IsOwnedByBinding(binding.Name, binding.UID, existing.OwnerReferences) {
+		if binding.Spec.Adoption != kubebindv1alpha1.AdoptionPolicyAdopt {
+			conditions.MarkFalse(
+				binding,
+				kubebindv1alpha1.APIServiceBindingConditionConnected,
+				"ForeignCustomResourceDefinition",
+				conditionsapi.ConditionSeverityError,
+				"CustomResourceDefinition %s is not owned by kube-bind.io. Set adoption to Adopt or a groupAlias to bind it under another group.",
+				crd.Name,
+			)
+			return nil
+		}
+
+		klog.FromContext(ctx).Info("Adopting CustomResourceDefinition", "name", existing.Name)
+		if metav1.GetControllerOf(existing) != nil {
+			newReference.Controller = nil
+		}
+		ownerReferences = append(append([]metav1.OwnerReference(nil), existing.OwnerReferences...), newReference)
 	}
 
-	crd.ObjectMeta = existing.ObjectMeta
+	crd.ObjectMeta = *existing.ObjectMeta.DeepCopy()
+	crd.OwnerReferences = ownerReferences
 	if _, err := r.updateCRD(ctx, crd); err != nil && !errors.IsInvalid(err) {
 		return nil
 	} else if errors.IsInvalid(err) {
@@ -174,16 +281,34 @@ func (r *reconciler) ensureCRDs(ctx context.Context, binding *kubebindv1alpha1.A
 			"CustomResourceDefinitionUpdateFailed",
 			conditionsapi.ConditionSeverityError,
 			"CustomResourceDefinition %s cannot be updated: %s",
-			binding.Name, err,
+			crd.Name, err,
 		)
 		return nil
 	}
 
+	binding.Status.SchemaRevision = export.Spec.SchemaRevision
 	conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionConnected)
 
 	return utilerrors.NewAggregate(errs)
 }
 
+func (r *reconciler) crdAllowed(name string) bool {
+	if len(r.crdAllowlist) == 0 {
+		return true
+	}
+	for _, allowed := range r.crdAllowlist {
+		if allowed == name {
+			return true
+		}
+		if group := strings.TrimPrefix(allowed, "*."); group != allowed {
+			if parts := strings.SplitN(name, ".", 2); len(parts) == 2 && parts[1] == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (r *reconciler) ensurePrettyName(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
 	clusterBinding, err := r.getClusterBinding(ctx)
 	if err != nil && !errors.IsNotFound(err) {
@@ -194,6 +319,86 @@ func (r *reconciler) ensurePrettyName(ctx context.Context, binding *kubebindv1al
 	}
 
 	binding.Status.ProviderPrettyName = clusterBinding.Spec.ProviderPrettyName
+	binding.Status.ExpirationTime = clusterBinding.Spec.ExpirationTime
+
+	return nil
+}
+
+// ensureLimits propagates the plan limits of the service provider, for the admission
+// webhook to enforce them.
+func (r *reconciler) ensureLimits(binding *kubebindv1alpha1.APIServiceBinding) error {
+	export, err := r.getServiceExport(kubebindhelpers.ExportName(binding))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		return nil // keep the last known limits
+	}
+
+	binding.Status.Limits = export.Spec.Limits.DeepCopy()
+
+	return nil
+}
+
+// ensureTrialConditions reports the expiry of trial bindings. The expiration time is
+// kept when the service provider deletes the expired ClusterBinding.
+func (r *reconciler) ensureTrialConditions(binding *kubebindv1alpha1.APIServiceBinding) {
+	expiration := binding.Status.ExpirationTime
+	if expiration == nil {
+		conditions.Delete(binding, kubebindv1alpha1.APIServiceBindingConditionTrialActive)
+		return
+	}
+
+	left := time.Until(expiration.Time)
+	if left <= 0 {
+		conditions.MarkFalse(
+			binding,
+			kubebindv1alpha1.APIServiceBindingConditionTrialActive,
+			"TrialExpired",
+			conditionsapi.ConditionSeverityError,
+			"The trial binding with the service provider expired at %s. Bind again with a regular binding to keep using the service.",
+			expiration.Time,
+		)
+		return
+	}
+
+	conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionTrialActive)
+	r.requeueAfter(binding, left)
+}
+
+func (r *reconciler) ensureCapabilities(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
+	required, found := binding.Annotations[kubebindv1alpha1.RequiredCapabilitiesAnnotationKey]
+	if !found {
+		conditions.Delete(binding, kubebindv1alpha1.APIServiceBindingConditionCapabilitiesSatisfied)
+		return nil
+	}
+
+	export, err := r.getServiceExport(kubebindhelpers.ExportName(binding))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		return nil // covered by the Connected condition
+	}
+
+	missing := kubebindhelpers.MissingCapabilities(export, required)
+	for _, c := range strings.Split(required, ",") {
+		capability := kubebindv1alpha1.APIServiceExportCapability(strings.TrimSpace(c))
+		if kubebindhelpers.HasCapability(export, capability) && !features.CapabilityEnabled(features.DefaultFeatureGate, capability) {
+			missing = append(missing, string(capability)+" (disabled by feature gate in the konnector)")
+		}
+	}
+	if len(missing) > 0 {
+		conditions.MarkFalse(
+			binding,
+			kubebindv1alpha1.APIServiceBindingConditionCapabilitiesSatisfied,
+			"CapabilitiesMissing",
+			conditionsapi.ConditionSeverityWarning,
+			"APIServiceExport %s does not advertise required capabilities: %s",
+			kubebindhelpers.ExportName(binding), strings.Join(missing, ", "),
+		)
+		return nil
+	}
+
+	conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionCapabilitiesSatisfied)
 
 	return nil
 }
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kube-bind/kube-bind/pkg/konnector/slo"
+)
+
+const (
+	defaultSLOObjective = 0.99
+	defaultSLOWindow    = time.Hour
+)
+
+var (
+	sloBurnRate = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "slo_burn_rate",
+		Help:           "Rate the error budget of the sync-latency objective of an APIServiceBinding is consumed with. Above 1 the objective is missed.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"binding"})
+)
+
+func init() {
+	legacyregistry.MustRegister(sloBurnRate)
+}
+
+// ensureSLO computes the error budget of spec.slo from the canary probes and
+// reflects it in status.slo and the SLOMet condition.
+func (r *reconciler) ensureSLO(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) {
+	policy := binding.Spec.SLO
+	if policy == nil || r.sloTracker == nil {
+		binding.Status.SLO = nil
+		conditions.Delete(binding, kubebindv1alpha1.APIServiceBindingConditionSLOMet)
+		sloBurnRate.DeleteLabelValues(binding.Name)
+		return
+	}
+
+	objective := defaultSLOObjective
+	if policy.Objective != "" {
+		if v, err := strconv.ParseFloat(policy.Objective, 64); err == nil {
+			objective = v / 100
+		}
+	}
+	window := defaultSLOWindow
+	if policy.Window != nil && policy.Window.Duration > 0 {
+		window = policy.Window.Duration
+	}
+	if window > slo.MaxWindow {
+		window = slo.MaxWindow
+	}
+
+	budget := slo.Evaluate(r.sloTracker.Probes(kubebindhelpers.ExportName(binding), time.Now().Add(-window)), policy.LatencyTarget.Duration, objective)
+	if budget.Probes == 0 {
+		binding.Status.SLO = nil
+		conditions.MarkUnknown(
+			binding,
+			kubebindv1alpha1.APIServiceBindingConditionSLOMet,
+			"NoProbes",
+			"No canary probes in the last %s. The service provider must provide a canary object and the konnector must run with --canary-interval.",
+			window,
+		)
+		sloBurnRate.DeleteLabelValues(binding.Name)
+		return
+	}
+
+	burnRate := strconv.FormatFloat(budget.BurnRate, 'f', 2, 64)
+	if math.IsInf(budget.BurnRate, 1) {
+		burnRate = "Inf"
+	}
+	binding.Status.SLO = &kubebindv1alpha1.SLOStatus{
+		Probes:   int32(budget.Probes),
+		Missed:   int32(budget.Missed),
+		BurnRate: burnRate,
+	}
+	sloBurnRate.WithLabelValues(binding.Name).Set(budget.BurnRate)
+
+	if budget.Met() {
+		conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionSLOMet)
+		return
+	}
+	conditions.MarkFalse(
+		binding,
+		kubebindv1alpha1.APIServiceBindingConditionSLOMet,
+		"BudgetBurning",
+		conditionsapi.ConditionSeverityWarning,
+		"Error budget burns at %sx over the last %s: %d of %d canary probes failed or exceeded the latency target of %s.",
+		burnRate,
+		window,
+		budget.Missed,
+		budget.Probes,
+		policy.LatencyTarget.Duration,
+	)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+)
+
+func TestEnsureTrialConditions(t *testing.T) {
+	tests := []struct {
+		name          string
+		expiration    *time.Duration
+		wantCondition corev1.ConditionStatus
+		wantRequeue   bool
+	}{
+		{
+			name: "no trial",
+		},
+		{
+			name:          "not expired",
+			expiration:    durationPtr(time.Hour),
+			wantCondition: corev1.ConditionTrue,
+			wantRequeue:   true,
+		},
+		{
+			name:          "expired",
+			expiration:    durationPtr(-time.Minute),
+			wantCondition: corev1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requeued time.Duration
+			r := &reconciler{
+				requeueAfter: func(binding *kubebindv1alpha1.APIServiceBinding, after time.Duration) {
+					requeued = after
+				},
+			}
+			binding := &kubebindv1alpha1.APIServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com"}}
+			if tt.expiration != nil {
+				binding.Status.ExpirationTime = &metav1.Time{Time: time.Now().Add(*tt.expiration)}
+			}
+			conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionTrialActive)
+
+			r.ensureTrialConditions(binding)
+
+			cond := conditions.Get(binding, kubebindv1alpha1.APIServiceBindingConditionTrialActive)
+			if tt.wantCondition == "" {
+				require.Nil(t, cond)
+			} else {
+				require.NotNil(t, cond)
+				require.Equal(t, tt.wantCondition, cond.Status)
+			}
+			if tt.wantRequeue {
+				require.InDelta(t, time.Hour, requeued, float64(time.Minute), "expected requeue at the expiration time")
+			} else {
+				require.Zero(t, requeued)
+			}
+		})
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
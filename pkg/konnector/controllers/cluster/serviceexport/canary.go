@@ -0,0 +1,302 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kube-bind/kube-bind/pkg/konnector/slo"
+)
+
+const (
+	canaryObjectName       = "kube-bind-canary"
+	canaryNamespace        = "kube-bind"
+	canaryProbePlaceholder = "{{probe}}"
+)
+
+var (
+	canaryProbes = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "canary_probes_total",
+		Help:           "Number of canary probes through the full sync path by APIServiceExport and result.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"export", "result"})
+
+	canaryRoundTrip = metrics.NewHistogramVec(&metrics.HistogramOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "canary_round_trip_seconds",
+		Help:           "Time for a successful canary probe to be created, updated and deleted in the consumer cluster and the service provider cluster.",
+		Buckets:        metrics.ExponentialBuckets(0.25, 2, 12),
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"export"})
+)
+
+func init() {
+	legacyregistry.MustRegister(canaryProbes)
+	legacyregistry.MustRegister(canaryRoundTrip)
+}
+
+// canaryResult is the outcome of the last canary probe of an APIServiceExport.
+type canaryResult struct {
+	latency time.Duration
+	err     error
+}
+
+// canaryTemplate returns the canary object template of the export, or empty if
+// the export is not probed.
+func (r *reconciler) canaryTemplate(export *kubebindv1alpha1.APIServiceExport) string {
+	if r.canaryInterval == 0 || kubebindhelpers.HasCapability(export, kubebindv1alpha1.APIServiceExportCapabilityReadOnly) {
+		return ""
+	}
+	return export.Annotations[kubebindv1alpha1.CanaryObjectAnnotationKey]
+}
+
+// runCanary probes the sync of the given resource every canaryInterval until ctx is done.
+func (r *reconciler) runCanary(ctx context.Context, name string, consumerGVR, providerGVR runtimeschema.GroupVersionResource, kind string, namespaced bool, isolation kubebindv1alpha1.Isolation, template string) {
+	logger := klog.FromContext(ctx).WithValues("canary", name)
+
+	consumerClient, err := dynamicclient.NewForConfig(r.consumerConfig)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	providerClient, err := dynamicclient.NewForConfig(r.providerConfig)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	p := &canaryProbe{
+		consumerGVR:    consumerGVR,
+		providerGVR:    providerGVR,
+		kind:           kind,
+		template:       template,
+		timeout:        r.canaryInterval,
+		consumerClient: consumerClient,
+		providerClient: providerClient,
+		providerNamespace: func(ns string) (string, error) {
+			sn, err := r.serviceNamespaceInformer.Lister().APIServiceNamespaces(r.providerNamespace).Get(kubebindhelpers.ServiceNamespaceName(isolation, ns, canaryObjectName))
+			if err != nil {
+				return "", err
+			}
+			return sn.Status.Namespace, nil
+		},
+	}
+	if namespaced {
+		p.namespace = canaryNamespace
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		start := time.Now()
+		err := p.run(ctx)
+		if ctx.Err() != nil {
+			return // stopped, the result is meaningless
+		}
+		latency := time.Since(start)
+
+		if err != nil {
+			logger.V(1).Info("canary probe failed", "err", err.Error())
+			canaryProbes.WithLabelValues(name, "failure").Inc()
+		} else {
+			logger.V(4).Info("canary probe succeeded", "latency", latency)
+			canaryProbes.WithLabelValues(name, "success").Inc()
+			canaryRoundTrip.WithLabelValues(name).Observe(latency.Seconds())
+		}
+
+		r.lock.Lock()
+		r.canaryResults[name] = canaryResult{latency: latency, err: err}
+		r.lock.Unlock()
+		r.sloTracker.Record(name, slo.Probe{Time: time.Now(), Latency: latency, Success: err == nil})
+		r.requeue(name)
+	}, r.canaryInterval)
+}
+
+// ensureCanaryCondition reflects the last canary probe in the CanaryHealthy condition.
+func (r *reconciler) ensureCanaryCondition(ctx context.Context, export *kubebindv1alpha1.APIServiceExport) {
+	if r.canaryTemplate(export) == "" {
+		conditions.Delete(export, kubebindv1alpha1.APIServiceExportConditionCanaryHealthy)
+		return
+	}
+
+	r.lock.Lock()
+	result, found := r.canaryResults[export.Name]
+	r.lock.Unlock()
+
+	switch {
+	case !found:
+		conditions.MarkFalse(
+			export,
+			kubebindv1alpha1.APIServiceExportConditionCanaryHealthy,
+			"Pending",
+			conditionsapi.ConditionSeverityInfo,
+			"No canary probe has finished yet.",
+		)
+	case result.err != nil:
+		conditions.MarkFalse(
+			export,
+			kubebindv1alpha1.APIServiceExportConditionCanaryHealthy,
+			"ProbeFailed",
+			conditionsapi.ConditionSeverityWarning,
+			"Canary probe failed after %s: %v",
+			result.latency.Round(time.Millisecond),
+			result.err,
+		)
+	default:
+		conditions.MarkTrue(export, kubebindv1alpha1.APIServiceExportConditionCanaryHealthy)
+	}
+}
+
+// canaryProbe creates, updates and deletes a probe object in the consumer cluster and
+// waits for every step to be reflected in the service provider cluster.
+type canaryProbe struct {
+	consumerGVR, providerGVR runtimeschema.GroupVersionResource
+
+	kind      string
+	namespace string
+	template  string
+	timeout   time.Duration
+
+	consumerClient, providerClient dynamicclient.Interface
+	providerNamespace              func(ns string) (string, error)
+}
+
+func (p *canaryProbe) run(ctx context.Context) error {
+	consumer := p.consumerClient.Resource(p.consumerGVR).Namespace(p.namespace)
+
+	// clean up what an interrupted probe left behind
+	if err := consumer.Delete(ctx, canaryObjectName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete old canary object: %w", err)
+	}
+	if err := p.waitFor(ctx, "old canary object to be deleted", p.consumerGone); err != nil {
+		return err
+	}
+
+	obj, err := p.render(strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err != nil {
+		return err
+	}
+	created, err := consumer.Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create canary object: %w", err)
+	}
+	if err := p.waitFor(ctx, "canary object to be created upstream", p.providerAtGeneration(created.GetGeneration())); err != nil {
+		return err
+	}
+
+	if strings.Contains(p.template, canaryProbePlaceholder) {
+		obj, err := p.render(strconv.FormatInt(time.Now().UnixNano(), 10))
+		if err != nil {
+			return err
+		}
+		obj.SetResourceVersion(created.GetResourceVersion())
+		updated, err := consumer.Update(ctx, obj, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to update canary object: %w", err)
+		}
+		if err := p.waitFor(ctx, "canary object to be updated upstream", p.providerAtGeneration(updated.GetGeneration())); err != nil {
+			return err
+		}
+	}
+
+	if err := consumer.Delete(ctx, canaryObjectName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete canary object: %w", err)
+	}
+	if err := p.waitFor(ctx, "canary object to be deleted upstream", p.providerGone); err != nil {
+		return err
+	}
+	return p.waitFor(ctx, "canary object to be deleted", p.consumerGone)
+}
+
+// render returns the canary object with the given probe value.
+func (p *canaryProbe) render(probe string) (*unstructured.Unstructured, error) {
+	content := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(strings.ReplaceAll(p.template, canaryProbePlaceholder, probe)), &content); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", kubebindv1alpha1.CanaryObjectAnnotationKey, err)
+	}
+	delete(content, "metadata")
+
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion(p.consumerGVR.GroupVersion().String())
+	obj.SetKind(p.kind)
+	obj.SetName(canaryObjectName)
+	obj.SetNamespace(p.namespace)
+	obj.SetLabels(map[string]string{kubebindv1alpha1.CanaryLabelKey: "true"})
+	return obj, nil
+}
+
+func (p *canaryProbe) waitFor(ctx context.Context, what string, condition wait.ConditionWithContextFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if err := wait.PollImmediateUntilWithContext(ctx, time.Second, condition); err != nil {
+		return fmt.Errorf("timed out waiting for %s: %w", what, err)
+	}
+	return nil
+}
+
+func (p *canaryProbe) consumerGone(ctx context.Context) (bool, error) {
+	_, err := p.consumerClient.Resource(p.consumerGVR).Namespace(p.namespace).Get(ctx, canaryObjectName, metav1.GetOptions{})
+	return errors.IsNotFound(err), nil
+}
+
+func (p *canaryProbe) getProviderObject(ctx context.Context) (*unstructured.Unstructured, error) {
+	ns := ""
+	if p.namespace != "" {
+		var err error
+		if ns, err = p.providerNamespace(p.namespace); err != nil {
+			return nil, err
+		} else if ns == "" {
+			return nil, errors.NewNotFound(runtimeschema.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiservicenamespaces"}, p.namespace)
+		}
+	}
+	return p.providerClient.Resource(p.providerGVR).Namespace(ns).Get(ctx, canaryObjectName, metav1.GetOptions{})
+}
+
+func (p *canaryProbe) providerAtGeneration(generation int64) wait.ConditionWithContextFunc {
+	return func(ctx context.Context) (bool, error) {
+		obj, err := p.getProviderObject(ctx)
+		if err != nil {
+			return false, nil // not there yet, or transient
+		}
+		return obj.GetAnnotations()[kubebindv1alpha1.ConsumerGenerationAnnotationKey] == strconv.FormatInt(generation, 10), nil
+	}
+}
+
+func (p *canaryProbe) providerGone(ctx context.Context) (bool, error) {
+	_, err := p.getProviderObject(ctx)
+	return errors.IsNotFound(err), nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceexport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCanaryRender(t *testing.T) {
+	p := &canaryProbe{
+		consumerGVR: runtimeschema.GroupVersionResource{Group: "mangodb.com", Version: "v1alpha1", Resource: "mangodbs"},
+		kind:        "MangoDB",
+		namespace:   canaryNamespace,
+		template:    `{"metadata":{"name":"foo"},"spec":{"tier":"Dedicated","comment":"probe {{probe}}"}}`,
+	}
+
+	obj, err := p.render("42")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"apiVersion": "mangodb.com/v1alpha1",
+		"kind":       "MangoDB",
+		"metadata": map[string]interface{}{
+			"name":      canaryObjectName,
+			"namespace": canaryNamespace,
+			"labels":    map[string]interface{}{"kube-bind.io/canary": "true"},
+		},
+		"spec": map[string]interface{}{"tier": "Dedicated", "comment": "probe 42"},
+	}, obj.Object)
+
+	p.template = `{"spec":`
+	_, err = p.render("42")
+	require.Error(t, err)
+}
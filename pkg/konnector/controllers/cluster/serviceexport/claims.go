@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+// claimInformer watches the consumer objects of an accepted cluster-scoped claim.
+// The konnector only reads them. They are never written.
+type claimInformer struct {
+	claim    kubebindv1alpha1.ClusterScopedClaim
+	informer informers.GenericInformer
+}
+
+// startClaimInformers starts informers for the given claims that requeue the
+// APIServiceExport on every change. They stop when ctx is done.
+func (r *reconciler) startClaimInformers(ctx context.Context, name string, claims []kubebindv1alpha1.ClusterScopedClaim, resyncPeriod time.Duration) ([]claimInformer, error) {
+	if len(claims) == 0 {
+		return nil, nil
+	}
+
+	dynamicClient, err := dynamicclient.NewForConfig(r.consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+	metadataClient, err := metadata.NewForConfig(r.consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+	dynamicInf := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, resyncPeriod)
+	metadataInf := metadatainformer.NewSharedInformerFactory(metadataClient, resyncPeriod)
+
+	requeue := func(interface{}) { r.requeue(name) }
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    requeue,
+		UpdateFunc: func(_, obj interface{}) { requeue(obj) },
+		DeleteFunc: requeue,
+	}
+
+	ret := make([]claimInformer, 0, len(claims))
+	for _, claim := range claims {
+		claimable, ok := kubebindhelpers.Claimable(claim)
+		if !ok {
+			continue
+		}
+		gvr := runtimeschema.GroupVersionResource{Group: claim.Group, Version: claimable.Version, Resource: claim.Resource}
+		var inf informers.GenericInformer
+		if claimable.MetadataOnly {
+			inf = metadataInf.ForResource(gvr)
+		} else {
+			inf = dynamicInf.ForResource(gvr)
+		}
+		inf.Informer().AddEventHandler(handler)
+		ret = append(ret, claimInformer{claim: claim, informer: inf})
+	}
+
+	dynamicInf.Start(ctx.Done())
+	metadataInf.Start(ctx.Done())
+
+	return ret, nil
+}
+
+// ensureClaimedObjects copies the objects of the accepted cluster-scoped claims
+// into the APIServiceExport status.
+func (r *reconciler) ensureClaimedObjects(ctx context.Context, export *kubebindv1alpha1.APIServiceExport) error {
+	r.lock.Lock()
+	c, found := r.syncContext[export.Name]
+	r.lock.Unlock()
+	if found && c.paused {
+		return nil // not syncing, keep the last state
+	}
+	if !found || len(c.claims) == 0 {
+		export.Status.ClaimedObjects = nil
+		return nil
+	}
+	if c.hibernated {
+		return nil // informers are stopped, keep the last state
+	}
+
+	var objs []kubebindv1alpha1.ClaimedObject
+	for _, ci := range c.claims {
+		if !ci.informer.Informer().HasSynced() {
+			return nil // keep the old state until all informers are synced
+		}
+		list, err := ci.informer.Lister().List(labels.Everything())
+		if err != nil {
+			return err
+		}
+		for _, obj := range list {
+			claimed, err := kubebindhelpers.ClaimedObject(ci.claim, obj)
+			if err != nil {
+				return err
+			}
+			objs = append(objs, claimed)
+		}
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].Group != objs[j].Group {
+			return objs[i].Group < objs[j].Group
+		}
+		if objs[i].Resource != objs[j].Resource {
+			return objs[i].Resource < objs[j].Resource
+		}
+		return objs[i].Name < objs[j].Name
+	})
+	export.Status.ClaimedObjects = objs
+
+	return nil
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventrelay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	kubernetesclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/indexers"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+)
+
+const (
+	controllerName = "kube-bind-konnector-cluster-eventrelay"
+)
+
+var eventsGVR = corev1.SchemeGroupVersion.WithResource("events")
+
+// NewController returns a new controller relaying Events about bound objects between
+// the service provider and the consumer cluster. The GVRs differ if the consumer binds
+// the resource under a group alias. Only namespaced resources are supported.
+func NewController(
+	consumerGVR, providerGVR schema.GroupVersionResource,
+	kind string,
+	providerNamespace string,
+	isolation kubebindv1alpha1.Isolation,
+	relay kubebindv1alpha1.EventRelay,
+	consumerNamespaces []string,
+	resync tuning.Resync,
+	consumerConfig, providerConfig *rest.Config,
+	consumerDynamicInformer informers.GenericInformer,
+	providerDynamicInformer multinsinformer.GetterInformer,
+	serviceNamespaceInformer dynamic.Informer[bindlisters.APIServiceNamespaceLister],
+	rateLimiter tuning.RateLimiter,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
+
+	logger := klog.Background().WithValues("controller", controllerName)
+
+	consumerConfig = rest.CopyConfig(consumerConfig)
+	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
+
+	providerConfig = rest.CopyConfig(providerConfig)
+	providerConfig = rest.AddUserAgent(providerConfig, controllerName)
+
+	consumerClient, err := kubernetesclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+	providerClient, err := kubernetesclient.NewForConfig(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// provider Events are read in the provider namespaces of the APIServiceNamespaces
+	providerEvents, err := multinsinformer.NewDynamicMultiNamespaceInformer(eventsGVR, providerNamespace, providerConfig, resync.ProviderPeriod, serviceNamespaceInformer)
+	if err != nil {
+		return nil, err
+	}
+
+	var consumerEvents dynamicinformer.DynamicSharedInformerFactory
+	if relay.Upsync {
+		dynamicConsumerClient, err := dynamicclient.NewForConfig(consumerConfig)
+		if err != nil {
+			return nil, err
+		}
+		consumerEvents = multinsinformer.NewNamespacesDynamicSharedInformerFactory(dynamicConsumerClient, resync.Period, consumerNamespaces)
+	}
+
+	c := &controller{
+		queue: queue,
+
+		providerEvents: providerEvents,
+		consumerEvents: consumerEvents,
+
+		reconciler: reconciler{
+			consumerGVR: consumerGVR,
+			providerGVR: providerGVR,
+			kind:        kind,
+			relay:       relay,
+
+			getServiceNamespace: func(upstreamNamespace string) (*kubebindv1alpha1.APIServiceNamespace, error) {
+				sns, err := serviceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, upstreamNamespace)
+				if err != nil {
+					return nil, err
+				}
+				for _, obj := range sns {
+					if sn := obj.(*kubebindv1alpha1.APIServiceNamespace); sn.Namespace == providerNamespace {
+						return sn, nil
+					}
+				}
+				return nil, errors.NewNotFound(kubebindv1alpha1.SchemeGroupVersion.WithResource("APIServiceNamespace").GroupResource(), upstreamNamespace)
+			},
+			getUpstreamNamespace: func(downstreamNamespace, name string) (string, error) {
+				sn, err := serviceNamespaceInformer.Lister().APIServiceNamespaces(providerNamespace).Get(kubebindhelpers.ServiceNamespaceName(isolation, downstreamNamespace, name))
+				if err != nil {
+					return "", err
+				}
+				return sn.Status.Namespace, nil
+			},
+			getConsumerObject: func(ns, name string) (metav1.Object, error) {
+				obj, err := consumerDynamicInformer.Lister().ByNamespace(ns).Get(name)
+				if err != nil {
+					return nil, err
+				}
+				return obj.(*unstructured.Unstructured), nil
+			},
+			getProviderObject: func(ns, name string) (metav1.Object, error) {
+				obj, err := providerDynamicInformer.Get(ns, name)
+				if err != nil {
+					return nil, err
+				}
+				return obj.(*unstructured.Unstructured), nil
+			},
+
+			getProviderEvent: func(ns, name string) (*corev1.Event, error) {
+				obj, err := providerEvents.Get(ns, name)
+				if err != nil {
+					return nil, err
+				}
+				return toEvent(obj)
+			},
+			getConsumerEvent: func(ns, name string) (*corev1.Event, error) {
+				obj, err := consumerEvents.ForResource(eventsGVR).Lister().ByNamespace(ns).Get(name)
+				if err != nil {
+					return nil, err
+				}
+				return toEvent(obj)
+			},
+
+			writeConsumerEvent: func(ctx context.Context, e *corev1.Event) error {
+				return writeEvent(ctx, consumerClient, e)
+			},
+			writeProviderEvent: func(ctx context.Context, e *corev1.Event) error {
+				return writeEvent(ctx, providerClient, e)
+			},
+		},
+	}
+
+	providerEvents.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueue(logger, kubebindv1alpha1.RelayedFromProvider, obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueue(logger, kubebindv1alpha1.RelayedFromProvider, newObj)
+		},
+	})
+	if consumerEvents != nil {
+		consumerEvents.ForResource(eventsGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueue(logger, kubebindv1alpha1.RelayedFromConsumer, obj)
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				c.enqueue(logger, kubebindv1alpha1.RelayedFromConsumer, newObj)
+			},
+		})
+	}
+
+	return c, nil
+}
+
+// controller relays Events about bound objects between the service provider and the
+// consumer cluster. Deletions are not relayed. Relayed Events expire like any other.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	providerEvents multinsinformer.GetterInformer
+	consumerEvents dynamicinformer.DynamicSharedInformerFactory
+
+	reconciler
+}
+
+// queueKey is an Event of the consumer or provider cluster.
+type queueKey struct {
+	origin          string
+	namespace, name string
+}
+
+func (c *controller) enqueue(logger klog.Logger, origin string, obj interface{}) {
+	e, err := toEvent(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	if !c.relevant(origin, e) {
+		return
+	}
+
+	key := queueKey{origin: origin, namespace: e.Namespace, name: e.Name}
+	logger.V(2).Info("queueing Event", "origin", origin, "key", e.Namespace+"/"+e.Name)
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithValues("controller", controllerName, "gvr", c.consumerGVR)
+	ctx = klog.NewContext(ctx, logger)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	go c.providerEvents.Start(ctx)
+	if c.consumerEvents != nil {
+		c.consumerEvents.Start(ctx.Done())
+	}
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(queueKey)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	logger := klog.FromContext(ctx).WithValues("origin", key.origin, "key", key.namespace+"/"+key.name)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(2).Info("processing key")
+
+	if err := c.process(ctx, key); err != nil {
+		utilruntime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key queueKey) error {
+	if key.origin == kubebindv1alpha1.RelayedFromProvider {
+		e, err := c.getProviderEvent(key.namespace, key.name)
+		if errors.IsNotFound(err) {
+			return nil // expired
+		} else if err != nil {
+			return err
+		}
+		return c.downsync(ctx, e)
+	}
+
+	e, err := c.getConsumerEvent(key.namespace, key.name)
+	if errors.IsNotFound(err) {
+		return nil // expired
+	} else if err != nil {
+		return err
+	}
+	return c.upsync(ctx, e)
+}
+
+func toEvent(obj interface{}) (*corev1.Event, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	var e corev1.Event
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// writeEvent creates the Event, or updates it if it exists and differs.
+func writeEvent(ctx context.Context, client kubernetesclient.Interface, e *corev1.Event) error {
+	existing, err := client.CoreV1().Events(e.Namespace).Get(ctx, e.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err := client.CoreV1().Events(e.Namespace).Create(ctx, e, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+	if !eventChanged(existing, e) {
+		return nil
+	}
+	e.ResourceVersion = existing.ResourceVersion
+	_, err = client.CoreV1().Events(e.Namespace).Update(ctx, e, metav1.UpdateOptions{})
+	return err
+}
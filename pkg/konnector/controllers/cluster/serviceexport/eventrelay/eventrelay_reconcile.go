@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventrelay
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+type reconciler struct {
+	consumerGVR, providerGVR schema.GroupVersionResource
+	kind                     string
+	relay                    kubebindv1alpha1.EventRelay
+
+	getServiceNamespace  func(upstreamNamespace string) (*kubebindv1alpha1.APIServiceNamespace, error)
+	getUpstreamNamespace func(downstreamNamespace, name string) (string, error)
+
+	getConsumerObject func(ns, name string) (metav1.Object, error)
+	getProviderObject func(ns, name string) (metav1.Object, error)
+
+	getProviderEvent func(ns, name string) (*corev1.Event, error)
+	getConsumerEvent func(ns, name string) (*corev1.Event, error)
+
+	writeConsumerEvent func(ctx context.Context, e *corev1.Event) error
+	writeProviderEvent func(ctx context.Context, e *corev1.Event) error
+}
+
+// relevant returns true if the Event of the given origin is about a bound object
+// and is selected for relaying.
+func (r *reconciler) relevant(origin string, e *corev1.Event) bool {
+	if _, relayed := e.Annotations[kubebindv1alpha1.RelayedEventAnnotationKey]; relayed {
+		return false // never relay back
+	}
+	if e.InvolvedObject.Kind != r.kind {
+		return false
+	}
+
+	if origin == kubebindv1alpha1.RelayedFromConsumer {
+		return r.relay.Upsync && e.InvolvedObject.APIVersion == r.consumerGVR.GroupVersion().String()
+	}
+	if e.InvolvedObject.APIVersion != r.providerGVR.GroupVersion().String() {
+		return false
+	}
+	for _, reason := range r.relay.Reasons {
+		if e.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// downsync relays a service provider Event into the consumer namespace of the object.
+func (r *reconciler) downsync(ctx context.Context, e *corev1.Event) error {
+	logger := klog.FromContext(ctx)
+
+	if !r.relevant(kubebindv1alpha1.RelayedFromProvider, e) {
+		return nil
+	}
+
+	sn, err := r.getServiceNamespace(e.Namespace)
+	if errors.IsNotFound(err) {
+		return nil // not a namespace of this consumer
+	} else if err != nil {
+		return err
+	}
+	ns := kubebindhelpers.ConsumerNamespace(sn.Name)
+
+	obj, err := r.getConsumerObject(ns, e.InvolvedObject.Name)
+	if errors.IsNotFound(err) {
+		logger.V(2).Info("Skipping Event of unknown downstream object", "downstreamNamespace", ns, "downstreamName", e.InvolvedObject.Name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	logger.V(2).Info("Relaying Event to consumer", "downstreamNamespace", ns, "reason", e.Reason)
+	return r.writeConsumerEvent(ctx, relayedEvent(e, kubebindv1alpha1.RelayedFromProvider, r.consumerGVR, obj))
+}
+
+// upsync relays a consumer Event into the provider namespace of the object.
+func (r *reconciler) upsync(ctx context.Context, e *corev1.Event) error {
+	logger := klog.FromContext(ctx)
+
+	if !r.relevant(kubebindv1alpha1.RelayedFromConsumer, e) {
+		return nil
+	}
+
+	ns, err := r.getUpstreamNamespace(e.Namespace, e.InvolvedObject.Name)
+	if errors.IsNotFound(err) {
+		return nil // not synced
+	} else if err != nil {
+		return err
+	} else if ns == "" {
+		return fmt.Errorf("upstream namespace of %s/%s not ready", e.Namespace, e.InvolvedObject.Name)
+	}
+
+	obj, err := r.getProviderObject(ns, e.InvolvedObject.Name)
+	if errors.IsNotFound(err) {
+		logger.V(2).Info("Skipping Event of unknown upstream object", "upstreamNamespace", ns, "upstreamName", e.InvolvedObject.Name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	logger.V(2).Info("Relaying Event to service provider", "upstreamNamespace", ns, "reason", e.Reason)
+	return r.writeProviderEvent(ctx, relayedEvent(e, kubebindv1alpha1.RelayedFromConsumer, r.providerGVR, obj))
+}
+
+// relayedEvent returns a copy of the Event about the given object in the other cluster.
+func relayedEvent(e *corev1.Event, origin string, gvr schema.GroupVersionResource, obj metav1.Object) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: obj.GetNamespace(),
+			Name:      e.Name,
+			Annotations: map[string]string{
+				kubebindv1alpha1.RelayedEventAnnotationKey: origin,
+			},
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: gvr.GroupVersion().String(),
+			Kind:       e.InvolvedObject.Kind,
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+			UID:        obj.GetUID(),
+			FieldPath:  e.InvolvedObject.FieldPath,
+		},
+		Reason:              e.Reason,
+		Message:             e.Message,
+		Type:                e.Type,
+		Source:              e.Source,
+		FirstTimestamp:      e.FirstTimestamp,
+		LastTimestamp:       e.LastTimestamp,
+		Count:               e.Count,
+		EventTime:           e.EventTime,
+		Series:              e.Series,
+		Action:              e.Action,
+		ReportingController: e.ReportingController,
+		ReportingInstance:   e.ReportingInstance,
+	}
+}
+
+// eventChanged returns true if the relayed Event differs from the existing one.
+func eventChanged(existing, relayed *corev1.Event) bool {
+	return existing.Message != relayed.Message ||
+		existing.Count != relayed.Count ||
+		!existing.LastTimestamp.Equal(&relayed.LastTimestamp) ||
+		!reflect.DeepEqual(existing.Series, relayed.Series) ||
+		existing.InvolvedObject != relayed.InvolvedObject
+}
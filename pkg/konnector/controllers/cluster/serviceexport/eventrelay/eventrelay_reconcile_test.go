@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventrelay
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	event := func(ns, apiVersion, reason string, annotations map[string]string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "db.17a", Annotations: annotations},
+			InvolvedObject: corev1.ObjectReference{
+				APIVersion: apiVersion,
+				Kind:       "MangoDB",
+				Namespace:  ns,
+				Name:       "db",
+				UID:        "upstream-uid",
+			},
+			Reason:  reason,
+			Message: "done",
+			Count:   1,
+		}
+	}
+	tests := []struct {
+		name     string
+		origin   string
+		event    *corev1.Event
+		upsync   bool
+		wantNS   string
+		wantUID  string
+		wantFrom string
+	}{
+		{
+			name:     "downsync",
+			origin:   kubebindv1alpha1.RelayedFromProvider,
+			event:    event("kube-bind-abc-default", "mangodb.com/v1", "BackupCompleted", nil),
+			wantNS:   "default",
+			wantUID:  "downstream-uid",
+			wantFrom: kubebindv1alpha1.RelayedFromProvider,
+		},
+		{
+			name:   "reason not relayed",
+			origin: kubebindv1alpha1.RelayedFromProvider,
+			event:  event("kube-bind-abc-default", "mangodb.com/v1", "Reconciled", nil),
+		},
+		{
+			name:   "other group",
+			origin: kubebindv1alpha1.RelayedFromProvider,
+			event:  event("kube-bind-abc-default", "other.com/v1", "BackupCompleted", nil),
+		},
+		{
+			name:   "unknown provider namespace",
+			origin: kubebindv1alpha1.RelayedFromProvider,
+			event:  event("other", "mangodb.com/v1", "BackupCompleted", nil),
+		},
+		{
+			name:   "relayed before",
+			origin: kubebindv1alpha1.RelayedFromProvider,
+			event:  event("kube-bind-abc-default", "mangodb.com/v1", "BackupCompleted", map[string]string{kubebindv1alpha1.RelayedEventAnnotationKey: kubebindv1alpha1.RelayedFromConsumer}),
+		},
+		{
+			name:   "upsync not accepted",
+			origin: kubebindv1alpha1.RelayedFromConsumer,
+			event:  event("default", "mangodb.acme.example/v1", "Scaled", nil),
+		},
+		{
+			name:     "upsync",
+			origin:   kubebindv1alpha1.RelayedFromConsumer,
+			event:    event("default", "mangodb.acme.example/v1", "Scaled", nil),
+			upsync:   true,
+			wantNS:   "kube-bind-abc-default",
+			wantUID:  "upstream-uid",
+			wantFrom: kubebindv1alpha1.RelayedFromConsumer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written *corev1.Event
+			write := func(ctx context.Context, e *corev1.Event) error {
+				written = e
+				return nil
+			}
+			r := &reconciler{
+				consumerGVR: schema.GroupVersionResource{Group: "mangodb.acme.example", Version: "v1", Resource: "mangodbs"},
+				providerGVR: schema.GroupVersionResource{Group: "mangodb.com", Version: "v1", Resource: "mangodbs"},
+				kind:        "MangoDB",
+				relay:       kubebindv1alpha1.EventRelay{Reasons: []string{"BackupCompleted"}, Upsync: tt.upsync},
+
+				getServiceNamespace: func(upstreamNamespace string) (*kubebindv1alpha1.APIServiceNamespace, error) {
+					if upstreamNamespace != "kube-bind-abc-default" {
+						return nil, errors.NewNotFound(schema.GroupResource{}, upstreamNamespace)
+					}
+					return &kubebindv1alpha1.APIServiceNamespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, nil
+				},
+				getUpstreamNamespace: func(downstreamNamespace, name string) (string, error) {
+					return "kube-bind-abc-" + downstreamNamespace, nil
+				},
+				getConsumerObject: func(ns, name string) (metav1.Object, error) {
+					return &metav1.ObjectMeta{Namespace: ns, Name: name, UID: "downstream-uid"}, nil
+				},
+				getProviderObject: func(ns, name string) (metav1.Object, error) {
+					return &metav1.ObjectMeta{Namespace: ns, Name: name, UID: "upstream-uid"}, nil
+				},
+				writeConsumerEvent: write,
+				writeProviderEvent: write,
+			}
+
+			var err error
+			if tt.origin == kubebindv1alpha1.RelayedFromProvider {
+				err = r.downsync(context.Background(), tt.event)
+			} else {
+				err = r.upsync(context.Background(), tt.event)
+			}
+			require.NoError(t, err)
+
+			if tt.wantNS == "" {
+				require.Nil(t, written)
+				return
+			}
+			require.NotNil(t, written)
+			require.Equal(t, tt.wantNS, written.Namespace)
+			require.Equal(t, tt.wantNS, written.InvolvedObject.Namespace)
+			require.Equal(t, tt.wantUID, string(written.InvolvedObject.UID))
+			require.Equal(t, tt.wantFrom, written.Annotations[kubebindv1alpha1.RelayedEventAnnotationKey])
+			require.Equal(t, tt.event.Reason, written.Reason)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+var (
+	hibernatedBindings = metrics.NewGauge(&metrics.GaugeOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "hibernated_bindings",
+		Help:           "Number of idle APIServiceBindings whose syncers are stopped until the first object is created.",
+		StabilityLevel: metrics.ALPHA,
+	})
+)
+
+func init() {
+	legacyregistry.MustRegister(hibernatedBindings)
+}
+
+// activityTracker is an event handler recording the time of the last event.
+type activityTracker struct {
+	last atomic.Int64
+}
+
+var _ cache.ResourceEventHandler = &activityTracker{}
+
+func newActivityTracker() *activityTracker {
+	a := &activityTracker{}
+	a.touch()
+	return a
+}
+
+func (a *activityTracker) touch() {
+	a.last.Store(time.Now().UnixNano())
+}
+
+func (a *activityTracker) since() time.Duration {
+	return time.Since(time.Unix(0, a.last.Load()))
+}
+
+func (a *activityTracker) OnAdd(obj interface{})               { a.touch() }
+func (a *activityTracker) OnUpdate(oldObj, newObj interface{}) { a.touch() }
+func (a *activityTracker) OnDelete(obj interface{})            { a.touch() }
+
+// monitorIdle hibernates the sync of the given APIServiceExport when there are no
+// consumer objects and no events on either side for hibernateIdleAfter. Upstream
+// objects only exist for downstream objects, so the consumer side is enough to
+// decide.
+func (r *reconciler) monitorIdle(ctx context.Context, name string, gvr runtimeschema.GroupVersionResource, namespaces []string, consumerInformer cache.SharedIndexInformer, activity *activityTracker) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if activity.since() < r.hibernateIdleAfter || len(consumerInformer.GetStore().ListKeys()) > 0 {
+			return
+		}
+		r.hibernate(ctx, name, gvr, namespaces)
+	}, r.hibernateIdleAfter/4)
+}
+
+// hibernate stops the syncers of the given APIServiceExport and replaces them with a
+// cheap watch which wakes them up again on the first consumer object. ctx is the
+// context of the syncers to stop. Empty namespaces mean all namespaces.
+func (r *reconciler) hibernate(ctx context.Context, name string, gvr runtimeschema.GroupVersionResource, namespaces []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if ctx.Err() != nil {
+		return // stopped or replaced in the meantime
+	}
+	c, found := r.syncContext[name]
+	if !found || c.hibernated {
+		return
+	}
+
+	klog.FromContext(c.parent).V(1).Info("Hibernating idle APIServiceExport sync", "name", name, "idleAfter", r.hibernateIdleAfter)
+	c.cancel()
+
+	triggerCtx, cancel := context.WithCancel(c.parent)
+	c.hibernated = true
+	c.cancel = func() {
+		cancel()
+		hibernatedBindings.Dec()
+	}
+	r.syncContext[name] = c
+	hibernatedBindings.Inc()
+
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	for _, ns := range namespaces {
+		go r.waitForConsumerObject(triggerCtx, name, gvr, ns)
+	}
+}
+
+// waitForConsumerObject watches the consumer cluster without caching anything, and
+// wakes up the sync of the given APIServiceExport when an object shows up in the
+// given namespace, or in any namespace if empty.
+func (r *reconciler) waitForConsumerObject(ctx context.Context, name string, gvr runtimeschema.GroupVersionResource, ns string) {
+	client, err := dynamicclient.NewForConfig(r.consumerConfig)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		list, err := client.Resource(gvr).Namespace(ns).List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		if len(list.Items) > 0 {
+			r.wake(ctx, name)
+			return
+		}
+
+		w, err := client.Resource(gvr).Namespace(ns).Watch(ctx, metav1.ListOptions{ResourceVersion: list.GetResourceVersion()})
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		defer w.Stop()
+		for ev := range w.ResultChan() {
+			switch ev.Type {
+			case watch.Added, watch.Modified:
+				r.wake(ctx, name)
+				return
+			case watch.Error:
+				return // start over with a fresh list
+			}
+		}
+	}, 10*time.Second)
+}
+
+// wake drops the hibernated sync of the given APIServiceExport and requeues it to
+// start the syncers again. ctx is the context of the wake-up trigger.
+func (r *reconciler) wake(ctx context.Context, name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if ctx.Err() != nil {
+		return // stopped or replaced in the meantime
+	}
+	c, found := r.syncContext[name]
+	if !found || !c.hibernated {
+		return
+	}
+
+	klog.FromContext(c.parent).V(1).Info("Waking up hibernated APIServiceExport sync", "name", name)
+	c.cancel()
+	delete(r.syncContext, name)
+	r.requeue(name)
+}
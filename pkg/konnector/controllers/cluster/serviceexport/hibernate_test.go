@@ -0,0 +1,245 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/kube-bind/kube-bind/pkg/konnector/backpressure"
+	"github.com/kube-bind/kube-bind/pkg/konnector/slo"
+)
+
+var fooGVR = runtimeschema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "foos"}
+
+// newConsumerServer serves an empty list of foos, and a watch which sends an
+// added foo when created is closed.
+func newConsumerServer(t *testing.T, created <-chan struct{}) *rest.Config {
+	t.Helper()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if req.URL.Query().Get("watch") != "true" {
+			fmt.Fprint(w, `{"apiVersion":"example.com/v1","kind":"FooList","metadata":{"resourceVersion":"1"},"items":[]}`)
+			return
+		}
+		w.(http.Flusher).Flush()
+		select {
+		case <-created:
+			fmt.Fprint(w, `{"type":"ADDED","object":{"apiVersion":"example.com/v1","kind":"Foo","metadata":{"name":"a","namespace":"default","resourceVersion":"2"}}}`+"\n")
+			w.(http.Flusher).Flush()
+		case <-req.Context().Done():
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	return &rest.Config{Host: s.URL}
+}
+
+// newHibernationReconciler returns a reconciler with a running sync for foo, whose
+// cancel is counted in stopped.
+func newHibernationReconciler(ctx context.Context, config *rest.Config, requeued chan<- string, stopped *atomic.Int32) (*reconciler, context.Context) {
+	syncCtx, cancel := context.WithCancel(ctx)
+	return &reconciler{
+		consumerConfig:     config,
+		hibernateIdleAfter: 100 * time.Millisecond,
+		syncContext: map[string]syncContext{
+			"foo": {
+				generation: 1,
+				parent:     ctx,
+				cancel: func() {
+					stopped.Add(1)
+					cancel()
+				},
+			},
+		},
+		requeue: func(name string) { requeued <- name },
+	}, syncCtx
+}
+
+func hibernatedGauge(t *testing.T) float64 {
+	t.Helper()
+	v, err := testutil.GetGaugeMetricValue(hibernatedBindings)
+	require.NoError(t, err)
+	return v
+}
+
+func TestHibernateAndWake(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	base := hibernatedGauge(t)
+	created := make(chan struct{})
+	requeued := make(chan string, 1)
+	var stopped atomic.Int32
+	r, syncCtx := newHibernationReconciler(ctx, newConsumerServer(t, created), requeued, &stopped)
+
+	r.hibernate(syncCtx, "foo", fooGVR, nil)
+	require.Equal(t, int32(1), stopped.Load(), "syncers should be stopped")
+	require.Error(t, syncCtx.Err())
+	require.True(t, r.syncContext["foo"].hibernated)
+	require.Equal(t, base+1, hibernatedGauge(t))
+
+	// hibernating twice is a no-op
+	r.hibernate(ctx, "foo", fooGVR, nil)
+	require.Equal(t, base+1, hibernatedGauge(t))
+
+	select {
+	case name := <-requeued:
+		t.Fatalf("unexpected wake-up of %q without consumer objects", name)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(created)
+	select {
+	case name := <-requeued:
+		require.Equal(t, "foo", name)
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("timed out waiting for the wake-up")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	require.NotContains(t, r.syncContext, "foo", "hibernated sync should be dropped to be started again")
+	require.Equal(t, base, hibernatedGauge(t))
+}
+
+func TestHibernateRaces(t *testing.T) {
+	t.Run("syncers replaced before hibernation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		base := hibernatedGauge(t)
+		var stopped atomic.Int32
+		r, syncCtx := newHibernationReconciler(ctx, nil, make(chan string, 1), &stopped)
+
+		// a generation change or resync cancels the syncers of the old sync
+		r.syncContext["foo"].cancel()
+		r.hibernate(syncCtx, "foo", fooGVR, nil)
+
+		require.False(t, r.syncContext["foo"].hibernated)
+		require.Equal(t, int32(1), stopped.Load())
+		require.Equal(t, base, hibernatedGauge(t))
+	})
+
+	t.Run("sync replaced while hibernated", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		base := hibernatedGauge(t)
+		requeued := make(chan string, 1)
+		var stopped atomic.Int32
+		r, syncCtx := newHibernationReconciler(ctx, newConsumerServer(t, make(chan struct{})), requeued, &stopped)
+
+		r.hibernate(syncCtx, "foo", fooGVR, nil)
+		require.Equal(t, base+1, hibernatedGauge(t))
+
+		// a generation change or resync stops the hibernated sync and starts a new one
+		r.lock.Lock()
+		r.syncContext["foo"].cancel()
+		r.syncContext["foo"] = syncContext{generation: 2, parent: ctx, cancel: func() {}}
+		r.lock.Unlock()
+		require.Equal(t, base, hibernatedGauge(t))
+
+		// a late trigger of the old hibernated sync does not touch the new one
+		r.wake(ctx, "foo")
+		require.Equal(t, int64(2), r.syncContext["foo"].generation)
+		require.Empty(t, requeued)
+	})
+}
+
+func TestHibernatedGaugeOnStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	base := hibernatedGauge(t)
+	var stopped atomic.Int32
+	r, syncCtx := newHibernationReconciler(ctx, newConsumerServer(t, make(chan struct{})), make(chan string, 1), &stopped)
+	r.canaryResults = map[string]canaryResult{}
+	r.sloTracker = slo.NewTracker()
+	r.backpressure = backpressure.NewTracker()
+
+	r.hibernate(syncCtx, "foo", fooGVR, []string{"a", "b"})
+	require.Equal(t, base+1, hibernatedGauge(t))
+
+	err := r.ensureControllers(ctx, "foo", nil)
+	require.NoError(t, err)
+	require.NotContains(t, r.syncContext, "foo")
+	require.Equal(t, base, hibernatedGauge(t))
+}
+
+func TestMonitorIdle(t *testing.T) {
+	tests := []struct {
+		name          string
+		objects       int
+		wantHibernate bool
+	}{
+		{name: "idle without objects", wantHibernate: true},
+		{name: "idle with objects", objects: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var stopped atomic.Int32
+			r, syncCtx := newHibernationReconciler(ctx, newConsumerServer(t, make(chan struct{})), make(chan string, 1), &stopped)
+			defer func() {
+				r.lock.Lock()
+				defer r.lock.Unlock()
+				r.syncContext["foo"].cancel()
+			}()
+
+			informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &unstructured.Unstructured{}, 0, cache.Indexers{})
+			for i := 0; i < tt.objects; i++ {
+				obj := &unstructured.Unstructured{}
+				obj.SetNamespace(metav1.NamespaceDefault)
+				obj.SetName(fmt.Sprintf("foo-%d", i))
+				require.NoError(t, informer.GetStore().Add(obj))
+			}
+			activity := newActivityTracker()
+
+			go r.monitorIdle(syncCtx, "foo", fooGVR, nil, informer, activity)
+
+			hibernated := func() bool {
+				r.lock.Lock()
+				defer r.lock.Unlock()
+				return r.syncContext["foo"].hibernated
+			}
+			if tt.wantHibernate {
+				require.Eventually(t, hibernated, wait.ForeverTestTimeout, 10*time.Millisecond)
+			} else {
+				require.Never(t, hibernated, 500*time.Millisecond, 10*time.Millisecond)
+			}
+		})
+	}
+}
@@ -61,6 +61,7 @@ var _ GetterInformer = &DynamicMultiNamespaceInformer{}
 type DynamicMultiNamespaceInformer struct {
 	gvr               schema.GroupVersionResource
 	providerNamespace string
+	resyncPeriod      time.Duration
 
 	providerDynamicClient dynamicclient.Interface
 
@@ -76,6 +77,7 @@ func NewDynamicMultiNamespaceInformer(
 	gvr schema.GroupVersionResource,
 	providerNamespace string,
 	providerConfig *rest.Config,
+	resyncPeriod time.Duration,
 	serviceNamespaceInformer dynamic.Informer[bindlisters.APIServiceNamespaceLister],
 ) (*DynamicMultiNamespaceInformer, error) {
 	providerConfig = rest.CopyConfig(providerConfig)
@@ -89,6 +91,7 @@ func NewDynamicMultiNamespaceInformer(
 	inf := DynamicMultiNamespaceInformer{
 		gvr:                      gvr,
 		providerNamespace:        providerNamespace,
+		resyncPeriod:             resyncPeriod,
 		providerDynamicClient:    providerDynamicClient,
 		serviceNamespaceInformer: serviceNamespaceInformer,
 
@@ -184,7 +187,7 @@ func (inf *DynamicMultiNamespaceInformer) enqueueServiceNamespace(obj interface{
 
 	logger.V(1).Info("starting dynamic informer", "namespace", sns.Status.Namespace)
 	ctx, cancel := context.WithCancel(context.Background())
-	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(inf.providerDynamicClient, time.Minute*30, sns.Status.Namespace, nil)
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(inf.providerDynamicClient, inf.resyncPeriod, sns.Status.Namespace, nil)
 	gvrInf := factory.ForResource(inf.gvr)
 	gvrInf.Lister() // to wire the GVR up in the informer factory
 	inf.namespaceCancel[name] = cancel
@@ -0,0 +1,304 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multinsinformer
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+var errReadOnly = errors.New("the store of a multi-namespace informer is read-only")
+
+// NewNamespacesDynamicSharedInformerFactory returns a dynamic informer factory whose
+// informers span the given namespaces, with one list and watch per namespace. Without
+// namespaces, the informers span all namespaces. Cluster-scoped resources must not be
+// used with namespaces.
+func NewNamespacesDynamicSharedInformerFactory(client dynamicclient.Interface, resyncPeriod time.Duration, namespaces []string) dynamicinformer.DynamicSharedInformerFactory {
+	if len(namespaces) == 0 {
+		return dynamicinformer.NewDynamicSharedInformerFactory(client, resyncPeriod)
+	}
+
+	f := &namespacesFactory{
+		factories: map[string]dynamicinformer.DynamicSharedInformerFactory{},
+		informers: map[schema.GroupVersionResource]*namespacesInformer{},
+	}
+	for _, ns := range sets.NewString(namespaces...).List() {
+		f.factories[ns] = dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, resyncPeriod, ns, nil)
+	}
+	return f
+}
+
+type namespacesFactory struct {
+	factories map[string]dynamicinformer.DynamicSharedInformerFactory
+
+	lock      sync.Mutex
+	informers map[schema.GroupVersionResource]*namespacesInformer
+}
+
+func (f *namespacesFactory) Start(stopCh <-chan struct{}) {
+	for _, factory := range f.factories {
+		factory.Start(stopCh)
+	}
+}
+
+func (f *namespacesFactory) ForResource(gvr schema.GroupVersionResource) informers.GenericInformer {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if inf, found := f.informers[gvr]; found {
+		return inf
+	}
+	inf := &namespacesInformer{
+		gvr:       gvr,
+		informers: map[string]cache.SharedIndexInformer{},
+		indexers:  namespacesIndexer{},
+	}
+	for ns, factory := range f.factories {
+		nsInf := factory.ForResource(gvr).Informer()
+		inf.informers[ns] = nsInf
+		inf.indexers[ns] = nsInf.GetIndexer()
+	}
+	f.informers[gvr] = inf
+	return inf
+}
+
+func (f *namespacesFactory) WaitForCacheSync(stopCh <-chan struct{}) map[schema.GroupVersionResource]bool {
+	ret := map[schema.GroupVersionResource]bool{}
+	for _, factory := range f.factories {
+		for gvr, synced := range factory.WaitForCacheSync(stopCh) {
+			if prev, found := ret[gvr]; !found || prev {
+				ret[gvr] = synced
+			}
+		}
+	}
+	return ret
+}
+
+// namespacesInformer aggregates the informers of a resource in several namespaces.
+// It is read-only, i.e. events and the store come from the namespace informers.
+type namespacesInformer struct {
+	gvr       schema.GroupVersionResource
+	informers map[string]cache.SharedIndexInformer
+	indexers  namespacesIndexer
+}
+
+var _ informers.GenericInformer = &namespacesInformer{}
+var _ cache.SharedIndexInformer = &namespacesInformer{}
+
+func (i *namespacesInformer) Informer() cache.SharedIndexInformer {
+	return i
+}
+
+func (i *namespacesInformer) Lister() cache.GenericLister {
+	return cache.NewGenericLister(i.indexers, i.gvr.GroupResource())
+}
+
+func (i *namespacesInformer) AddEventHandler(handler cache.ResourceEventHandler) {
+	for _, inf := range i.informers {
+		inf.AddEventHandler(handler)
+	}
+}
+
+func (i *namespacesInformer) AddEventHandlerWithResyncPeriod(handler cache.ResourceEventHandler, resyncPeriod time.Duration) {
+	for _, inf := range i.informers {
+		inf.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+func (i *namespacesInformer) GetStore() cache.Store {
+	return i.indexers
+}
+
+func (i *namespacesInformer) GetIndexer() cache.Indexer {
+	return i.indexers
+}
+
+// GetController is not supported as there is no single controller.
+func (i *namespacesInformer) GetController() cache.Controller {
+	return nil
+}
+
+func (i *namespacesInformer) Run(stopCh <-chan struct{}) {
+	for _, inf := range i.informers {
+		go inf.Run(stopCh)
+	}
+	<-stopCh
+}
+
+func (i *namespacesInformer) HasSynced() bool {
+	for _, inf := range i.informers {
+		if !inf.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// LastSyncResourceVersion is empty as resource versions of different lists are not comparable.
+func (i *namespacesInformer) LastSyncResourceVersion() string {
+	return ""
+}
+
+func (i *namespacesInformer) SetWatchErrorHandler(handler cache.WatchErrorHandler) error {
+	for _, inf := range i.informers {
+		if err := inf.SetWatchErrorHandler(handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *namespacesInformer) SetTransform(handler cache.TransformFunc) error {
+	for _, inf := range i.informers {
+		if err := inf.SetTransform(handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *namespacesInformer) AddIndexers(indexers cache.Indexers) error {
+	return i.indexers.AddIndexers(indexers)
+}
+
+// namespacesIndexer is a read-only indexer over the indexers of several namespaces.
+type namespacesIndexer map[string]cache.Indexer
+
+var _ cache.Indexer = namespacesIndexer{}
+
+func (x namespacesIndexer) Add(obj interface{}) error           { return errReadOnly }
+func (x namespacesIndexer) Update(obj interface{}) error        { return errReadOnly }
+func (x namespacesIndexer) Delete(obj interface{}) error        { return errReadOnly }
+func (x namespacesIndexer) Replace([]interface{}, string) error { return errReadOnly }
+func (x namespacesIndexer) Resync() error                       { return nil }
+func (x namespacesIndexer) GetIndexers() cache.Indexers         { return x.any().GetIndexers() }
+func (x namespacesIndexer) ListIndexFuncValues(name string) []string {
+	values := sets.NewString()
+	for _, indexer := range x {
+		values.Insert(indexer.ListIndexFuncValues(name)...)
+	}
+	return values.List()
+}
+
+func (x namespacesIndexer) List() []interface{} {
+	var ret []interface{}
+	for _, indexer := range x {
+		ret = append(ret, indexer.List()...)
+	}
+	return ret
+}
+
+func (x namespacesIndexer) ListKeys() []string {
+	var ret []string
+	for _, indexer := range x {
+		ret = append(ret, indexer.ListKeys()...)
+	}
+	return ret
+}
+
+func (x namespacesIndexer) Get(obj interface{}) (interface{}, bool, error) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	return x.GetByKey(key)
+}
+
+func (x namespacesIndexer) GetByKey(key string) (interface{}, bool, error) {
+	ns, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	indexer, found := x[ns]
+	if !found {
+		return nil, false, nil
+	}
+	return indexer.GetByKey(key)
+}
+
+func (x namespacesIndexer) Index(name string, obj interface{}) ([]interface{}, error) {
+	var ret []interface{}
+	for _, indexer := range x {
+		objs, err := indexer.Index(name, obj)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, objs...)
+	}
+	return ret, nil
+}
+
+func (x namespacesIndexer) IndexKeys(name, value string) ([]string, error) {
+	if name == cache.NamespaceIndex {
+		if indexer, found := x[value]; found {
+			return indexer.IndexKeys(name, value)
+		}
+		return nil, nil
+	}
+	var ret []string
+	for _, indexer := range x {
+		keys, err := indexer.IndexKeys(name, value)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, keys...)
+	}
+	return ret, nil
+}
+
+func (x namespacesIndexer) ByIndex(name, value string) ([]interface{}, error) {
+	if name == cache.NamespaceIndex {
+		if indexer, found := x[value]; found {
+			return indexer.ByIndex(name, value)
+		}
+		return nil, nil
+	}
+	var ret []interface{}
+	for _, indexer := range x {
+		objs, err := indexer.ByIndex(name, value)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, objs...)
+	}
+	return ret, nil
+}
+
+func (x namespacesIndexer) AddIndexers(indexers cache.Indexers) error {
+	for _, indexer := range x {
+		if err := indexer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// any returns one of the indexers. They all have the same indexers.
+func (x namespacesIndexer) any() cache.Indexer {
+	for _, indexer := range x {
+		return indexer
+	}
+	return cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multinsinformer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestNamespacesDynamicSharedInformerFactory(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	widget := func(ns, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("example.com/v1")
+		obj.SetKind("Widget")
+		obj.SetNamespace(ns)
+		obj.SetName(name)
+		return obj
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "WidgetList"},
+		widget("a", "one"), widget("b", "two"), widget("c", "three"),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	f := NewNamespacesDynamicSharedInformerFactory(client, time.Minute, []string{"a", "b", "a"})
+	inf := f.ForResource(gvr)
+	f.Start(ctx.Done())
+	require.Equal(t, map[schema.GroupVersionResource]bool{gvr: true}, f.WaitForCacheSync(ctx.Done()))
+
+	require.ElementsMatch(t, []string{"a/one", "b/two"}, inf.Informer().GetIndexer().ListKeys())
+
+	_, found, err := inf.Informer().GetIndexer().GetByKey("c/three")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	objs, err := inf.Lister().ByNamespace("b").List(labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+
+	obj, err := inf.Lister().ByNamespace("a").Get("one")
+	require.NoError(t, err)
+	require.Equal(t, "one", obj.(*unstructured.Unstructured).GetName())
+
+	require.Error(t, inf.Informer().GetStore().Add(widget("a", "four")))
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providermessage
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+const (
+	// UpstreamRejectedReason is used when the service provider rejected creation
+	// or update of the upstream object.
+	UpstreamRejectedReason = "UpstreamRejected"
+
+	// ReadOnlyReason is used when the downstream object of a read-only APIServiceExport
+	// diverges from the upstream object.
+	ReadOnlyReason = "ReadOnly"
+
+	// PolicyViolationReason is used when an upsync policy of the konnector denies
+	// sending the downstream object to the service provider.
+	PolicyViolationReason = "PolicyViolation"
+
+	// UpstreamSchemaMismatchReason is used when the service provider rejected the
+	// upstream object because of fields unknown to its schema, with Strict field
+	// validation of the APIServiceBinding.
+	UpstreamSchemaMismatchReason = "UpstreamSchemaMismatch"
+
+	// DownstreamSchemaMismatchReason is used when the consumer cluster rejected the
+	// upstream status because of fields unknown to the schema of the bound CRD, with
+	// Strict field validation of the APIServiceBinding.
+	DownstreamSchemaMismatchReason = "DownstreamSchemaMismatch"
+)
+
+// IsOwnedBySpec returns true if the condition was set by the spec controller, and hence
+// must not be overridden by the status controller.
+func IsOwnedBySpec(cond map[string]interface{}) bool {
+	return cond["reason"] == UpstreamRejectedReason || cond["reason"] == ReadOnlyReason || cond["reason"] == PolicyViolationReason ||
+		cond["reason"] == UpstreamSchemaMismatchReason
+}
+
+// FromUpstream returns reason and message of a failure reported in the upstream
+// object's conditions: either a Ready condition with status False, or any
+// condition with severity Error.
+func FromUpstream(upstream *unstructured.Unstructured) (reason, message string, found bool) {
+	conds, _, _ := unstructured.NestedSlice(upstream.Object, "status", "conditions")
+
+	var fallback map[string]interface{}
+	for _, c := range conds {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cond["status"] != string(corev1.ConditionFalse) {
+			continue
+		}
+		if cond["type"] == "Ready" {
+			return reasonAndMessage(cond)
+		}
+		if cond["severity"] == "Error" && fallback == nil {
+			fallback = cond
+		}
+	}
+	if fallback != nil {
+		return reasonAndMessage(fallback)
+	}
+
+	return "", "", false
+}
+
+func reasonAndMessage(cond map[string]interface{}) (reason, message string, found bool) {
+	reason, _ = cond["reason"].(string)
+	message, _ = cond["message"].(string)
+	if t, ok := cond["type"].(string); ok && message != "" {
+		message = t + ": " + message
+	}
+	if reason == "" {
+		reason = "ProviderError"
+	}
+	return reason, message, true
+}
+
+// Get returns the provider message condition of the downstream object, or nil.
+func Get(obj *unstructured.Unstructured) map[string]interface{} {
+	conds, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conds {
+		if cond, ok := c.(map[string]interface{}); ok && cond["type"] == kubebindv1alpha1.ProviderMessageConditionType {
+			return cond
+		}
+	}
+	return nil
+}
+
+// Set puts the provider message condition with the given reason and message onto
+// the downstream object. If previous carries the same reason and message, it is
+// kept as is to not bump the transition time.
+func Set(obj *unstructured.Unstructured, previous map[string]interface{}, reason, message string) error {
+	cond := previous
+	if cond == nil || cond["reason"] != reason || cond["message"] != message {
+		cond = map[string]interface{}{
+			"type":               kubebindv1alpha1.ProviderMessageConditionType,
+			"status":             string(corev1.ConditionTrue),
+			"reason":             reason,
+			"message":            message,
+			"lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+
+	if err := Remove(obj); err != nil {
+		return err
+	}
+	conds, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	return unstructured.SetNestedSlice(obj.Object, append(conds, cond), "status", "conditions")
+}
+
+// Remove drops the provider message condition from the downstream object.
+func Remove(obj *unstructured.Unstructured) error {
+	conds, found, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if !found || Get(obj) == nil {
+		return nil
+	}
+
+	filtered := make([]interface{}, 0, len(conds))
+	for _, c := range conds {
+		if c, ok := c.(map[string]interface{}); ok && c["type"] == kubebindv1alpha1.ProviderMessageConditionType {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	if len(filtered) == 0 {
+		unstructured.RemoveNestedField(obj.Object, "status", "conditions")
+		return nil
+	}
+	return unstructured.SetNestedSlice(obj.Object, filtered, "status", "conditions")
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providermessage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFromUpstream(t *testing.T) {
+	tests := []struct {
+		name        string
+		conditions  []interface{}
+		wantFound   bool
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name: "no conditions",
+		},
+		{
+			name: "ready true",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+		{
+			name: "ready false",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Degraded", "status": "False", "severity": "Error", "reason": "Other", "message": "other"},
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "QuotaExceeded", "message": "quota exceeded"},
+			},
+			wantFound:   true,
+			wantReason:  "QuotaExceeded",
+			wantMessage: "Ready: quota exceeded",
+		},
+		{
+			name: "error severity",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+				map[string]interface{}{"type": "Provisioned", "status": "False", "severity": "Error", "message": "no capacity"},
+			},
+			wantFound:   true,
+			wantReason:  "ProviderError",
+			wantMessage: "Provisioned: no capacity",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tt.conditions != nil {
+				err := unstructured.SetNestedSlice(obj.Object, tt.conditions, "status", "conditions")
+				require.NoError(t, err)
+			}
+			reason, message, found := FromUpstream(obj)
+			require.Equal(t, tt.wantFound, found)
+			require.Equal(t, tt.wantReason, reason)
+			require.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
+
+func TestSetAndRemove(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	err := unstructured.SetNestedSlice(obj.Object, []interface{}{
+		map[string]interface{}{"type": "Ready", "status": "True"},
+	}, "status", "conditions")
+	require.NoError(t, err)
+
+	require.NoError(t, Set(obj, nil, UpstreamRejectedReason, "denied"))
+	cond := Get(obj)
+	require.NotNil(t, cond)
+	require.Equal(t, "denied", cond["message"])
+
+	require.NoError(t, Set(obj, cond, UpstreamRejectedReason, "denied"))
+	conds, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	require.Len(t, conds, 2)
+
+	require.NoError(t, Remove(obj))
+	require.Nil(t, Get(obj))
+	conds, _, _ = unstructured.NestedSlice(obj.Object, "status", "conditions")
+	require.Len(t, conds, 1)
+}
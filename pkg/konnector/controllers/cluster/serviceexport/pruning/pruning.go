@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pruning finds the fields an API server dropped from a synced object
+// because they are unknown to its schema.
+package pruning
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxFields bounds the number of fields recorded in an annotation.
+const maxFields = 20
+
+// Fields returns the paths of the fields of sent that are missing in stored, e.g.
+// "spec.template.replicas", starting with prefix. Fields of a different type or in
+// lists of a different length in stored are not pruned but changed, and are skipped.
+func Fields(prefix string, sent, stored interface{}) []string {
+	var ret []string
+	fields(prefix, sent, stored, &ret)
+	sort.Strings(ret)
+	return ret
+}
+
+func fields(path string, sent, stored interface{}, ret *[]string) {
+	switch s := sent.(type) {
+	case map[string]interface{}:
+		st, ok := stored.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for k, v := range s {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			sv, found := st[k]
+			if !found {
+				if v != nil { // nulls are dropped anyway
+					*ret = append(*ret, p)
+				}
+				continue
+			}
+			fields(p, v, sv, ret)
+		}
+	case []interface{}:
+		st, ok := stored.([]interface{})
+		if !ok || len(st) != len(s) {
+			return
+		}
+		for i := range s {
+			fields(fmt.Sprintf("%s[%d]", path, i), s[i], st[i], ret)
+		}
+	}
+}
+
+// Annotate sets the annotation key on obj to the given fields, or removes it if
+// there are none. It returns whether obj changed.
+func Annotate(obj *unstructured.Unstructured, key string, fields []string) bool {
+	annotations := obj.GetAnnotations()
+	if len(fields) == 0 {
+		if _, found := annotations[key]; !found {
+			return false
+		}
+		delete(annotations, key)
+		obj.SetAnnotations(annotations)
+		return true
+	}
+
+	if len(fields) > maxFields {
+		fields = append(fields[:maxFields:maxFields], "...")
+	}
+	value := strings.Join(fields, ",")
+	if annotations[key] == value {
+		return false
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+	return true
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pruning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		sent   interface{}
+		stored interface{}
+		want   []string
+	}{
+		{
+			name:   "nothing pruned",
+			sent:   map[string]interface{}{"replicas": int64(1)},
+			stored: map[string]interface{}{"replicas": int64(1), "defaulted": "x"},
+		},
+		{
+			name: "nested and list fields",
+			sent: map[string]interface{}{
+				"replicas": int64(1),
+				"tier":     "gold",
+				"template": map[string]interface{}{"size": "L", "zone": "a"},
+				"ports":    []interface{}{map[string]interface{}{"port": int64(80), "name": "http"}},
+			},
+			stored: map[string]interface{}{
+				"replicas": int64(1),
+				"template": map[string]interface{}{"size": "L"},
+				"ports":    []interface{}{map[string]interface{}{"port": int64(80)}},
+			},
+			want: []string{"spec.ports[0].name", "spec.template.zone", "spec.tier"},
+		},
+		{
+			name:   "nulls and changed types skipped",
+			sent:   map[string]interface{}{"a": nil, "b": map[string]interface{}{"c": "d"}},
+			stored: map[string]interface{}{"b": "d"},
+		},
+		{
+			name:   "null stored skipped",
+			sent:   map[string]interface{}{"a": "b"},
+			stored: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Fields("spec", tt.sent, tt.stored))
+		})
+	}
+}
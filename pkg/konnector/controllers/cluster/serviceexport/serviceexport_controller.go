@@ -21,24 +21,33 @@ import (
 	"fmt"
 	"time"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubernetesclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
 	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/committer"
 	"github.com/kube-bind/kube-bind/pkg/indexers"
+	"github.com/kube-bind/kube-bind/pkg/konnector/backpressure"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/policy"
+	"github.com/kube-bind/kube-bind/pkg/konnector/slo"
 )
 
 const (
@@ -54,8 +63,20 @@ func NewController(
 	serviceNamespaceInformer bindinformers.APIServiceNamespaceInformer,
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	crdInformer dynamic.Informer[apiextensionslisters.CustomResourceDefinitionLister],
+	syncedMaxStaleness time.Duration,
+	hibernateIdleAfter time.Duration,
+	canaryInterval time.Duration,
+	snapshotDir string,
+	syncTuning tuning.Sync,
+	resync tuning.Resync,
+	upsyncPolicy *policy.Evaluator,
+	allowedRegions []string,
+	watchNamespaces []string,
+	sloTracker *slo.Tracker,
+	backpressureTracker *backpressure.Tracker,
+	rateLimiter tuning.RateLimiter,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -69,6 +90,14 @@ func NewController(
 	if err != nil {
 		return nil, err
 	}
+	providerKubeClient, err := kubernetesclient.NewForConfig(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+	consumerKubeClient, err := kubernetesclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	dynamicServiceNamespaceInformer := dynamic.NewDynamicInformer[bindlisters.APIServiceNamespaceLister](serviceNamespaceInformer)
 	c := &controller{
@@ -89,14 +118,49 @@ func NewController(
 			serviceNamespaceInformer: dynamicServiceNamespaceInformer,
 			consumerConfig:           consumerConfig,
 			providerConfig:           providerConfig,
-
-			syncContext: map[string]syncContext{},
+			syncedMaxStaleness:       syncedMaxStaleness,
+			hibernateIdleAfter:       hibernateIdleAfter,
+			canaryInterval:           canaryInterval,
+			snapshotDir:              snapshotDir,
+			syncTuning:               syncTuning,
+			resync:                   resync,
+			rateLimiter:              rateLimiter,
+			upsyncPolicy:             upsyncPolicy,
+			allowedRegions:           allowedRegions,
+			watchNamespaces:          watchNamespaces,
+			sloTracker:               sloTracker,
+			backpressure:             backpressureTracker,
+
+			syncContext:   map[string]syncContext{},
+			canaryResults: map[string]canaryResult{},
 
 			getCRD: func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 				return crdInformer.Lister().Get(name)
 			},
-			getServiceBinding: func(name string) (*kubebindv1alpha1.APIServiceBinding, error) {
-				return serviceBindingInformer.Lister().Get(name)
+			getServiceBinding: func(exportName string) (*kubebindv1alpha1.APIServiceBinding, error) {
+				bindings, err := serviceBindingInformer.Informer().GetIndexer().ByIndex(indexers.ByServiceBindingExport, indexers.ByServiceBindingExportKey(consumerSecretRefKey, exportName))
+				if err != nil {
+					return nil, err
+				}
+				if len(bindings) == 0 {
+					return nil, errors.NewNotFound(kubebindv1alpha1.Resource("apiservicebindings"), exportName)
+				}
+				return bindings[0].(*kubebindv1alpha1.APIServiceBinding), nil
+			},
+			createServiceAccountToken: func(ctx context.Context, ns, name string, tr *authenticationv1.TokenRequest) (*authenticationv1.TokenRequest, error) {
+				return consumerKubeClient.CoreV1().ServiceAccounts(ns).CreateToken(ctx, name, tr, metav1.CreateOptions{})
+			},
+			getProviderSecret: func(ctx context.Context, ns, name string) (*corev1.Secret, error) {
+				return providerKubeClient.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+			},
+			createProviderSecret: func(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+				return providerKubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+			},
+			updateProviderSecret: func(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+				return providerKubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+			},
+			deleteProviderSecret: func(ctx context.Context, ns, name string) error {
+				return providerKubeClient.CoreV1().Secrets(ns).Delete(ctx, name, metav1.DeleteOptions{})
 			},
 		},
 
@@ -107,6 +171,13 @@ func NewController(
 		),
 	}
 
+	c.reconciler.requeue = func(name string) {
+		c.queue.Add(providerNamespace + "/" + name)
+	}
+	c.reconciler.requeueAfter = func(name string, after time.Duration) {
+		c.queue.AddAfter(providerNamespace+"/"+name, after)
+	}
+
 	indexers.AddIfNotPresentOrDie(serviceNamespaceInformer.Informer().GetIndexer(), cache.Indexers{
 		indexers.ServiceNamespaceByNamespace: indexers.IndexServiceNamespaceByNamespace,
 	})
@@ -165,7 +236,7 @@ func (c *controller) enqueueServiceBinding(logger klog.Logger, obj interface{})
 		return
 	}
 
-	key := c.providerNamespace + "/" + binding.Name
+	key := c.providerNamespace + "/" + kubebindhelpers.ExportName(binding)
 	logger.V(2).Info("queueing APIServiceExport", "key", key, "reason", "APIServiceBinding", "APIServiceBindingKey", binding.Name)
 	c.queue.Add(key)
 }
@@ -180,6 +251,29 @@ func (c *controller) enqueueCRD(logger klog.Logger, obj interface{}) {
 	key := c.providerNamespace + "/" + crdKey
 	logger.V(2).Info("queueing APIServiceExport", "key", key, "reason", "APIServiceExport", "APIServiceExportKey", crdKey)
 	c.queue.Add(key)
+
+	// CRDs of bindings with group alias are named differently than their export
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	bindings, err := indexers.IndexCRDByServiceBinding(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, name := range bindings {
+		binding, err := c.serviceBindingInformer.Lister().Get(name)
+		if err != nil {
+			continue // gone, or the CRD is enqueued again when it is created
+		}
+		exportName := kubebindhelpers.ExportName(binding)
+		if exportName == crdKey {
+			continue
+		}
+		key := c.providerNamespace + "/" + exportName
+		logger.V(2).Info("queueing APIServiceExport", "key", key, "reason", "CustomResourceDefinition", "CustomResourceDefinitionKey", crdKey)
+		c.queue.Add(key)
+	}
 }
 
 // Start starts the controller, which stops when ctx.Done() is closed.
@@ -18,9 +18,13 @@ package serviceexport
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -29,17 +33,31 @@ import (
 	"k8s.io/apimachinery/pkg/util/runtime"
 	dynamicclient "k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubernetesclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
 	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/konnector/backpressure"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/eventrelay"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/snapshot"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/spec"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/status"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncevents"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/servicebinding"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/policy"
+	"github.com/kube-bind/kube-bind/pkg/konnector/slo"
+)
+
+const (
+	minResyncPeriod = 10 * time.Second
 )
 
 type reconciler struct {
@@ -50,18 +68,144 @@ type reconciler struct {
 
 	consumerConfig, providerConfig *rest.Config
 
-	lock        sync.Mutex
-	syncContext map[string]syncContext // by CRD name
+	// syncedMaxStaleness enables the synced condition on downstream objects if non-zero.
+	syncedMaxStaleness time.Duration
+	// hibernateIdleAfter enables hibernation of idle bindings if non-zero.
+	hibernateIdleAfter time.Duration
+	// canaryInterval enables the canary probe of exports with a canary object if non-zero.
+	canaryInterval time.Duration
+	// snapshotDir is where sync snapshots are persisted. Empty disables them.
+	snapshotDir string
+	// syncTuning configures concurrency and rate limits of the spec and status controllers.
+	syncTuning tuning.Sync
+	// rateLimiter configures the retries of the workqueues of the sync controllers.
+	rateLimiter tuning.RateLimiter
+	// resync are the default resync periods of the informers, unless overridden by the binding.
+	resync tuning.Resync
+	// upsyncPolicy is evaluated before objects are sent to the service provider. Nil allows all.
+	upsyncPolicy *policy.Evaluator
+	// allowedRegions restricts the regions of APIServiceExports. Empty allows all.
+	allowedRegions []string
+	// watchNamespaces restricts the consumer objects of namespaced resources that are
+	// synced to these namespaces. Empty means all namespaces.
+	watchNamespaces []string
+	// sloTracker receives the canary probes for the SLO of the bindings.
+	sloTracker *slo.Tracker
+	// backpressure has the upsync throttles of the exports.
+	backpressure *backpressure.Tracker
+
+	lock          sync.Mutex
+	syncContext   map[string]syncContext  // by CRD name
+	canaryResults map[string]canaryResult // by CRD name
 
 	getCRD            func(name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	getServiceBinding func(name string) (*kubebindv1alpha1.APIServiceBinding, error)
+
+	createServiceAccountToken func(ctx context.Context, ns, name string, tr *authenticationv1.TokenRequest) (*authenticationv1.TokenRequest, error)
+	getProviderSecret         func(ctx context.Context, ns, name string) (*corev1.Secret, error)
+	createProviderSecret      func(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error)
+	updateProviderSecret      func(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error)
+	deleteProviderSecret      func(ctx context.Context, ns, name string) error
+
+	requeue      func(name string)
+	requeueAfter func(name string, after time.Duration)
 }
 
 type syncContext struct {
-	generation int64
+	generation    int64
+	adoption      kubebindv1alpha1.AdoptionPolicy
+	validation    kubebindv1alpha1.FieldValidationPolicy
+	resync        string
+	syncPolicyKey string
+	claimsKey     string
+	relayKey      string
+	canary        string
+	groupAlias    string
+	syncVersion   string
+
+	// claims are the informers of the accepted cluster-scoped claims.
+	claims []claimInformer
+
+	// paused is true if the syncers have been stopped by the kube-bind.io/paused
+	// annotation of the binding.
+	paused bool
+
+	// hibernated is true if the syncers have been stopped because the binding was idle.
+	// cancel stops the wake-up trigger then.
+	hibernated bool
+	parent     context.Context
 	cancel     func()
 }
 
+// eventRelayKey identifies the accepted event relay, to restart the sync when it changes.
+func eventRelayKey(relay *kubebindv1alpha1.EventRelay) string {
+	if relay == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", *relay)
+}
+
+// syncPolicy is the effective sync configuration of a binding.
+type syncPolicy struct {
+	resync          tuning.Resync
+	maxStaleness    time.Duration
+	tuning          tuning.Sync
+	watchNamespaces []string
+}
+
+// key identifies the sync policy, to restart the sync when it changes.
+func (p syncPolicy) key() string {
+	return fmt.Sprintf("%+v", p)
+}
+
+// syncPolicy returns the sync configuration of the binding, i.e. the konnector
+// defaults overridden by spec.sync of the binding. A resync period of the binding
+// applies to the consumer and the provider informers.
+func (r *reconciler) syncPolicy(binding *kubebindv1alpha1.APIServiceBinding) syncPolicy {
+	p := syncPolicy{
+		resync:          r.resync,
+		maxStaleness:    r.syncedMaxStaleness,
+		tuning:          r.syncTuning,
+		watchNamespaces: r.watchNamespaces,
+	}
+	sync := binding.Spec.Sync
+	if sync == nil {
+		return p
+	}
+	if sync.ResyncPeriod != nil && sync.ResyncPeriod.Duration > 0 {
+		period := sync.ResyncPeriod.Duration
+		if period < minResyncPeriod {
+			period = minResyncPeriod
+		}
+		p.resync = tuning.Resync{Period: period, ProviderPeriod: period}
+	}
+	if sync.MaxStaleness != nil && sync.MaxStaleness.Duration > 0 {
+		p.maxStaleness = sync.MaxStaleness.Duration
+	}
+	p.tuning.Spec = overrideSyncTuning(p.tuning.Spec, sync.Spec)
+	p.tuning.Status = overrideSyncTuning(p.tuning.Status, sync.Status)
+	if len(sync.WatchNamespaces) > 0 {
+		p.watchNamespaces = sync.WatchNamespaces
+	}
+	return p
+}
+
+func overrideSyncTuning(c tuning.Controller, t *kubebindv1alpha1.SyncTuning) tuning.Controller {
+	if t == nil {
+		return c
+	}
+	if t.Workers != nil {
+		c.Workers = int(*t.Workers)
+	}
+	if t.QPS != nil {
+		c.QPS = float32(*t.QPS)
+	}
+	if t.Burst != nil {
+		c.Burst = int(*t.Burst)
+	}
+	return c
+}
+
 func (r *reconciler) reconcile(ctx context.Context, name string, export *kubebindv1alpha1.APIServiceExport) error {
 	errs := []error{}
 
@@ -73,14 +217,44 @@ func (r *reconciler) reconcile(ctx context.Context, name string, export *kubebin
 		if err := r.ensureServiceBindingConditionCopied(ctx, export); err != nil {
 			errs = append(errs, err)
 		}
+		r.ensureCanaryCondition(ctx, export)
+		r.ensureBackpressure(ctx, export)
 		if err := r.ensureCRDConditionsCopied(ctx, export); err != nil {
 			errs = append(errs, err)
 		}
+		if err := r.ensureClaimedObjects(ctx, export); err != nil {
+			errs = append(errs, err)
+		}
+		if err := r.ensureServiceAccountTokens(ctx, export); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	return utilerrors.NewAggregate(errs)
 }
 
+// syncVersion returns the version to sync, the first served version of the
+// schema revision the binding is pinned to. If the export does not publish that
+// revision anymore, the consumer CRD is kept as it is, hence its first served
+// version is synced.
+func syncVersion(export *kubebindv1alpha1.APIServiceExport, binding *kubebindv1alpha1.APIServiceBinding, crd *apiextensionsv1.CustomResourceDefinition) string {
+	pinned, found := kubebindhelpers.ExportAtSchemaRevision(export, binding.Spec.SchemaRevision)
+	if !found {
+		for _, v := range crd.Spec.Versions {
+			if v.Served {
+				return v.Name
+			}
+		}
+		return ""
+	}
+	for _, v := range pinned.Spec.Versions {
+		if v.Served {
+			return v.Name
+		}
+	}
+	return ""
+}
+
 func (r *reconciler) ensureControllers(ctx context.Context, name string, export *kubebindv1alpha1.APIServiceExport) error {
 	logger := klog.FromContext(ctx)
 
@@ -93,11 +267,25 @@ func (r *reconciler) ensureControllers(ctx context.Context, name string, export
 			c.cancel()
 			delete(r.syncContext, name)
 		}
+		delete(r.canaryResults, name)
+		r.sloTracker.Forget(name)
+		r.backpressure.Forget(name)
 		return nil
 	}
 
 	var errs []error
-	crd, err := r.getCRD(export.Name)
+
+	// any binding that references this resource?
+	binding, err := r.getServiceBinding(export.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	crdName := export.Name
+	if binding != nil {
+		crdName = kubebindhelpers.BoundCRDName(binding)
+	}
+	crd, err := r.getCRD(crdName)
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	} else if errors.IsNotFound(err) {
@@ -113,11 +301,19 @@ func (r *reconciler) ensureControllers(ctx context.Context, name string, export
 		return nil
 	}
 
-	// any binding that references this resource?
-	binding, err := r.getServiceBinding(export.Name)
-	if err != nil && !errors.IsNotFound(err) {
-		return err
+	if !kubebindhelpers.RegionAllowed(export.Labels[kubebindv1alpha1.RegionLabelKey], r.allowedRegions) {
+		// stop it, even if the CRD exists from before the region changed
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if c, found := r.syncContext[export.Name]; found {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "RegionNotAllowed", "region", export.Labels[kubebindv1alpha1.RegionLabelKey])
+			c.cancel()
+			delete(r.syncContext, export.Name)
+		}
+
+		return nil
 	}
+
 	if binding == nil {
 		// stop it
 		r.lock.Lock()
@@ -133,38 +329,89 @@ func (r *reconciler) ensureControllers(ctx context.Context, name string, export
 
 	r.lock.Lock()
 	c, found := r.syncContext[export.Name]
+	if servicebinding.Paused(binding) {
+		defer r.lock.Unlock()
+		if found && c.paused {
+			return nil
+		}
+		if found {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "Paused")
+			c.cancel()
+		}
+		// keep the rest of the context, e.g. to not forget the claimed objects
+		c.paused = true
+		c.hibernated = false
+		c.cancel = func() {}
+		r.syncContext[export.Name] = c
+		return nil
+	}
+	if found && c.paused {
+		logger.V(1).Info("Resuming APIServiceExport sync")
+		delete(r.syncContext, export.Name)
+		found = false
+	}
 	if found {
-		if c.generation == export.Generation {
+		resync := export.Annotations[kubebindv1alpha1.ResyncAnnotationKey]
+		syncPolicyKey := r.syncPolicy(binding).key()
+		claimsKey := fmt.Sprintf("%v", kubebindhelpers.AcceptedClaims(export, binding))
+		relayKey := eventRelayKey(kubebindhelpers.AcceptedEventRelay(export, binding))
+		canary := r.canaryTemplate(export)
+		version := syncVersion(export, binding, crd)
+		if c.generation == export.Generation && c.adoption == binding.Spec.Adoption && c.validation == binding.Spec.FieldValidation && c.resync == resync &&
+			c.syncPolicyKey == syncPolicyKey && c.claimsKey == claimsKey && c.relayKey == relayKey &&
+			c.canary == canary && c.groupAlias == binding.Spec.GroupAlias && c.syncVersion == version {
 			r.lock.Unlock()
 			return nil // all as expected
 		}
 
 		// technically, we could be less aggressive here if nothing big changed in the resource, e.g. just schemas. But ¯\_(ツ)_/¯
 
-		logger.V(1).Info("Stopping APIServiceExport sync", "reason", "GenerationChanged", "generation", export.Generation)
+		if c.resync != resync {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "ResyncRequested", "resync", resync)
+		} else if c.syncPolicyKey != syncPolicyKey {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "SyncPolicyChanged", "syncPolicy", syncPolicyKey)
+		} else if c.claimsKey != claimsKey {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "AcceptedClaimsChanged", "claims", claimsKey)
+		} else if c.relayKey != relayKey {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "AcceptedEventRelayChanged", "eventRelay", relayKey)
+		} else if c.canary != canary {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "CanaryChanged")
+		} else if c.validation != binding.Spec.FieldValidation {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "FieldValidationChanged", "fieldValidation", binding.Spec.FieldValidation)
+		} else if c.groupAlias != binding.Spec.GroupAlias {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "GroupAliasChanged", "groupAlias", binding.Spec.GroupAlias)
+		} else if c.syncVersion != version {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "SyncVersionChanged", "version", version, "schemaRevision", binding.Spec.SchemaRevision)
+		} else {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "GenerationChanged", "generation", export.Generation, "adoption", binding.Spec.Adoption)
+		}
 		c.cancel()
 		delete(r.syncContext, export.Name)
 	}
 	r.lock.Unlock()
 
 	// start a new syncer
+	sp := r.syncPolicy(binding)
+	resyncPeriods, maxStaleness := sp.resync, sp.maxStaleness
+	acceptedClaims := kubebindhelpers.AcceptedClaims(export, binding)
+	acceptedRelay := kubebindhelpers.AcceptedEventRelay(export, binding)
 
-	var syncVersion string
-	for _, v := range export.Spec.Versions {
-		if v.Served {
-			syncVersion = v.Name
-			break
-		}
-	}
-	gvr := runtimeschema.GroupVersionResource{Group: export.Spec.Group, Version: syncVersion, Resource: export.Spec.Names.Plural}
+	version := syncVersion(export, binding, crd)
+	gvr := runtimeschema.GroupVersionResource{Group: export.Spec.Group, Version: version, Resource: export.Spec.Names.Plural}
+	consumerGVR := gvr
+	consumerGVR.Group = kubebindhelpers.BoundGroup(binding, gvr.Group)
 
 	dynamicConsumerClient := dynamicclient.NewForConfigOrDie(r.consumerConfig)
 	dynamicProviderClient := dynamicclient.NewForConfigOrDie(r.providerConfig)
-	consumerInf := dynamicinformer.NewDynamicSharedInformerFactory(dynamicConsumerClient, time.Minute*30)
+	var consumerNamespaces []string
+	if crd.Spec.Scope == apiextensionsv1.NamespaceScoped {
+		consumerNamespaces = sp.watchNamespaces
+	}
+	consumerInf := multinsinformer.NewNamespacesDynamicSharedInformerFactory(dynamicConsumerClient, resyncPeriods.Period, consumerNamespaces)
 
 	var providerInf multinsinformer.GetterInformer
 	if crd.Spec.Scope == apiextensionsv1.ClusterScoped || export.Spec.InformerScope == kubebindv1alpha1.ClusterScope {
-		factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicProviderClient, time.Minute*30)
+		factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicProviderClient, resyncPeriods.ProviderPeriod)
 		factory.ForResource(gvr).Lister() // wire the GVR up in the informer factory
 		providerInf = multinsinformer.GetterInformerWrapper{
 			GVR:      gvr,
@@ -175,6 +422,7 @@ func (r *reconciler) ensureControllers(ctx context.Context, name string, export
 			gvr,
 			r.providerNamespace,
 			r.providerConfig,
+			resyncPeriods.ProviderPeriod,
 			r.serviceNamespaceInformer,
 		)
 		if err != nil {
@@ -182,37 +430,103 @@ func (r *reconciler) ensureControllers(ctx context.Context, name string, export
 		}
 	}
 
+	events := syncevents.NewRecorder(kubernetesclient.NewForConfigOrDie(r.consumerConfig).CoreV1(), binding)
+
 	specCtrl, err := spec.NewController(
+		consumerGVR,
 		gvr,
 		r.providerNamespace,
+		binding.Name,
+		export.UID,
+		kubebindhelpers.HasCapability(export, kubebindv1alpha1.APIServiceExportCapabilityReadOnly),
+		export.Spec.Isolation,
+		binding.Spec.Adoption,
+		binding.Spec.FieldValidation,
+		binding.CreationTimestamp.Time,
 		r.consumerConfig,
 		r.providerConfig,
-		consumerInf.ForResource(gvr),
+		consumerInf.ForResource(consumerGVR),
 		providerInf,
 		r.serviceNamespaceInformer,
+		r.openSnapshot(export.Name, "spec", fmt.Sprintf("%s|%d|%s|%s|%s", consumerGVR, export.Generation, binding.Spec.Adoption, export.Annotations[kubebindv1alpha1.ResyncAnnotationKey], export.UID)),
+		sp.tuning.Spec,
+		r.upsyncPolicy,
+		r.backpressure.Throttle(export.Name),
+		events,
+		r.rateLimiter,
 	)
 	if err != nil {
 		runtime.HandleError(err)
 		return nil // nothing we can do here
 	}
 	statusCtrl, err := status.NewController(
+		consumerGVR,
 		gvr,
 		r.providerNamespace,
+		export.Spec.Isolation,
+		export.Name,
+		export.Spec.StatusSync,
+		maxStaleness,
+		binding.Spec.FieldValidation,
 		r.consumerConfig,
 		r.providerConfig,
-		consumerInf.ForResource(gvr),
+		consumerInf.ForResource(consumerGVR),
 		providerInf,
 		r.serviceNamespaceInformer,
+		r.openSnapshot(export.Name, "status", fmt.Sprintf("%s|%d|%s|%s", consumerGVR, export.Generation, maxStaleness, export.Annotations[kubebindv1alpha1.ResyncAnnotationKey])),
+		sp.tuning.Status,
+		events,
+		r.rateLimiter,
 	)
 	if err != nil {
 		runtime.HandleError(err)
 		return nil // nothing we can do here
 	}
 
+	var relayCtrl interface {
+		Start(ctx context.Context, numThreads int)
+	}
+	if acceptedRelay != nil && crd.Spec.Scope == apiextensionsv1.NamespaceScoped {
+		relayCtrl, err = eventrelay.NewController(
+			consumerGVR,
+			gvr,
+			export.Spec.Names.Kind,
+			r.providerNamespace,
+			export.Spec.Isolation,
+			*acceptedRelay,
+			consumerNamespaces,
+			resyncPeriods,
+			r.consumerConfig,
+			r.providerConfig,
+			consumerInf.ForResource(consumerGVR),
+			providerInf,
+			r.serviceNamespaceInformer,
+			r.rateLimiter,
+		)
+		if err != nil {
+			runtime.HandleError(err)
+			return nil // nothing we can do here
+		}
+	}
+
+	parent := ctx
 	ctx, cancel := context.WithCancel(ctx)
 
+	var activity *activityTracker
+	if r.hibernateIdleAfter > 0 {
+		activity = newActivityTracker()
+		consumerInf.ForResource(consumerGVR).Informer().AddEventHandler(activity)
+		providerInf.AddEventHandler(activity)
+	}
+
 	consumerInf.Start(ctx.Done())
 	providerInf.Start(ctx)
+	go events.Start(ctx)
+
+	claims, err := r.startClaimInformers(ctx, export.Name, acceptedClaims, resyncPeriods.Period)
+	if err != nil {
+		runtime.HandleError(err)
+	}
 
 	go func() {
 		// to not block the main thread
@@ -222,8 +536,19 @@ func (r *reconciler) ensureControllers(ctx context.Context, name string, export
 		providerSynced := providerInf.WaitForCacheSync(ctx.Done())
 		logger.V(2).Info("Synced informers", "provider", providerSynced)
 
-		go specCtrl.Start(ctx, 1)
-		go statusCtrl.Start(ctx, 1)
+		go specCtrl.Start(ctx, sp.tuning.Spec.NumWorkers())
+		go statusCtrl.Start(ctx, sp.tuning.Status.NumWorkers())
+		if relayCtrl != nil {
+			go relayCtrl.Start(ctx, 1)
+		}
+
+		if canary := r.canaryTemplate(export); canary != "" {
+			go r.runCanary(ctx, export.Name, consumerGVR, gvr, export.Spec.Names.Kind, crd.Spec.Scope == apiextensionsv1.NamespaceScoped, export.Spec.Isolation, canary)
+		}
+
+		if activity != nil {
+			go r.monitorIdle(ctx, export.Name, consumerGVR, consumerNamespaces, consumerInf.ForResource(consumerGVR).Informer(), activity)
+		}
 	}()
 
 	r.lock.Lock()
@@ -232,13 +557,58 @@ func (r *reconciler) ensureControllers(ctx context.Context, name string, export
 		c.cancel()
 	}
 	r.syncContext[export.Name] = syncContext{
-		generation: export.Generation,
-		cancel:     cancel,
+		generation:    export.Generation,
+		adoption:      binding.Spec.Adoption,
+		validation:    binding.Spec.FieldValidation,
+		resync:        export.Annotations[kubebindv1alpha1.ResyncAnnotationKey],
+		syncPolicyKey: sp.key(),
+		claimsKey:     fmt.Sprintf("%v", acceptedClaims),
+		relayKey:      eventRelayKey(acceptedRelay),
+		canary:        r.canaryTemplate(export),
+		groupAlias:    binding.Spec.GroupAlias,
+		syncVersion:   version,
+		claims:        claims,
+		parent:        parent,
+		cancel:        cancel,
 	}
 
 	return utilerrors.NewAggregate(errs)
 }
 
+// ensureBackpressure paces the upsync by the interval the service provider asks for
+// in the backpressure annotation.
+func (r *reconciler) ensureBackpressure(ctx context.Context, export *kubebindv1alpha1.APIServiceExport) {
+	if r.backpressure == nil {
+		return
+	}
+
+	var interval time.Duration
+	if value, found := export.Annotations[kubebindv1alpha1.BackpressureAnnotationKey]; found {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			klog.FromContext(ctx).Info("ignoring invalid backpressure annotation", "value", value)
+		} else {
+			interval = d
+		}
+	}
+	r.backpressure.Throttle(export.Name).SetInterval(interval)
+}
+
+// openSnapshot opens the sync snapshot of the given APIServiceExport and controller.
+// It returns nil if snapshots are disabled or cannot be read.
+func (r *reconciler) openSnapshot(name, controller, fingerprint string) *snapshot.Snapshot {
+	if r.snapshotDir == "" {
+		return nil
+	}
+	path := filepath.Join(r.snapshotDir, strings.ReplaceAll(r.consumerSecretRefKey, "/", "_"), name+"."+controller+".json.gz")
+	snap, err := snapshot.Open(path, fingerprint)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("failed to open sync snapshot %s: %w", path, err))
+		return nil
+	}
+	return snap
+}
+
 func (r *reconciler) ensureServiceBindingConditionCopied(ctx context.Context, export *kubebindv1alpha1.APIServiceExport) error {
 	binding, err := r.getServiceBinding(export.Name)
 	if err != nil && !errors.IsNotFound(err) {
@@ -265,6 +635,10 @@ func (r *reconciler) ensureServiceBindingConditionCopied(ctx context.Context, ex
 
 	conditions.MarkTrue(export, kubebindv1alpha1.APIServiceExportConditionConnected)
 
+	// tell the service provider which schema revision is bound, e.g. for rollouts
+	export.Status.SchemaRevision = binding.Status.SchemaRevision
+	export.Status.PinnedSchemaRevision = binding.Spec.SchemaRevision
+
 	if inSync := conditions.Get(binding, kubebindv1alpha1.APIServiceBindingConditionSchemaInSync); inSync != nil {
 		inSync := inSync.DeepCopy()
 		inSync.Type = kubebindv1alpha1.APIServiceExportConditionConsumerInSync
@@ -284,7 +658,16 @@ func (r *reconciler) ensureServiceBindingConditionCopied(ctx context.Context, ex
 }
 
 func (r *reconciler) ensureCRDConditionsCopied(ctx context.Context, export *kubebindv1alpha1.APIServiceExport) error {
-	crd, err := r.getCRD(export.Name)
+	binding, err := r.getServiceBinding(export.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	crdName := export.Name
+	if binding != nil {
+		crdName = kubebindhelpers.BoundCRDName(binding)
+	}
+
+	crd, err := r.getCRD(crdName)
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	} else if errors.IsNotFound(err) {
@@ -19,16 +19,21 @@ package serviceexport
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/require"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
 )
 
 func TestEnsureCRDConditionsCopied(t *testing.T) {
@@ -62,6 +67,9 @@ func TestEnsureCRDConditionsCopied(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &reconciler{
 				getCRD: tt.getCRD,
+				getServiceBinding: func(name string) (*kubebindv1alpha1.APIServiceBinding, error) {
+					return nil, errors.NewNotFound(kubebindv1alpha1.Resource("apiservicebindings"), name)
+				},
 			}
 			export := tt.export.DeepCopy()
 			if err := r.ensureCRDConditionsCopied(context.Background(), export); (err != nil) != tt.wantErr {
@@ -106,3 +114,218 @@ func newExport(name string, conditions []conditionsapi.Condition) *kubebindv1alp
 		},
 	}
 }
+
+func TestSyncPolicy(t *testing.T) {
+	defaults := syncPolicy{
+		resync:          tuning.Resync{Period: 30 * time.Minute, ProviderPeriod: time.Hour},
+		maxStaleness:    time.Minute,
+		tuning:          tuning.Sync{Spec: tuning.Controller{Workers: 1, QPS: 10, Burst: 20}, Status: tuning.Controller{Workers: 2}},
+		watchNamespaces: []string{"default"},
+	}
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	tests := []struct {
+		name string
+		sync *kubebindv1alpha1.SyncPolicy
+		want func(p *syncPolicy)
+	}{
+		{
+			name: "defaults",
+			want: func(p *syncPolicy) {},
+		},
+		{
+			name: "overrides",
+			sync: &kubebindv1alpha1.SyncPolicy{
+				ResyncPeriod: &metav1.Duration{Duration: 5 * time.Minute},
+				MaxStaleness: &metav1.Duration{Duration: 15 * time.Second},
+			},
+			want: func(p *syncPolicy) {
+				p.resync = tuning.Resync{Period: 5 * time.Minute, ProviderPeriod: 5 * time.Minute}
+				p.maxStaleness = 15 * time.Second
+			},
+		},
+		{
+			name: "resync period too short",
+			sync: &kubebindv1alpha1.SyncPolicy{
+				ResyncPeriod: &metav1.Duration{Duration: time.Second},
+			},
+			want: func(p *syncPolicy) {
+				p.resync = tuning.Resync{Period: minResyncPeriod, ProviderPeriod: minResyncPeriod}
+			},
+		},
+		{
+			name: "tuning and namespaces",
+			sync: &kubebindv1alpha1.SyncPolicy{
+				Spec:            &kubebindv1alpha1.SyncTuning{QPS: int32Ptr(100)},
+				Status:          &kubebindv1alpha1.SyncTuning{Workers: int32Ptr(4), Burst: int32Ptr(50)},
+				WatchNamespaces: []string{"team-a", "team-b"},
+			},
+			want: func(p *syncPolicy) {
+				p.tuning.Spec = tuning.Controller{Workers: 1, QPS: 100, Burst: 20}
+				p.tuning.Status = tuning.Controller{Workers: 4, Burst: 50}
+				p.watchNamespaces = []string{"team-a", "team-b"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reconciler{resync: defaults.resync, syncedMaxStaleness: defaults.maxStaleness, syncTuning: defaults.tuning, watchNamespaces: defaults.watchNamespaces}
+			binding := &kubebindv1alpha1.APIServiceBinding{Spec: kubebindv1alpha1.APIServiceBindingSpec{Sync: tt.sync}}
+			want := defaults
+			tt.want(&want)
+			require.Equal(t, want, r.syncPolicy(binding))
+		})
+	}
+}
+
+func TestEnsureServiceAccountTokens(t *testing.T) {
+	claim := kubebindv1alpha1.ServiceAccountTokenClaim{Namespace: "ns", Name: "sa", Audience: "https://provider.example.com", ExpirationSeconds: 3600}
+	secretName := kubebindhelpers.ServiceAccountTokenSecretName("foo", claim)
+	now := time.Now()
+	fresh := kubebindv1alpha1.ServiceAccountTokenStatus{
+		Namespace: "ns", Name: "sa", Audience: claim.Audience, SecretName: secretName,
+		ExpirationTimestamp: metav1.NewTime(now.Add(time.Hour)),
+		RenewTimestamp:      metav1.NewTime(now.Add(30 * time.Minute)),
+	}
+
+	tests := []struct {
+		name        string
+		accepted    bool
+		previous    []kubebindv1alpha1.ServiceAccountTokenStatus
+		wantIssued  bool
+		wantDeleted []string
+		wantTokens  int
+	}{
+		{name: "not accepted", wantTokens: 0},
+		{name: "issued", accepted: true, wantIssued: true, wantTokens: 1},
+		{name: "fresh token is kept", accepted: true, previous: []kubebindv1alpha1.ServiceAccountTokenStatus{fresh}, wantTokens: 1},
+		{name: "due token is renewed", accepted: true, previous: []kubebindv1alpha1.ServiceAccountTokenStatus{func() kubebindv1alpha1.ServiceAccountTokenStatus {
+			due := fresh
+			due.RenewTimestamp = metav1.NewTime(now.Add(-time.Minute))
+			return due
+		}()}, wantIssued: true, wantTokens: 1},
+		{name: "acceptance revoked", previous: []kubebindv1alpha1.ServiceAccountTokenStatus{fresh}, wantDeleted: []string{secretName}, wantTokens: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binding := &kubebindv1alpha1.APIServiceBinding{}
+			if tt.accepted {
+				binding.Spec.AcceptedServiceAccountTokenClaims = []kubebindv1alpha1.ServiceAccountTokenClaim{claim}
+			}
+			secrets := map[string]*corev1.Secret{}
+			for _, p := range tt.previous {
+				secrets[p.SecretName] = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster", Name: p.SecretName}}
+			}
+			var issued bool
+			var deleted []string
+			r := &reconciler{
+				getServiceBinding: func(name string) (*kubebindv1alpha1.APIServiceBinding, error) {
+					return binding, nil
+				},
+				createServiceAccountToken: func(ctx context.Context, ns, name string, tr *authenticationv1.TokenRequest) (*authenticationv1.TokenRequest, error) {
+					issued = true
+					require.Equal(t, []string{claim.Audience}, tr.Spec.Audiences)
+					tr = tr.DeepCopy()
+					tr.Status = authenticationv1.TokenRequestStatus{Token: "token", ExpirationTimestamp: metav1.NewTime(time.Now().Add(time.Hour))}
+					return tr, nil
+				},
+				getProviderSecret: func(ctx context.Context, ns, name string) (*corev1.Secret, error) {
+					if s, ok := secrets[name]; ok {
+						return s, nil
+					}
+					return nil, errors.NewNotFound(corev1.Resource("secrets"), name)
+				},
+				createProviderSecret: func(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+					secrets[secret.Name] = secret
+					return secret, nil
+				},
+				updateProviderSecret: func(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+					secrets[secret.Name] = secret
+					return secret, nil
+				},
+				deleteProviderSecret: func(ctx context.Context, ns, name string) error {
+					deleted = append(deleted, name)
+					return nil
+				},
+				requeueAfter: func(name string, after time.Duration) {},
+			}
+
+			export := &kubebindv1alpha1.APIServiceExport{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cluster", Name: "foo"},
+				Spec:       kubebindv1alpha1.APIServiceExportSpec{ServiceAccountTokenClaims: []kubebindv1alpha1.ServiceAccountTokenClaim{claim}},
+				Status:     kubebindv1alpha1.APIServiceExportStatus{ServiceAccountTokens: tt.previous},
+			}
+			require.NoError(t, r.ensureServiceAccountTokens(context.Background(), export))
+			require.Equal(t, tt.wantIssued, issued)
+			require.Equal(t, tt.wantDeleted, deleted)
+			require.Len(t, export.Status.ServiceAccountTokens, tt.wantTokens)
+			if tt.wantIssued {
+				require.Equal(t, []byte("token"), secrets[secretName].Data[serviceAccountTokenKey])
+			}
+		})
+	}
+}
+
+func TestEnsureControllersPaused(t *testing.T) {
+	export := newExport("foo", nil)
+	export.Status.ClaimedObjects = []kubebindv1alpha1.ClaimedObject{{Resource: "storageclasses", Name: "fast"}}
+	binding := &kubebindv1alpha1.APIServiceBinding{ObjectMeta: metav1.ObjectMeta{
+		Name:        "foo",
+		Annotations: map[string]string{kubebindv1alpha1.PausedAnnotationKey: "true"},
+	}}
+
+	cancelled := 0
+	r := &reconciler{
+		getCRD: newGetCRD("foo", newCRD("foo", nil)),
+		getServiceBinding: func(name string) (*kubebindv1alpha1.APIServiceBinding, error) {
+			return binding, nil
+		},
+		syncContext: map[string]syncContext{
+			"foo": {generation: 1, claims: []claimInformer{{}}, cancel: func() { cancelled++ }},
+		},
+	}
+	ctx := context.Background()
+
+	require.NoError(t, r.ensureControllers(ctx, "foo", export))
+	require.Equal(t, 1, cancelled)
+	c := r.syncContext["foo"]
+	require.True(t, c.paused)
+	require.Len(t, c.claims, 1)
+
+	// paused stays paused, and claimed objects are kept
+	require.NoError(t, r.ensureControllers(ctx, "foo", export))
+	require.Equal(t, 1, cancelled)
+	require.NoError(t, r.ensureClaimedObjects(ctx, export))
+	require.Len(t, export.Status.ClaimedObjects, 1)
+}
+
+func TestSyncVersion(t *testing.T) {
+	export := newExport("foo", nil)
+	export.Spec.SchemaRevision = "rev-2"
+	export.Spec.Versions = []kubebindv1alpha1.APIServiceExportVersion{{Name: "v1alpha1"}, {Name: "v1", Served: true}}
+	export.Spec.PreviousSchemaRevisions = []kubebindv1alpha1.APIServiceExportSchemaRevision{
+		{Name: "rev-1", Versions: []kubebindv1alpha1.APIServiceExportVersion{{Name: "v1alpha1", Served: true}}},
+	}
+	crd := newCRD("foo", nil)
+	crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1beta1", Served: true}}
+
+	tests := []struct {
+		name     string
+		revision string
+		want     string
+	}{
+		{name: "not pinned", want: "v1"},
+		{name: "pinned to latest", revision: "rev-2", want: "v1"},
+		{name: "pinned to previous", revision: "rev-1", want: "v1alpha1"},
+		{name: "revision not published anymore", revision: "rev-0", want: "v1beta1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binding := &kubebindv1alpha1.APIServiceBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec:       kubebindv1alpha1.APIServiceBindingSpec{SchemaRevision: tt.revision},
+			}
+			require.Equal(t, tt.want, syncVersion(export, binding, crd))
+		})
+	}
+}
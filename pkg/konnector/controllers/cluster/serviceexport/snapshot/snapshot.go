@@ -0,0 +1,238 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Snapshot records the resource versions of downstream and upstream objects when
+// they were last found in sync. It is persisted to a file such that a restarted
+// konnector can skip objects that did not change in the meantime, instead of
+// reconciling all of them again.
+//
+// A nil Snapshot is valid and records nothing.
+type Snapshot struct {
+	path        string
+	fingerprint string
+
+	lock    sync.Mutex
+	entries map[string]Entry
+	dirty   bool
+	// stopped is set when Run returned. Changes are saved right away from then on.
+	stopped bool
+}
+
+// Entry holds the resource versions of a pair of objects in sync.
+type Entry struct {
+	Consumer string `json:"c,omitempty"`
+	Provider string `json:"p,omitempty"`
+}
+
+type file struct {
+	Fingerprint string           `json:"fingerprint"`
+	Entries     map[string]Entry `json:"entries"`
+}
+
+// Open loads the snapshot from path. The fingerprint identifies everything the
+// sync depends on apart from the objects, e.g. the resource version and policies.
+// If it does not match the stored one, the stored entries are discarded.
+func Open(path, fingerprint string) (*Snapshot, error) {
+	s := &Snapshot{
+		path:        path,
+		fingerprint: fingerprint,
+		entries:     map[string]Entry{},
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return s, nil // corrupt, start over
+	}
+	var content file
+	if err := json.NewDecoder(r).Decode(&content); err != nil || content.Fingerprint != fingerprint {
+		return s, nil // corrupt or outdated, start over
+	}
+	if content.Entries != nil {
+		s.entries = content.Entries
+	}
+	return s, nil
+}
+
+// Record stores the resource versions of the given object pair.
+func (s *Snapshot) Record(key, consumerRV, providerRV string) {
+	if s == nil {
+		return
+	}
+	defer s.flushIfStopped()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e := Entry{Consumer: consumerRV, Provider: providerRV}
+	if s.entries[key] != e {
+		s.entries[key] = e
+		s.dirty = true
+	}
+}
+
+// Forget removes the given object pair.
+func (s *Snapshot) Forget(key string) {
+	if s == nil {
+		return
+	}
+	defer s.flushIfStopped()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, found := s.entries[key]; found {
+		delete(s.entries, key)
+		s.dirty = true
+	}
+}
+
+// ConsumerUnchanged returns true if the consumer object has the recorded resource version.
+func (s *Snapshot) ConsumerUnchanged(key, rv string) bool {
+	if s == nil || rv == "" {
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, found := s.entries[key]
+	return found && e.Consumer == rv
+}
+
+// ProviderUnchanged returns true if the provider object has the recorded resource version.
+func (s *Snapshot) ProviderUnchanged(key, rv string) bool {
+	if s == nil || rv == "" {
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, found := s.entries[key]
+	return found && e.Provider == rv
+}
+
+// Save writes the snapshot if it changed since the last save.
+func (s *Snapshot) Save() error {
+	if s == nil {
+		return nil
+	}
+	s.lock.Lock()
+	if !s.dirty {
+		s.lock.Unlock()
+		return nil
+	}
+	content := file{Fingerprint: s.fingerprint, Entries: make(map[string]Entry, len(s.entries))}
+	for k, v := range s.entries {
+		content.Entries[k] = v
+	}
+	s.dirty = false
+	s.lock.Unlock()
+
+	if err := s.write(&content); err != nil {
+		s.lock.Lock()
+		s.dirty = true
+		s.lock.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (s *Snapshot) write(content *file) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+
+	w := gzip.NewWriter(tmp)
+	if err := json.NewEncoder(w).Encode(content); err != nil {
+		tmp.Close() // nolint:errcheck
+		return err
+	}
+	if err := w.Close(); err != nil {
+		tmp.Close() // nolint:errcheck
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Run saves the snapshot every interval, and a last time when ctx is done.
+// Afterwards, every change is saved immediately, such that syncs finishing while
+// the konnector drains on shutdown are not lost.
+func (s *Snapshot) Run(ctx context.Context, interval time.Duration) {
+	if s == nil {
+		return
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := s.Save(); err != nil {
+			runtime.HandleError(err)
+		}
+	}, interval)
+
+	s.lock.Lock()
+	s.stopped = true
+	s.lock.Unlock()
+	if err := s.Save(); err != nil {
+		runtime.HandleError(err)
+	}
+}
+
+func (s *Snapshot) flushIfStopped() {
+	s.lock.Lock()
+	stopped := s.stopped
+	s.lock.Unlock()
+
+	if stopped {
+		if err := s.Save(); err != nil {
+			runtime.HandleError(err)
+		}
+	}
+}
+
+// ResourceVersion returns the resource version of an informer object, or an empty
+// string for tombstones and other unknown types.
+func ResourceVersion(obj interface{}) string {
+	if o, ok := obj.(metav1.Object); ok {
+		return o.GetResourceVersion()
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json.gz")
+
+	s, err := Open(path, "v1")
+	require.NoError(t, err)
+	require.False(t, s.ConsumerUnchanged("ns/a", "1"))
+
+	s.Record("ns/a", "1", "10")
+	s.Record("ns/b", "2", "20")
+	s.Forget("ns/b")
+	require.NoError(t, s.Save())
+
+	s, err = Open(path, "v1")
+	require.NoError(t, err)
+	require.True(t, s.ConsumerUnchanged("ns/a", "1"))
+	require.True(t, s.ProviderUnchanged("ns/a", "10"))
+	require.False(t, s.ConsumerUnchanged("ns/a", "2"))
+	require.False(t, s.ProviderUnchanged("ns/b", "20"))
+
+	s, err = Open(path, "v2")
+	require.NoError(t, err)
+	require.False(t, s.ConsumerUnchanged("ns/a", "1"), "entries of another fingerprint must be discarded")
+
+	// changes after Run returned, e.g. while draining, are saved right away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.Run(ctx, time.Hour)
+	s.Record("ns/c", "3", "30")
+	s, err = Open(path, "v2")
+	require.NoError(t, err)
+	require.True(t, s.ConsumerUnchanged("ns/c", "3"))
+
+	var nilSnapshot *Snapshot
+	nilSnapshot.Record("ns/a", "1", "10")
+	require.False(t, nilSnapshot.ConsumerUnchanged("ns/a", "1"))
+	require.NoError(t, nilSnapshot.Save())
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	syncs = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "spec_syncs_total",
+		Help:           "Number of spec syncs of consumer objects to the service provider by result (success or error).",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"result"})
+
+	syncDuration = metrics.NewHistogram(&metrics.HistogramOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "spec_sync_duration_seconds",
+		Help:           "Duration of spec syncs of consumer objects to the service provider.",
+		Buckets:        metrics.ExponentialBuckets(0.005, 2, 12),
+		StabilityLevel: metrics.ALPHA,
+	})
+
+	policyDenials = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "upsync_policy_denials_total",
+		Help:           "Number of spec syncs of consumer objects denied by an upsync policy.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"policy"})
+
+	throttled = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "upsync_throttled_total",
+		Help:           "Number of spec syncs delayed because the service provider signals overload, by reason (backpressure annotation or tooManyRequests).",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"reason"})
+)
+
+func init() {
+	legacyregistry.MustRegister(syncs, syncDuration, policyDenials, throttled)
+}
+
+func recordSync(start time.Time, err error) {
+	syncDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		syncs.WithLabelValues("error").Inc()
+		return
+	}
+	syncs.WithLabelValues("success").Inc()
+}
+
+func recordThrottled(reason string) {
+	throttled.WithLabelValues(reason).Inc()
+}
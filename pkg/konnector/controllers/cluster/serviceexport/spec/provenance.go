@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// provenance is stamped on downstream objects, such that cluster auditors can
+// select the objects managed by a service provider with a label selector.
+type provenance struct {
+	// binding is the name of the APIServiceBinding.
+	binding string
+	// exportUID is the UID of the APIServiceExport in the service provider cluster.
+	exportUID string
+	// provider is the hash of the service provider cluster URL.
+	provider string
+}
+
+// apply sets the provenance labels and annotations on obj. It returns false if
+// they were set already.
+func (p provenance) apply(obj *unstructured.Unstructured) bool {
+	changed := false
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range map[string]string{
+		kubebindv1alpha1.ExportUIDLabelKey: p.exportUID,
+		kubebindv1alpha1.ProviderLabelKey:  p.provider,
+	} {
+		if labels[k] != v {
+			labels[k] = v
+			changed = true
+		}
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if annotations[kubebindv1alpha1.BindingAnnotationKey] != p.binding {
+		annotations[kubebindv1alpha1.BindingAnnotationKey] = p.binding
+		changed = true
+	}
+	if changed {
+		obj.SetLabels(labels)
+		obj.SetAnnotations(annotations)
+	}
+	return changed
+}
+
+// removeProvenance removes the provenance labels and annotations from obj, e.g.
+// from the upstream copy of a downstream object.
+func removeProvenance(obj *unstructured.Unstructured) {
+	if labels := obj.GetLabels(); labels != nil {
+		delete(labels, kubebindv1alpha1.ExportUIDLabelKey)
+		delete(labels, kubebindv1alpha1.ProviderLabelKey)
+		obj.SetLabels(labels)
+	}
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		delete(annotations, kubebindv1alpha1.BindingAnnotationKey)
+		obj.SetAnnotations(annotations)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestProvenance(t *testing.T) {
+	p := provenance{binding: "mangodbs.mangodb.com", exportUID: "1234", provider: "abc"}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetLabels(map[string]string{"app": "shop"})
+	require.True(t, p.apply(obj))
+	require.Equal(t, map[string]string{"app": "shop", "kube-bind.io/export-uid": "1234", "kube-bind.io/provider": "abc"}, obj.GetLabels())
+	require.Equal(t, map[string]string{"kube-bind.io/binding": "mangodbs.mangodb.com"}, obj.GetAnnotations())
+	require.False(t, p.apply(obj))
+
+	p.exportUID = "5678"
+	require.True(t, p.apply(obj), "a re-created export must be re-stamped")
+	require.Equal(t, "5678", obj.GetLabels()["kube-bind.io/export-uid"])
+
+	removeProvenance(obj)
+	require.Equal(t, map[string]string{"app": "shop"}, obj.GetLabels())
+	require.Empty(t, obj.GetAnnotations())
+}
@@ -39,35 +39,62 @@ import (
 	"k8s.io/utils/pointer"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/indexers"
+	"github.com/kube-bind/kube-bind/pkg/konnector/backpressure"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/snapshot"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncevents"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/drain"
+	"github.com/kube-bind/kube-bind/pkg/konnector/policy"
+	"github.com/kube-bind/kube-bind/pkg/konnector/tracing"
 )
 
 const (
 	controllerName = "kube-bind-konnector-cluster-spec"
 
 	applyManager = "kube-bind.io"
+
+	snapshotSaveInterval = 30 * time.Second
 )
 
 // NewController returns a new controller reconciling downstream objects to upstream.
+// The GVRs differ if the consumer binds the resource under a group alias.
 func NewController(
-	gvr schema.GroupVersionResource,
+	consumerGVR, providerGVR schema.GroupVersionResource,
 	providerNamespace string,
+	bindingName string,
+	exportUID types.UID,
+	readOnly bool,
+	isolation kubebindv1alpha1.Isolation,
+	adoption kubebindv1alpha1.AdoptionPolicy,
+	fieldValidation kubebindv1alpha1.FieldValidationPolicy,
+	boundSince time.Time,
 	consumerConfig, providerConfig *rest.Config,
 	consumerDynamicInformer informers.GenericInformer,
 	providerDynamicInformer multinsinformer.GetterInformer,
 	serviceNamespaceInformer dynamic.Informer[bindlisters.APIServiceNamespaceLister],
+	snap *snapshot.Snapshot,
+	tune tuning.Controller,
+	upsyncPolicy *policy.Evaluator,
+	throttle *backpressure.Throttle,
+	events *syncevents.Recorder,
+	rateLimiter tuning.RateLimiter,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
-	providerConfig = rest.CopyConfig(providerConfig)
+	providerConfig = tune.Config(providerConfig)
 	providerConfig = rest.AddUserAgent(providerConfig, controllerName)
 
+	consumerConfig = tune.Config(consumerConfig)
+	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
+
 	providerClient, err := dynamicclient.NewForConfig(providerConfig)
 	if err != nil {
 		return nil, err
@@ -81,10 +108,18 @@ func NewController(
 		return nil, err
 	}
 
-	dynamicConsumerLister := dynamiclister.New(consumerDynamicInformer.Informer().GetIndexer(), gvr)
+	strict := fieldValidation == kubebindv1alpha1.FieldValidationPolicyStrict
+	var providerFieldValidation string
+	if strict {
+		providerFieldValidation = metav1.FieldValidationStrict
+	}
+
+	dynamicConsumerLister := dynamiclister.New(consumerDynamicInformer.Informer().GetIndexer(), consumerGVR)
 	c := &controller{
 		queue: queue,
 
+		consumerGVR: consumerGVR,
+
 		consumerClient: consumerClient,
 		providerClient: providerClient,
 
@@ -95,14 +130,32 @@ func NewController(
 
 		serviceNamespaceInformer: serviceNamespaceInformer,
 
+		snapshot: snap,
+		throttle: throttle,
+		events:   events,
+
 		reconciler: reconciler{
 			providerNamespace: providerNamespace,
+			readOnly:          readOnly,
+			isolation:         isolation,
+			adoption:          adoption,
+			strict:            strict,
+			reportPruned:      fieldValidation == kubebindv1alpha1.FieldValidationPolicyPruneAndReport,
+			boundSince:        boundSince,
+			provenance: provenance{
+				binding:   bindingName,
+				exportUID: string(exportUID),
+				provider:  kubebindhelpers.ProviderHash(providerConfig.Host),
+			},
 			getServiceNamespace: func(name string) (*kubebindv1alpha1.APIServiceNamespace, error) {
 				return serviceNamespaceInformer.Lister().APIServiceNamespaces(providerNamespace).Get(name)
 			},
 			createServiceNamespace: func(ctx context.Context, sn *kubebindv1alpha1.APIServiceNamespace) (*kubebindv1alpha1.APIServiceNamespace, error) {
 				return providerBindClient.KubeBindV1alpha1().APIServiceNamespaces(providerNamespace).Create(ctx, sn, metav1.CreateOptions{})
 			},
+			deleteServiceNamespace: func(ctx context.Context, name string) error {
+				return providerBindClient.KubeBindV1alpha1().APIServiceNamespaces(providerNamespace).Delete(ctx, name, metav1.DeleteOptions{})
+			},
 			getProviderObject: func(ns, name string) (*unstructured.Unstructured, error) {
 				obj, err := providerDynamicInformer.Get(ns, name)
 				if err != nil {
@@ -111,22 +164,30 @@ func NewController(
 				return obj.(*unstructured.Unstructured), nil
 			},
 			createProviderObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-				return providerClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+				obj.SetAPIVersion(providerGVR.GroupVersion().String())
+				return providerClient.Resource(providerGVR).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{FieldValidation: providerFieldValidation})
 			},
 			updateProviderObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+				obj.SetAPIVersion(providerGVR.GroupVersion().String())
 				data, err := json.Marshal(obj.Object)
 				if err != nil {
 					return nil, err
 				}
-				return providerClient.Resource(gvr).Namespace(obj.GetNamespace()).Patch(ctx,
-					obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: applyManager, Force: pointer.Bool(true)},
+				return providerClient.Resource(providerGVR).Namespace(obj.GetNamespace()).Patch(ctx,
+					obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: applyManager, Force: pointer.Bool(true), FieldValidation: providerFieldValidation},
 				)
 			},
+			evaluatePolicy: func(obj *unstructured.Unstructured) (*policy.Violation, error) {
+				return upsyncPolicy.Evaluate(consumerGVR.GroupResource(), obj)
+			},
 			deleteProviderObject: func(ctx context.Context, ns, name string) error {
-				return providerClient.Resource(gvr).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
+				return providerClient.Resource(providerGVR).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
 			},
 			updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-				return consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
+				return consumerClient.Resource(consumerGVR).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
+			},
+			updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+				return consumerClient.Resource(consumerGVR).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
 			},
 			requeue: func(obj *unstructured.Unstructured, after time.Duration) error {
 				key, err := cache.MetaNamespaceKeyFunc(obj)
@@ -136,30 +197,41 @@ func NewController(
 				queue.AddAfter(key, after)
 				return nil
 			},
+			recordInSync: func(downstream, upstream *unstructured.Unstructured) {
+				key, err := cache.MetaNamespaceKeyFunc(downstream)
+				if err != nil {
+					runtime.HandleError(err)
+					return
+				}
+				snap.Record(key, downstream.GetResourceVersion(), upstream.GetResourceVersion())
+			},
 		},
 	}
 
 	consumerDynamicInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			c.enqueueConsumer(logger, obj)
+			c.enqueueConsumer(logger, obj, true)
 		},
 		UpdateFunc: func(_, newObj interface{}) {
-			c.enqueueConsumer(logger, newObj)
+			c.enqueueConsumer(logger, newObj, false)
 		},
 		DeleteFunc: func(obj interface{}) {
-			c.enqueueConsumer(logger, obj)
+			if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				c.snapshot.Forget(key)
+			}
+			c.enqueueConsumer(logger, obj, false)
 		},
 	})
 
 	providerDynamicInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			c.enqueueProvider(logger, obj)
+			c.enqueueProvider(logger, obj, true)
 		},
 		UpdateFunc: func(_, newObj interface{}) {
-			c.enqueueProvider(logger, newObj)
+			c.enqueueProvider(logger, newObj, false)
 		},
 		DeleteFunc: func(obj interface{}) {
-			c.enqueueProvider(logger, obj)
+			c.enqueueProvider(logger, obj, false)
 		},
 	})
 
@@ -170,6 +242,8 @@ func NewController(
 type controller struct {
 	queue workqueue.RateLimitingInterface
 
+	consumerGVR schema.GroupVersionResource
+
 	consumerClient dynamicclient.Interface
 	providerClient dynamicclient.Interface
 
@@ -180,21 +254,35 @@ type controller struct {
 
 	serviceNamespaceInformer dynamic.Informer[bindlisters.APIServiceNamespaceLister]
 
+	// snapshot skips objects of initial add events that did not change since the
+	// konnector last found them in sync.
+	snapshot *snapshot.Snapshot
+
+	// throttle paces the upsync while the service provider signals overload.
+	throttle *backpressure.Throttle
+
+	// events records failed upsyncs on the consumer objects and the APIServiceBinding.
+	events *syncevents.Recorder
+
 	reconciler
 }
 
-func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}) {
+func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}, added bool) {
 	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
 		runtime.HandleError(err)
 		return
 	}
+	if added && c.snapshot.ConsumerUnchanged(key, snapshot.ResourceVersion(obj)) {
+		logger.V(3).Info("skipping Unstructured unchanged since snapshot", "key", key)
+		return
+	}
 
 	logger.V(2).Info("queueing Unstructured", "key", key)
 	c.queue.Add(key)
 }
 
-func (c *controller) enqueueProvider(logger klog.Logger, obj interface{}) {
+func (c *controller) enqueueProvider(logger klog.Logger, obj interface{}, added bool) {
 	upstreamKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
 		runtime.HandleError(err)
@@ -214,10 +302,17 @@ func (c *controller) enqueueProvider(logger klog.Logger, obj interface{}) {
 			}
 			return
 		}
-		for _, obj := range sns {
-			sn := obj.(*kubebindv1alpha1.APIServiceNamespace)
+		for _, o := range sns {
+			sn := o.(*kubebindv1alpha1.APIServiceNamespace)
 			if sn.Namespace == c.providerNamespace {
-				key := fmt.Sprintf("%s/%s", sn.Name, name)
+				if object := kubebindhelpers.IsolatedObject(sn.Name); object != "" && object != name {
+					return // not the object the namespace is dedicated to
+				}
+				key := fmt.Sprintf("%s/%s", kubebindhelpers.ConsumerNamespace(sn.Name), name)
+				if added && c.snapshot.ProviderUnchanged(key, snapshot.ResourceVersion(obj)) {
+					logger.V(3).Info("skipping Unstructured unchanged since snapshot", "key", key)
+					return
+				}
 				logger.V(2).Info("queueing Unstructured", "key", key)
 				c.queue.Add(key)
 				return
@@ -226,11 +321,15 @@ func (c *controller) enqueueProvider(logger klog.Logger, obj interface{}) {
 		return
 	}
 
+	if added && c.snapshot.ProviderUnchanged(upstreamKey, snapshot.ResourceVersion(obj)) {
+		logger.V(3).Info("skipping Unstructured unchanged since snapshot", "key", upstreamKey)
+		return
+	}
 	logger.V(2).Info("queueing Unstructured", "key", upstreamKey)
 	c.queue.Add(upstreamKey)
 }
 
-func (c *controller) enqueueServiceNamespace(logger klog.Logger, obj interface{}) {
+func (c *controller) enqueueServiceNamespace(logger klog.Logger, obj interface{}, added bool) {
 	snKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
 		runtime.HandleError(err)
@@ -245,17 +344,24 @@ func (c *controller) enqueueServiceNamespace(logger klog.Logger, obj interface{}
 		return // not for us
 	}
 
-	objs, err := c.consumerDynamicIndexer.ByIndex(cache.NamespaceIndex, name)
+	objs, err := c.consumerDynamicIndexer.ByIndex(cache.NamespaceIndex, kubebindhelpers.ConsumerNamespace(name))
 	if err != nil {
 		runtime.HandleError(err)
 		return
 	}
+	object := kubebindhelpers.IsolatedObject(name)
 	for _, obj := range objs {
 		key, err := cache.MetaNamespaceKeyFunc(obj)
 		if err != nil {
 			runtime.HandleError(err)
 			continue
 		}
+		if object != "" && key != kubebindhelpers.ConsumerNamespace(name)+"/"+object {
+			continue
+		}
+		if added && c.snapshot.ConsumerUnchanged(key, snapshot.ResourceVersion(obj)) {
+			continue
+		}
 		logger.V(2).Info("queueing Unstructured", "key", key, "reason", "APIServiceNamespace", "ServiceNamespaceKey", key)
 		c.queue.Add(key)
 	}
@@ -273,16 +379,18 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 
 	c.serviceNamespaceInformer.Informer().AddDynamicEventHandler(ctx, controllerName, cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			c.enqueueServiceNamespace(logger, obj)
+			c.enqueueServiceNamespace(logger, obj, true)
 		},
 		UpdateFunc: func(_, newObj interface{}) {
-			c.enqueueServiceNamespace(logger, newObj)
+			c.enqueueServiceNamespace(logger, newObj, false)
 		},
 		DeleteFunc: func(obj interface{}) {
-			c.enqueueServiceNamespace(logger, obj)
+			c.enqueueServiceNamespace(logger, obj, false)
 		},
 	})
 
+	go c.snapshot.Run(ctx, snapshotSaveInterval)
+
 	for i := 0; i < numThreads; i++ {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
@@ -305,15 +413,44 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	}
 	key := k.(string)
 
-	logger := klog.FromContext(ctx).WithValues("key", key)
-	ctx = klog.NewContext(ctx, logger)
-	logger.V(2).Info("processing key")
-
 	// No matter what, tell the queue we're done with this key, to unblock
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	logger := klog.FromContext(ctx).WithValues("key", key)
+	ctx = klog.NewContext(ctx, logger)
+
+	if delay := c.throttle.Delay(time.Now()); delay > 0 {
+		logger.V(3).Info("delaying key due to service provider backpressure", "delay", delay)
+		recordThrottled("backpressure")
+		c.queue.AddAfter(key, delay)
+		return true
+	}
+
+	start := time.Now()
+	started, err := drain.Process(ctx, func(ctx context.Context) error {
+		logger.V(2).Info("processing key")
+		ctx, end := tracing.StartSync(ctx, "Upsync", c.provenance.binding, c.consumerGVR, key)
+		err := c.process(ctx, key)
+		end(err)
+		return err
+	})
+	if !started {
+		return false // shutting down, don't start new work
+	}
+	recordSync(start, err)
+	if errors.IsTooManyRequests(err) && c.throttle != nil {
+		retryAfter := backpressure.DefaultRetryAfter
+		if seconds, ok := errors.SuggestsClientDelay(err); ok && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		logger.V(1).Info("service provider is throttling, backing off", "retryAfter", retryAfter)
+		recordThrottled("tooManyRequests")
+		c.throttle.Reject(time.Now(), retryAfter)
+		c.queue.AddAfter(key, retryAfter)
+		return true
+	}
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		c.queue.AddRateLimited(key)
 		return true
@@ -339,5 +476,11 @@ func (c *controller) process(ctx context.Context, key string) error {
 		return nil
 	}
 
-	return c.reconcile(ctx, obj)
+	if err := c.reconcile(ctx, obj); err != nil {
+		if !errors.IsTooManyRequests(err) {
+			c.events.SyncFailed(obj, syncevents.ReasonUpsyncFailed, err)
+		}
+		return err
+	}
+	return nil
 }
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -29,38 +30,87 @@ import (
 	"k8s.io/klog/v2"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/providermessage"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/pruning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/policy"
 )
 
 type reconciler struct {
 	providerNamespace string
+	readOnly          bool
+	// isolation decides whether consumer objects get their own APIServiceNamespace.
+	isolation kubebindv1alpha1.Isolation
+
+	// adoption is the policy for objects created before boundSince, i.e. before the
+	// APIServiceBinding existed.
+	adoption   kubebindv1alpha1.AdoptionPolicy
+	boundSince time.Time
+
+	// strict makes the service provider reject upstream objects with unknown fields
+	// instead of pruning them.
+	strict bool
+	// reportPruned records the fields the service provider pruned on the downstream object.
+	reportPruned bool
+
+	// provenance is stamped on every synced downstream object.
+	provenance provenance
 
 	getServiceNamespace    func(name string) (*kubebindv1alpha1.APIServiceNamespace, error)
 	createServiceNamespace func(ctx context.Context, sn *kubebindv1alpha1.APIServiceNamespace) (*kubebindv1alpha1.APIServiceNamespace, error)
+	deleteServiceNamespace func(ctx context.Context, name string) error
 
 	getProviderObject    func(ns, name string) (*unstructured.Unstructured, error)
 	createProviderObject func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
 	updateProviderObject func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
 	deleteProviderObject func(ctx context.Context, ns, name string) error
 
-	updateConsumerObject func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	updateConsumerObject       func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	updateConsumerObjectStatus func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
 
 	requeue func(obj *unstructured.Unstructured, after time.Duration) error
+
+	// evaluatePolicy returns the upsync policy violated by the downstream object, if any.
+	evaluatePolicy func(obj *unstructured.Unstructured) (*policy.Violation, error)
+
+	// recordInSync is called with downstream and upstream objects found in sync.
+	recordInSync func(downstream, upstream *unstructured.Unstructured)
 }
 
 // reconcile syncs downstream objects (metadata and spec) with upstream objects.
 func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructured) error {
 	logger := klog.FromContext(ctx)
 
+	if _, found := obj.GetLabels()[kubebindv1alpha1.VirtualClusterManagedByLabel]; found {
+		// translated from a virtual cluster, synced by the konnector serving the virtual cluster
+		logger.V(2).Info("ignoring object translated from a virtual cluster")
+		return nil
+	}
+
+	if r.isPreExisting(obj) {
+		if r.adoption != kubebindv1alpha1.AdoptionPolicyAdopt {
+			logger.V(2).Info("ignoring object that existed before the APIServiceBinding", "adoption", r.adoption)
+			return nil
+		}
+		logger.Info("Adopting object that existed before the APIServiceBinding")
+	}
+
 	ns := obj.GetNamespace()
+	snName := kubebindhelpers.ServiceNamespaceName(r.isolation, ns, obj.GetName())
 	if ns != "" {
-		sn, err := r.getServiceNamespace(ns)
+		sn, err := r.getServiceNamespace(snName)
 		if err != nil && !errors.IsNotFound(err) {
 			return err
 		} else if errors.IsNotFound(err) {
-			logger.V(1).Info("creating APIServiceNamespace", "namespace", ns)
+			if obj.GetDeletionTimestamp() != nil && r.isolation == kubebindv1alpha1.ObjectIsolation {
+				// the dedicated namespace is gone already
+				_, err := r.removeDownstreamFinalizer(ctx, obj)
+				return err
+			}
+			logger.V(1).Info("creating APIServiceNamespace", "namespace", ns, "name", snName)
 			sn, err = r.createServiceNamespace(ctx, &kubebindv1alpha1.APIServiceNamespace{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      ns,
+					Name:      snName,
 					Namespace: r.providerNamespace,
 				},
 			})
@@ -84,7 +134,23 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 	upstream, err := r.getProviderObject(ns, obj.GetName())
 	if err != nil && !errors.IsNotFound(err) {
 		return err
-	} else if errors.IsNotFound(err) {
+	}
+	if r.readOnly {
+		return r.reconcileReadOnly(ctx, obj, upstream)
+	}
+	if obj.GetDeletionTimestamp() == nil {
+		violation, err := r.evaluatePolicy(obj)
+		if err != nil {
+			return err
+		}
+		if violation != nil {
+			// deletion is still synced, but nothing else until the object or the policies change
+			logger.V(1).Info("not syncing object denied by upsync policy", "policy", violation.Policy)
+			policyDenials.WithLabelValues(violation.Policy).Inc()
+			return r.ensureProviderMessage(ctx, obj, providermessage.PolicyViolationReason, violation.Error())
+		}
+	}
+	if errors.IsNotFound(err) {
 		if obj.GetDeletionTimestamp() != nil && !obj.GetDeletionTimestamp().IsZero() {
 			logger.V(2).Info("object is already deleting, don't sync")
 
@@ -92,10 +158,10 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 				return err
 			}
 
-			return nil
+			return r.ensureServiceNamespaceDeleted(ctx, obj, snName)
 		}
 
-		if obj, err = r.ensureDownstreamFinalizer(ctx, obj); err != nil {
+		if obj, err = r.ensureDownstreamMetadata(ctx, obj); err != nil {
 			return err
 		}
 
@@ -109,13 +175,17 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		upstream.SetDeletionGracePeriodSeconds(nil)
 		upstream.SetOwnerReferences(nil)
 		upstream.SetFinalizers(nil)
+		removeProvenance(upstream)
+		setConsumerGeneration(upstream, obj.GetGeneration())
 		unstructured.RemoveNestedField(upstream.Object, "status")
 
 		logger.Info("Creating upstream object")
-		if _, err := r.createProviderObject(ctx, upstream); err != nil && !errors.IsAlreadyExists(err) {
-			return err
+		if created, err := r.createProviderObject(ctx, upstream); err != nil && !errors.IsAlreadyExists(err) {
+			return r.ensureProviderRejection(ctx, obj, err)
 		} else if errors.IsAlreadyExists(err) {
 			logger.Info("Upstream object already exists. Waiting for requeue.") // the upstream object will lead to a requeue
+		} else if obj, err = r.ensurePrunedFields(ctx, obj, upstream, created); err != nil {
+			return err
 		}
 	}
 
@@ -137,11 +207,11 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		}
 
 		logger.V(2).Info("upstream deleted, finalizer removed in downstream, waiting for downstream deletion to finish")
-		return nil // we will get an event when the upstream is deleted
+		return r.ensureServiceNamespaceDeleted(ctx, obj, snName)
 	}
 
-	// just in case, checking for finalizer
-	if obj, err = r.ensureDownstreamFinalizer(ctx, obj); err != nil {
+	// just in case, checking for finalizer and provenance
+	if obj, err = r.ensureDownstreamMetadata(ctx, obj); err != nil {
 		return err
 	}
 
@@ -155,11 +225,17 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		logger.Error(err, "failed to get downstream spec")
 		return nil
 	}
-	if reflect.DeepEqual(downstreamSpec, upstreamSpec) {
-		return nil // nothing to do
+	consumerGeneration := strconv.FormatInt(obj.GetGeneration(), 10)
+	if reflect.DeepEqual(downstreamSpec, upstreamSpec) && upstream.GetAnnotations()[kubebindv1alpha1.ConsumerGenerationAnnotationKey] == consumerGeneration {
+		if err := r.ensureProviderRejection(ctx, obj, nil); err != nil {
+			return err
+		}
+		r.recordInSync(obj, upstream)
+		return nil
 	}
 
 	upstream = upstream.DeepCopy()
+	setConsumerGeneration(upstream, obj.GetGeneration())
 	if foundDownstreamSpec {
 		if err := unstructured.SetNestedField(upstream.Object, downstreamSpec, "spec"); err != nil {
 			bs, err := json.Marshal(downstreamSpec)
@@ -176,14 +252,144 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 
 	logger.Info("Updating update object")
 	upstream.SetManagedFields(nil) // server side apply does not want this
-	if _, err := r.updateProviderObject(ctx, upstream); err != nil {
+	if stored, err := r.updateProviderObject(ctx, upstream); err != nil {
+		return r.ensureProviderRejection(ctx, obj, err)
+	} else if obj, err = r.ensurePrunedFields(ctx, obj, upstream, stored); err != nil {
+		return err
+	}
+
+	return r.ensureProviderRejection(ctx, obj, nil)
+}
+
+// ensurePrunedFields records the spec fields the service provider dropped from the sent
+// upstream object in an annotation on the downstream object, with PruneAndReport field
+// validation. Otherwise, a left-over annotation is removed.
+func (r *reconciler) ensurePrunedFields(ctx context.Context, obj, sent, stored *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	var pruned []string
+	if spec, found, _ := unstructured.NestedFieldNoCopy(sent.Object, "spec"); found && r.reportPruned {
+		pruned = pruning.Fields("", map[string]interface{}{"spec": spec}, stored.Object)
+	}
+
+	downstream := obj.DeepCopy()
+	if !pruning.Annotate(downstream, kubebindv1alpha1.PrunedUpstreamFieldsAnnotationKey, pruned) {
+		return obj, nil
+	}
+	klog.FromContext(ctx).V(1).Info("Updating downstream object pruned fields", "fields", pruned)
+	return r.updateConsumerObject(ctx, downstream)
+}
+
+// ensureServiceNamespaceDeleted deletes the APIServiceNamespace dedicated to a deleted
+// object with Object isolation, for the service provider to delete its namespace.
+func (r *reconciler) ensureServiceNamespaceDeleted(ctx context.Context, obj *unstructured.Unstructured, snName string) error {
+	if r.isolation != kubebindv1alpha1.ObjectIsolation || obj.GetNamespace() == "" {
+		return nil
+	}
+
+	klog.FromContext(ctx).V(1).Info("deleting APIServiceNamespace of deleted object", "name", snName)
+	if err := r.deleteServiceNamespace(ctx, snName); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// ensureProviderRejection records a rejection of the upstream object by the service provider
+// on the downstream object's status, or clears it if err is nil. Other errors are returned as is.
+func (r *reconciler) ensureProviderRejection(ctx context.Context, obj *unstructured.Unstructured, err error) error {
+	rejected := errors.IsInvalid(err) || errors.IsForbidden(err) || errors.IsBadRequest(err)
+	if err != nil && !rejected {
+		return err
+	}
+
+	if r.strict && errors.IsBadRequest(err) {
+		// unknown fields. Retrying is pointless until the downstream spec or the upstream schema changes.
+		return r.ensureProviderMessage(ctx, obj, providermessage.UpstreamSchemaMismatchReason, err.Error())
+	}
+
+	if !rejected {
+		return r.ensureProviderMessage(ctx, obj, "", "")
+	}
+	if err := r.ensureProviderMessage(ctx, obj, providermessage.UpstreamRejectedReason, err.Error()); err != nil {
 		return err
 	}
+	if !errors.IsInvalid(err) {
+		return err // might be transient, e.g. RBAC. Retry with backoff.
+	}
+	return nil // retrying is pointless until the downstream spec changes
+}
+
+// ensureProviderMessage sets the provider message condition owned by this controller on the
+// downstream object, or clears it if reason is empty.
+func (r *reconciler) ensureProviderMessage(ctx context.Context, obj *unstructured.Unstructured, reason, message string) error {
+	logger := klog.FromContext(ctx)
+
+	previous := providermessage.Get(obj)
+	downstream := obj.DeepCopy()
+	if reason != "" {
+		if err := providermessage.Set(downstream, previous, reason, message); err != nil {
+			return err
+		}
+	} else if previous == nil || !providermessage.IsOwnedBySpec(previous) {
+		return nil
+	} else if err := providermessage.Remove(downstream); err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(obj, downstream) {
+		logger.V(1).Info("Updating downstream object provider message", "reason", reason)
+		if _, err := r.updateConsumerObjectStatus(ctx, downstream); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (r *reconciler) ensureDownstreamFinalizer(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+// reconcileReadOnly handles objects of read-only APIServiceExports: nothing is created, updated
+// or deleted upstream, but the consumer is told when the downstream object diverges.
+func (r *reconciler) reconcileReadOnly(ctx context.Context, obj, upstream *unstructured.Unstructured) error {
+	if obj.GetDeletionTimestamp() != nil && !obj.GetDeletionTimestamp().IsZero() {
+		_, err := r.removeDownstreamFinalizer(ctx, obj)
+		return err
+	}
+
+	if upstream != nil {
+		downstreamSpec, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+		upstreamSpec, _, _ := unstructured.NestedFieldNoCopy(upstream.Object, "spec")
+		if reflect.DeepEqual(downstreamSpec, upstreamSpec) {
+			return r.ensureProviderMessage(ctx, obj, "", "")
+		}
+	}
+
+	return r.ensureProviderMessage(ctx, obj, providermessage.ReadOnlyReason, "APIServiceExport is read-only. Changes are not synced to the service provider.")
+}
+
+// setConsumerGeneration records the generation of the downstream object on the upstream object.
+func setConsumerGeneration(upstream *unstructured.Unstructured, generation int64) {
+	annotations := upstream.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[kubebindv1alpha1.ConsumerGenerationAnnotationKey] = strconv.FormatInt(generation, 10)
+	upstream.SetAnnotations(annotations)
+}
+
+// isPreExisting returns true if the object was created before the APIServiceBinding and
+// has never been synced.
+func (r *reconciler) isPreExisting(obj *unstructured.Unstructured) bool {
+	if r.boundSince.IsZero() || !obj.GetCreationTimestamp().Time.Before(r.boundSince) {
+		return false
+	}
+	for _, f := range obj.GetFinalizers() {
+		if f == kubebindv1alpha1.DownstreamFinalizer {
+			return false
+		}
+	}
+	return true
+}
+
+// ensureDownstreamMetadata adds our finalizer and the provenance labels and annotations
+// to the downstream object, in one update.
+func (r *reconciler) ensureDownstreamMetadata(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 	logger := klog.FromContext(ctx)
 
 	// check that downstream has our finalizer
@@ -195,17 +401,18 @@ func (r *reconciler) ensureDownstreamFinalizer(ctx context.Context, obj *unstruc
 		}
 	}
 
+	updated := obj.DeepCopy()
 	if !found {
 		logger.V(2).Info("adding finalizer to downstream object")
-		obj = obj.DeepCopy()
-		obj.SetFinalizers(append(obj.GetFinalizers(), kubebindv1alpha1.DownstreamFinalizer))
-		var err error
-		if obj, err = r.updateConsumerObject(ctx, obj); err != nil {
-			return nil, err
-		}
+		updated.SetFinalizers(append(updated.GetFinalizers(), kubebindv1alpha1.DownstreamFinalizer))
+	}
+	if r.provenance.apply(updated) {
+		logger.V(2).Info("adding provenance to downstream object")
+	} else if found {
+		return obj, nil
 	}
 
-	return obj, nil
+	return r.updateConsumerObject(ctx, updated)
 }
 
 func (r *reconciler) removeDownstreamFinalizer(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/konnector/policy"
+)
+
+var boundSince = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newObject(created time.Time, finalizers ...string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "mangodb.com/v1alpha1",
+		"kind":       "MangoDB",
+		"spec":       map[string]interface{}{"tier": "Dedicated"},
+	}}
+	obj.SetName("foo")
+	obj.SetCreationTimestamp(metav1.NewTime(created))
+	obj.SetFinalizers(finalizers)
+	return obj
+}
+
+func TestIsPreExisting(t *testing.T) {
+	tests := []struct {
+		name       string
+		boundSince time.Time
+		obj        *unstructured.Unstructured
+		want       bool
+	}{
+		{name: "binding time unknown", obj: newObject(boundSince.Add(-time.Hour))},
+		{name: "created after binding", boundSince: boundSince, obj: newObject(boundSince.Add(time.Hour))},
+		{name: "created with binding", boundSince: boundSince, obj: newObject(boundSince)},
+		{name: "created before binding", boundSince: boundSince, obj: newObject(boundSince.Add(-time.Hour)), want: true},
+		{name: "created before binding, but synced", boundSince: boundSince, obj: newObject(boundSince.Add(-time.Hour), "other", kubebindv1alpha1.DownstreamFinalizer)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reconciler{boundSince: tt.boundSince}
+			require.Equal(t, tt.want, r.isPreExisting(tt.obj))
+		})
+	}
+}
+
+func TestReconcileAdoption(t *testing.T) {
+	tests := []struct {
+		name        string
+		adoption    kubebindv1alpha1.AdoptionPolicy
+		obj         *unstructured.Unstructured
+		wantCreated bool
+	}{
+		{name: "pre-existing, no policy", obj: newObject(boundSince.Add(-time.Hour))},
+		{name: "pre-existing, ignored", adoption: kubebindv1alpha1.AdoptionPolicyIgnore, obj: newObject(boundSince.Add(-time.Hour))},
+		{name: "pre-existing, adopted", adoption: kubebindv1alpha1.AdoptionPolicyAdopt, obj: newObject(boundSince.Add(-time.Hour)), wantCreated: true},
+		{name: "new, ignored", adoption: kubebindv1alpha1.AdoptionPolicyIgnore, obj: newObject(boundSince.Add(time.Hour)), wantCreated: true},
+		{name: "new, adopted", adoption: kubebindv1alpha1.AdoptionPolicyAdopt, obj: newObject(boundSince.Add(time.Hour)), wantCreated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *unstructured.Unstructured
+			r := &reconciler{
+				adoption:   tt.adoption,
+				boundSince: boundSince,
+				getProviderObject: func(ns, name string) (*unstructured.Unstructured, error) {
+					return nil, errors.NewNotFound(schema.GroupResource{Group: "mangodb.com", Resource: "mangodbs"}, name)
+				},
+				createProviderObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					created = obj
+					return obj, nil
+				},
+				updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					return obj, nil
+				},
+				evaluatePolicy: func(obj *unstructured.Unstructured) (*policy.Violation, error) {
+					return nil, nil
+				},
+				recordInSync: func(downstream, upstream *unstructured.Unstructured) {},
+			}
+
+			err := r.reconcile(context.Background(), tt.obj)
+			require.NoError(t, err)
+			if !tt.wantCreated {
+				require.Nil(t, created, "pre-existing object must not be upsynced")
+				return
+			}
+			require.NotNil(t, created)
+			require.Equal(t, "foo", created.GetName())
+			require.Equal(t, map[string]interface{}{"tier": "Dedicated"}, created.Object["spec"])
+		})
+	}
+}
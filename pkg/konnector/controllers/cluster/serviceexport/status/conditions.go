@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// setDownstreamCondition puts the condition onto the downstream object, replacing one of
+// the same type. The lastTransitionTime of the condition in orig is kept if status, reason
+// and message did not change.
+func setDownstreamCondition(orig, downstream *unstructured.Unstructured, cond map[string]interface{}) error {
+	if previous := getDownstreamCondition(orig, cond["type"].(string)); previous != nil &&
+		previous["status"] == cond["status"] && previous["reason"] == cond["reason"] && previous["message"] == cond["message"] {
+		cond["lastTransitionTime"] = previous["lastTransitionTime"]
+	} else {
+		cond["lastTransitionTime"] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	if err := removeDownstreamCondition(downstream, cond["type"].(string)); err != nil {
+		return err
+	}
+	conds, _, _ := unstructured.NestedSlice(downstream.Object, "status", "conditions")
+	return unstructured.SetNestedSlice(downstream.Object, append(conds, cond), "status", "conditions")
+}
+
+// getDownstreamCondition returns the condition of the given type, or nil.
+func getDownstreamCondition(obj *unstructured.Unstructured, conditionType string) map[string]interface{} {
+	conds, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conds {
+		if cond, ok := c.(map[string]interface{}); ok && cond["type"] == conditionType {
+			return cond
+		}
+	}
+	return nil
+}
+
+// removeDownstreamCondition drops the condition of the given type from the downstream object.
+func removeDownstreamCondition(obj *unstructured.Unstructured, conditionType string) error {
+	conds, found, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if !found || getDownstreamCondition(obj, conditionType) == nil {
+		return nil
+	}
+
+	filtered := make([]interface{}, 0, len(conds))
+	for _, c := range conds {
+		if c, ok := c.(map[string]interface{}); ok && c["type"] == conditionType {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	if len(filtered) == 0 {
+		unstructured.RemoveNestedField(obj.Object, "status", "conditions")
+		return nil
+	}
+	return unstructured.SetNestedSlice(obj.Object, filtered, "status", "conditions")
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// translateGeneration maps the observedGeneration of the upstream status and its conditions
+// to the generation of the downstream object, using the consumer generation recorded on the
+// upstream object by the spec controller. It returns false if the upstream status is older
+// than the downstream status, i.e. must not be downsynced.
+//
+// If the upstream object has no consumer generation, the status is returned as is.
+func translateGeneration(upstream, downstream *unstructured.Unstructured, status interface{}) (interface{}, bool) {
+	consumerGeneration, err := strconv.ParseInt(upstream.GetAnnotations()[kubebindv1alpha1.ConsumerGenerationAnnotationKey], 10, 64)
+	if err != nil {
+		return status, true
+	}
+	m, ok := status.(map[string]interface{})
+	if !ok {
+		return status, true
+	}
+	m = runtime.DeepCopyJSONValue(m).(map[string]interface{})
+
+	upstreamGeneration := upstream.GetGeneration()
+	previous, previousFound, _ := unstructured.NestedInt64(downstream.Object, "status", "observedGeneration")
+	if observed, found, _ := unstructured.NestedInt64(m, "observedGeneration"); found {
+		switch {
+		case observed == upstreamGeneration:
+			if previousFound && previous > consumerGeneration {
+				return nil, false // the downstream status reflects a newer spec
+			}
+			m["observedGeneration"] = consumerGeneration
+		case previousFound:
+			// the service provider has not seen the latest spec yet
+			m["observedGeneration"] = previous
+		default:
+			delete(m, "observedGeneration")
+		}
+	}
+
+	conds, found, _ := unstructured.NestedSlice(m, "conditions")
+	if !found {
+		return m, true
+	}
+	for _, c := range conds {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if observed, found, _ := unstructured.NestedInt64(cond, "observedGeneration"); !found {
+			continue
+		} else if observed == upstreamGeneration {
+			cond["observedGeneration"] = consumerGeneration
+		} else {
+			delete(cond, "observedGeneration") // cannot be mapped
+		}
+	}
+	m["conditions"] = conds
+
+	return m, true
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestTranslateGeneration(t *testing.T) {
+	upstream := func(generation int64, consumerGeneration string, status map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+		obj.SetGeneration(generation)
+		if consumerGeneration != "" {
+			obj.SetAnnotations(map[string]string{kubebindv1alpha1.ConsumerGenerationAnnotationKey: consumerGeneration})
+		}
+		return obj
+	}
+	downstream := func(observedGeneration int64) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		if observedGeneration > 0 {
+			obj.Object["status"] = map[string]interface{}{"observedGeneration": observedGeneration}
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name       string
+		upstream   *unstructured.Unstructured
+		downstream *unstructured.Unstructured
+		want       interface{}
+		wantFresh  bool
+	}{
+		{
+			name:       "no consumer generation",
+			upstream:   upstream(3, "", map[string]interface{}{"observedGeneration": int64(3)}),
+			downstream: downstream(0),
+			want:       map[string]interface{}{"observedGeneration": int64(3)},
+			wantFresh:  true,
+		},
+		{
+			name: "latest spec observed",
+			upstream: upstream(3, "7", map[string]interface{}{"observedGeneration": int64(3), "conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "observedGeneration": int64(3)},
+				map[string]interface{}{"type": "Other", "observedGeneration": int64(2)},
+			}}),
+			downstream: downstream(5),
+			want: map[string]interface{}{"observedGeneration": int64(7), "conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "observedGeneration": int64(7)},
+				map[string]interface{}{"type": "Other"},
+			}},
+			wantFresh: true,
+		},
+		{
+			name:       "latest spec not observed yet",
+			upstream:   upstream(4, "8", map[string]interface{}{"observedGeneration": int64(3)}),
+			downstream: downstream(7),
+			want:       map[string]interface{}{"observedGeneration": int64(7)},
+			wantFresh:  true,
+		},
+		{
+			name:       "stale upstream status",
+			upstream:   upstream(3, "7", map[string]interface{}{"observedGeneration": int64(3)}),
+			downstream: downstream(8),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.upstream.Object["status"]
+			got, fresh := translateGeneration(tt.upstream, tt.downstream, status)
+			require.Equal(t, tt.wantFresh, fresh)
+			if tt.wantFresh {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
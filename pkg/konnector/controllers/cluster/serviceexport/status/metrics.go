@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	freshnessTargets = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "freshness_target_total",
+		Help:           "Number of spec changes of bound objects by whether the service provider synced them within the freshness target of the APIServiceBinding (result attained) or not (result missed).",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"binding", "result"})
+
+	syncs = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "status_syncs_total",
+		Help:           "Number of status syncs of service provider objects to the consumer by result (success or error).",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"result"})
+
+	syncDuration = metrics.NewHistogram(&metrics.HistogramOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "status_sync_duration_seconds",
+		Help:           "Duration of status syncs of service provider objects to the consumer.",
+		Buckets:        metrics.ExponentialBuckets(0.005, 2, 12),
+		StabilityLevel: metrics.ALPHA,
+	})
+)
+
+func init() {
+	legacyregistry.MustRegister(freshnessTargets, syncs, syncDuration)
+}
+
+func recordSync(start time.Time, err error) {
+	syncDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		syncs.WithLabelValues("error").Inc()
+		return
+	}
+	syncs.WithLabelValues("success").Inc()
+}
+
+// recordFreshness counts a transition of the synced condition to Synced as attained,
+// and to StatusStale as missed. Objects that become synced after being stale are
+// only counted once as missed.
+func recordFreshness(binding string, previous, current map[string]interface{}) {
+	if current == nil {
+		return
+	}
+	if previous != nil && previous["reason"] == current["reason"] && previous["observedGeneration"] == current["observedGeneration"] {
+		return
+	}
+	switch current["reason"] {
+	case syncedReason:
+		if previous != nil && previous["reason"] == statusStaleReason && previous["observedGeneration"] == current["observedGeneration"] {
+			return
+		}
+		freshnessTargets.WithLabelValues(binding, "attained").Inc()
+	case statusStaleReason:
+		freshnessTargets.WithLabelValues(binding, "missed").Inc()
+	}
+}
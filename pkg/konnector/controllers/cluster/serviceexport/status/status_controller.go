@@ -36,33 +36,51 @@ import (
 	"k8s.io/klog/v2"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/indexers"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/snapshot"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncevents"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/drain"
+	"github.com/kube-bind/kube-bind/pkg/konnector/tracing"
 )
 
 const (
 	controllerName = "kube-bind-konnector-cluster-status"
+
+	snapshotSaveInterval = 30 * time.Second
 )
 
 // NewController returns a new controller reconciling status of upstream to downstream.
+// The GVRs differ if the consumer binds the resource under a group alias.
 func NewController(
-	gvr schema.GroupVersionResource,
+	consumerGVR, providerGVR schema.GroupVersionResource,
 	providerNamespace string,
+	isolation kubebindv1alpha1.Isolation,
+	bindingName string,
+	statusSync *kubebindv1alpha1.StatusSyncPolicy,
+	syncedMaxStaleness time.Duration,
+	fieldValidation kubebindv1alpha1.FieldValidationPolicy,
 	consumerConfig, providerConfig *rest.Config,
 	consumerDynamicInformer informers.GenericInformer,
 	providerDynamicInformer multinsinformer.GetterInformer,
 	serviceNamespaceInformer dynamic.Informer[bindlisters.APIServiceNamespaceLister],
+	snap *snapshot.Snapshot,
+	tune tuning.Controller,
+	events *syncevents.Recorder,
+	rateLimiter tuning.RateLimiter,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
-	consumerConfig = rest.CopyConfig(consumerConfig)
+	consumerConfig = tune.Config(consumerConfig)
 	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
 
-	providerConfig = rest.CopyConfig(providerConfig)
+	providerConfig = tune.Config(providerConfig)
 	providerConfig = rest.AddUserAgent(providerConfig, controllerName)
 
 	consumerClient, err := dynamicclient.NewForConfig(consumerConfig)
@@ -74,11 +92,17 @@ func NewController(
 		return nil, err
 	}
 
-	dynamicConsumerLister := dynamiclister.New(consumerDynamicInformer.Informer().GetIndexer(), gvr)
+	strict := fieldValidation == kubebindv1alpha1.FieldValidationPolicyStrict
+	var consumerFieldValidation string
+	if strict {
+		consumerFieldValidation = metav1.FieldValidationStrict
+	}
+
+	dynamicConsumerLister := dynamiclister.New(consumerDynamicInformer.Informer().GetIndexer(), consumerGVR)
 	c := &controller{
 		queue: queue,
 
-		gvr:               gvr,
+		consumerGVR:       consumerGVR,
 		providerNamespace: providerNamespace,
 
 		consumerClient: consumerClient,
@@ -90,8 +114,18 @@ func NewController(
 		providerDynamicInformer: providerDynamicInformer,
 
 		serviceNamespaceInformer: serviceNamespaceInformer,
+		isolation:                isolation,
+
+		snapshot: snap,
 
 		reconciler: reconciler{
+			bindingName:        bindingName,
+			statusSync:         statusSync,
+			syncedMaxStaleness: syncedMaxStaleness,
+			strict:             strict,
+			reportPruned:       fieldValidation == kubebindv1alpha1.FieldValidationPolicyPruneAndReport,
+			events:             events,
+
 			getServiceNamespace: func(upstreamNamespace string) (*kubebindv1alpha1.APIServiceNamespace, error) {
 				sns, err := serviceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, upstreamNamespace)
 				if err != nil {
@@ -105,36 +139,58 @@ func NewController(
 			getConsumerObject: func(ns, name string) (*unstructured.Unstructured, error) {
 				return dynamicConsumerLister.Namespace(ns).Get(name)
 			},
+			updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+				return consumerClient.Resource(consumerGVR).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
+			},
 			updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-				return consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+				return consumerClient.Resource(consumerGVR).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{FieldValidation: consumerFieldValidation})
 			},
 			deleteProviderObject: func(ctx context.Context, ns, name string) error {
-				return providerClient.Resource(gvr).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
+				return providerClient.Resource(providerGVR).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
+			requeue: func(obj *unstructured.Unstructured, after time.Duration) error {
+				key, err := cache.MetaNamespaceKeyFunc(obj)
+				if err != nil {
+					return err
+				}
+				queue.AddAfter(key, after)
+				return nil
+			},
+			recordInSync: func(downstream, upstream *unstructured.Unstructured) {
+				key, err := cache.MetaNamespaceKeyFunc(upstream)
+				if err != nil {
+					runtime.HandleError(err)
+					return
+				}
+				snap.Record(key, downstream.GetResourceVersion(), upstream.GetResourceVersion())
 			},
 		},
 	}
 
 	consumerDynamicInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			c.enqueueConsumer(logger, obj)
+			c.enqueueConsumer(logger, obj, true)
 		},
 		UpdateFunc: func(_, newObj interface{}) {
-			c.enqueueConsumer(logger, newObj)
+			c.enqueueConsumer(logger, newObj, false)
 		},
 		DeleteFunc: func(obj interface{}) {
-			c.enqueueConsumer(logger, obj)
+			c.enqueueConsumer(logger, obj, false)
 		},
 	})
 
 	providerDynamicInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			c.enqueueProvider(logger, obj)
+			c.enqueueProvider(logger, obj, true)
 		},
 		UpdateFunc: func(_, newObj interface{}) {
-			c.enqueueProvider(logger, newObj)
+			c.enqueueProvider(logger, newObj, false)
 		},
 		DeleteFunc: func(obj interface{}) {
-			c.enqueueProvider(logger, obj)
+			if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				c.snapshot.Forget(key)
+			}
+			c.enqueueProvider(logger, obj, false)
 		},
 	})
 
@@ -145,7 +201,7 @@ func NewController(
 type controller struct {
 	queue workqueue.RateLimitingInterface
 
-	gvr               schema.GroupVersionResource
+	consumerGVR       schema.GroupVersionResource
 	providerNamespace string
 
 	consumerClient, providerClient dynamicclient.Interface
@@ -157,10 +213,17 @@ type controller struct {
 
 	serviceNamespaceInformer dynamic.Informer[bindlisters.APIServiceNamespaceLister]
 
+	// isolation decides whether consumer objects have their own APIServiceNamespace.
+	isolation kubebindv1alpha1.Isolation
+
+	// snapshot skips objects of initial add events that did not change since the
+	// konnector last found them in sync.
+	snapshot *snapshot.Snapshot
+
 	reconciler
 }
 
-func (c *controller) enqueueProvider(logger klog.Logger, obj interface{}) {
+func (c *controller) enqueueProvider(logger klog.Logger, obj interface{}, added bool) {
 	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
 		runtime.HandleError(err)
@@ -177,9 +240,13 @@ func (c *controller) enqueueProvider(logger klog.Logger, obj interface{}) {
 			runtime.HandleError(err)
 			return
 		}
-		for _, obj := range sns {
-			sns := obj.(*kubebindv1alpha1.APIServiceNamespace)
-			if sns.Namespace == c.providerNamespace {
+		for _, o := range sns {
+			sn := o.(*kubebindv1alpha1.APIServiceNamespace)
+			if sn.Namespace == c.providerNamespace {
+				if added && c.snapshot.ProviderUnchanged(key, snapshot.ResourceVersion(obj)) {
+					logger.V(3).Info("skipping Unstructured unchanged since snapshot", "key", key)
+					return
+				}
 				logger.V(2).Info("queueing Unstructured", "key", key)
 				c.queue.Add(key)
 				return
@@ -189,7 +256,7 @@ func (c *controller) enqueueProvider(logger klog.Logger, obj interface{}) {
 	}
 }
 
-func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}) {
+func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}, added bool) {
 	upstreamKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
 		runtime.HandleError(err)
@@ -202,7 +269,7 @@ func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}) {
 	}
 
 	if ns != "" {
-		sn, err := c.serviceNamespaceInformer.Lister().APIServiceNamespaces(ns).Get(name)
+		sn, err := c.serviceNamespaceInformer.Lister().APIServiceNamespaces(c.providerNamespace).Get(kubebindhelpers.ServiceNamespaceName(c.isolation, ns, name))
 		if err != nil {
 			if !errors.IsNotFound(err) {
 				runtime.HandleError(err)
@@ -211,6 +278,10 @@ func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}) {
 		}
 		if sn.Namespace == c.providerNamespace && sn.Status.Namespace != "" {
 			key := fmt.Sprintf("%s/%s", sn.Status.Namespace, name)
+			if added && c.snapshot.ConsumerUnchanged(key, snapshot.ResourceVersion(obj)) {
+				logger.V(3).Info("skipping Unstructured unchanged since snapshot", "key", key)
+				return
+			}
 			logger.V(2).Info("queueing Unstructured", "key", key)
 			c.queue.Add(key)
 			return
@@ -218,11 +289,15 @@ func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}) {
 		return
 	}
 
+	if added && c.snapshot.ConsumerUnchanged(upstreamKey, snapshot.ResourceVersion(obj)) {
+		logger.V(3).Info("skipping Unstructured unchanged since snapshot", "key", upstreamKey)
+		return
+	}
 	logger.V(2).Info("queueing Unstructured", "key", upstreamKey)
 	c.queue.Add(upstreamKey)
 }
 
-func (c *controller) enqueueServiceNamespace(logger klog.Logger, obj interface{}) {
+func (c *controller) enqueueServiceNamespace(logger klog.Logger, obj interface{}, added bool) {
 	snKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
 		runtime.HandleError(err)
@@ -256,6 +331,9 @@ func (c *controller) enqueueServiceNamespace(logger klog.Logger, obj interface{}
 			runtime.HandleError(err)
 			continue
 		}
+		if added && c.snapshot.ProviderUnchanged(key, snapshot.ResourceVersion(obj)) {
+			continue
+		}
 		logger.V(2).Info("queueing Unstructured", "key", key, "reason", "APIServiceNamespace", "ServiceNamespaceKey", key)
 		c.queue.Add(key)
 	}
@@ -273,16 +351,18 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 
 	c.serviceNamespaceInformer.Informer().AddDynamicEventHandler(ctx, controllerName, cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			c.enqueueServiceNamespace(logger, obj)
+			c.enqueueServiceNamespace(logger, obj, true)
 		},
 		UpdateFunc: func(_, newObj interface{}) {
-			c.enqueueServiceNamespace(logger, newObj)
+			c.enqueueServiceNamespace(logger, newObj, false)
 		},
 		DeleteFunc: func(obj interface{}) {
-			c.enqueueServiceNamespace(logger, obj)
+			c.enqueueServiceNamespace(logger, obj, false)
 		},
 	})
 
+	go c.snapshot.Run(ctx, snapshotSaveInterval)
+
 	for i := 0; i < numThreads; i++ {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
@@ -305,15 +385,26 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	}
 	key := k.(string)
 
-	logger := klog.FromContext(ctx).WithValues("key", key)
-	ctx = klog.NewContext(ctx, logger)
-	logger.V(2).Info("processing key")
-
 	// No matter what, tell the queue we're done with this key, to unblock
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	logger := klog.FromContext(ctx).WithValues("key", key)
+	ctx = klog.NewContext(ctx, logger)
+
+	start := time.Now()
+	started, err := drain.Process(ctx, func(ctx context.Context) error {
+		logger.V(2).Info("processing key")
+		ctx, end := tracing.StartSync(ctx, "StatusDownsync", c.bindingName, c.consumerGVR, key)
+		err := c.process(ctx, key)
+		end(err)
+		return err
+	})
+	if !started {
+		return false // shutting down, don't start new work
+	}
+	recordSync(start, err)
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		c.queue.AddRateLimited(key)
 		return true
@@ -370,7 +461,7 @@ func (c *controller) removeDownstreamFinalizer(ctx context.Context, obj *unstruc
 		obj = obj.DeepCopy()
 		obj.SetFinalizers(finalizers)
 		var err error
-		if obj, err = c.consumerClient.Resource(c.gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{}); err != nil && !errors.IsNotFound(err) {
+		if obj, err = c.consumerClient.Resource(c.consumerGVR).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{}); err != nil && !errors.IsNotFound(err) {
 			return nil, err
 		}
 	}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindfake "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned/fake"
+	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions"
+	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/indexers"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/snapshot"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+)
+
+func TestEnqueueProviderSnapshot(t *testing.T) {
+	snap, err := snapshot.Open(filepath.Join(t.TempDir(), "snapshot"), "fingerprint")
+	require.NoError(t, err)
+	snap.Record("kube-bind-abc-default/foo", "3", "5")
+
+	informer := bindinformers.NewSharedInformerFactory(bindfake.NewSimpleClientset(), 0).KubeBind().V1alpha1().APIServiceNamespaces()
+	require.NoError(t, informer.Informer().AddIndexers(cache.Indexers{
+		indexers.ServiceNamespaceByNamespace: indexers.IndexServiceNamespaceByNamespace,
+	}))
+	require.NoError(t, informer.Informer().GetIndexer().Add(&kubebindv1alpha1.APIServiceNamespace{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster-abc", Name: "default", ResourceVersion: "9"},
+		Status:     kubebindv1alpha1.APIServiceNamespaceStatus{Namespace: "kube-bind-abc-default"},
+	}))
+
+	tests := []struct {
+		name            string
+		resourceVersion string
+		added           bool
+		wantQueued      bool
+	}{
+		{name: "unchanged since snapshot", resourceVersion: "5", added: true},
+		{name: "changed since snapshot", resourceVersion: "6", added: true, wantQueued: true},
+		{name: "unchanged, but updated", resourceVersion: "5", wantQueued: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &controller{
+				queue:                    workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+				providerNamespace:        "cluster-abc",
+				serviceNamespaceInformer: dynamic.NewDynamicInformer[bindlisters.APIServiceNamespaceLister](informer),
+				snapshot:                 snap,
+			}
+			defer c.queue.ShutDown()
+
+			obj := &unstructured.Unstructured{}
+			obj.SetNamespace("kube-bind-abc-default")
+			obj.SetName("foo")
+			obj.SetResourceVersion(tt.resourceVersion)
+			c.enqueueProvider(klog.Background(), obj, tt.added)
+
+			if !tt.wantQueued {
+				require.Zero(t, c.queue.Len())
+				return
+			}
+			require.Equal(t, 1, c.queue.Len())
+			key, _ := c.queue.Get()
+			require.Equal(t, "kube-bind-abc-default/foo", key)
+		})
+	}
+}
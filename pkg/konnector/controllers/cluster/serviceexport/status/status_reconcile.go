@@ -19,22 +19,49 @@ package status
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/klog/v2"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/providermessage"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/pruning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncevents"
 )
 
 type reconciler struct {
+	bindingName string
+	statusSync  *kubebindv1alpha1.StatusSyncPolicy
+
+	// syncedMaxStaleness enables the synced condition on downstream objects if non-zero.
+	syncedMaxStaleness time.Duration
+
+	// strict makes the consumer cluster reject upstream status with unknown fields
+	// instead of pruning it.
+	strict bool
+	// reportPruned records the status fields the consumer cluster pruned on the downstream object.
+	reportPruned bool
+
 	getServiceNamespace func(upstreamNamespace string) (*kubebindv1alpha1.APIServiceNamespace, error)
 
 	getConsumerObject          func(ns, name string) (*unstructured.Unstructured, error)
+	updateConsumerObject       func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
 	updateConsumerObjectStatus func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
 
 	deleteProviderObject func(ctx context.Context, ns, name string) error
+
+	requeue func(obj *unstructured.Unstructured, after time.Duration) error
+
+	// recordInSync is called with downstream and upstream objects found in sync.
+	recordInSync func(downstream, upstream *unstructured.Unstructured)
+
+	// events records failed status downsyncs on the consumer objects and the APIServiceBinding.
+	events *syncevents.Recorder
 }
 
 // reconcile syncs upstream status to consumer objects.
@@ -47,11 +74,11 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		if err != nil && !errors.IsNotFound(err) {
 			return err
 		} else if errors.IsNotFound(err) {
-			runtime.HandleError(err)
+			utilruntime.HandleError(err)
 			return err // hoping the APIServiceNamespace will be created soon. Otherwise, this item goes into backoff.
 		}
 		if sn.Status.Namespace == "" {
-			runtime.HandleError(err)
+			utilruntime.HandleError(err)
 			return err // hoping the status is set soon.
 		}
 
@@ -59,10 +86,10 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		ctx = klog.NewContext(ctx, logger)
 
 		// continue with downstream namespace
-		ns = sn.Name
+		ns = kubebindhelpers.ConsumerNamespace(sn.Name)
 	}
 
-	downstream, err := r.getConsumerObject(ns, obj.GetName())
+	orig, err := r.getConsumerObject(ns, obj.GetName())
 	if err != nil && !errors.IsNotFound(err) {
 		logger.Info("failed to get downstream object", "error", err, "downstreamNamespace", ns, "downstreamName", obj.GetName())
 		return err
@@ -76,27 +103,154 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		return nil
 	}
 
-	orig := downstream
-	downstream = downstream.DeepCopy()
 	status, found, err := unstructured.NestedFieldNoCopy(obj.Object, "status")
 	if err != nil {
-		runtime.HandleError(err)
+		utilruntime.HandleError(err)
 		return nil // nothing we can do here
 	}
 	if found {
-		if err := unstructured.SetNestedField(downstream.Object, status, "status"); err != nil {
-			runtime.HandleError(err)
+		var fresh bool
+		if status, fresh = translateGeneration(obj, orig, status); !fresh {
+			logger.V(2).Info("Skipping stale upstream status", "downstreamNamespace", ns, "downstreamName", obj.GetName())
+			return nil
+		}
+		status, found = filterStatus(status, r.statusSync)
+	}
+
+	var synced map[string]interface{}
+	var after time.Duration
+	if r.syncedMaxStaleness > 0 {
+		synced, after = syncedCondition(orig, obj, r.syncedMaxStaleness, time.Now())
+		recordFreshness(r.bindingName, getDownstreamCondition(orig, kubebindv1alpha1.SyncedConditionType), synced)
+		if after > 0 {
+			if err := r.requeue(obj, after); err != nil {
+				return err
+			}
+		}
+	}
+
+	maxSize := maxStatusSize(r.statusSync)
+	for {
+		downstream, truncated, err := r.downstreamWithStatus(orig, obj, status, found, maxSize, synced)
+		if err != nil {
+			utilruntime.HandleError(err)
 			return nil // nothing we can do here
 		}
+		if reflect.DeepEqual(orig, downstream) {
+			if after == 0 {
+				r.recordInSync(orig, obj)
+			}
+			return nil
+		}
+
+		logger.Info("Updating downstream object status", "downstreamNamespace", ns, "downstreamName", obj.GetName(), "truncated", truncated)
+		if stored, err := r.updateConsumerObjectStatus(ctx, downstream); err == nil {
+			return r.ensurePrunedFields(ctx, downstream, stored)
+		} else if r.strict && errors.IsBadRequest(err) {
+			logger.Info("Downstream object rejected upstream status", "downstreamNamespace", ns, "downstreamName", obj.GetName(), "error", err)
+			return r.ensureSchemaMismatch(ctx, orig, err)
+		} else if !errors.IsRequestEntityTooLargeError(err) || maxSize < minTruncatedStringLength {
+			r.events.SyncFailed(orig, syncevents.ReasonStatusDownsyncFailed, err)
+			return err
+		}
+
+		// the consumer cluster has a lower limit than expected. Try again with a smaller status.
+		maxSize /= 2
+		logger.Info("Downstream object status too large, truncating further", "maxSize", maxSize)
+	}
+}
+
+// downstreamWithStatus returns a copy of the downstream object with the given upstream status,
+// truncated to maxSize, and the provider message, truncation and synced conditions. If synced
+// is nil, the synced condition is removed.
+func (r *reconciler) downstreamWithStatus(orig, upstream *unstructured.Unstructured, status interface{}, found bool, maxSize int, synced map[string]interface{}) (*unstructured.Unstructured, bool, error) {
+	downstream := orig.DeepCopy()
+
+	var truncated bool
+	var originalSize int
+	if found {
+		var policy kubebindv1alpha1.StatusTruncationPolicy
+		if r.statusSync != nil {
+			policy = r.statusSync.Truncation
+		}
+		status, originalSize, truncated = truncateStatus(status, maxSize, policy)
+		found = status != nil
+	}
+	if found {
+		if err := unstructured.SetNestedField(downstream.Object, status, "status"); err != nil {
+			return nil, false, err
+		}
 	} else {
 		unstructured.RemoveNestedField(downstream.Object, "status")
 	}
-	if !reflect.DeepEqual(orig, downstream) {
-		logger.Info("Updating downstream object status", "downstreamNamespace", ns, "downstreamName", obj.GetName())
-		if _, err := r.updateConsumerObjectStatus(ctx, downstream); err != nil {
-			return err
+
+	if err := r.ensureProviderMessage(orig, upstream, downstream); err != nil {
+		return nil, false, err
+	}
+	if err := ensureTruncatedCondition(orig, downstream, truncated, originalSize, maxSize); err != nil {
+		return nil, false, err
+	}
+	if synced == nil {
+		if err := removeDownstreamCondition(downstream, kubebindv1alpha1.SyncedConditionType); err != nil {
+			return nil, false, err
 		}
+	} else if err := setDownstreamCondition(orig, downstream, runtime.DeepCopyJSON(synced)); err != nil {
+		return nil, false, err
 	}
 
-	return nil
+	return downstream, truncated, nil
+}
+
+// ensurePrunedFields records the status fields the consumer cluster dropped from the
+// sent downstream object in an annotation, with PruneAndReport field validation.
+// Otherwise, a left-over annotation is removed.
+func (r *reconciler) ensurePrunedFields(ctx context.Context, sent, stored *unstructured.Unstructured) error {
+	var pruned []string
+	if status, found, _ := unstructured.NestedFieldNoCopy(sent.Object, "status"); found && r.reportPruned {
+		pruned = pruning.Fields("", map[string]interface{}{"status": status}, stored.Object)
+	}
+
+	downstream := stored.DeepCopy()
+	if !pruning.Annotate(downstream, kubebindv1alpha1.PrunedDownstreamFieldsAnnotationKey, pruned) {
+		return nil
+	}
+	klog.FromContext(ctx).V(1).Info("Updating downstream object pruned fields", "fields", pruned)
+	_, err := r.updateConsumerObject(ctx, downstream)
+	return err
+}
+
+// ensureSchemaMismatch records the rejection of the upstream status by the consumer
+// cluster in the provider message condition of the downstream object, keeping its
+// current status. A message recorded by the spec controller takes precedence.
+func (r *reconciler) ensureSchemaMismatch(ctx context.Context, orig *unstructured.Unstructured, rejection error) error {
+	previous := providermessage.Get(orig)
+	if previous != nil && providermessage.IsOwnedBySpec(previous) {
+		return nil
+	}
+
+	downstream := orig.DeepCopy()
+	if err := providermessage.Set(downstream, previous, providermessage.DownstreamSchemaMismatchReason, rejection.Error()); err != nil {
+		return err
+	}
+	if reflect.DeepEqual(orig, downstream) {
+		return nil
+	}
+	_, err := r.updateConsumerObjectStatus(ctx, downstream)
+	return err
+}
+
+// ensureProviderMessage maps failures reported by the provider on the upstream object
+// to the provider message condition of the downstream object. A message recorded
+// by the spec controller is kept until the spec controller clears it.
+func (r *reconciler) ensureProviderMessage(orig, upstream, downstream *unstructured.Unstructured) error {
+	previous := providermessage.Get(orig)
+	if reason, message, found := providermessage.FromUpstream(upstream); found {
+		return providermessage.Set(downstream, previous, reason, message)
+	}
+	if previous != nil && providermessage.IsOwnedBySpec(previous) {
+		reason, _ := previous["reason"].(string)
+		message, _ := previous["message"].(string)
+		return providermessage.Set(downstream, previous, reason, message)
+	}
+	return providermessage.Remove(downstream)
 }
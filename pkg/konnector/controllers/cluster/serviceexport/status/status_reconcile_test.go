@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster/serviceexport/providermessage"
+)
+
+func TestEnsureSchemaMismatch(t *testing.T) {
+	rejection := apierrors.NewBadRequest(`strict decoding error: unknown field "status.replicas"`)
+	object := func(reason, message string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"phase": "Running"},
+		}}
+		if reason != "" {
+			require.NoError(t, providermessage.Set(obj, nil, reason, message))
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name       string
+		orig       *unstructured.Unstructured
+		wantReason string
+	}{
+		{
+			name:       "no message",
+			orig:       object("", ""),
+			wantReason: providermessage.DownstreamSchemaMismatchReason,
+		},
+		{
+			name:       "upstream failure replaced",
+			orig:       object("Failed", "Ready: backend down"),
+			wantReason: providermessage.DownstreamSchemaMismatchReason,
+		},
+		{
+			name: "same message kept",
+			orig: object(providermessage.DownstreamSchemaMismatchReason, rejection.Error()),
+		},
+		{
+			name: "spec controller message kept",
+			orig: object(providermessage.UpstreamRejectedReason, "denied"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *unstructured.Unstructured
+			r := &reconciler{
+				strict: true,
+				updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					updated = obj
+					return obj, nil
+				},
+			}
+			require.NoError(t, r.ensureSchemaMismatch(context.Background(), tt.orig, rejection))
+			if tt.wantReason == "" {
+				require.Nil(t, updated)
+				return
+			}
+			require.NotNil(t, updated)
+			cond := providermessage.Get(updated)
+			require.Equal(t, tt.wantReason, cond["reason"])
+			require.Equal(t, rejection.Error(), cond["message"])
+			require.Equal(t, tt.orig.Object["status"].(map[string]interface{})["phase"], updated.Object["status"].(map[string]interface{})["phase"])
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// filterStatus returns a copy of the upstream status with the fields selected by
+// the policy. Without policy, the status is returned as is.
+func filterStatus(status interface{}, policy *kubebindv1alpha1.StatusSyncPolicy) (interface{}, bool) {
+	if policy == nil || (len(policy.Include) == 0 && len(policy.Exclude) == 0) {
+		return status, true
+	}
+
+	obj := map[string]interface{}{"status": runtime.DeepCopyJSONValue(status)}
+	if len(policy.Include) > 0 {
+		included := map[string]interface{}{}
+		for _, path := range policy.Include {
+			fields := fieldPath(path)
+			value, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+			if err != nil || !found {
+				continue
+			}
+			_ = unstructured.SetNestedField(included, value, fields...) // nolint: errcheck
+		}
+		obj = included
+	}
+	for _, path := range policy.Exclude {
+		unstructured.RemoveNestedField(obj, fieldPath(path)...)
+	}
+
+	ret, found := obj["status"]
+	return ret, found
+}
+
+// fieldPath turns a simple JSONPath like `{.status.foo}` into its fields.
+func fieldPath(path kubebindv1alpha1.StatusFieldPath) []string {
+	p := strings.TrimSuffix(strings.TrimPrefix(string(path), "{"), "}")
+	p = strings.TrimPrefix(strings.TrimPrefix(p, "$"), ".")
+	return strings.Split(p, ".")
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestFilterStatus(t *testing.T) {
+	status := func() map[string]interface{} {
+		return map[string]interface{}{
+			"phase": "Running",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+			"diagnostics": map[string]interface{}{
+				"traces":  []interface{}{"a", "b"},
+				"summary": "ok",
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		policy    *kubebindv1alpha1.StatusSyncPolicy
+		want      interface{}
+		wantFound bool
+	}{
+		{
+			name:      "no policy",
+			want:      status(),
+			wantFound: true,
+		},
+		{
+			name: "include",
+			policy: &kubebindv1alpha1.StatusSyncPolicy{
+				Include: []kubebindv1alpha1.StatusFieldPath{".status.phase", ".status.diagnostics.summary", ".status.missing"},
+			},
+			want: map[string]interface{}{
+				"phase":       "Running",
+				"diagnostics": map[string]interface{}{"summary": "ok"},
+			},
+			wantFound: true,
+		},
+		{
+			name: "exclude",
+			policy: &kubebindv1alpha1.StatusSyncPolicy{
+				Exclude: []kubebindv1alpha1.StatusFieldPath{".status.diagnostics.traces", ".status.phase"},
+			},
+			want: map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				},
+				"diagnostics": map[string]interface{}{"summary": "ok"},
+			},
+			wantFound: true,
+		},
+		{
+			name: "include and exclude",
+			policy: &kubebindv1alpha1.StatusSyncPolicy{
+				Include: []kubebindv1alpha1.StatusFieldPath{"{.status.diagnostics}"},
+				Exclude: []kubebindv1alpha1.StatusFieldPath{".status.diagnostics.traces"},
+			},
+			want: map[string]interface{}{
+				"diagnostics": map[string]interface{}{"summary": "ok"},
+			},
+			wantFound: true,
+		},
+		{
+			name: "nothing included",
+			policy: &kubebindv1alpha1.StatusSyncPolicy{
+				Include: []kubebindv1alpha1.StatusFieldPath{".status.missing"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := status()
+			got, found := filterStatus(upstream, tt.policy)
+			require.Equal(t, tt.wantFound, found)
+			if tt.wantFound {
+				require.Equal(t, tt.want, got)
+			}
+			require.Equal(t, status(), upstream, "upstream status must not be mutated")
+		})
+	}
+}
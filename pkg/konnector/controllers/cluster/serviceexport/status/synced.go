@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+const (
+	syncedReason      = "Synced"
+	syncingReason     = "Syncing"
+	statusStaleReason = "StatusStale"
+)
+
+// syncedCondition computes the synced condition of the downstream object. It is true if
+// the service provider acknowledged the current spec and its status reflects it. If this
+// does not happen within maxStaleness, the reason becomes StatusStale. The returned
+// duration is when the condition has to be computed again to detect staleness.
+func syncedCondition(downstream, upstream *unstructured.Unstructured, maxStaleness time.Duration, now time.Time) (map[string]interface{}, time.Duration) {
+	generation := downstream.GetGeneration()
+
+	var acknowledged bool
+	if consumerGeneration, found := upstream.GetAnnotations()[kubebindv1alpha1.ConsumerGenerationAnnotationKey]; found {
+		acknowledged = consumerGeneration == strconv.FormatInt(generation, 10)
+	} else {
+		downstreamSpec, _, _ := unstructured.NestedFieldNoCopy(downstream.Object, "spec")
+		upstreamSpec, _, _ := unstructured.NestedFieldNoCopy(upstream.Object, "spec")
+		acknowledged = reflect.DeepEqual(downstreamSpec, upstreamSpec)
+	}
+	observedGeneration, found, _ := unstructured.NestedInt64(upstream.Object, "status", "observedGeneration")
+	observed := !found || observedGeneration == upstream.GetGeneration()
+
+	cond := map[string]interface{}{
+		"type":               kubebindv1alpha1.SyncedConditionType,
+		"observedGeneration": generation,
+	}
+	if acknowledged && observed {
+		cond["status"] = string(corev1.ConditionTrue)
+		cond["reason"] = syncedReason
+		cond["message"] = fmt.Sprintf("Generation %d is acknowledged by the service provider and the status is up to date.", generation)
+		return cond, 0
+	}
+
+	message := fmt.Sprintf("Waiting for the service provider to acknowledge generation %d.", generation)
+	if acknowledged {
+		message = fmt.Sprintf("Waiting for the service provider to update the status for generation %d.", generation)
+	}
+
+	since := now
+	stale := false
+	if previous := getDownstreamCondition(downstream, kubebindv1alpha1.SyncedConditionType); previous != nil &&
+		previous["status"] == string(corev1.ConditionFalse) && previous["observedGeneration"] == generation {
+		if previous["reason"] == statusStaleReason {
+			stale = true
+		} else if s, ok := previous["lastTransitionTime"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				since = t
+			}
+		}
+	}
+	if !stale && now.Sub(since) >= maxStaleness {
+		stale = true
+	}
+
+	cond["status"] = string(corev1.ConditionFalse)
+	if stale {
+		cond["reason"] = statusStaleReason
+		cond["message"] = fmt.Sprintf("%s Not synced for more than %s.", message, maxStaleness)
+		return cond, 0
+	}
+	cond["reason"] = syncingReason
+	cond["message"] = message
+	return cond, maxStaleness - now.Sub(since)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func newSyncedObjects(generation int64, consumerGeneration string, previous map[string]interface{}) (downstream, upstream *unstructured.Unstructured) {
+	downstream = &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"tier": "Dedicated"},
+	}}
+	downstream.SetName("foo")
+	downstream.SetGeneration(generation)
+	if previous != nil {
+		downstream.Object["status"] = map[string]interface{}{"conditions": []interface{}{previous}}
+	}
+
+	upstream = &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"tier": "Dedicated"},
+		"status": map[string]interface{}{"phase": "Running", "observedGeneration": int64(7)},
+	}}
+	upstream.SetName("foo")
+	upstream.SetGeneration(7)
+	if consumerGeneration != "" {
+		upstream.SetAnnotations(map[string]string{kubebindv1alpha1.ConsumerGenerationAnnotationKey: consumerGeneration})
+	}
+	return downstream, upstream
+}
+
+func syncedFalse(reason string, generation int64, since time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"type":               kubebindv1alpha1.SyncedConditionType,
+		"status":             "False",
+		"reason":             reason,
+		"observedGeneration": generation,
+		"lastTransitionTime": since.UTC().Format(time.RFC3339),
+	}
+}
+
+func TestSyncedCondition(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	maxStaleness := time.Minute
+
+	tests := []struct {
+		name               string
+		generation         int64
+		consumerGeneration string
+		previous           map[string]interface{}
+		upstream           func(upstream *unstructured.Unstructured)
+		wantStatus         string
+		wantReason         string
+		wantMessage        string
+		wantAfter          time.Duration
+	}{
+		{
+			name:               "in sync",
+			generation:         2,
+			consumerGeneration: "2",
+			wantStatus:         "True",
+			wantReason:         syncedReason,
+			wantMessage:        "Generation 2 is acknowledged by the service provider and the status is up to date.",
+		},
+		{
+			name:        "in sync by spec without consumer generation",
+			generation:  2,
+			wantStatus:  "True",
+			wantReason:  syncedReason,
+			wantMessage: "Generation 2 is acknowledged by the service provider and the status is up to date.",
+		},
+		{
+			name:               "in sync after being stale",
+			generation:         2,
+			consumerGeneration: "2",
+			previous:           syncedFalse(statusStaleReason, 2, now.Add(-time.Hour)),
+			wantStatus:         "True",
+			wantReason:         syncedReason,
+			wantMessage:        "Generation 2 is acknowledged by the service provider and the status is up to date.",
+		},
+		{
+			name:               "provider never acknowledged",
+			generation:         2,
+			consumerGeneration: "1",
+			wantStatus:         "False",
+			wantReason:         syncingReason,
+			wantMessage:        "Waiting for the service provider to acknowledge generation 2.",
+			wantAfter:          maxStaleness,
+		},
+		{
+			name:               "provider never acknowledged, still within max staleness",
+			generation:         2,
+			consumerGeneration: "1",
+			previous:           syncedFalse(syncingReason, 2, now.Add(-20*time.Second)),
+			wantStatus:         "False",
+			wantReason:         syncingReason,
+			wantMessage:        "Waiting for the service provider to acknowledge generation 2.",
+			wantAfter:          40 * time.Second,
+		},
+		{
+			name:               "stale past max staleness",
+			generation:         2,
+			consumerGeneration: "1",
+			previous:           syncedFalse(syncingReason, 2, now.Add(-2*time.Minute)),
+			wantStatus:         "False",
+			wantReason:         statusStaleReason,
+			wantMessage:        "Waiting for the service provider to acknowledge generation 2. Not synced for more than 1m0s.",
+		},
+		{
+			name:               "stays stale",
+			generation:         2,
+			consumerGeneration: "1",
+			previous:           syncedFalse(statusStaleReason, 2, now.Add(-time.Second)),
+			wantStatus:         "False",
+			wantReason:         statusStaleReason,
+			wantMessage:        "Waiting for the service provider to acknowledge generation 2. Not synced for more than 1m0s.",
+		},
+		{
+			name:               "new generation restarts the clock",
+			generation:         3,
+			consumerGeneration: "2",
+			previous:           syncedFalse(statusStaleReason, 2, now.Add(-time.Hour)),
+			wantStatus:         "False",
+			wantReason:         syncingReason,
+			wantMessage:        "Waiting for the service provider to acknowledge generation 3.",
+			wantAfter:          maxStaleness,
+		},
+		{
+			name:               "status not observed yet",
+			generation:         2,
+			consumerGeneration: "2",
+			upstream: func(upstream *unstructured.Unstructured) {
+				upstream.SetGeneration(8)
+			},
+			wantStatus:  "False",
+			wantReason:  syncingReason,
+			wantMessage: "Waiting for the service provider to update the status for generation 2.",
+			wantAfter:   maxStaleness,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downstream, upstream := newSyncedObjects(tt.generation, tt.consumerGeneration, tt.previous)
+			if tt.upstream != nil {
+				tt.upstream(upstream)
+			}
+
+			cond, after := syncedCondition(downstream, upstream, maxStaleness, now)
+			require.Equal(t, kubebindv1alpha1.SyncedConditionType, cond["type"])
+			require.Equal(t, tt.generation, cond["observedGeneration"])
+			require.Equal(t, tt.wantStatus, cond["status"])
+			require.Equal(t, tt.wantReason, cond["reason"])
+			require.Equal(t, tt.wantMessage, cond["message"])
+			require.Equal(t, tt.wantAfter, after)
+		})
+	}
+}
+
+func TestReconcileSyncedDisabled(t *testing.T) {
+	downstream, upstream := newSyncedObjects(2, "1", syncedFalse(statusStaleReason, 2, time.Now().Add(-time.Hour)))
+
+	var updated *unstructured.Unstructured
+	r := &reconciler{
+		getConsumerObject: func(ns, name string) (*unstructured.Unstructured, error) {
+			return downstream, nil
+		},
+		updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			updated = obj
+			return obj, nil
+		},
+		requeue: func(obj *unstructured.Unstructured, after time.Duration) error {
+			t.Fatalf("unexpected requeue after %s with max staleness zero", after)
+			return nil
+		},
+		recordInSync: func(downstream, upstream *unstructured.Unstructured) {},
+	}
+
+	err := r.reconcile(context.Background(), upstream)
+	require.NoError(t, err)
+	require.NotNil(t, updated)
+	require.Nil(t, getDownstreamCondition(updated, kubebindv1alpha1.SyncedConditionType), "zero max staleness should remove the synced condition")
+	require.Equal(t, "Running", updated.Object["status"].(map[string]interface{})["phase"])
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+const (
+	// defaultMaxStatusSize leaves room for spec and metadata below the etcd request limit of 1.5MiB.
+	defaultMaxStatusSize = 1 << 20
+
+	// minTruncatedStringLength is the length below which strings are not truncated.
+	minTruncatedStringLength = 64
+
+	// statusTooLargeReason is the reason of the status truncated condition.
+	statusTooLargeReason = "StatusTooLarge"
+)
+
+// maxStatusSize returns the status size limit of the policy.
+func maxStatusSize(policy *kubebindv1alpha1.StatusSyncPolicy) int {
+	if policy == nil || policy.MaxSize == nil || policy.MaxSize.Value() <= 0 {
+		return defaultMaxStatusSize
+	}
+	return int(policy.MaxSize.Value())
+}
+
+// truncateStatus shortens the status until its JSON serialization fits into maxSize.
+// Arrays are halved first, largest first, then long strings, and finally whole fields
+// are dropped. The original size is returned along with whether truncation happened.
+func truncateStatus(status interface{}, maxSize int, policy kubebindv1alpha1.StatusTruncationPolicy) (ret interface{}, originalSize int, truncated bool) {
+	originalSize = jsonSize(status)
+	if originalSize <= maxSize {
+		return status, originalSize, false
+	}
+
+	root := map[string]interface{}{"status": runtime.DeepCopyJSONValue(status)}
+	for jsonSize(root["status"]) > maxSize {
+		var arrays, strs []node
+		collect(root["status"], func(v interface{}) { root["status"] = v }, &arrays, &strs)
+
+		if n := largest(arrays); n != nil {
+			a := n.value.([]interface{})
+			if policy == kubebindv1alpha1.StatusTruncationKeepHead {
+				n.set(a[:len(a)/2])
+			} else {
+				n.set(a[len(a)-len(a)/2:])
+			}
+			continue
+		}
+		if n := largest(strs); n != nil {
+			s := n.value.(string)
+			n.set(s[:len(s)/2] + "...")
+			continue
+		}
+
+		// drop the largest field
+		m, ok := root["status"].(map[string]interface{})
+		if !ok || len(m) == 0 {
+			return nil, originalSize, true
+		}
+		var largestKey string
+		largestSize := -1
+		for k, v := range m {
+			if size := jsonSize(v); size > largestSize {
+				largestKey, largestSize = k, size
+			}
+		}
+		delete(m, largestKey)
+	}
+
+	return root["status"], originalSize, true
+}
+
+type node struct {
+	value interface{}
+	size  int
+	set   func(interface{})
+}
+
+// collect finds all non-empty arrays and long strings in v.
+func collect(v interface{}, set func(interface{}), arrays, strs *[]node) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			k := k
+			collect(v[k], func(nv interface{}) { v[k] = nv }, arrays, strs)
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			*arrays = append(*arrays, node{value: v, size: jsonSize(v), set: set})
+		}
+		for i := range v {
+			i := i
+			collect(v[i], func(nv interface{}) { v[i] = nv }, arrays, strs)
+		}
+	case string:
+		if len(v) > minTruncatedStringLength {
+			*strs = append(*strs, node{value: v, size: len(v), set: set})
+		}
+	}
+}
+
+func largest(nodes []node) *node {
+	var ret *node
+	for i := range nodes {
+		if ret == nil || nodes[i].size > ret.size {
+			ret = &nodes[i]
+		}
+	}
+	return ret
+}
+
+func jsonSize(v interface{}) int {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(bs)
+}
+
+// ensureTruncatedCondition reports truncation of the status on the downstream object,
+// or removes the report if the status was not truncated.
+func ensureTruncatedCondition(orig, downstream *unstructured.Unstructured, truncated bool, originalSize, maxSize int) error {
+	if !truncated {
+		return removeDownstreamCondition(downstream, kubebindv1alpha1.StatusTruncatedConditionType)
+	}
+	return setDownstreamCondition(orig, downstream, map[string]interface{}{
+		"type":    kubebindv1alpha1.StatusTruncatedConditionType,
+		"status":  string(corev1.ConditionTrue),
+		"reason":  statusTooLargeReason,
+		"message": fmt.Sprintf("The status of the service provider object has %d bytes and was truncated to at most %d bytes.", originalSize, maxSize),
+	})
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestTruncateStatus(t *testing.T) {
+	history := func(n int) []interface{} {
+		ret := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			ret = append(ret, fmt.Sprintf("entry-%03d", i))
+		}
+		return ret
+	}
+
+	t.Run("small status is untouched", func(t *testing.T) {
+		status := map[string]interface{}{"phase": "Running"}
+		got, _, truncated := truncateStatus(status, 100, "")
+		require.False(t, truncated)
+		require.Equal(t, status, got)
+	})
+
+	t.Run("keep tail", func(t *testing.T) {
+		status := map[string]interface{}{"phase": "Running", "history": history(100)}
+		got, originalSize, truncated := truncateStatus(status, 200, kubebindv1alpha1.StatusTruncationKeepTail)
+		require.True(t, truncated)
+		require.Equal(t, jsonSize(status), originalSize)
+		require.LessOrEqual(t, jsonSize(got), 200)
+		require.Equal(t, "Running", got.(map[string]interface{})["phase"])
+		h := got.(map[string]interface{})["history"].([]interface{})
+		require.NotEmpty(t, h)
+		require.Equal(t, "entry-099", h[len(h)-1])
+		require.Len(t, status["history"], 100, "input must not be mutated")
+	})
+
+	t.Run("keep head", func(t *testing.T) {
+		status := map[string]interface{}{"history": history(100)}
+		got, _, truncated := truncateStatus(status, 200, kubebindv1alpha1.StatusTruncationKeepHead)
+		require.True(t, truncated)
+		require.LessOrEqual(t, jsonSize(got), 200)
+		require.Equal(t, "entry-000", got.(map[string]interface{})["history"].([]interface{})[0])
+	})
+
+	t.Run("long strings are shortened", func(t *testing.T) {
+		status := map[string]interface{}{"phase": "Running", "message": strings.Repeat("x", 1000)}
+		got, _, truncated := truncateStatus(status, 300, "")
+		require.True(t, truncated)
+		require.LessOrEqual(t, jsonSize(got), 300)
+		require.Equal(t, "Running", got.(map[string]interface{})["phase"])
+		require.True(t, strings.HasSuffix(got.(map[string]interface{})["message"].(string), "..."))
+	})
+
+	t.Run("fields are dropped as last resort", func(t *testing.T) {
+		status := map[string]interface{}{"phase": "Running", "replicas": int64(3)}
+		got, _, truncated := truncateStatus(status, 16, "")
+		require.True(t, truncated)
+		require.LessOrEqual(t, jsonSize(got), 16)
+	})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncevents
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindscheme "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned/scheme"
+)
+
+const (
+	// ReasonUpsyncFailed is the Event reason of a failed spec upsync.
+	ReasonUpsyncFailed = "UpsyncFailed"
+	// ReasonStatusDownsyncFailed is the Event reason of a failed status downsync.
+	ReasonStatusDownsyncFailed = "StatusDownsyncFailed"
+)
+
+// Recorder records Warning Events about failed syncs on the consumer objects and
+// on their APIServiceBinding, such that kubectl describe shows why an object is
+// stuck. Repeated failures are aggregated into Events with a count by the event
+// correlator of client-go, such that retries do not flood the consumer cluster.
+type Recorder struct {
+	broadcaster record.EventBroadcaster
+	recorder    record.EventRecorder
+	events      corev1client.EventsGetter
+	binding     *kubebindv1alpha1.APIServiceBinding
+}
+
+// NewRecorder returns a Recorder creating Events in the consumer cluster about
+// the objects of the given APIServiceBinding.
+func NewRecorder(events corev1client.EventsGetter, binding *kubebindv1alpha1.APIServiceBinding) *Recorder {
+	broadcaster := record.NewBroadcaster()
+	return &Recorder{
+		broadcaster: broadcaster,
+		recorder:    broadcaster.NewRecorder(bindscheme.Scheme, corev1.EventSource{Component: "konnector"}),
+		events:      events,
+		binding:     binding,
+	}
+}
+
+// Start sends the recorded Events to the consumer cluster until ctx is done.
+func (r *Recorder) Start(ctx context.Context) {
+	w := r.broadcaster.StartRecordingToSink(&corev1client.EventSinkImpl{Interface: r.events.Events("")})
+	<-ctx.Done()
+	w.Stop()
+	r.broadcaster.Shutdown()
+}
+
+// SyncFailed records an Event with the given reason and the error of the
+// service provider or consumer cluster on the consumer object and on the
+// APIServiceBinding. Conflicts and cancellation are not recorded, as they are
+// retried right away.
+func (r *Recorder) SyncFailed(obj *unstructured.Unstructured, reason string, err error) {
+	if r == nil || err == nil || apierrors.IsConflict(err) || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	var action string
+	switch reason {
+	case ReasonUpsyncFailed:
+		action = "upsync the spec to the service provider"
+	case ReasonStatusDownsyncFailed:
+		action = "downsync the status from the service provider"
+	default:
+		action = "sync"
+	}
+	r.recorder.Eventf(obj, corev1.EventTypeWarning, reason, "Failed to %s: %v", action, err)
+
+	name := obj.GetName()
+	if ns := obj.GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+	r.recorder.Eventf(r.binding, corev1.EventTypeWarning, reason, "Failed to %s of %s %s: %v", action, obj.GetKind(), name, err)
+}
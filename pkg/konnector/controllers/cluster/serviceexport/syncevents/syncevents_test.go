@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncevents
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestSyncFailed(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("example.com/v1")
+	obj.SetKind("MangoDB")
+	obj.SetNamespace("default")
+	obj.SetName("test")
+
+	tests := []struct {
+		name   string
+		reason string
+		err    error
+		want   []string
+	}{
+		{
+			name:   "upsync failure",
+			reason: ReasonUpsyncFailed,
+			err:    fmt.Errorf("admission webhook denied the request"),
+			want: []string{
+				"Warning UpsyncFailed Failed to upsync the spec to the service provider: admission webhook denied the request",
+				"Warning UpsyncFailed Failed to upsync the spec to the service provider of MangoDB default/test: admission webhook denied the request",
+			},
+		},
+		{
+			name:   "status downsync failure",
+			reason: ReasonStatusDownsyncFailed,
+			err:    fmt.Errorf("forbidden"),
+			want: []string{
+				"Warning StatusDownsyncFailed Failed to downsync the status from the service provider: forbidden",
+				"Warning StatusDownsyncFailed Failed to downsync the status from the service provider of MangoDB default/test: forbidden",
+			},
+		},
+		{
+			name:   "conflicts are not recorded",
+			reason: ReasonUpsyncFailed,
+			err:    apierrors.NewConflict(schema.GroupResource{Group: "example.com", Resource: "mangodbs"}, "test", fmt.Errorf("modified")),
+		},
+		{
+			name:   "cancellation is not recorded",
+			reason: ReasonStatusDownsyncFailed,
+			err:    fmt.Errorf("stopping: %w", context.Canceled),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := record.NewFakeRecorder(10)
+			r := &Recorder{
+				recorder: fake,
+				binding:  &kubebindv1alpha1.APIServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "mangodbs"}},
+			}
+			r.SyncFailed(obj, tt.reason, tt.err)
+			close(fake.Events)
+
+			var got []string
+			for e := range fake.Events {
+				got = append(got, e)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSyncFailedNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.SyncFailed(&unstructured.Unstructured{}, ReasonUpsyncFailed, fmt.Errorf("boom"))
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceexport
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+const (
+	defaultTokenExpirationSeconds = 3600
+
+	// serviceAccountTokenKey is the key of the token in the token Secrets.
+	serviceAccountTokenKey = "token"
+)
+
+// ensureServiceAccountTokens issues tokens for the accepted service account token
+// claims of the export via the TokenRequest API in the consumer cluster, and
+// stores them in Secrets next to the export. Tokens are renewed after 80% of
+// their lifetime, and Secrets of claims that are gone are deleted.
+func (r *reconciler) ensureServiceAccountTokens(ctx context.Context, export *kubebindv1alpha1.APIServiceExport) error {
+	logger := klog.FromContext(ctx)
+
+	var claims []kubebindv1alpha1.ServiceAccountTokenClaim
+	binding, err := r.getServiceBinding(export.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if err == nil {
+		claims = kubebindhelpers.AcceptedServiceAccountTokenClaims(export, binding)
+	}
+
+	previous := map[string]kubebindv1alpha1.ServiceAccountTokenStatus{}
+	for _, t := range export.Status.ServiceAccountTokens {
+		previous[t.SecretName] = t
+	}
+
+	var errs []error
+	var tokens []kubebindv1alpha1.ServiceAccountTokenStatus
+	for _, claim := range claims {
+		secretName := kubebindhelpers.ServiceAccountTokenSecretName(export.Name, claim)
+		prev, found := previous[secretName]
+		delete(previous, secretName)
+
+		now := time.Now()
+		if found && now.Before(prev.RenewTimestamp.Time) {
+			_, err := r.getProviderSecret(ctx, export.Namespace, secretName)
+			if err == nil {
+				tokens = append(tokens, prev)
+				r.requeueAfter(export.Name, prev.RenewTimestamp.Sub(now))
+				continue
+			} else if !errors.IsNotFound(err) {
+				errs = append(errs, err)
+				tokens = append(tokens, prev)
+				continue
+			}
+		}
+
+		expirationSeconds := claim.ExpirationSeconds
+		if expirationSeconds == 0 {
+			expirationSeconds = defaultTokenExpirationSeconds
+		}
+		tr, err := r.createServiceAccountToken(ctx, claim.Namespace, claim.Name, &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{
+				Audiences:         []string{claim.Audience},
+				ExpirationSeconds: pointer.Int64(expirationSeconds),
+			},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to request token of ServiceAccount %s/%s: %w", claim.Namespace, claim.Name, err))
+			if found {
+				tokens = append(tokens, prev)
+			}
+			continue
+		}
+		if err := r.ensureTokenSecret(ctx, export, secretName, tr.Status.Token); err != nil {
+			errs = append(errs, err)
+			if found {
+				tokens = append(tokens, prev)
+			}
+			continue
+		}
+
+		expiration := tr.Status.ExpirationTimestamp.Time
+		renew := now.Add(expiration.Sub(now) * 4 / 5)
+		logger.V(2).Info("Issued ServiceAccount token", "namespace", claim.Namespace, "name", claim.Name, "audience", claim.Audience, "secret", secretName, "expiration", expiration)
+		tokens = append(tokens, kubebindv1alpha1.ServiceAccountTokenStatus{
+			Namespace:           claim.Namespace,
+			Name:                claim.Name,
+			Audience:            claim.Audience,
+			SecretName:          secretName,
+			ExpirationTimestamp: metav1.NewTime(expiration),
+			RenewTimestamp:      metav1.NewTime(renew),
+		})
+		r.requeueAfter(export.Name, renew.Sub(now))
+	}
+
+	// the claim or its acceptance is gone
+	for secretName := range previous {
+		if err := r.deleteProviderSecret(ctx, export.Namespace, secretName); err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+			tokens = append(tokens, previous[secretName]) // retry the deletion
+			continue
+		}
+		logger.V(2).Info("Deleted ServiceAccount token Secret", "secret", secretName)
+	}
+
+	export.Status.ServiceAccountTokens = tokens
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *reconciler) ensureTokenSecret(ctx context.Context, export *kubebindv1alpha1.APIServiceExport, name, token string) error {
+	secret, err := r.getProviderSecret(ctx, export.Namespace, name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: export.Namespace,
+				Name:      name,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: kubebindv1alpha1.SchemeGroupVersion.String(),
+						Kind:       "APIServiceExport",
+						Name:       export.Name,
+						UID:        export.UID,
+						Controller: pointer.Bool(true),
+					},
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				serviceAccountTokenKey: []byte(token),
+			},
+		}
+		if _, err := r.createProviderSecret(ctx, secret); err != nil {
+			return fmt.Errorf("failed to create Secret %s/%s: %w", export.Namespace, name, err)
+		}
+		return nil
+	}
+
+	secret = secret.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[serviceAccountTokenKey] = []byte(token)
+	if _, err := r.updateProviderSecret(ctx, secret); err != nil {
+		return fmt.Errorf("failed to update Secret %s/%s: %w", export.Namespace, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamic
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	_ cache.TransformFunc = TrimCustomResourceDefinition
+	_ cache.TransformFunc = TrimObjectMetadata
+)
+
+// TrimCustomResourceDefinition drops the OpenAPI schemas and managed fields of
+// cached CRDs. The konnector never reads them from the cache, but they make up
+// most of the memory of a CRD informer.
+func TrimCustomResourceDefinition(obj interface{}) (interface{}, error) {
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	if !ok {
+		return obj, nil
+	}
+	crd.ManagedFields = nil
+	for i := range crd.Spec.Versions {
+		crd.Spec.Versions[i].Schema = nil
+	}
+	return crd, nil
+}
+
+// TrimObjectMetadata drops the managed fields of cached PartialObjectMetadata.
+func TrimObjectMetadata(obj interface{}) (interface{}, error) {
+	if m, ok := obj.(*metav1.PartialObjectMetadata); ok {
+		m.ManagedFields = nil
+		return m, nil
+	}
+	return obj, nil
+}
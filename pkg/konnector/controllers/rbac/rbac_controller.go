@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
+	kubernetesclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+)
+
+const (
+	controllerName = "kube-bind-konnector-rbac"
+)
+
+// NewController returns a new controller reconciling the ClusterRole with the
+// permissions the konnector needs on the bound resources. If baseClusterRole is
+// not empty, rules of that ClusterRole that are broader than needed are reported.
+func NewController(
+	consumerConfig *rest.Config,
+	baseClusterRole string,
+	serviceBindingInformer bindinformers.APIServiceBindingInformer,
+	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
+	clusterRoleInformer rbacinformers.ClusterRoleInformer,
+	rateLimiter tuning.RateLimiter,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
+
+	logger := klog.Background().WithValues("controller", controllerName)
+
+	consumerConfig = rest.CopyConfig(consumerConfig)
+	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
+
+	kubeClient, err := kubernetesclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &controller{
+		queue: queue,
+
+		reconciler: reconciler{
+			baseClusterRole: baseClusterRole,
+
+			listServiceBindings: func() ([]*kubebindv1alpha1.APIServiceBinding, error) {
+				return serviceBindingInformer.Lister().List(labels.Everything())
+			},
+			getCRD: func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+				return crdInformer.Lister().Get(name)
+			},
+			getClusterRole: func(name string) (*rbacv1.ClusterRole, error) {
+				return clusterRoleInformer.Lister().Get(name)
+			},
+			createClusterRole: func(ctx context.Context, role *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error) {
+				return kubeClient.RbacV1().ClusterRoles().Create(ctx, role, metav1.CreateOptions{})
+			},
+			updateClusterRole: func(ctx context.Context, role *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error) {
+				return kubeClient.RbacV1().ClusterRoles().Update(ctx, role, metav1.UpdateOptions{})
+			},
+		},
+	}
+
+	enqueue := func(reason string) func(obj interface{}) {
+		return func(obj interface{}) {
+			logger.V(2).Info("queueing ClusterRole", "key", BindingsClusterRoleName, "reason", reason)
+			c.queue.Add(BindingsClusterRoleName)
+		}
+	}
+
+	serviceBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue("APIServiceBinding"),
+		UpdateFunc: func(_, newObj interface{}) { enqueue("APIServiceBinding")(newObj) },
+		DeleteFunc: enqueue("APIServiceBinding"),
+	})
+
+	crdInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue("CustomResourceDefinition"),
+		UpdateFunc: func(_, newObj interface{}) { enqueue("CustomResourceDefinition")(newObj) },
+		DeleteFunc: enqueue("CustomResourceDefinition"),
+	})
+
+	clusterRoleInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			role, ok := obj.(*rbacv1.ClusterRole)
+			return ok && (role.Name == BindingsClusterRoleName || (baseClusterRole != "" && role.Name == baseClusterRole))
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    enqueue("ClusterRole"),
+			UpdateFunc: func(_, newObj interface{}) { enqueue("ClusterRole")(newObj) },
+			DeleteFunc: enqueue("ClusterRole"),
+		},
+	})
+
+	return c, nil
+}
+
+// controller reconciles the ClusterRole granting the konnector access to bound
+// resources. There is only one key, BindingsClusterRoleName.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	reconciler
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	defer runtime.HandleCrash()
+
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := klog.FromContext(ctx).WithValues("key", key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(2).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.reconcile(ctx); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
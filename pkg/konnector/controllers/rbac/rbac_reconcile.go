@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+var (
+	unusedRules = metrics.NewGauge(&metrics.GaugeOpts{
+		Namespace:      "kube_bind",
+		Subsystem:      "konnector",
+		Name:           "rbac_unused_rules",
+		Help:           "Number of rules of the konnector ClusterRole that grant more than the konnector needs for the current bindings.",
+		StabilityLevel: metrics.ALPHA,
+	})
+)
+
+func init() {
+	legacyregistry.MustRegister(unusedRules)
+}
+
+type reconciler struct {
+	// baseClusterRole is the ClusterRole bound to the konnector, checked for unused permissions.
+	baseClusterRole string
+
+	listServiceBindings func() ([]*kubebindv1alpha1.APIServiceBinding, error)
+	getCRD              func(name string) (*apiextensionsv1.CustomResourceDefinition, error)
+	getClusterRole      func(name string) (*rbacv1.ClusterRole, error)
+	createClusterRole   func(ctx context.Context, role *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error)
+	updateClusterRole   func(ctx context.Context, role *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error)
+
+	lock       sync.Mutex
+	lastReport string
+}
+
+func (r *reconciler) reconcile(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+
+	bindings, err := r.listServiceBindings()
+	if err != nil {
+		return err
+	}
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	for _, binding := range bindings {
+		crd, err := r.getCRD(kubebindhelpers.BoundCRDName(binding))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		} else if errors.IsNotFound(err) {
+			continue // not synced yet, nothing to access
+		}
+		crds = append(crds, crd)
+	}
+	rules := append(BindingRules(crds), ClaimRules(bindings)...)
+	rules = append(rules, EventRelayRules(bindings)...)
+
+	if err := r.ensureClusterRole(ctx, rules); errors.IsForbidden(err) {
+		logger.Info("not allowed to reconcile the bindings ClusterRole, assuming the konnector has the permissions otherwise", "name", BindingsClusterRoleName, "err", err.Error())
+	} else if err != nil {
+		return err
+	}
+
+	r.report(logger, rules)
+	return nil
+}
+
+func (r *reconciler) ensureClusterRole(ctx context.Context, rules []rbacv1.PolicyRule) error {
+	existing, err := r.getClusterRole(BindingsClusterRoleName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		_, err := r.createClusterRole(ctx, &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: BindingsClusterRoleName,
+			},
+			Rules: rules,
+		})
+		return err
+	}
+
+	if (len(existing.Rules) == 0 && len(rules) == 0) || equality.Semantic.DeepEqual(existing.Rules, rules) {
+		return nil
+	}
+	role := existing.DeepCopy()
+	role.Rules = rules
+	_, err = r.updateClusterRole(ctx, role)
+	return err
+}
+
+// report logs the rules of the base ClusterRole that are not needed, whenever they change.
+func (r *reconciler) report(logger klog.Logger, rules []rbacv1.PolicyRule) {
+	if r.baseClusterRole == "" {
+		return
+	}
+	base, err := r.getClusterRole(r.baseClusterRole)
+	if err != nil {
+		logger.V(2).Info("cannot get ClusterRole to report unused permissions", "name", r.baseClusterRole, "err", err.Error())
+		return
+	}
+
+	unused := Unused(base.Rules, append(BaseRules(), rules...))
+	unusedRules.Set(float64(len(unused)))
+
+	report := fmt.Sprintf("%v", unused)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if report == r.lastReport {
+		return
+	}
+	r.lastReport = report
+	if len(unused) == 0 {
+		logger.Info("ClusterRole grants no unused permissions", "name", r.baseClusterRole)
+		return
+	}
+	for _, rule := range unused {
+		logger.Info("ClusterRole grants permissions the konnector does not need", "name", r.baseClusterRole,
+			"apiGroups", rule.APIGroups, "resources", rule.Resources, "resourceNames", rule.ResourceNames, "nonResourceURLs", rule.NonResourceURLs, "verbs", rule.Verbs)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+const (
+	// BindingsClusterRoleName is the ClusterRole reconciled by the konnector with
+	// the permissions on the bound resources.
+	BindingsClusterRoleName = "kube-bind-konnector-bindings"
+)
+
+var (
+	readVerbs  = []string{"get", "list", "watch"}
+	writeVerbs = []string{"get", "list", "watch", "create", "update", "patch"}
+)
+
+// BaseRules returns the permissions the konnector needs on the consumer cluster
+// independently of what is bound. The permissions on bound resources are granted
+// by reconciling the BindingsClusterRoleName ClusterRole, which needs escalate.
+func BaseRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{kubebindv1alpha1.GroupName}, Resources: []string{"apiservicebindings", "apiservicebindings/status"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+		{APIGroups: []string{kubebindv1alpha1.GroupName}, Resources: []string{"backendtrustpolicies"}, Verbs: readVerbs},
+		{APIGroups: []string{apiextensionsv1.GroupName}, Resources: []string{"customresourcedefinitions"}, Verbs: writeVerbs},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: writeVerbs},
+		{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: readVerbs},
+		{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, Verbs: []string{"get", "list", "watch", "create", "update", "patch", "delete"}},
+		{APIGroups: []string{rbacv1.GroupName}, Resources: []string{"clusterroles"}, Verbs: []string{"get", "list", "watch", "create"}},
+		{APIGroups: []string{rbacv1.GroupName}, Resources: []string{"clusterroles"}, ResourceNames: []string{BindingsClusterRoleName}, Verbs: []string{"update", "patch", "escalate"}},
+	}
+}
+
+// BindingRules returns the permissions the konnector needs to sync the given
+// bound CRDs, sorted by group and resource.
+func BindingRules(crds []*apiextensionsv1.CustomResourceDefinition) []rbacv1.PolicyRule {
+	sorted := make([]*apiextensionsv1.CustomResourceDefinition, len(crds))
+	copy(sorted, crds)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Spec.Group != sorted[j].Spec.Group {
+			return sorted[i].Spec.Group < sorted[j].Spec.Group
+		}
+		return sorted[i].Spec.Names.Plural < sorted[j].Spec.Names.Plural
+	})
+
+	rules := make([]rbacv1.PolicyRule, 0, len(sorted))
+	for _, crd := range sorted {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{crd.Spec.Group},
+			Resources: []string{crd.Spec.Names.Plural, crd.Spec.Names.Plural + "/status"},
+			Verbs:     []string{"get", "list", "watch", "update", "patch"},
+		})
+	}
+	return rules
+}
+
+// ClaimRules returns read-only permissions on the claimable cluster-scoped
+// resources accepted by the given bindings, sorted by group and resource.
+func ClaimRules(bindings []*kubebindv1alpha1.APIServiceBinding) []rbacv1.PolicyRule {
+	seen := map[kubebindv1alpha1.ClusterScopedClaim]bool{}
+	var claims []kubebindv1alpha1.ClusterScopedClaim
+	for _, binding := range bindings {
+		for _, claim := range binding.Spec.AcceptedClaims {
+			if _, ok := kubebindhelpers.Claimable(claim); !ok || seen[claim] {
+				continue
+			}
+			seen[claim] = true
+			claims = append(claims, claim)
+		}
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		if claims[i].Group != claims[j].Group {
+			return claims[i].Group < claims[j].Group
+		}
+		return claims[i].Resource < claims[j].Resource
+	})
+
+	rules := make([]rbacv1.PolicyRule, 0, len(claims))
+	for _, claim := range claims {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{claim.Group},
+			Resources: []string{claim.Resource},
+			Verbs:     readVerbs,
+		})
+	}
+	return rules
+}
+
+// EventRelayRules returns the permissions on Events if any of the given bindings
+// accepted an event relay. Relayed Events are written, and upsynced ones are watched.
+func EventRelayRules(bindings []*kubebindv1alpha1.APIServiceBinding) []rbacv1.PolicyRule {
+	for _, binding := range bindings {
+		if binding.Spec.AcceptedEventRelay != nil {
+			return []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: writeVerbs}}
+		}
+	}
+	return nil
+}
+
+// Unused returns the granted rules that are not fully covered by the required
+// ones, i.e. that grant more than the konnector needs.
+func Unused(granted, required []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	var unused []rbacv1.PolicyRule
+	for _, rule := range granted {
+		if !covers(required, rule) {
+			unused = append(unused, rule)
+		}
+	}
+	return unused
+}
+
+// covers returns true if every permission of rule is granted by one of the owner
+// rules. Wildcards in rule are only covered by wildcards.
+func covers(owner []rbacv1.PolicyRule, rule rbacv1.PolicyRule) bool {
+	for _, verb := range rule.Verbs {
+		for _, url := range rule.NonResourceURLs {
+			if !anyRule(owner, func(o rbacv1.PolicyRule) bool {
+				return has(o.Verbs, verb) && hasURL(o.NonResourceURLs, url)
+			}) {
+				return false
+			}
+		}
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				if !anyRule(owner, func(o rbacv1.PolicyRule) bool {
+					return has(o.Verbs, verb) && has(o.APIGroups, group) && hasResource(o.Resources, resource) &&
+						(len(o.ResourceNames) == 0 || (len(rule.ResourceNames) > 0 && subset(rule.ResourceNames, o.ResourceNames)))
+				}) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func anyRule(rules []rbacv1.PolicyRule, f func(rbacv1.PolicyRule) bool) bool {
+	for _, r := range rules {
+		if f(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func has(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s || x == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasResource(resources []string, resource string) bool {
+	if has(resources, resource) {
+		return true
+	}
+	for _, r := range resources {
+		// "*/status" covers every status subresource
+		if strings.HasPrefix(r, "*/") && strings.Contains(resource, "/") && strings.HasSuffix(resource, r[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasURL(urls []string, url string) bool {
+	for _, u := range urls {
+		if u == url || u == "*" || (strings.HasSuffix(u, "*") && strings.HasPrefix(url, strings.TrimSuffix(u, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func subset(ss, of []string) bool {
+	for _, s := range ss {
+		if !has(of, s) {
+			return false
+		}
+	}
+	return true
+}
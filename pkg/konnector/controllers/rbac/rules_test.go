@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestUnused(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "mangodb.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "mangodbs"},
+		},
+	}
+	required := append(BaseRules(), BindingRules([]*apiextensionsv1.CustomResourceDefinition{crd})...)
+
+	tests := []struct {
+		name    string
+		granted []rbacv1.PolicyRule
+		want    []rbacv1.PolicyRule
+	}{
+		{
+			name:    "base rules",
+			granted: BaseRules(),
+		},
+		{
+			name:    "bound resource",
+			granted: []rbacv1.PolicyRule{{APIGroups: []string{"mangodb.com"}, Resources: []string{"mangodbs/status"}, Verbs: []string{"get", "update"}}},
+		},
+		{
+			name:    "wildcard",
+			granted: []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+			want:    []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+		},
+		{
+			name:    "delete is not needed",
+			granted: []rbacv1.PolicyRule{{APIGroups: []string{"mangodb.com"}, Resources: []string{"mangodbs"}, Verbs: []string{"get", "delete"}}},
+			want:    []rbacv1.PolicyRule{{APIGroups: []string{"mangodb.com"}, Resources: []string{"mangodbs"}, Verbs: []string{"get", "delete"}}},
+		},
+		{
+			name:    "escalate on other ClusterRoles",
+			granted: []rbacv1.PolicyRule{{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}, Verbs: []string{"escalate"}}},
+			want:    []rbacv1.PolicyRule{{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}, Verbs: []string{"escalate"}}},
+		},
+		{
+			name:    "non-resource URLs",
+			granted: []rbacv1.PolicyRule{{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}}},
+			want:    []rbacv1.PolicyRule{{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Unused(tt.granted, required))
+		})
+	}
+}
+
+func TestClaimRules(t *testing.T) {
+	bindings := []*kubebindv1alpha1.APIServiceBinding{
+		{Spec: kubebindv1alpha1.APIServiceBindingSpec{AcceptedClaims: []kubebindv1alpha1.ClusterScopedClaim{
+			{Group: "storage.k8s.io", Resource: "storageclasses"},
+			{Resource: "nodes"},
+		}}},
+		{Spec: kubebindv1alpha1.APIServiceBindingSpec{AcceptedClaims: []kubebindv1alpha1.ClusterScopedClaim{
+			{Group: "storage.k8s.io", Resource: "storageclasses"},
+			{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"}, // not claimable
+		}}},
+	}
+	require.Equal(t, []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"storage.k8s.io"}, Resources: []string{"storageclasses"}, Verbs: []string{"get", "list", "watch"}},
+	}, ClaimRules(bindings))
+}
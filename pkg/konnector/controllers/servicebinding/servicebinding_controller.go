@@ -25,11 +25,10 @@ import (
 	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
 	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
-	coreinformers "k8s.io/client-go/informers/core/v1"
-	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -41,6 +40,9 @@ import (
 	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/committer"
 	"github.com/kube-bind/kube-bind/pkg/indexers"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/drain"
+	"github.com/kube-bind/kube-bind/pkg/konnector/secrets"
 )
 
 const (
@@ -51,10 +53,13 @@ const (
 func NewController(
 	consumerConfig *rest.Config,
 	serviceBindingInformer bindinformers.APIServiceBindingInformer,
-	consumerSecretInformer coreinformers.SecretInformer,
+	trustPolicyInformer bindinformers.BackendTrustPolicyInformer,
+	consumerSecrets *secrets.Watcher,
 	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
+	requireApproval bool,
+	rateLimiter tuning.RateLimiter,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -72,14 +77,16 @@ func NewController(
 		serviceBindingLister:  serviceBindingInformer.Lister(),
 		serviceBindingIndexer: serviceBindingInformer.Informer().GetIndexer(),
 
-		consumerSecretLister: consumerSecretInformer.Lister(),
-
 		crdLister:  crdInformer.Lister(),
 		crdIndexer: crdInformer.Informer().GetIndexer(),
 
 		reconciler: reconciler{
+			requireApproval: requireApproval,
 			getConsumerSecret: func(ns, name string) (*corev1.Secret, error) {
-				return consumerSecretInformer.Lister().Secrets(ns).Get(name)
+				return consumerSecrets.Get(ns, name)
+			},
+			listTrustPolicies: func() ([]*kubebindv1alpha1.BackendTrustPolicy, error) {
+				return trustPolicyInformer.Lister().List(labels.Everything())
 			},
 		},
 
@@ -107,7 +114,7 @@ func NewController(
 		},
 	})
 
-	consumerSecretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	consumerSecrets.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			c.enqueueConsumerSecret(logger, obj)
 		},
@@ -119,15 +126,15 @@ func NewController(
 		},
 	})
 
-	consumerSecretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	trustPolicyInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			c.enqueueConsumerSecret(logger, obj)
+			c.enqueueAllServiceBindings(logger, obj)
 		},
 		UpdateFunc: func(_, newObj interface{}) {
-			c.enqueueConsumerSecret(logger, newObj)
+			c.enqueueAllServiceBindings(logger, newObj)
 		},
 		DeleteFunc: func(obj interface{}) {
-			c.enqueueConsumerSecret(logger, obj)
+			c.enqueueAllServiceBindings(logger, obj)
 		},
 	})
 
@@ -146,8 +153,6 @@ type controller struct {
 	serviceBindingLister  bindlisters.APIServiceBindingLister
 	serviceBindingIndexer cache.Indexer
 
-	consumerSecretLister corelisters.SecretLister
-
 	crdLister  apiextensionslisters.CustomResourceDefinitionLister
 	crdIndexer cache.Indexer
 
@@ -194,6 +199,24 @@ func (c *controller) enqueueConsumerSecret(logger klog.Logger, obj interface{})
 	}
 }
 
+func (c *controller) enqueueAllServiceBindings(logger klog.Logger, obj interface{}) {
+	policyKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	bindings, err := c.serviceBindingLister.List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, binding := range bindings {
+		logger.V(2).Info("queueing APIServiceBinding", "key", binding.Name, "reason", "BackendTrustPolicy", "BackendTrustPolicyKey", policyKey)
+		c.queue.Add(binding.Name)
+	}
+}
+
 // Start starts the controller, which stops when ctx.Done() is closed.
 func (c *controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
@@ -234,7 +257,13 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	started, err := drain.Process(ctx, func(ctx context.Context) error {
+		return c.process(ctx, key)
+	})
+	if !started {
+		return false // shutting down, don't start new work
+	}
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		c.queue.AddRateLimited(key)
 		return true
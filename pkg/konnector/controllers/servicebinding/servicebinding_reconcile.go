@@ -18,6 +18,7 @@ package servicebinding
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -25,12 +26,16 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
 )
 
 type reconciler struct {
 	getConsumerSecret func(ns, name string) (*corev1.Secret, error)
+	listTrustPolicies func() ([]*kubebindv1alpha1.BackendTrustPolicy, error)
+
+	requireApproval bool
 }
 
 func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
@@ -40,6 +45,13 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 		errs = append(errs, err)
 	}
 
+	if err := r.ensureTrustedBackend(ctx, binding); err != nil {
+		errs = append(errs, err)
+	}
+
+	r.ensureApproved(binding)
+	r.ensurePaused(binding)
+
 	conditions.SetSummary(binding)
 
 	return utilerrors.NewAggregate(errs)
@@ -117,7 +129,7 @@ func (r *reconciler) ensureValidKubeconfigSecret(ctx context.Context, binding *k
 		)
 		return nil
 	}
-	if _, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig)); err != nil {
+	if _, err := kubebindhelpers.ProviderRESTConfigs(kubeconfig); err != nil {
 		conditions.MarkFalse(
 			binding,
 			kubebindv1alpha1.APIServiceBindingConditionSecretValid,
@@ -138,3 +150,49 @@ func (r *reconciler) ensureValidKubeconfigSecret(ctx context.Context, binding *k
 
 	return nil
 }
+
+// Approved returns whether the binding has been approved via the kube-bind.io/approved-by annotation.
+func Approved(binding *kubebindv1alpha1.APIServiceBinding) bool {
+	return binding.Annotations[kubebindv1alpha1.ApprovedByAnnotationKey] != ""
+}
+
+func (r *reconciler) ensureApproved(binding *kubebindv1alpha1.APIServiceBinding) {
+	if !r.requireApproval {
+		conditions.Delete(binding, kubebindv1alpha1.APIServiceBindingConditionApproved)
+		return
+	}
+
+	if !Approved(binding) {
+		conditions.MarkFalse(
+			binding,
+			kubebindv1alpha1.APIServiceBindingConditionApproved,
+			"PendingApproval",
+			conditionsapi.ConditionSeverityInfo,
+			"Waiting for the %s annotation to be set by an approver.",
+			kubebindv1alpha1.ApprovedByAnnotationKey,
+		)
+		return
+	}
+
+	conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionApproved)
+}
+
+// Paused returns whether the sync of the binding is paused via the kube-bind.io/paused annotation.
+func Paused(binding *kubebindv1alpha1.APIServiceBinding) bool {
+	return binding.Annotations[kubebindv1alpha1.PausedAnnotationKey] == "true"
+}
+
+func (r *reconciler) ensurePaused(binding *kubebindv1alpha1.APIServiceBinding) {
+	if !Paused(binding) {
+		conditions.Delete(binding, kubebindv1alpha1.APIServiceBindingConditionPaused)
+		return
+	}
+
+	conditions.Set(binding, &conditionsapi.Condition{
+		Type:     kubebindv1alpha1.APIServiceBindingConditionPaused,
+		Status:   corev1.ConditionTrue,
+		Severity: conditionsapi.ConditionSeverityNone,
+		Reason:   "PausedByAnnotation",
+		Message:  fmt.Sprintf("Objects are not synced while the %s annotation is \"true\". Remove it to resume.", kubebindv1alpha1.PausedAnnotationKey),
+	})
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicebinding
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// BackendTrusted checks the backend of the kubeconfig secret against the BackendTrustPolicies.
+// The backend URL is taken from the annotation kubectl bind puts on the secret.
+// Fallback endpoints in the kubeconfig must be trusted as well.
+func BackendTrusted(policies []*kubebindv1alpha1.BackendTrustPolicy, secret *corev1.Secret, kubeconfig []byte) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	endpoints, err := helpers.ProviderRESTConfigs(kubeconfig)
+	if err != nil {
+		return err
+	}
+	for _, config := range endpoints {
+		if err := helpers.BackendTrusted(policies, helpers.BackendIdentity{
+			URL:    secret.Annotations[kubebindv1alpha1.BackendURLAnnotationKey],
+			Server: config.Host,
+			CAData: config.CAData,
+		}, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reconciler) ensureTrustedBackend(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
+	ref := binding.Spec.KubeconfigSecretRef
+	secret, err := r.getConsumerSecret(ref.Namespace, ref.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if errors.IsNotFound(err) || !conditions.IsTrue(binding, kubebindv1alpha1.APIServiceBindingConditionSecretValid) {
+		conditions.Delete(binding, kubebindv1alpha1.APIServiceBindingConditionBackendTrusted)
+		return nil
+	}
+
+	policies, err := r.listTrustPolicies()
+	if err != nil {
+		return err
+	}
+	if err := BackendTrusted(policies, secret, secret.Data[ref.Key]); err != nil {
+		conditions.MarkFalse(
+			binding,
+			kubebindv1alpha1.APIServiceBindingConditionBackendTrusted,
+			"BackendNotTrusted",
+			conditionsapi.ConditionSeverityError,
+			"%v. Ask a cluster administrator to add the backend to a BackendTrustPolicy.",
+			err,
+		)
+		return nil
+	}
+
+	conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionBackendTrusted)
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tuning
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Controller configures the concurrency and client rate limits of a controller.
+type Controller struct {
+	// Workers is the number of concurrent workers. Values below 1 mean 1.
+	Workers int
+	// QPS and Burst limit the requests of the controller against the consumer and
+	// provider cluster. Zero keeps the client defaults.
+	QPS   float32
+	Burst int
+}
+
+// Client configures the rate limits of all clients against a cluster.
+type Client struct {
+	// QPS and Burst limit the requests against the cluster. Zero keeps the
+	// client-go defaults of 5 QPS and a burst of 10.
+	QPS   float32
+	Burst int
+}
+
+// Resync configures the resync periods of the informers.
+type Resync struct {
+	// Period is the resync period of the informers of the konnector, except the
+	// dynamic informers of bound resources in service provider clusters.
+	Period time.Duration
+	// ProviderPeriod is the resync period of the dynamic informers of bound
+	// resources in service provider clusters.
+	ProviderPeriod time.Duration
+}
+
+// RateLimiter configures the retries of failed items in the workqueues of the
+// controllers.
+type RateLimiter struct {
+	// BaseDelay and MaxDelay bound the exponential backoff per failed item.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// QPS and Burst limit the overall rate of retries of a workqueue.
+	QPS   float64
+	Burst int
+}
+
+// New returns a workqueue rate limiter. Every workqueue needs its own.
+func (r RateLimiter) New() workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(r.BaseDelay, r.MaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(r.QPS), r.Burst)},
+	)
+}
+
+// Sync configures the spec (upsync) and status (downsync) controllers independently.
+type Sync struct {
+	Spec   Controller
+	Status Controller
+}
+
+// Workers configures the concurrency of the controllers other than spec and
+// status sync.
+type Workers struct {
+	// Default is the number of workers of the konnector controller and of the
+	// controllers of each service provider cluster. Values below 1 mean 1.
+	Default int
+	// ServiceBinding is the number of workers of the APIServiceBinding controllers.
+	// Values below 1 mean 1.
+	ServiceBinding int
+}
+
+// NumDefault returns the number of workers of the konnector and per-cluster controllers, at least 1.
+func (w Workers) NumDefault() int {
+	return atLeastOne(w.Default)
+}
+
+// NumServiceBinding returns the number of workers of the APIServiceBinding controllers, at least 1.
+func (w Workers) NumServiceBinding() int {
+	return atLeastOne(w.ServiceBinding)
+}
+
+// NumWorkers returns the number of workers to start, at least 1.
+func (c Controller) NumWorkers() int {
+	return atLeastOne(c.Workers)
+}
+
+// Config returns a copy of config with the rate limits of the controller applied.
+// Every controller gets its own rate limiter, such that one cannot starve the other.
+func (c Controller) Config(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	if c.QPS > 0 {
+		config.QPS = c.QPS
+	}
+	if c.Burst > 0 {
+		config.Burst = c.Burst
+	}
+	return config
+}
+
+// Config returns a copy of config with the rate limits applied.
+func (c Client) Config(config *rest.Config) *rest.Config {
+	return Controller{QPS: c.QPS, Burst: c.Burst}.Config(config)
+}
+
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tuning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	r := RateLimiter{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, QPS: 1000, Burst: 1000}
+	limiter := r.New()
+
+	var delays []time.Duration
+	for i := 0; i < 6; i++ {
+		delays = append(delays, limiter.When("item"))
+	}
+	require.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}, delays)
+
+	limiter.Forget("item")
+	require.Equal(t, 100*time.Millisecond, limiter.When("item"))
+
+	require.Equal(t, 0, r.New().NumRequeues("item"), "every workqueue gets its own limiter")
+}
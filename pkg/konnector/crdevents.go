@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kube-bind/kube-bind/deploy/crd"
+)
+
+// recordCRDAction returns a callback for crd.CreateWithPolicy that records what
+// happened to the konnector CRDs at startup as events on the CRDs. Unchanged CRDs
+// are not recorded.
+func (s *Server) recordCRDAction(ctx context.Context) func(*apiextensionsv1.CustomResourceDefinition, crd.Action, error) {
+	logger := klog.FromContext(ctx)
+
+	return func(c *apiextensionsv1.CustomResourceDefinition, action crd.Action, err error) {
+		eventType, reason, message := corev1.EventTypeNormal, "", ""
+		switch {
+		case err != nil && action == crd.ActionRefused:
+			eventType, reason, message = corev1.EventTypeWarning, "CRDUpgradeRefused",
+				fmt.Sprintf("CRD differs from the konnector version and was not updated because of --crd-upgrade-policy=%s", s.Config.Options.CRDUpgradePolicy)
+		case err != nil:
+			eventType, reason, message = corev1.EventTypeWarning, "CRDInstallFailed", fmt.Sprintf("Failed to install CRD: %v", err)
+		case action == crd.ActionCreated:
+			reason, message = "CRDCreated", "CRD was installed by the konnector"
+		case action == crd.ActionUpdated:
+			reason, message = "CRDUpdated", "CRD was updated to the konnector version"
+		case action == crd.ActionSkipped:
+			eventType, reason, message = corev1.EventTypeWarning, "CRDUpgradeSkipped",
+				fmt.Sprintf("CRD differs from the konnector version and was left alone because of --crd-upgrade-policy=%s", s.Config.Options.CRDUpgradePolicy)
+		default:
+			return
+		}
+		logger.Info("CRD installation", "crd", c.Name, "action", action, "message", message)
+
+		now := metav1.Now()
+		if _, err := s.Config.KubeClient.CoreV1().Events(metav1.NamespaceDefault).Create(ctx, &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: c.Name + ".",
+				Namespace:    metav1.NamespaceDefault,
+			},
+			InvolvedObject: corev1.ObjectReference{
+				APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+				Kind:       "CustomResourceDefinition",
+				Name:       c.Name,
+			},
+			Reason:              reason,
+			Message:             message,
+			Type:                eventType,
+			Source:              corev1.EventSource{Component: "konnector"},
+			ReportingController: "konnector",
+			FirstTimestamp:      now,
+			LastTimestamp:       now,
+			Count:               1,
+		}, metav1.CreateOptions{}); err != nil {
+			logger.Error(err, "failed to record CRD event", "crd", c.Name, "reason", reason)
+		}
+	}
+}
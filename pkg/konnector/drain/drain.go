@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type contextKey struct{}
+
+// Tracker tracks in-flight work items of controllers, such that a shutdown can
+// wait for them to finish instead of aborting them halfway.
+type Tracker struct {
+	work   context.Context
+	cancel func()
+
+	lock      sync.Mutex
+	inflight  int
+	completed int
+	idle      *sync.Cond
+}
+
+// Summary describes the outcome of Drain.
+type Summary struct {
+	// Completed is the number of in-flight items that finished while draining.
+	Completed int
+	// Aborted is the number of in-flight items that were cancelled after the grace period.
+	Aborted int
+	// Duration is how long draining took.
+	Duration time.Duration
+}
+
+// NewTracker returns a new Tracker.
+func NewTracker() *Tracker {
+	t := &Tracker{}
+	t.work, t.cancel = context.WithCancel(context.Background())
+	t.idle = sync.NewCond(&t.lock)
+	return t
+}
+
+// WithTracker returns a context carrying the given tracker for Begin.
+func WithTracker(ctx context.Context, t *Tracker) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// Begin marks the start of a work item. The returned context carries the values of
+// ctx, but is not cancelled with it. It is only cancelled when draining times out.
+// done must be called when the item is finished. Without a tracker in ctx, ctx is
+// returned as is.
+func Begin(ctx context.Context) (context.Context, func()) {
+	t, ok := ctx.Value(contextKey{}).(*Tracker)
+	if !ok {
+		return ctx, func() {}
+	}
+
+	t.lock.Lock()
+	t.inflight++
+	t.lock.Unlock()
+
+	var once sync.Once
+	return detached{Context: ctx, work: t.work}, func() {
+		once.Do(func() {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			t.inflight--
+			t.completed++
+			if t.inflight == 0 {
+				t.idle.Broadcast()
+			}
+		})
+	}
+}
+
+// Process processes a work item of a controller with process. On shutdown, i.e. if
+// ctx is done already, it returns false without starting the item. Otherwise, the
+// item runs with the context of Begin, such that it is finished within the grace
+// period instead of being cut off halfway, e.g. between status updates.
+func Process(ctx context.Context, process func(ctx context.Context) error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, nil
+	}
+
+	ctx, done := Begin(ctx)
+	defer done()
+	return true, process(ctx)
+}
+
+// Drain waits for in-flight work items to finish, at most for the grace period.
+// Remaining items are cancelled afterwards.
+func (t *Tracker) Drain(grace time.Duration) Summary {
+	start := time.Now()
+
+	t.lock.Lock()
+	completedBefore := t.completed
+	t.lock.Unlock()
+
+	idle := make(chan struct{})
+	go func() {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		for t.inflight > 0 {
+			t.idle.Wait()
+		}
+		close(idle)
+	}()
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-idle:
+	case <-timer.C:
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.cancel()
+	return Summary{
+		Completed: t.completed - completedBefore,
+		Aborted:   t.inflight,
+		Duration:  time.Since(start),
+	}
+}
+
+// detached is a context with the values of the embedded context, but the
+// cancellation of work.
+type detached struct {
+	context.Context
+	work context.Context
+}
+
+func (d detached) Deadline() (time.Time, bool) { return d.work.Deadline() }
+func (d detached) Done() <-chan struct{}       { return d.work.Done() }
+func (d detached) Err() error                  { return d.work.Err() }
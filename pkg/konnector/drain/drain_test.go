@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type valueKey struct{}
+
+func TestDrain(t *testing.T) {
+	tracker := NewTracker()
+	ctx, cancel := context.WithCancel(WithTracker(context.WithValue(context.Background(), valueKey{}, "value"), tracker))
+
+	workCtx, done := Begin(ctx)
+	_, stuckDone := Begin(ctx)
+	defer stuckDone()
+
+	cancel()
+	require.NoError(t, workCtx.Err(), "work must survive the cancellation of its parent")
+	require.Equal(t, "value", workCtx.Value(valueKey{}))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		done()
+	}()
+	summary := tracker.Drain(100 * time.Millisecond)
+	require.Equal(t, 1, summary.Completed)
+	require.Equal(t, 1, summary.Aborted)
+	require.Error(t, workCtx.Err(), "work must be cancelled after the grace period")
+}
+
+func TestBeginWithoutTracker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	workCtx, done := Begin(ctx)
+	defer done()
+	cancel()
+	require.Error(t, workCtx.Err())
+}
+
+func TestProcess(t *testing.T) {
+	tracker := NewTracker()
+	ctx, cancel := context.WithCancel(WithTracker(context.Background(), tracker))
+
+	started, err := Process(ctx, func(ctx context.Context) error {
+		cancel()
+		require.NoError(t, ctx.Err(), "started item must survive the shutdown")
+		return errors.New("failed")
+	})
+	require.True(t, started)
+	require.EqualError(t, err, "failed")
+
+	started, err = Process(ctx, func(ctx context.Context) error {
+		t.Fatal("no new item must be started on shutdown")
+		return nil
+	})
+	require.False(t, started)
+	require.NoError(t, err)
+
+	summary := tracker.Drain(time.Second)
+	require.Equal(t, 0, summary.Aborted)
+}
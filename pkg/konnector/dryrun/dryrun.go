@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dryrun turns the writes of the konnector into server-side dry-run
+// requests. The API servers validate and admit them as usual, but persist
+// nothing, and every write is logged as what the konnector would have done.
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// Wrap returns a copy of the config whose creates, updates, patches and deletes
+// are sent as dry-run and logged with the given cluster, e.g. consumer or
+// provider.
+func Wrap(config *rest.Config, cluster string) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &roundTripper{delegate: rt, cluster: cluster}
+	})
+	return config
+}
+
+type roundTripper struct {
+	delegate http.RoundTripper
+	cluster  string
+}
+
+var verbs = map[string]string{
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "patch",
+	http.MethodDelete: "delete",
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	verb, found := verbs[req.Method]
+	if !found {
+		return rt.delegate.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	q := req.URL.Query()
+	q.Set("dryRun", "All")
+	req.URL.RawQuery = q.Encode()
+
+	// the API server ignores the query parameter of deletes with DeleteOptions in the body
+	if req.Method == http.MethodDelete && req.Body != nil && req.Body != http.NoBody {
+		body, err := dryRunDeleteOptions(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("dry-run: refusing to %s %s: %w", verb, req.URL.Path, err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		req.ContentLength = int64(len(body))
+	}
+
+	resp, err := rt.delegate.RoundTrip(req)
+	logger := klog.FromContext(req.Context()).WithValues("cluster", rt.cluster, "verb", verb, "path", req.URL.Path)
+	if err != nil {
+		logger.Info("Dry-run: would fail", "err", err)
+	} else if resp.StatusCode >= 300 {
+		logger.Info("Dry-run: would fail", "status", resp.StatusCode)
+	} else {
+		logger.Info("Dry-run: would " + verb)
+	}
+	return resp, err
+}
+
+// dryRunDeleteOptions sets dryRun in the JSON DeleteOptions of the body.
+func dryRunDeleteOptions(body io.ReadCloser) ([]byte, error) {
+	defer body.Close()
+	bs, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	options := map[string]interface{}{}
+	if len(bytes.TrimSpace(bs)) > 0 {
+		if err := json.Unmarshal(bs, &options); err != nil {
+			return nil, fmt.Errorf("failed to decode DeleteOptions: %w", err)
+		}
+	}
+	options["dryRun"] = []string{"All"}
+	return json.Marshal(options)
+}
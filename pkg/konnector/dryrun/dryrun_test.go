@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestWrap(t *testing.T) {
+	type request struct {
+		method string
+		dryRun string
+		body   map[string]interface{}
+	}
+	var lock sync.Mutex
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, dryRun: r.URL.Query().Get("dryRun")}
+		if bs, _ := io.ReadAll(r.Body); len(bs) > 0 {
+			require.NoError(t, json.Unmarshal(bs, &req.body))
+		}
+		lock.Lock()
+		requests = append(requests, req)
+		lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&corev1.ConfigMap{ // nolint:errcheck
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cm"},
+		})
+	}))
+	defer server.Close()
+
+	client, err := kubeclient.NewForConfig(Wrap(&rest.Config{Host: server.URL}, "consumer"))
+	require.NoError(t, err)
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cm"}}
+
+	_, err = client.CoreV1().ConfigMaps("default").Get(ctx, "cm", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, err = client.CoreV1().ConfigMaps("default").Create(ctx, cm, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = client.CoreV1().ConfigMaps("default").Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, client.CoreV1().ConfigMaps("default").Delete(ctx, "cm", metav1.DeleteOptions{}))
+
+	require.Len(t, requests, 4)
+	require.Equal(t, http.MethodGet, requests[0].method)
+	require.Empty(t, requests[0].dryRun)
+	for _, req := range requests[1:] {
+		require.Equal(t, "All", req.dryRun, req.method)
+	}
+	require.Equal(t, http.MethodDelete, requests[3].method)
+	require.Equal(t, []interface{}{"All"}, requests[3].body["dryRun"])
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package failover spreads the requests of a client over the API endpoints of
+// a service provider cluster. Requests go to the endpoint that answered last.
+// If it cannot be reached, the other endpoints are tried in order, such that
+// the konnector follows the service provider through a control-plane
+// migration without re-binding.
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// Config returns a rest config of the first endpoint whose requests fail over
+// to the other endpoints. The endpoints keep their own TLS configuration and
+// credentials. Host stays the one of the first endpoint, whichever endpoint
+// serves the requests.
+func Config(endpoints ...*rest.Config) (*rest.Config, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no endpoints")
+	}
+	if len(endpoints) == 1 {
+		return endpoints[0], nil
+	}
+
+	t := &transport{}
+	for _, config := range endpoints {
+		u, err := baseURL(config)
+		if err != nil {
+			return nil, err
+		}
+		rt, err := rest.TransportFor(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transport for %s: %w", config.Host, err)
+		}
+		t.endpoints = append(t.endpoints, endpoint{url: u, rt: rt})
+	}
+
+	// TLS, proxy and authentication are part of the transports of the endpoints.
+	config := rest.CopyConfig(endpoints[0])
+	config.TLSClientConfig = rest.TLSClientConfig{}
+	config.BearerToken, config.BearerTokenFile = "", ""
+	config.Username, config.Password = "", ""
+	config.Impersonate = rest.ImpersonationConfig{}
+	config.AuthProvider, config.AuthConfigPersister, config.ExecProvider = nil, nil, nil
+	config.WrapTransport = nil
+	config.Proxy, config.Dial = nil, nil
+	config.Transport = t
+	return config, nil
+}
+
+func baseURL(config *rest.Config) (*url.URL, error) {
+	host := config.Host
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host %q: %w", config.Host, err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u, nil
+}
+
+type endpoint struct {
+	url *url.URL
+	rt  http.RoundTripper
+}
+
+type transport struct {
+	endpoints []endpoint
+
+	lock   sync.Mutex
+	active int
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lock.Lock()
+	active := t.active
+	t.lock.Unlock()
+
+	// the request is built against the first endpoint
+	path := strings.TrimPrefix(req.URL.Path, t.endpoints[0].url.Path)
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	var errs []error
+	for i := range t.endpoints {
+		idx := (active + i) % len(t.endpoints)
+		e := t.endpoints[idx]
+
+		r := req.Clone(req.Context())
+		r.URL.Scheme, r.URL.Host, r.URL.Path = e.url.Scheme, e.url.Host, e.url.Path+path
+		r.URL.RawPath = ""
+		r.Host = ""
+		if i > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+
+		resp, err := e.rt.RoundTrip(r)
+		if err == nil {
+			if idx != active {
+				t.lock.Lock()
+				t.active = idx
+				t.lock.Unlock()
+				klog.FromContext(req.Context()).Info("failed over to service provider endpoint", "endpoint", e.url.String(), "previous", t.endpoints[active].url.String())
+			}
+			return resp, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", e.url.Host, err))
+		if !replayable || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			break
+		}
+	}
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+	return nil, fmt.Errorf("all endpoints failed: %w", utilerrors.NewAggregate(errs))
+}
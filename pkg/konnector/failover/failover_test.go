@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestConfig(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	var paths, bodies, tokens []string
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, string(body))
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+
+	config, err := Config(
+		&rest.Config{Host: down.URL + "/clusters/old", BearerToken: "old"},
+		&rest.Config{Host: up.URL + "/clusters/new", BearerToken: "new"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, down.URL+"/clusters/old", config.Host)
+	rt, err := rest.TransportFor(config)
+	require.NoError(t, err)
+	client := &http.Client{Transport: rt}
+
+	// fails over, replaying the body
+	resp, err := client.Post(down.URL+"/clusters/old/api/v1/namespaces", "application/json", bytes.NewReader([]byte(`{"kind":"Namespace"}`)))
+	require.NoError(t, err)
+	resp.Body.Close() // nolint:errcheck
+
+	// sticks to the endpoint that answered
+	resp, err = client.Get(down.URL + "/clusters/old/api/v1/namespaces/default")
+	require.NoError(t, err)
+	resp.Body.Close() // nolint:errcheck
+
+	require.Equal(t, []string{"/clusters/new/api/v1/namespaces", "/clusters/new/api/v1/namespaces/default"}, paths)
+	require.Equal(t, []string{`{"kind":"Namespace"}`, ""}, bodies)
+	require.Equal(t, []string{"Bearer new", "Bearer new"}, tokens)
+
+	// all endpoints down
+	up.Close()
+	_, err = client.Get(down.URL + "/clusters/old/api")
+	require.ErrorContains(t, err, "all endpoints failed")
+}
+
+func TestConfigSingleEndpoint(t *testing.T) {
+	endpoint := &rest.Config{Host: "https://example.com", BearerToken: "token"}
+	config, err := Config(endpoint)
+	require.NoError(t, err)
+	require.Same(t, endpoint, config)
+}
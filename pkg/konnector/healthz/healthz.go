@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/klog/v2"
+)
+
+// Status is the liveness and readiness of a konnector process. It is updated
+// by the konnector command as it starts informers, acquires the leader lease
+// and starts the controllers.
+type Status struct {
+	informersSynced    atomic.Bool
+	leading            atomic.Bool
+	controllersStarted atomic.Bool
+
+	leaderElection *leaderelection.HealthzAdaptor
+}
+
+type check struct {
+	name  string
+	check func(r *http.Request) error
+}
+
+// NewStatus returns a Status whose liveness reflects the given leader election
+// watchdog. It can be nil if leader election is not used.
+func NewStatus(leaderElection *leaderelection.HealthzAdaptor) *Status {
+	return &Status{leaderElection: leaderElection}
+}
+
+// SetInformersSynced records whether the informer caches of all consumer clusters have synced.
+func (s *Status) SetInformersSynced(synced bool) {
+	s.informersSynced.Store(synced)
+}
+
+// SetLeading records whether this konnector holds the leader lease.
+func (s *Status) SetLeading(leading bool) {
+	s.leading.Store(leading)
+}
+
+// SetControllersStarted records whether the controllers of the leader are running.
+func (s *Status) SetControllersStarted(started bool) {
+	s.controllersStarted.Store(started)
+}
+
+func (s *Status) livenessChecks() []check {
+	return []check{
+		{"ping", func(*http.Request) error { return nil }},
+		{"leader-election", func(r *http.Request) error {
+			if s.leaderElection == nil {
+				return nil
+			}
+			return s.leaderElection.Check(r)
+		}},
+	}
+}
+
+func (s *Status) readinessChecks() []check {
+	return append(s.livenessChecks(),
+		check{"informer-sync", func(*http.Request) error {
+			if !s.informersSynced.Load() {
+				return errors.New("informers not synced")
+			}
+			return nil
+		}},
+		// a standby replica is ready to take over once its informers are synced.
+		check{"controllers", func(*http.Request) error {
+			if s.leading.Load() && !s.controllersStarted.Load() {
+				return errors.New("leading, but controllers not started")
+			}
+			return nil
+		}},
+	)
+}
+
+// Handler serves /healthz for liveness and /readyz for readiness probes. Failed
+// checks are listed in the response, and all checks with ?verbose.
+func (s *Status) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		serveChecks(w, r, "healthz", s.livenessChecks())
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		serveChecks(w, r, "readyz", s.readinessChecks())
+	})
+	return mux
+}
+
+func serveChecks(w http.ResponseWriter, r *http.Request, name string, checks []check) {
+	var out bytes.Buffer
+	failed := false
+	for _, c := range checks {
+		if err := c.check(r); err != nil {
+			failed = true
+			fmt.Fprintf(&out, "[-]%s failed: %v\n", c.name, err)
+		} else {
+			fmt.Fprintf(&out, "[+]%s ok\n", c.name)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if failed {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(&out, "%s check failed\n", name)
+		w.Write(out.Bytes()) // nolint:errcheck
+		return
+	}
+	if _, verbose := r.URL.Query()["verbose"]; verbose {
+		fmt.Fprintf(&out, "%s check passed\n", name)
+		w.Write(out.Bytes()) // nolint:errcheck
+		return
+	}
+	fmt.Fprint(w, "ok") // nolint:errcheck
+}
+
+// Serve serves the probes on the given address until ctx is done.
+func (s *Status) Serve(ctx context.Context, address string) {
+	logger := klog.FromContext(ctx)
+
+	server := &http.Server{Addr: address, Handler: s.Handler()}
+	go func() {
+		<-ctx.Done()
+		server.Close() // nolint:errcheck
+	}()
+	go func() {
+		logger.Info("serving health probes", "address", address)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(err, "failed to serve health probes")
+		}
+	}()
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name               string
+		informersSynced    bool
+		leading            bool
+		controllersStarted bool
+		wantHealthz        int
+		wantReadyz         int
+	}{
+		{name: "informers syncing", wantHealthz: http.StatusOK, wantReadyz: http.StatusInternalServerError},
+		{name: "standby", informersSynced: true, wantHealthz: http.StatusOK, wantReadyz: http.StatusOK},
+		{name: "leading, starting", informersSynced: true, leading: true, wantHealthz: http.StatusOK, wantReadyz: http.StatusInternalServerError},
+		{name: "leading, started", informersSynced: true, leading: true, controllersStarted: true, wantHealthz: http.StatusOK, wantReadyz: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStatus(nil)
+			s.SetInformersSynced(tt.informersSynced)
+			s.SetLeading(tt.leading)
+			s.SetControllersStarted(tt.controllersStarted)
+
+			for path, want := range map[string]int{"/healthz": tt.wantHealthz, "/readyz?verbose": tt.wantReadyz} {
+				rec := httptest.NewRecorder()
+				s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+				require.Equal(t, want, rec.Code, "%s: %s", path, rec.Body.String())
+			}
+		})
+	}
+}
@@ -19,23 +19,29 @@ package konnector
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	crdinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
 	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
-	coreinformers "k8s.io/client-go/informers/core/v1"
-	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/informers"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
 	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
@@ -43,7 +49,14 @@ import (
 	"github.com/kube-bind/kube-bind/pkg/indexers"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/cluster"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/dynamic"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/rbac"
 	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/servicebinding"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/dryrun"
+	"github.com/kube-bind/kube-bind/pkg/konnector/policy"
+	"github.com/kube-bind/kube-bind/pkg/konnector/secrets"
+	"github.com/kube-bind/kube-bind/pkg/konnector/selfupgrade"
+	"github.com/kube-bind/kube-bind/pkg/konnector/sharding"
 )
 
 const (
@@ -54,28 +67,80 @@ const (
 func New(
 	consumerConfig *rest.Config,
 	serviceBindingInformer bindinformers.APIServiceBindingInformer,
-	secretInformer coreinformers.SecretInformer,
-	namespaceInformer coreinformers.NamespaceInformer,
+	trustPolicyInformer bindinformers.BackendTrustPolicyInformer,
+	secretWatcher *secrets.Watcher,
+	namespaceInformer informers.GenericInformer,
 	crdInformer crdinformers.CustomResourceDefinitionInformer,
+	clusterRoleInformer rbacinformers.ClusterRoleInformer,
+	syncedMaxStaleness time.Duration,
+	hibernateIdleAfter time.Duration,
+	canaryInterval time.Duration,
+	snapshotDir string,
+	syncTuning tuning.Sync,
+	workers tuning.Workers,
+	upsyncPolicies []policy.Policy,
+	crdAllowlist []string,
+	rbacClusterRole string,
+	allowedRegions []string,
+	watchNamespaces []string,
+	syncedPrinterColumns bool,
+	requireApproval bool,
+	providerProxyURL string,
+	providerCA ProviderCA,
+	providerAPI tuning.Client,
+	resync tuning.Resync,
+	rateLimiter tuning.RateLimiter,
+	shards sharding.Owner,
+	versionRequirements *selfupgrade.Requirements,
+	identity string,
+	dryRun bool,
 ) (*Controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter.New(), controllerName)
 
 	logger := klog.Background().WithValues("Controller", controllerName)
 
 	consumerConfig = rest.CopyConfig(consumerConfig)
 	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
+	if dryRun {
+		consumerConfig = dryrun.Wrap(consumerConfig, "consumer")
+	}
 
 	bindClient, err := bindclient.NewForConfig(consumerConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	servicebindingCtrl, err := servicebinding.NewController(consumerConfig, serviceBindingInformer, secretInformer, crdInformer)
+	var providerProxy func(*http.Request) (*url.URL, error)
+	if providerProxyURL != "" {
+		u, err := url.Parse(providerProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		providerProxy = http.ProxyURL(u)
+	}
+
+	servicebindingCtrl, err := servicebinding.NewController(consumerConfig, serviceBindingInformer, trustPolicyInformer, secretWatcher, crdInformer, requireApproval, rateLimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	upsyncPolicy, err := policy.NewEvaluator(upsyncPolicies, func(name string) (*metav1.PartialObjectMetadata, error) {
+		obj, err := namespaceInformer.Lister().Get(name)
+		if err != nil {
+			return nil, err
+		}
+		return obj.(*metav1.PartialObjectMetadata), nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	namespaceDynamicInformer := dynamic.NewDynamicInformer[corelisters.NamespaceLister](namespaceInformer)
+	rbacCtrl, err := rbac.NewController(consumerConfig, rbacClusterRole, serviceBindingInformer, crdInformer, clusterRoleInformer, rateLimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaceDynamicInformer := dynamic.NewDynamicInformer[cache.GenericLister](namespaceInformer)
 	serviceBindingDynamicInformer := dynamic.NewDynamicInformer[bindlisters.APIServiceBindingLister](serviceBindingInformer)
 	crdDynamicInformer := dynamic.NewDynamicInformer[apiextensionslisters.CustomResourceDefinitionLister](crdInformer)
 	c := &Controller{
@@ -87,28 +152,60 @@ func New(
 		serviceBindingLister:  serviceBindingInformer.Lister(),
 		serviceBindingIndexer: serviceBindingInformer.Informer().GetIndexer(),
 
-		secretLister:  secretInformer.Lister(),
-		secretIndexer: secretInformer.Informer().GetIndexer(),
+		secretWatcher: secretWatcher,
 
 		ServiceBindingCtrl: servicebindingCtrl,
+		RBACCtrl:           rbacCtrl,
+
+		workers: workers,
 
 		reconciler: reconciler{
-			controllers: map[string]*controllerContext{},
+			controllers:         map[string]*controllerContext{},
+			requireApproval:     requireApproval,
+			shards:              shards,
+			paused:              sets.NewString(),
+			providerProxy:       providerProxy,
+			getProviderCABundle: providerCA.Bundle,
 			getSecret: func(ns, name string) (*corev1.Secret, error) {
-				return secretInformer.Lister().Secrets(ns).Get(name)
+				return secretWatcher.Get(ns, name)
+			},
+			listTrustPolicies: func() ([]*kubebindv1alpha1.BackendTrustPolicy, error) {
+				return trustPolicyInformer.Lister().List(labels.Everything())
 			},
-			newClusterController: func(consumerSecretRefKey, providerNamespace string, providerConfig *rest.Config) (startable, error) {
-				providerConfig = rest.CopyConfig(providerConfig)
+			newClusterController: func(consumerSecretRefKey, providerNamespace string, providerConfig, virtualClusterConfig *rest.Config) (startable, error) {
+				providerConfig = providerAPI.Config(providerConfig)
 				providerConfig = rest.AddUserAgent(providerConfig, controllerName)
+				if dryRun {
+					providerConfig = dryrun.Wrap(providerConfig, "provider")
+					if virtualClusterConfig != nil {
+						virtualClusterConfig = dryrun.Wrap(virtualClusterConfig, "provider")
+					}
+				}
 
 				return cluster.NewController(
 					consumerSecretRefKey,
 					providerNamespace,
 					consumerConfig,
 					providerConfig,
+					virtualClusterConfig,
 					namespaceDynamicInformer,
 					serviceBindingDynamicInformer,
 					crdDynamicInformer,
+					syncedMaxStaleness,
+					hibernateIdleAfter,
+					canaryInterval,
+					snapshotDir,
+					syncTuning,
+					resync,
+					workers,
+					upsyncPolicy,
+					crdAllowlist,
+					allowedRegions,
+					watchNamespaces,
+					syncedPrinterColumns,
+					versionRequirements,
+					identity,
+					rateLimiter,
 				)
 			},
 		},
@@ -122,7 +219,9 @@ func New(
 
 	// nolint:errcheck
 	indexers.AddIfNotPresentOrDie(serviceBindingInformer.Informer().GetIndexer(), cache.Indexers{
-		indexers.ByServiceBindingKubeconfigSecret: indexers.IndexServiceBindingByKubeconfigSecret,
+		indexers.ByServiceBindingKubeconfigSecret:     indexers.IndexServiceBindingByKubeconfigSecret,
+		indexers.ByServiceBindingVirtualClusterSecret: indexers.IndexServiceBindingByVirtualClusterSecret,
+		indexers.ByServiceBindingExport:               indexers.IndexServiceBindingByExport,
 	})
 
 	indexers.AddIfNotPresentOrDie(crdInformer.Informer().GetIndexer(), cache.Indexers{
@@ -141,7 +240,7 @@ func New(
 		},
 	})
 
-	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	secretWatcher.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			c.enqueueSecret(logger, obj)
 		},
@@ -152,6 +251,44 @@ func New(
 			c.enqueueSecret(logger, obj)
 		},
 	})
+
+	trustPolicyInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueAllServiceBindings(logger, "BackendTrustPolicy", obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueAllServiceBindings(logger, "BackendTrustPolicy", newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueAllServiceBindings(logger, "BackendTrustPolicy", obj)
+		},
+	})
+
+	shards.AddHandler(func() {
+		bindings, err := c.serviceBindingLister.List(labels.Everything())
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		for _, binding := range bindings {
+			logger.V(2).Info("queueing APIServiceBinding", "key", binding.Name, "reason", "ShardMembershipChanged")
+			c.queue.Add(binding.Name)
+		}
+	})
+
+	if providerCA.ConfigMapInformer != nil {
+		providerCA.ConfigMapInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueAllServiceBindings(logger, "ProviderCAConfigMap", obj)
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				c.enqueueAllServiceBindings(logger, "ProviderCAConfigMap", newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				c.enqueueAllServiceBindings(logger, "ProviderCAConfigMap", obj)
+			},
+		})
+	}
 	return c, nil
 }
 
@@ -174,10 +311,12 @@ type Controller struct {
 	serviceBindingLister  bindlisters.APIServiceBindingLister
 	serviceBindingIndexer cache.Indexer
 
-	secretLister  corelisters.SecretLister
-	secretIndexer cache.Indexer
+	secretWatcher *secrets.Watcher
 
 	ServiceBindingCtrl GenericController
+	RBACCtrl           GenericController
+
+	workers tuning.Workers
 
 	reconciler
 
@@ -193,6 +332,34 @@ func (c *Controller) enqueueServiceBinding(logger klog.Logger, obj interface{})
 
 	logger.V(2).Info("queueing APIServiceBinding", "key", key)
 	c.queue.Add(key)
+
+	// bindings of the same kubeconfig secret might conflict with this one, or not anymore
+	binding, ok := obj.(*kubebindv1alpha1.APIServiceBinding)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		if binding, ok = tombstone.Obj.(*kubebindv1alpha1.APIServiceBinding); !ok {
+			return
+		}
+	}
+	if binding.Spec.VirtualCluster == nil && !conditions.Has(binding, kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid) {
+		return
+	}
+	others, err := c.serviceBindingIndexer.ByIndex(indexers.ByServiceBindingKubeconfigSecret, indexers.ByServiceBindingKubeconfigSecretKey(binding))
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, obj := range others {
+		other := obj.(*kubebindv1alpha1.APIServiceBinding)
+		if other.Name == binding.Name {
+			continue
+		}
+		logger.V(2).Info("queueing APIServiceBinding", "key", other.Name, "reason", "APIServiceBindingOfSameSecret", "APIServiceBindingKey", key)
+		c.queue.Add(other.Name)
+	}
 }
 
 func (c *Controller) enqueueSecret(logger klog.Logger, obj interface{}) {
@@ -212,6 +379,12 @@ func (c *Controller) enqueueSecret(logger klog.Logger, obj interface{}) {
 		runtime.HandleError(err)
 		return
 	}
+	virtualClusterBindings, err := c.serviceBindingIndexer.ByIndex(indexers.ByServiceBindingVirtualClusterSecret, fmt.Sprintf("%s/%s", ns, name))
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	bindings = append(bindings, virtualClusterBindings...)
 	if len(bindings) == 0 {
 		return
 	}
@@ -227,6 +400,24 @@ func (c *Controller) enqueueSecret(logger klog.Logger, obj interface{}) {
 	}
 }
 
+func (c *Controller) enqueueAllServiceBindings(logger klog.Logger, reason string, obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	bindings, err := c.serviceBindingLister.List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, binding := range bindings {
+		logger.V(2).Info("queueing APIServiceBinding", "key", binding.Name, "reason", reason, reason+"Key", key)
+		c.queue.Add(binding.Name)
+	}
+}
+
 // Start starts the konnector. It does block.
 func (k *Controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
@@ -241,7 +432,10 @@ func (k *Controller) Start(ctx context.Context, numThreads int) {
 		go wait.UntilWithContext(ctx, k.startWorker, time.Second)
 	}
 
-	go k.ServiceBindingCtrl.Start(ctx, numThreads)
+	k.secretWatcher.Start(ctx)
+
+	go k.ServiceBindingCtrl.Start(ctx, k.workers.NumServiceBinding())
+	go k.RBACCtrl.Start(ctx, 1)
 
 	<-ctx.Done()
 }
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/servicebinding"
+	"github.com/kube-bind/kube-bind/pkg/konnector/management"
+	"github.com/kube-bind/kube-bind/pkg/konnector/sharding"
+)
+
+var _ management.Backend = &Controller{}
+
+// Bindings returns the live state of all APIServiceBindings.
+func (c *Controller) Bindings() ([]management.Binding, error) {
+	bindings, err := c.serviceBindingLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(bindings, func(i, j int) bool { return bindings[i].Name < bindings[j].Name })
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ret := make([]management.Binding, 0, len(bindings))
+	for _, binding := range bindings {
+		ref := binding.Spec.KubeconfigSecretRef
+		b := management.Binding{
+			Name:             binding.Name,
+			KubeconfigSecret: ref.Namespace + "/" + ref.Name,
+			Owned:            c.shards.Owns(sharding.BindingKey(binding)),
+			Paused:           c.paused.Has(binding.Name) || servicebinding.Paused(binding),
+
+			AcceptedClaims:                    binding.Spec.AcceptedClaims,
+			AcceptedServiceAccountTokenClaims: binding.Spec.AcceptedServiceAccountTokenClaims,
+		}
+		if cond := conditions.Get(binding, conditionsapi.ReadyCondition); cond != nil {
+			b.Ready = string(cond.Status)
+		}
+		for _, cond := range binding.Status.Conditions {
+			if cond.Status != corev1.ConditionTrue {
+				b.Problems = append(b.Problems, cond)
+			}
+		}
+		sort.SliceStable(b.Problems, func(i, j int) bool {
+			return b.Problems[j].LastTransitionTime.Before(&b.Problems[i].LastTransitionTime)
+		})
+		if ctrlContext, found := c.controllers[binding.Name]; found {
+			since := metav1.NewTime(ctrlContext.started)
+			b.Syncing = true
+			b.SyncingSince = &since
+			b.ProviderNamespace = ctrlContext.providerNamespace
+		}
+		ret = append(ret, b)
+	}
+	return ret, nil
+}
+
+// Resync restarts the cluster controller of the named APIServiceBinding with
+// fresh informers. APIServiceBindings sharing the same kubeconfig share the
+// controller and are restarted too.
+func (c *Controller) Resync(name string) error {
+	if err := c.checkBinding(name); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ctrlContext, found := c.controllers[name]
+	if !found {
+		c.queue.Add(name)
+		return nil
+	}
+	ctrlContext.cancel()
+	for _, n := range ctrlContext.serviceBindings.List() {
+		delete(c.controllers, n)
+		c.queue.Add(n)
+	}
+	return nil
+}
+
+// Pause stops syncing the named APIServiceBinding. It is not persisted and
+// ends when the konnector restarts.
+func (c *Controller) Pause(name string) error {
+	if err := c.checkBinding(name); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.paused.Insert(name)
+	c.lock.Unlock()
+
+	c.queue.Add(name)
+	return nil
+}
+
+// Resume syncs a paused APIServiceBinding again.
+func (c *Controller) Resume(name string) error {
+	if err := c.checkBinding(name); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.paused.Delete(name)
+	c.lock.Unlock()
+
+	c.queue.Add(name)
+	return nil
+}
+
+func (c *Controller) checkBinding(name string) error {
+	if _, err := c.serviceBindingLister.Get(name); errors.IsNotFound(err) {
+		return management.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
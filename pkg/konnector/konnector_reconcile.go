@@ -18,7 +18,11 @@ package konnector
 
 import (
 	"context"
+	"net/http"
+	"net/url"
+	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -28,6 +32,13 @@ import (
 	"k8s.io/klog/v2"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/servicebinding"
+	"github.com/kube-bind/kube-bind/pkg/konnector/failover"
+	"github.com/kube-bind/kube-bind/pkg/konnector/sharding"
+	"github.com/kube-bind/kube-bind/pkg/konnector/tracing"
 )
 
 type startable interface {
@@ -38,20 +49,39 @@ type reconciler struct {
 	lock        sync.Mutex
 	controllers map[string]*controllerContext // by service binding name
 
-	newClusterController func(consumerSecretRefKey, providerNamespace string, providerConfig *rest.Config) (startable, error)
+	requireApproval bool
+	// shards tells which APIServiceBindings this replica serves.
+	shards sharding.Owner
+	// paused are the APIServiceBindings paused through the management API.
+	paused sets.String
+	// providerProxy is used for service provider kubeconfigs without proxy-url.
+	// If nil, the proxy environment variables are honored.
+	providerProxy func(*http.Request) (*url.URL, error)
+	// getProviderCABundle returns the CA bundle trusted in addition to the CA of
+	// service provider kubeconfigs.
+	getProviderCABundle func() ([]byte, error)
+
+	newClusterController func(consumerSecretRefKey, providerNamespace string, providerConfig, virtualClusterConfig *rest.Config) (startable, error)
 	getSecret            func(ns, name string) (*corev1.Secret, error)
+	listTrustPolicies    func() ([]*kubebindv1alpha1.BackendTrustPolicy, error)
 }
 
 type controllerContext struct {
-	kubeconfig      string
-	cancel          func()
-	serviceBindings sets.String // when this is empty, the Controller should be stopped by closing the context
+	kubeconfig               string
+	virtualClusterKubeconfig string // empty if objects materialize in the konnector's cluster
+	caBundle                 string
+	clientCert               string // PEM certificate and key from the secret, if any
+	providerNamespace        string
+	started                  time.Time
+	cancel                   func()
+	serviceBindings          sets.String // when this is empty, the Controller should be stopped by closing the context
 }
 
 func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
 	logger := klog.FromContext(ctx)
 
 	var kubeconfig string
+	var clientCert, clientKey []byte
 
 	ref := binding.Spec.KubeconfigSecretRef
 	secret, err := r.getSecret(ref.Namespace, ref.Name)
@@ -61,15 +91,96 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 		logger.V(2).Info("secret not found", "secret", ref.Namespace+"/"+ref.Name)
 	} else {
 		kubeconfig = string(secret.Data[ref.Key])
+		if clientCert, clientKey, err = clientCertificate(secret); err != nil {
+			logger.Error(err, "invalid client certificate in secret", "secret", ref.Namespace+"/"+ref.Name)
+			kubeconfig = "" // nothing we can do here
+		}
+	}
+
+	if r.requireApproval && !servicebinding.Approved(binding) {
+		logger.V(2).Info("not syncing APIServiceBinding pending approval")
+		kubeconfig = ""
+	}
+
+	if kubeconfig != "" && !r.shards.Owns(sharding.BindingKey(binding)) {
+		logger.V(4).Info("not syncing APIServiceBinding of another shard")
+		kubeconfig = ""
+	}
+
+	if kubeconfig != "" && r.isPaused(binding.Name) {
+		logger.V(2).Info("not syncing paused APIServiceBinding")
+		kubeconfig = ""
+	}
+
+	if kubeconfig != "" {
+		policies, err := r.listTrustPolicies()
+		if err != nil {
+			return err
+		}
+		if err := servicebinding.BackendTrusted(policies, secret, []byte(kubeconfig)); err != nil {
+			// the APIServiceBinding Controller will set a condition
+			logger.Error(err, "not syncing APIServiceBinding of untrusted backend", "secret", ref.Namespace+"/"+ref.Name)
+			kubeconfig = ""
+		}
+	}
+
+	var virtualClusterKubeconfig string
+	if binding.Spec.VirtualCluster == nil {
+		conditions.Delete(binding, kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid)
+	}
+	if vc := binding.Spec.VirtualCluster; vc != nil && kubeconfig != "" {
+		vcRef := vc.KubeconfigSecretRef
+		key := vcRef.Key
+		if key == "" {
+			key = "config"
+		}
+		secret, err := r.getSecret(vcRef.Namespace, vcRef.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		} else if errors.IsNotFound(err) {
+			logger.V(2).Info("virtual cluster secret not found", "secret", vcRef.Namespace+"/"+vcRef.Name)
+			conditions.MarkFalse(
+				binding,
+				kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid,
+				"VirtualClusterSecretNotFound",
+				conditionsapi.ConditionSeverityError,
+				"Virtual cluster kubeconfig secret %s/%s not found.",
+				vcRef.Namespace, vcRef.Name,
+			)
+		} else if virtualClusterKubeconfig = string(secret.Data[key]); virtualClusterKubeconfig == "" {
+			conditions.MarkFalse(
+				binding,
+				kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid,
+				"VirtualClusterSecretInvalid",
+				conditionsapi.ConditionSeverityError,
+				"Virtual cluster kubeconfig secret %s/%s is missing %q string key.",
+				vcRef.Namespace, vcRef.Name, key,
+			)
+		}
+		if virtualClusterKubeconfig == "" {
+			// don't fall back to materializing objects in the host cluster
+			kubeconfig = ""
+		}
+	}
+
+	caBundle, err := r.getProviderCABundle()
+	if err != nil {
+		return err
 	}
 
 	r.lock.Lock()
 	defer r.lock.Unlock()
 	ctrlContext, found := r.controllers[binding.Name]
 
-	// stop existing with old kubeconfig
-	if found && ctrlContext.kubeconfig != kubeconfig {
-		logger.V(2).Info("stopping Controller with old kubeconfig", "secret", ref.Namespace+"/"+ref.Name)
+	// stop existing with old kubeconfig, client certificate or CA bundle. If they
+	// were rotated, a new Controller with fresh clients and informers is started below.
+	clientCertAndKey := string(clientCert) + string(clientKey)
+	if found && (ctrlContext.kubeconfig != kubeconfig || ctrlContext.virtualClusterKubeconfig != virtualClusterKubeconfig || ctrlContext.caBundle != string(caBundle) || ctrlContext.clientCert != clientCertAndKey) {
+		if kubeconfig != "" {
+			logger.Info("restarting Controller with rotated kubeconfig", "secret", ref.Namespace+"/"+ref.Name)
+		} else {
+			logger.V(2).Info("stopping Controller with old kubeconfig", "secret", ref.Namespace+"/"+ref.Name)
+		}
 		ctrlContext.serviceBindings.Delete(binding.Name)
 		if len(ctrlContext.serviceBindings) == 0 {
 			ctrlContext.cancel()
@@ -84,11 +195,24 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 
 	// find existing with new kubeconfig
 	for _, ctrlContext := range r.controllers {
-		if ctrlContext.kubeconfig == kubeconfig {
+		if ctrlContext.kubeconfig == kubeconfig && ctrlContext.caBundle == string(caBundle) && ctrlContext.clientCert == clientCertAndKey {
+			if ctrlContext.virtualClusterKubeconfig != virtualClusterKubeconfig {
+				logger.V(2).Info("not syncing APIServiceBinding targeting another cluster than the existing Controller", "secret", ref.Namespace+"/"+ref.Name, "bindings", ctrlContext.serviceBindings.List())
+				conditions.MarkFalse(
+					binding,
+					kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid,
+					"VirtualClusterConflict",
+					conditionsapi.ConditionSeverityError,
+					"APIServiceBindings %s of the same kubeconfig secret %s/%s target another cluster. All of them must target the same virtual cluster, or none.",
+					strings.Join(ctrlContext.serviceBindings.List(), ", "), ref.Namespace, ref.Name,
+				)
+				return nil
+			}
 			// add to it
 			logger.V(2).Info("adding to existing Controller", "secret", ref.Namespace+"/"+ref.Name)
 			r.controllers[binding.Name] = ctrlContext
 			ctrlContext.serviceBindings.Insert(binding.Name)
+			markVirtualClusterValid(binding)
 			return nil
 		}
 	}
@@ -109,11 +233,39 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 		return nil // nothing we can do here. The APIServiceBinding Controller will set a condition
 	}
 	providerNamespace := kubeContext.Namespace
-	providerConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+	// the kubeconfig can list fallback endpoints the requests fail over to
+	endpoints, err := kubebindhelpers.ProviderRESTConfigs([]byte(kubeconfig))
 	if err != nil {
 		logger.Error(err, "invalid kubeconfig in secret", "namespace", ref.Namespace, "name", ref.Name)
 		return nil // nothing we can do here. The APIServiceBinding Controller will set a condition
 	}
+	for _, endpoint := range endpoints {
+		if endpoint.Proxy == nil && r.providerProxy != nil {
+			endpoint.Proxy = r.providerProxy
+		}
+		if err := withCABundle(endpoint, caBundle); err != nil {
+			logger.Error(err, "failed to add provider CA bundle", "namespace", ref.Namespace, "name", ref.Name)
+			return nil // nothing we can do here
+		}
+		if err := withClientCertificate(endpoint, clientCert, clientKey); err != nil {
+			logger.Error(err, "failed to use client certificate in secret", "namespace", ref.Namespace, "name", ref.Name)
+			return nil // nothing we can do here
+		}
+	}
+	providerConfig, err := failover.Config(endpoints...)
+	if err != nil {
+		logger.Error(err, "failed to set up fallback endpoints in secret", "namespace", ref.Namespace, "name", ref.Name)
+		return nil // nothing we can do here
+	}
+	tracing.WrapConfig(providerConfig)
+
+	var virtualClusterConfig *rest.Config
+	if virtualClusterKubeconfig != "" {
+		if virtualClusterConfig, err = clientcmd.RESTConfigFromKubeConfig([]byte(virtualClusterKubeconfig)); err != nil {
+			logger.Error(err, "invalid virtual cluster kubeconfig in secret", "namespace", binding.Spec.VirtualCluster.KubeconfigSecretRef.Namespace, "name", binding.Spec.VirtualCluster.KubeconfigSecretRef.Name)
+			return nil // nothing we can do here
+		}
+	}
 
 	// create new because there is none yet for this kubeconfig
 	logger.V(2).Info("starting new Controller", "secret", ref.Namespace+"/"+ref.Name)
@@ -121,6 +273,7 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 		binding.Spec.KubeconfigSecretRef.Namespace+"/"+binding.Spec.KubeconfigSecretRef.Name,
 		providerNamespace,
 		providerConfig,
+		virtualClusterConfig,
 	)
 	if err != nil {
 		logger.Error(err, "failed to start new cluster Controller")
@@ -129,11 +282,33 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 
 	ctrlCtx, cancel := context.WithCancel(ctx)
 	r.controllers[binding.Name] = &controllerContext{
-		kubeconfig:      kubeconfig,
-		cancel:          cancel,
-		serviceBindings: sets.NewString(binding.Name),
+		kubeconfig:               kubeconfig,
+		virtualClusterKubeconfig: virtualClusterKubeconfig,
+		caBundle:                 string(caBundle),
+		clientCert:               clientCertAndKey,
+		providerNamespace:        providerNamespace,
+		started:                  time.Now(),
+		cancel:                   cancel,
+		serviceBindings:          sets.NewString(binding.Name),
 	}
 	go ctrl.Start(ctrlCtx)
+	markVirtualClusterValid(binding)
 
 	return nil
 }
+
+// markVirtualClusterValid sets the VirtualClusterValid condition if the binding
+// targets a virtual cluster, and removes a conflict otherwise.
+func markVirtualClusterValid(binding *kubebindv1alpha1.APIServiceBinding) {
+	if binding.Spec.VirtualCluster == nil {
+		conditions.Delete(binding, kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid)
+		return
+	}
+	conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid)
+}
+
+func (r *reconciler) isPaused(name string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.paused.Has(name)
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kube-bind/kube-bind/pkg/konnector/sharding"
+)
+
+func newKubeconfig(t *testing.T, server, namespace string) []byte {
+	t.Helper()
+
+	cfg := clientcmdapi.NewConfig()
+	cfg.Clusters["default"] = &clientcmdapi.Cluster{Server: server}
+	cfg.AuthInfos["default"] = &clientcmdapi.AuthInfo{Token: "token"}
+	cfg.Contexts["default"] = &clientcmdapi.Context{Cluster: "default", AuthInfo: "default", Namespace: namespace}
+	cfg.CurrentContext = "default"
+	bs, err := clientcmd.Write(*cfg)
+	require.NoError(t, err)
+	return bs
+}
+
+func newVirtualClusterBinding(name string, vc *kubebindv1alpha1.VirtualClusterTarget) *kubebindv1alpha1.APIServiceBinding {
+	return &kubebindv1alpha1.APIServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: kubebindv1alpha1.APIServiceBindingSpec{
+			KubeconfigSecretRef: kubebindv1alpha1.ClusterSecretKeyRef{
+				LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{Name: "kubeconfig", Key: "kubeconfig"},
+				Namespace:         "kube-bind",
+			},
+			VirtualCluster: vc,
+		},
+	}
+}
+
+type startedController struct {
+	virtualClusterHost string // empty without virtual cluster
+	ctx                chan context.Context
+}
+
+func (c *startedController) Start(ctx context.Context) {
+	c.ctx <- ctx
+}
+
+// newVirtualClusterReconciler returns a reconciler with the given secrets, recording
+// the started cluster controllers.
+func newVirtualClusterReconciler(secrets map[string]*corev1.Secret, started *[]*startedController) *reconciler {
+	return &reconciler{
+		controllers: map[string]*controllerContext{},
+		shards:      sharding.All,
+		paused:      sets.NewString(),
+		getProviderCABundle: func() ([]byte, error) {
+			return nil, nil
+		},
+		getSecret: func(ns, name string) (*corev1.Secret, error) {
+			if s, found := secrets[ns+"/"+name]; found {
+				return s, nil
+			}
+			return nil, errors.NewNotFound(corev1.Resource("secrets"), name)
+		},
+		listTrustPolicies: func() ([]*kubebindv1alpha1.BackendTrustPolicy, error) {
+			return nil, nil
+		},
+		newClusterController: func(consumerSecretRefKey, providerNamespace string, providerConfig, virtualClusterConfig *rest.Config) (startable, error) {
+			c := &startedController{ctx: make(chan context.Context, 1)}
+			if virtualClusterConfig != nil {
+				c.virtualClusterHost = virtualClusterConfig.Host
+			}
+			*started = append(*started, c)
+			return c, nil
+		},
+	}
+}
+
+func TestReconcileVirtualCluster(t *testing.T) {
+	vc := &kubebindv1alpha1.VirtualClusterTarget{
+		KubeconfigSecretRef: kubebindv1alpha1.VirtualClusterSecretKeyRef{Namespace: "tenant-a", Name: "vc-a"},
+	}
+	providerSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig"},
+		Data:       map[string][]byte{"kubeconfig": newKubeconfig(t, "https://provider", "cluster-abc")},
+	}
+
+	tests := []struct {
+		name          string
+		vcSecret      *corev1.Secret
+		wantStarted   bool
+		wantVCHost    string
+		wantCondition corev1.ConditionStatus
+		wantReason    string
+	}{
+		{
+			name:          "secret missing",
+			wantCondition: corev1.ConditionFalse,
+			wantReason:    "VirtualClusterSecretNotFound",
+		},
+		{
+			name: "key missing",
+			vcSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "vc-a"},
+				Data:       map[string][]byte{"kubeconfig": newKubeconfig(t, "https://vcluster-a", "default")},
+			},
+			wantCondition: corev1.ConditionFalse,
+			wantReason:    "VirtualClusterSecretInvalid",
+		},
+		{
+			name: "valid",
+			vcSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "vc-a"},
+				Data:       map[string][]byte{"config": newKubeconfig(t, "https://vcluster-a", "default")},
+			},
+			wantStarted:   true,
+			wantVCHost:    "https://vcluster-a",
+			wantCondition: corev1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secrets := map[string]*corev1.Secret{"kube-bind/kubeconfig": providerSecret}
+			if tt.vcSecret != nil {
+				secrets["tenant-a/vc-a"] = tt.vcSecret
+			}
+			var started []*startedController
+			r := newVirtualClusterReconciler(secrets, &started)
+
+			binding := newVirtualClusterBinding("foo", vc)
+			err := r.reconcile(context.Background(), binding)
+			require.NoError(t, err)
+
+			cond := conditions.Get(binding, kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid)
+			require.NotNil(t, cond)
+			require.Equal(t, tt.wantCondition, cond.Status)
+			require.Equal(t, tt.wantReason, cond.Reason)
+
+			if !tt.wantStarted {
+				require.Empty(t, started, "must not fall back to materializing objects in the host cluster")
+				require.Empty(t, r.controllers)
+				return
+			}
+			require.Len(t, started, 1)
+			require.Equal(t, tt.wantVCHost, started[0].virtualClusterHost)
+			require.Contains(t, r.controllers, "foo")
+		})
+	}
+}
+
+func TestReconcileVirtualClusterRestart(t *testing.T) {
+	vcSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "vc-a"},
+		Data:       map[string][]byte{"config": newKubeconfig(t, "https://vcluster-a", "default")},
+	}
+	secrets := map[string]*corev1.Secret{
+		"kube-bind/kubeconfig": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig"},
+			Data:       map[string][]byte{"kubeconfig": newKubeconfig(t, "https://provider", "cluster-abc")},
+		},
+		"tenant-a/vc-a": vcSecret,
+	}
+	var started []*startedController
+	r := newVirtualClusterReconciler(secrets, &started)
+
+	binding := newVirtualClusterBinding("foo", &kubebindv1alpha1.VirtualClusterTarget{
+		KubeconfigSecretRef: kubebindv1alpha1.VirtualClusterSecretKeyRef{Namespace: "tenant-a", Name: "vc-a", Key: "config"},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, r.reconcile(ctx, binding))
+	require.Len(t, started, 1)
+	oldCtx := <-started[0].ctx
+
+	// unchanged config does not restart
+	require.NoError(t, r.reconcile(ctx, binding))
+	require.Len(t, started, 1)
+
+	// the virtual cluster kubeconfig changes, e.g. the vcluster was recreated
+	secrets["tenant-a/vc-a"] = &corev1.Secret{
+		ObjectMeta: vcSecret.ObjectMeta,
+		Data:       map[string][]byte{"config": newKubeconfig(t, "https://vcluster-a-recreated", "default")},
+	}
+	require.NoError(t, r.reconcile(ctx, binding))
+	require.Len(t, started, 2)
+	require.Error(t, oldCtx.Err(), "old controller should be stopped")
+	require.Equal(t, "https://vcluster-a-recreated", started[1].virtualClusterHost)
+}
+
+func TestReconcileVirtualClusterConflict(t *testing.T) {
+	secrets := map[string]*corev1.Secret{
+		"kube-bind/kubeconfig": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig"},
+			Data:       map[string][]byte{"kubeconfig": newKubeconfig(t, "https://provider", "cluster-abc")},
+		},
+		"tenant-a/vc-a": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "vc-a"},
+			Data:       map[string][]byte{"config": newKubeconfig(t, "https://vcluster-a", "default")},
+		},
+	}
+	var started []*startedController
+	r := newVirtualClusterReconciler(secrets, &started)
+
+	foo := newVirtualClusterBinding("foo", &kubebindv1alpha1.VirtualClusterTarget{
+		KubeconfigSecretRef: kubebindv1alpha1.VirtualClusterSecretKeyRef{Namespace: "tenant-a", Name: "vc-a"},
+	})
+	require.NoError(t, r.reconcile(context.Background(), foo))
+	require.True(t, conditions.IsTrue(foo, kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid))
+
+	// bar uses the same kubeconfig secret, but targets the host cluster
+	bar := newVirtualClusterBinding("bar", nil)
+	require.NoError(t, r.reconcile(context.Background(), bar))
+	require.Len(t, started, 1)
+	require.NotContains(t, r.controllers, "bar")
+	cond := conditions.Get(bar, kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid)
+	require.NotNil(t, cond)
+	require.Equal(t, corev1.ConditionFalse, cond.Status)
+	require.Equal(t, "VirtualClusterConflict", cond.Reason)
+	require.Contains(t, cond.Message, "foo")
+
+	// once bar targets the same virtual cluster, it joins the controller
+	bar.Spec.VirtualCluster = foo.Spec.VirtualCluster
+	require.NoError(t, r.reconcile(context.Background(), bar))
+	require.Len(t, started, 1)
+	require.Contains(t, r.controllers, "bar")
+	require.True(t, conditions.IsTrue(bar, kubebindv1alpha1.APIServiceBindingConditionVirtualClusterValid))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/client-go/rest"
+)
+
+// Client calls the management API of one konnector pod through the pods/proxy
+// subresource of the API server.
+type Client struct {
+	restClient rest.Interface
+	namespace  string
+	pod        string
+	port       int
+	token      string
+}
+
+// NewClient returns a Client for the given pod. The rest.Interface must be a
+// core/v1 client, e.g. from kubernetes.Interface.CoreV1().RESTClient().
+func NewClient(restClient rest.Interface, namespace, pod string, port int, token string) *Client {
+	return &Client{
+		restClient: restClient,
+		namespace:  namespace,
+		pod:        pod,
+		port:       port,
+		token:      token,
+	}
+}
+
+func (c *Client) request(verb string, path ...string) *rest.Request {
+	return c.restClient.Verb(verb).
+		Namespace(c.namespace).
+		Resource("pods").
+		Name(fmt.Sprintf("%s:%d", c.pod, c.port)).
+		SubResource("proxy").
+		Suffix(path...).
+		SetHeader(TokenHeader, c.token)
+}
+
+// Bindings returns the live state of the APIServiceBindings in the pod.
+func (c *Client) Bindings(ctx context.Context) ([]Binding, error) {
+	bs, err := c.request("GET", "v1", "bindings").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+	var bindings []Binding
+	if err := json.Unmarshal(bs, &bindings); err != nil {
+		return nil, err
+	}
+	return bindings, nil
+}
+
+// Diagnostics returns a diagnostics snapshot of the pod.
+func (c *Client) Diagnostics(ctx context.Context) (*Diagnostics, error) {
+	bs, err := c.request("GET", "v1", "diagnostics").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+	var diagnostics Diagnostics
+	if err := json.Unmarshal(bs, &diagnostics); err != nil {
+		return nil, err
+	}
+	return &diagnostics, nil
+}
+
+// Action calls resync, pause or resume for the named APIServiceBinding of the
+// given consumer.
+func (c *Client) Action(ctx context.Context, action, consumer, name string) error {
+	return c.request("POST", "v1", "bindings", name, action).
+		Param("consumer", consumer).
+		Do(ctx).
+		Error()
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package management
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// DashboardPath serves the embedded read-only consumer dashboard.
+const DashboardPath = "/dashboard/"
+
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// addDashboardRoutes serves the static files of the dashboard. They contain
+// no data. The page asks for the management token and polls the diagnostics
+// with relative URLs, such that it works through port-forwarding as well as
+// through the pods/proxy subresource of the API server.
+func addDashboardRoutes(mux *mux.Router) {
+	files, err := fs.Sub(dashboardFiles, "dashboard")
+	if err != nil {
+		panic(err) // embedded at build time
+	}
+	fileServer := http.StripPrefix(DashboardPath, http.FileServer(http.FS(files)))
+	mux.Handle(DashboardPath[:len(DashboardPath)-1], http.RedirectHandler(DashboardPath, http.StatusMovedPermanently)).Methods("GET")
+	mux.PathPrefix(DashboardPath).Methods("GET").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		fileServer.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,134 @@
+// The consumer dashboard renders the diagnostics of the konnector replica
+// serving it. It only reads, and authenticates with the management token.
+"use strict";
+
+const tokenKey = "kube-bind-konnector-dashboard-token";
+const tokenHeader = "X-Kube-Bind-Management-Token";
+const refreshInterval = 15000;
+
+let timer;
+
+function el(tag, attrs, ...children) {
+  const e = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    e.setAttribute(k, v);
+  }
+  for (const c of children) {
+    e.append(c === undefined || c === null ? "" : c);
+  }
+  return e;
+}
+
+function age(t) {
+  if (!t) {
+    return "";
+  }
+  const s = Math.max(0, Math.round((Date.now() - Date.parse(t)) / 1000));
+  if (s < 120) {
+    return s + "s";
+  } else if (s < 7200) {
+    return Math.round(s / 60) + "m";
+  } else if (s < 172800) {
+    return Math.round(s / 3600) + "h";
+  }
+  return Math.round(s / 86400) + "d";
+}
+
+async function get(path) {
+  // relative to the dashboard, such that it works through pods/proxy too.
+  const resp = await fetch("../" + path, {headers: {[tokenHeader]: sessionStorage.getItem(tokenKey)}});
+  if (resp.status === 401) {
+    logout();
+    throw new Error("the token is invalid");
+  }
+  if (!resp.ok) {
+    throw new Error((await resp.text()).trim() || resp.statusText);
+  }
+  return resp.json();
+}
+
+function show(text, isError) {
+  const m = document.getElementById("message");
+  m.textContent = text;
+  m.className = isError ? "error" : "";
+}
+
+function sync(b) {
+  if (b.paused) {
+    return el("span", {class: "Unknown"}, "Paused");
+  } else if (!b.owned) {
+    return el("span", {}, "Other shard");
+  } else if (b.syncing) {
+    return el("span", {class: "True", title: b.syncingSince}, "Syncing for " + age(b.syncingSince));
+  }
+  return el("span", {class: "False"}, "Not syncing");
+}
+
+function claims(b) {
+  const items = (b.acceptedClaims || []).map((c) => el("li", {}, c.resource + (c.group ? "." + c.group : "")))
+    .concat((b.acceptedServiceAccountTokenClaims || []).map((c) =>
+      el("li", {}, "token of " + c.namespace + "/" + c.name + " for " + c.audience)));
+  return items.length ? el("ul", {}, ...items) : "";
+}
+
+function problems(b) {
+  const items = (b.problems || []).map((c) => el("li", {class: c.severity === "Warning" ? "Unknown" : "False", title: c.lastTransitionTime},
+    c.type + ": " + (c.reason || c.status) + (c.message ? " – " + c.message : "") + " (" + age(c.lastTransitionTime) + " ago)"));
+  return items.length ? el("ul", {}, ...items) : "";
+}
+
+function renderBindings(bindings) {
+  const rows = bindings.map((b) => el("tr", {},
+    el("td", {title: "kubeconfig " + b.kubeconfigSecret}, (b.consumer ? b.consumer + "/" : "") + b.name),
+    el("td", {class: b.ready || "Unknown"}, b.ready || "Unknown"),
+    el("td", {}, sync(b)),
+    el("td", {}, b.providerNamespace || ""),
+    el("td", {}, claims(b)),
+    el("td", {}, problems(b))));
+  document.getElementById("bindings").replaceChildren(...(rows.length ? rows : [el("tr", {}, el("td", {colspan: 6}, "No APIServiceBindings"))]));
+}
+
+async function refresh() {
+  clearTimeout(timer);
+  if (!sessionStorage.getItem(tokenKey)) {
+    return;
+  }
+  try {
+    const diagnostics = await get("v1/diagnostics");
+    document.getElementById("konnector").textContent = [diagnostics.identity, diagnostics.version].filter((s) => s).join(" · ");
+    renderBindings(diagnostics.bindings);
+    show("Updated " + new Date(diagnostics.time).toLocaleTimeString(), false);
+  } catch (e) {
+    show(e.message, true);
+  }
+  timer = setTimeout(refresh, refreshInterval);
+}
+
+function login(token) {
+  sessionStorage.setItem(tokenKey, token);
+  document.getElementById("login").hidden = true;
+  document.getElementById("dashboard").hidden = false;
+  document.getElementById("logout").hidden = false;
+  refresh();
+}
+
+function logout() {
+  clearTimeout(timer);
+  sessionStorage.removeItem(tokenKey);
+  document.getElementById("login").hidden = false;
+  document.getElementById("dashboard").hidden = true;
+  document.getElementById("logout").hidden = true;
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+  document.getElementById("login-form").addEventListener("submit", (e) => {
+    e.preventDefault();
+    const input = document.getElementById("token");
+    login(input.value.trim());
+    input.value = "";
+  });
+  document.getElementById("logout").addEventListener("click", logout);
+  if (sessionStorage.getItem(tokenKey)) {
+    login(sessionStorage.getItem(tokenKey));
+  }
+});
@@ -0,0 +1,253 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package management
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime"
+	"sort"
+
+	"github.com/gorilla/mux"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+// TokenHeader carries the management token. The Authorization header cannot be
+// used because the API server strips it when proxying to pods.
+const TokenHeader = "X-Kube-Bind-Management-Token"
+
+// ErrNotFound is returned by a Backend for unknown APIServiceBindings.
+var ErrNotFound = errors.New("APIServiceBinding not found")
+
+// Binding is the live state of an APIServiceBinding in a konnector replica.
+type Binding struct {
+	// Consumer is the consumer cluster as passed to --consumer-kubeconfig, or
+	// empty for the konnector's own cluster.
+	Consumer string `json:"consumer,omitempty"`
+	Name     string `json:"name"`
+	// KubeconfigSecret is the namespace/name of the service provider kubeconfig.
+	KubeconfigSecret string `json:"kubeconfigSecret"`
+	// Ready is the status of the Ready condition, or empty if it is not set.
+	Ready string `json:"ready,omitempty"`
+	// Owned is false if the binding belongs to another shard.
+	Owned bool `json:"owned"`
+	// Paused is true if syncing was paused through the management API or the
+	// kube-bind.io/paused annotation. Resume only undoes the former.
+	Paused bool `json:"paused"`
+	// Syncing is true if this replica runs a controller syncing the binding.
+	Syncing           bool         `json:"syncing"`
+	SyncingSince      *metav1.Time `json:"syncingSince,omitempty"`
+	ProviderNamespace string       `json:"providerNamespace,omitempty"`
+	// Problems are the conditions of the APIServiceBinding that are not True,
+	// most recent first.
+	Problems []conditionsapi.Condition `json:"problems,omitempty"`
+	// AcceptedClaims and AcceptedServiceAccountTokenClaims are the claims the
+	// consumer consented to in the APIServiceBinding.
+	AcceptedClaims                    []kubebindv1alpha1.ClusterScopedClaim       `json:"acceptedClaims,omitempty"`
+	AcceptedServiceAccountTokenClaims []kubebindv1alpha1.ServiceAccountTokenClaim `json:"acceptedServiceAccountTokenClaims,omitempty"`
+}
+
+// Diagnostics is a snapshot of a konnector replica for troubleshooting.
+type Diagnostics struct {
+	Identity       string      `json:"identity"`
+	Version        string      `json:"version"`
+	Time           metav1.Time `json:"time"`
+	Goroutines     int         `json:"goroutines"`
+	HeapAllocBytes uint64      `json:"heapAllocBytes"`
+	Bindings       []Binding   `json:"bindings"`
+}
+
+// Backend is implemented by the konnector controller of one consumer cluster.
+type Backend interface {
+	// Bindings returns the live state of all APIServiceBindings.
+	Bindings() ([]Binding, error)
+	// Resync restarts the controller syncing the named binding, relisting all
+	// objects from both clusters.
+	Resync(name string) error
+	// Pause stops syncing the named binding until Resume is called or the
+	// konnector restarts.
+	Pause(name string) error
+	// Resume undoes Pause.
+	Resume(name string) error
+}
+
+// Server serves the management API of a konnector replica.
+type Server struct {
+	identity string
+	version  string
+	token    string
+	// backends by consumer name
+	backends  map[string]Backend
+	dashboard bool
+}
+
+// NewServer returns a management Server authenticating requests with the given
+// token. If dashboard is true, it also serves the read-only consumer dashboard.
+func NewServer(identity, version, token string, backends map[string]Backend, dashboard bool) *Server {
+	return &Server{
+		identity:  identity,
+		version:   version,
+		token:     token,
+		backends:  backends,
+		dashboard: dashboard,
+	}
+}
+
+// Handler serves
+//
+//	GET  /v1/bindings
+//	POST /v1/bindings/{name}/resync?consumer=<consumer>
+//	POST /v1/bindings/{name}/pause?consumer=<consumer>
+//	POST /v1/bindings/{name}/resume?consumer=<consumer>
+//	GET  /v1/diagnostics
+//
+// and, if enabled, the static files of the dashboard under DashboardPath
+// without authentication.
+func (s *Server) Handler() http.Handler {
+	api := mux.NewRouter()
+	api.HandleFunc("/v1/bindings", s.handleBindings).Methods("GET")
+	api.HandleFunc("/v1/bindings/{name}/{action:resync|pause|resume}", s.handleAction).Methods("POST")
+	api.HandleFunc("/v1/diagnostics", s.handleDiagnostics).Methods("GET")
+	if !s.dashboard {
+		return s.authenticate(api)
+	}
+
+	router := mux.NewRouter()
+	addDashboardRoutes(router)
+	router.PathPrefix("/").Handler(s.authenticate(api))
+	return router
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(TokenHeader)), []byte(s.token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) bindings() ([]Binding, error) {
+	ret := []Binding{}
+	for consumer, backend := range s.backends {
+		bindings, err := backend.Bindings()
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range bindings {
+			b.Consumer = consumer
+			ret = append(ret, b)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Consumer != ret[j].Consumer {
+			return ret[i].Consumer < ret[j].Consumer
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret, nil
+}
+
+func (s *Server) handleBindings(w http.ResponseWriter, r *http.Request) {
+	bindings, err := s.bindings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, bindings)
+}
+
+func (s *Server) handleAction(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	backend, found := s.backends[r.URL.Query().Get("consumer")]
+	if !found {
+		http.Error(w, "unknown consumer", http.StatusNotFound)
+		return
+	}
+
+	var err error
+	switch vars["action"] {
+	case "resync":
+		err = backend.Resync(vars["name"])
+	case "pause":
+		err = backend.Pause(vars["name"])
+	case "resume":
+		err = backend.Resume(vars["name"])
+	}
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	klog.FromContext(r.Context()).Info("management action", "action", vars["action"], "binding", vars["name"], "consumer", r.URL.Query().Get("consumer"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	bindings, err := s.bindings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	writeJSON(w, &Diagnostics{
+		Identity:       s.identity,
+		Version:        s.version,
+		Time:           metav1.Now(),
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: mem.HeapAlloc,
+		Bindings:       bindings,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Serve serves the management API on the given address until ctx is done.
+func (s *Server) Serve(ctx context.Context, address string) {
+	logger := klog.FromContext(ctx)
+
+	server := &http.Server{Addr: address, Handler: s.Handler()}
+	go func() {
+		<-ctx.Done()
+		server.Close() // nolint:errcheck
+	}()
+	go func() {
+		logger.Info("serving management API", "address", address)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(err, "failed to serve management API")
+		}
+	}()
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package management
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+type fakeBackend struct {
+	bindings []Binding
+	actions  []string
+}
+
+func (b *fakeBackend) Bindings() ([]Binding, error) { return b.bindings, nil }
+func (b *fakeBackend) Resync(name string) error     { return b.action("resync", name) }
+func (b *fakeBackend) Pause(name string) error      { return b.action("pause", name) }
+func (b *fakeBackend) Resume(name string) error     { return b.action("resume", name) }
+
+func (b *fakeBackend) action(action, name string) error {
+	for _, binding := range b.bindings {
+		if binding.Name == name {
+			b.actions = append(b.actions, action+" "+name)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func TestServer(t *testing.T) {
+	local := &fakeBackend{bindings: []Binding{{Name: "foo", Syncing: true}, {Name: "bar"}}}
+	remote := &fakeBackend{bindings: []Binding{{Name: "foo"}}}
+	server := NewServer("konnector-0", "v0.1.0", "secret", map[string]Backend{"": local, "remote": remote}, true)
+
+	// mimic the pods/proxy subresource of the API server
+	ts := httptest.NewServer(http.StripPrefix("/api/v1/namespaces/kube-bind/pods/konnector-0:8090/proxy", server.Handler()))
+	defer ts.Close()
+	kubeClient, err := kubeclient.NewForConfig(&rest.Config{Host: ts.URL})
+	require.NoError(t, err)
+	newClient := func(token string) *Client {
+		return NewClient(kubeClient.CoreV1().RESTClient(), "kube-bind", "konnector-0", 8090, token)
+	}
+	ctx := context.Background()
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := newClient("wrong").Bindings(ctx)
+		require.Error(t, err)
+		_, err = newClient("").Diagnostics(ctx)
+		require.Error(t, err)
+		require.Error(t, newClient("wrong").Action(ctx, "pause", "", "foo"))
+		require.Empty(t, local.actions)
+	})
+
+	t.Run("bindings of all consumers", func(t *testing.T) {
+		bindings, err := newClient("secret").Bindings(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []Binding{
+			{Name: "bar"},
+			{Name: "foo", Syncing: true},
+			{Consumer: "remote", Name: "foo"},
+		}, bindings)
+	})
+
+	t.Run("actions", func(t *testing.T) {
+		client := newClient("secret")
+		require.NoError(t, client.Action(ctx, "pause", "", "foo"))
+		require.NoError(t, client.Action(ctx, "resume", "", "bar"))
+		require.NoError(t, client.Action(ctx, "resync", "remote", "foo"))
+		require.Error(t, client.Action(ctx, "pause", "", "unknown"))
+		require.Error(t, client.Action(ctx, "pause", "unknown", "foo"))
+		require.Error(t, client.Action(ctx, "delete", "", "foo"))
+		require.Equal(t, []string{"pause foo", "resume bar"}, local.actions)
+		require.Equal(t, []string{"resync foo"}, remote.actions)
+	})
+
+	t.Run("diagnostics", func(t *testing.T) {
+		diagnostics, err := newClient("secret").Diagnostics(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "konnector-0", diagnostics.Identity)
+		require.Equal(t, "v0.1.0", diagnostics.Version)
+		require.Len(t, diagnostics.Bindings, 3)
+		require.Positive(t, diagnostics.Goroutines)
+	})
+
+	t.Run("dashboard without token", func(t *testing.T) {
+		prefix := ts.URL + "/api/v1/namespaces/kube-bind/pods/konnector-0:8090/proxy"
+		resp, err := http.Get(prefix + DashboardPath)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+		require.Equal(t, "DENY", resp.Header.Get("X-Frame-Options"))
+
+		resp, err = http.Get(prefix + "/v1/diagnostics")
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
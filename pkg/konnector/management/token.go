@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package management
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// TokenSecretName is the Secret in the konnector namespace holding the token
+	// of the management API. Everybody allowed to read it and to proxy to the
+	// konnector pods can manage the konnector.
+	TokenSecretName = "kube-bind-konnector-management"
+	// TokenSecretKey is the key of the token in the Secret.
+	TokenSecretKey = "token"
+)
+
+// EnsureToken returns the management token shared by all konnector replicas,
+// creating the Secret with a random token if it does not exist yet.
+func EnsureToken(ctx context.Context, secrets corev1client.SecretsGetter, namespace string) (string, error) {
+	secret, err := secrets.Secrets(namespace).Get(ctx, TokenSecretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		bs := make([]byte, 32)
+		if _, err := rand.Read(bs); err != nil {
+			return "", err
+		}
+		secret, err = secrets.Secrets(namespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      TokenSecretName,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{TokenSecretKey: []byte(hex.EncodeToString(bs))},
+		}, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			// another replica was faster
+			secret, err = secrets.Secrets(namespace).Get(ctx, TokenSecretName, metav1.GetOptions{})
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	token := string(secret.Data[TokenSecretKey])
+	if token == "" {
+		return "", fmt.Errorf("secret %s/%s has no %q key", namespace, TokenSecretName, TokenSecretKey)
+	}
+	return token, nil
+}
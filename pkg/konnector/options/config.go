@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"github.com/spf13/pflag"
+
+	configv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/config/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/features"
+)
+
+// applyConfigFile sets the options from the KonnectorConfiguration in --config,
+// except those given as flags.
+func (options *Options) applyConfigFile(fs *pflag.FlagSet) error {
+	config, err := configv1alpha1.LoadKonnectorConfiguration(options.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	if err := features.SetFromConfigFile(fs, config.FeatureGates); err != nil {
+		return err
+	}
+
+	configv1alpha1.OverrideString(fs, "kubeconfig", &options.KubeConfigPath, config.Kubeconfig)
+	configv1alpha1.OverrideSlice(fs, "consumer-kubeconfig", &options.ConsumerKubeconfigs, config.ConsumerKubeconfigs)
+	configv1alpha1.Override(fs, "dry-run", &options.DryRun, config.DryRun)
+
+	if le := config.LeaderElection; le != nil {
+		configv1alpha1.Override(fs, "leader-elect", &options.LeaderElection.Enabled, le.LeaderElect)
+		configv1alpha1.Override(fs, "sharding", &options.Sharding, le.Sharding)
+		configv1alpha1.OverrideString(fs, "lease-name", &options.LeaseLockName, le.ResourceName)
+		configv1alpha1.OverrideString(fs, "lease-namespace", &options.LeaseLockNamespace, le.ResourceNamespace)
+		configv1alpha1.OverrideDuration(fs, "leader-elect-lease-duration", &options.LeaderElection.LeaseDuration, le.LeaseDuration)
+		configv1alpha1.OverrideDuration(fs, "leader-elect-renew-deadline", &options.LeaderElection.RenewDeadline, le.RenewDeadline)
+		configv1alpha1.OverrideDuration(fs, "leader-elect-retry-period", &options.LeaderElection.RetryPeriod, le.RetryPeriod)
+	}
+	if e := config.Endpoints; e != nil {
+		configv1alpha1.Override(fs, "metrics-bind-address", &options.MetricsBindAddress, e.MetricsBindAddress)
+		configv1alpha1.Override(fs, "health-probe-bind-address", &options.HealthProbeBindAddress, e.HealthProbeBindAddress)
+		configv1alpha1.Override(fs, "debug-address", &options.DebugAddress, e.DebugAddress)
+		configv1alpha1.Override(fs, "webhook-bind-address", &options.WebhookBindAddress, e.WebhookBindAddress)
+		configv1alpha1.Override(fs, "management-bind-address", &options.ManagementBindAddress, e.ManagementBindAddress)
+		configv1alpha1.Override(fs, "management-dashboard", &options.ManagementDashboard, e.ManagementDashboard)
+		configv1alpha1.OverrideString(fs, "webhook-cert-dir", &options.WebhookCertDir, e.WebhookCertDir)
+	}
+	if t := config.Tracing; t != nil {
+		configv1alpha1.OverrideString(fs, "tracing-endpoint", &options.TracingEndpoint, t.Endpoint)
+		configv1alpha1.Override(fs, "tracing-sampling-rate-per-million", &options.TracingSamplingRatePerMillion, t.SamplingRatePerMillion)
+	}
+	if c := config.CRDs; c != nil {
+		configv1alpha1.Override(fs, "install-crds", &options.InstallCRDs, c.Install)
+		configv1alpha1.OverrideString(fs, "crd-upgrade-policy", &options.CRDUpgradePolicy, c.UpgradePolicy)
+		configv1alpha1.OverrideString(fs, "crd-conflict-policy", &options.CRDConflictPolicy, c.ConflictPolicy)
+		configv1alpha1.Override(fs, "migrate-storage", &options.MigrateStorage, c.MigrateStorage)
+		configv1alpha1.OverrideSlice(fs, "crd-allowlist", &options.CRDAllowlist, c.Allowlist)
+		configv1alpha1.Override(fs, "synced-printer-columns", &options.SyncedPrinterColumns, c.SyncedPrinterColumns)
+	}
+	if c := config.ClientConnection; c != nil {
+		if c.Consumer != nil {
+			configv1alpha1.Override(fs, "kube-api-qps", &options.KubeAPI.QPS, c.Consumer.QPS)
+			configv1alpha1.Override(fs, "kube-api-burst", &options.KubeAPI.Burst, c.Consumer.Burst)
+		}
+		if c.Provider != nil {
+			configv1alpha1.Override(fs, "provider-api-qps", &options.ProviderAPI.QPS, c.Provider.QPS)
+			configv1alpha1.Override(fs, "provider-api-burst", &options.ProviderAPI.Burst, c.Provider.Burst)
+		}
+	}
+	if s := config.Sync; s != nil {
+		if s.Spec != nil {
+			configv1alpha1.Override(fs, "spec-sync-workers", &options.Sync.Spec.Workers, s.Spec.Workers)
+			configv1alpha1.Override(fs, "spec-sync-qps", &options.Sync.Spec.QPS, s.Spec.QPS)
+			configv1alpha1.Override(fs, "spec-sync-burst", &options.Sync.Spec.Burst, s.Spec.Burst)
+		}
+		if s.Status != nil {
+			configv1alpha1.Override(fs, "status-sync-workers", &options.Sync.Status.Workers, s.Status.Workers)
+			configv1alpha1.Override(fs, "status-sync-qps", &options.Sync.Status.QPS, s.Status.QPS)
+			configv1alpha1.Override(fs, "status-sync-burst", &options.Sync.Status.Burst, s.Status.Burst)
+		}
+		configv1alpha1.OverrideDuration(fs, "synced-condition-max-staleness", &options.SyncedConditionMaxStaleness, s.SyncedConditionMaxStaleness)
+		configv1alpha1.OverrideDuration(fs, "hibernate-idle-bindings-after", &options.HibernateIdleBindingsAfter, s.HibernateIdleBindingsAfter)
+		configv1alpha1.OverrideDuration(fs, "canary-interval", &options.CanaryInterval, s.CanaryInterval)
+		configv1alpha1.OverrideString(fs, "sync-snapshot-dir", &options.SyncSnapshotDir, s.SnapshotDir)
+		configv1alpha1.OverrideDuration(fs, "resync-period", &options.Resync.Period, s.ResyncPeriod)
+		configv1alpha1.OverrideDuration(fs, "provider-resync-period", &options.Resync.ProviderPeriod, s.ProviderResyncPeriod)
+		configv1alpha1.OverrideDuration(fs, "shutdown-grace-period", &options.ShutdownGracePeriod, s.ShutdownGracePeriod)
+	}
+	if w := config.Workers; w != nil {
+		configv1alpha1.Override(fs, "workers", &options.Workers.Default, w.Default)
+		configv1alpha1.Override(fs, "servicebinding-workers", &options.Workers.ServiceBinding, w.ServiceBinding)
+	}
+	if w := config.Workqueue; w != nil {
+		configv1alpha1.OverrideDuration(fs, "workqueue-base-delay", &options.RateLimiter.BaseDelay, w.BaseDelay)
+		configv1alpha1.OverrideDuration(fs, "workqueue-max-delay", &options.RateLimiter.MaxDelay, w.MaxDelay)
+		configv1alpha1.Override(fs, "workqueue-qps", &options.RateLimiter.QPS, w.QPS)
+		configv1alpha1.Override(fs, "workqueue-burst", &options.RateLimiter.Burst, w.Burst)
+	}
+	if p := config.Policy; p != nil {
+		configv1alpha1.OverrideString(fs, "upsync-policies", &options.UpsyncPoliciesFile, p.UpsyncPoliciesFile)
+		configv1alpha1.OverrideSlice(fs, "allowed-regions", &options.AllowedRegions, p.AllowedRegions)
+		configv1alpha1.OverrideSlice(fs, "watch-namespaces", &options.WatchNamespaces, p.WatchNamespaces)
+		configv1alpha1.Override(fs, "require-binding-approval", &options.RequireBindingApproval, p.RequireBindingApproval)
+		configv1alpha1.Override(fs, "rbac-cluster-role", &options.RBACClusterRole, p.RBACClusterRole)
+	}
+	if s := config.SelfUpgrade; s != nil {
+		configv1alpha1.Override(fs, "self-upgrade", &options.SelfUpgrade, s.Enabled)
+		configv1alpha1.OverrideString(fs, "self-upgrade-image", &options.SelfUpgradeImage, s.Image)
+		configv1alpha1.OverrideString(fs, "self-upgrade-deployment", &options.SelfUpgradeDeployment, s.Deployment)
+	}
+	if p := config.Provider; p != nil {
+		configv1alpha1.OverrideString(fs, "provider-proxy-url", &options.ProviderProxyURL, p.ProxyURL)
+		configv1alpha1.OverrideString(fs, "provider-ca-file", &options.ProviderCAFile, p.CAFile)
+		configv1alpha1.OverrideString(fs, "provider-ca-configmap", &options.ProviderCAConfigMap, p.CAConfigMap)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		args    []string
+		check   func(t *testing.T, o *CompletedOptions)
+		wantErr string
+	}{
+		{
+			name:   "defaults without fields",
+			config: "apiVersion: config.kube-bind.io/v1alpha1\nkind: KonnectorConfiguration\n",
+			check: func(t *testing.T, o *CompletedOptions) {
+				require.True(t, o.InstallCRDs)
+				require.Equal(t, 2, o.Workers.Default)
+				require.Equal(t, 15*time.Second, o.LeaderElection.LeaseDuration)
+				require.Empty(t, o.ManagementBindAddress, "management API must be opt-in")
+			},
+		},
+		{
+			name: "file values",
+			config: `apiVersion: config.kube-bind.io/v1alpha1
+kind: KonnectorConfiguration
+leaderElection:
+  leaderElect: false
+  leaseDuration: 30s
+crds:
+  install: false
+  allowlist: ["*.mangodb.com"]
+clientConnection:
+  provider:
+    qps: 20
+workers:
+  default: 4
+`,
+			check: func(t *testing.T, o *CompletedOptions) {
+				require.False(t, o.LeaderElection.Enabled)
+				require.Equal(t, 30*time.Second, o.LeaderElection.LeaseDuration)
+				require.False(t, o.InstallCRDs)
+				require.Equal(t, []string{"*.mangodb.com"}, o.CRDAllowlist)
+				require.Equal(t, float32(20), o.ProviderAPI.QPS)
+				require.Zero(t, o.ProviderAPI.Burst, "unset fields keep the client-go default")
+				require.Equal(t, 4, o.Workers.Default)
+			},
+		},
+		{
+			name: "flags take precedence",
+			config: `apiVersion: config.kube-bind.io/v1alpha1
+kind: KonnectorConfiguration
+crds:
+  install: true
+workers:
+  default: 4
+  serviceBinding: 3
+`,
+			args: []string{"--install-crds=false", "--workers=8"},
+			check: func(t *testing.T, o *CompletedOptions) {
+				require.False(t, o.InstallCRDs)
+				require.Equal(t, 8, o.Workers.Default)
+				require.Equal(t, 3, o.Workers.ServiceBinding)
+			},
+		},
+		{
+			name:    "unknown field",
+			config:  "apiVersion: config.kube-bind.io/v1alpha1\nkind: KonnectorConfiguration\nworkers:\n  defualt: 4\n",
+			wantErr: "unknown field",
+		},
+		{
+			name:    "wrong kind",
+			config:  "apiVersion: config.kube-bind.io/v1alpha1\nkind: BackendConfiguration\n",
+			wantErr: "must be of apiVersion",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.config), 0600))
+
+			o := NewOptions()
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			o.AddFlags(fs)
+			require.NoError(t, fs.Parse(append([]string{"--config=" + path}, tt.args...)))
+
+			completed, err := o.Complete()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, completed.Validate())
+			tt.check(t, completed)
+		})
+	}
+}
+
+func TestValidateManagement(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "disabled without lease namespace", args: []string{"--leader-elect=false", "--lease-namespace="}},
+		{name: "enabled", args: []string{"--management-bind-address=:8090", "--lease-namespace=kube-bind"}},
+		{name: "enabled without lease namespace", args: []string{"--management-bind-address=:8090", "--lease-namespace="}, wantErr: "--management-bind-address requires --lease-namespace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOptions()
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			o.AddFlags(fs)
+			require.NoError(t, fs.Parse(tt.args))
+
+			completed, err := o.Complete()
+			require.NoError(t, err)
+			err = completed.Validate()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
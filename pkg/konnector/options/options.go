@@ -19,26 +19,134 @@ package options
 import (
 	"fmt"
 	"math/rand"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/component-base/logs"
 	logsv1 "k8s.io/component-base/logs/api/v1"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+
+	"github.com/kube-bind/kube-bind/deploy/crd"
+	configv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/config/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/features"
+	"github.com/kube-bind/kube-bind/pkg/konnector/controllers/tuning"
+	"github.com/kube-bind/kube-bind/pkg/konnector/management"
+	"github.com/kube-bind/kube-bind/pkg/konnector/webhook"
+)
+
+const (
+	// CRDConflictPolicyFail makes the konnector fail at startup if its CRDs cannot be installed or updated.
+	CRDConflictPolicyFail = "Fail"
+	// CRDConflictPolicyWarn makes the konnector log a warning and continue with the existing CRDs.
+	CRDConflictPolicyWarn = "Warn"
+
+	// minResyncPeriod is the lowest resync period of the informers, to protect the API servers.
+	minResyncPeriod = 10 * time.Second
 )
 
 type Options struct {
 	Logs *logs.Options
 
 	ExtraOptions
+
+	// flags are the flags added with AddFlags, for the precedence over ConfigFile.
+	flags *pflag.FlagSet
 }
 
 type ExtraOptions struct {
-	KubeConfigPath string
+	// ConfigFile is a KonnectorConfiguration file. Flags override its values.
+	ConfigFile string
+
+	KubeConfigPath      string
+	ConsumerKubeconfigs []string
 
 	LeaseLockName      string
 	LeaseLockNamespace string
 	LeaseLockIdentity  string
+	// LeaderElection tunes the failover between konnector replicas.
+	LeaderElection LeaderElection
+	// Sharding makes all replicas active, each serving a subset of the APIServiceBindings.
+	Sharding bool
+
+	SyncedConditionMaxStaleness time.Duration
+	SyncedPrinterColumns        bool
+	HibernateIdleBindingsAfter  time.Duration
+	CanaryInterval              time.Duration
+	SyncSnapshotDir             string
+	ShutdownGracePeriod         time.Duration
+	// Sync tunes the spec (upsync) and status (downsync) controllers independently.
+	Sync tuning.Sync
+	// Resync is the resync period of the informers.
+	Resync tuning.Resync
+	// RateLimiter configures the retries of failed items of the controllers.
+	RateLimiter tuning.RateLimiter
+	// Workers tunes the concurrency of the other controllers.
+	Workers tuning.Workers
+	// KubeAPI and ProviderAPI rate limit the clients against the consumer and
+	// the service provider clusters.
+	KubeAPI     tuning.Client
+	ProviderAPI tuning.Client
+
+	MetricsBindAddress     string
+	HealthProbeBindAddress string
+	DebugAddress           string
+	WebhookBindAddress     string
+	WebhookCertDir         string
+	ManagementBindAddress  string
+	ManagementDashboard    bool
+
+	// TracingEndpoint is the OTLP gRPC endpoint the spans of the sync are exported
+	// to, sampling TracingSamplingRatePerMillion of the traces.
+	TracingEndpoint               string
+	TracingSamplingRatePerMillion int32
+
+	InstallCRDs       bool
+	CRDUpgradePolicy  string
+	CRDConflictPolicy string
+	CRDAllowlist      []string
+	MigrateStorage    bool
+
+	RBACClusterRole string
+
+	UpsyncPoliciesFile string
+	AllowedRegions     []string
+	WatchNamespaces    []string
+
+	RequireBindingApproval bool
+
+	ProviderProxyURL    string
+	ProviderCAFile      string
+	ProviderCAConfigMap string
+
+	// SelfUpgrade updates the image of the konnector Deployment to the version
+	// required by the service providers.
+	SelfUpgrade           bool
+	SelfUpgradeImage      string
+	SelfUpgradeDeployment string
+
+	// DryRun sends all writes of the controllers as server-side dry-run, and logs them.
+	DryRun bool
+}
+
+// LeaderElection are the timings of the leader election between konnector replicas.
+type LeaderElection struct {
+	// Enabled makes replicas acquire a Lease before starting the controllers. It
+	// can be disabled for single-replica installations.
+	Enabled bool
+	// LeaseDuration is how long standby replicas wait before taking over a lease
+	// that has not been renewed.
+	LeaseDuration time.Duration
+	// RenewDeadline is how long the leader retries renewing the lease before it
+	// gives up leadership.
+	RenewDeadline time.Duration
+	// RetryPeriod is the interval between attempts to acquire or renew the lease.
+	RetryPeriod time.Duration
 }
 
 type completedOptions struct {
@@ -63,6 +171,48 @@ func NewOptions() *Options {
 			LeaseLockName:      "kube-bind",
 			LeaseLockNamespace: os.Getenv("POD_NAMESPACE"),
 			LeaseLockIdentity:  os.Getenv("POD_NAME"),
+			LeaderElection: LeaderElection{
+				Enabled:       true,
+				LeaseDuration: 15 * time.Second,
+				RenewDeadline: 10 * time.Second,
+				RetryPeriod:   2 * time.Second,
+			},
+
+			InstallCRDs:       true,
+			CRDUpgradePolicy:  string(crd.UpgradePolicyUpdate),
+			CRDConflictPolicy: CRDConflictPolicyFail,
+			MigrateStorage:    true,
+
+			ShutdownGracePeriod: 20 * time.Second,
+
+			RBACClusterRole: "kube-bind-konnector",
+
+			HealthProbeBindAddress: ":8081",
+
+			TracingSamplingRatePerMillion: 10000,
+
+			Sync: tuning.Sync{
+				Spec:   tuning.Controller{Workers: 1},
+				Status: tuning.Controller{Workers: 1},
+			},
+			Resync: tuning.Resync{
+				Period:         30 * time.Minute,
+				ProviderPeriod: 30 * time.Minute,
+			},
+			// the defaults of workqueue.DefaultControllerRateLimiter
+			RateLimiter: tuning.RateLimiter{
+				BaseDelay: 5 * time.Millisecond,
+				MaxDelay:  1000 * time.Second,
+				QPS:       10,
+				Burst:     100,
+			},
+			Workers: tuning.Workers{
+				Default:        2,
+				ServiceBinding: 2,
+			},
+
+			SelfUpgradeImage:      "ghcr.io/kube-bind/konnector",
+			SelfUpgradeDeployment: "konnector",
 		},
 	}
 
@@ -75,13 +225,81 @@ func NewOptions() *Options {
 
 func (options *Options) AddFlags(fs *pflag.FlagSet) {
 	logsv1.AddFlags(options.Logs, fs)
+	features.DefaultMutableFeatureGate.AddFlag(fs)
+	options.flags = fs
 
+	fs.StringVar(&options.ConfigFile, "config", options.ConfigFile, "A KonnectorConfiguration file of apiVersion "+configv1alpha1.SchemeGroupVersion.String()+" with the options of the konnector. Flags given on the command line override the values of the file.")
 	fs.StringVar(&options.KubeConfigPath, "kubeconfig", options.KubeConfigPath, "Kubeconfig file for the local cluster.")
+	fs.StringSliceVar(&options.ConsumerKubeconfigs, "consumer-kubeconfig", options.ConsumerKubeconfigs, "Kubeconfig files of additional consumer clusters served by this konnector, each as <path> or <path>#<context>. Bindings and informers are kept separately per consumer cluster.")
 	fs.StringVar(&options.LeaseLockName, "lease-name", options.LeaseLockName, "Name of lease lock")
 	fs.StringVar(&options.LeaseLockNamespace, "lease-namespace", options.LeaseLockNamespace, "Name of lease lock namespace")
+	fs.BoolVar(&options.LeaderElection.Enabled, "leader-elect", options.LeaderElection.Enabled, "Acquire a Lease before starting the controllers, such that only one of multiple konnector replicas is active. Disable for single-replica or development installations, which then need no Lease RBAC and lock namespace. Never run multiple replicas without it.")
+	fs.BoolVar(&options.Sharding, "sharding", options.Sharding, "Run all konnector replicas active-active instead of leader election. Every replica keeps a Lease <lease-name>-<pod name> in the lease namespace alive and serves the APIServiceBindings whose kubeconfig secret hashes to it. The lease duration and retry period of leader election apply to these Leases.")
+	fs.DurationVar(&options.LeaderElection.LeaseDuration, "leader-elect-lease-duration", options.LeaderElection.LeaseDuration, "The duration standby konnectors wait before taking over a lease that has not been renewed. Higher values tolerate longer API server disruptions, lower values fail over faster.")
+	fs.DurationVar(&options.LeaderElection.RenewDeadline, "leader-elect-renew-deadline", options.LeaderElection.RenewDeadline, "The duration the leading konnector retries renewing its lease before giving up leadership. Must be less than the lease duration.")
+	fs.DurationVar(&options.LeaderElection.RetryPeriod, "leader-elect-retry-period", options.LeaderElection.RetryPeriod, "The interval between attempts of konnectors to acquire or renew the lease.")
+	fs.StringVar(&options.MetricsBindAddress, "metrics-bind-address", options.MetricsBindAddress, "The address to serve Prometheus metrics on, e.g. :8080. Empty disables the metrics endpoint.")
+	fs.StringVar(&options.HealthProbeBindAddress, "health-probe-bind-address", options.HealthProbeBindAddress, "The address to serve the /healthz liveness and /readyz readiness probes on. Empty disables the probes.")
+	fs.StringVar(&options.DebugAddress, "debug-address", options.DebugAddress, "The address to serve net/http/pprof profiles on under /debug/pprof, e.g. localhost:6060. Empty disables profiling. Do not expose it publicly.")
+	fs.StringVar(&options.WebhookBindAddress, "webhook-bind-address", options.WebhookBindAddress, "The address to serve the validating admission webhook on, e.g. :9443, that rejects bound objects exceeding the plan limits of the service provider on creation. It must be registered with a ValidatingWebhookConfiguration for path "+webhook.LimitsPath+". Empty disables the webhook.")
+	fs.StringVar(&options.ManagementBindAddress, "management-bind-address", options.ManagementBindAddress, "The address to serve the management API used by kubectl bind konnector on, e.g. :8090. It is reached through the pods/proxy subresource of the API server and authenticated with the token in Secret "+management.TokenSecretName+" in the --lease-namespace. Empty disables the management API.")
+	fs.BoolVar(&options.ManagementDashboard, "management-dashboard", options.ManagementDashboard, "Serve a read-only dashboard of the APIServiceBindings, their sync health, accepted claims and recent errors under "+management.DashboardPath+" of the management API, e.g. through kubectl port-forward. It asks for the management token.")
+	fs.StringVar(&options.TracingEndpoint, "tracing-endpoint", options.TracingEndpoint, "The OTLP gRPC endpoint, e.g. otel-collector.monitoring:4317, to export OpenTelemetry traces of the spec and status sync to. Every synced object gets a span of its APIServiceBinding, a child span of the sync, and client spans of the API calls to the service provider and consumer clusters below. Empty disables tracing.")
+	fs.Int32Var(&options.TracingSamplingRatePerMillion, "tracing-sampling-rate-per-million", options.TracingSamplingRatePerMillion, "The number of syncs per million that are traced if --tracing-endpoint is set.")
+	fs.StringVar(&options.WebhookCertDir, "webhook-cert-dir", options.WebhookCertDir, "The directory with tls.crt and tls.key of the admission webhook serving certificate.")
+	fs.BoolVar(&options.InstallCRDs, "install-crds", options.InstallCRDs, "Install and upgrade the kube-bind CRDs of the konnector at startup. Set to false if the CRDs are managed otherwise, e.g. via GitOps or Helm. The konnector then waits for them to be established.")
+	fs.StringVar(&options.CRDUpgradePolicy, "crd-upgrade-policy", options.CRDUpgradePolicy, "What to do at startup with existing kube-bind CRDs whose schemas differ from the ones of this konnector: Update overwrites them, Create leaves them alone, and Fail stops the konnector. The outcome is recorded as event on the CRD.")
+	fs.StringVar(&options.CRDConflictPolicy, "crd-conflict-policy", options.CRDConflictPolicy, "What to do if pre-existing kube-bind CRDs are incompatible and cannot be updated at startup: Fail or Warn.")
+	fs.BoolVar(&options.MigrateStorage, "migrate-storage", options.MigrateStorage, "Rewrite the objects of the kube-bind CRDs of the konnector at startup if the CRDs list old stored versions or have pending migrations, such that upgrades need no manual steps. Completed migrations are recorded in the kube-bind.io/migrations annotation of the CRDs.")
+	fs.StringSliceVar(&options.CRDAllowlist, "crd-allowlist", options.CRDAllowlist, "CRDs of bound API services the konnector may create and update, by name or as *.<group>. Empty allows all.")
+	fs.StringVar(&options.RBACClusterRole, "rbac-cluster-role", options.RBACClusterRole, "The ClusterRole granted to the konnector, reported on if it grants more than needed for the current bindings. Empty disables the report.")
+	fs.StringVar(&options.UpsyncPoliciesFile, "upsync-policies", options.UpsyncPoliciesFile, "YAML file with CEL policies that consumer objects must satisfy before they are sent to a service provider. Violations are reported as condition on the objects.")
+	fs.StringSliceVar(&options.AllowedRegions, "allowed-regions", options.AllowedRegions, "Regions of service providers, as advertised in the kube-bind.io/region label of APIServiceExports, that bound resources may be synced to. Empty allows all, otherwise exports without region are refused.")
+	fs.StringSliceVar(&options.WatchNamespaces, "watch-namespaces", options.WatchNamespaces, "Namespaces to which the konnector restricts list/watch of consumer objects of namespaced bound resources. Objects in other namespaces are not synced. Cluster-scoped resources are not affected. Empty means all namespaces.")
+	fs.StringVar(&options.ProviderProxyURL, "provider-proxy-url", options.ProviderProxyURL, "An http, https or socks5 proxy URL, e.g. socks5://proxy.internal:1080, to connect to service provider clusters through if their kubeconfig does not set a proxy-url. If unset, HTTPS_PROXY and NO_PROXY are honored.")
+	fs.StringVar(&options.ProviderCAFile, "provider-ca-file", options.ProviderCAFile, "A PEM CA bundle file trusted for all service provider clusters in addition to the CA in their kubeconfig. Kubeconfigs without CA trust only this bundle instead of the system roots.")
+	fs.StringVar(&options.ProviderCAConfigMap, "provider-ca-configmap", options.ProviderCAConfigMap, "A <namespace>/<name> of a ConfigMap with a PEM CA bundle in the \"ca.crt\" key, trusted like --provider-ca-file. The ConfigMap is watched, and syncs are restarted when it changes.")
+	fs.BoolVar(&options.RequireBindingApproval, "require-binding-approval", options.RequireBindingApproval, "Only sync APIServiceBindings with a kube-bind.io/approved-by annotation. Combine with an admission policy restricting who may set it.")
+	fs.DurationVar(&options.SyncedConditionMaxStaleness, "synced-condition-max-staleness", options.SyncedConditionMaxStaleness, "If non-zero, maintain a kube-bind.io/Synced condition on bound objects that turns to StatusStale if the service provider does not acknowledge a spec change within this duration.")
+	fs.BoolVar(&options.SyncedPrinterColumns, "synced-printer-columns", options.SyncedPrinterColumns, "Add Synced and Provider printer columns to the CRDs of bound resources, showing the kube-bind.io/Synced condition and the APIServiceBinding of objects in kubectl get. Requires --synced-condition-max-staleness.")
+	fs.DurationVar(&options.CanaryInterval, "canary-interval", options.CanaryInterval, "If non-zero, periodically create, update and delete a probe object through the full sync path of every binding whose APIServiceExport provides a canary object, and report the round-trip latency as metrics and the CanaryHealthy condition. The konnector needs create and delete permissions on bound resources in the kube-bind namespace. Must be at least 10s.")
+	fs.DurationVar(&options.HibernateIdleBindingsAfter, "hibernate-idle-bindings-after", options.HibernateIdleBindingsAfter, "If non-zero, stop the informers of bindings without any objects and events for this duration. They are woken up by a cheap watch on the first object. Must be at least 1m.")
+	fs.DurationVar(&options.ShutdownGracePeriod, "shutdown-grace-period", options.ShutdownGracePeriod, "How long to wait for in-flight syncs to finish on termination before aborting them and releasing the leader lease. Should be lower than the pod's terminationGracePeriodSeconds.")
+	fs.DurationVar(&options.Resync.Period, "resync-period", options.Resync.Period, "The resync period of the informers of the konnector, except for the dynamic informers of bound resources in service provider clusters. Lower values detect drift sooner at the cost of more load on the konnector.")
+	fs.DurationVar(&options.Resync.ProviderPeriod, "provider-resync-period", options.Resync.ProviderPeriod, "The resync period of the dynamic informers of bound resources in service provider clusters. APIServiceBindings can override it with spec.sync.resyncPeriod.")
+	fs.DurationVar(&options.RateLimiter.BaseDelay, "workqueue-base-delay", options.RateLimiter.BaseDelay, "The delay of the first retry of a failed item of the controllers. It doubles with every further failure of the item up to --workqueue-max-delay.")
+	fs.DurationVar(&options.RateLimiter.MaxDelay, "workqueue-max-delay", options.RateLimiter.MaxDelay, "The maximum delay between retries of a failed item of the controllers. Lower values let items recover sooner after long service provider outages.")
+	fs.Float64Var(&options.RateLimiter.QPS, "workqueue-qps", options.RateLimiter.QPS, "The maximum rate of retries per second of the workqueue of each controller, across all items.")
+	fs.IntVar(&options.RateLimiter.Burst, "workqueue-burst", options.RateLimiter.Burst, "The maximum burst of retries of the workqueue of each controller, across all items.")
+	fs.IntVar(&options.Workers.Default, "workers", options.Workers.Default, "Number of concurrent workers of the konnector controller and of the controllers of each service provider cluster.")
+	fs.IntVar(&options.Workers.ServiceBinding, "servicebinding-workers", options.Workers.ServiceBinding, "Number of concurrent workers of the APIServiceBinding controllers.")
+	fs.IntVar(&options.Sync.Spec.Workers, "spec-sync-workers", options.Sync.Spec.Workers, "Number of concurrent workers syncing the spec of consumer objects to the service provider, per binding.")
+	fs.Float32Var(&options.Sync.Spec.QPS, "spec-sync-qps", options.Sync.Spec.QPS, "Maximum requests per second of the spec sync of each binding. Zero uses the client default.")
+	fs.IntVar(&options.Sync.Spec.Burst, "spec-sync-burst", options.Sync.Spec.Burst, "Maximum request burst of the spec sync of each binding. Zero uses the client default.")
+	fs.IntVar(&options.Sync.Status.Workers, "status-sync-workers", options.Sync.Status.Workers, "Number of concurrent workers syncing the status of service provider objects to the consumer, per binding.")
+	fs.Float32Var(&options.Sync.Status.QPS, "status-sync-qps", options.Sync.Status.QPS, "Maximum requests per second of the status sync of each binding. Zero uses the client default.")
+	fs.IntVar(&options.Sync.Status.Burst, "status-sync-burst", options.Sync.Status.Burst, "Maximum request burst of the status sync of each binding. Zero uses the client default.")
+	fs.Float32Var(&options.KubeAPI.QPS, "kube-api-qps", options.KubeAPI.QPS, "Maximum requests per second against the consumer cluster. Zero uses the client-go default of 5.")
+	fs.IntVar(&options.KubeAPI.Burst, "kube-api-burst", options.KubeAPI.Burst, "Maximum request burst against the consumer cluster. Zero uses the client-go default of 10.")
+	fs.Float32Var(&options.ProviderAPI.QPS, "provider-api-qps", options.ProviderAPI.QPS, "Maximum requests per second against each service provider cluster. The spec and status sync of each binding are limited separately by --spec-sync-qps and --status-sync-qps. Zero uses the client-go default of 5.")
+	fs.IntVar(&options.ProviderAPI.Burst, "provider-api-burst", options.ProviderAPI.Burst, "Maximum request burst against each service provider cluster. Zero uses the client-go default of 10.")
+	fs.BoolVar(&options.SelfUpgrade, "self-upgrade", options.SelfUpgrade, "Update the image of the konnector Deployment in the --lease-namespace to <self-upgrade-image>:<version> if the konnector is older than the minimum or recommended konnector version of the service provider of any binding. The highest of these versions is chosen, and the konnector is never downgraded. Without it, outdated konnectors are only reported in the VersionSkew condition of the ClusterBindings.")
+	fs.StringVar(&options.SelfUpgradeImage, "self-upgrade-image", options.SelfUpgradeImage, "The konnector image repository used by --self-upgrade, e.g. of a registry mirror.")
+	fs.StringVar(&options.SelfUpgradeDeployment, "self-upgrade-deployment", options.SelfUpgradeDeployment, "The name of the konnector Deployment updated by --self-upgrade. Its container must be named \"konnector\".")
+	fs.BoolVar(&options.DryRun, "dry-run", options.DryRun, "Run all controllers, but send their creates, updates, patches and deletes in the consumer and service provider clusters as server-side dry-run and log them instead of performing them, e.g. to see what the sync would do when adopting kube-bind on an existing cluster. The kube-bind CRDs of the konnector are still installed, and the leader election Lease is still acquired. Heartbeats do not reach the service provider.")
+	fs.StringVar(&options.SyncSnapshotDir, "sync-snapshot-dir", options.SyncSnapshotDir, "If set, persist which bound objects are in sync to this directory, e.g. on a persistent volume, such that a restarted konnector skips unchanged objects instead of reconciling all of them again.")
 }
 
 func (options *Options) Complete() (*CompletedOptions, error) {
+	if options.ConfigFile != "" {
+		fs := options.flags
+		if fs == nil {
+			fs = pflag.NewFlagSet("konnector", pflag.ContinueOnError)
+		}
+		if err := options.applyConfigFile(fs); err != nil {
+			return nil, err
+		}
+	}
 	if options.LeaseLockIdentity == "" {
 		options.LeaseLockIdentity = fmt.Sprintf("%d", rand.Int31())
 	}
@@ -95,5 +313,133 @@ func (options *Options) Complete() (*CompletedOptions, error) {
 }
 
 func (options *CompletedOptions) Validate() error {
+	switch crd.UpgradePolicy(options.CRDUpgradePolicy) {
+	case crd.UpgradePolicyCreate, crd.UpgradePolicyUpdate, crd.UpgradePolicyFail:
+	default:
+		return fmt.Errorf("--crd-upgrade-policy must be %q, %q or %q", crd.UpgradePolicyCreate, crd.UpgradePolicyUpdate, crd.UpgradePolicyFail)
+	}
+	if options.CRDConflictPolicy != CRDConflictPolicyFail && options.CRDConflictPolicy != CRDConflictPolicyWarn {
+		return fmt.Errorf("--crd-conflict-policy must be %q or %q", CRDConflictPolicyFail, CRDConflictPolicyWarn)
+	}
+	for _, name := range options.CRDAllowlist {
+		if name == "" || strings.Contains(strings.TrimPrefix(name, "*."), "*") {
+			return fmt.Errorf("invalid --crd-allowlist entry %q, expected a CRD name or *.<group>", name)
+		}
+	}
+	for _, target := range options.ConsumerKubeconfigs {
+		if path, _ := SplitConsumerKubeconfig(target); path == "" {
+			return fmt.Errorf("invalid --consumer-kubeconfig %q, expected <path> or <path>#<context>", target)
+		}
+	}
+	if le := options.LeaderElection; options.Sharding {
+		if le.LeaseDuration <= 0 || le.RetryPeriod <= 0 || le.LeaseDuration <= le.RetryPeriod {
+			return fmt.Errorf("--sharding requires a positive --leader-elect-retry-period less than --leader-elect-lease-duration")
+		}
+	} else if !le.Enabled {
+		// timings are unused
+	} else if le.LeaseDuration <= 0 || le.RenewDeadline <= 0 || le.RetryPeriod <= 0 {
+		return fmt.Errorf("--leader-elect-lease-duration, --leader-elect-renew-deadline and --leader-elect-retry-period must be positive")
+	} else if le.LeaseDuration <= le.RenewDeadline {
+		return fmt.Errorf("--leader-elect-lease-duration must be greater than --leader-elect-renew-deadline")
+	} else if float64(le.RenewDeadline) <= leaderelection.JitterFactor*float64(le.RetryPeriod) {
+		return fmt.Errorf("--leader-elect-renew-deadline must be greater than %.1f times --leader-elect-retry-period", leaderelection.JitterFactor)
+	}
+	if options.SelfUpgrade && (options.SelfUpgradeImage == "" || options.SelfUpgradeDeployment == "") {
+		return fmt.Errorf("--self-upgrade requires --self-upgrade-image and --self-upgrade-deployment")
+	}
+	if options.ManagementBindAddress != "" && options.LeaseLockNamespace == "" {
+		return fmt.Errorf("--management-bind-address requires --lease-namespace for the token Secret")
+	}
+	if options.WebhookBindAddress != "" && options.WebhookCertDir == "" {
+		return fmt.Errorf("--webhook-bind-address requires --webhook-cert-dir")
+	}
+	if options.SyncedConditionMaxStaleness < 0 {
+		return fmt.Errorf("--synced-condition-max-staleness must not be negative")
+	}
+	if options.SyncedPrinterColumns && options.SyncedConditionMaxStaleness == 0 {
+		return fmt.Errorf("--synced-printer-columns requires --synced-condition-max-staleness")
+	}
+	if options.ShutdownGracePeriod < 0 {
+		return fmt.Errorf("--shutdown-grace-period must not be negative")
+	}
+	if options.Resync.Period < minResyncPeriod || options.Resync.ProviderPeriod < minResyncPeriod {
+		return fmt.Errorf("--resync-period and --provider-resync-period must be at least %s", minResyncPeriod)
+	}
+	if rl := options.RateLimiter; rl.BaseDelay <= 0 || rl.MaxDelay < rl.BaseDelay {
+		return fmt.Errorf("--workqueue-base-delay must be positive and at most --workqueue-max-delay")
+	} else if rl.QPS <= 0 || rl.Burst < 1 {
+		return fmt.Errorf("--workqueue-qps and --workqueue-burst must be positive")
+	}
+	if options.Workers.Default < 1 {
+		return fmt.Errorf("--workers must be at least 1")
+	}
+	if options.Workers.ServiceBinding < 1 {
+		return fmt.Errorf("--servicebinding-workers must be at least 1")
+	}
+	for name, c := range map[string]tuning.Controller{"spec": options.Sync.Spec, "status": options.Sync.Status} {
+		if c.Workers < 1 {
+			return fmt.Errorf("--%s-sync-workers must be at least 1", name)
+		}
+		if c.QPS < 0 || c.Burst < 0 {
+			return fmt.Errorf("--%s-sync-qps and --%s-sync-burst must not be negative", name, name)
+		}
+	}
+	if options.KubeAPI.QPS < 0 || options.KubeAPI.Burst < 0 {
+		return fmt.Errorf("--kube-api-qps and --kube-api-burst must not be negative")
+	}
+	if options.ProviderAPI.QPS < 0 || options.ProviderAPI.Burst < 0 {
+		return fmt.Errorf("--provider-api-qps and --provider-api-burst must not be negative")
+	}
+	if options.TracingEndpoint != "" {
+		if errs := tracingapi.ValidateTracingConfiguration(&tracingapi.TracingConfiguration{
+			Endpoint:               &options.TracingEndpoint,
+			SamplingRatePerMillion: &options.TracingSamplingRatePerMillion,
+		}, nil, field.NewPath("tracing")); len(errs) > 0 {
+			return fmt.Errorf("invalid --tracing-endpoint or --tracing-sampling-rate-per-million: %w", errs.ToAggregate())
+		}
+	}
+	if options.CanaryInterval != 0 && options.CanaryInterval < 10*time.Second {
+		return fmt.Errorf("--canary-interval must be zero or at least 10s")
+	}
+	if options.HibernateIdleBindingsAfter != 0 && options.HibernateIdleBindingsAfter < time.Minute {
+		return fmt.Errorf("--hibernate-idle-bindings-after must be zero or at least 1m")
+	}
+	if options.ProviderCAConfigMap != "" {
+		if _, _, err := options.ProviderCAConfigMapNamespaceName(); err != nil {
+			return err
+		}
+	}
+	if options.ProviderProxyURL != "" {
+		u, err := url.Parse(options.ProviderProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid --provider-proxy-url: %w", err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("--provider-proxy-url must have the scheme http, https or socks5, got %q", u.Scheme)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("--provider-proxy-url must have a host")
+		}
+	}
 	return nil
 }
+
+// ProviderCAConfigMapNamespaceName splits --provider-ca-configmap into namespace and name.
+func (options *ExtraOptions) ProviderCAConfigMapNamespaceName() (string, string, error) {
+	parts := strings.SplitN(options.ProviderCAConfigMap, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("--provider-ca-configmap must be <namespace>/<name>, got %q", options.ProviderCAConfigMap)
+	}
+	return parts[0], parts[1], nil
+}
+
+// SplitConsumerKubeconfig splits a --consumer-kubeconfig value into the kubeconfig
+// path and the optional context.
+func SplitConsumerKubeconfig(s string) (path, context string) {
+	if i := strings.LastIndex(s, "#"); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
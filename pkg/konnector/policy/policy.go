@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates CEL policies registered by the platform team before
+// the konnector sends any object to a service provider.
+package policy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// costLimit bounds the evaluation cost of one policy, like for CRD validation rules.
+const costLimit = 1000000
+
+// Config is the content of the --upsync-policies file.
+type Config struct {
+	Policies []Policy `json:"policies"`
+}
+
+// Policy is a CEL expression that must evaluate to true for an object to be sent to
+// the service provider. The expression can access the consumer object as "object"
+// and the metadata of its namespace as "namespaceObject", which is null for
+// cluster-scoped objects.
+//
+// Example: "!has(namespaceObject.metadata.labels) ||
+// !('data-classification' in namespaceObject.metadata.labels) ||
+// namespaceObject.metadata.labels['data-classification'] != 'restricted'"
+type Policy struct {
+	Name string `json:"name"`
+	// Resources restricts the policy to these resources, as <resource>.<group>. Empty means all.
+	Resources []string `json:"resources,omitempty"`
+	// Expression is the CEL expression evaluated to a bool.
+	Expression string `json:"expression"`
+	// Message is reported on violating objects. It defaults to the expression.
+	Message string `json:"message,omitempty"`
+}
+
+// Violation is a policy denying an object.
+type Violation struct {
+	Policy  string
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("denied by upsync policy %s: %s", v.Policy, v.Message)
+}
+
+type compiled struct {
+	Policy
+	program cel.Program
+}
+
+// Evaluator evaluates the policies against consumer objects. A nil Evaluator allows everything.
+type Evaluator struct {
+	policies     []compiled
+	getNamespace func(name string) (*metav1.PartialObjectMetadata, error)
+}
+
+// Load reads and compiles the policies of the given file.
+func Load(path string) ([]Policy, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := yaml.UnmarshalStrict(bs, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if _, err := compile(config.Policies); err != nil {
+		return nil, fmt.Errorf("invalid policies in %s: %w", path, err)
+	}
+	return config.Policies, nil
+}
+
+// NewEvaluator returns an Evaluator of the given policies, or nil if there are none.
+// getNamespace returns the metadata of consumer namespaces, usually from an informer.
+func NewEvaluator(policies []Policy, getNamespace func(name string) (*metav1.PartialObjectMetadata, error)) (*Evaluator, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	ps, err := compile(policies)
+	if err != nil {
+		return nil, err
+	}
+	return &Evaluator{policies: ps, getNamespace: getNamespace}, nil
+}
+
+func compile(policies []Policy) ([]compiled, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("namespaceObject", cel.DynType),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	ret := make([]compiled, 0, len(policies))
+	for _, p := range policies {
+		if p.Name == "" {
+			return nil, fmt.Errorf("policy name must not be empty")
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("duplicate policy %q", p.Name)
+		}
+		names[p.Name] = true
+		for _, r := range p.Resources {
+			if r == "" || strings.Contains(r, "/") {
+				return nil, fmt.Errorf("policy %q: invalid resource %q, expected <resource>.<group>", p.Name, r)
+			}
+		}
+
+		ast, issues := env.Compile(p.Expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("policy %q: %w", p.Name, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return nil, fmt.Errorf("policy %q: expression must evaluate to bool, not %s", p.Name, ast.OutputType())
+		}
+		program, err := env.Program(ast, cel.CostLimit(costLimit))
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", p.Name, err)
+		}
+		ret = append(ret, compiled{Policy: p, program: program})
+	}
+	return ret, nil
+}
+
+// Evaluate returns the first violated policy for the object of the given resource,
+// or nil if all policies allow it. Evaluation errors are violations, i.e. policies
+// fail closed.
+func (e *Evaluator) Evaluate(gr schema.GroupResource, obj *unstructured.Unstructured) (*Violation, error) {
+	if e == nil {
+		return nil, nil
+	}
+
+	var namespaceObject interface{}
+	if ns := obj.GetNamespace(); ns != "" {
+		namespace, err := e.getNamespace(ns)
+		if err != nil {
+			return nil, err
+		}
+		if namespaceObject, err = runtime.DefaultUnstructuredConverter.ToUnstructured(namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range e.policies {
+		if !p.applies(gr) {
+			continue
+		}
+		val, _, err := p.program.Eval(map[string]interface{}{
+			"object":          obj.Object,
+			"namespaceObject": namespaceObject,
+		})
+		if err != nil {
+			return &Violation{Policy: p.Name, Message: fmt.Sprintf("evaluation failed: %v", err)}, nil
+		}
+		if allowed, ok := val.Value().(bool); !ok {
+			return &Violation{Policy: p.Name, Message: fmt.Sprintf("evaluated to %v instead of bool", val.Value())}, nil
+		} else if !allowed {
+			message := p.Message
+			if message == "" {
+				message = fmt.Sprintf("failed expression: %s", p.Expression)
+			}
+			return &Violation{Policy: p.Name, Message: message}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (p *compiled) applies(gr schema.GroupResource) bool {
+	if len(p.Resources) == 0 {
+		return true
+	}
+	for _, r := range p.Resources {
+		if r == gr.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestEvaluate(t *testing.T) {
+	namespaces := map[string]*metav1.PartialObjectMetadata{
+		"public":     {ObjectMeta: metav1.ObjectMeta{Name: "public"}},
+		"restricted": {ObjectMeta: metav1.ObjectMeta{Name: "restricted", Labels: map[string]string{"data-classification": "restricted"}}},
+	}
+	getNamespace := func(name string) (*metav1.PartialObjectMetadata, error) {
+		return namespaces[name], nil
+	}
+	restricted := Policy{
+		Name:       "no-restricted-namespaces",
+		Expression: "!has(namespaceObject.metadata.labels) || !('data-classification' in namespaceObject.metadata.labels) || namespaceObject.metadata.labels['data-classification'] != 'restricted'",
+		Message:    "objects from restricted namespaces must not leave the cluster",
+	}
+	mangodbs := schema.GroupResource{Group: "mangodb.com", Resource: "mangodbs"}
+	object := func(ns string, spec map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		obj.SetNamespace(ns)
+		obj.SetName("test")
+		return obj
+	}
+
+	tests := []struct {
+		name     string
+		policies []Policy
+		gr       schema.GroupResource
+		obj      *unstructured.Unstructured
+		want     *Violation
+	}{
+		{
+			name: "no policies",
+			gr:   mangodbs,
+			obj:  object("restricted", nil),
+		},
+		{
+			name:     "allowed namespace",
+			policies: []Policy{restricted},
+			gr:       mangodbs,
+			obj:      object("public", nil),
+		},
+		{
+			name:     "restricted namespace",
+			policies: []Policy{restricted},
+			gr:       mangodbs,
+			obj:      object("restricted", nil),
+			want:     &Violation{Policy: "no-restricted-namespaces", Message: "objects from restricted namespaces must not leave the cluster"},
+		},
+		{
+			name:     "other resource",
+			policies: []Policy{{Name: "p", Resources: []string{"foos.example.com"}, Expression: "false"}},
+			gr:       mangodbs,
+			obj:      object("public", nil),
+		},
+		{
+			name:     "object field without message",
+			policies: []Policy{{Name: "small", Resources: []string{"mangodbs.mangodb.com"}, Expression: "object.spec.size != 'large'"}},
+			gr:       mangodbs,
+			obj:      object("public", map[string]interface{}{"size": "large"}),
+			want:     &Violation{Policy: "small", Message: "failed expression: object.spec.size != 'large'"},
+		},
+		{
+			name:     "evaluation error fails closed",
+			policies: []Policy{{Name: "missing", Expression: "object.spec.missing == 'x'"}},
+			gr:       mangodbs,
+			obj:      object("public", map[string]interface{}{}),
+			want:     &Violation{Policy: "missing", Message: "evaluation failed: no such key: missing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEvaluator(tt.policies, getNamespace)
+			require.NoError(t, err)
+			got, err := e.Evaluate(tt.gr, tt.obj)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	_, err := compile([]Policy{{Name: "a", Expression: "object.spec"}})
+	require.NoError(t, err, "dyn expressions are checked at evaluation")
+
+	_, err = compile([]Policy{{Name: "a", Expression: "1 + 1"}})
+	require.Error(t, err)
+
+	_, err = compile([]Policy{{Name: "a", Expression: "true"}, {Name: "a", Expression: "true"}})
+	require.Error(t, err)
+
+	_, err = compile([]Policy{{Name: "a", Expression: "object."}})
+	require.Error(t, err)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"bytes"
+	"os"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+// ProviderCABundleKey is the key of the CA bundle in the --provider-ca-configmap ConfigMap.
+const ProviderCABundleKey = "ca.crt"
+
+// ProviderCA is an additional CA bundle trusted for all service provider clusters,
+// such that providers with a private CA work without embedding the CA in every
+// kubeconfig secret.
+type ProviderCA struct {
+	// File is the CA bundle read from --provider-ca-file.
+	File []byte
+
+	// ConfigMapInformer watches the --provider-ca-configmap ConfigMap. It is nil
+	// if no ConfigMap is configured.
+	ConfigMapInformer coreinformers.ConfigMapInformer
+	Namespace         string
+	Name              string
+}
+
+// Bundle returns the file and ConfigMap CA bundles concatenated. A missing
+// ConfigMap is treated as empty.
+func (p ProviderCA) Bundle() ([]byte, error) {
+	bundle := p.File
+	if p.ConfigMapInformer == nil {
+		return bundle, nil
+	}
+	cm, err := p.ConfigMapInformer.Lister().ConfigMaps(p.Namespace).Get(p.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	} else if errors.IsNotFound(err) {
+		return bundle, nil
+	}
+	return appendPEM(bundle, []byte(cm.Data[ProviderCABundleKey])), nil
+}
+
+// withCABundle makes the rest config trust the CA bundle in addition to the CA of
+// the kubeconfig. Note that a kubeconfig without CA then trusts only the bundle
+// instead of the system roots.
+func withCABundle(config *rest.Config, bundle []byte) error {
+	if len(bundle) == 0 || config.Insecure {
+		return nil
+	}
+	ca := config.CAData
+	if len(ca) == 0 && config.CAFile != "" {
+		var err error
+		if ca, err = os.ReadFile(config.CAFile); err != nil {
+			return err
+		}
+	}
+	config.CAData = appendPEM(ca, bundle)
+	config.CAFile = ""
+	return nil
+}
+
+func appendPEM(a, b []byte) []byte {
+	a = bytes.TrimSpace(a)
+	b = bytes.TrimSpace(b)
+	if len(a) == 0 || len(b) == 0 {
+		return append(append([]byte{}, a...), b...)
+	}
+	merged := append(append([]byte{}, a...), '\n')
+	return append(merged, b...)
+}
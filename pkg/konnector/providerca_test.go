@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestWithCABundle(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, []byte("kubeconfig-file-ca\n"), 0600))
+
+	tests := []struct {
+		name       string
+		config     rest.Config
+		bundle     string
+		wantCAData string
+		wantCAFile string
+	}{
+		{
+			name:       "no bundle",
+			config:     rest.Config{TLSClientConfig: rest.TLSClientConfig{CAData: []byte("kubeconfig-ca")}},
+			wantCAData: "kubeconfig-ca",
+		},
+		{
+			name:       "merged with kubeconfig CA",
+			config:     rest.Config{TLSClientConfig: rest.TLSClientConfig{CAData: []byte("kubeconfig-ca\n")}},
+			bundle:     "provider-ca\n",
+			wantCAData: "kubeconfig-ca\nprovider-ca",
+		},
+		{
+			name:       "merged with kubeconfig CA file",
+			config:     rest.Config{TLSClientConfig: rest.TLSClientConfig{CAFile: caFile}},
+			bundle:     "provider-ca",
+			wantCAData: "kubeconfig-file-ca\nprovider-ca",
+		},
+		{
+			name:       "kubeconfig without CA",
+			bundle:     "provider-ca",
+			wantCAData: "provider-ca",
+		},
+		{
+			name:   "insecure",
+			config: rest.Config{TLSClientConfig: rest.TLSClientConfig{Insecure: true}},
+			bundle: "provider-ca",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			require.NoError(t, withCABundle(&config, []byte(tt.bundle)))
+			require.Equal(t, tt.wantCAData, string(config.CAData))
+			require.Equal(t, tt.wantCAFile, config.CAFile)
+		})
+	}
+}
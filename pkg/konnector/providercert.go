@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+// The keys of a client certificate and key in the kubeconfig secret of an
+// APIServiceBinding, e.g. issued per consumer and rotated by cert-manager. They
+// take precedence over a client certificate in the kubeconfig, and the sync is
+// restarted when they change.
+//
+// Client certificate files referenced by the kubeconfig, e.g. mounted into the
+// konnector pod, need no restart. They are reloaded on new connections.
+const (
+	ProviderClientCertKey = corev1.TLSCertKey
+	ProviderClientKeyKey  = corev1.TLSPrivateKeyKey
+)
+
+// clientCertificate returns the PEM encoded client certificate and key in the
+// kubeconfig secret, or nil if there are none.
+func clientCertificate(secret *corev1.Secret) (cert, key []byte, err error) {
+	cert, key = secret.Data[ProviderClientCertKey], secret.Data[ProviderClientKeyKey]
+	if len(cert) == 0 && len(key) == 0 {
+		return nil, nil, nil
+	}
+	if len(cert) == 0 || len(key) == 0 {
+		return nil, nil, fmt.Errorf("secret must have both %q and %q, or none", ProviderClientCertKey, ProviderClientKeyKey)
+	}
+	return cert, key, nil
+}
+
+// withClientCertificate makes the service provider config authenticate with the
+// client certificate instead of the one in the kubeconfig, if any.
+func withClientCertificate(config *rest.Config, cert, key []byte) error {
+	if len(cert) == 0 {
+		return nil
+	}
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		return fmt.Errorf("invalid client certificate: %w", err)
+	}
+	if config.ExecProvider != nil || config.AuthProvider != nil {
+		return errors.New("client certificate cannot be combined with exec or auth provider in the kubeconfig")
+	}
+	config.CertData, config.KeyData = cert, key
+	config.CertFile, config.KeyFile = "", ""
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func TestClientCertificate(t *testing.T) {
+	cert, key, err := certutil.GenerateSelfSignedCertKey("consumer", nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		data     map[string][]byte
+		config   rest.Config
+		wantErr  bool
+		wantCert []byte
+		wantFile string
+	}{
+		{
+			name:     "none",
+			data:     map[string][]byte{"kubeconfig": []byte("kubeconfig")},
+			config:   rest.Config{TLSClientConfig: rest.TLSClientConfig{CertFile: "client.crt", KeyFile: "client.key"}},
+			wantFile: "client.crt",
+		},
+		{
+			name:     "replaces kubeconfig certificate",
+			data:     map[string][]byte{ProviderClientCertKey: cert, ProviderClientKeyKey: key},
+			config:   rest.Config{TLSClientConfig: rest.TLSClientConfig{CertFile: "client.crt", KeyFile: "client.key"}},
+			wantCert: cert,
+		},
+		{
+			name:    "missing key",
+			data:    map[string][]byte{ProviderClientCertKey: cert},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pair",
+			data:    map[string][]byte{ProviderClientCertKey: cert, ProviderClientKeyKey: []byte("key")},
+			wantErr: true,
+		},
+		{
+			name:    "exec provider",
+			data:    map[string][]byte{ProviderClientCertKey: cert, ProviderClientKeyKey: key},
+			config:  rest.Config{ExecProvider: &clientcmdapi.ExecConfig{Command: "get-token"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			cert, key, err := clientCertificate(&corev1.Secret{Data: tt.data})
+			if err == nil {
+				err = withClientCertificate(&config, cert, key)
+			}
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCert, config.CertData)
+			require.Equal(t, tt.wantFile, config.CertFile)
+		})
+	}
+}
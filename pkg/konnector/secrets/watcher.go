@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/indexers"
+)
+
+// Watcher watches the Secrets referenced by APIServiceBindings, i.e. the kubeconfigs
+// of service provider clusters and of virtual clusters, each with a single-object
+// watch. Other Secrets of the consumer cluster are neither cached nor visible to
+// the konnector.
+type Watcher struct {
+	client              kubernetes.Interface
+	resyncPeriod        time.Duration
+	listServiceBindings func() ([]*kubebindv1alpha1.APIServiceBinding, error)
+
+	lock     sync.Mutex
+	ctx      context.Context // nil until started
+	watches  map[string]*watch
+	handlers []cache.ResourceEventHandler
+}
+
+type watch struct {
+	informer cache.SharedIndexInformer
+	cancel   context.CancelFunc
+}
+
+// NewWatcher returns a Watcher that follows the Secret references of the given
+// APIServiceBindings.
+func NewWatcher(client kubernetes.Interface, resyncPeriod time.Duration, serviceBindingInformer bindinformers.APIServiceBindingInformer) *Watcher {
+	w := &Watcher{
+		client:       client,
+		resyncPeriod: resyncPeriod,
+		listServiceBindings: func() ([]*kubebindv1alpha1.APIServiceBinding, error) {
+			return serviceBindingInformer.Lister().List(labels.Everything())
+		},
+		watches: map[string]*watch{},
+	}
+
+	serviceBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { w.sync() },
+		UpdateFunc: func(_, _ interface{}) { w.sync() },
+		DeleteFunc: func(interface{}) { w.sync() },
+	})
+
+	return w
+}
+
+// References returns the keys, as namespace/name, of the Secrets the binding references.
+func References(binding *kubebindv1alpha1.APIServiceBinding) []string {
+	refs := []string{indexers.ByServiceBindingKubeconfigSecretKey(binding)}
+	if vc := binding.Spec.VirtualCluster; vc != nil {
+		refs = append(refs, vc.KubeconfigSecretRef.Namespace+"/"+vc.KubeconfigSecretRef.Name)
+	}
+	return refs
+}
+
+// AddEventHandler adds a handler that is called for every change of a watched Secret.
+func (w *Watcher) AddEventHandler(handler cache.ResourceEventHandler) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.handlers = append(w.handlers, handler)
+	for _, wt := range w.watches {
+		wt.informer.AddEventHandler(handler)
+	}
+}
+
+// Start starts the watches of the referenced Secrets. They are stopped when ctx is done.
+func (w *Watcher) Start(ctx context.Context) {
+	w.lock.Lock()
+	w.ctx = ctx
+	w.lock.Unlock()
+
+	w.sync()
+}
+
+// Get returns the Secret from its watch. A Secret that is not watched yet gets a
+// watch, and an error is returned until it has synced.
+func (w *Watcher) Get(namespace, name string) (*corev1.Secret, error) {
+	key := namespace + "/" + name
+
+	w.lock.Lock()
+	wt, found := w.watches[key]
+	if !found && w.ctx != nil {
+		wt = w.startLocked(key)
+		found = true
+	}
+	w.lock.Unlock()
+
+	if !found {
+		return nil, fmt.Errorf("secret %s is not watched yet", key)
+	}
+	if !wt.informer.HasSynced() {
+		return nil, fmt.Errorf("secret %s is not synced yet", key)
+	}
+	obj, exists, err := wt.informer.GetStore().GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(corev1.Resource("secrets"), name)
+	}
+	return obj.(*corev1.Secret), nil
+}
+
+// sync starts watches for newly referenced Secrets and stops those no longer referenced.
+func (w *Watcher) sync() {
+	bindings, err := w.listServiceBindings()
+	if err != nil {
+		klog.Background().Error(err, "failed to list APIServiceBindings")
+		return
+	}
+	referenced := sets.NewString()
+	for _, binding := range bindings {
+		referenced.Insert(References(binding)...)
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.ctx == nil {
+		return
+	}
+	for key, wt := range w.watches {
+		if !referenced.Has(key) {
+			klog.Background().V(2).Info("stopping watch of Secret", "key", key)
+			wt.cancel()
+			delete(w.watches, key)
+		}
+	}
+	for _, key := range referenced.List() {
+		if _, found := w.watches[key]; !found {
+			w.startLocked(key)
+		}
+	}
+}
+
+func (w *Watcher) startLocked(key string) *watch {
+	klog.Background().V(2).Info("starting watch of Secret", "key", key)
+
+	ns, name, _ := cache.SplitMetaNamespaceKey(key) // nolint:errcheck
+	informer := coreinformers.NewFilteredSecretInformer(w.client, ns, w.resyncPeriod, cache.Indexers{}, func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	})
+	for _, handler := range w.handlers {
+		informer.AddEventHandler(handler)
+	}
+
+	ctx, cancel := context.WithCancel(w.ctx)
+	go informer.Run(ctx.Done())
+
+	wt := &watch{informer: informer, cancel: cancel}
+	w.watches[key] = wt
+	return wt
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindfake "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned/fake"
+	bindinformers "github.com/kube-bind/kube-bind/pkg/client/informers/externalversions"
+)
+
+func TestWatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	binding := &kubebindv1alpha1.APIServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com"},
+		Spec: kubebindv1alpha1.APIServiceBindingSpec{
+			KubeconfigSecretRef: kubebindv1alpha1.ClusterSecretKeyRef{
+				LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{Name: "kubeconfig-abc", Key: "kubeconfig"},
+				Namespace:         "kube-bind",
+			},
+		},
+	}
+	kubeClient := kubefake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig-abc"},
+	})
+	bindClient := bindfake.NewSimpleClientset(binding)
+	factory := bindinformers.NewSharedInformerFactory(bindClient, 0)
+
+	w := NewWatcher(kubeClient, 0, factory.KubeBind().V1alpha1().APIServiceBindings())
+	changed := make(chan string, 10)
+	w.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { changed <- obj.(*corev1.Secret).Name },
+	})
+
+	_, err := w.Get("kube-bind", "kubeconfig-abc")
+	require.Error(t, err, "nothing is watched before start")
+
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+	w.Start(ctx)
+
+	require.Eventually(t, func() bool {
+		secret, err := w.Get("kube-bind", "kubeconfig-abc")
+		return err == nil && secret.Name == "kubeconfig-abc"
+	}, wait.ForeverTestTimeout, 10*time.Millisecond)
+	require.Equal(t, "kubeconfig-abc", <-changed)
+
+	require.Eventually(t, func() bool {
+		_, err := w.Get("kube-bind", "kubeconfig-missing")
+		return errors.IsNotFound(err)
+	}, wait.ForeverTestTimeout, 10*time.Millisecond)
+
+	require.NoError(t, bindClient.KubeBindV1alpha1().APIServiceBindings().Delete(ctx, binding.Name, metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+		_, found := w.watches["kube-bind/kubeconfig-abc"]
+		return !found
+	}, wait.ForeverTestTimeout, 10*time.Millisecond, "the watch of an unreferenced Secret must be stopped")
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfupgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	"k8s.io/klog/v2"
+)
+
+// ContainerName is the name of the konnector container in its Deployment.
+const ContainerName = "konnector"
+
+// Controller upgrades the image of the konnector Deployment to the target
+// version of the Requirements if the running konnector is older. It never
+// downgrades, and leaves development builds alone.
+type Controller struct {
+	client      appsv1client.DeploymentsGetter
+	namespace   string
+	name        string
+	image       string
+	running     string
+	requirement *Requirements
+	interval    time.Duration
+}
+
+// NewController returns a Controller for the Deployment namespace/name, setting
+// the image to <image>:<version>.
+func NewController(client appsv1client.DeploymentsGetter, namespace, name, image, running string, requirements *Requirements) *Controller {
+	return &Controller{
+		client:      client,
+		namespace:   namespace,
+		name:        name,
+		image:       image,
+		running:     running,
+		requirement: requirements,
+		interval:    time.Minute,
+	}
+}
+
+// Start checks the requirements periodically until the context is done.
+func (c *Controller) Start(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("controller", "selfupgrade", "deployment", c.namespace+"/"+c.name)
+	ctx = klog.NewContext(ctx, logger)
+
+	if c.running == DevelopmentVersion {
+		logger.Info("Self-upgrade disabled for development builds")
+		return
+	} else if _, err := parse(c.running); err != nil {
+		logger.Error(err, "Self-upgrade disabled for unknown konnector version", "version", c.running)
+		return
+	}
+	logger.Info("Starting self-upgrade controller", "version", c.running, "image", c.image)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.sync(ctx); err != nil {
+			logger.Error(err, "failed to upgrade konnector")
+		}
+	}, c.interval)
+}
+
+func (c *Controller) sync(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+
+	target := c.requirement.Target()
+	if target == "" {
+		return nil
+	}
+	targetVersion, err := parse(target)
+	if err != nil {
+		return err
+	}
+	if current, err := parse(c.running); err != nil {
+		return err
+	} else if !current.LT(targetVersion) {
+		return nil
+	}
+
+	deployment, err := c.client.Deployments(c.namespace).Get(ctx, c.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	image := c.image + ":" + target
+	found := false
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name != ContainerName {
+			continue
+		}
+		found = true
+		if container.Image == image {
+			return nil // rollout in progress
+		}
+	}
+	if !found {
+		return fmt.Errorf("deployment %s/%s has no container %q", c.namespace, c.name, ContainerName)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []map[string]interface{}{
+						{"name": ContainerName, "image": image},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info("Upgrading konnector to the version required by the service providers", "from", c.running, "to", target, "image", image)
+	_, err = c.client.Deployments(c.namespace).Patch(ctx, c.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
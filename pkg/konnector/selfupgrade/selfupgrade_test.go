@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfupgrade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestSkew(t *testing.T) {
+	req := &kubebindv1alpha1.KonnectorVersionRequirements{Minimum: "v0.4.0", Recommended: "v0.5.0"}
+	tests := []struct {
+		running string
+		req     *kubebindv1alpha1.KonnectorVersionRequirements
+		want    string
+	}{
+		{running: "v0.3.2", req: req, want: ReasonBelowMinimum},
+		{running: "v0.4.1", req: req, want: ReasonBelowRecommended},
+		{running: "v0.5.0", req: req},
+		{running: "v0.6.0", req: req},
+		{running: "v0.3.2"},
+		{running: DevelopmentVersion, req: req},
+		{running: "unknown", req: req},
+		{running: "v0.3.2", req: &kubebindv1alpha1.KonnectorVersionRequirements{Recommended: "v0.4.0"}, want: ReasonBelowRecommended},
+	}
+	for _, tt := range tests {
+		reason, _ := Skew(tt.running, tt.req)
+		require.Equal(t, tt.want, reason, tt.running)
+	}
+}
+
+func TestRequirementsTarget(t *testing.T) {
+	r := NewRequirements()
+	require.Empty(t, r.Target())
+
+	r.Set("a", &kubebindv1alpha1.KonnectorVersionRequirements{Minimum: "v0.4.0", Recommended: "v0.5.0"})
+	r.Set("b", &kubebindv1alpha1.KonnectorVersionRequirements{Minimum: "v0.4.2"})
+	require.Equal(t, "v0.5.0", r.Target())
+
+	r.Set("c", &kubebindv1alpha1.KonnectorVersionRequirements{Minimum: "v0.10.0"})
+	require.Equal(t, "v0.10.0", r.Target())
+
+	r.Set("c", nil)
+	require.Equal(t, "v0.5.0", r.Target())
+}
+
+func TestControllerSync(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "konnector"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: ContainerName, Image: "ghcr.io/kube-bind/konnector:v0.4.0"}},
+		}}},
+	})
+	image := func() string {
+		d, err := client.AppsV1().Deployments("kube-bind").Get(ctx, "konnector", metav1.GetOptions{})
+		require.NoError(t, err)
+		return d.Spec.Template.Spec.Containers[0].Image
+	}
+
+	r := NewRequirements()
+	c := NewController(client.AppsV1(), "kube-bind", "konnector", "ghcr.io/kube-bind/konnector", "v0.4.0", r)
+
+	// up to date
+	r.Set("a", &kubebindv1alpha1.KonnectorVersionRequirements{Minimum: "v0.3.0"})
+	require.NoError(t, c.sync(ctx))
+	require.Equal(t, "ghcr.io/kube-bind/konnector:v0.4.0", image())
+
+	// upgrade
+	r.Set("b", &kubebindv1alpha1.KonnectorVersionRequirements{Minimum: "v0.4.0", Recommended: "v0.5.1"})
+	require.NoError(t, c.sync(ctx))
+	require.Equal(t, "ghcr.io/kube-bind/konnector:v0.5.1", image())
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package selfupgrade compares the konnector version with the versions required
+// and recommended by the service providers of all bindings, and optionally
+// upgrades the konnector Deployment to a version satisfying all of them.
+package selfupgrade
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// DevelopmentVersion is the version of konnector binaries built without
+// version ldflags. They are never considered skewed nor upgraded.
+const DevelopmentVersion = "v0.0.0"
+
+const (
+	// ReasonBelowMinimum is the VersionSkew reason if the konnector is older than
+	// the minimum version of the service provider.
+	ReasonBelowMinimum = "BelowMinimumVersion"
+	// ReasonBelowRecommended is the VersionSkew reason if the konnector is older
+	// than the recommended version of the service provider.
+	ReasonBelowRecommended = "BelowRecommendedVersion"
+)
+
+// Skew compares the running konnector version with the requirements of a
+// service provider. It returns an empty reason if the konnector is up to date,
+// or if a version cannot be compared.
+func Skew(running string, req *kubebindv1alpha1.KonnectorVersionRequirements) (reason, message string) {
+	if req == nil || running == DevelopmentVersion {
+		return "", ""
+	}
+	current, err := parse(running)
+	if err != nil {
+		return "", ""
+	}
+	if v, err := parse(req.Minimum); err == nil && current.LT(v) {
+		return ReasonBelowMinimum, fmt.Sprintf("Konnector %s is older than the minimum version %s supported by the service provider", running, req.Minimum)
+	}
+	if v, err := parse(req.Recommended); err == nil && current.LT(v) {
+		return ReasonBelowRecommended, fmt.Sprintf("Konnector %s is older than the version %s recommended by the service provider", running, req.Recommended)
+	}
+	return "", ""
+}
+
+// Requirements collects the konnector version requirements of the service
+// providers of all bindings. It is safe for concurrent use.
+type Requirements struct {
+	lock       sync.Mutex
+	byProvider map[string]kubebindv1alpha1.KonnectorVersionRequirements
+}
+
+// NewRequirements returns empty Requirements.
+func NewRequirements() *Requirements {
+	return &Requirements{byProvider: map[string]kubebindv1alpha1.KonnectorVersionRequirements{}}
+}
+
+// Set records the requirements of the service provider with the given key, or
+// forgets them if req is nil.
+func (r *Requirements) Set(provider string, req *kubebindv1alpha1.KonnectorVersionRequirements) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if req == nil {
+		delete(r.byProvider, provider)
+		return
+	}
+	r.byProvider[provider] = *req
+}
+
+// Target returns the lowest version satisfying the minimum versions of all
+// service providers, raised to the highest recommended version. As service
+// providers do not cap the version, it is compatible with all of them. It
+// returns an empty string without requirements.
+func (r *Requirements) Target() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	providers := make([]string, 0, len(r.byProvider))
+	for provider := range r.byProvider {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	var target string
+	var targetVersion semver.Version
+	for _, provider := range providers {
+		req := r.byProvider[provider]
+		for _, s := range []string{req.Minimum, req.Recommended} {
+			if v, err := parse(s); err == nil && (target == "" || v.GT(targetVersion)) {
+				target, targetVersion = s, v
+			}
+		}
+	}
+	return target
+}
+
+func parse(s string) (semver.Version, error) {
+	if s == "" {
+		return semver.Version{}, fmt.Errorf("empty version")
+	}
+	return semver.Parse(strings.TrimPrefix(s, "v"))
+}
@@ -18,13 +18,29 @@ package konnector
 
 import (
 	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/component-base/metrics/legacyregistry"
+	_ "k8s.io/component-base/metrics/prometheus/workqueue" // register workqueue metrics, per controller name
 	"k8s.io/klog/v2"
 
 	"github.com/kube-bind/kube-bind/deploy/crd"
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/konnector/options"
+	"github.com/kube-bind/kube-bind/pkg/konnector/policy"
+	"github.com/kube-bind/kube-bind/pkg/konnector/secrets"
+	"github.com/kube-bind/kube-bind/pkg/konnector/sharding"
+	"github.com/kube-bind/kube-bind/pkg/konnector/webhook"
+	"github.com/kube-bind/kube-bind/pkg/migration"
 )
 
 type Server struct {
@@ -33,13 +49,70 @@ type Server struct {
 }
 
 func NewServer(config *Config) (*Server, error) {
+	var upsyncPolicies []policy.Policy
+	if path := config.Options.UpsyncPoliciesFile; path != "" {
+		var err error
+		if upsyncPolicies, err = policy.Load(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var providerCA ProviderCA
+	if path := config.Options.ProviderCAFile; path != "" {
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(bs) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", path)
+		}
+		providerCA.File = bs
+	}
+	if config.ProviderCAInformers != nil {
+		ns, name, err := config.Options.ProviderCAConfigMapNamespaceName()
+		if err != nil {
+			return nil, err
+		}
+		providerCA.ConfigMapInformer = config.ProviderCAInformers.Core().V1().ConfigMaps()
+		providerCA.Namespace, providerCA.Name = ns, name
+	}
+
+	shards := config.Shards
+	if shards == nil {
+		shards = sharding.All
+	}
+
 	// construct controllers
 	k, err := New(
 		config.ClientConfig,
 		config.BindInformers.KubeBind().V1alpha1().APIServiceBindings(),
-		config.KubeInformers.Core().V1().Secrets(), // TODO(sttts): watch individual secrets for security and memory consumption
-		config.KubeInformers.Core().V1().Namespaces(),
+		config.BindInformers.KubeBind().V1alpha1().BackendTrustPolicies(),
+		secrets.NewWatcher(config.KubeClient, config.Options.Resync.Period, config.BindInformers.KubeBind().V1alpha1().APIServiceBindings()),
+		config.MetadataInformers.ForResource(corev1.SchemeGroupVersion.WithResource("namespaces")),
 		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
+		config.KubeInformers.Rbac().V1().ClusterRoles(),
+		config.Options.SyncedConditionMaxStaleness,
+		config.Options.HibernateIdleBindingsAfter,
+		config.Options.CanaryInterval,
+		config.Options.SyncSnapshotDir,
+		config.Options.Sync,
+		config.Options.Workers,
+		upsyncPolicies,
+		config.Options.CRDAllowlist,
+		config.Options.RBACClusterRole,
+		config.Options.AllowedRegions,
+		config.Options.WatchNamespaces,
+		config.Options.SyncedPrinterColumns,
+		config.Options.RequireBindingApproval,
+		config.Options.ProviderProxyURL,
+		providerCA,
+		config.Options.ProviderAPI,
+		config.Options.Resync,
+		config.Options.RateLimiter,
+		shards,
+		config.VersionRequirements,
+		config.Options.LeaseLockIdentity,
+		config.Options.DryRun,
 	)
 	if err != nil {
 		return nil, err
@@ -62,13 +135,52 @@ type Prepared struct {
 }
 
 func (s *Server) PrepareRun(ctx context.Context) (Prepared, error) {
-	// install/upgrade CRDs
-	if err := crd.Create(ctx,
-		s.Config.ApiextensionsClient.ApiextensionsV1().CustomResourceDefinitions(),
-		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiservicebindings"},
-	); err != nil {
-		return Prepared{}, err
+	logger := klog.FromContext(ctx)
+
+	crds := []metav1.GroupResource{
+		{Group: kubebindv1alpha1.GroupName, Resource: "apiservicebindings"},
+		{Group: kubebindv1alpha1.GroupName, Resource: "backendtrustpolicies"},
+	}
+	if s.Config.Options.InstallCRDs {
+		// install/upgrade CRDs
+		if err := crd.CreateWithPolicy(ctx,
+			s.Config.ApiextensionsClient.ApiextensionsV1().CustomResourceDefinitions(),
+			crd.UpgradePolicy(s.Config.Options.CRDUpgradePolicy),
+			s.recordCRDAction(ctx),
+			crds...,
+		); err != nil && (ctx.Err() != nil || errors.Is(err, crd.ErrUpgradeRefused) || s.Config.Options.CRDConflictPolicy == options.CRDConflictPolicyFail) {
+			return Prepared{}, err
+		} else if err != nil {
+			logger.Error(err, "failed to install or upgrade CRDs, continuing with existing ones because of --crd-conflict-policy=Warn")
+		}
+	} else {
+		// the CRDs are managed by someone else, e.g. GitOps. Wait for them.
+		logger.Info("skipping CRD installation because of --install-crds=false")
+		if err := crd.WaitForEstablished(ctx,
+			s.Config.ApiextensionsClient.ApiextensionsV1().CustomResourceDefinitions(),
+			crds...,
+		); err != nil {
+			return Prepared{}, err
+		}
+	}
+
+	if s.Config.Options.MigrateStorage && s.Config.Options.DryRun {
+		logger.Info("skipping storage migration because of --dry-run")
+	} else if s.Config.Options.MigrateStorage {
+		dynamicClient, err := dynamic.NewForConfig(s.Config.ClientConfig)
+		if err != nil {
+			return Prepared{}, err
+		}
+		if err := migration.Migrate(ctx,
+			s.Config.ApiextensionsClient.ApiextensionsV1().CustomResourceDefinitions(),
+			dynamicClient,
+			migration.Migrations,
+			crds...,
+		); err != nil {
+			return Prepared{}, err
+		}
 	}
+
 	return Prepared{
 		prepared: &prepared{
 			Server: *s,
@@ -76,7 +188,9 @@ func (s *Server) PrepareRun(ctx context.Context) (Prepared, error) {
 	}, nil
 }
 
-func (s *Prepared) OptionallyStartInformers(ctx context.Context) {
+// OptionallyStartInformers starts the informers and waits for their caches to
+// sync. It returns whether all of them have synced.
+func (s *Prepared) OptionallyStartInformers(ctx context.Context) bool {
 	logger := klog.FromContext(ctx)
 
 	// start informer factories
@@ -84,18 +198,104 @@ func (s *Prepared) OptionallyStartInformers(ctx context.Context) {
 	s.Config.KubeInformers.Start(ctx.Done())
 	s.Config.BindInformers.Start(ctx.Done())
 	s.Config.ApiextensionsInformers.Start(ctx.Done())
+	s.Config.MetadataInformers.Start(ctx.Done())
+	if s.Config.ProviderCAInformers != nil {
+		s.Config.ProviderCAInformers.Start(ctx.Done())
+		s.Config.ProviderCAInformers.WaitForCacheSync(ctx.Done())
+	}
 	kubeSynced := s.Config.KubeInformers.WaitForCacheSync(ctx.Done())
 	kubeBindSynced := s.Config.BindInformers.WaitForCacheSync(ctx.Done())
 	apiextensionsSynced := s.Config.ApiextensionsInformers.WaitForCacheSync(ctx.Done())
+	metadataSynced := s.Config.MetadataInformers.WaitForCacheSync(ctx.Done())
 
 	logger.Info("local informers are synced",
 		"kubeSynced", fmt.Sprintf("%v", kubeSynced),
 		"kubeBindSynced", fmt.Sprintf("%v", kubeBindSynced),
 		"apiextensionsSynced", fmt.Sprintf("%v", apiextensionsSynced),
+		"metadataSynced", fmt.Sprintf("%v", metadataSynced),
 	)
+
+	for _, synced := range []map[reflect.Type]bool{kubeSynced, kubeBindSynced, apiextensionsSynced} {
+		for _, ok := range synced {
+			if !ok {
+				return false
+			}
+		}
+	}
+	for _, ok := range metadataSynced {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// OptionallyStartMetricsServer serves Prometheus metrics if a metrics bind address
+// is configured. It is started on every replica, independent of leader election.
+func (s *Prepared) OptionallyStartMetricsServer(ctx context.Context) {
+	if s.Config.Options.MetricsBindAddress == "" {
+		return
+	}
+	logger := klog.FromContext(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", legacyregistry.Handler())
+	server := &http.Server{Addr: s.Config.Options.MetricsBindAddress, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close() // nolint:errcheck
+	}()
+	go func() {
+		logger.Info("serving metrics", "address", s.Config.Options.MetricsBindAddress)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(err, "failed to serve metrics")
+		}
+	}()
+}
+
+// OptionallyStartDebugServer serves net/http/pprof profiles if a debug address is
+// configured, e.g. to capture heap and goroutine profiles.
+func (s *Prepared) OptionallyStartDebugServer(ctx context.Context) {
+	if s.Config.Options.DebugAddress == "" {
+		return
+	}
+	logger := klog.FromContext(ctx)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	server := &http.Server{Addr: s.Config.Options.DebugAddress, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close() // nolint:errcheck
+	}()
+	go func() {
+		logger.Info("serving pprof profiles", "address", s.Config.Options.DebugAddress)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(err, "failed to serve pprof profiles")
+		}
+	}()
+}
+
+// OptionallyStartWebhookServer serves the plan limits admission webhook if a webhook
+// bind address is configured. It is started on every replica, independent of leader
+// election.
+func (s *Prepared) OptionallyStartWebhookServer(ctx context.Context) error {
+	if s.Config.Options.WebhookBindAddress == "" {
+		return nil
+	}
+
+	webhook.Serve(ctx, s.Config.Options.WebhookBindAddress, s.Config.Options.WebhookCertDir, webhook.NewLimits(
+		s.Config.BindInformers.KubeBind().V1alpha1().APIServiceBindings().Lister(),
+		webhook.ObjectCounter(s.Config.MetadataClient),
+	))
+	return nil
 }
 
 func (s Prepared) Run(ctx context.Context) error {
-	s.Controller.Start(ctx, 2)
+	s.Controller.Start(ctx, s.Config.Options.Workers.NumDefault())
 	return nil
 }
@@ -0,0 +1,244 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharding distributes APIServiceBindings across active konnector
+// replicas. Every replica keeps a membership Lease alive, and every shard key is
+// owned by exactly one live member, chosen by rendezvous hashing. When a replica
+// joins or leaves, only the keys of that replica move.
+package sharding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// GroupLabelKey is set on the membership Leases to the name of the shard group,
+// i.e. of all replicas of one konnector deployment.
+const GroupLabelKey = "kube-bind.io/shard-group"
+
+// Owner tells whether this replica serves a shard key.
+type Owner interface {
+	// Owns returns whether this replica serves the key.
+	Owns(key string) bool
+	// AddHandler registers a function called when the ownership of keys changes.
+	AddHandler(handler func())
+}
+
+// All is the Owner of a single active replica that serves every key.
+var All Owner = all{}
+
+type all struct{}
+
+func (all) Owns(string) bool  { return true }
+func (all) AddHandler(func()) {}
+
+// BindingKey returns the shard key of the APIServiceBinding. Bindings of the
+// same kubeconfig secret, i.e. of the same service provider, share one cluster
+// controller and hence one shard.
+func BindingKey(binding *kubebindv1alpha1.APIServiceBinding) string {
+	ref := binding.Spec.KubeconfigSecretRef
+	return ref.Namespace + "/" + ref.Name
+}
+
+// OwnerOf returns the member owning the key by rendezvous hashing, or an empty
+// string if there are no members.
+func OwnerOf(members []string, key string) string {
+	var owner string
+	var highest uint64
+	for _, m := range members {
+		sum := sha256.Sum256([]byte(m + "/" + key))
+		if score := binary.BigEndian.Uint64(sum[:8]); owner == "" || score > highest || (score == highest && m < owner) {
+			owner, highest = m, score
+		}
+	}
+	return owner
+}
+
+// Sharder maintains the membership Lease of this replica and the set of live
+// members of the shard group.
+type Sharder struct {
+	client        coordinationv1client.LeasesGetter
+	namespace     string
+	group         string
+	identity      string
+	leaseDuration time.Duration
+	renewInterval time.Duration
+
+	lock     sync.RWMutex
+	members  []string // sorted
+	handlers []func()
+
+	now func() time.Time
+}
+
+// NewSharder returns a Sharder for the replica identity in the shard group. The
+// membership Leases live in the given namespace. Replicas that do not renew their
+// Lease within leaseDuration lose their keys.
+func NewSharder(client coordinationv1client.LeasesGetter, namespace, group, identity string, leaseDuration, renewInterval time.Duration) *Sharder {
+	return &Sharder{
+		client:        client,
+		namespace:     namespace,
+		group:         group,
+		identity:      identity,
+		leaseDuration: leaseDuration,
+		renewInterval: renewInterval,
+		now:           time.Now,
+	}
+}
+
+// Owns returns whether this replica serves the key. Before the first membership
+// round, it owns nothing.
+func (s *Sharder) Owns(key string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return OwnerOf(s.members, key) == s.identity
+}
+
+// Members returns the live members of the shard group.
+func (s *Sharder) Members() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return append([]string(nil), s.members...)
+}
+
+// AddHandler registers a function called when members join or leave.
+func (s *Sharder) AddHandler(handler func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// Start joins the shard group and keeps the membership up to date until the
+// context is done. Then the membership Lease is deleted, such that the other
+// members take over the keys immediately.
+func (s *Sharder) Start(ctx context.Context) error {
+	if err := s.sync(ctx); err != nil {
+		return err
+	}
+	go func() {
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			if err := s.sync(ctx); err != nil && ctx.Err() == nil {
+				klog.FromContext(ctx).Error(err, "failed to sync shard membership")
+			}
+		}, s.renewInterval)
+
+		// leave the group
+		logger := klog.FromContext(ctx)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.client.Leases(s.namespace).Delete(ctx, s.leaseName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "failed to delete shard membership Lease")
+		}
+	}()
+	return nil
+}
+
+func (s *Sharder) leaseName() string {
+	return fmt.Sprintf("%s-%s", s.group, s.identity)
+}
+
+// sync renews the own Lease and recomputes the live members.
+func (s *Sharder) sync(ctx context.Context) error {
+	if err := s.renew(ctx); err != nil {
+		return err
+	}
+
+	leases, err := s.client.Leases(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{GroupLabelKey: s.group}).String(),
+	})
+	if err != nil {
+		return err
+	}
+	members := liveMembers(leases.Items, s.now())
+
+	s.lock.Lock()
+	changed := fmt.Sprintf("%v", s.members) != fmt.Sprintf("%v", members)
+	s.members = members
+	handlers := s.handlers
+	s.lock.Unlock()
+
+	if changed {
+		klog.FromContext(ctx).Info("shard membership changed", "members", members)
+		for _, h := range handlers {
+			h()
+		}
+	}
+	return nil
+}
+
+func (s *Sharder) renew(ctx context.Context) error {
+	now := metav1.NewMicroTime(s.now())
+	seconds := int32(s.leaseDuration / time.Second)
+	spec := coordinationv1.LeaseSpec{
+		HolderIdentity:       pointer.String(s.identity),
+		LeaseDurationSeconds: &seconds,
+		RenewTime:            &now,
+	}
+
+	leases := s.client.Leases(s.namespace)
+	existing, err := leases.Get(ctx, s.leaseName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		spec.AcquireTime = &now
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.leaseName(),
+				Namespace: s.namespace,
+				Labels:    map[string]string{GroupLabelKey: s.group},
+			},
+			Spec: spec,
+		}, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+	spec.AcquireTime = existing.Spec.AcquireTime
+	existing.Spec = spec
+	_, err = leases.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+// liveMembers returns the sorted holders of the Leases renewed within their duration.
+func liveMembers(leases []coordinationv1.Lease, now time.Time) []string {
+	var members []string
+	for _, l := range leases {
+		if l.Spec.HolderIdentity == nil || l.Spec.RenewTime == nil || l.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expiry := l.Spec.RenewTime.Add(time.Duration(*l.Spec.LeaseDurationSeconds) * time.Second)
+		if now.After(expiry) {
+			continue
+		}
+		members = append(members, *l.Spec.HolderIdentity)
+	}
+	sort.Strings(members)
+	return members
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+)
+
+func TestOwnerOf(t *testing.T) {
+	require.Equal(t, "", OwnerOf(nil, "ns/secret"))
+	require.Equal(t, "a", OwnerOf([]string{"a"}, "ns/secret"))
+
+	members := []string{"a", "b", "c"}
+	owners := map[string]string{}
+	counts := map[string]int{}
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("ns/secret-%d", i)
+		owners[key] = OwnerOf(members, key)
+		counts[owners[key]]++
+	}
+	for _, m := range members {
+		require.Greater(t, counts[m], 50, "member %s owns too few keys", m)
+	}
+
+	// only the keys of the leaving member move
+	for key, owner := range owners {
+		if owner != "c" {
+			require.Equal(t, owner, OwnerOf([]string{"a", "b"}, key), key)
+		}
+	}
+}
+
+func TestLiveMembers(t *testing.T) {
+	now := time.Now()
+	lease := func(holder string, renewed time.Duration) coordinationv1.Lease {
+		renewTime := metav1.NewMicroTime(now.Add(-renewed))
+		return coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       pointer.String(holder),
+			LeaseDurationSeconds: pointer.Int32(15),
+			RenewTime:            &renewTime,
+		}}
+	}
+	require.Equal(t, []string{"a", "c"}, liveMembers([]coordinationv1.Lease{
+		lease("c", 5*time.Second),
+		lease("b", time.Minute),
+		lease("a", 0),
+		{},
+	}, now))
+}
+
+func TestSharder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := fake.NewSimpleClientset()
+	a := NewSharder(client.CoordinationV1(), "kube-bind", "konnector", "a", 15*time.Second, time.Hour)
+	b := NewSharder(client.CoordinationV1(), "kube-bind", "konnector", "b", 15*time.Second, time.Hour)
+
+	changed := 0
+	a.AddHandler(func() { changed++ })
+
+	require.NoError(t, a.Start(ctx))
+	require.Equal(t, []string{"a"}, a.Members())
+	require.Equal(t, 1, changed)
+	require.True(t, a.Owns("ns/secret"))
+
+	require.NoError(t, b.Start(ctx))
+	require.NoError(t, a.sync(ctx))
+	require.Equal(t, []string{"a", "b"}, a.Members())
+	require.Equal(t, 2, changed)
+
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("ns/secret-%d", i)
+		require.NotEqual(t, a.Owns(key), b.Owns(key), "key %s must be owned by exactly one member", key)
+	}
+}
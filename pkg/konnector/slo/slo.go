@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package slo keeps the recent canary probes of bindings and computes how fast they
+// burn their error budget against a sync-latency objective.
+package slo
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// MaxWindow is the longest window probes are kept for.
+const MaxWindow = 24 * time.Hour
+
+// Probe is the outcome of one canary probe.
+type Probe struct {
+	Time    time.Time
+	Latency time.Duration
+	Success bool
+}
+
+// Tracker records the canary probes by binding name. It is safe for concurrent use.
+type Tracker struct {
+	lock     sync.Mutex
+	probes   map[string][]Probe
+	onRecord []func(name string)
+}
+
+// NewTracker returns an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{probes: map[string][]Probe{}}
+}
+
+// OnRecord registers f to be called after every recorded probe.
+func (t *Tracker) OnRecord(f func(name string)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.onRecord = append(t.onRecord, f)
+}
+
+// Record adds a probe of the given binding and forgets probes older than MaxWindow.
+func (t *Tracker) Record(name string, p Probe) {
+	t.lock.Lock()
+	probes := append(t.probes[name], p)
+	cutoff := p.Time.Add(-MaxWindow)
+	i := 0
+	for i < len(probes) && probes[i].Time.Before(cutoff) {
+		i++
+	}
+	t.probes[name] = probes[i:]
+	callbacks := t.onRecord
+	t.lock.Unlock()
+
+	for _, f := range callbacks {
+		f(name)
+	}
+}
+
+// Forget drops the probes of the given binding.
+func (t *Tracker) Forget(name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.probes, name)
+}
+
+// Probes returns the probes of the given binding since the given time.
+func (t *Tracker) Probes(name string, since time.Time) []Probe {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var ret []Probe
+	for _, p := range t.probes[name] {
+		if !p.Time.Before(since) {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// Budget is the error budget consumption over a window.
+type Budget struct {
+	// Probes is the number of probes in the window.
+	Probes int
+	// Missed is the number of probes that failed or exceeded the latency target.
+	Missed int
+	// BurnRate is the rate the error budget is consumed with. 1 means it is used
+	// up exactly at the end of the window. It is +Inf if the objective allows no
+	// misses at all, but there are some.
+	BurnRate float64
+}
+
+// Met returns true if the budget is not burnt faster than it is refilled.
+func (b Budget) Met() bool {
+	return b.BurnRate <= 1
+}
+
+// Evaluate computes the budget of the given probes against a latency target and an
+// objective, the fraction of probes that must meet the target, e.g. 0.99.
+func Evaluate(probes []Probe, target time.Duration, objective float64) Budget {
+	b := Budget{Probes: len(probes)}
+	for _, p := range probes {
+		if !p.Success || p.Latency > target {
+			b.Missed++
+		}
+	}
+	if b.Probes == 0 || b.Missed == 0 {
+		return b
+	}
+
+	allowed := 1 - objective
+	if allowed <= 0 {
+		b.BurnRate = math.Inf(1)
+		return b
+	}
+	b.BurnRate = float64(b.Missed) / float64(b.Probes) / allowed
+	return b
+}
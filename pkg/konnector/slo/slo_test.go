@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	ok := Probe{Latency: time.Second, Success: true}
+	slow := Probe{Latency: time.Minute, Success: true}
+	failed := Probe{Latency: time.Second}
+
+	tests := []struct {
+		name      string
+		probes    []Probe
+		objective float64
+		want      Budget
+	}{
+		{name: "no probes", objective: 0.99, want: Budget{}},
+		{name: "all good", probes: []Probe{ok, ok}, objective: 0.99, want: Budget{Probes: 2}},
+		{name: "within budget", probes: []Probe{ok, ok, ok, slow}, objective: 0.5, want: Budget{Probes: 4, Missed: 1, BurnRate: 0.5}},
+		{name: "burning", probes: []Probe{ok, failed, slow, ok}, objective: 0.9, want: Budget{Probes: 4, Missed: 2, BurnRate: 5}},
+		{name: "no budget", probes: []Probe{ok, failed}, objective: 1, want: Budget{Probes: 2, Missed: 1, BurnRate: math.Inf(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Evaluate(tt.probes, 10*time.Second, tt.objective)
+			require.Equal(t, tt.want.Probes, got.Probes)
+			require.Equal(t, tt.want.Missed, got.Missed)
+			require.InDelta(t, tt.want.BurnRate, got.BurnRate, 1e-9)
+			require.Equal(t, tt.want.BurnRate <= 1, got.Met())
+		})
+	}
+}
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker()
+	var recorded []string
+	tr.OnRecord(func(name string) { recorded = append(recorded, name) })
+
+	now := time.Now()
+	tr.Record("foo", Probe{Time: now.Add(-2 * MaxWindow)})
+	tr.Record("foo", Probe{Time: now.Add(-time.Hour)})
+	tr.Record("foo", Probe{Time: now})
+
+	require.Equal(t, []string{"foo", "foo", "foo"}, recorded)
+	require.Len(t, tr.Probes("foo", now.Add(-MaxWindow)), 2)
+	require.Len(t, tr.Probes("foo", now.Add(-time.Minute)), 1)
+
+	tr.Forget("foo")
+	require.Empty(t, tr.Probes("foo", now.Add(-MaxWindow)))
+}
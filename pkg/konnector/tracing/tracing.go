@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing instruments the sync of the konnector with OpenTelemetry
+// spans: per synced object a span of its APIServiceBinding, a child span of the
+// sync operation, and below client spans of the API calls to the service
+// provider and consumer clusters.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	componenttracing "k8s.io/component-base/tracing"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+)
+
+const instrumentationName = "github.com/kube-bind/kube-bind/pkg/konnector"
+
+// Attributes of the sync spans.
+const (
+	BindingKey  = attribute.Key("kube-bind.binding")
+	ResourceKey = attribute.Key("kube-bind.resource")
+	ObjectKey   = attribute.Key("kube-bind.object")
+)
+
+// Setup installs a global TracerProvider exporting the spans of the konnector
+// via OTLP gRPC to the given endpoint, sampling the given number of traces per
+// million. It returns a function flushing and stopping the exporter. An empty
+// endpoint disables tracing.
+func Setup(ctx context.Context, endpoint string, samplingRatePerMillion int32, identity, version string) (func(context.Context) error, error) {
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	tp, err := componenttracing.NewProvider(ctx,
+		&tracingapi.TracingConfiguration{Endpoint: &endpoint, SamplingRatePerMillion: &samplingRatePerMillion},
+		nil,
+		[]resource.Option{resource.WithAttributes(
+			semconv.ServiceNameKey.String("konnector"),
+			semconv.ServiceInstanceIDKey.String(identity),
+			semconv.ServiceVersionKey.String(version),
+		)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(componenttracing.Propagators())
+
+	return func(ctx context.Context) error {
+		if sdk, ok := tp.(*sdktrace.TracerProvider); ok {
+			return sdk.Shutdown(ctx)
+		}
+		return nil
+	}, nil
+}
+
+// StartSync starts a span for the APIServiceBinding and a child span for the
+// sync operation of the object with the given key. The returned function ends
+// both, recording the error if any. The API calls done with the returned
+// context become children of the object span.
+func StartSync(ctx context.Context, operation, binding string, gvr schema.GroupVersionResource, key string) (context.Context, func(error)) {
+	tracer := otel.Tracer(instrumentationName)
+	ctx, bindingSpan := tracer.Start(ctx, "APIServiceBinding", trace.WithAttributes(BindingKey.String(binding)))
+	ctx, span := tracer.Start(ctx, operation, trace.WithAttributes(
+		BindingKey.String(binding),
+		ResourceKey.String(gvr.GroupResource().String()),
+		ObjectKey.String(key),
+	))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			bindingSpan.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		bindingSpan.End()
+	}
+}
+
+// WrapConfig makes the clients of the config create client spans for requests
+// done within a span, e.g. of StartSync, and propagate the trace to the API
+// server. The requests of informers are not traced.
+func WrapConfig(config *rest.Config) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &roundTripper{
+			delegate: rt,
+			traced:   componenttracing.WrapperFor(otel.GetTracerProvider())(rt),
+		}
+	})
+}
+
+type roundTripper struct {
+	delegate, traced http.RoundTripper
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if trace.SpanContextFromContext(req.Context()).IsValid() {
+		return rt.traced.RoundTrip(req)
+	}
+	return rt.delegate.RoundTrip(req)
+}
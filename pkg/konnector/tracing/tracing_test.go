@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/oteltest"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func TestStartSync(t *testing.T) {
+	recorder := new(oteltest.SpanRecorder)
+	otel.SetTracerProvider(oteltest.NewTracerProvider(oteltest.WithSpanRecorder(recorder)))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NotEmpty(t, r.Header.Get("traceparent"), "trace is not propagated")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	config := &rest.Config{}
+	WrapConfig(config)
+	transport, err := rest.TransportFor(config)
+	require.NoError(t, err)
+
+	gvr := schema.GroupVersionResource{Group: "mangodb.com", Version: "v1", Resource: "mangodbs"}
+	ctx, end := StartSync(context.Background(), "Upsync", "mangodbs", gvr, "default/test")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close() // nolint:errcheck
+	end(errors.New("forbidden"))
+
+	spans := recorder.Completed()
+	require.Len(t, spans, 3)
+	call, sync, binding := spans[0], spans[1], spans[2]
+	require.Equal(t, "APIServiceBinding", binding.Name())
+	require.Equal(t, "mangodbs", binding.Attributes()[BindingKey].AsString())
+	require.Equal(t, "Upsync", sync.Name())
+	require.Equal(t, binding.SpanContext().SpanID(), sync.ParentSpanID())
+	require.Equal(t, "mangodbs.mangodb.com", sync.Attributes()[ResourceKey].AsString())
+	require.Equal(t, "default/test", sync.Attributes()[ObjectKey].AsString())
+	require.Equal(t, codes.Error, sync.StatusCode())
+	require.Equal(t, sync.SpanContext().SpanID(), call.ParentSpanID())
+
+	// requests outside of a sync, e.g. of informers, are not traced
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Empty(t, r.Header.Get("traceparent"))
+	})
+	resp, err = transport.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close() // nolint:errcheck
+	require.Len(t, recorder.Completed(), 3)
+}
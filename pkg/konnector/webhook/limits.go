@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+)
+
+// LimitsPath is the path the plan limits webhook is served under.
+const LimitsPath = "/validate-plan-limits"
+
+// Limits is a validating admission webhook rejecting the creation of bound objects
+// that exceed the plan limits of the service provider, as propagated to the
+// status of APIServiceBindings.
+type Limits struct {
+	bindingLister bindlisters.APIServiceBindingLister
+	countObjects  func(ctx context.Context, gvr schema.GroupVersionResource) (int64, error)
+}
+
+// NewLimits returns a plan limits webhook. countObjects returns the number of
+// objects of a resource in the consumer cluster.
+func NewLimits(bindingLister bindlisters.APIServiceBindingLister, countObjects func(ctx context.Context, gvr schema.GroupVersionResource) (int64, error)) *Limits {
+	return &Limits{
+		bindingLister: bindingLister,
+		countObjects:  countObjects,
+	}
+}
+
+func (l *Limits) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context())
+
+	var review admissionv1.AdmissionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode AdmissionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "AdmissionReview without request", http.StatusBadRequest)
+		return
+	}
+
+	response, err := l.admit(r.Context(), review.Request)
+	if err != nil {
+		logger.Error(err, "failed to check plan limits", "resource", review.Request.Resource)
+		response = &admissionv1.AdmissionResponse{
+			Result: &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusInternalServerError, Message: err.Error()},
+		}
+	}
+	response.UID = review.Request.UID
+	review.Request = nil
+	review.Response = response
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&review); err != nil {
+		logger.Error(err, "failed to encode AdmissionReview")
+	}
+}
+
+func (l *Limits) admit(ctx context.Context, req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	if req.Operation != admissionv1.Create || req.SubResource != "" {
+		return allowed, nil
+	}
+
+	gvr := schema.GroupVersionResource{Group: req.Resource.Group, Version: req.Resource.Version, Resource: req.Resource.Resource}
+	binding, err := l.bindingFor(gvr.GroupResource())
+	if err != nil {
+		return nil, err
+	}
+	if binding == nil || binding.Status.Limits == nil {
+		return allowed, nil
+	}
+	limits := binding.Status.Limits
+	crdName := kubebindhelpers.BoundCRDName(binding)
+
+	if limits.MaxObjectSize != nil {
+		if size := int64(len(req.Object.Raw)); size > limits.MaxObjectSize.Value() {
+			return denied("plan limit exceeded: %s objects may be at most %s in size by the plan with %s, but this one is %d bytes", crdName, limits.MaxObjectSize, providerName(binding), size), nil
+		}
+	}
+	if limits.MaxObjects != nil {
+		count, err := l.countObjects(ctx, gvr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", crdName, err)
+		}
+		if count >= *limits.MaxObjects {
+			return denied("plan limit exceeded: at most %d %s objects are allowed by the plan with %s", *limits.MaxObjects, crdName, providerName(binding)), nil
+		}
+	}
+
+	return allowed, nil
+}
+
+func (l *Limits) bindingFor(gr schema.GroupResource) (*kubebindv1alpha1.APIServiceBinding, error) {
+	bindings, err := l.bindingLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range bindings {
+		if kubebindhelpers.BoundCRDName(binding) == gr.String() {
+			return binding, nil
+		}
+	}
+	return nil, nil
+}
+
+func providerName(binding *kubebindv1alpha1.APIServiceBinding) string {
+	if binding.Status.ProviderPrettyName != "" {
+		return binding.Status.ProviderPrettyName
+	}
+	return "the service provider"
+}
+
+func denied(format string, args ...interface{}) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusForbidden,
+			Reason:  metav1.StatusReasonForbidden,
+			Message: fmt.Sprintf(format, args...),
+		},
+	}
+}
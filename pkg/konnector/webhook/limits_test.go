@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+)
+
+func TestLimitsAdmit(t *testing.T) {
+	size := resource.MustParse("100")
+	tests := []struct {
+		name        string
+		limits      *kubebindv1alpha1.PlanLimits
+		operation   admissionv1.Operation
+		resource    string
+		count       int64
+		object      string
+		wantAllowed bool
+	}{
+		{name: "no limits", resource: "foos", count: 10, object: "{}", wantAllowed: true},
+		{name: "below max objects", limits: &kubebindv1alpha1.PlanLimits{MaxObjects: pointer.Int64(3)}, resource: "foos", count: 2, object: "{}", wantAllowed: true},
+		{name: "max objects reached", limits: &kubebindv1alpha1.PlanLimits{MaxObjects: pointer.Int64(3)}, resource: "foos", count: 3, object: "{}"},
+		{name: "too large", limits: &kubebindv1alpha1.PlanLimits{MaxObjectSize: &size}, resource: "foos", object: `{"spec":"` + string(make([]byte, 100)) + `"}`},
+		{name: "update", limits: &kubebindv1alpha1.PlanLimits{MaxObjects: pointer.Int64(3)}, operation: admissionv1.Update, resource: "foos", count: 3, object: "{}", wantAllowed: true},
+		{name: "other resource", limits: &kubebindv1alpha1.PlanLimits{MaxObjects: pointer.Int64(3)}, resource: "bars", count: 3, object: "{}", wantAllowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			binding := &kubebindv1alpha1.APIServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com"}}
+			binding.Status.Limits = tt.limits
+			require.NoError(t, indexer.Add(binding))
+
+			l := NewLimits(bindlisters.NewAPIServiceBindingLister(indexer), func(ctx context.Context, gvr schema.GroupVersionResource) (int64, error) {
+				return tt.count, nil
+			})
+			operation := tt.operation
+			if operation == "" {
+				operation = admissionv1.Create
+			}
+			resp, err := l.admit(context.Background(), &admissionv1.AdmissionRequest{
+				Operation: operation,
+				Resource:  metav1.GroupVersionResource{Group: "example.com", Version: "v1", Resource: tt.resource},
+				Object:    runtime.RawExtension{Raw: []byte(tt.object)},
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantAllowed, resp.Allowed, "%v", resp.Result)
+		})
+	}
+}
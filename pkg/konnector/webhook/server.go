@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/metadata"
+	"k8s.io/klog/v2"
+)
+
+// ObjectCounter returns a function counting the objects of a resource across all
+// namespaces. It lists a single object and relies on the remaining item count of
+// the kube-apiserver, such that it is cheap also for large numbers of objects.
+func ObjectCounter(client metadata.Interface) func(ctx context.Context, gvr schema.GroupVersionResource) (int64, error) {
+	return func(ctx context.Context, gvr schema.GroupVersionResource) (int64, error) {
+		list, err := client.Resource(gvr).List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			return 0, err
+		}
+		count := int64(len(list.Items))
+		if list.RemainingItemCount != nil {
+			count += *list.RemainingItemCount
+		}
+		return count, nil
+	}
+}
+
+// Serve serves the webhooks via TLS on the given address until ctx is done. The
+// serving certificate is read from tls.crt and tls.key in certDir.
+func Serve(ctx context.Context, address, certDir string, limits *Limits) {
+	logger := klog.FromContext(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle(LimitsPath, limits)
+	server := &http.Server{Addr: address, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close() // nolint:errcheck
+	}()
+	go func() {
+		logger.Info("serving admission webhooks", "address", address)
+		if err := server.ListenAndServeTLS(filepath.Join(certDir, "tls.crt"), filepath.Join(certDir, "tls.key")); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(err, "failed to serve admission webhooks")
+		}
+	}()
+}
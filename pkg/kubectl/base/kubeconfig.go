@@ -19,14 +19,21 @@ package base
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/url"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 )
 
 func ParseRemoteKubeconfig(kubeconfig []byte) (host string, ns string, err error) {
@@ -78,12 +85,52 @@ func FindRemoteKubeconfig(ctx context.Context, kubeClient *kubernetes.Clientset,
 	return "", nil
 }
 
+// ValidateProxyURL checks that the proxy URL is a valid http, https or socks5 URL.
+func ValidateProxyURL(proxyURL string) error {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q, must be http, https or socks5", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("proxy URL %q has no host", proxyURL)
+	}
+	return nil
+}
+
+// SetProxyURL sets the proxy-url of the cluster of the current context of the
+// kubeconfig and of its fallbacks, such that clients like the konnector connect
+// to the service provider cluster through the proxy.
+func SetProxyURL(kubeconfig []byte, proxyURL string) ([]byte, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeContext, found := config.Contexts[config.CurrentContext]
+	if !found {
+		return nil, fmt.Errorf("current context %q of remote kubeconfig not found", config.CurrentContext)
+	}
+	cluster, found := config.Clusters[kubeContext.Cluster]
+	if !found {
+		return nil, fmt.Errorf("cluster %q in current context %q of remote kubeconfig not found", kubeContext.Cluster, config.CurrentContext)
+	}
+	cluster.ProxyURL = proxyURL
+	for i := 1; config.Clusters[helpers.FallbackClusterName(kubeContext.Cluster, i)] != nil; i++ {
+		config.Clusters[helpers.FallbackClusterName(kubeContext.Cluster, i)].ProxyURL = proxyURL
+	}
+	return clientcmd.Write(*config)
+}
+
 // EnsureKubeconfigSecret creates a secret which contains the service binding authenticated data such as
 // the binding session id and the kubeconfig of the service provider cluster. If it is pre-existing, the kubeconfig
 // is updated.
 //
 // It does special checking that only kubeconfigs with the same host and default namespace are updated.
-func EnsureKubeconfigSecret(ctx context.Context, kubeconfig, name string, client kubernetes.Interface) (*corev1.Secret, bool, error) {
+func EnsureKubeconfigSecret(ctx context.Context, kubeconfig, name, backendURL string, client kubernetes.Interface) (*corev1.Secret, bool, error) {
 	remoteHost, remoteNamespace, err := ParseRemoteKubeconfig([]byte(kubeconfig))
 	if err != nil {
 		return nil, false, err
@@ -100,6 +147,12 @@ func EnsureKubeconfigSecret(ctx context.Context, kubeconfig, name string, client
 			},
 		}
 
+		if backendURL != "" {
+			secret.Annotations = map[string]string{
+				kubebindv1alpha1.BackendURLAnnotationKey: backendURL,
+			}
+		}
+
 		secret, err := client.CoreV1().Secrets("kube-bind").Create(ctx, secret, v1.CreateOptions{})
 		if err != nil {
 			return nil, false, err
@@ -127,6 +180,12 @@ func EnsureKubeconfigSecret(ctx context.Context, kubeconfig, name string, client
 			return errors.NewAlreadyExists(corev1.Resource("secret"), secret.Name)
 		}
 		secret.Data["kubeconfig"] = []byte(kubeconfig)
+		if backendURL != "" {
+			if secret.Annotations == nil {
+				secret.Annotations = map[string]string{}
+			}
+			secret.Annotations[kubebindv1alpha1.BackendURLAnnotationKey] = backendURL
+		}
 		if _, err := client.CoreV1().Secrets("kube-bind").Update(ctx, secret, v1.UpdateOptions{}); err != nil {
 			return err
 		}
@@ -137,3 +196,66 @@ func EnsureKubeconfigSecret(ctx context.Context, kubeconfig, name string, client
 
 	return secret, false, nil
 }
+
+// RemoteConfigForBinding returns the rest config, host and namespace of the service provider
+// cluster as found in the kubeconfig secret referenced by the given APIServiceBinding.
+func RemoteConfigForBinding(ctx context.Context, client kubernetes.Interface, binding *kubebindv1alpha1.APIServiceBinding) (config *rest.Config, host string, ns string, err error) {
+	ref := &binding.Spec.KubeconfigSecretRef
+	secret, err := client.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, v1.GetOptions{})
+	if err != nil {
+		return nil, "", "", err
+	}
+	kubeconfig, found := secret.Data[ref.Key]
+	if !found {
+		return nil, "", "", fmt.Errorf("secret %s/%s does not contain key %q", ref.Namespace, ref.Name, ref.Key)
+	}
+	host, ns, err = ParseRemoteKubeconfig(kubeconfig)
+	if err != nil {
+		return nil, "", "", err
+	}
+	config, err = clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return config, host, ns, nil
+}
+
+// SecretBackendURL returns the URL of the service provider backend of a kubeconfig
+// secret, which is recorded by kubectl bind. If override is set, it must point to
+// the same backend, such that the service provider token is never sent elsewhere.
+// Without a recorded URL, override is trusted with a warning.
+func SecretBackendURL(secret *corev1.Secret, override string, errOut io.Writer) (string, error) {
+	recorded := secret.Annotations[kubebindv1alpha1.BackendURLAnnotationKey]
+	switch {
+	case recorded == "" && override == "":
+		return "", fmt.Errorf("secret %s/%s does not record the service provider backend, pass --url", secret.Namespace, secret.Name)
+	case recorded == "":
+		fmt.Fprintf(errOut, "⚠️  Secret %s/%s does not record the service provider backend, trusting --url %s\n", secret.Namespace, secret.Name, override) // nolint: errcheck
+		return override, nil
+	case override == "":
+		return recorded, nil
+	}
+
+	same, err := sameBackend(override, recorded)
+	if err != nil {
+		return "", err
+	}
+	if !same {
+		return "", fmt.Errorf("--url %s does not match the service provider backend %s recorded on secret %s/%s", override, recorded, secret.Namespace, secret.Name)
+	}
+	return override, nil
+}
+
+// sameBackend returns whether the URLs have the same scheme and host. The path
+// differs between the URL passed to kubectl bind and the one of the backend root.
+func sameBackend(a, b string) (bool, error) {
+	aURL, err := url.Parse(a)
+	if err != nil {
+		return false, err
+	}
+	bURL, err := url.Parse(b)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(aURL.Scheme, bURL.Scheme) && strings.EqualFold(aURL.Host, bURL.Host), nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestSetProxyURL(t *testing.T) {
+	kubeconfig, err := SetProxyURL([]byte(testKubeconfig), "socks5://proxy.internal:1080")
+	require.NoError(t, err)
+
+	config, err := clientcmd.Load(kubeconfig)
+	require.NoError(t, err)
+	require.Equal(t, "socks5://proxy.internal:1080", config.Clusters["dev"].ProxyURL)
+	require.Empty(t, config.Clusters["prod"].ProxyURL, "only the cluster of the current context is changed")
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	require.NoError(t, err)
+	require.NotNil(t, restConfig.Proxy)
+}
+
+func TestValidateProxyURL(t *testing.T) {
+	tests := []struct {
+		proxyURL string
+		wantErr  bool
+	}{
+		{proxyURL: "http://proxy:3128"},
+		{proxyURL: "https://proxy:3128"},
+		{proxyURL: "socks5://proxy:1080"},
+		{proxyURL: "ftp://proxy:21", wantErr: true},
+		{proxyURL: "proxy:3128", wantErr: true},
+		{proxyURL: "socks5://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.proxyURL, func(t *testing.T) {
+			err := ValidateProxyURL(tt.proxyURL)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSecretBackendURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+		recorded string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "recorded",
+			recorded: "https://mangodb.com/export",
+			want:     "https://mangodb.com/export",
+		},
+		{
+			name:     "matching override",
+			override: "https://MangoDB.com/",
+			recorded: "https://mangodb.com/export",
+			want:     "https://MangoDB.com/",
+		},
+		{
+			name:     "other host",
+			override: "https://evil.com/export",
+			recorded: "https://mangodb.com/export",
+			wantErr:  "does not match the service provider backend https://mangodb.com/export",
+		},
+		{
+			name:     "other port",
+			override: "https://mangodb.com:8443/export",
+			recorded: "https://mangodb.com/export",
+			wantErr:  "does not match",
+		},
+		{
+			name:     "other scheme",
+			override: "http://mangodb.com/export",
+			recorded: "https://mangodb.com/export",
+			wantErr:  "does not match",
+		},
+		{
+			name:     "not recorded",
+			override: "https://mangodb.com/export",
+			want:     "https://mangodb.com/export",
+		},
+		{
+			name:    "neither",
+			wantErr: "pass --url",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig-abcde"}}
+			if tt.recorded != "" {
+				secret.Annotations = map[string]string{kubebindv1alpha1.BackendURLAnnotationKey: tt.recorded}
+			}
+
+			got, err := SecretBackendURL(secret, tt.override, io.Discard)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
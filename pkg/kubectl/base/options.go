@@ -17,7 +17,11 @@ limitations under the License.
 package base
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/tools/clientcmd"
@@ -38,6 +42,8 @@ type Options struct {
 	// ClientConfig is the resolved cliendcmd.ClientConfig based on the client connection flags. This is only valid
 	// after calling Complete.
 	ClientConfig clientcmd.ClientConfig
+
+	overrideFlags *pflag.FlagSet
 }
 
 // NewOptions provides an instance of Options with default values.
@@ -67,6 +73,7 @@ func (o *Options) BindFlags(cmd *cobra.Command) {
 	kubectlConfigOverrideFlags.Timeout.LongName = ""
 
 	clientcmd.BindOverrideFlags(o.KubectlOverrides, cmd.PersistentFlags(), kubectlConfigOverrideFlags)
+	o.overrideFlags = cmd.PersistentFlags()
 }
 
 // Complete initializes ClientConfig based on Kubeconfig and KubectlOverrides.
@@ -84,6 +91,57 @@ func (o *Options) Complete() error {
 	return nil
 }
 
+// UseContext switches ClientConfig to the given kubeconfig context.
+func (o *Options) UseContext(name string) error {
+	o.KubectlOverrides.CurrentContext = name
+	return o.Complete()
+}
+
+// ContextNames returns the kubeconfig contexts to operate on. With all set, these
+// are all contexts of the kubeconfig sorted by name. Otherwise, the given names
+// must exist in the kubeconfig. This is only valid after calling Complete.
+func (o *Options) ContextNames(names []string, all bool) ([]string, error) {
+	config, err := o.ClientConfig.RawConfig()
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		names = make([]string, 0, len(config.Contexts))
+		for name := range config.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no contexts found in kubeconfig")
+		}
+		return names, nil
+	}
+	for _, name := range names {
+		if _, found := config.Contexts[name]; !found {
+			return nil, fmt.Errorf("context %q not found in kubeconfig", name)
+		}
+	}
+	return names, nil
+}
+
+// OverrideArgs returns the kubeconfig override flags, e.g. --context, in a form
+// to pass on to downstream commands such that they talk to the same cluster.
+// The context is taken from KubectlOverrides, i.e. it reflects UseContext.
+func (o *Options) OverrideArgs() []string {
+	var args []string
+	if o.overrideFlags != nil {
+		o.overrideFlags.VisitAll(func(flag *pflag.Flag) {
+			if flag.Changed && flag.Name != "context" {
+				args = append(args, "--"+flag.Name+"="+flag.Value.String())
+			}
+		})
+	}
+	if o.KubectlOverrides.CurrentContext != "" {
+		args = append(args, "--context="+o.KubectlOverrides.CurrentContext)
+	}
+	return args
+}
+
 // Validate validates the configured options.
 func (o *Options) Validate() error {
 	return nil
@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package base
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+- name: prod
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: admin
+- name: dev
+  context:
+    cluster: dev
+    user: admin
+users:
+- name: admin
+  user:
+    token: secret
+`
+
+func TestContexts(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(testKubeconfig), 0600))
+
+	tests := []struct {
+		name    string
+		names   []string
+		all     bool
+		want    []string
+		wantErr bool
+	}{
+		{name: "all", all: true, want: []string{"dev", "prod"}},
+		{name: "selected", names: []string{"prod"}, want: []string{"prod"}},
+		{name: "unknown", names: []string{"prod", "staging"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOptions(genericclioptions.IOStreams{})
+			o.Kubeconfig = kubeconfig
+			require.NoError(t, o.Complete())
+
+			got, err := o.ContextNames(tt.names, tt.all)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOverrideArgs(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(testKubeconfig), 0600))
+
+	cmd := &cobra.Command{}
+	o := NewOptions(genericclioptions.IOStreams{})
+	o.BindFlags(cmd)
+	require.NoError(t, cmd.PersistentFlags().Parse([]string{"--context=dev", "--user=admin"}))
+	o.Kubeconfig = kubeconfig
+	require.NoError(t, o.Complete())
+	require.Equal(t, []string{"--user=admin", "--context=dev"}, o.OverrideArgs())
+
+	require.NoError(t, o.UseContext("prod"))
+	require.Equal(t, []string{"--user=admin", "--context=prod"}, o.OverrideArgs())
+	config, err := o.ClientConfig.ClientConfig()
+	require.NoError(t, err)
+	require.Equal(t, "https://prod.example.com", config.Host)
+}
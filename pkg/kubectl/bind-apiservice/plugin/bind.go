@@ -30,6 +30,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -40,6 +41,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/features"
 	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
 )
 
@@ -58,12 +60,67 @@ type BindAPIServiceOptions struct {
 	remoteNamespace           string
 	file                      string
 
+	// FromBundle is a signed binding bundle to bind from without contacting the
+	// service provider.
+	FromBundle      string
+	BundlePublicKey string
+
 	// skipKonnector skips the deployment of the konnector.
 	SkipKonnector          bool
 	KonnectorImageOverride string
 	DowngradeKonnector     bool
 	NoBanner               bool
 
+	// RequiredCapabilities are APIServiceExport capabilities the consumer relies on.
+	RequiredCapabilities []string
+
+	// AdoptExisting makes the konnector upsync objects that existed before the binding.
+	AdoptExisting bool
+
+	// StrictFieldValidation makes the konnector reject objects with unknown fields
+	// instead of pruning them.
+	StrictFieldValidation bool
+
+	// ReportPrunedFields makes the konnector record the unknown fields it prunes
+	// on the objects.
+	ReportPrunedFields bool
+
+	// ProviderProxyURL is the proxy to connect to the service provider cluster
+	// through. It is stored as proxy-url in the kubeconfig Secret.
+	ProviderProxyURL string
+
+	// AllowedRegions restricts binding to APIServiceExports labelled with one
+	// of these regions. Empty means any region.
+	AllowedRegions []string
+
+	// AcceptClaims are the cluster-scoped claims of the service provider the
+	// consumer consents to, e.g. storageclasses.storage.k8s.io.
+	AcceptClaims []string
+
+	// AcceptServiceAccountTokens are the consumer ServiceAccounts, as namespace/name,
+	// the service provider may get projected tokens of.
+	AcceptServiceAccountTokens []string
+
+	// AcceptEventRelay consents to the event relay offered by the service provider.
+	AcceptEventRelay bool
+
+	// AllowActions are the actions the service provider may request on consumer
+	// objects in any namespace, e.g. Restart.
+	AllowActions []string
+
+	// GroupAliases are <group>=<alias> pairs to bind the resources of a group under
+	// another group, e.g. to bind the same resource of two service providers.
+	GroupAliases []string
+
+	// acceptedClaims are the claims per APIServiceBinding name that were accepted.
+	acceptedClaims map[string][]kubebindv1alpha1.ClusterScopedClaim
+	// acceptedTokenClaims are the service account token claims per APIServiceExport name that were accepted.
+	acceptedTokenClaims map[string][]kubebindv1alpha1.ServiceAccountTokenClaim
+	// acceptedEventRelays are the event relays per APIServiceExport name that were accepted.
+	acceptedEventRelays map[string]*kubebindv1alpha1.EventRelay
+	// groupAliasSuffixes are the group alias suffixes offered by the service provider per APIServiceExport name.
+	groupAliasSuffixes map[string]string
+
 	url string
 }
 
@@ -86,11 +143,24 @@ func (b *BindAPIServiceOptions) AddCmdFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&b.remoteKubeconfigNamespace, "remote-kubeconfig-namespace", b.remoteKubeconfigNamespace, "The namespace of the remote kubeconfig secret to read from")
 	cmd.Flags().StringVar(&b.remoteKubeconfigName, "remote-kubeconfig-name", b.remoteKubeconfigNamespace, "The name of the remote kubeconfig secret to read from")
 	cmd.Flags().StringVarP(&b.file, "file", "f", b.file, "A file with an APIServiceExportRequest manifest. Use - to read from stdin")
+	cmd.Flags().StringVar(&b.ProviderProxyURL, "provider-proxy-url", b.ProviderProxyURL, "An http, https or socks5 proxy URL, e.g. socks5://proxy.internal:1080, to reach the service provider cluster through. It is stored in the kubeconfig Secret for the konnector. If unset, HTTPS_PROXY and NO_PROXY are honored.")
+	cmd.Flags().StringVar(&b.FromBundle, "from-bundle", b.FromBundle, "A signed binding bundle file generated by the service provider with \"kubectl bind bundle\". Binds without network access to the service provider backend.")
+	cmd.Flags().StringVar(&b.BundlePublicKey, "bundle-public-key", b.BundlePublicKey, "A PEM encoded ed25519 public key file to verify the binding bundle signature with")
 	cmd.Flags().StringVar(&b.remoteNamespace, "remote-namespace", b.remoteNamespace, "The namespace in the remote cluster where the konnector is deployed")
 	cmd.Flags().BoolVar(&b.SkipKonnector, "skip-konnector", b.SkipKonnector, "Skip the deployment of the konnector")
 	cmd.Flags().BoolVar(&b.DowngradeKonnector, "downgrade-konnector", b.DowngradeKonnector, "Downgrade the konnector to the version of the kubectl-bind-apiservice binary")
 	cmd.Flags().StringVar(&b.KonnectorImageOverride, "konnector-image", b.KonnectorImageOverride, "The konnector image to use")
 	cmd.Flags().MarkHidden("konnector-image") // nolint:errcheck
+	cmd.Flags().StringSliceVar(&b.RequiredCapabilities, "require-capabilities", b.RequiredCapabilities, "APIServiceExport capabilities the consumer relies on. A warning is shown if the service provider does not advertise them.")
+	cmd.Flags().BoolVar(&b.AdoptExisting, "adopt-existing", b.AdoptExisting, "Adopt objects and CustomResourceDefinitions that already exist in the consumer cluster, e.g. from a previous local operator, and sync them to the service provider.")
+	cmd.Flags().BoolVar(&b.StrictFieldValidation, "strict-field-validation", b.StrictFieldValidation, "Reject objects with fields unknown to the schema of the other cluster, e.g. during version skew, with a condition on the object instead of dropping the fields silently.")
+	cmd.Flags().BoolVar(&b.ReportPrunedFields, "report-pruned-fields", b.ReportPrunedFields, "Drop fields unknown to the schema of the other cluster like by default, but list them in the kube-bind.io/pruned-upstream-fields and kube-bind.io/pruned-downstream-fields annotations of the object.")
+	cmd.Flags().StringSliceVar(&b.AcceptClaims, "accept-claims", b.AcceptClaims, "Cluster-scoped consumer resources the service provider may read, as resource.group, e.g. storageclasses.storage.k8s.io or nodes. Claims that are not accepted are not synced.")
+	cmd.Flags().StringSliceVar(&b.AcceptServiceAccountTokens, "accept-service-account-tokens", b.AcceptServiceAccountTokens, "Consumer ServiceAccounts, as namespace/name, the service provider may get projected, audience-bound tokens of, e.g. to call back into this cluster. Tokens of claims that are not accepted are not issued.")
+	cmd.Flags().BoolVar(&b.AcceptEventRelay, "accept-event-relay", b.AcceptEventRelay, "Accept the event relay offered by the service provider, i.e. its Events about bound objects are copied into the consumer namespaces, and if offered, consumer Events about them are copied to the service provider.")
+	cmd.Flags().StringSliceVar(&b.AllowActions, "allow-actions", b.AllowActions, "Actions the service provider may request on consumer objects in any namespace with APIServiceActions, e.g. Restart. Other actions are denied.")
+	cmd.Flags().StringSliceVar(&b.GroupAliases, "group-alias", b.GroupAliases, "Bind the resources of an API group under another group, as <group>=<alias>, e.g. databases.example.com=databases.acme.example. The konnector translates between the groups, such that the same resource of different service providers can be bound side by side.")
+	cmd.Flags().StringSliceVar(&b.AllowedRegions, "allowed-regions", b.AllowedRegions, "Regions the service provider's APIServiceExports must be labelled with (kube-bind.io/region). Binding fails for exports outside these regions.")
 	cmd.Flags().BoolVar(&b.NoBanner, "no-banner", b.NoBanner, "Do not show the red banner")
 	cmd.Flags().MarkHidden("no-banner") // nolint:errcheck
 }
@@ -109,6 +179,33 @@ func (b *BindAPIServiceOptions) Complete(args []string) error {
 
 // Validate validates the BindAPIServiceOptions are complete and usable.
 func (b *BindAPIServiceOptions) Validate() error {
+	if err := features.ValidateCapabilities(features.DefaultFeatureGate, b.RequiredCapabilities); err != nil {
+		return err
+	}
+	if b.StrictFieldValidation && b.ReportPrunedFields {
+		return errors.New("strict-field-validation and report-pruned-fields are mutually exclusive")
+	}
+	if b.ProviderProxyURL != "" {
+		if err := base.ValidateProxyURL(b.ProviderProxyURL); err != nil {
+			return fmt.Errorf("invalid provider-proxy-url: %w", err)
+		}
+	}
+	if b.FromBundle != "" {
+		if b.url != "" || b.file != "" {
+			return errors.New("from-bundle is mutually exclusive with url and file")
+		}
+		if b.remoteKubeconfigFile != "" || b.remoteKubeconfigNamespace != "" || b.remoteKubeconfigName != "" {
+			return errors.New("from-bundle is mutually exclusive with the remote-kubeconfig flags")
+		}
+		if b.BundlePublicKey == "" {
+			return errors.New("bundle-public-key is required with from-bundle")
+		}
+		return b.Options.Validate()
+	}
+	if b.BundlePublicKey != "" {
+		return errors.New("bundle-public-key requires from-bundle")
+	}
+
 	if b.url == "" && b.file == "" {
 		return errors.New("url or file is required")
 	}
@@ -121,6 +218,21 @@ func (b *BindAPIServiceOptions) Validate() error {
 		}
 	}
 
+	for _, action := range b.AllowActions {
+		if kubebindv1alpha1.ActionType(action) != kubebindv1alpha1.ActionTypeRestart {
+			return fmt.Errorf("unknown action %q, must be %s", action, kubebindv1alpha1.ActionTypeRestart)
+		}
+	}
+	for _, pair := range b.GroupAliases {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid group alias %q, must be <group>=<alias>", pair)
+		}
+		if errs := validation.IsDNS1123Subdomain(parts[1]); len(errs) > 0 || !strings.Contains(parts[1], ".") {
+			return fmt.Errorf("invalid group alias %q, must be a DNS subdomain with at least one dot", parts[1])
+		}
+	}
+
 	if allowed := sets.NewString(b.Print.AllowedFormats()...); *b.Print.OutputFormat != "" && !allowed.Has(*b.Print.OutputFormat) {
 		return fmt.Errorf("invalid output format %q (allowed: %s)", *b.Print.OutputFormat, strings.Join(allowed.List(), ", "))
 	}
@@ -150,6 +262,10 @@ func (b *BindAPIServiceOptions) Run(ctx context.Context) error {
 		return err
 	}
 
+	if b.FromBundle != "" {
+		return b.runFromBundle(ctx, config)
+	}
+
 	remoteKubeconfig, remoteNamespace, remoteConfig, err := b.getRemoteKubeconfig(ctx, config)
 	if err != nil {
 		return err
@@ -166,6 +282,9 @@ func (b *BindAPIServiceOptions) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if err := b.warnAboutCapabilities(ctx, remoteConfig, remoteNamespace, result); err != nil {
+		return err
+	}
 	if err := b.deployKonnector(ctx, config); err != nil {
 		return err
 	}
@@ -218,6 +337,13 @@ func (b *BindAPIServiceOptions) getRemoteKubeconfig(ctx context.Context, config
 	if c.Namespace == "" {
 		return "", "", nil, fmt.Errorf("remote namespace is required, either as flag or in the passed remote kubeconfig")
 	}
+	if b.ProviderProxyURL != "" {
+		cluster, found := remoteKubeConfig.Clusters[c.Cluster]
+		if !found {
+			return "", "", nil, fmt.Errorf("cluster %q of current context %q not found in remote kubeconfig", c.Cluster, remoteKubeConfig.CurrentContext)
+		}
+		cluster.ProxyURL = b.ProviderProxyURL
+	}
 	remoteConfig, err = clientcmd.NewDefaultClientConfig(*remoteKubeConfig, &clientcmd.ConfigOverrides{}).ClientConfig()
 	if err != nil {
 		return "", "", nil, err
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/client-go/rest"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/bundle"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// runFromBundle binds the APIServiceExports of a signed binding bundle. The service
+// provider is not contacted, only the konnector will once it reaches it.
+func (b *BindAPIServiceOptions) runFromBundle(ctx context.Context, config *rest.Config) error {
+	keyBytes, err := os.ReadFile(b.BundlePublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle public key: %w", err)
+	}
+	key, err := bundle.ParsePublicKey(keyBytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse bundle public key: %w", err)
+	}
+	f, err := os.Open(b.FromBundle)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // nolint: errcheck
+	bndl, err := bundle.Read(f, key)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(b.Options.ErrOut, "🔏 Verified binding bundle %s with %d APIServiceExport(s).\n", b.FromBundle, len(bndl.Exports)) // nolint: errcheck
+
+	remoteHost, remoteNamespace, err := base.ParseRemoteKubeconfig(bndl.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("invalid kubeconfig in binding bundle: %w", err)
+	}
+	kubeconfig := bndl.Kubeconfig
+	if b.ProviderProxyURL != "" {
+		if kubeconfig, err = base.SetProxyURL(kubeconfig, b.ProviderProxyURL); err != nil {
+			return fmt.Errorf("invalid kubeconfig in binding bundle: %w", err)
+		}
+	}
+
+	if err := b.deployKonnector(ctx, config); err != nil {
+		return err
+	}
+	secretName, err := b.createKubeconfigSecret(ctx, config, remoteHost, remoteNamespace, string(kubeconfig))
+	if err != nil {
+		return err
+	}
+
+	request := &kubebindv1alpha1.APIServiceExportRequest{}
+	for _, export := range bndl.Exports {
+		request.Spec.Resources = append(request.Spec.Resources, kubebindv1alpha1.APIServiceExportRequestResource{
+			GroupResource: kubebindv1alpha1.GroupResource{
+				Group:    export.Spec.Group,
+				Resource: export.Spec.Names.Plural,
+			},
+		})
+	}
+	bindings, err := b.createAPIServiceBindings(ctx, config, request, secretName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(b.Options.ErrOut) // nolint: errcheck
+	return b.printTable(ctx, config, bindings)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+// reviewClaims shows the cluster-scoped claims of the export and records those
+// the consumer accepted with --accept-claims for the APIServiceBinding.
+func (b *BindAPIServiceOptions) reviewClaims(export *kubebindv1alpha1.APIServiceExport) {
+	if len(export.Spec.ClusterScopedClaims) == 0 {
+		return
+	}
+
+	accepted := parseClaims(b.AcceptClaims)
+	var notAccepted []string
+	for _, claim := range export.Spec.ClusterScopedClaims {
+		claimable, ok := helpers.Claimable(claim)
+		switch {
+		case !ok:
+			fmt.Fprintf(b.Options.IOStreams.ErrOut, "⚠️  APIServiceExport %s claims %s, which cannot be claimed. It is ignored.\n", export.Name, claimName(claim)) // nolint: errcheck
+			continue
+		case claimable.MetadataOnly:
+			fmt.Fprintf(b.Options.IOStreams.ErrOut, "ℹ️  APIServiceExport %s claims read access to the names and labels of %s.\n", export.Name, claimName(claim)) // nolint: errcheck
+		default:
+			fmt.Fprintf(b.Options.IOStreams.ErrOut, "ℹ️  APIServiceExport %s claims read access to %s.\n", export.Name, claimName(claim)) // nolint: errcheck
+		}
+
+		if !containsClaim(accepted, claim) {
+			notAccepted = append(notAccepted, claimName(claim))
+			continue
+		}
+		if b.acceptedClaims == nil {
+			b.acceptedClaims = map[string][]kubebindv1alpha1.ClusterScopedClaim{}
+		}
+		b.acceptedClaims[export.Name] = append(b.acceptedClaims[export.Name], claim)
+	}
+
+	if len(notAccepted) > 0 {
+		fmt.Fprintf(b.Options.IOStreams.ErrOut, "⚠️  Claims %s are not accepted and will not be synced. Pass --accept-claims=%s to consent.\n", strings.Join(notAccepted, ", "), strings.Join(notAccepted, ",")) // nolint: errcheck
+	}
+}
+
+// reviewServiceAccountTokenClaims shows the service account token claims of the
+// export and records those the consumer accepted with --accept-service-account-tokens
+// for the APIServiceBinding.
+func (b *BindAPIServiceOptions) reviewServiceAccountTokenClaims(export *kubebindv1alpha1.APIServiceExport) {
+	if len(export.Spec.ServiceAccountTokenClaims) == 0 {
+		return
+	}
+
+	accepted := sets.NewString(b.AcceptServiceAccountTokens...)
+	notAccepted := sets.NewString()
+	for _, claim := range export.Spec.ServiceAccountTokenClaims {
+		sa := claim.Namespace + "/" + claim.Name
+		fmt.Fprintf(b.Options.IOStreams.ErrOut, "ℹ️  APIServiceExport %s claims tokens of ServiceAccount %s for audience %q.\n", export.Name, sa, claim.Audience) // nolint: errcheck
+
+		if !accepted.Has(sa) {
+			notAccepted.Insert(sa)
+			continue
+		}
+		if b.acceptedTokenClaims == nil {
+			b.acceptedTokenClaims = map[string][]kubebindv1alpha1.ServiceAccountTokenClaim{}
+		}
+		b.acceptedTokenClaims[export.Name] = append(b.acceptedTokenClaims[export.Name], claim)
+	}
+
+	if notAccepted.Len() > 0 {
+		fmt.Fprintf(b.Options.IOStreams.ErrOut, "⚠️  Tokens of ServiceAccounts %s are not accepted and will not be issued. Pass --accept-service-account-tokens=%s to consent.\n", strings.Join(notAccepted.List(), ", "), strings.Join(notAccepted.List(), ",")) // nolint: errcheck
+	}
+}
+
+// reviewEventRelay shows the event relay offered by the export and records it for
+// the APIServiceBinding if the consumer accepted it with --accept-event-relay.
+func (b *BindAPIServiceOptions) reviewEventRelay(export *kubebindv1alpha1.APIServiceExport) {
+	relay := export.Spec.EventRelay
+	if relay == nil {
+		return
+	}
+
+	if len(relay.Reasons) > 0 {
+		fmt.Fprintf(b.Options.IOStreams.ErrOut, "ℹ️  APIServiceExport %s offers to relay its Events with reasons %s into your namespaces.\n", export.Name, strings.Join(relay.Reasons, ", ")) // nolint: errcheck
+	}
+	if relay.Upsync {
+		fmt.Fprintf(b.Options.IOStreams.ErrOut, "ℹ️  APIServiceExport %s asks to receive your Events about its objects.\n", export.Name) // nolint: errcheck
+	}
+	if !b.AcceptEventRelay {
+		fmt.Fprintf(b.Options.IOStreams.ErrOut, "⚠️  The event relay is not accepted and Events are not relayed. Pass --accept-event-relay to consent.\n") // nolint: errcheck
+		return
+	}
+	if b.acceptedEventRelays == nil {
+		b.acceptedEventRelays = map[string]*kubebindv1alpha1.EventRelay{}
+	}
+	b.acceptedEventRelays[export.Name] = relay.DeepCopy()
+}
+
+// parseClaims parses claims in resource.group notation. Core resources have no group.
+func parseClaims(ss []string) []kubebindv1alpha1.ClusterScopedClaim {
+	claims := make([]kubebindv1alpha1.ClusterScopedClaim, 0, len(ss))
+	for _, s := range ss {
+		resource, group, _ := strings.Cut(strings.TrimSpace(s), ".")
+		claims = append(claims, kubebindv1alpha1.ClusterScopedClaim{Group: group, Resource: resource})
+	}
+	return claims
+}
+
+func claimName(claim kubebindv1alpha1.ClusterScopedClaim) string {
+	if claim.Group == "" {
+		return claim.Resource
+	}
+	return claim.Resource + "." + claim.Group
+}
+
+func containsClaim(claims []kubebindv1alpha1.ClusterScopedClaim, claim kubebindv1alpha1.ClusterScopedClaim) bool {
+	for _, c := range claims {
+		if c == claim {
+			return true
+		}
+	}
+	return false
+}
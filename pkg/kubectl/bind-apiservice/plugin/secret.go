@@ -64,7 +64,7 @@ func (b *BindAPIServiceOptions) createKubeconfigSecret(ctx context.Context, conf
 }
 
 func (b *BindAPIServiceOptions) ensureKubeconfigSecretWithLogging(ctx context.Context, kubeconfig, name string, client kubeclient.Interface) (string, error) {
-	secret, created, err := base.EnsureKubeconfigSecret(ctx, kubeconfig, name, client)
+	secret, created, err := base.EnsureKubeconfigSecret(ctx, kubeconfig, name, "", client)
 	if err != nil {
 		return "", err
 	}
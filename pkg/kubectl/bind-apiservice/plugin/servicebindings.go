@@ -19,9 +19,11 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -44,9 +46,16 @@ func (b *BindAPIServiceOptions) createAPIServiceBindings(ctx context.Context, co
 		return nil, err
 	}
 
+	aliases := parseGroupAliases(b.GroupAliases)
+
 	var bindings []*kubebindv1alpha1.APIServiceBinding
 	for _, resource := range request.Spec.Resources {
-		name := resource.Resource + "." + resource.Group
+		exportName := resource.Resource + "." + resource.Group
+		name, groupAlias := exportName, aliases[resource.Group]
+		if groupAlias != "" {
+			// name the binding like its CRD to not conflict with bindings of the same export of other service providers
+			name = resource.Resource + "." + groupAlias
+		}
 		existing, err := bindClient.KubeBindV1alpha1().APIServiceBindings().Get(ctx, name, metav1.GetOptions{})
 		if err != nil && !apierrors.IsNotFound(err) {
 			return nil, err
@@ -55,20 +64,58 @@ func (b *BindAPIServiceOptions) createAPIServiceBindings(ctx context.Context, co
 				return nil, fmt.Errorf("found existing APIServiceBinding %s not from this service provider", name)
 			}
 			fmt.Fprintf(b.Options.IOStreams.ErrOut, "✅ Updating existing APIServiceBinding %s.\n", existing.Name) // nolint: errcheck
+			adopt := b.AdoptExisting && existing.Spec.Adoption != kubebindv1alpha1.AdoptionPolicyAdopt
+			validation := b.fieldValidationPolicy()
+			validate := validation != "" && existing.Spec.FieldValidation != validation
+			accept := len(b.AcceptClaims) > 0 && !equality.Semantic.DeepEqual(existing.Spec.AcceptedClaims, b.acceptedClaims[exportName])
+			acceptTokens := len(b.AcceptServiceAccountTokens) > 0 && !equality.Semantic.DeepEqual(existing.Spec.AcceptedServiceAccountTokenClaims, b.acceptedTokenClaims[exportName])
+			acceptRelay := b.AcceptEventRelay && !equality.Semantic.DeepEqual(existing.Spec.AcceptedEventRelay, b.acceptedEventRelays[exportName])
+			allow := len(b.AllowActions) > 0 && !equality.Semantic.DeepEqual(existing.Spec.AllowedActions, b.allowedActions())
+			if adopt || validate || accept || acceptTokens || acceptRelay || allow {
+				existing = existing.DeepCopy()
+				if adopt {
+					existing.Spec.Adoption = kubebindv1alpha1.AdoptionPolicyAdopt
+				}
+				if validate {
+					existing.Spec.FieldValidation = validation
+				}
+				if accept {
+					existing.Spec.AcceptedClaims = b.acceptedClaims[exportName]
+				}
+				if acceptTokens {
+					existing.Spec.AcceptedServiceAccountTokenClaims = b.acceptedTokenClaims[exportName]
+				}
+				if acceptRelay {
+					existing.Spec.AcceptedEventRelay = b.acceptedEventRelays[exportName]
+				}
+				if allow {
+					existing.Spec.AllowedActions = b.allowedActions()
+				}
+				if existing, err = bindClient.KubeBindV1alpha1().APIServiceBindings().Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+					return nil, err
+				}
+			}
 			bindings = append(bindings, existing)
 
 			// checking CRD to match the binding
-			crd, err := apiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, resource.Resource+"."+resource.Group, metav1.GetOptions{})
+			crd, err := apiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, helpers.BoundCRDName(existing), metav1.GetOptions{})
 			if err != nil && !apierrors.IsNotFound(err) {
 				return nil, err
-			} else if err == nil {
+			} else if err == nil && !b.AdoptExisting {
 				if !helpers.IsOwnedByBinding(existing.Name, existing.UID, crd.OwnerReferences) {
-					return nil, fmt.Errorf("CustomResourceDefinition %s exists, but is not owned by kube-bind", crd.Name)
+					return nil, fmt.Errorf("CustomResourceDefinition %s exists, but is not owned by kube-bind. Use --adopt-existing to take it over", crd.Name)
 				}
 			}
 			continue
 		}
 
+		var export string
+		if groupAlias != "" {
+			export = exportName
+		} else if groupAlias, err = b.groupAlias(ctx, apiextensionsClient, exportName, resource.Group); err != nil {
+			return nil, err
+		}
+
 		// create new APIServiceBinding.
 		first := true
 		if err := wait.PollInfinite(1*time.Second, func() (bool, error) {
@@ -78,8 +125,9 @@ func (b *BindAPIServiceOptions) createAPIServiceBindings(ctx context.Context, co
 			}
 			created, err := bindClient.KubeBindV1alpha1().APIServiceBindings().Create(ctx, &kubebindv1alpha1.APIServiceBinding{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      resource.Resource + "." + resource.Group,
-					Namespace: "kube-bind",
+					Name:        name,
+					Namespace:   "kube-bind",
+					Annotations: b.bindingAnnotations(),
 				},
 				Spec: kubebindv1alpha1.APIServiceBindingSpec{
 					KubeconfigSecretRef: kubebindv1alpha1.ClusterSecretKeyRef{
@@ -89,6 +137,14 @@ func (b *BindAPIServiceOptions) createAPIServiceBindings(ctx context.Context, co
 						},
 						Namespace: "kube-bind",
 					},
+					Adoption:                          b.adoptionPolicy(),
+					FieldValidation:                   b.fieldValidationPolicy(),
+					AcceptedClaims:                    b.acceptedClaims[exportName],
+					AcceptedServiceAccountTokenClaims: b.acceptedTokenClaims[exportName],
+					AcceptedEventRelay:                b.acceptedEventRelays[exportName],
+					AllowedActions:                    b.allowedActions(),
+					GroupAlias:                        groupAlias,
+					Export:                            export,
 				},
 			}, metav1.CreateOptions{})
 			if err != nil {
@@ -104,7 +160,7 @@ func (b *BindAPIServiceOptions) createAPIServiceBindings(ctx context.Context, co
 			)
 			_, _ = bindClient.KubeBindV1alpha1().APIServiceBindings().UpdateStatus(ctx, created, metav1.UpdateOptions{}) // nolint:errcheck
 
-			fmt.Fprintf(b.Options.IOStreams.ErrOut, "✅ Created APIServiceBinding %s.%s\n", resource.Resource, resource.Group) // nolint: errcheck
+			fmt.Fprintf(b.Options.IOStreams.ErrOut, "✅ Created APIServiceBinding %s\n", name) // nolint: errcheck
 			bindings = append(bindings, created)
 			return true, nil
 		}); err != nil {
@@ -115,3 +171,73 @@ func (b *BindAPIServiceOptions) createAPIServiceBindings(ctx context.Context, co
 
 	return bindings, nil
 }
+
+// parseGroupAliases parses <group>=<alias> pairs. They are validated before.
+func parseGroupAliases(pairs []string) map[string]string {
+	aliases := map[string]string{}
+	for _, pair := range pairs {
+		if parts := strings.SplitN(pair, "=", 2); len(parts) == 2 {
+			aliases[parts[0]] = parts[1]
+		}
+	}
+	return aliases
+}
+
+// groupAlias returns the group to bind the resource under if an unrelated CRD of the
+// same name exists and the service provider offers a group alias suffix.
+func (b *BindAPIServiceOptions) groupAlias(ctx context.Context, client apiextensionsclientset.Interface, name, group string) (string, error) {
+	if b.AdoptExisting {
+		return "", nil
+	}
+	crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if helpers.IsOwnedByBinding(name, "", crd.OwnerReferences) {
+		return "", nil
+	}
+
+	suffix := b.groupAliasSuffixes[name]
+	if suffix == "" {
+		return "", nil // the konnector will report the conflict
+	}
+	alias := group + "." + suffix
+	fmt.Fprintf(b.Options.IOStreams.ErrOut, "ℹ️  CustomResourceDefinition %s exists already. Binding it under the API group %s instead.\n", name, alias) // nolint: errcheck
+	return alias, nil
+}
+
+func (b *BindAPIServiceOptions) bindingAnnotations() map[string]string {
+	if len(b.RequiredCapabilities) == 0 {
+		return nil
+	}
+	return map[string]string{
+		kubebindv1alpha1.RequiredCapabilitiesAnnotationKey: strings.Join(b.RequiredCapabilities, ","),
+	}
+}
+
+func (b *BindAPIServiceOptions) adoptionPolicy() kubebindv1alpha1.AdoptionPolicy {
+	if b.AdoptExisting {
+		return kubebindv1alpha1.AdoptionPolicyAdopt
+	}
+	return ""
+}
+
+func (b *BindAPIServiceOptions) fieldValidationPolicy() kubebindv1alpha1.FieldValidationPolicy {
+	if b.StrictFieldValidation {
+		return kubebindv1alpha1.FieldValidationPolicyStrict
+	}
+	if b.ReportPrunedFields {
+		return kubebindv1alpha1.FieldValidationPolicyPruneAndReport
+	}
+	return ""
+}
+
+func (b *BindAPIServiceOptions) allowedActions() []kubebindv1alpha1.ActionPolicy {
+	var ret []kubebindv1alpha1.ActionPolicy
+	for _, action := range b.AllowActions {
+		ret = append(ret, kubebindv1alpha1.ActionPolicy{Action: kubebindv1alpha1.ActionType(action)})
+	}
+	return ret
+}
@@ -19,6 +19,7 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -30,6 +31,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
 )
 
@@ -86,6 +88,46 @@ func (b *BindAPIServiceOptions) createServiceExportRequest(
 	return result, nil
 }
 
+func (b *BindAPIServiceOptions) warnAboutCapabilities(
+	ctx context.Context,
+	remoteConfig *rest.Config,
+	ns string,
+	request *kubebindv1alpha1.APIServiceExportRequest,
+) error {
+	bindRemoteClient, err := bindclient.NewForConfig(remoteConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, resource := range request.Spec.Resources {
+		name := resource.Resource + "." + resource.Group
+		export, err := bindRemoteClient.KubeBindV1alpha1().APIServiceExports(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if region := export.Labels[kubebindv1alpha1.RegionLabelKey]; !helpers.RegionAllowed(region, b.AllowedRegions) {
+			return fmt.Errorf("APIServiceExport %s is in region %q, which is not one of the allowed regions %s", name, region, strings.Join(b.AllowedRegions, ", "))
+		}
+		if missing := helpers.MissingCapabilities(export, strings.Join(b.RequiredCapabilities, ",")); len(missing) > 0 {
+			fmt.Fprintf(b.Options.IOStreams.ErrOut, "⚠️  APIServiceExport %s does not advertise required capabilities: %s\n", name, strings.Join(missing, ", ")) // nolint: errcheck
+		}
+		if helpers.HasCapability(export, kubebindv1alpha1.APIServiceExportCapabilityReadOnly) {
+			fmt.Fprintf(b.Options.IOStreams.ErrOut, "ℹ️  APIServiceExport %s is read-only. Changes to its objects are not synced to the service provider.\n", name) // nolint: errcheck
+		}
+		b.reviewClaims(export)
+		b.reviewServiceAccountTokenClaims(export)
+		b.reviewEventRelay(export)
+		if suffix := export.Annotations[kubebindv1alpha1.GroupAliasSuffixAnnotationKey]; suffix != "" {
+			if b.groupAliasSuffixes == nil {
+				b.groupAliasSuffixes = map[string]string{}
+			}
+			b.groupAliasSuffixes[name] = suffix
+		}
+	}
+
+	return nil
+}
+
 func (b *BindAPIServiceOptions) printTable(ctx context.Context, config *rest.Config, bindings []*kubebindv1alpha1.APIServiceBinding) error {
 	printer := printers.NewTablePrinter(printers.PrintOptions{
 		WithKind: true,
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/exec"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	"github.com/kube-bind/kube-bind/pkg/kubectl/bind-bundle/plugin"
+)
+
+var (
+	bundleExampleUses = `
+	# generate a signed binding bundle with all APIServiceExports of a consumer, e.g. for an air-gapped cluster.
+	%[1]s bundle --remote-kubeconfig consumer.kubeconfig --signing-key provider.key -o mangodb.tgz
+
+	# generate a signed binding bundle with selected APIServiceExports only.
+	%[1]s bundle mangodbs.mangodb.com --remote-kubeconfig consumer.kubeconfig --signing-key provider.key -o mangodb.tgz
+
+	# install the bundle on the consumer cluster.
+	%[1]s --from-bundle mangodb.tgz --bundle-public-key provider.pub
+	`
+)
+
+func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
+	opts := plugin.NewBundleOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "bundle [<export-name>...]",
+		Short:        "Generate a signed binding bundle to bind without network access to the service provider backend",
+		Example:      fmt.Sprintf(bundleExampleUses, "kubectl bind"),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := logsv1.ValidateAndApply(opts.Logs, nil); err != nil {
+				return err
+			}
+
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+
+			return opts.Run(cmd.Context())
+		},
+	}
+	opts.AddCmdFlags(cmd)
+
+	return cmd, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/bundle"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// BundleOptions are the options for the kubectl-bind-bundle command.
+type BundleOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// RemoteKubeconfig is the kubeconfig of the consumer for its namespace in
+	// the service provider cluster. It is used to read the APIServiceExports
+	// and is put into the bundle for the konnector.
+	RemoteKubeconfig string
+	// SigningKey is a PEM encoded ed25519 private key file to sign the bundle with.
+	SigningKey string
+	// OutputFile is the file the bundle is written to.
+	OutputFile string
+
+	exports []string
+}
+
+// NewBundleOptions returns new BundleOptions.
+func NewBundleOptions(streams genericclioptions.IOStreams) *BundleOptions {
+	return &BundleOptions{
+		Options: base.NewOptions(streams),
+		Logs:    logs.NewOptions(),
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (o *BundleOptions) AddCmdFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	logsv1.AddFlags(o.Logs, cmd.Flags())
+
+	cmd.Flags().StringVar(&o.RemoteKubeconfig, "remote-kubeconfig", o.RemoteKubeconfig, "A kubeconfig file of the consumer for its namespace in the service provider cluster.")
+	cmd.Flags().StringVar(&o.SigningKey, "signing-key", o.SigningKey, "A PEM encoded ed25519 private key file to sign the bundle with, e.g. generated with \"openssl genpkey -algorithm ed25519\".")
+	cmd.Flags().StringVarP(&o.OutputFile, "output-file", "o", o.OutputFile, "The file to write the bundle to. Use - to write to stdout.")
+}
+
+// Complete ensures all fields are initialized.
+func (o *BundleOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	o.exports = args
+	return nil
+}
+
+// Validate validates the BundleOptions are complete and usable.
+func (o *BundleOptions) Validate() error {
+	if o.RemoteKubeconfig == "" {
+		return errors.New("--remote-kubeconfig is required")
+	}
+	if o.SigningKey == "" {
+		return errors.New("--signing-key is required")
+	}
+	if o.OutputFile == "" {
+		return errors.New("--output-file is required")
+	}
+
+	return o.Options.Validate()
+}
+
+// Run generates the binding bundle.
+func (o *BundleOptions) Run(ctx context.Context) error {
+	keyBytes, err := os.ReadFile(o.SigningKey)
+	if err != nil {
+		return fmt.Errorf("failed to read signing key: %w", err)
+	}
+	key, err := bundle.ParsePrivateKey(keyBytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	kubeconfig, err := os.ReadFile(o.RemoteKubeconfig)
+	if err != nil {
+		return err
+	}
+	_, remoteNamespace, err := base.ParseRemoteKubeconfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	remoteConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	bindClient, err := bindclient.NewForConfig(remoteConfig)
+	if err != nil {
+		return err
+	}
+
+	var exports []*kubebindv1alpha1.APIServiceExport
+	if len(o.exports) == 0 {
+		list, err := bindClient.KubeBindV1alpha1().APIServiceExports(remoteNamespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			exports = append(exports, &list.Items[i])
+		}
+	} else {
+		for _, name := range o.exports {
+			export, err := bindClient.KubeBindV1alpha1().APIServiceExports(remoteNamespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			exports = append(exports, export)
+		}
+	}
+	if len(exports) == 0 {
+		return fmt.Errorf("no APIServiceExports found in namespace %s", remoteNamespace)
+	}
+
+	out := o.Options.Out
+	if o.OutputFile != "-" {
+		f, err := os.Create(o.OutputFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // nolint: errcheck
+		out = f
+	}
+	if err := bundle.Write(out, &bundle.Bundle{Kubeconfig: kubeconfig, Exports: exports}, key); err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		fmt.Fprintf(o.Options.ErrOut, "📦 Added APIServiceExport %s to the bundle.\n", export.Name) // nolint: errcheck
+	}
+	if o.OutputFile != "-" {
+		fmt.Fprintf(o.Options.ErrOut, "🔏 Wrote signed binding bundle to %s.\n", o.OutputFile) // nolint: errcheck
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	"github.com/kube-bind/kube-bind/pkg/kubectl/bind-demo/plugin"
+)
+
+var (
+	demoExampleUses = `
+	# create a service provider and a consumer kind cluster, deploy the example backend and bind a MangoDB resource.
+	%[1]s demo
+
+	# run the demo with images built from source, e.g. to reproduce a bug.
+	%[1]s demo --backend-image ghcr.io/kube-bind/example-backend:dev --konnector-image ghcr.io/kube-bind/konnector:dev --load-images
+
+	# delete the kind clusters of the demo.
+	%[1]s demo --delete
+	`
+)
+
+func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
+	opts := plugin.NewDemoOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "demo",
+		Short:        "Create a local demo environment of two kind clusters with a service provider and a bound consumer",
+		Example:      fmt.Sprintf(demoExampleUses, "kubectl bind"),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return cmd.Help()
+			}
+
+			if err := logsv1.ValidateAndApply(opts.Logs, nil); err != nil {
+				return err
+			}
+
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+
+			return opts.Run(cmd.Context())
+		},
+	}
+	opts.AddCmdFlags(cmd)
+
+	return cmd, nil
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	"github.com/kube-bind/kube-bind/deploy/crd"
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/bind-demo/provider"
+	bindplugin "github.com/kube-bind/kube-bind/pkg/kubectl/bind/plugin"
+)
+
+const (
+	demoGroup    = "mangodb.com"
+	demoResource = "mangodbs"
+)
+
+// DemoOptions are the options for the kubectl-bind-demo command.
+type DemoOptions struct {
+	Streams genericclioptions.IOStreams
+	Logs    *logs.Options
+
+	// Name is the prefix of the kind clusters, <name>-provider and <name>-consumer.
+	Name string
+	// KindBinary is the kind executable.
+	KindBinary string
+	// KubeconfigDir is the directory the kubeconfigs of the kind clusters are written to.
+	KubeconfigDir string
+	// BackendImage is the example backend image deployed to the provider cluster.
+	BackendImage string
+	// KonnectorImage is the konnector image deployed to the consumer cluster.
+	// If empty, the image matching the version of kubectl-bind is used.
+	KonnectorImage string
+	// LoadImages loads the images from the local docker daemon into the kind
+	// clusters, e.g. for images built from source.
+	LoadImages bool
+	// BackendPort and IssuerPort are the ports on 127.0.0.1 where the example
+	// backend and its development OIDC issuer are reachable.
+	BackendPort int
+	IssuerPort  int
+	// Delete deletes the kind clusters of the demo instead of creating them.
+	Delete bool
+
+	// Runner is runs the command. It can be replaced in tests.
+	Runner func(cmd *exec.Cmd) error
+}
+
+// NewDemoOptions returns new DemoOptions.
+func NewDemoOptions(streams genericclioptions.IOStreams) *DemoOptions {
+	return &DemoOptions{
+		Streams: streams,
+		Logs:    logs.NewOptions(),
+
+		Name:          "kube-bind-demo",
+		KindBinary:    "kind",
+		KubeconfigDir: ".",
+		BackendImage:  "ghcr.io/kube-bind/example-backend:latest",
+		BackendPort:   8080,
+		IssuerPort:    5556,
+
+		Runner: func(cmd *exec.Cmd) error {
+			return cmd.Run()
+		},
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (o *DemoOptions) AddCmdFlags(cmd *cobra.Command) {
+	logsv1.AddFlags(o.Logs, cmd.Flags())
+
+	cmd.Flags().StringVar(&o.Name, "name", o.Name, "The prefix of the kind clusters, i.e. <name>-provider and <name>-consumer.")
+	cmd.Flags().StringVar(&o.KindBinary, "kind", o.KindBinary, "The kind executable.")
+	cmd.Flags().StringVar(&o.KubeconfigDir, "kubeconfig-dir", o.KubeconfigDir, "The directory the kubeconfigs of the kind clusters are written to.")
+	cmd.Flags().StringVar(&o.BackendImage, "backend-image", o.BackendImage, "The example backend image deployed to the provider cluster.")
+	cmd.Flags().StringVar(&o.KonnectorImage, "konnector-image", o.KonnectorImage, "The konnector image deployed to the consumer cluster. Defaults to the image of the kubectl-bind version.")
+	cmd.Flags().BoolVar(&o.LoadImages, "load-images", o.LoadImages, "Load the backend and konnector images from the local docker daemon into the kind clusters, e.g. after \"make image\".")
+	cmd.Flags().IntVar(&o.BackendPort, "backend-port", o.BackendPort, "The port on 127.0.0.1 the example backend is reachable at.")
+	cmd.Flags().IntVar(&o.IssuerPort, "issuer-port", o.IssuerPort, "The port on 127.0.0.1 the development OIDC issuer of the example backend is reachable at.")
+	cmd.Flags().BoolVar(&o.Delete, "delete", o.Delete, "Delete the kind clusters of the demo.")
+}
+
+// Complete ensures all fields are initialized.
+func (o *DemoOptions) Complete(args []string) error {
+	return nil
+}
+
+// Validate validates the DemoOptions are complete and usable.
+func (o *DemoOptions) Validate() error {
+	if o.Name == "" {
+		return errors.New("--name must not be empty")
+	}
+	if o.KindBinary == "" {
+		return errors.New("--kind must not be empty")
+	}
+	if o.LoadImages && o.KonnectorImage == "" {
+		return errors.New("--load-images requires --konnector-image")
+	}
+	if o.BackendPort == o.IssuerPort {
+		return errors.New("--backend-port and --issuer-port must differ")
+	}
+	for name, port := range map[string]int{"backend-port": o.BackendPort, "issuer-port": o.IssuerPort} {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("--%s must be a port number", name)
+		}
+	}
+	return nil
+}
+
+func (o *DemoOptions) providerCluster() string {
+	return o.Name + "-provider"
+}
+
+func (o *DemoOptions) consumerCluster() string {
+	return o.Name + "-consumer"
+}
+
+// Run creates a service provider and a consumer kind cluster, deploys the
+// example backend and binds its MangoDB resource in the consumer cluster.
+// Existing clusters of the demo are reused.
+func (o *DemoOptions) Run(ctx context.Context) error {
+	if o.Delete {
+		for _, name := range []string{o.consumerCluster(), o.providerCluster()} {
+			fmt.Fprintf(o.Streams.ErrOut, "🗑️  Deleting kind cluster %q.\n", name) // nolint: errcheck
+			if err := o.Runner(o.kind("delete", "cluster", "--name", name)); err != nil {
+				return fmt.Errorf("failed to delete kind cluster %q: %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(o.KubeconfigDir, 0755); err != nil {
+		return err
+	}
+	providerKubeconfig := filepath.Join(o.KubeconfigDir, o.providerCluster()+".kubeconfig")
+	consumerKubeconfig := filepath.Join(o.KubeconfigDir, o.consumerCluster()+".kubeconfig")
+
+	existing, err := o.kindClusters()
+	if err != nil {
+		return err
+	}
+	if err := o.ensureKindCluster(existing, o.providerCluster(), providerKubeconfig, map[int]int{
+		provider.BackendNodePort: o.BackendPort,
+		provider.IssuerNodePort:  o.IssuerPort,
+	}); err != nil {
+		return err
+	}
+	if err := o.ensureKindCluster(existing, o.consumerCluster(), consumerKubeconfig, nil); err != nil {
+		return err
+	}
+
+	if o.LoadImages {
+		for cluster, image := range map[string]string{o.providerCluster(): o.BackendImage, o.consumerCluster(): o.KonnectorImage} {
+			fmt.Fprintf(o.Streams.ErrOut, "📦 Loading image %s into kind cluster %q.\n", image, cluster) // nolint: errcheck
+			if err := o.Runner(o.kind("load", "docker-image", image, "--name", cluster)); err != nil {
+				return fmt.Errorf("failed to load image %s into kind cluster %q: %w", image, cluster, err)
+			}
+		}
+	}
+
+	if err := o.deployProvider(ctx, providerKubeconfig); err != nil {
+		return err
+	}
+
+	backendURL := fmt.Sprintf("http://127.0.0.1:%d", o.BackendPort)
+	fmt.Fprintf(o.Streams.ErrOut, "⏳ Waiting for the example backend at %s.\n", backendURL) // nolint: errcheck
+	if err := waitForBackend(ctx, backendURL+"/export"); err != nil {
+		return fmt.Errorf("example backend did not become ready: %w", err)
+	}
+
+	if err := o.bind(ctx, consumerKubeconfig, backendURL+"/export"); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Streams.ErrOut, "\n🎉 The demo is ready. The consumer cluster has %s.%s bound from the provider cluster:\n\n", demoResource, demoGroup)                                                                                        // nolint: errcheck
+	fmt.Fprintf(o.Streams.ErrOut, "\texport KUBECONFIG=%s\n\tkubectl get %s.%s -A\n\tkubectl bind list\n\n", consumerKubeconfig, demoResource, demoGroup)                                                                                       // nolint: errcheck
+	fmt.Fprintf(o.Streams.ErrOut, "The provider cluster is at %s. Attach the output of \"kubectl bind demo\" and the\nflags used to bug reports. Delete the demo with \"kubectl bind demo --delete --name %s\".\n", providerKubeconfig, o.Name) // nolint: errcheck
+
+	return nil
+}
+
+// deployProvider installs the kube-bind CRDs and deploys the example backend
+// to the service provider cluster. The konnector reaches the provider
+// kube-apiserver through the kind network by container name.
+func (o *DemoOptions) deployProvider(ctx context.Context, kubeconfig string) error {
+	fmt.Fprintf(o.Streams.ErrOut, "🚀 Deploying the example backend %s to kind cluster %q.\n", o.BackendImage, o.providerCluster()) // nolint: errcheck
+
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return err
+	}
+	crdClient, err := apiextensionsclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubeclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if err := crd.Create(ctx,
+		crdClient.ApiextensionsV1().CustomResourceDefinitions(),
+		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "clusterbindings"},
+		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiserviceexports"},
+		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiservicenamespaces"},
+		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiserviceexportrequests"},
+		metav1.GroupResource{Group: kubebindv1alpha1.GroupName, Resource: "apiserviceactions"},
+	); err != nil {
+		return fmt.Errorf("failed to create kube-bind CustomResourceDefinitions: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	externalAddress := fmt.Sprintf("https://%s-control-plane:6443", o.providerCluster())
+	return provider.Bootstrap(ctx, kubeClient.Discovery(), dynamicClient, o.BackendImage, externalAddress, o.BackendPort, o.IssuerPort)
+}
+
+func waitForBackend(ctx context.Context, exportURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	return wait.PollImmediateUntilWithContext(ctx, 2*time.Second, func(ctx context.Context) (bool, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, exportURL, nil)
+		if err != nil {
+			return false, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false, nil
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK, nil
+	})
+}
+
+// bind runs "kubectl bind" against the consumer cluster and plays the browser
+// of the user: the development OIDC issuer logs in without interaction, and
+// the MangoDB resource is selected.
+func (o *DemoOptions) bind(ctx context.Context, kubeconfig, exportURL string) error {
+	fmt.Fprintf(o.Streams.ErrOut, "🔗 Binding %s.%s in kind cluster %q.\n", demoResource, demoGroup, o.consumerCluster()) // nolint: errcheck
+
+	opts := bindplugin.NewBindOptions(o.Streams)
+	cmd := &cobra.Command{}
+	opts.AddCmdFlags(cmd)
+	if err := cmd.Flags().Parse([]string{"--kubeconfig=" + kubeconfig}); err != nil {
+		return err
+	}
+	if err := opts.Complete([]string{exportURL}); err != nil {
+		return err
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	opts.Runner = func(cmd *exec.Cmd) error {
+		if o.KonnectorImage != "" {
+			cmd.Args = append(cmd.Args, "--konnector-image="+o.KonnectorImage)
+		}
+		return o.Runner(cmd)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	urlCh := make(chan string, 1)
+	browserErrCh := make(chan error, 1)
+	go func() {
+		select {
+		case authURL := <-urlCh:
+			browserErrCh <- browse(ctx, authURL, demoGroup, demoResource)
+		case <-ctx.Done():
+			browserErrCh <- ctx.Err()
+		}
+	}()
+
+	if err := opts.Run(ctx, urlCh); err != nil {
+		cancel()
+		if browserErr := <-browserErrCh; browserErr != nil && !errors.Is(browserErr, context.Canceled) {
+			return fmt.Errorf("failed to bind: %w (browser: %v)", err, browserErr)
+		}
+		return fmt.Errorf("failed to bind: %w", err)
+	}
+	return nil
+}
+
+// browse follows the authentication URL through the OIDC login to the resource
+// selection of the example backend, and selects the given resource. The backend
+// then redirects to the callback of "kubectl bind".
+func browse(ctx context.Context, authURL, group, resource string) error {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Jar: jar, Timeout: time.Minute}
+
+	get := func(u string) (*url.URL, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body) // nolint:errcheck
+			return nil, fmt.Errorf("unexpected status %d from %s: %s", resp.StatusCode, resp.Request.URL, body)
+		}
+		return resp.Request.URL, nil
+	}
+
+	resourcesURL, err := get(authURL)
+	if err != nil {
+		return err
+	}
+	if resourcesURL.Path != "/resources" {
+		return fmt.Errorf("expected to be forwarded to the resource selection, got %s", resourcesURL)
+	}
+	values := url.Values{"s": {resourcesURL.Query().Get("s")}, "group": {group}, "resource": {resource}}
+	bindURL := *resourcesURL
+	bindURL.Path = "/bind"
+	bindURL.RawQuery = values.Encode()
+	_, err = get(bindURL.String())
+	return err
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// kindConfig returns the kind cluster configuration. The port mappings are
+// node port to host port on 127.0.0.1.
+func kindConfig(portMappings map[int]int) string {
+	var buf bytes.Buffer
+	buf.WriteString("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n- role: control-plane\n")
+	if len(portMappings) == 0 {
+		return buf.String()
+	}
+	buf.WriteString("  extraPortMappings:\n")
+	for _, nodePort := range sortedKeys(portMappings) {
+		fmt.Fprintf(&buf, "  - containerPort: %d\n    hostPort: %d\n    listenAddress: 127.0.0.1\n", nodePort, portMappings[nodePort])
+	}
+	return buf.String()
+}
+
+func sortedKeys(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// parseClusters parses the output of "kind get clusters".
+func parseClusters(output string) []string {
+	var clusters []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "No kind clusters") {
+			clusters = append(clusters, line)
+		}
+	}
+	return clusters
+}
+
+func (o *DemoOptions) kind(args ...string) *exec.Cmd {
+	cmd := exec.Command(o.KindBinary, args...)
+	cmd.Stdout = o.Streams.ErrOut
+	cmd.Stderr = o.Streams.ErrOut
+	return cmd
+}
+
+func (o *DemoOptions) kindClusters() ([]string, error) {
+	var out bytes.Buffer
+	cmd := o.kind("get", "clusters")
+	cmd.Stdout = &out
+	if err := o.Runner(cmd); err != nil {
+		return nil, fmt.Errorf("failed to list kind clusters: %w", err)
+	}
+	return parseClusters(out.String()), nil
+}
+
+// ensureKindCluster creates the kind cluster if it does not exist yet, and
+// writes its kubeconfig to the given file.
+func (o *DemoOptions) ensureKindCluster(existing []string, name, kubeconfig string, portMappings map[int]int) error {
+	for _, c := range existing {
+		if c == name {
+			fmt.Fprintf(o.Streams.ErrOut, "♻️  Reusing kind cluster %q.\n", name) // nolint: errcheck
+			var out bytes.Buffer
+			cmd := o.kind("get", "kubeconfig", "--name", name)
+			cmd.Stdout = &out
+			if err := o.Runner(cmd); err != nil {
+				return fmt.Errorf("failed to get kubeconfig of kind cluster %q: %w", name, err)
+			}
+			return os.WriteFile(kubeconfig, out.Bytes(), 0600)
+		}
+	}
+
+	fmt.Fprintf(o.Streams.ErrOut, "🏗️  Creating kind cluster %q.\n", name) // nolint: errcheck
+	cmd := o.kind("create", "cluster", "--name", name, "--kubeconfig", kubeconfig, "--config", "-")
+	cmd.Stdin = strings.NewReader(kindConfig(portMappings))
+	if err := o.Runner(cmd); err != nil {
+		return fmt.Errorf("failed to create kind cluster %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKindConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		portMappings map[int]int
+		want         string
+	}{
+		{
+			name: "no port mappings",
+			want: `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+- role: control-plane
+`,
+		},
+		{
+			name:         "port mappings sorted by node port",
+			portMappings: map[int]int{30556: 5556, 30080: 8080},
+			want: `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+- role: control-plane
+  extraPortMappings:
+  - containerPort: 30080
+    hostPort: 8080
+    listenAddress: 127.0.0.1
+  - containerPort: 30556
+    hostPort: 5556
+    listenAddress: 127.0.0.1
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, kindConfig(tt.portMappings))
+		})
+	}
+}
+
+func TestParseClusters(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{name: "none", output: "No kind clusters found.\n"},
+		{name: "empty", output: ""},
+		{name: "some", output: "kind\nkube-bind-demo-consumer\nkube-bind-demo-provider\n", want: []string{"kind", "kube-bind-demo-consumer", "kube-bind-demo-provider"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, parseClusters(tt.output))
+		})
+	}
+}
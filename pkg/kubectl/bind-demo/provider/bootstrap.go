@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"embed"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kube-bind/kube-bind/pkg/bootstrap"
+)
+
+const (
+	// BackendNodePort is the node port of the example backend.
+	BackendNodePort = 30080
+	// IssuerNodePort is the node port of the development OIDC issuer of the example backend.
+	IssuerNodePort = 30556
+)
+
+//go:embed *.yaml
+var raw embed.FS
+
+// Bootstrap deploys the example backend in development mode and an exported
+// MangoDB CustomResourceDefinition to the service provider cluster of the demo.
+func Bootstrap(ctx context.Context, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, image, externalAddress string, backendPort, issuerPort int) error {
+	return bootstrap.Bootstrap(ctx, discoveryClient, dynamicClient, sets.NewString(), raw,
+		bootstrap.ReplaceOption(
+			"BACKEND_IMAGE", image,
+			"EXTERNAL_ADDRESS", externalAddress,
+			"BACKEND_PORT", strconv.Itoa(backendPort),
+			"ISSUER_PORT", strconv.Itoa(issuerPort),
+		),
+	)
+}
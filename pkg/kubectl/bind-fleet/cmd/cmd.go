@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/exec"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	"github.com/kube-bind/kube-bind/pkg/kubectl/bind-fleet/plugin"
+)
+
+var (
+	fleetApplyExampleUses = `
+	# apply the bindings of fleet.yaml to all its clusters, four at a time.
+	%[1]s fleet apply -f fleet.yaml
+
+	# where fleet.yaml lists kubeconfig contexts or Cluster API Clusters, and the bindings:
+	clusters:
+	- context: prod-eu
+	- clusterRef:
+	    namespace: fleet
+	    name: prod-us
+	bindings:
+	- url: https://mangodb.com/exports
+	- name: mangodb-bundle
+	  fromBundle: mangodb.tgz
+	  bundlePublicKey: mangodb.pub
+	  args: ["--accept-claims=storageclasses.storage.k8s.io"]
+	`
+)
+
+func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:          "fleet",
+		Short:        "Bind across a fleet of consumer clusters",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	opts := plugin.NewApplyOptions(streams)
+	applyCmd := &cobra.Command{
+		Use:          "apply -f fleet.yaml",
+		Short:        "Apply bindings to many consumer clusters concurrently, with retries and a summary report",
+		Example:      fmt.Sprintf(fleetApplyExampleUses, "kubectl bind"),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := logsv1.ValidateAndApply(opts.Logs, nil); err != nil {
+				return err
+			}
+
+			if len(args) != 0 {
+				return cmd.Help()
+			}
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+
+			return opts.Run(cmd.Context())
+		},
+	}
+	opts.AddCmdFlags(applyCmd)
+	cmd.AddCommand(applyCmd)
+
+	return cmd, nil
+}
@@ -0,0 +1,332 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// ApplyOptions are the options for the kubectl-bind-fleet-apply command.
+type ApplyOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// File is the fleet manifest. Use - to read from stdin.
+	File string
+	// Parallelism is the number of clusters bound concurrently. The bindings of one
+	// cluster are applied one after another.
+	Parallelism int
+	// Retries is how often a failed binding is retried, waiting RetryInterval in between.
+	Retries       int
+	RetryInterval time.Duration
+
+	// Runner runs the "kubectl bind" command. It can be replaced in tests.
+	Runner func(cmd *exec.Cmd) error
+	// ClusterKubeconfig returns the kubeconfig of a Cluster API Cluster. It can be
+	// replaced in tests.
+	ClusterKubeconfig func(ctx context.Context, ref ClusterRef) ([]byte, error)
+}
+
+// NewApplyOptions returns new ApplyOptions.
+func NewApplyOptions(streams genericclioptions.IOStreams) *ApplyOptions {
+	o := &ApplyOptions{
+		Options:       base.NewOptions(streams),
+		Logs:          logs.NewOptions(),
+		Parallelism:   4,
+		Retries:       2,
+		RetryInterval: 10 * time.Second,
+
+		Runner: func(cmd *exec.Cmd) error {
+			return cmd.Run()
+		},
+	}
+	o.ClusterKubeconfig = o.clusterAPIKubeconfig
+	return o
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (o *ApplyOptions) AddCmdFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	logsv1.AddFlags(o.Logs, cmd.Flags())
+
+	cmd.Flags().StringVarP(&o.File, "file", "f", o.File, "The fleet manifest with the clusters and the bindings to apply. Use - to read from stdin.")
+	cmd.Flags().IntVar(&o.Parallelism, "parallelism", o.Parallelism, "Number of clusters to bind concurrently. The bindings of one cluster are applied one after another.")
+	cmd.Flags().IntVar(&o.Retries, "retries", o.Retries, "Number of times a failed binding is retried.")
+	cmd.Flags().DurationVar(&o.RetryInterval, "retry-interval", o.RetryInterval, "Time to wait before retrying a failed binding.")
+}
+
+// Complete ensures all fields are initialized.
+func (o *ApplyOptions) Complete(args []string) error {
+	return o.Options.Complete()
+}
+
+// Validate validates the ApplyOptions are complete and usable.
+func (o *ApplyOptions) Validate() error {
+	if o.File == "" {
+		return errors.New("--file is required")
+	}
+	if o.Parallelism < 1 {
+		return errors.New("--parallelism must be at least 1")
+	}
+	if o.Retries < 0 {
+		return errors.New("--retries must not be negative")
+	}
+	if o.RetryInterval < 0 {
+		return errors.New("--retry-interval must not be negative")
+	}
+
+	return o.Options.Validate()
+}
+
+// result is the outcome of one binding in one cluster.
+type result struct {
+	cluster  string
+	binding  string
+	attempts int
+	err      error
+}
+
+// Run applies every binding of the fleet manifest to every cluster, clusters
+// concurrently, and prints a summary report. It fails if any binding failed.
+func (o *ApplyOptions) Run(ctx context.Context) error {
+	var bs []byte
+	var err error
+	if o.File == "-" {
+		bs, err = io.ReadAll(o.Options.In)
+	} else {
+		bs, err = os.ReadFile(o.File)
+	}
+	if err != nil {
+		return err
+	}
+	fleet, err := LoadFleet(bs)
+	if err != nil {
+		return fmt.Errorf("invalid fleet manifest %s: %w", o.File, err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "kubectl-bind-fleet-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var lock sync.Mutex
+	results := make([][]result, len(fleet.Clusters))
+	sem := make(chan struct{}, o.Parallelism)
+	var wg sync.WaitGroup
+	for i, c := range fleet.Clusters {
+		i, c := i, c
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = o.applyCluster(ctx, &lock, tmpDir, i, c, fleet.Bindings)
+		}()
+	}
+	wg.Wait()
+
+	w := tabwriter.NewWriter(o.Options.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "CLUSTER\tBINDING\tRESULT\tATTEMPTS\tERROR\n") // nolint: errcheck
+	failed, total := 0, 0
+	for _, rs := range results {
+		for _, r := range rs {
+			total++
+			status, msg := "Bound", ""
+			if r.err != nil {
+				failed++
+				status, msg = "Failed", r.err.Error()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", r.cluster, r.binding, status, r.attempts, msg) // nolint: errcheck
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d bindings failed", failed, total)
+	}
+	return nil
+}
+
+// applyCluster applies the bindings to one cluster, one after another.
+func (o *ApplyOptions) applyCluster(ctx context.Context, lock *sync.Mutex, tmpDir string, i int, c Cluster, bindings []Binding) []result {
+	name := c.String()
+	progress := func(format string, args ...interface{}) {
+		lock.Lock()
+		defer lock.Unlock()
+		fmt.Fprintf(o.Options.ErrOut, "[%s] "+format+"\n", append([]interface{}{name}, args...)...) // nolint: errcheck
+	}
+
+	results := make([]result, 0, len(bindings))
+	targetArgs, err := o.targetArgs(ctx, tmpDir, i, c)
+	if err != nil {
+		progress("❌ Failed to get kubeconfig: %v", err)
+		for _, b := range bindings {
+			results = append(results, result{cluster: name, binding: b.String(), err: err})
+		}
+		return results
+	}
+
+	for _, b := range bindings {
+		r := result{cluster: name, binding: b.String()}
+		for r.attempts = 1; ; r.attempts++ {
+			progress("⏳ Binding %s (attempt %d/%d)", b, r.attempts, o.Retries+1)
+			if r.err = o.bind(ctx, lock, name, targetArgs, b); r.err == nil {
+				progress("✅ Bound %s", b)
+				break
+			}
+			if r.attempts > o.Retries || ctx.Err() != nil {
+				progress("❌ Failed to bind %s: %v", b, r.err)
+				break
+			}
+			progress("⚠️ Failed to bind %s, retrying in %s: %v", b, o.RetryInterval, r.err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(o.RetryInterval):
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// targetArgs returns the flags that point "kubectl bind" to the given cluster.
+func (o *ApplyOptions) targetArgs(ctx context.Context, tmpDir string, i int, c Cluster) ([]string, error) {
+	if c.ClusterRef == nil {
+		var args []string
+		if o.Options.Kubeconfig != "" {
+			args = append(args, "--kubeconfig="+o.Options.Kubeconfig)
+		}
+		return append(args, "--context="+c.Context), nil
+	}
+
+	kubeconfig, err := o.ClusterKubeconfig(ctx, *c.ClusterRef)
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Join(tmpDir, fmt.Sprintf("cluster-%d.kubeconfig", i))
+	if err := os.WriteFile(file, kubeconfig, 0600); err != nil {
+		return nil, err
+	}
+	return []string{"--kubeconfig=" + file}, nil
+}
+
+// clusterAPIKubeconfig reads the kubeconfig of a Cluster API Cluster from the
+// management cluster, i.e. the cluster the kubeconfig flags point to.
+func (o *ApplyOptions) clusterAPIKubeconfig(ctx context.Context, ref ClusterRef) ([]byte, error) {
+	config, err := o.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubeclient.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := kubeClient.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name+"-kubeconfig", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	kubeconfig, found := secret.Data["value"]
+	if !found {
+		return nil, fmt.Errorf("secret %s/%s-kubeconfig does not contain key %q", ref.Namespace, ref.Name, "value")
+	}
+	return kubeconfig, nil
+}
+
+// bind runs "kubectl bind" once, with its output prefixed by the cluster name.
+func (o *ApplyOptions) bind(ctx context.Context, lock *sync.Mutex, name string, targetArgs []string, b Binding) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	if b.URL != "" {
+		args = append(args, b.URL)
+	} else {
+		args = append(args, "--from-bundle="+b.FromBundle, "--bundle-public-key="+b.BundlePublicKey)
+	}
+	args = append(args, targetArgs...)
+	args = append(args, b.Args...)
+
+	stdout := &prefixWriter{lock: lock, w: o.Options.Out, prefix: "[" + name + "] "}
+	stderr := &prefixWriter{lock: lock, w: o.Options.ErrOut, prefix: "[" + name + "] "}
+	defer stdout.Flush()
+	defer stderr.Flush()
+
+	command := exec.CommandContext(ctx, executable, args...)
+	command.Stdout = stdout
+	command.Stderr = stderr
+	return o.Runner(command)
+}
+
+// prefixWriter writes complete lines with a prefix, such that the output of
+// concurrent commands does not interleave within lines.
+type prefixWriter struct {
+	lock   *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(bs []byte) (int, error) {
+	p.buf = append(p.buf, bs...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			return len(bs), nil
+		}
+		p.write(p.buf[:i+1])
+		p.buf = p.buf[i+1:]
+	}
+}
+
+// Flush writes a trailing incomplete line.
+func (p *prefixWriter) Flush() {
+	if len(p.buf) > 0 {
+		p.write(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+func (p *prefixWriter) write(line []byte) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	fmt.Fprintf(p.w, "%s%s", p.prefix, line) // nolint: errcheck
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const testFleet = `
+clusters:
+- context: prod-eu
+- clusterRef:
+    name: prod-us
+bindings:
+- url: https://mangodb.com/exports
+- name: mangodb-bundle
+  fromBundle: mangodb.tgz
+  bundlePublicKey: mangodb.pub
+  args: ["--accept-claims=nodes"]
+`
+
+func TestLoadFleet(t *testing.T) {
+	tests := []struct {
+		name    string
+		fleet   string
+		wantErr string
+	}{
+		{name: "valid", fleet: testFleet},
+		{name: "no clusters", fleet: "bindings: [{url: https://mangodb.com}]", wantErr: "no clusters given"},
+		{name: "context and clusterRef", fleet: "clusters: [{context: a, clusterRef: {name: b}}]\nbindings: [{url: https://mangodb.com}]", wantErr: "exactly one of context and clusterRef"},
+		{name: "duplicate cluster", fleet: "clusters: [{context: a}, {context: a}]\nbindings: [{url: https://mangodb.com}]", wantErr: "listed twice"},
+		{name: "bundle without key", fleet: "clusters: [{context: a}]\nbindings: [{fromBundle: mangodb.tgz}]", wantErr: "bundlePublicKey is required"},
+		{name: "unknown field", fleet: "clusters: [{contxt: a}]\nbindings: [{url: https://mangodb.com}]", wantErr: "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fleet, err := LoadFleet([]byte(tt.fleet))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "default/prod-us", fleet.Clusters[1].String())
+			require.Equal(t, "mangodb-bundle", fleet.Bindings[1].String())
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "fleet.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testFleet), 0600))
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	o := NewApplyOptions(genericclioptions.IOStreams{Out: out, ErrOut: errOut})
+	o.File = file
+	o.RetryInterval = 0
+	o.ClusterKubeconfig = func(ctx context.Context, ref ClusterRef) ([]byte, error) {
+		if ref != (ClusterRef{Namespace: "default", Name: "prod-us"}) {
+			return nil, fmt.Errorf("unexpected cluster %v", ref)
+		}
+		return []byte("kubeconfig of prod-us"), nil
+	}
+
+	var lock sync.Mutex
+	calls := map[string]int{}
+	o.Runner = func(cmd *exec.Cmd) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		args := strings.Join(cmd.Args[1:], " ")
+		calls[args]++
+		fmt.Fprintf(cmd.Stderr, "binding with %s", args) // nolint: errcheck
+
+		switch {
+		case strings.Contains(args, "--context=prod-eu") && strings.Contains(args, "mangodb.tgz"):
+			return errors.New("bundle expired")
+		case strings.HasPrefix(cmd.Args[2], "--kubeconfig=") && calls[args] == 1:
+			if kubeconfig, err := os.ReadFile(strings.TrimPrefix(cmd.Args[2], "--kubeconfig=")); err != nil || string(kubeconfig) != "kubeconfig of prod-us" {
+				return fmt.Errorf("unexpected kubeconfig %q: %v", kubeconfig, err)
+			}
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	err := o.Run(context.Background())
+	require.EqualError(t, err, "1 of 4 bindings failed")
+
+	require.Equal(t, 3, calls["--from-bundle=mangodb.tgz --bundle-public-key=mangodb.pub --context=prod-eu --accept-claims=nodes"])
+	require.Equal(t, 1, calls["https://mangodb.com/exports --context=prod-eu"])
+	require.Contains(t, errOut.String(), "[prod-eu] binding with https://mangodb.com/exports --context=prod-eu\n")
+	require.Contains(t, errOut.String(), "[default/prod-us] ⚠️ Failed to bind https://mangodb.com/exports, retrying in 0s: connection refused\n")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 5)
+	require.Regexp(t, `^prod-eu +https://mangodb.com/exports +Bound +1 *$`, lines[1])
+	require.Regexp(t, `^prod-eu +mangodb-bundle +Failed +3 +bundle expired$`, lines[2])
+	require.Regexp(t, `^default/prod-us +https://mangodb.com/exports +Bound +2 *$`, lines[3])
+	require.Regexp(t, `^default/prod-us +mangodb-bundle +Bound +1 *$`, lines[4])
+}
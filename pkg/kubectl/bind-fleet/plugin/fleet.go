@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Fleet is the manifest of "kubectl bind fleet apply". Every binding is applied
+// to every cluster.
+type Fleet struct {
+	// Clusters are the consumer clusters to bind in.
+	Clusters []Cluster `json:"clusters"`
+	// Bindings are the bindings to apply to each cluster, in order.
+	Bindings []Binding `json:"bindings"`
+}
+
+// Cluster is a consumer cluster. Exactly one of context and clusterRef must be set.
+type Cluster struct {
+	// Context is a context of the kubeconfig.
+	Context string `json:"context,omitempty"`
+	// ClusterRef references a Cluster API Cluster in the management cluster. Its
+	// kubeconfig is read from the <name>-kubeconfig secret next to it.
+	ClusterRef *ClusterRef `json:"clusterRef,omitempty"`
+}
+
+// ClusterRef references a Cluster API Cluster.
+type ClusterRef struct {
+	// Namespace of the Cluster. Defaults to "default".
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the Cluster.
+	Name string `json:"name"`
+}
+
+// Binding is what is passed to "kubectl bind" for each cluster. Exactly one of
+// url and fromBundle must be set.
+type Binding struct {
+	// Name identifies the binding in the progress and the report. Defaults to the
+	// url or the bundle.
+	Name string `json:"name,omitempty"`
+	// URL is the service provider URL to bind, as passed to "kubectl bind". This
+	// authenticates interactively against the service provider.
+	URL string `json:"url,omitempty"`
+	// FromBundle is a signed binding bundle to bind from, without authentication.
+	FromBundle string `json:"fromBundle,omitempty"`
+	// BundlePublicKey is the public key file to verify fromBundle with.
+	BundlePublicKey string `json:"bundlePublicKey,omitempty"`
+	// Args are further flags for "kubectl bind", e.g. --accept-claims=nodes.
+	Args []string `json:"args,omitempty"`
+}
+
+func (c Cluster) String() string {
+	if c.ClusterRef != nil {
+		return c.ClusterRef.Namespace + "/" + c.ClusterRef.Name
+	}
+	return c.Context
+}
+
+func (b Binding) String() string {
+	switch {
+	case b.Name != "":
+		return b.Name
+	case b.URL != "":
+		return b.URL
+	default:
+		return b.FromBundle
+	}
+}
+
+// LoadFleet parses and validates a fleet manifest.
+func LoadFleet(bs []byte) (*Fleet, error) {
+	var fleet Fleet
+	if err := yaml.UnmarshalStrict(bs, &fleet); err != nil {
+		return nil, err
+	}
+
+	if len(fleet.Clusters) == 0 {
+		return nil, errors.New("no clusters given")
+	}
+	if len(fleet.Bindings) == 0 {
+		return nil, errors.New("no bindings given")
+	}
+	seen := map[string]bool{}
+	for i := range fleet.Clusters {
+		c := &fleet.Clusters[i]
+		if (c.Context == "") == (c.ClusterRef == nil) {
+			return nil, fmt.Errorf("cluster #%d: exactly one of context and clusterRef must be set", i+1)
+		}
+		if c.ClusterRef != nil {
+			if c.ClusterRef.Name == "" {
+				return nil, fmt.Errorf("cluster #%d: clusterRef.name is required", i+1)
+			}
+			if c.ClusterRef.Namespace == "" {
+				c.ClusterRef.Namespace = "default"
+			}
+		}
+		if seen[c.String()] {
+			return nil, fmt.Errorf("cluster #%d: %s is listed twice", i+1, c)
+		}
+		seen[c.String()] = true
+	}
+	for i, b := range fleet.Bindings {
+		if (b.URL == "") == (b.FromBundle == "") {
+			return nil, fmt.Errorf("binding #%d: exactly one of url and fromBundle must be set", i+1)
+		}
+		if b.FromBundle != "" && b.BundlePublicKey == "" {
+			return nil, fmt.Errorf("binding #%d: bundlePublicKey is required with fromBundle", i+1)
+		}
+	}
+
+	return &fleet, nil
+}
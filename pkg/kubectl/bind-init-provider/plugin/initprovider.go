@@ -0,0 +1,253 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+	"sigs.k8s.io/yaml"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+//go:embed templates/*.yaml
+var templates embed.FS
+
+// InitProviderOptions are the options for the kubectl-bind-init-provider command.
+type InitProviderOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// File is the file to read the CustomResourceDefinition from instead of the cluster.
+	File string
+	// OutputDir is the directory the scaffolding is written to.
+	OutputDir string
+	// InformerScope is the scope of the informers of the konnector on the
+	// service provider cluster, Cluster or Namespaced.
+	InformerScope string
+	// NamespacePrefix is the prefix of the service provider namespaces of consumer clusters.
+	NamespacePrefix string
+	// PrettyName is the name of the service provider shown to consumers.
+	PrettyName string
+
+	name string
+}
+
+// NewInitProviderOptions returns new InitProviderOptions.
+func NewInitProviderOptions(streams genericclioptions.IOStreams) *InitProviderOptions {
+	return &InitProviderOptions{
+		Options: base.NewOptions(streams),
+		Logs:    logs.NewOptions(),
+
+		OutputDir:       ".",
+		InformerScope:   string(kubebindv1alpha1.NamespacedScope),
+		NamespacePrefix: "cluster",
+		PrettyName:      "Example Backend",
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (o *InitProviderOptions) AddCmdFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	logsv1.AddFlags(o.Logs, cmd.Flags())
+
+	cmd.Flags().StringVarP(&o.File, "file", "f", o.File, "Read the CustomResourceDefinition from the given file instead of the cluster.")
+	cmd.Flags().StringVarP(&o.OutputDir, "output-dir", "o", o.OutputDir, "The directory to write the scaffolding to.")
+	cmd.Flags().StringVar(&o.InformerScope, "consumer-scope", o.InformerScope, "How consumers access the service provider cluster, Namespaced or Cluster.")
+	cmd.Flags().StringVar(&o.NamespacePrefix, "namespace-prefix", o.NamespacePrefix, "The prefix of the service provider namespaces of consumer clusters.")
+	cmd.Flags().StringVar(&o.PrettyName, "pretty-name", o.PrettyName, "The name of the service provider shown to consumers.")
+}
+
+// Complete ensures all fields are initialized.
+func (o *InitProviderOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.name = args[0]
+	}
+	switch strings.ToLower(o.InformerScope) {
+	case "namespaced":
+		o.InformerScope = string(kubebindv1alpha1.NamespacedScope)
+	case "cluster":
+		o.InformerScope = string(kubebindv1alpha1.ClusterScope)
+	}
+	return nil
+}
+
+// Validate validates the InitProviderOptions are complete and usable.
+func (o *InitProviderOptions) Validate() error {
+	if o.name == "" && o.File == "" {
+		return errors.New("CustomResourceDefinition name or --file is required")
+	}
+	if o.name != "" && o.File != "" {
+		return errors.New("CustomResourceDefinition name and --file are mutually exclusive")
+	}
+	switch kubebindv1alpha1.Scope(o.InformerScope) {
+	case kubebindv1alpha1.NamespacedScope, kubebindv1alpha1.ClusterScope:
+	default:
+		return fmt.Errorf("--consumer-scope must be %q or %q", kubebindv1alpha1.NamespacedScope, kubebindv1alpha1.ClusterScope)
+	}
+	if o.OutputDir == "" {
+		return errors.New("--output-dir must not be empty")
+	}
+
+	return o.Options.Validate()
+}
+
+// Run scaffolds a service provider for the CustomResourceDefinition.
+func (o *InitProviderOptions) Run(ctx context.Context) error {
+	crd, err := o.getCRD(ctx)
+	if err != nil {
+		return err
+	}
+
+	files, err := Scaffold(crd, o.InformerScope, o.NamespacePrefix, o.PrettyName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(o.OutputDir, 0755); err != nil {
+		return err
+	}
+	for _, name := range scaffoldFiles {
+		fileName := filepath.Join(o.OutputDir, name)
+		if err := os.WriteFile(fileName, files[name], 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Options.IOStreams.ErrOut, "📝 Wrote %s\n", fileName) // nolint: errcheck
+	}
+
+	return nil
+}
+
+func (o *InitProviderOptions) getCRD(ctx context.Context) (*apiextensionsv1.CustomResourceDefinition, error) {
+	if o.File != "" {
+		bs, err := os.ReadFile(o.File)
+		if err != nil {
+			return nil, err
+		}
+		var crd apiextensionsv1.CustomResourceDefinition
+		if err := yaml.UnmarshalStrict(bs, &crd); err != nil {
+			return nil, fmt.Errorf("failed to parse CustomResourceDefinition in %s: %w", o.File, err)
+		}
+		if crd.Kind != "CustomResourceDefinition" {
+			return nil, fmt.Errorf("%s does not contain a CustomResourceDefinition, but %q", o.File, crd.Kind)
+		}
+		return &crd, nil
+	}
+
+	config, err := o.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	apiextensionsClient, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return apiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, o.name, metav1.GetOptions{})
+}
+
+// scaffoldFiles are the files written by Scaffold, in output order.
+var scaffoldFiles = []string{
+	"apiserviceexport.yaml",
+	"crd-patch.yaml",
+	"rbac.yaml",
+	"namespace.yaml",
+	"backend.yaml",
+}
+
+type templateInput struct {
+	Name            string
+	Group           string
+	Resource        string
+	InformerScope   string
+	NamespacePrefix string
+	PrettyName      string
+}
+
+// Scaffold returns the APIServiceExport, the CRD patch, the RBAC and namespace
+// templates and a sample backend configuration for the CRD by file name.
+func Scaffold(crd *apiextensionsv1.CustomResourceDefinition, informerScope, namespacePrefix, prettyName string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	exportSpec, err := kubebindhelpers.CRDToServiceExport(crd)
+	if err != nil {
+		return nil, err
+	}
+	export := &kubebindv1alpha1.APIServiceExport{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kubebindv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "APIServiceExport",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      crd.Name,
+			Namespace: "CLUSTER_NAMESPACE",
+		},
+		Spec: kubebindv1alpha1.APIServiceExportSpec{
+			APIServiceExportCRDSpec: *exportSpec,
+			InformerScope:           kubebindv1alpha1.Scope(informerScope),
+		},
+	}
+	bs, err := yaml.Marshal(export)
+	if err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("# APIServiceExport of %s. The example backend creates it for every\n# APIServiceExportRequest in the namespace of the consumer cluster.\n", crd.Name)
+	files["apiserviceexport.yaml"] = append([]byte(header), bs...)
+
+	input := templateInput{
+		Name:            crd.Name,
+		Group:           crd.Spec.Group,
+		Resource:        crd.Spec.Names.Plural,
+		InformerScope:   informerScope,
+		NamespacePrefix: namespacePrefix,
+		PrettyName:      prettyName,
+	}
+	for _, name := range scaffoldFiles[1:] {
+		tmpl, err := template.ParseFS(templates, "templates/"+name)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, input); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		files[name] = buf.Bytes()
+	}
+
+	return files, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestScaffold(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "mangodb.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   "mangodbs",
+				Singular: "mangodb",
+				Kind:     "MangoDB",
+				ListKind: "MangoDBList",
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Storage: true, Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{Type: "object"},
+				}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		informerScope string
+		wantBinding   bool
+	}{
+		{name: "namespaced", informerScope: "Namespaced", wantBinding: true},
+		{name: "cluster", informerScope: "Cluster", wantBinding: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := Scaffold(crd, tt.informerScope, "cluster", "MangoDB Inc.")
+			require.NoError(t, err)
+			require.Len(t, files, len(scaffoldFiles))
+
+			var export kubebindv1alpha1.APIServiceExport
+			require.NoError(t, yaml.Unmarshal(files["apiserviceexport.yaml"], &export))
+			require.Equal(t, "mangodbs.mangodb.com", export.Name)
+			require.Equal(t, "mangodb.com", export.Spec.Group)
+			require.Equal(t, kubebindv1alpha1.Scope(tt.informerScope), export.Spec.InformerScope)
+			require.Len(t, export.Spec.Versions, 1)
+
+			require.Contains(t, string(files["rbac.yaml"]), `- "mangodbs/status"`)
+			require.Contains(t, string(files["backend.yaml"]), "--consumer-scope="+tt.informerScope)
+			require.Equal(t, tt.wantBinding, containsRoleBinding(files["namespace.yaml"]))
+
+			for name, bs := range files {
+				var obj map[string]interface{}
+				require.NoError(t, yaml.Unmarshal(bs, &obj), "file %s", name)
+			}
+		})
+	}
+}
+
+func containsRoleBinding(bs []byte) bool {
+	return bytes.Contains(bs, []byte("kind: RoleBinding"))
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/exec"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	"github.com/kube-bind/kube-bind/pkg/kubectl/bind-konnector/plugin"
+)
+
+var (
+	konnectorExampleUses = `
+	# show the live state of the APIServiceBindings in all konnector pods.
+	%[1]s konnector bindings
+
+	# restart syncing an APIServiceBinding, relisting all objects.
+	%[1]s konnector resync mangodbs.mangodb.com
+
+	# pause and resume syncing an APIServiceBinding.
+	%[1]s konnector pause mangodbs.mangodb.com
+	%[1]s konnector resume mangodbs.mangodb.com
+
+	# dump diagnostics of all konnector pods.
+	%[1]s konnector diagnostics
+	`
+)
+
+func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:          "konnector",
+		Short:        "Manage the konnector through its management API",
+		Example:      fmt.Sprintf(konnectorExampleUses, "kubectl bind"),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newCommand(streams, "bindings", "Show the live state of the APIServiceBindings in the konnector pods", 0,
+		func(ctx context.Context, opts *plugin.KonnectorOptions) error { return opts.RunBindings(ctx) }))
+	cmd.AddCommand(newCommand(streams, "diagnostics", "Dump diagnostics of the konnector pods", 0,
+		func(ctx context.Context, opts *plugin.KonnectorOptions) error { return opts.RunDiagnostics(ctx) }))
+	for action, short := range map[string]string{
+		"resync": "Restart syncing an APIServiceBinding, relisting all objects",
+		"pause":  "Pause syncing an APIServiceBinding until it is resumed or the konnector restarts",
+		"resume": "Resume syncing a paused APIServiceBinding",
+	} {
+		action := action
+		cmd.AddCommand(newCommand(streams, action+" <apiservicebinding-name>", short, 1,
+			func(ctx context.Context, opts *plugin.KonnectorOptions) error { return opts.RunAction(ctx, action) }))
+	}
+
+	return cmd, nil
+}
+
+func newCommand(streams genericclioptions.IOStreams, use, short string, numArgs int, run func(context.Context, *plugin.KonnectorOptions) error) *cobra.Command {
+	opts := plugin.NewKonnectorOptions(streams)
+	cmd := &cobra.Command{
+		Use:          use,
+		Short:        short,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := logsv1.ValidateAndApply(opts.Logs, nil); err != nil {
+				return err
+			}
+
+			if len(args) != numArgs {
+				return cmd.Help()
+			}
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+
+			return run(cmd.Context(), opts)
+		},
+	}
+	opts.AddCmdFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kube-bind/kube-bind/pkg/konnector/management"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// KonnectorOptions are the options for the kubectl-bind-konnector commands.
+type KonnectorOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// Namespace is the namespace of the konnector pods and the management token.
+	Namespace string
+	// Selector selects the konnector pods.
+	Selector string
+	// Port is the port of the management API in the konnector pods.
+	Port int
+	// Consumer is the consumer cluster of the APIServiceBinding, as passed to
+	// --consumer-kubeconfig of the konnector. Empty is the konnector's own cluster.
+	Consumer string
+
+	name string
+}
+
+// NewKonnectorOptions returns new KonnectorOptions.
+func NewKonnectorOptions(streams genericclioptions.IOStreams) *KonnectorOptions {
+	return &KonnectorOptions{
+		Options:   base.NewOptions(streams),
+		Logs:      logs.NewOptions(),
+		Namespace: "kube-bind",
+		Selector:  "app=konnector",
+		Port:      8090,
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (o *KonnectorOptions) AddCmdFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	logsv1.AddFlags(o.Logs, cmd.Flags())
+
+	cmd.Flags().StringVar(&o.Namespace, "konnector-namespace", o.Namespace, "The namespace of the konnector.")
+	cmd.Flags().StringVar(&o.Selector, "selector", o.Selector, "The label selector of the konnector pods.")
+	cmd.Flags().IntVar(&o.Port, "management-port", o.Port, "The port of the konnector management API, see --management-bind-address of the konnector.")
+	cmd.Flags().StringVar(&o.Consumer, "consumer", o.Consumer, "The consumer cluster of the APIServiceBinding as passed to --consumer-kubeconfig of the konnector. Empty is the konnector's own cluster.")
+}
+
+// Complete ensures all fields are initialized.
+func (o *KonnectorOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.name = args[0]
+	}
+	return nil
+}
+
+// Validate validates the KonnectorOptions are complete and usable.
+func (o *KonnectorOptions) Validate() error {
+	if o.Port <= 0 || o.Port > 65535 {
+		return fmt.Errorf("invalid --management-port %d", o.Port)
+	}
+
+	return o.Options.Validate()
+}
+
+type podClient struct {
+	pod    string
+	client *management.Client
+}
+
+// clients returns a management client for every running konnector pod.
+func (o *KonnectorOptions) clients(ctx context.Context) ([]podClient, error) {
+	config, err := o.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubeclient.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(o.Namespace).Get(ctx, management.TokenSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the konnector management token: %w", err)
+	}
+	token := string(secret.Data[management.TokenSecretKey])
+
+	pods, err := kubeClient.CoreV1().Pods(o.Namespace).List(ctx, metav1.ListOptions{LabelSelector: o.Selector})
+	if err != nil {
+		return nil, err
+	}
+	var clients []podClient
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		clients = append(clients, podClient{
+			pod:    pod.Name,
+			client: management.NewClient(kubeClient.CoreV1().RESTClient(), o.Namespace, pod.Name, o.Port, token),
+		})
+	}
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("no running konnector pods found in namespace %s with selector %q", o.Namespace, o.Selector)
+	}
+	return clients, nil
+}
+
+// RunBindings prints the live state of the APIServiceBindings in every konnector pod.
+func (o *KonnectorOptions) RunBindings(ctx context.Context) error {
+	clients, err := o.clients(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(o.Options.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "POD\tCONSUMER\tNAME\tREADY\tSYNCING\tSINCE\tPAUSED\tOWNED\n") // nolint: errcheck
+	for _, c := range clients {
+		bindings, err := c.client.Bindings(ctx)
+		if err != nil {
+			fmt.Fprintf(o.Options.ErrOut, "failed to get bindings of pod %s: %v\n", c.pod, err) // nolint: errcheck
+			continue
+		}
+		for _, b := range bindings {
+			since := "<none>"
+			if b.SyncingSince != nil {
+				since = duration.HumanDuration(time.Since(b.SyncingSince.Time))
+			}
+			consumer := b.Consumer
+			if consumer == "" {
+				consumer = "<local>"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\t%s\t%v\t%v\n", c.pod, consumer, b.Name, b.Ready, b.Syncing, since, b.Paused, b.Owned) // nolint: errcheck
+		}
+	}
+	return w.Flush()
+}
+
+// RunAction calls resync, pause or resume for the APIServiceBinding on every
+// konnector pod, such that standby replicas take it over on failover.
+func (o *KonnectorOptions) RunAction(ctx context.Context, action string) error {
+	if o.name == "" {
+		return errors.New("APIServiceBinding name is required")
+	}
+	clients, err := o.clients(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, c := range clients {
+		if err := c.client.Action(ctx, action, o.Consumer, o.name); err != nil {
+			errs = append(errs, fmt.Errorf("pod %s: %w", c.pod, err))
+		}
+	}
+	if len(errs) == len(clients) {
+		return fmt.Errorf("failed to %s APIServiceBinding %s: %v", action, o.name, errs)
+	}
+	for _, err := range errs {
+		fmt.Fprintf(o.Options.ErrOut, "Warning: %v\n", err) // nolint: errcheck
+	}
+	fmt.Fprintf(o.Options.Out, "Called %s for APIServiceBinding %s on %d of %d konnector pods.\n", action, o.name, len(clients)-len(errs), len(clients)) // nolint: errcheck
+	return nil
+}
+
+// RunDiagnostics prints the diagnostics of every konnector pod as YAML.
+func (o *KonnectorOptions) RunDiagnostics(ctx context.Context) error {
+	clients, err := o.clients(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, c := range clients {
+		diagnostics, err := c.client.Diagnostics(ctx)
+		if err != nil {
+			fmt.Fprintf(o.Options.ErrOut, "failed to get diagnostics of pod %s: %v\n", c.pod, err) // nolint: errcheck
+			continue
+		}
+		bs, err := yaml.Marshal(map[string]interface{}{"pod": c.pod, "diagnostics": diagnostics})
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(o.Options.Out, "---") // nolint: errcheck
+		}
+		fmt.Fprint(o.Options.Out, string(bs)) // nolint: errcheck
+	}
+	return nil
+}
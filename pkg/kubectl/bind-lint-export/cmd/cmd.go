@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/exec"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	"github.com/kube-bind/kube-bind/pkg/kubectl/bind-lint-export/plugin"
+)
+
+var (
+	lintExportExampleUses = `
+	# check a CustomResourceDefinition of the service provider cluster before exporting it.
+	%[1]s lint-export mangodbs.mangodb.com
+
+	# check APIServiceExports and CustomResourceDefinitions in files, e.g. in CI.
+	%[1]s lint-export -f apiserviceexport.yaml -f crds.yaml
+
+	# check a cluster-scoped CustomResourceDefinition for consumers with cluster-wide access.
+	%[1]s lint-export widgets.example.com --consumer-scope Cluster
+	`
+)
+
+func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
+	opts := plugin.NewLintExportOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "lint-export [<crd-name>...]",
+		Short:        "Check APIServiceExports and CustomResourceDefinitions for issues that keep consumers from binding them",
+		Example:      fmt.Sprintf(lintExportExampleUses, "kubectl bind"),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := logsv1.ValidateAndApply(opts.Logs, nil); err != nil {
+				return err
+			}
+
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+
+			return opts.Run(cmd.Context())
+		},
+	}
+	opts.AddCmdFlags(cmd)
+
+	return cmd, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"sigs.k8s.io/yaml"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// maxExportSize is the size above which the schemas of an APIServiceExport
+// cannot be stored anymore, neither as APIServiceExport nor as CRD on the
+// consumer cluster. It matches the default request size limit of etcd.
+const maxExportSize = 1536 * 1024
+
+// Severity is how severe a Finding is.
+type Severity string
+
+const (
+	// SeverityError means consumers cannot bind the APIServiceExport.
+	SeverityError Severity = "Error"
+	// SeverityWarning means consumers can bind the APIServiceExport, but
+	// something will likely not work as expected.
+	SeverityWarning Severity = "Warning"
+)
+
+// Finding is a bindability issue of an APIServiceExport.
+type Finding struct {
+	Severity Severity
+	// Version is the version of the APIServiceExport the finding is about,
+	// or empty if it is about the whole APIServiceExport.
+	Version string
+	Message string
+}
+
+// Lint checks an APIServiceExport for issues that keep consumers from binding
+// it. Schemas larger than maxSchemaSize bytes are reported as warning, 0 disables
+// that check.
+func Lint(export *kubebindv1alpha1.APIServiceExport, maxSchemaSize int) []Finding {
+	var findings []Finding
+	errorf := func(version, format string, args ...interface{}) {
+		findings = append(findings, Finding{Severity: SeverityError, Version: version, Message: fmt.Sprintf(format, args...)})
+	}
+	warnf := func(version, format string, args ...interface{}) {
+		findings = append(findings, Finding{Severity: SeverityWarning, Version: version, Message: fmt.Sprintf(format, args...)})
+	}
+
+	spec := export.Spec
+	switch spec.InformerScope {
+	case kubebindv1alpha1.ClusterScope, kubebindv1alpha1.NamespacedScope:
+	case "":
+		errorf("", "informerScope is required")
+	default:
+		errorf("", "unknown informerScope %q", spec.InformerScope)
+	}
+	if spec.Scope == apiextensionsv1.ClusterScoped {
+		if spec.InformerScope != kubebindv1alpha1.ClusterScope {
+			errorf("", "cluster-scoped resources can only be exported with informerScope %q", kubebindv1alpha1.ClusterScope)
+		}
+		if spec.Isolation == kubebindv1alpha1.ObjectIsolation {
+			errorf("", "cluster-scoped resources cannot be exported with isolation %q", kubebindv1alpha1.ObjectIsolation)
+		}
+	}
+
+	if len(spec.Versions) == 0 {
+		errorf("", "no served versions")
+		return findings
+	}
+	storage := 0
+	size := 0
+	for i := range spec.Versions {
+		version := &spec.Versions[i]
+		if version.Storage {
+			storage++
+		}
+
+		raw := version.Schema.OpenAPIV3Schema.Raw
+		size += len(raw)
+		if maxSchemaSize > 0 && len(raw) > maxSchemaSize {
+			warnf(version.Name, "schema has %d bytes, more than the recommended maximum of %d bytes", len(raw), maxSchemaSize)
+		}
+
+		var hasStatus bool
+		if len(raw) == 0 {
+			errorf(version.Name, "no OpenAPI v3 schema")
+		} else {
+			var schema apiextensionsv1.JSONSchemaProps
+			if err := yaml.Unmarshal(raw, &schema); err != nil {
+				errorf(version.Name, "invalid OpenAPI v3 schema: %v", err)
+			} else if err := validateStructural(&schema); err != nil {
+				errorf(version.Name, "schema is not structural: %v", err)
+			}
+			_, hasStatus = schema.Properties["status"]
+		}
+
+		if version.Subresources.Status == nil {
+			if hasStatus {
+				errorf(version.Name, "schema has a status, but no status subresource, so the status cannot be synced to the consumer")
+			} else {
+				warnf(version.Name, "no status subresource, so the service provider cannot report status to the consumer")
+			}
+		}
+	}
+	if storage != 1 {
+		errorf("", "exactly one version must be the storage version, found %d", storage)
+	}
+	if size > maxExportSize {
+		errorf("", "schemas have %d bytes in total, more than the object size limit of %d bytes", size, maxExportSize)
+	}
+
+	return findings
+}
+
+func validateStructural(schema *apiextensionsv1.JSONSchemaProps) error {
+	var internal apiextensions.JSONSchemaProps
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(schema, &internal, nil); err != nil {
+		return err
+	}
+	s, err := structuralschema.NewStructural(&internal)
+	if err != nil {
+		return err
+	}
+	if errs := structuralschema.ValidateStructural(nil, s); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestLint(t *testing.T) {
+	const structural = `{"type":"object","properties":{"spec":{"type":"object"},"status":{"type":"object"}}}`
+	withStatus := apiextensionsv1.CustomResourceSubresources{Status: &apiextensionsv1.CustomResourceSubresourceStatus{}}
+
+	tests := []struct {
+		name    string
+		mutate  func(export *kubebindv1alpha1.APIServiceExport)
+		want    []Finding
+		maxSize int
+	}{
+		{name: "bindable"},
+		{
+			name: "non-structural schema",
+			mutate: func(export *kubebindv1alpha1.APIServiceExport) {
+				export.Spec.Versions[0].Schema.OpenAPIV3Schema.Raw = []byte(`{"type":"object","properties":{"spec":{}}}`)
+			},
+			want: []Finding{{Severity: SeverityError, Version: "v1alpha1", Message: "schema is not structural: properties[spec].type: Required value: must not be empty for specified object fields"}},
+		},
+		{
+			name: "missing schema",
+			mutate: func(export *kubebindv1alpha1.APIServiceExport) {
+				export.Spec.Versions[0].Schema.OpenAPIV3Schema.Raw = nil
+			},
+			want: []Finding{{Severity: SeverityError, Version: "v1alpha1", Message: "no OpenAPI v3 schema"}},
+		},
+		{
+			name: "status without subresource",
+			mutate: func(export *kubebindv1alpha1.APIServiceExport) {
+				export.Spec.Versions[0].Subresources = apiextensionsv1.CustomResourceSubresources{}
+			},
+			want: []Finding{{Severity: SeverityError, Version: "v1alpha1", Message: "schema has a status, but no status subresource, so the status cannot be synced to the consumer"}},
+		},
+		{
+			name: "no status at all",
+			mutate: func(export *kubebindv1alpha1.APIServiceExport) {
+				export.Spec.Versions[0].Schema.OpenAPIV3Schema.Raw = []byte(`{"type":"object"}`)
+				export.Spec.Versions[0].Subresources = apiextensionsv1.CustomResourceSubresources{}
+			},
+			want: []Finding{{Severity: SeverityWarning, Version: "v1alpha1", Message: "no status subresource, so the service provider cannot report status to the consumer"}},
+		},
+		{
+			name: "cluster-scoped with namespaced informers",
+			mutate: func(export *kubebindv1alpha1.APIServiceExport) {
+				export.Spec.Scope = apiextensionsv1.ClusterScoped
+			},
+			want: []Finding{{Severity: SeverityError, Message: `cluster-scoped resources can only be exported with informerScope "Cluster"`}},
+		},
+		{
+			name: "cluster-scoped with object isolation",
+			mutate: func(export *kubebindv1alpha1.APIServiceExport) {
+				export.Spec.Scope = apiextensionsv1.ClusterScoped
+				export.Spec.InformerScope = kubebindv1alpha1.ClusterScope
+				export.Spec.Isolation = kubebindv1alpha1.ObjectIsolation
+			},
+			want: []Finding{{Severity: SeverityError, Message: `cluster-scoped resources cannot be exported with isolation "Object"`}},
+		},
+		{
+			name:    "large schema",
+			maxSize: 32,
+			want:    []Finding{{Severity: SeverityWarning, Version: "v1alpha1", Message: "schema has 84 bytes, more than the recommended maximum of 32 bytes"}},
+		},
+		{
+			name: "oversized schemas",
+			mutate: func(export *kubebindv1alpha1.APIServiceExport) {
+				export.Spec.Versions[0].Schema.OpenAPIV3Schema.Raw = []byte(`{"type":"object","description":"` + strings.Repeat("x", maxExportSize) + `","properties":{"status":{"type":"object"}}}`)
+			},
+			want: []Finding{{Severity: SeverityError, Message: "schemas have 1572940 bytes in total, more than the object size limit of 1572864 bytes"}},
+		},
+		{
+			name: "no storage version",
+			mutate: func(export *kubebindv1alpha1.APIServiceExport) {
+				export.Spec.Versions[0].Storage = false
+			},
+			want: []Finding{{Severity: SeverityError, Message: "exactly one version must be the storage version, found 0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export := &kubebindv1alpha1.APIServiceExport{
+				Spec: kubebindv1alpha1.APIServiceExportSpec{
+					APIServiceExportCRDSpec: kubebindv1alpha1.APIServiceExportCRDSpec{
+						Group: "mangodb.com",
+						Scope: apiextensionsv1.NamespaceScoped,
+						Versions: []kubebindv1alpha1.APIServiceExportVersion{{
+							Name:         "v1alpha1",
+							Served:       true,
+							Storage:      true,
+							Schema:       kubebindv1alpha1.APIServiceExportSchema{OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(structural)}},
+							Subresources: withStatus,
+						}},
+					},
+					InformerScope: kubebindv1alpha1.NamespacedScope,
+				},
+			}
+			if tt.mutate != nil {
+				tt.mutate(export)
+			}
+			require.Equal(t, tt.want, Lint(export, tt.maxSize))
+		})
+	}
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+	"sigs.k8s.io/yaml"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// LintExportOptions are the options for the kubectl-bind-lint-export command.
+type LintExportOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// Files are the files to read APIServiceExports and CustomResourceDefinitions
+	// from instead of the cluster. "-" reads from stdin.
+	Files []string
+	// InformerScope is the informerScope CustomResourceDefinitions are checked
+	// with, Cluster or Namespaced.
+	InformerScope string
+	// MaxSchemaSize is the schema size in bytes per version above which a
+	// warning is reported.
+	MaxSchemaSize int
+
+	names []string
+}
+
+// NewLintExportOptions returns new LintExportOptions.
+func NewLintExportOptions(streams genericclioptions.IOStreams) *LintExportOptions {
+	return &LintExportOptions{
+		Options: base.NewOptions(streams),
+		Logs:    logs.NewOptions(),
+
+		InformerScope: string(kubebindv1alpha1.NamespacedScope),
+		MaxSchemaSize: 256 * 1024,
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (o *LintExportOptions) AddCmdFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	logsv1.AddFlags(o.Logs, cmd.Flags())
+
+	cmd.Flags().StringSliceVarP(&o.Files, "file", "f", o.Files, "Read APIServiceExports and CustomResourceDefinitions from the given files instead of the cluster. Use - for stdin.")
+	cmd.Flags().StringVar(&o.InformerScope, "consumer-scope", o.InformerScope, "How consumers access the service provider cluster, Namespaced or Cluster. Only used for CustomResourceDefinitions.")
+	cmd.Flags().IntVar(&o.MaxSchemaSize, "max-schema-size", o.MaxSchemaSize, "The schema size in bytes per version above which a warning is reported. 0 disables the check.")
+}
+
+// Complete ensures all fields are initialized.
+func (o *LintExportOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	o.names = args
+	switch strings.ToLower(o.InformerScope) {
+	case "namespaced":
+		o.InformerScope = string(kubebindv1alpha1.NamespacedScope)
+	case "cluster":
+		o.InformerScope = string(kubebindv1alpha1.ClusterScope)
+	}
+	return nil
+}
+
+// Validate validates the LintExportOptions are complete and usable.
+func (o *LintExportOptions) Validate() error {
+	if len(o.names) == 0 && len(o.Files) == 0 {
+		return errors.New("CustomResourceDefinition names or --file is required")
+	}
+	if len(o.names) > 0 && len(o.Files) > 0 {
+		return errors.New("CustomResourceDefinition names and --file are mutually exclusive")
+	}
+	switch kubebindv1alpha1.Scope(o.InformerScope) {
+	case kubebindv1alpha1.NamespacedScope, kubebindv1alpha1.ClusterScope:
+	default:
+		return fmt.Errorf("--consumer-scope must be %q or %q", kubebindv1alpha1.NamespacedScope, kubebindv1alpha1.ClusterScope)
+	}
+	if o.MaxSchemaSize < 0 {
+		return errors.New("--max-schema-size must not be negative")
+	}
+
+	return o.Options.Validate()
+}
+
+// Run lints the APIServiceExports and CustomResourceDefinitions and fails if
+// any of them cannot be bound by consumers.
+func (o *LintExportOptions) Run(ctx context.Context) error {
+	exports, err := o.getExports(ctx)
+	if err != nil {
+		return err
+	}
+
+	broken := 0
+	for _, export := range exports {
+		findings := Lint(export, o.MaxSchemaSize)
+		if len(findings) == 0 {
+			fmt.Fprintf(o.Options.Out, "✅ %s is bindable\n", export.Name) // nolint: errcheck
+			continue
+		}
+
+		hasErrors := false
+		for _, f := range findings {
+			icon := "⚠️ "
+			if f.Severity == SeverityError {
+				icon = "❌"
+				hasErrors = true
+			}
+			subject := export.Name
+			if f.Version != "" {
+				subject += " " + f.Version
+			}
+			fmt.Fprintf(o.Options.Out, "%s %s: %s\n", icon, subject, f.Message) // nolint: errcheck
+		}
+		if hasErrors {
+			broken++
+		}
+	}
+
+	if broken > 0 {
+		return fmt.Errorf("%d of %d APIServiceExports cannot be bound", broken, len(exports))
+	}
+	return nil
+}
+
+func (o *LintExportOptions) getExports(ctx context.Context) ([]*kubebindv1alpha1.APIServiceExport, error) {
+	var exports []*kubebindv1alpha1.APIServiceExport
+	if len(o.Files) > 0 {
+		for _, file := range o.Files {
+			var bs []byte
+			var err error
+			if file == "-" {
+				bs, err = io.ReadAll(o.Options.IOStreams.In)
+			} else {
+				bs, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			fileExports, err := o.parseExports(bs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+			}
+			exports = append(exports, fileExports...)
+		}
+		return exports, nil
+	}
+
+	config, err := o.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	apiextensionsClient, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range o.names {
+		crd, err := apiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		export, err := o.crdToExport(crd)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	return exports, nil
+}
+
+// parseExports reads all APIServiceExports and CustomResourceDefinitions of a
+// multi-document YAML file.
+func (o *LintExportOptions) parseExports(bs []byte) ([]*kubebindv1alpha1.APIServiceExport, error) {
+	var exports []*kubebindv1alpha1.APIServiceExport
+	d := kubeyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(bs)))
+	for {
+		doc, err := d.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		var meta metav1.TypeMeta
+		if err := yaml.Unmarshal(doc, &meta); err != nil {
+			return nil, err
+		}
+		switch meta.Kind {
+		case "APIServiceExport":
+			var export kubebindv1alpha1.APIServiceExport
+			if err := yaml.UnmarshalStrict(doc, &export); err != nil {
+				return nil, fmt.Errorf("failed to parse APIServiceExport: %w", err)
+			}
+			exports = append(exports, &export)
+		case "CustomResourceDefinition":
+			var crd apiextensionsv1.CustomResourceDefinition
+			if err := yaml.UnmarshalStrict(doc, &crd); err != nil {
+				return nil, fmt.Errorf("failed to parse CustomResourceDefinition: %w", err)
+			}
+			export, err := o.crdToExport(&crd)
+			if err != nil {
+				return nil, err
+			}
+			exports = append(exports, export)
+		default:
+			return nil, fmt.Errorf("unsupported kind %q, expected APIServiceExport or CustomResourceDefinition", meta.Kind)
+		}
+	}
+	return exports, nil
+}
+
+// crdToExport returns the APIServiceExport a service provider would publish
+// for the CustomResourceDefinition.
+func (o *LintExportOptions) crdToExport(crd *apiextensionsv1.CustomResourceDefinition) (*kubebindv1alpha1.APIServiceExport, error) {
+	spec, err := kubebindhelpers.CRDToServiceExport(crd)
+	if err != nil {
+		return nil, err
+	}
+	return &kubebindv1alpha1.APIServiceExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: crd.Name,
+		},
+		Spec: kubebindv1alpha1.APIServiceExportSpec{
+			APIServiceExportCRDSpec: *spec,
+			InformerScope:           kubebindv1alpha1.Scope(o.InformerScope),
+		},
+	}, nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/util/homedir"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+// ListOptions are the options for the kubectl-bind-list command.
+type ListOptions struct {
+	genericclioptions.IOStreams
+	Logs  *logs.Options
+	Print *genericclioptions.PrintFlags
+
+	// CacheDir is where the catalog and its ETag are cached between calls. Empty
+	// disables the cache.
+	CacheDir string
+
+	// Client is the HTTP client to talk to the backend. It can be replaced in tests.
+	Client *http.Client
+
+	url string
+}
+
+// NewListOptions returns new ListOptions.
+func NewListOptions(streams genericclioptions.IOStreams) *ListOptions {
+	return &ListOptions{
+		IOStreams: streams,
+		Logs:      logs.NewOptions(),
+		Print:     genericclioptions.NewPrintFlags("").WithDefaultOutput(""),
+		CacheDir:  filepath.Join(homedir.HomeDir(), ".kube", "cache", "kube-bind", "catalog"),
+		Client:    http.DefaultClient,
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (o *ListOptions) AddCmdFlags(cmd *cobra.Command) {
+	logsv1.AddFlags(o.Logs, cmd.Flags())
+	o.Print.AddFlags(cmd)
+
+	cmd.Flags().StringVar(&o.CacheDir, "cache-dir", o.CacheDir, "Directory to cache the catalog in. The backend only sends it again when it changed. Set to empty to disable.")
+}
+
+// Complete ensures all fields are initialized.
+func (o *ListOptions) Complete(args []string) error {
+	if len(args) > 0 {
+		o.url = args[0]
+	}
+	return nil
+}
+
+// Validate validates the ListOptions are complete and usable.
+func (o *ListOptions) Validate() error {
+	if o.url == "" {
+		return errors.New("url is required")
+	}
+	if _, err := url.Parse(o.url); err != nil {
+		return fmt.Errorf("invalid url %q: %w", o.url, err)
+	}
+	if allowed := sets.NewString(o.Print.AllowedFormats()...); *o.Print.OutputFormat != "" && !allowed.Has(*o.Print.OutputFormat) {
+		return fmt.Errorf("invalid output format %q (allowed: %s)", *o.Print.OutputFormat, strings.Join(allowed.List(), ", "))
+	}
+	return nil
+}
+
+// Run fetches the catalog of the service provider and prints it.
+func (o *ListOptions) Run(ctx context.Context) error {
+	catalog, err := o.getCatalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *o.Print.OutputFormat != "" {
+		printer, err := o.Print.ToPrinter()
+		if err != nil {
+			return err
+		}
+		return printer.PrintObj(catalog, o.Out)
+	}
+
+	if len(catalog.Resources) == 0 {
+		fmt.Fprintf(o.ErrOut, "No resources found.\n") // nolint: errcheck
+		return nil
+	}
+	w := tabwriter.NewWriter(o.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "RESOURCE\tGROUP\tKIND\tSCOPE\tVERSIONS\n") // nolint: errcheck
+	for _, r := range catalog.Resources {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Resource, r.Group, r.Kind, r.Scope, strings.Join(r.Versions, ",")) // nolint: errcheck
+	}
+	return w.Flush()
+}
+
+// getCatalog fetches the catalog next to the given backend URL, e.g.
+// https://mangodb.com/catalog for https://mangodb.com/export.
+func (o *ListOptions) getCatalog(ctx context.Context) (*kubebindv1alpha1.APIServiceCatalog, error) {
+	backendURL, err := url.Parse(o.url)
+	if err != nil {
+		return nil, err
+	}
+	u := backendURL.ResolveReference(&url.URL{Path: "catalog"})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	cached := o.readCache(u.String())
+	if cached != nil {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		body = cached.Data
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to get catalog from %s: %s: %s", u, resp.Status, strings.TrimSpace(string(body)))
+	default:
+		o.writeCache(u.String(), &cacheEntry{ETag: resp.Header.Get("ETag"), Data: body})
+	}
+
+	var catalog kubebindv1alpha1.APIServiceCatalog
+	if err := json.Unmarshal(body, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to decode catalog from %s: %w", u, err)
+	}
+	catalog.SetGroupVersionKind(kubebindv1alpha1.SchemeGroupVersion.WithKind("APIServiceCatalog"))
+	return &catalog, nil
+}
+
+type cacheEntry struct {
+	ETag string          `json:"etag"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (o *ListOptions) cacheFile(u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return filepath.Join(o.CacheDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// readCache returns the cached catalog of the given URL, or nil.
+func (o *ListOptions) readCache(u string) *cacheEntry {
+	if o.CacheDir == "" {
+		return nil
+	}
+	bs, err := os.ReadFile(o.cacheFile(u))
+	if err != nil {
+		return nil
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(bs, &e); err != nil || e.ETag == "" {
+		return nil
+	}
+	return &e
+}
+
+// writeCache stores the catalog of the given URL. Failures are not fatal, the
+// catalog is just fetched completely next time.
+func (o *ListOptions) writeCache(u string, e *cacheEntry) {
+	if o.CacheDir == "" || e.ETag == "" {
+		return
+	}
+	bs, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(o.CacheDir, 0o750); err != nil {
+		return
+	}
+	_ = os.WriteFile(o.cacheFile(u), bs, 0o600) // nolint:errcheck
+}
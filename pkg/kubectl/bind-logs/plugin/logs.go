@@ -0,0 +1,237 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// LogsOptions are the options for the kubectl-bind-logs command.
+type LogsOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// URL is the URL of the service provider backend. It defaults to the one
+	// recorded on the kubeconfig secret of the binding, and must match it if both are set.
+	URL string
+
+	// Client is the HTTP client to talk to the backend. It can be replaced in tests.
+	Client *http.Client
+
+	name            string
+	objectNamespace string
+	objectName      string
+}
+
+// NewLogsOptions returns new LogsOptions.
+func NewLogsOptions(streams genericclioptions.IOStreams) *LogsOptions {
+	return &LogsOptions{
+		Options: base.NewOptions(streams),
+		Logs:    logs.NewOptions(),
+		Client:  http.DefaultClient,
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (l *LogsOptions) AddCmdFlags(cmd *cobra.Command) {
+	l.Options.BindFlags(cmd)
+	logsv1.AddFlags(l.Logs, cmd.Flags())
+
+	cmd.Flags().StringVar(&l.URL, "url", l.URL, "The URL of the service provider backend. Defaults to the one recorded by \"kubectl bind\", and must match it.")
+}
+
+// Complete ensures all fields are initialized.
+func (l *LogsOptions) Complete(args []string) error {
+	if err := l.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		l.name = args[0]
+	}
+	if len(args) > 1 {
+		if parts := strings.SplitN(args[1], "/", 2); len(parts) == 2 {
+			l.objectNamespace, l.objectName = parts[0], parts[1]
+		} else {
+			l.objectName = args[1]
+		}
+	}
+	return nil
+}
+
+// Validate validates the LogsOptions are complete and usable.
+func (l *LogsOptions) Validate() error {
+	if l.name == "" {
+		return errors.New("APIServiceBinding name is required")
+	}
+
+	return l.Options.Validate()
+}
+
+// Run prints the service provider's events about objects of the given APIServiceBinding.
+// They are fetched from the service provider backend, which authorizes the request with
+// the token of the binding's kubeconfig and only returns events of the consumer.
+func (l *LogsOptions) Run(ctx context.Context) error {
+	config, err := l.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	bindClient, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubeclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	binding, err := bindClient.KubeBindV1alpha1().APIServiceBindings().Get(ctx, l.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	ref := binding.Spec.KubeconfigSecretRef
+	secret, err := kubeClient.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	kubeconfig, found := secret.Data[ref.Key]
+	if !found {
+		return fmt.Errorf("secret %s/%s does not contain key %q", ref.Namespace, ref.Name, ref.Key)
+	}
+	remoteConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	backendURL, err := base.SecretBackendURL(secret, l.URL, l.Options.ErrOut)
+	if err != nil {
+		return err
+	}
+
+	events, err := l.fetchEvents(ctx, backendURL, remoteConfig.BearerToken, helpers.ExportName(binding))
+	if err != nil {
+		return err
+	}
+	return l.printEvents(events)
+}
+
+// fetchEvents gets the events of the consumer about objects of the given export
+// from the backend.
+func (l *LogsOptions) fetchEvents(ctx context.Context, backendURL, token, export string) ([]corev1.Event, error) {
+	if token == "" {
+		return nil, errors.New("service provider kubeconfig does not contain a bearer token")
+	}
+	u, err := url.Parse(backendURL)
+	if err != nil {
+		return nil, err
+	}
+	u = u.ResolveReference(&url.URL{Path: "events"})
+	query := url.Values{"export": []string{export}}
+	if l.objectNamespace != "" {
+		query.Set("namespace", l.objectNamespace)
+	}
+	if l.objectName != "" {
+		query.Set("name", l.objectName)
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s: %s", u.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var list corev1.EventList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("invalid response from service provider: %w", err)
+	}
+	return list.Items, nil
+}
+
+func (l *LogsOptions) printEvents(events []corev1.Event) error {
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+
+	if len(events) == 0 {
+		fmt.Fprintf(l.Options.ErrOut, "No events found.\n") // nolint: errcheck
+		return nil
+	}
+
+	w := tabwriter.NewWriter(l.Options.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "LAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE\n") // nolint: errcheck
+	for _, e := range events {
+		object := e.InvolvedObject.Name
+		if ns := e.InvolvedObject.Namespace; ns != "" {
+			object = ns + "/" + object
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", // nolint: errcheck
+			duration.HumanDuration(time.Since(eventTime(e))),
+			e.Type,
+			e.Reason,
+			object,
+			strings.TrimSpace(e.Message),
+		)
+	}
+	return w.Flush()
+}
+
+func eventTime(e corev1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestFetchEvents(t *testing.T) {
+	var gotAuth, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/events", r.URL.Path)
+		gotAuth = r.Header.Get("Authorization")
+		gotQuery = r.URL.RawQuery
+		if gotAuth != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		bs, err := json.Marshal(corev1.EventList{Items: []corev1.Event{
+			{InvolvedObject: corev1.ObjectReference{Kind: "Foo", Namespace: "default", Name: "one"}, Reason: "Provisioned"},
+		}})
+		require.NoError(t, err)
+		w.Write(bs) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	t.Run("success", func(t *testing.T) {
+		l := NewLogsOptions(genericclioptions.NewTestIOStreamsDiscard())
+		l.objectNamespace, l.objectName = "default", "one"
+
+		events, err := l.fetchEvents(context.Background(), server.URL+"/", "token", "foos.example.com")
+		require.NoError(t, err)
+		require.Equal(t, "Bearer token", gotAuth)
+		require.Equal(t, "export=foos.example.com&name=one&namespace=default", gotQuery)
+		require.Len(t, events, 1)
+		require.Equal(t, "one", events[0].InvolvedObject.Name)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		l := NewLogsOptions(genericclioptions.NewTestIOStreamsDiscard())
+		_, err := l.fetchEvents(context.Background(), server.URL, "wrong", "foos.example.com")
+		require.ErrorContains(t, err, "401")
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		l := NewLogsOptions(genericclioptions.NewTestIOStreamsDiscard())
+		_, err := l.fetchEvents(context.Background(), server.URL, "", "foos.example.com")
+		require.Error(t, err)
+	})
+}
+
+func TestPrintEvents(t *testing.T) {
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	l := NewLogsOptions(streams)
+
+	now := time.Now()
+	err := l.printEvents([]corev1.Event{
+		{InvolvedObject: corev1.ObjectReference{Namespace: "default", Name: "two"}, Reason: "Second", LastTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+		{InvolvedObject: corev1.ObjectReference{Name: "cluster-wide"}, Reason: "First", LastTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+	})
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	require.Contains(t, string(lines[1]), "cluster-wide")
+	require.Contains(t, string(lines[2]), "default/two")
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+	"github.com/kube-bind/kube-bind/pkg/connection"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// RotateCredentialsOptions are the options for the kubectl-bind-rotate-credentials command.
+type RotateCredentialsOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// URL is the URL of the service provider backend, e.g. the one passed to "kubectl bind".
+	// It defaults to the one recorded on the kubeconfig secret, and must match it if both are set.
+	URL string
+	// SkipRevoke keeps the old credentials valid after rotation.
+	SkipRevoke bool
+
+	// Client is the HTTP client to talk to the backend. It can be replaced in tests.
+	Client *http.Client
+
+	name string
+}
+
+// NewRotateCredentialsOptions returns new RotateCredentialsOptions.
+func NewRotateCredentialsOptions(streams genericclioptions.IOStreams) *RotateCredentialsOptions {
+	return &RotateCredentialsOptions{
+		Options: base.NewOptions(streams),
+		Logs:    logs.NewOptions(),
+		Client:  http.DefaultClient,
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (o *RotateCredentialsOptions) AddCmdFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	logsv1.AddFlags(o.Logs, cmd.Flags())
+
+	cmd.Flags().StringVar(&o.URL, "url", o.URL, "The URL of the service provider backend, e.g. the one passed to \"kubectl bind\". Defaults to the one recorded by \"kubectl bind\", and must match it.")
+	cmd.Flags().BoolVar(&o.SkipRevoke, "skip-revoke", o.SkipRevoke, "Do not revoke the old credentials after rotation.")
+}
+
+// Complete ensures all fields are initialized.
+func (o *RotateCredentialsOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.name = args[0]
+	}
+	return nil
+}
+
+// Validate validates the RotateCredentialsOptions are complete and usable.
+func (o *RotateCredentialsOptions) Validate() error {
+	if o.name == "" {
+		return errors.New("APIServiceBinding name is required")
+	}
+	if o.URL != "" {
+		if _, err := url.Parse(o.URL); err != nil {
+			return fmt.Errorf("invalid url %q: %w", o.URL, err)
+		}
+	}
+
+	return o.Options.Validate()
+}
+
+// Run rotates the credentials of the given APIServiceBinding. The kubeconfig secret
+// is swapped with a single update, the new credentials are verified against the
+// service provider cluster, and only then the old credentials are revoked. If the
+// verification fails, the old kubeconfig is restored.
+func (o *RotateCredentialsOptions) Run(ctx context.Context) error {
+	config, err := o.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	bindClient, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubeclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	binding, err := bindClient.KubeBindV1alpha1().APIServiceBindings().Get(ctx, o.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	ref := binding.Spec.KubeconfigSecretRef
+	secret, err := kubeClient.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	oldKubeconfig, found := secret.Data[ref.Key]
+	if !found {
+		return fmt.Errorf("secret %s/%s does not contain key %q", ref.Namespace, ref.Name, ref.Key)
+	}
+	// never send the token to a backend other than the one that issued it
+	backendURL, err := base.SecretBackendURL(secret, o.URL, o.Options.ErrOut)
+	if err != nil {
+		return err
+	}
+	oldHost, oldNamespace, err := base.ParseRemoteKubeconfig(oldKubeconfig)
+	if err != nil {
+		return err
+	}
+	oldConfig, err := clientcmd.RESTConfigFromKubeConfig(oldKubeconfig)
+	if err != nil {
+		return err
+	}
+
+	// request fresh credentials
+	newKubeconfig, err := o.post(ctx, backendURL, "credentials/rotate", oldConfig.BearerToken)
+	if err != nil {
+		return fmt.Errorf("failed to rotate credentials: %w", err)
+	}
+	newHost, newNamespace, err := base.ParseRemoteKubeconfig(newKubeconfig)
+	if err != nil {
+		return fmt.Errorf("invalid kubeconfig from service provider: %w", err)
+	}
+	// during a control-plane migration, the new primary endpoint was announced as fallback before
+	sameProvider, err := sharesEndpoint(oldKubeconfig, newKubeconfig)
+	if err != nil {
+		return fmt.Errorf("invalid kubeconfig from service provider: %w", err)
+	}
+	if !sameProvider || newNamespace != oldNamespace {
+		return fmt.Errorf("service provider returned a kubeconfig for host %s, namespace %s, expected host %s or one of its fallback endpoints, namespace %s", newHost, newNamespace, oldHost, oldNamespace)
+	}
+	newConfig, err := clientcmd.RESTConfigFromKubeConfig(newKubeconfig)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Options.ErrOut, "🔑 Received new credentials for host %s, namespace %s\n", newHost, newNamespace) // nolint: errcheck
+
+	// swap the secret
+	if err := o.updateKubeconfig(ctx, kubeClient, &ref, newKubeconfig); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Options.ErrOut, "🔒 Updated secret %s/%s\n", ref.Namespace, ref.Name) // nolint: errcheck
+
+	// verify the new credentials
+	newBindClient, err := bindclient.NewForConfig(newConfig)
+	if err != nil {
+		return err
+	}
+	if status := connection.Check(ctx, newBindClient, newNamespace); status.AuthStatus != kubebindv1alpha1.ConnectionAuthorized {
+		fmt.Fprintf(o.Options.ErrOut, "❌ Connection with new credentials failed: %s. Restoring old credentials.\n", status.AuthStatus) // nolint: errcheck
+		if err := o.updateKubeconfig(ctx, kubeClient, &ref, oldKubeconfig); err != nil {
+			return fmt.Errorf("failed to restore old credentials: %w", err)
+		}
+		return errors.New(status.Message)
+	}
+	fmt.Fprintf(o.Options.ErrOut, "✅ Connection with new credentials succeeded\n") // nolint: errcheck
+
+	if o.SkipRevoke {
+		return nil
+	}
+
+	// revoke the old credentials
+	if _, err := o.post(ctx, backendURL, "credentials/revoke", newConfig.BearerToken); err != nil {
+		return fmt.Errorf("failed to revoke old credentials: %w", err)
+	}
+	fmt.Fprintf(o.Options.ErrOut, "🗑  Revoked old credentials\n") // nolint: errcheck
+
+	return nil
+}
+
+// sharesEndpoint returns whether the kubeconfigs have a primary or fallback
+// endpoint in common.
+func sharesEndpoint(a, b []byte) (bool, error) {
+	aEndpoints, err := helpers.ProviderRESTConfigs(a)
+	if err != nil {
+		return false, err
+	}
+	bEndpoints, err := helpers.ProviderRESTConfigs(b)
+	if err != nil {
+		return false, err
+	}
+	hosts := sets.NewString()
+	for _, config := range aEndpoints {
+		hosts.Insert(config.Host)
+	}
+	for _, config := range bEndpoints {
+		if hosts.Has(config.Host) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (o *RotateCredentialsOptions) updateKubeconfig(ctx context.Context, kubeClient kubeclient.Interface, ref *kubebindv1alpha1.ClusterSecretKeyRef, kubeconfig []byte) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := kubeClient.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		secret.Data[ref.Key] = kubeconfig
+		_, err = kubeClient.CoreV1().Secrets(ref.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// post sends a POST request authenticated with the given token to the given
+// path relative to the backend URL.
+func (o *RotateCredentialsOptions) post(ctx context.Context, backend, path, token string) ([]byte, error) {
+	if token == "" {
+		return nil, errors.New("service provider kubeconfig does not contain a bearer token")
+	}
+	backendURL, err := url.Parse(backend)
+	if err != nil {
+		return nil, err
+	}
+	u := backendURL.ResolveReference(&url.URL{Path: path})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", u, resp.Status)
+	}
+	return body, nil
+}
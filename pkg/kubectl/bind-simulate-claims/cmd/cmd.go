@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/exec"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	"github.com/kube-bind/kube-bind/pkg/kubectl/bind-simulate-claims/plugin"
+)
+
+var (
+	simulateClaimsExampleUses = `
+	# report which objects of the current (test) cluster the konnector would sync for the claims of an export.
+	%[1]s simulate-claims -f export.yaml
+
+	# simulate against a snapshot of a consumer cluster and the claims the consumer accepted.
+	kubectl get storageclasses,nodes -o yaml > snapshot.yaml
+	%[1]s simulate-claims -f export.yaml --snapshot snapshot.yaml --binding binding.yaml
+
+	# report the claimed objects as JSON.
+	%[1]s simulate-claims -f export.yaml --snapshot snapshots/ -o json
+	`
+)
+
+func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
+	opts := plugin.NewSimulateClaimsOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "simulate-claims",
+		Short:        "Report which consumer objects the konnector would sync for the claims of APIServiceExports",
+		Example:      fmt.Sprintf(simulateClaimsExampleUses, "kubectl bind"),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := logsv1.ValidateAndApply(opts.Logs, nil); err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				return cmd.Help()
+			}
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+
+			return opts.Run(cmd.Context())
+		},
+	}
+	opts.AddCmdFlags(cmd)
+
+	return cmd, nil
+}
@@ -0,0 +1,380 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+	"sigs.k8s.io/yaml"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// statusBytesWarning is the size of claimed objects above which the summary warns
+// about the object size limit of the APIServiceExport (1.5 MiB by default in etcd).
+const statusBytesWarning = 1 << 20
+
+// SimulateClaimsOptions are the options for the kubectl-bind-simulate-claims command.
+type SimulateClaimsOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// Files are the files with the APIServiceExports to simulate. "-" reads from stdin.
+	Files []string
+	// Snapshots are files or directories with the objects of a consumer cluster.
+	// If empty, the consumer cluster of the kubeconfig is read.
+	Snapshots []string
+	// Bindings are files with APIServiceBindings of the consumer. Exports without
+	// a binding are simulated with all claims accepted.
+	Bindings []string
+	// Output is the output format: empty for a summary, json or yaml.
+	Output string
+}
+
+// NewSimulateClaimsOptions returns new SimulateClaimsOptions.
+func NewSimulateClaimsOptions(streams genericclioptions.IOStreams) *SimulateClaimsOptions {
+	return &SimulateClaimsOptions{
+		Options: base.NewOptions(streams),
+		Logs:    logs.NewOptions(),
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (o *SimulateClaimsOptions) AddCmdFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	logsv1.AddFlags(o.Logs, cmd.Flags())
+
+	cmd.Flags().StringSliceVarP(&o.Files, "file", "f", o.Files, "Files with the APIServiceExports whose claims are simulated. Use - for stdin.")
+	cmd.Flags().StringSliceVar(&o.Snapshots, "snapshot", o.Snapshots, "Files or directories with YAML or JSON objects of a consumer cluster, e.g. from kubectl get storageclasses,nodes -o yaml. If not set, the consumer cluster of the kubeconfig is read, e.g. a test cluster. Nothing is written to it.")
+	cmd.Flags().StringSliceVar(&o.Bindings, "binding", o.Bindings, "Files with APIServiceBindings of the consumer to take the accepted claims from. Exports without a binding are simulated with all claims accepted.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. One of: json|yaml. Default is a summary.")
+}
+
+// Complete ensures all fields are initialized.
+func (o *SimulateClaimsOptions) Complete(args []string) error {
+	return o.Options.Complete()
+}
+
+// Validate validates the SimulateClaimsOptions are complete and usable.
+func (o *SimulateClaimsOptions) Validate() error {
+	if len(o.Files) == 0 {
+		return errors.New("--file is required")
+	}
+	if o.Output != "" && o.Output != "json" && o.Output != "yaml" {
+		return fmt.Errorf("invalid output format %q (allowed: json, yaml)", o.Output)
+	}
+
+	return o.Options.Validate()
+}
+
+// Run simulates the claims of the APIServiceExports and prints what the
+// konnector would sync.
+func (o *SimulateClaimsOptions) Run(ctx context.Context) error {
+	var exports []*kubebindv1alpha1.APIServiceExport
+	for _, file := range o.Files {
+		objs, err := o.readObjects(file)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			if obj.GetKind() != "APIServiceExport" {
+				return fmt.Errorf("unsupported kind %q in %s, expected APIServiceExport", obj.GetKind(), file)
+			}
+			var export kubebindv1alpha1.APIServiceExport
+			if err := fromUnstructured(obj, &export); err != nil {
+				return fmt.Errorf("failed to parse APIServiceExport in %s: %w", file, err)
+			}
+			exports = append(exports, &export)
+		}
+	}
+
+	bindings := map[string]*kubebindv1alpha1.APIServiceBinding{}
+	for _, file := range o.Bindings {
+		objs, err := o.readObjects(file)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			if obj.GetKind() != "APIServiceBinding" {
+				return fmt.Errorf("unsupported kind %q in %s, expected APIServiceBinding", obj.GetKind(), file)
+			}
+			var binding kubebindv1alpha1.APIServiceBinding
+			if err := fromUnstructured(obj, &binding); err != nil {
+				return fmt.Errorf("failed to parse APIServiceBinding in %s: %w", file, err)
+			}
+			bindings[kubebindhelpers.ExportName(&binding)] = &binding
+		}
+	}
+
+	var consumerObjs []*unstructured.Unstructured
+	if len(o.Snapshots) > 0 {
+		for _, snapshot := range o.Snapshots {
+			objs, err := o.readSnapshot(snapshot)
+			if err != nil {
+				return err
+			}
+			consumerObjs = append(consumerObjs, objs...)
+		}
+	} else {
+		objs, err := o.readConsumerCluster(ctx, exports)
+		if err != nil {
+			return err
+		}
+		consumerObjs = objs
+	}
+
+	var report Report
+	for _, export := range exports {
+		r, err := Simulate(export, bindings[export.Name], consumerObjs)
+		if err != nil {
+			return err
+		}
+		report.Exports = append(report.Exports, *r)
+	}
+
+	return o.print(&report)
+}
+
+// readConsumerCluster lists the objects of the claimable resources and gets the
+// ServiceAccounts claimed by the exports in the consumer cluster.
+func (o *SimulateClaimsOptions) readConsumerCluster(ctx context.Context, exports []*kubebindv1alpha1.APIServiceExport) ([]*unstructured.Unstructured, error) {
+	config, err := o.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*unstructured.Unstructured
+	listed := map[kubebindv1alpha1.ClusterScopedClaim]bool{}
+	serviceAccounts := schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	for _, export := range exports {
+		for _, claim := range export.Spec.ClusterScopedClaims {
+			claimable, ok := kubebindhelpers.Claimable(claim)
+			if !ok || listed[claim] {
+				continue
+			}
+			listed[claim] = true
+			list, err := client.Resource(schema.GroupVersionResource{Group: claim.Group, Version: claimable.Version, Resource: claim.Resource}).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s: %w", claim.Resource, err)
+			}
+			for i := range list.Items {
+				ret = append(ret, &list.Items[i])
+			}
+		}
+		for _, claim := range export.Spec.ServiceAccountTokenClaims {
+			sa, err := client.Resource(serviceAccounts).Namespace(claim.Namespace).Get(ctx, claim.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to get ServiceAccount %s/%s: %w", claim.Namespace, claim.Name, err)
+			}
+			ret = append(ret, sa)
+		}
+	}
+	return ret, nil
+}
+
+// readSnapshot reads the objects of a file, or of all YAML and JSON files of a
+// directory tree.
+func (o *SimulateClaimsOptions) readSnapshot(path string) ([]*unstructured.Unstructured, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return o.readObjects(path)
+	}
+
+	var ret []*unstructured.Unstructured
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		objs, err := o.readObjects(p)
+		if err != nil {
+			return err
+		}
+		ret = append(ret, objs...)
+		return nil
+	})
+	return ret, err
+}
+
+// readObjects reads all objects of a multi-document YAML or JSON file. Lists
+// are flattened.
+func (o *SimulateClaimsOptions) readObjects(file string) ([]*unstructured.Unstructured, error) {
+	var bs []byte
+	var err error
+	if file == "-" {
+		bs, err = io.ReadAll(o.Options.IOStreams.In)
+	} else {
+		bs, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	objs, err := parseObjects(bs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return objs, nil
+}
+
+func parseObjects(bs []byte) ([]*unstructured.Unstructured, error) {
+	var ret []*unstructured.Unstructured
+	d := kubeyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(bs)))
+	for {
+		doc, err := d.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(doc, &obj.Object); err != nil {
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if !obj.IsList() {
+			ret = append(ret, obj)
+			continue
+		}
+		if err := obj.EachListItem(func(item runtime.Object) error {
+			ret = append(ret, item.(*unstructured.Unstructured))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func fromUnstructured(obj *unstructured.Unstructured, into interface{}) error {
+	bs, err := json.Marshal(obj.Object)
+	if err != nil {
+		return err
+	}
+	return yaml.UnmarshalStrict(bs, into)
+}
+
+func (o *SimulateClaimsOptions) print(report *Report) error {
+	switch o.Output {
+	case "json":
+		bs, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(o.Options.Out, string(bs))
+		return err
+	case "yaml":
+		bs, err := yaml.Marshal(report)
+		if err != nil {
+			return err
+		}
+		_, err = o.Options.Out.Write(bs)
+		return err
+	}
+
+	out := o.Options.Out
+	for _, e := range report.Exports {
+		accepted := "with the claims accepted by the APIServiceBinding"
+		if e.AllAccepted {
+			accepted = "with all claims accepted"
+		}
+		fmt.Fprintf(out, "APIServiceExport %s, %s:\n", e.Name, accepted) // nolint: errcheck
+		if len(e.ClusterScopedClaims) == 0 && len(e.ServiceAccountTokens) == 0 {
+			fmt.Fprintf(out, "  no claims\n") // nolint: errcheck
+		}
+		for _, c := range e.ClusterScopedClaims {
+			resource := c.Resource
+			if c.Group != "" {
+				resource += "." + c.Group
+			}
+			switch c.Outcome {
+			case OutcomeNotClaimable:
+				fmt.Fprintf(out, "  ❌ %s cannot be claimed, the konnector refuses it\n", resource) // nolint: errcheck
+			case OutcomeNotAccepted:
+				fmt.Fprintf(out, "  ➖ %s is not accepted by the consumer and not synced\n", resource) // nolint: errcheck
+			default:
+				what := "full objects"
+				if c.MetadataOnly {
+					what = "names and labels"
+				}
+				fmt.Fprintf(out, "  ✅ %s: %d objects synced as %s\n", resource, len(c.Objects), what) // nolint: errcheck
+				for _, obj := range c.Objects {
+					fmt.Fprintf(out, "       %s\n", describe(obj)) // nolint: errcheck
+				}
+			}
+		}
+		for _, t := range e.ServiceAccountTokens {
+			sa := fmt.Sprintf("token of ServiceAccount %s/%s for audience %s", t.Namespace, t.Name, t.Audience)
+			switch t.Outcome {
+			case OutcomeNotAccepted:
+				fmt.Fprintf(out, "  ➖ %s is not accepted by the consumer and not issued\n", sa) // nolint: errcheck
+			case OutcomeServiceAccountNotFound:
+				fmt.Fprintf(out, "  ❌ %s cannot be issued, the ServiceAccount does not exist\n", sa) // nolint: errcheck
+			default:
+				fmt.Fprintf(out, "  ✅ %s is issued into Secret %s\n", sa, t.SecretName) // nolint: errcheck
+			}
+		}
+		if e.StatusBytes > statusBytesWarning {
+			fmt.Fprintf(out, "  ⚠️  status.claimedObjects of the APIServiceExport is %d bytes, close to the object size limit. Consider narrower or metadata-only claims.\n", e.StatusBytes) // nolint: errcheck
+		} else if e.StatusBytes > 0 {
+			fmt.Fprintf(out, "  status.claimedObjects of the APIServiceExport: %d bytes\n", e.StatusBytes) // nolint: errcheck
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+// Outcome is what the konnector does with a claim.
+type Outcome string
+
+const (
+	// OutcomeSynced means the claim is synced to the service provider.
+	OutcomeSynced Outcome = "Synced"
+	// OutcomeNotAccepted means the consumer did not accept the claim in the APIServiceBinding.
+	OutcomeNotAccepted Outcome = "NotAccepted"
+	// OutcomeNotClaimable means the konnector refuses the claim, independently of the consumer.
+	OutcomeNotClaimable Outcome = "NotClaimable"
+	// OutcomeServiceAccountNotFound means the token cannot be issued because the ServiceAccount does not exist.
+	OutcomeServiceAccountNotFound Outcome = "ServiceAccountNotFound"
+)
+
+// Report is the simulated claim enforcement of APIServiceExports against a consumer cluster.
+type Report struct {
+	Exports []ExportReport `json:"exports"`
+}
+
+// ExportReport is the simulated claim enforcement of one APIServiceExport.
+type ExportReport struct {
+	Name string `json:"name"`
+	// AllAccepted is true if no APIServiceBinding was given, and all claims are
+	// assumed to be accepted by the consumer.
+	AllAccepted          bool          `json:"allAccepted"`
+	ClusterScopedClaims  []ClaimReport `json:"clusterScopedClaims,omitempty"`
+	ServiceAccountTokens []TokenReport `json:"serviceAccountTokens,omitempty"`
+	// StatusBytes is the size of status.claimedObjects of the APIServiceExport
+	// in its JSON serialization.
+	StatusBytes int `json:"statusBytes"`
+}
+
+// ClaimReport is the simulated enforcement of one cluster-scoped claim.
+type ClaimReport struct {
+	kubebindv1alpha1.ClusterScopedClaim `json:",inline"`
+	Outcome                             Outcome `json:"outcome"`
+	// MetadataOnly means only name and labels of the objects are synced.
+	MetadataOnly bool `json:"metadataOnly,omitempty"`
+	// Objects are the consumer objects the konnector would copy into the
+	// APIServiceExport status.
+	Objects []kubebindv1alpha1.ClaimedObject `json:"objects,omitempty"`
+}
+
+// TokenReport is the simulated enforcement of one service account token claim.
+type TokenReport struct {
+	kubebindv1alpha1.ServiceAccountTokenClaim `json:",inline"`
+	Outcome                                   Outcome `json:"outcome"`
+	// SecretName is the Secret in the service provider namespace the token is written to.
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// Simulate returns what the konnector would sync for the claims of the export
+// from the given consumer objects. If binding is nil, all claims are assumed to
+// be accepted by the consumer.
+func Simulate(export *kubebindv1alpha1.APIServiceExport, binding *kubebindv1alpha1.APIServiceBinding, objs []*unstructured.Unstructured) (*ExportReport, error) {
+	allAccepted := binding == nil
+	if allAccepted {
+		binding = &kubebindv1alpha1.APIServiceBinding{Spec: kubebindv1alpha1.APIServiceBindingSpec{
+			AcceptedClaims:                    export.Spec.ClusterScopedClaims,
+			AcceptedServiceAccountTokenClaims: export.Spec.ServiceAccountTokenClaims,
+		}}
+	}
+	report := &ExportReport{Name: export.Name, AllAccepted: allAccepted}
+
+	accepted := map[kubebindv1alpha1.ClusterScopedClaim]bool{}
+	for _, claim := range kubebindhelpers.AcceptedClaims(export, binding) {
+		accepted[claim] = true
+	}
+	var claimed []kubebindv1alpha1.ClaimedObject
+	for _, claim := range export.Spec.ClusterScopedClaims {
+		cr := ClaimReport{ClusterScopedClaim: claim}
+		claimable, ok := kubebindhelpers.Claimable(claim)
+		switch {
+		case !ok:
+			cr.Outcome = OutcomeNotClaimable
+		case !accepted[claim]:
+			cr.Outcome = OutcomeNotAccepted
+		default:
+			cr.Outcome = OutcomeSynced
+			cr.MetadataOnly = claimable.MetadataOnly
+			for _, obj := range objs {
+				if obj.GroupVersionKind().GroupKind() != (schema.GroupKind{Group: claim.Group, Kind: claimable.Kind}) {
+					continue
+				}
+				c, err := kubebindhelpers.ClaimedObject(claim, obj)
+				if err != nil {
+					return nil, err
+				}
+				cr.Objects = append(cr.Objects, c)
+			}
+			sort.Slice(cr.Objects, func(i, j int) bool { return cr.Objects[i].Name < cr.Objects[j].Name })
+			claimed = append(claimed, cr.Objects...)
+		}
+		report.ClusterScopedClaims = append(report.ClusterScopedClaims, cr)
+	}
+	if len(claimed) > 0 {
+		bs, err := json.Marshal(claimed)
+		if err != nil {
+			return nil, err
+		}
+		report.StatusBytes = len(bs)
+	}
+
+	acceptedTokens := map[kubebindv1alpha1.ServiceAccountTokenClaim]bool{}
+	for _, claim := range kubebindhelpers.AcceptedServiceAccountTokenClaims(export, binding) {
+		acceptedTokens[claim] = true
+	}
+	for _, claim := range export.Spec.ServiceAccountTokenClaims {
+		tr := TokenReport{ServiceAccountTokenClaim: claim}
+		switch {
+		case !acceptedTokens[claim]:
+			tr.Outcome = OutcomeNotAccepted
+		case !hasServiceAccount(objs, claim.Namespace, claim.Name):
+			tr.Outcome = OutcomeServiceAccountNotFound
+		default:
+			tr.Outcome = OutcomeSynced
+			tr.SecretName = kubebindhelpers.ServiceAccountTokenSecretName(export.Name, claim)
+		}
+		report.ServiceAccountTokens = append(report.ServiceAccountTokens, tr)
+	}
+
+	return report, nil
+}
+
+func hasServiceAccount(objs []*unstructured.Unstructured, ns, name string) bool {
+	for _, obj := range objs {
+		if obj.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("ServiceAccount") && obj.GetNamespace() == ns && obj.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// describe returns a one-line description of a claimed object for the summary.
+func describe(c kubebindv1alpha1.ClaimedObject) string {
+	s := c.Name
+	if len(c.Labels) > 0 {
+		s += fmt.Sprintf(" %v", c.Labels)
+	}
+	if c.Object != nil {
+		s += fmt.Sprintf(" (%d bytes)", len(c.Object.Raw))
+	}
+	return s
+}
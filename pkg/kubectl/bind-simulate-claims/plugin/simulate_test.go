@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+const snapshot = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: storage.k8s.io/v1
+  kind: StorageClass
+  metadata:
+    name: standard
+    labels:
+      tier: default
+    uid: 1234
+    resourceVersion: "42"
+  provisioner: rancher.io/local-path
+- apiVersion: v1
+  kind: Node
+  metadata:
+    name: node-1
+    labels:
+      zone: a
+  spec:
+    podCIDR: 10.0.0.0/24
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  namespace: default
+  name: reader
+`
+
+func TestSimulate(t *testing.T) {
+	objs, err := parseObjects([]byte(snapshot))
+	require.NoError(t, err)
+	require.Len(t, objs, 3)
+
+	storageClasses := kubebindv1alpha1.ClusterScopedClaim{Group: "storage.k8s.io", Resource: "storageclasses"}
+	nodes := kubebindv1alpha1.ClusterScopedClaim{Resource: "nodes"}
+	namespaces := kubebindv1alpha1.ClusterScopedClaim{Resource: "namespaces"}
+	reader := kubebindv1alpha1.ServiceAccountTokenClaim{Namespace: "default", Name: "reader", Audience: "provider"}
+	missing := kubebindv1alpha1.ServiceAccountTokenClaim{Namespace: "default", Name: "missing", Audience: "provider"}
+	export := &kubebindv1alpha1.APIServiceExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com"},
+		Spec: kubebindv1alpha1.APIServiceExportSpec{
+			ClusterScopedClaims:       []kubebindv1alpha1.ClusterScopedClaim{storageClasses, nodes, namespaces},
+			ServiceAccountTokenClaims: []kubebindv1alpha1.ServiceAccountTokenClaim{reader, missing},
+		},
+	}
+
+	t.Run("all accepted", func(t *testing.T) {
+		report, err := Simulate(export, nil, objs)
+		require.NoError(t, err)
+		require.True(t, report.AllAccepted)
+		require.Len(t, report.ClusterScopedClaims, 3)
+
+		sc := report.ClusterScopedClaims[0]
+		require.Equal(t, OutcomeSynced, sc.Outcome)
+		require.False(t, sc.MetadataOnly)
+		require.Len(t, sc.Objects, 1)
+		require.Equal(t, "standard", sc.Objects[0].Name)
+		require.Equal(t, map[string]string{"tier": "default"}, sc.Objects[0].Labels)
+		require.NotNil(t, sc.Objects[0].Object)
+		require.NotContains(t, string(sc.Objects[0].Object.Raw), "resourceVersion")
+		require.Contains(t, string(sc.Objects[0].Object.Raw), "rancher.io/local-path")
+
+		node := report.ClusterScopedClaims[1]
+		require.Equal(t, OutcomeSynced, node.Outcome)
+		require.True(t, node.MetadataOnly)
+		require.Len(t, node.Objects, 1)
+		require.Equal(t, "node-1", node.Objects[0].Name)
+		require.Nil(t, node.Objects[0].Object)
+
+		require.Equal(t, OutcomeNotClaimable, report.ClusterScopedClaims[2].Outcome)
+		require.Greater(t, report.StatusBytes, 0)
+
+		require.Len(t, report.ServiceAccountTokens, 2)
+		require.Equal(t, OutcomeSynced, report.ServiceAccountTokens[0].Outcome)
+		require.NotEmpty(t, report.ServiceAccountTokens[0].SecretName)
+		require.Equal(t, OutcomeServiceAccountNotFound, report.ServiceAccountTokens[1].Outcome)
+	})
+
+	t.Run("accepted by binding", func(t *testing.T) {
+		binding := &kubebindv1alpha1.APIServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com"},
+			Spec: kubebindv1alpha1.APIServiceBindingSpec{
+				AcceptedClaims: []kubebindv1alpha1.ClusterScopedClaim{nodes},
+			},
+		}
+		report, err := Simulate(export, binding, objs)
+		require.NoError(t, err)
+		require.False(t, report.AllAccepted)
+		require.Equal(t, OutcomeNotAccepted, report.ClusterScopedClaims[0].Outcome)
+		require.Empty(t, report.ClusterScopedClaims[0].Objects)
+		require.Equal(t, OutcomeSynced, report.ClusterScopedClaims[1].Outcome)
+		require.Equal(t, OutcomeNotClaimable, report.ClusterScopedClaims[2].Outcome)
+		require.Equal(t, OutcomeNotAccepted, report.ServiceAccountTokens[0].Outcome)
+	})
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+	"sigs.k8s.io/yaml"
+
+	kubebindhelpers "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/util/conditions"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// StatusOptions are the options for the kubectl-bind-status command.
+type StatusOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// Output is the output format: empty for a summary, json or yaml.
+	Output string
+	// KonnectorNamespace is the namespace the konnector is deployed in.
+	KonnectorNamespace string
+	// LeaseName is the name of the leader election lease of the konnector.
+	LeaseName string
+}
+
+// Status is the health summary of the bindings and the konnector in the consumer cluster.
+type Status struct {
+	Bindings  []BindingStatus `json:"bindings"`
+	Konnector KonnectorStatus `json:"konnector"`
+}
+
+// BindingStatus is the readiness of one APIServiceBinding.
+type BindingStatus struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider,omitempty"`
+	Ready    bool   `json:"ready"`
+	Message  string `json:"message,omitempty"`
+	// Notices are the current incident and maintenance notices of the service provider.
+	Notices []string `json:"notices,omitempty"`
+}
+
+// KonnectorStatus is the state of the konnector deployment and its pods.
+type KonnectorStatus struct {
+	// Found is false if there is no konnector deployment.
+	Found bool `json:"found"`
+
+	Images        []string `json:"images,omitempty"`
+	Replicas      int32    `json:"replicas"`
+	ReadyReplicas int32    `json:"readyReplicas"`
+	// Leader is the identity of the konnector pod holding the leader election lease.
+	Leader string      `json:"leader,omitempty"`
+	Pods   []PodStatus `json:"pods,omitempty"`
+
+	// Problems are the konnector issues that likely affect bindings.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// PodStatus is the state of one konnector pod.
+type PodStatus struct {
+	Name     string `json:"name"`
+	Image    string `json:"image,omitempty"`
+	Ready    bool   `json:"ready"`
+	Restarts int32  `json:"restarts"`
+	// LastTerminationReason is the reason of the last container termination, e.g. OOMKilled.
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+}
+
+// NewStatusOptions returns new StatusOptions.
+func NewStatusOptions(streams genericclioptions.IOStreams) *StatusOptions {
+	return &StatusOptions{
+		Options:            base.NewOptions(streams),
+		Logs:               logs.NewOptions(),
+		KonnectorNamespace: "kube-bind",
+		LeaseName:          "kube-bind",
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (s *StatusOptions) AddCmdFlags(cmd *cobra.Command) {
+	s.Options.BindFlags(cmd)
+	logsv1.AddFlags(s.Logs, cmd.Flags())
+
+	cmd.Flags().StringVarP(&s.Output, "output", "o", s.Output, "Output format. One of: json|yaml. Default is a summary.")
+	cmd.Flags().StringVar(&s.KonnectorNamespace, "konnector-namespace", s.KonnectorNamespace, "The namespace the konnector is deployed in.")
+	cmd.Flags().StringVar(&s.LeaseName, "lease-name", s.LeaseName, "The name of the leader election lease of the konnector.")
+}
+
+// Complete ensures all fields are initialized.
+func (s *StatusOptions) Complete(args []string) error {
+	return s.Options.Complete()
+}
+
+// Validate validates the StatusOptions are complete and usable.
+func (s *StatusOptions) Validate() error {
+	if s.Output != "" && s.Output != "json" && s.Output != "yaml" {
+		return fmt.Errorf("invalid output format %q (allowed: json, yaml)", s.Output)
+	}
+
+	return s.Options.Validate()
+}
+
+// Run reports the health of all APIServiceBindings and of the konnector.
+func (s *StatusOptions) Run(ctx context.Context) error {
+	config, err := s.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	bindClient, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubeclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	var status Status
+
+	bindings, err := bindClient.KubeBindV1alpha1().APIServiceBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		bs := BindingStatus{
+			Name:     binding.Name,
+			Provider: binding.Status.ProviderPrettyName,
+			Ready:    conditions.IsTrue(binding, conditionsapi.ReadyCondition),
+		}
+		if !bs.Ready {
+			bs.Message = conditions.GetMessage(binding, conditionsapi.ReadyCondition)
+		}
+		for _, n := range kubebindhelpers.CurrentNotices(binding.Status.Notices, time.Now()) {
+			bs.Notices = append(bs.Notices, kubebindhelpers.NoticeMessage(n))
+		}
+		status.Bindings = append(status.Bindings, bs)
+	}
+
+	deployment, err := kubeClient.AppsV1().Deployments(s.KonnectorNamespace).Get(ctx, "konnector", metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		status.Konnector = konnectorStatus(nil, nil, "")
+		return s.print(&status)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return err
+	}
+	pods, err := kubeClient.CoreV1().Pods(s.KonnectorNamespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+
+	var leader string
+	lease, err := kubeClient.CoordinationV1().Leases(s.KonnectorNamespace).Get(ctx, s.LeaseName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if err == nil && lease.Spec.HolderIdentity != nil {
+		leader = *lease.Spec.HolderIdentity
+	}
+
+	status.Konnector = konnectorStatus(deployment, pods.Items, leader)
+	return s.print(&status)
+}
+
+// konnectorStatus summarizes the konnector deployment and pods, and collects
+// the problems found, like missing replicas, restarts and OOMKills.
+func konnectorStatus(deployment *appsv1.Deployment, pods []corev1.Pod, leader string) KonnectorStatus {
+	if deployment == nil {
+		return KonnectorStatus{Problems: []string{"konnector deployment not found"}}
+	}
+
+	ret := KonnectorStatus{
+		Found:         true,
+		ReadyReplicas: deployment.Status.ReadyReplicas,
+		Leader:        leader,
+	}
+	if deployment.Spec.Replicas != nil {
+		ret.Replicas = *deployment.Spec.Replicas
+	}
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		ret.Images = append(ret.Images, c.Image)
+	}
+	if ret.ReadyReplicas < ret.Replicas {
+		ret.Problems = append(ret.Problems, fmt.Sprintf("%d of %d konnector replicas are ready", ret.ReadyReplicas, ret.Replicas))
+	}
+
+	leaderFound := false
+	for i := range pods {
+		pod := &pods[i]
+		ps := PodStatus{Name: pod.Name}
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady {
+				ps.Ready = c.Status == corev1.ConditionTrue
+			}
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			ps.Restarts += cs.RestartCount
+			if ps.Image == "" {
+				ps.Image = cs.Image
+			}
+			if t := cs.LastTerminationState.Terminated; t != nil && t.Reason != "" {
+				ps.LastTerminationReason = t.Reason
+			}
+		}
+		if ps.LastTerminationReason == "OOMKilled" {
+			ret.Problems = append(ret.Problems, fmt.Sprintf("konnector pod %s was OOMKilled, consider raising its memory limit", pod.Name))
+		} else if ps.Restarts > 0 {
+			ret.Problems = append(ret.Problems, fmt.Sprintf("konnector pod %s restarted %d times", pod.Name, ps.Restarts))
+		}
+		if pod.Name == leader {
+			leaderFound = true
+		}
+		ret.Pods = append(ret.Pods, ps)
+	}
+
+	if leader == "" {
+		ret.Problems = append(ret.Problems, "no konnector is leader, bindings are not reconciled")
+	} else if !leaderFound {
+		ret.Problems = append(ret.Problems, fmt.Sprintf("konnector leader %q is not a pod of the konnector deployment", leader))
+	}
+
+	return ret
+}
+
+func (s *StatusOptions) print(status *Status) error {
+	switch s.Output {
+	case "json":
+		bs, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(s.Options.Out, string(bs))
+		return err
+	case "yaml":
+		bs, err := yaml.Marshal(status)
+		if err != nil {
+			return err
+		}
+		_, err = s.Options.Out.Write(bs)
+		return err
+	}
+
+	if len(status.Bindings) == 0 {
+		fmt.Fprintf(s.Options.Out, "No APIServiceBindings found.\n") // nolint: errcheck
+	} else {
+		w := tabwriter.NewWriter(s.Options.Out, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(w, "BINDING\tPROVIDER\tREADY\tMESSAGE\n") // nolint: errcheck
+		for _, b := range status.Bindings {
+			fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", b.Name, b.Provider, b.Ready, b.Message) // nolint: errcheck
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		for _, b := range status.Bindings {
+			for _, n := range b.Notices {
+				fmt.Fprintf(s.Options.Out, "⚠️  %s: %s\n", b.Name, n) // nolint: errcheck
+			}
+		}
+	}
+	fmt.Fprintln(s.Options.Out) // nolint: errcheck
+
+	k := status.Konnector
+	if k.Found {
+		fmt.Fprintf(s.Options.Out, "Konnector: %d/%d ready, leader %q, image %v\n", k.ReadyReplicas, k.Replicas, k.Leader, k.Images) // nolint: errcheck
+		w := tabwriter.NewWriter(s.Options.Out, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(w, "POD\tREADY\tRESTARTS\tLAST TERMINATION\tIMAGE\n") // nolint: errcheck
+		for _, p := range k.Pods {
+			fmt.Fprintf(w, "%s\t%t\t%d\t%s\t%s\n", p.Name, p.Ready, p.Restarts, p.LastTerminationReason, p.Image) // nolint: errcheck
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(k.Problems) == 0 {
+		fmt.Fprintf(s.Options.ErrOut, "✅ Konnector is healthy\n") // nolint: errcheck
+	}
+	for _, p := range k.Problems {
+		fmt.Fprintf(s.Options.ErrOut, "❌ %s\n", p) // nolint: errcheck
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKonnectorStatus(t *testing.T) {
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "ghcr.io/kube-bind/konnector:v0.1.0"}}}},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 2},
+	}
+	healthy := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "konnector-a"},
+		Status: corev1.PodStatus{
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			ContainerStatuses: []corev1.ContainerStatus{{Image: "ghcr.io/kube-bind/konnector:v0.1.0"}},
+		},
+	}
+	oomKilled := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "konnector-b"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				RestartCount:         3,
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		pods       []corev1.Pod
+		leader     string
+		wantFound  bool
+		want       []string
+	}{
+		{name: "no deployment", want: []string{"konnector deployment not found"}},
+		{name: "healthy", deployment: deployment, pods: []corev1.Pod{healthy}, leader: "konnector-a", wantFound: true},
+		{name: "no leader", deployment: deployment, pods: []corev1.Pod{healthy}, wantFound: true, want: []string{"no konnector is leader, bindings are not reconciled"}},
+		{name: "oom killed", deployment: deployment, pods: []corev1.Pod{healthy, oomKilled}, leader: "konnector-a", wantFound: true, want: []string{"konnector pod konnector-b was OOMKilled, consider raising its memory limit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := konnectorStatus(tt.deployment, tt.pods, tt.leader)
+			require.Equal(t, tt.wantFound, got.Found)
+			require.Equal(t, tt.want, got.Problems)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+	"github.com/kube-bind/kube-bind/pkg/connection"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// TestConnectionOptions are the options for the kubectl-bind-test-connection command.
+type TestConnectionOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	name string
+}
+
+// NewTestConnectionOptions returns new TestConnectionOptions.
+func NewTestConnectionOptions(streams genericclioptions.IOStreams) *TestConnectionOptions {
+	return &TestConnectionOptions{
+		Options: base.NewOptions(streams),
+		Logs:    logs.NewOptions(),
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (t *TestConnectionOptions) AddCmdFlags(cmd *cobra.Command) {
+	t.Options.BindFlags(cmd)
+	logsv1.AddFlags(t.Logs, cmd.Flags())
+}
+
+// Complete ensures all fields are initialized.
+func (t *TestConnectionOptions) Complete(args []string) error {
+	if err := t.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		t.name = args[0]
+	}
+	return nil
+}
+
+// Validate validates the TestConnectionOptions are complete and usable.
+func (t *TestConnectionOptions) Validate() error {
+	if t.name == "" {
+		return errors.New("APIServiceBinding name is required")
+	}
+
+	return t.Options.Validate()
+}
+
+// Run checks the connection to the service provider cluster of the given APIServiceBinding.
+func (t *TestConnectionOptions) Run(ctx context.Context) error {
+	config, err := t.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	bindClient, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubeclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	binding, err := bindClient.KubeBindV1alpha1().APIServiceBindings().Get(ctx, t.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	remoteConfig, host, ns, err := base.RemoteConfigForBinding(ctx, kubeClient, binding)
+	if err != nil {
+		return err
+	}
+	remoteBindClient, err := bindclient.NewForConfig(remoteConfig)
+	if err != nil {
+		return err
+	}
+
+	if last := binding.Status.Connection; last != nil {
+		fmt.Fprintf(t.Options.ErrOut, "Last konnector check at %s: %s (latency %s)\n", last.LastCheckTime.Time, last.AuthStatus, last.Latency.Duration) // nolint: errcheck
+	}
+
+	status := connection.Check(ctx, remoteBindClient, ns)
+	if status.AuthStatus != kubebindv1alpha1.ConnectionAuthorized {
+		fmt.Fprintf(t.Options.ErrOut, "❌ Connection to %s failed: %s\n", host, status.AuthStatus) // nolint: errcheck
+		return errors.New(status.Message)
+	}
+	fmt.Fprintf(t.Options.ErrOut, "✅ Connection to %s succeeded (latency %s)\n", host, status.Latency.Duration) // nolint: errcheck
+
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// UpgradeSchemaOptions are the options for the kubectl-bind-upgrade-schema command.
+type UpgradeSchemaOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// Revision is the schema revision to pin the binding to. Empty means the
+	// latest revision the APIServiceExport publishes.
+	Revision string
+	// FollowLatest unpins the binding such that it follows every new revision.
+	FollowLatest bool
+
+	name string
+}
+
+// NewUpgradeSchemaOptions returns new UpgradeSchemaOptions.
+func NewUpgradeSchemaOptions(streams genericclioptions.IOStreams) *UpgradeSchemaOptions {
+	return &UpgradeSchemaOptions{
+		Options: base.NewOptions(streams),
+		Logs:    logs.NewOptions(),
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (o *UpgradeSchemaOptions) AddCmdFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	logsv1.AddFlags(o.Logs, cmd.Flags())
+
+	cmd.Flags().StringVar(&o.Revision, "revision", o.Revision, "The schema revision to pin the APIServiceBinding to. Defaults to the latest revision of the APIServiceExport.")
+	cmd.Flags().BoolVar(&o.FollowLatest, "follow-latest", o.FollowLatest, "Unpin the APIServiceBinding such that it follows every new schema revision.")
+}
+
+// Complete ensures all fields are initialized.
+func (o *UpgradeSchemaOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.name = args[0]
+	}
+	return nil
+}
+
+// Validate validates the UpgradeSchemaOptions are complete and usable.
+func (o *UpgradeSchemaOptions) Validate() error {
+	if o.name == "" {
+		return errors.New("APIServiceBinding name is required")
+	}
+	if o.FollowLatest && o.Revision != "" {
+		return errors.New("--revision and --follow-latest are mutually exclusive")
+	}
+
+	return o.Options.Validate()
+}
+
+// Run pins the APIServiceBinding to the requested schema revision. The konnector
+// then updates the bound CustomResourceDefinition.
+func (o *UpgradeSchemaOptions) Run(ctx context.Context) error {
+	config, err := o.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	bindClient, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		binding, err := bindClient.KubeBindV1alpha1().APIServiceBindings().Get(ctx, o.name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		revision := o.Revision
+		if revision == "" && !o.FollowLatest {
+			revision = binding.Status.LatestSchemaRevision
+			if revision == "" {
+				return fmt.Errorf("APIServiceExport of APIServiceBinding %s does not publish schema revisions", o.name)
+			}
+		}
+		if binding.Spec.SchemaRevision == revision {
+			fmt.Fprintf(o.Options.ErrOut, "✅ APIServiceBinding %s is already at schema revision %s\n", o.name, describe(revision)) // nolint: errcheck
+			return nil
+		}
+
+		binding = binding.DeepCopy()
+		from := binding.Spec.SchemaRevision
+		if from == "" {
+			from = binding.Status.SchemaRevision
+		}
+		binding.Spec.SchemaRevision = revision
+		if _, err := bindClient.KubeBindV1alpha1().APIServiceBindings().Update(ctx, binding, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Options.ErrOut, "✅ Moved APIServiceBinding %s from schema revision %s to %s\n", o.name, describe(from), describe(revision)) // nolint: errcheck
+		return nil
+	})
+}
+
+func describe(revision string) string {
+	if revision == "" {
+		return "latest"
+	}
+	return revision
+}
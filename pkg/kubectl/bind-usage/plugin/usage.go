@@ -0,0 +1,321 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+	"sigs.k8s.io/yaml"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
+)
+
+// UsageOptions are the options for the kubectl-bind-usage command.
+type UsageOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// Output is the output format: empty for a table, json or yaml.
+	Output string
+	// ProviderUsage enables fetching the usage reported by the service provider.
+	ProviderUsage bool
+
+	name string
+}
+
+// Usage is the usage report of one APIServiceBinding.
+type Usage struct {
+	Binding  string `json:"binding"`
+	Provider string `json:"provider,omitempty"`
+
+	Resources []ResourceUsage `json:"resources"`
+
+	// ProviderUsage is what the service provider reports via the kube-bind.io/usage
+	// annotation of the APIServiceExport, if any.
+	ProviderUsage map[string]string `json:"providerUsage,omitempty"`
+	// ProviderError is set if the provider usage could not be fetched.
+	ProviderError string `json:"providerError,omitempty"`
+}
+
+// ResourceUsage is the usage of one bound resource in the consumer cluster.
+type ResourceUsage struct {
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+
+	Objects    int      `json:"objects"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	// LastActivity is the latest creation or managed fields update of any object.
+	LastActivity *metav1.Time `json:"lastActivity,omitempty"`
+}
+
+// NewUsageOptions returns new UsageOptions.
+func NewUsageOptions(streams genericclioptions.IOStreams) *UsageOptions {
+	return &UsageOptions{
+		Options:       base.NewOptions(streams),
+		Logs:          logs.NewOptions(),
+		ProviderUsage: true,
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (u *UsageOptions) AddCmdFlags(cmd *cobra.Command) {
+	u.Options.BindFlags(cmd)
+	logsv1.AddFlags(u.Logs, cmd.Flags())
+
+	cmd.Flags().StringVarP(&u.Output, "output", "o", u.Output, "Output format. One of: json|yaml. Default is a table.")
+	cmd.Flags().BoolVar(&u.ProviderUsage, "provider-usage", u.ProviderUsage, "Fetch the usage reported by the service providers, using the credentials of the bindings.")
+}
+
+// Complete ensures all fields are initialized.
+func (u *UsageOptions) Complete(args []string) error {
+	if err := u.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		u.name = args[0]
+	}
+	return nil
+}
+
+// Validate validates the UsageOptions are complete and usable.
+func (u *UsageOptions) Validate() error {
+	if u.Output != "" && u.Output != "json" && u.Output != "yaml" {
+		return fmt.Errorf("invalid output format %q (allowed: json, yaml)", u.Output)
+	}
+
+	return u.Options.Validate()
+}
+
+// Run reports the usage of the bound resources of all or the given APIServiceBinding.
+func (u *UsageOptions) Run(ctx context.Context) error {
+	config, err := u.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	bindClient, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubeclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	apiextensionsClient, err := apiextensionsclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	metadataClient, err := metadata.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	var bindings []kubebindv1alpha1.APIServiceBinding
+	if u.name != "" {
+		binding, err := bindClient.KubeBindV1alpha1().APIServiceBindings().Get(ctx, u.name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		bindings = append(bindings, *binding)
+	} else {
+		list, err := bindClient.KubeBindV1alpha1().APIServiceBindings().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		bindings = list.Items
+	}
+
+	usages := make([]Usage, 0, len(bindings))
+	for i := range bindings {
+		binding := &bindings[i]
+		usage := Usage{
+			Binding:  binding.Name,
+			Provider: binding.Status.ProviderPrettyName,
+		}
+
+		crd, err := apiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, helpers.BoundCRDName(binding), metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get CRD of APIServiceBinding %s: %w", binding.Name, err)
+		}
+		for _, v := range crd.Spec.Versions {
+			if !v.Storage {
+				continue
+			}
+			gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: v.Name, Resource: crd.Spec.Names.Plural}
+			var items []metav1.PartialObjectMetadata
+			opts := metav1.ListOptions{Limit: 500}
+			for {
+				list, err := metadataClient.Resource(gvr).List(ctx, opts)
+				if err != nil {
+					return fmt.Errorf("failed to list %s: %w", gvr, err)
+				}
+				items = append(items, list.Items...)
+				if opts.Continue = list.Continue; opts.Continue == "" {
+					break
+				}
+			}
+			usage.Resources = append(usage.Resources, resourceUsage(gvr, items))
+		}
+
+		if u.ProviderUsage {
+			if usage.ProviderUsage, err = u.providerUsage(ctx, kubeClient, binding); err != nil {
+				usage.ProviderError = err.Error()
+			}
+		}
+
+		usages = append(usages, usage)
+	}
+
+	return u.print(usages)
+}
+
+// providerUsage returns the usage reported by the service provider on the APIServiceExport.
+func (u *UsageOptions) providerUsage(ctx context.Context, kubeClient kubeclient.Interface, binding *kubebindv1alpha1.APIServiceBinding) (map[string]string, error) {
+	remoteConfig, _, ns, err := base.RemoteConfigForBinding(ctx, kubeClient, binding)
+	if err != nil {
+		return nil, err
+	}
+	remoteBindClient, err := bindclient.NewForConfig(remoteConfig)
+	if err != nil {
+		return nil, err
+	}
+	export, err := remoteBindClient.KubeBindV1alpha1().APIServiceExports(ns).Get(ctx, helpers.ExportName(binding), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	value, found := export.Annotations[kubebindv1alpha1.UsageAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+	var usage map[string]string
+	if err := json.Unmarshal([]byte(value), &usage); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", kubebindv1alpha1.UsageAnnotationKey, err)
+	}
+	return usage, nil
+}
+
+func resourceUsage(gvr schema.GroupVersionResource, items []metav1.PartialObjectMetadata) ResourceUsage {
+	ret := ResourceUsage{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Objects:  len(items),
+	}
+	namespaces := sets.NewString()
+	var last time.Time
+	for _, item := range items {
+		if item.Namespace != "" {
+			namespaces.Insert(item.Namespace)
+		}
+		if t := item.CreationTimestamp.Time; t.After(last) {
+			last = t
+		}
+		for _, f := range item.ManagedFields {
+			if f.Time != nil && f.Time.After(last) {
+				last = f.Time.Time
+			}
+		}
+	}
+	ret.Namespaces = namespaces.List()
+	if !last.IsZero() {
+		ret.LastActivity = &metav1.Time{Time: last}
+	}
+	return ret
+}
+
+func (u *UsageOptions) print(usages []Usage) error {
+	switch u.Output {
+	case "json":
+		bs, err := json.MarshalIndent(usages, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(u.Options.Out, string(bs))
+		return err
+	case "yaml":
+		bs, err := yaml.Marshal(usages)
+		if err != nil {
+			return err
+		}
+		_, err = u.Options.Out.Write(bs)
+		return err
+	}
+
+	if len(usages) == 0 {
+		fmt.Fprintf(u.Options.ErrOut, "No APIServiceBindings found.\n") // nolint: errcheck
+		return nil
+	}
+
+	w := tabwriter.NewWriter(u.Options.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "BINDING\tPROVIDER\tRESOURCE\tOBJECTS\tNAMESPACES\tLAST ACTIVITY\tPROVIDER USAGE\n") // nolint: errcheck
+	for _, usage := range usages {
+		providerUsage := "<none>"
+		if usage.ProviderError != "" {
+			providerUsage = "<error>"
+		} else if len(usage.ProviderUsage) > 0 {
+			keys := make([]string, 0, len(usage.ProviderUsage))
+			for k := range usage.ProviderUsage {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			pairs := make([]string, 0, len(keys))
+			for _, k := range keys {
+				pairs = append(pairs, k+"="+usage.ProviderUsage[k])
+			}
+			providerUsage = strings.Join(pairs, ",")
+		}
+		for _, r := range usage.Resources {
+			lastActivity := "<never>"
+			if r.LastActivity != nil {
+				lastActivity = duration.HumanDuration(time.Since(r.LastActivity.Time)) + " ago"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\n", usage.Binding, usage.Provider, schema.GroupResource{Group: r.Group, Resource: r.Resource}, r.Objects, len(r.Namespaces), lastActivity, providerUsage) // nolint: errcheck
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, usage := range usages {
+		if usage.ProviderError != "" {
+			fmt.Fprintf(u.Options.ErrOut, "Failed to get provider usage of %s: %s\n", usage.Binding, usage.ProviderError) // nolint: errcheck
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestResourceUsage(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "mangodb.com", Version: "v1alpha1", Resource: "mangodbs"}
+	created := metav1.NewTime(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC))
+	updated := metav1.NewTime(time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC))
+
+	t.Run("no objects", func(t *testing.T) {
+		got := resourceUsage(gvr, nil)
+		require.Equal(t, ResourceUsage{Group: "mangodb.com", Version: "v1alpha1", Resource: "mangodbs", Namespaces: []string{}}, got)
+	})
+
+	t.Run("objects", func(t *testing.T) {
+		got := resourceUsage(gvr, []metav1.PartialObjectMetadata{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "b", Name: "one", CreationTimestamp: created}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "two", CreationTimestamp: created, ManagedFields: []metav1.ManagedFieldsEntry{{Time: &updated}}}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "three", CreationTimestamp: created}},
+		})
+		require.Equal(t, 3, got.Objects)
+		require.Equal(t, []string{"a", "b"}, got.Namespaces)
+		require.Equal(t, updated.Time, got.LastActivity.Time)
+	})
+}
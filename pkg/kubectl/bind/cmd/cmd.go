@@ -28,6 +28,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 	logsv1 "k8s.io/component-base/logs/api/v1"
 
+	"github.com/kube-bind/kube-bind/pkg/features"
 	"github.com/kube-bind/kube-bind/pkg/kubectl/bind/plugin"
 )
 
@@ -45,6 +46,15 @@ var (
 
 	# bind to a remote API service via a request manifest from a https URL.
 	%[1]s bind apiservice --remote-kubeconfig name https://some-url.com/apiservice-export-requests.yaml
+
+	# bind without network access to the service provider backend, from a signed bundle handed out by the service provider.
+	%[1]s bind --from-bundle mangodb.tgz --bundle-public-key mangodb.pub
+
+	# bind the same service in several consumer clusters, one after another.
+	%[1]s bind https://mangodb.com/exports --contexts prod-eu,prod-us
+
+	# bind the same service in every cluster of the kubeconfig.
+	%[1]s bind https://mangodb.com/exports --all-contexts
 	`
 )
 
@@ -71,7 +81,7 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 			yellow := color.New(color.BgRed, color.FgBlack).SprintFunc()
 			fmt.Fprintf(streams.ErrOut, yellow("DISCLAIMER: This is a prototype. It will change in incompatible ways at any time.")+"\n\n") // nolint: errcheck
 
-			if len(args) == 0 {
+			if len(args) == 0 && opts.FromBundle == "" {
 				return cmd.Help()
 			}
 			if err := opts.Complete(args); err != nil {
@@ -86,6 +96,7 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 		},
 	}
 	opts.AddCmdFlags(cmd)
+	features.DefaultMutableFeatureGate.AddFlag(cmd.PersistentFlags())
 
 	return cmd, nil
 }
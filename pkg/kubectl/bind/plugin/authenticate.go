@@ -32,6 +32,7 @@ import (
 	clientgoversion "k8s.io/client-go/pkg/version"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
 	"github.com/kube-bind/kube-bind/pkg/version"
 )
 
@@ -91,6 +92,23 @@ func validateProviderVersion(providerVersion string) error {
 	return nil
 }
 
+// checkRegions returns an error if the provider does not advertise one of the
+// allowed regions. Empty allowed regions allow every provider.
+func checkRegions(provider *kubebindv1alpha1.BindingProvider, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, region := range provider.Regions {
+		if helpers.RegionAllowed(region, allowed) {
+			return nil
+		}
+	}
+	if len(provider.Regions) == 0 {
+		return fmt.Errorf("service provider does not advertise its region, but only %s are allowed", strings.Join(allowed, ", "))
+	}
+	return fmt.Errorf("service provider is in region %s, but only %s are allowed", strings.Join(provider.Regions, ", "), strings.Join(allowed, ", "))
+}
+
 func (b *BindOptions) authenticate(provider *kubebindv1alpha1.BindingProvider, callback, sessionID, clusterID string, urlCh chan<- string) error {
 	var oauth2Method *kubebindv1alpha1.OAuth2CodeGrant
 	for _, m := range provider.AuthenticationMethods {
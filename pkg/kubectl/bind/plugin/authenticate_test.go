@@ -18,6 +18,8 @@ package plugin
 
 import (
 	"testing"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
 )
 
 func TestValidateVersion(t *testing.T) {
@@ -42,3 +44,26 @@ func TestValidateVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		regions []string
+		allowed []string
+		wantErr bool
+	}{
+		{"nothing allowed explicitly", nil, nil, false},
+		{"unknown region", nil, []string{"eu"}, true},
+		{"allowed region", []string{"eu"}, []string{"eu"}, false},
+		{"one of multiple", []string{"us", "eu"}, []string{"eu", "ch"}, false},
+		{"other region", []string{"us"}, []string{"eu"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &kubebindv1alpha1.BindingProvider{Regions: tt.regions}
+			if err := checkRegions(provider, tt.allowed); (err != nil) != tt.wantErr {
+				t.Errorf("checkRegions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
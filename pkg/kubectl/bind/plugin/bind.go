@@ -39,6 +39,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	kubeclient "k8s.io/client-go/kubernetes"
@@ -46,6 +47,8 @@ import (
 	logsv1 "k8s.io/component-base/logs/api/v1"
 
 	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	"github.com/kube-bind/kube-bind/pkg/features"
 	"github.com/kube-bind/kube-bind/pkg/kubectl/base"
 	"github.com/kube-bind/kube-bind/pkg/kubectl/bind/authenticator"
 )
@@ -65,6 +68,56 @@ type BindOptions struct {
 	// skipKonnector skips the deployment of the konnector.
 	SkipKonnector bool
 
+	// RequiredCapabilities are APIServiceExport capabilities the consumer relies on.
+	RequiredCapabilities []string
+
+	// AllowedRegions refuses service providers that do not advertise one of these
+	// regions for data residency. Empty allows all.
+	AllowedRegions []string
+
+	// AcceptClaims are the cluster-scoped claims of the service provider the
+	// consumer consents to, e.g. storageclasses.storage.k8s.io.
+	AcceptClaims []string
+
+	// AcceptServiceAccountTokens are the consumer ServiceAccounts, as namespace/name,
+	// the service provider may get projected tokens of.
+	AcceptServiceAccountTokens []string
+
+	// AcceptEventRelay consents to the event relay offered by the service provider.
+	AcceptEventRelay bool
+
+	// AllowActions are the actions the service provider may request on consumer
+	// objects in any namespace, e.g. Restart.
+	AllowActions []string
+
+	// GroupAliases are <group>=<alias> pairs to bind the resources of a group under another group.
+	GroupAliases []string
+
+	// AdoptExisting makes the konnector upsync objects that existed before the binding.
+	AdoptExisting bool
+
+	// StrictFieldValidation makes the konnector reject objects with unknown fields
+	// instead of pruning them.
+	StrictFieldValidation bool
+
+	// ReportPrunedFields makes the konnector record the unknown fields it prunes
+	// on the objects.
+	ReportPrunedFields bool
+
+	// ProviderProxyURL is the proxy to connect to the service provider cluster
+	// through. It is stored as proxy-url in the kubeconfig Secret.
+	ProviderProxyURL string
+
+	// FromBundle is a signed binding bundle to bind from, without authentication
+	// against the service provider.
+	FromBundle      string
+	BundlePublicKey string
+
+	// Contexts are kubeconfig contexts of consumer clusters to bind in one after
+	// another. AllContexts binds in every context of the kubeconfig.
+	Contexts    []string
+	AllContexts bool
+
 	// Runner is runs the command. It can be replaced in tests.
 	Runner func(cmd *exec.Cmd) error
 
@@ -95,6 +148,21 @@ func (b *BindOptions) AddCmdFlags(cmd *cobra.Command) {
 	b.Print.AddFlags(cmd)
 
 	cmd.Flags().BoolVar(&b.SkipKonnector, "skip-konnector", b.SkipKonnector, "Skip the deployment of the konnector")
+	cmd.Flags().StringSliceVar(&b.RequiredCapabilities, "require-capabilities", b.RequiredCapabilities, "APIServiceExport capabilities the consumer relies on. A warning is shown if the service provider does not advertise them.")
+	cmd.Flags().StringSliceVar(&b.AllowedRegions, "allowed-regions", b.AllowedRegions, "Regions where the service provider may store and process data, e.g. eu. Service providers in other or unknown regions are refused.")
+	cmd.Flags().StringSliceVar(&b.GroupAliases, "group-alias", b.GroupAliases, "Bind the resources of an API group under another group, as <group>=<alias>, e.g. databases.example.com=databases.acme.example. The konnector translates between the groups, such that the same resource of different service providers can be bound side by side.")
+	cmd.Flags().StringSliceVar(&b.AcceptClaims, "accept-claims", b.AcceptClaims, "Cluster-scoped consumer resources the service provider may read, as resource.group, e.g. storageclasses.storage.k8s.io or nodes. Claims that are not accepted are not synced.")
+	cmd.Flags().StringSliceVar(&b.AcceptServiceAccountTokens, "accept-service-account-tokens", b.AcceptServiceAccountTokens, "Consumer ServiceAccounts, as namespace/name, the service provider may get projected, audience-bound tokens of, e.g. to call back into this cluster. Tokens of claims that are not accepted are not issued.")
+	cmd.Flags().BoolVar(&b.AcceptEventRelay, "accept-event-relay", b.AcceptEventRelay, "Accept the event relay offered by the service provider, i.e. its Events about bound objects are copied into the consumer namespaces, and if offered, consumer Events about them are copied to the service provider.")
+	cmd.Flags().StringSliceVar(&b.AllowActions, "allow-actions", b.AllowActions, "Actions the service provider may request on consumer objects in any namespace with APIServiceActions, e.g. Restart. Other actions are denied.")
+	cmd.Flags().BoolVar(&b.AdoptExisting, "adopt-existing", b.AdoptExisting, "Adopt objects and CustomResourceDefinitions that already exist in the consumer cluster, e.g. from a previous local operator, and sync them to the service provider.")
+	cmd.Flags().BoolVar(&b.StrictFieldValidation, "strict-field-validation", b.StrictFieldValidation, "Reject objects with fields unknown to the schema of the other cluster, e.g. during version skew, with a condition on the object instead of dropping the fields silently.")
+	cmd.Flags().BoolVar(&b.ReportPrunedFields, "report-pruned-fields", b.ReportPrunedFields, "Drop fields unknown to the schema of the other cluster like by default, but list them in the kube-bind.io/pruned-upstream-fields and kube-bind.io/pruned-downstream-fields annotations of the object.")
+	cmd.Flags().StringVar(&b.ProviderProxyURL, "provider-proxy-url", b.ProviderProxyURL, "An http, https or socks5 proxy URL, e.g. socks5://proxy.internal:1080, to reach the service provider cluster through. It is stored in the kubeconfig Secret for the konnector. If unset, HTTPS_PROXY and NO_PROXY are honored.")
+	cmd.Flags().StringVar(&b.FromBundle, "from-bundle", b.FromBundle, "A signed binding bundle file generated by the service provider with \"kubectl bind bundle\". Binds without network access to the service provider backend.")
+	cmd.Flags().StringVar(&b.BundlePublicKey, "bundle-public-key", b.BundlePublicKey, "A PEM encoded ed25519 public key file to verify the binding bundle signature with")
+	cmd.Flags().StringSliceVar(&b.Contexts, "contexts", b.Contexts, "Kubeconfig contexts of consumer clusters to bind in, one after another. Defaults to the current context.")
+	cmd.Flags().BoolVar(&b.AllContexts, "all-contexts", b.AllContexts, "Bind in every context of the kubeconfig, one after another.")
 	cmd.Flags().BoolVarP(&b.DryRun, "dry-run", "d", b.DryRun, "If true, only print the requests that would be sent to the service provider after authentication, without actually binding.")
 }
 
@@ -112,6 +180,37 @@ func (b *BindOptions) Complete(args []string) error {
 
 // Validate validates the BindOptions are complete and usable.
 func (b *BindOptions) Validate() error {
+	if len(b.Contexts) > 0 && b.AllContexts {
+		return errors.New("contexts and all-contexts are mutually exclusive")
+	}
+	if (len(b.Contexts) > 0 || b.AllContexts) && b.KubectlOverrides.CurrentContext != "" {
+		return errors.New("context is mutually exclusive with contexts and all-contexts")
+	}
+	if err := features.ValidateCapabilities(features.DefaultFeatureGate, b.RequiredCapabilities); err != nil {
+		return err
+	}
+	if b.StrictFieldValidation && b.ReportPrunedFields {
+		return errors.New("strict-field-validation and report-pruned-fields are mutually exclusive")
+	}
+	if b.ProviderProxyURL != "" {
+		if err := base.ValidateProxyURL(b.ProviderProxyURL); err != nil {
+			return fmt.Errorf("invalid provider-proxy-url: %w", err)
+		}
+	}
+
+	if b.FromBundle != "" {
+		if b.URL != "" {
+			return errors.New("url and from-bundle are mutually exclusive")
+		}
+		if b.DryRun {
+			return errors.New("dry-run is not supported with from-bundle")
+		}
+		if b.BundlePublicKey == "" {
+			return errors.New("bundle-public-key is required with from-bundle")
+		}
+		return b.Options.Validate()
+	}
+
 	if b.URL == "" {
 		return errors.New("url is required as an argument") // should not happen because we validate that before
 	}
@@ -130,8 +229,37 @@ func (b *BindOptions) Validate() error {
 	return b.Options.Validate()
 }
 
-// Run starts the binding process.
+// Run starts the binding process, in every selected kubeconfig context in turn.
+// A failure in one context does not stop the others.
 func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
+	if len(b.Contexts) == 0 && !b.AllContexts {
+		return b.run(ctx, urlCh)
+	}
+
+	names, err := b.ContextNames(b.Contexts, b.AllContexts)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range names {
+		fmt.Fprintf(b.Options.ErrOut, "🎯 Binding in context %q\n", name) // nolint: errcheck
+		if err := b.UseContext(name); err != nil {
+			errs = append(errs, fmt.Errorf("context %q: %w", name, err))
+			continue
+		}
+		if err := b.run(ctx, urlCh); err != nil {
+			fmt.Fprintf(b.Options.ErrOut, "❌ Failed to bind in context %q: %v\n", name, err) // nolint: errcheck
+			errs = append(errs, fmt.Errorf("context %q: %w", name, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (b *BindOptions) run(ctx context.Context, urlCh chan<- string) error {
+	if b.FromBundle != "" {
+		return b.runFromBundle(ctx)
+	}
+
 	config, err := b.ClientConfig.ClientConfig()
 	if err != nil {
 		return err
@@ -165,6 +293,17 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 	if provider.APIVersion != kubebindv1alpha1.GroupVersion {
 		return fmt.Errorf("unsupported binding provider version: %q", provider.APIVersion)
 	}
+	if err := checkRegions(provider, b.AllowedRegions); err != nil {
+		return err
+	}
+	trustPolicies, err := getTrustPolicies(ctx, config)
+	if err != nil {
+		return err
+	}
+	identity := providerIdentity(exportURL.String(), provider)
+	if err := helpers.BackendTrusted(trustPolicies, identity, true); err != nil {
+		return err
+	}
 
 	ns, err := kubeClient.CoreV1().Namespaces().Get(ctx, "kube-bind", metav1.GetOptions{})
 	if err != nil && !apierrors.IsNotFound(err) {
@@ -208,6 +347,12 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 	if bindingResponse.Authentication.OAuth2CodeGrant.SessionID != sessionID {
 		return fmt.Errorf("unexpected response: sessionID does not match")
 	}
+	if identity, err = withKubeconfig(identity, bindingResponse.Kubeconfig); err != nil {
+		return err
+	}
+	if err := helpers.BackendTrusted(trustPolicies, identity, false); err != nil {
+		return err
+	}
 
 	// extract the requests
 	var apiRequests []*kubebindv1alpha1.APIServiceExportRequestResponse
@@ -238,7 +383,13 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 	}
 
 	// copy kubeconfig into local cluster
-	remoteHost, remoteNamespace, err := base.ParseRemoteKubeconfig(bindingResponse.Kubeconfig)
+	kubeconfig := bindingResponse.Kubeconfig
+	if b.ProviderProxyURL != "" {
+		if kubeconfig, err = base.SetProxyURL(kubeconfig, b.ProviderProxyURL); err != nil {
+			return err
+		}
+	}
+	remoteHost, remoteNamespace, err := base.ParseRemoteKubeconfig(kubeconfig)
 	if err != nil {
 		return err
 	}
@@ -246,7 +397,7 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 	if err != nil {
 		return err
 	}
-	secret, created, err := base.EnsureKubeconfigSecret(ctx, string(bindingResponse.Kubeconfig), secretName, kubeClient)
+	secret, created, err := base.EnsureKubeconfigSecret(ctx, string(kubeconfig), secretName, exportURL.String(), kubeClient)
 	if err != nil {
 		return err
 	}
@@ -301,8 +452,7 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 				args = append(args, "--"+flag.Name+"="+flag.Value.String())
 			}
 		})
-
-		// TODO: support passing through the base options
+		args = append(args, b.Options.OverrideArgs()...)
 
 		fmt.Fprintf(b.Options.ErrOut, "🚀 Executing: %s %s\n", "kubectl bind", strings.Join(args, " ")) // nolint: errcheck
 		fmt.Fprintf(b.Options.ErrOut, "✨ Use \"-o yaml\" and \"--dry-run\" to get the APIServiceExportRequest.\n   and pass it to \"kubectl bind apiservice\" directly. Great for automation.\n")
@@ -318,6 +468,28 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 	return nil
 }
 
+// runFromBundle hands the binding bundle over to the apiservice sub-command,
+// which verifies and installs it. No authentication is involved.
+func (b *BindOptions) runFromBundle(ctx context.Context) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{"apiservice"}
+	b.flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed && PassOnFlags.Has(flag.Name) {
+			args = append(args, "--"+flag.Name+"="+flag.Value.String())
+		}
+	})
+	args = append(args, b.Options.OverrideArgs()...)
+
+	fmt.Fprintf(b.Options.ErrOut, "🚀 Executing: %s %s\n", "kubectl bind", strings.Join(args, " ")) // nolint: errcheck
+	command := exec.CommandContext(ctx, executable, append(args, "--no-banner")...)
+	command.Stdout = b.Options.Out
+	command.Stderr = b.Options.ErrOut
+	return b.Runner(command)
+}
+
 func ClusterID(ns *corev1.Namespace) string {
 	hash := sha256.Sum224([]byte(ns.UID))
 	base62hash := toBase62(hash)
@@ -23,14 +23,28 @@ import (
 var (
 	// passOnFlags are the flags we pass to downstream commands like kubectl-bind-apiservice.
 	PassOnFlags = sets.NewString(
+		"accept-claims",
+		"accept-event-relay",
+		"accept-service-account-tokens",
+		"adopt-existing",
+		"allow-actions",
 		"allow-missing-template-keys",
+		"allowed-regions",
+		"bundle-public-key",
+		"feature-gates",
+		"from-bundle",
+		"group-alias",
 		"kubeconfig",
 		"log-flush-frequency",
 		"logging-format",
 		"o",
 		"output",
+		"provider-proxy-url",
+		"report-pruned-fields",
+		"require-capabilities",
 		"show-managed-fields",
 		"skip-konnector",
+		"strict-field-validation",
 		"template",
 		"v",
 		"vmodule",
@@ -38,6 +52,8 @@ var (
 
 	// passOnEnvVars are the flags we DO NOT pass to downstream commands like kubectl-bind-apiservice.
 	LocalFlags = sets.NewString(
+		"all-contexts",
+		"contexts",
 		"d",
 		"dry-run",
 	)
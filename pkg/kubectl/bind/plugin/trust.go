@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+	bindclient "github.com/kube-bind/kube-bind/pkg/client/clientset/versioned"
+)
+
+// getTrustPolicies returns the BackendTrustPolicies of the consumer cluster, or none
+// if they are not installed.
+func getTrustPolicies(ctx context.Context, config *rest.Config) ([]*kubebindv1alpha1.BackendTrustPolicy, error) {
+	client, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.KubeBindV1alpha1().BackendTrustPolicies().List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list BackendTrustPolicies: %w", err)
+	}
+	policies := make([]*kubebindv1alpha1.BackendTrustPolicy, 0, len(list.Items))
+	for i := range list.Items {
+		policies = append(policies, &list.Items[i])
+	}
+	return policies, nil
+}
+
+// providerIdentity returns what is known about the backend before authentication.
+func providerIdentity(exportURL string, provider *kubebindv1alpha1.BindingProvider) helpers.BackendIdentity {
+	id := helpers.BackendIdentity{URL: exportURL}
+	for _, m := range provider.AuthenticationMethods {
+		if m.Method == "OAuth2CodeGrant" && m.OAuth2CodeGrant != nil {
+			id.Issuer = m.OAuth2CodeGrant.AuthenticatedURL
+			break
+		}
+	}
+	return id
+}
+
+// withKubeconfig adds the service provider cluster of the kubeconfig to the identity.
+func withKubeconfig(id helpers.BackendIdentity, kubeconfig []byte) (helpers.BackendIdentity, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return id, fmt.Errorf("invalid kubeconfig of service provider: %w", err)
+	}
+	id.Server = config.Host
+	id.CAData = config.CAData
+	return id, nil
+}
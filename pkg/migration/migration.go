@@ -0,0 +1,246 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration rewrites stored kube-bind objects after upgrades, similar to
+// the kube-storage-version-migrator, but scoped to the kube-bind CRDs. Every
+// object is written back in the current storage version, such that defaults of
+// the new schema are persisted and old versions can be dropped from the CRD.
+// Named migrations transform objects once, e.g. to move a renamed field.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// MigrationsAnnotationKey is the annotation on kube-bind CRDs listing the names of
+// the migrations that completed for all of its objects, comma separated.
+const MigrationsAnnotationKey = "kube-bind.io/migrations"
+
+// Migration transforms the stored objects of a kube-bind resource once.
+type Migration struct {
+	// Name identifies the migration. It is recorded on the CRD when the migration
+	// completed, and must never change.
+	Name string
+	// Resource is the kube-bind resource whose objects are migrated.
+	Resource metav1.GroupResource
+	// Migrate changes the object in place and returns whether it changed. It must
+	// be idempotent as it might run more than once on the same object, e.g. after
+	// a conflict or with multiple replicas.
+	Migrate func(obj *unstructured.Unstructured) (bool, error)
+}
+
+// Migrations are the named migrations of kube-bind objects, applied in order.
+// Append new ones for changes that defaulting cannot express, instead of asking
+// for manual steps in the release notes. Never remove or reorder entries.
+var Migrations []Migration
+
+// Migrate rewrites the objects of the given kube-bind resources whose CRD still
+// lists other stored versions than the storage version, or which have pending
+// migrations. Afterwards, the storage version is the only stored version of the
+// CRD, and the migrations are recorded in its annotation. CRDs that do not exist
+// are skipped.
+func Migrate(ctx context.Context, crdClient apiextensionsv1client.CustomResourceDefinitionInterface, dynamicClient dynamic.Interface, migrations []Migration, grs ...metav1.GroupResource) error {
+	for _, gr := range grs {
+		if err := migrateResource(ctx, crdClient, dynamicClient, migrations, gr); err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", gr, err)
+		}
+	}
+	return nil
+}
+
+func migrateResource(ctx context.Context, crdClient apiextensionsv1client.CustomResourceDefinitionInterface, dynamicClient dynamic.Interface, migrations []Migration, gr metav1.GroupResource) error {
+	logger := klog.FromContext(ctx).WithValues("crd", gr.String())
+
+	crd, err := crdClient.Get(ctx, gr.String(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	storageVersion, statusSubresource := storageVersionOf(crd)
+	if storageVersion == "" {
+		return fmt.Errorf("CRD %s has no storage version", crd.Name)
+	}
+	pending := pendingMigrations(crd, migrations, gr)
+	rewrite := len(crd.Status.StoredVersions) != 1 || crd.Status.StoredVersions[0] != storageVersion
+	if !rewrite && len(pending) == 0 {
+		return nil
+	}
+
+	logger.Info("migrating stored objects", "storageVersion", storageVersion, "storedVersions", crd.Status.StoredVersions, "migrations", names(pending))
+	client := dynamicClient.Resource(schema.GroupVersionResource{Group: gr.Group, Version: storageVersion, Resource: gr.Resource})
+	count := 0
+	opts := metav1.ListOptions{Limit: 500}
+	for {
+		list, err := client.List(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			if err := migrateObject(ctx, client, &list.Items[i], pending, statusSubresource); err != nil {
+				return fmt.Errorf("failed to migrate %s: %w", qualifiedName(&list.Items[i]), err)
+			}
+			count++
+		}
+		if opts.Continue = list.GetContinue(); opts.Continue == "" {
+			break
+		}
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		crd, err := crdClient.Get(ctx, crd.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			done := sets.NewString(completed(crd)...).Insert(names(pending)...)
+			if crd.Annotations == nil {
+				crd.Annotations = map[string]string{}
+			}
+			crd.Annotations[MigrationsAnnotationKey] = strings.Join(done.List(), ",")
+			if crd, err = crdClient.Update(ctx, crd, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+		if rewrite {
+			crd.Status.StoredVersions = []string{storageVersion}
+			if _, err := crdClient.UpdateStatus(ctx, crd, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to record migration on CRD %s: %w", crd.Name, err)
+	}
+
+	logger.Info("migrated stored objects", "objects", count)
+	return nil
+}
+
+// migrateObject writes the object back, after applying the migrations. Status
+// changes are written through the status subresource if the CRD has one.
+func migrateObject(ctx context.Context, client dynamic.NamespaceableResourceInterface, obj *unstructured.Unstructured, migrations []Migration, statusSubresource bool) error {
+	ri := dynamic.ResourceInterface(client)
+	if ns := obj.GetNamespace(); ns != "" {
+		ri = client.Namespace(ns)
+	}
+
+	first := true
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			var err error
+			if obj, err = ri.Get(ctx, obj.GetName(), metav1.GetOptions{}); apierrors.IsNotFound(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+		first = false
+
+		if _, err := apply(obj, migrations); err != nil {
+			return err
+		}
+		updated, err := ri.Update(ctx, obj, metav1.UpdateOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !statusSubresource || len(migrations) == 0 {
+			return nil
+		}
+
+		// the update dropped status changes. Apply them again on the status subresource.
+		if changed, err := apply(updated, migrations); err != nil || !changed {
+			return err
+		}
+		obj = updated
+		_, err = ri.UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+func apply(obj *unstructured.Unstructured, migrations []Migration) (bool, error) {
+	changed := false
+	for _, m := range migrations {
+		c, err := m.Migrate(obj)
+		if err != nil {
+			return false, fmt.Errorf("migration %s failed: %w", m.Name, err)
+		}
+		changed = changed || c
+	}
+	return changed, nil
+}
+
+func storageVersionOf(crd *apiextensionsv1.CustomResourceDefinition) (string, bool) {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name, v.Subresources != nil && v.Subresources.Status != nil
+		}
+	}
+	return "", false
+}
+
+func completed(crd *apiextensionsv1.CustomResourceDefinition) []string {
+	value := crd.Annotations[MigrationsAnnotationKey]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func pendingMigrations(crd *apiextensionsv1.CustomResourceDefinition, migrations []Migration, gr metav1.GroupResource) []Migration {
+	done := sets.NewString(completed(crd)...)
+	var pending []Migration
+	for _, m := range migrations {
+		if m.Resource == gr && !done.Has(m.Name) {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+func names(migrations []Migration) []string {
+	names := make([]string, 0, len(migrations))
+	for _, m := range migrations {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+func qualifiedName(obj *unstructured.Unstructured) string {
+	if ns := obj.GetNamespace(); ns != "" {
+		return ns + "/" + obj.GetName()
+	}
+	return obj.GetName()
+}
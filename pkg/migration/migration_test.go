@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var (
+	widgets   = metav1.GroupResource{Group: "kube-bind.io", Resource: "widgets"}
+	widgetGVR = schema.GroupVersionResource{Group: "kube-bind.io", Version: "v1alpha1", Resource: "widgets"}
+)
+
+func TestMigrate(t *testing.T) {
+	renameColor := Migration{
+		Name:     "rename-colour",
+		Resource: widgets,
+		Migrate: func(obj *unstructured.Unstructured) (bool, error) {
+			colour, found, err := unstructured.NestedString(obj.Object, "spec", "colour")
+			if err != nil || !found {
+				return false, err
+			}
+			unstructured.RemoveNestedField(obj.Object, "spec", "colour")
+			return true, unstructured.SetNestedField(obj.Object, colour, "spec", "color")
+		},
+	}
+
+	tests := []struct {
+		name           string
+		crd            *apiextensionsv1.CustomResourceDefinition
+		migrations     []Migration
+		wantUpdates    int
+		wantStored     []string
+		wantAnnotation string
+		wantSpec       map[string]interface{}
+	}{
+		{
+			name: "missing CRD",
+		},
+		{
+			name:        "up to date",
+			crd:         newCRD("v1alpha1"),
+			migrations:  []Migration{{Name: "other", Resource: metav1.GroupResource{Group: "kube-bind.io", Resource: "gadgets"}}},
+			wantUpdates: 0,
+			wantStored:  []string{"v1alpha1"},
+			wantSpec:    map[string]interface{}{"colour": "blue"},
+		},
+		{
+			name:        "old stored version",
+			crd:         newCRD("v1alpha0", "v1alpha1"),
+			wantUpdates: 1,
+			wantStored:  []string{"v1alpha1"},
+			wantSpec:    map[string]interface{}{"colour": "blue"},
+		},
+		{
+			name:           "pending migration",
+			crd:            newCRD("v1alpha1"),
+			migrations:     []Migration{renameColor},
+			wantUpdates:    1,
+			wantStored:     []string{"v1alpha1"},
+			wantAnnotation: "rename-colour",
+			wantSpec:       map[string]interface{}{"color": "blue"},
+		},
+		{
+			name: "completed migration",
+			crd: func() *apiextensionsv1.CustomResourceDefinition {
+				crd := newCRD("v1alpha1")
+				crd.Annotations = map[string]string{MigrationsAnnotationKey: "rename-colour"}
+				return crd
+			}(),
+			migrations:     []Migration{renameColor},
+			wantUpdates:    0,
+			wantStored:     []string{"v1alpha1"},
+			wantAnnotation: "rename-colour",
+			wantSpec:       map[string]interface{}{"colour": "blue"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			var crdObjects []runtime.Object
+			if tt.crd != nil {
+				crdObjects = append(crdObjects, tt.crd)
+			}
+			crdClient := apiextensionsfake.NewSimpleClientset(crdObjects...).ApiextensionsV1().CustomResourceDefinitions()
+			widget := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kube-bind.io/v1alpha1",
+				"kind":       "Widget",
+				"metadata":   map[string]interface{}{"name": "foo", "namespace": "default"},
+				"spec":       map[string]interface{}{"colour": "blue"},
+			}}
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{widgetGVR: "WidgetList"}, widget)
+
+			err := Migrate(ctx, crdClient, dynamicClient, tt.migrations, widgets)
+			require.NoError(t, err)
+
+			updates := 0
+			for _, action := range dynamicClient.Actions() {
+				if action.GetVerb() == "update" {
+					updates++
+				}
+			}
+			require.Equal(t, tt.wantUpdates, updates)
+
+			if tt.crd == nil {
+				return
+			}
+			crd, err := crdClient.Get(ctx, tt.crd.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStored, crd.Status.StoredVersions)
+			require.Equal(t, tt.wantAnnotation, crd.Annotations[MigrationsAnnotationKey])
+
+			obj, err := dynamicClient.Resource(widgetGVR).Namespace("default").Get(ctx, "foo", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSpec, obj.Object["spec"])
+		})
+	}
+}
+
+func newCRD(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.kube-bind.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "kube-bind.io",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	bindlisters "github.com/kube-bind/kube-bind/pkg/client/listers/kubebind/v1alpha1"
+)
+
+// ClaimContext is the context under which a consumer claimed an object in the service
+// provider cluster.
+type ClaimContext struct {
+	Consumer
+
+	// ClusterBinding of the consumer cluster. It is nil if the consumer has been unbound.
+	ClusterBinding *kubebindv1alpha1.ClusterBinding
+
+	// Export is the APIServiceExport of the object's resource. It is nil if the resource
+	// is not exported (anymore) to the consumer.
+	Export *kubebindv1alpha1.APIServiceExport
+
+	// ConsumerGeneration is the generation of the consumer object whose spec was synced
+	// last, or zero if unknown.
+	ConsumerGeneration int64
+}
+
+// GetClaimContext returns the claim context of a namespaced object of the given resource.
+func GetClaimContext(
+	obj metav1.Object,
+	gr schema.GroupResource,
+	namespaces corelisters.NamespaceLister,
+	clusterBindings bindlisters.ClusterBindingLister,
+	exports bindlisters.APIServiceExportLister,
+) (*ClaimContext, error) {
+	consumer, err := ConsumerForObject(obj, namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &ClaimContext{Consumer: consumer}
+	if ret.ClusterBinding, err = clusterBindings.ClusterBindings(consumer.ClusterNamespace).Get("cluster"); errors.IsNotFound(err) {
+		ret.ClusterBinding = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if ret.Export, err = exports.APIServiceExports(consumer.ClusterNamespace).Get(gr.Resource + "." + gr.Group); errors.IsNotFound(err) {
+		ret.Export = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if generation, err := strconv.ParseInt(obj.GetAnnotations()[kubebindv1alpha1.ConsumerGenerationAnnotationKey], 10, 64); err == nil {
+		ret.ConsumerGeneration = generation
+	}
+
+	return ret, nil
+}
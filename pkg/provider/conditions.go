@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+// SetCondition sets the condition in status.conditions of the object, replacing one of the
+// same type. The last transition time is kept if the status did not change. The konnector
+// downsyncs the conditions to the consumer object.
+//
+// Typed objects can use the conditions util package of kube-bind instead.
+func SetCondition(obj *unstructured.Unstructured, cond conditionsapi.Condition) error {
+	conds, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return err
+	}
+
+	if cond.LastTransitionTime.IsZero() {
+		cond.LastTransitionTime = metav1.Now().Rfc3339Copy()
+	}
+	var existing []interface{}
+	for _, c := range conds {
+		m, ok := c.(map[string]interface{})
+		if !ok || m["type"] != string(cond.Type) {
+			existing = append(existing, c)
+			continue
+		}
+		if m["status"] == string(cond.Status) {
+			if t, ok := m["lastTransitionTime"].(string); ok {
+				if err := cond.LastTransitionTime.UnmarshalQueryParameter(t); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&cond)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedSlice(obj.Object, append(existing, m), "status", "conditions")
+}
+
+// MarkConsumerError sets a condition with status False and severity Error. The konnector shows
+// it to the consumer as kube-bind.io/provider-message condition of the consumer object.
+func MarkConsumerError(obj *unstructured.Unstructured, t conditionsapi.ConditionType, reason, messageFormat string, messageArgs ...interface{}) error {
+	return SetCondition(obj, conditionsapi.Condition{
+		Type:     t,
+		Status:   corev1.ConditionFalse,
+		Severity: conditionsapi.ConditionSeverityError,
+		Reason:   reason,
+		Message:  fmt.Sprintf(messageFormat, messageArgs...),
+	})
+}
+
+// MarkReady sets the Ready condition to True.
+func MarkReady(obj *unstructured.Unstructured) error {
+	return SetCondition(obj, conditionsapi.Condition{
+		Type:   conditionsapi.ReadyCondition,
+		Status: corev1.ConditionTrue,
+	})
+}
+
+// MarkNotReady sets the Ready condition to False. The konnector shows it to the consumer as
+// kube-bind.io/provider-message condition of the consumer object.
+func MarkNotReady(obj *unstructured.Unstructured, reason, messageFormat string, messageArgs ...interface{}) error {
+	return SetCondition(obj, conditionsapi.Condition{
+		Type:     conditionsapi.ReadyCondition,
+		Status:   corev1.ConditionFalse,
+		Severity: conditionsapi.ConditionSeverityError,
+		Reason:   reason,
+		Message:  fmt.Sprintf(messageFormat, messageArgs...),
+	})
+}
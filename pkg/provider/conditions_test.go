@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	conditionsapi "github.com/kube-bind/kube-bind/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+func TestSetCondition(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Other", "status": "True", "lastTransitionTime": "2022-01-01T00:00:00Z"},
+				map[string]interface{}{"type": "Ready", "status": "False", "lastTransitionTime": "2022-01-01T00:00:00Z"},
+			},
+		},
+	}}
+
+	require.NoError(t, MarkNotReady(obj, "DatabaseDown", "database %s is down", "foo"))
+	conds, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	require.NoError(t, err)
+	require.Len(t, conds, 2)
+	require.Equal(t, map[string]interface{}{
+		"type":               string(conditionsapi.ReadyCondition),
+		"status":             "False",
+		"severity":           "Error",
+		"reason":             "DatabaseDown",
+		"message":            "database foo is down",
+		"lastTransitionTime": "2022-01-01T00:00:00Z",
+	}, conds[1])
+
+	require.NoError(t, MarkReady(obj))
+	conds, _, err = unstructured.NestedSlice(obj.Object, "status", "conditions")
+	require.NoError(t, err)
+	require.Len(t, conds, 2)
+	require.Equal(t, "True", conds[1].(map[string]interface{})["status"])
+	require.NotEqual(t, "2022-01-01T00:00:00Z", conds[1].(map[string]interface{})["lastTransitionTime"])
+}
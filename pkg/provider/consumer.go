@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package provider contains helpers for operators in a service provider cluster that
+// reconcile objects synced by kube-bind, so they don't have to reimplement kube-bind
+// conventions by hand.
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+	"github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1/helpers"
+)
+
+// Consumer identifies where an object in the service provider cluster originates from.
+type Consumer struct {
+	// ClusterNamespace is the namespace in the service provider cluster that represents
+	// the consumer cluster. It holds the ClusterBinding, the APIServiceExports and the
+	// APIServiceNamespaces of that consumer.
+	ClusterNamespace string
+
+	// Namespace is the name of the namespace in the consumer cluster.
+	Namespace string
+
+	// Object is the name of the consumer object if the namespace is dedicated to it,
+	// i.e. with Object isolation of the APIServiceExport.
+	Object string
+}
+
+// ConsumerForNamespace returns the consumer of a namespace created by the service
+// provider for an APIServiceNamespace.
+func ConsumerForNamespace(ns *corev1.Namespace) (Consumer, error) {
+	value, found := ns.Annotations[kubebindv1alpha1.APIServiceNamespaceAnnotationKey]
+	if !found {
+		return Consumer{}, fmt.Errorf("namespace %q is not bound: missing annotation %s", ns.Name, kubebindv1alpha1.APIServiceNamespaceAnnotationKey)
+	}
+	comps := strings.SplitN(value, "/", 2)
+	if len(comps) != 2 || comps[0] == "" || comps[1] == "" {
+		return Consumer{}, fmt.Errorf("namespace %q has invalid annotation %s=%q", ns.Name, kubebindv1alpha1.APIServiceNamespaceAnnotationKey, value)
+	}
+	return Consumer{ClusterNamespace: comps[0], Namespace: helpers.ConsumerNamespace(comps[1]), Object: helpers.IsolatedObject(comps[1])}, nil
+}
+
+// ConsumerForObject returns the consumer of a namespaced object in the service provider
+// cluster that was synced by kube-bind.
+func ConsumerForObject(obj metav1.Object, namespaces corelisters.NamespaceLister) (Consumer, error) {
+	if obj.GetNamespace() == "" {
+		return Consumer{}, fmt.Errorf("cannot resolve consumer of cluster-scoped object %q", obj.GetName())
+	}
+	ns, err := namespaces.Get(obj.GetNamespace())
+	if err != nil {
+		return Consumer{}, err
+	}
+	return ConsumerForNamespace(ns)
+}
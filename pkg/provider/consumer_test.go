@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Kube Bind Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubebindv1alpha1 "github.com/kube-bind/kube-bind/pkg/apis/kubebind/v1alpha1"
+)
+
+func TestConsumerForNamespace(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        Consumer
+		wantErr     bool
+	}{
+		{name: "not bound", wantErr: true},
+		{name: "invalid", annotations: map[string]string{kubebindv1alpha1.APIServiceNamespaceAnnotationKey: "foo"}, wantErr: true},
+		{name: "empty name", annotations: map[string]string{kubebindv1alpha1.APIServiceNamespaceAnnotationKey: "kube-bind-abc/"}, wantErr: true},
+		{
+			name:        "bound",
+			annotations: map[string]string{kubebindv1alpha1.APIServiceNamespaceAnnotationKey: "kube-bind-abc/default"},
+			want:        Consumer{ClusterNamespace: "kube-bind-abc", Namespace: "default"},
+		},
+		{
+			name:        "object isolation",
+			annotations: map[string]string{kubebindv1alpha1.APIServiceNamespaceAnnotationKey: "kube-bind-abc/default.my.db"},
+			want:        Consumer{ClusterNamespace: "kube-bind-abc", Namespace: "default", Object: "my.db"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConsumerForNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-bind-abc-default", Annotations: tt.annotations}})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}